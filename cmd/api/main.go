@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -48,9 +49,12 @@ import (
 	"github.com/damoang/angple-backend/pkg/i18n"
 	"github.com/damoang/angple-backend/pkg/jwt"
 	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/damoang/angple-backend/pkg/mail"
+	"github.com/damoang/angple-backend/pkg/oidc"
 	pkgredis "github.com/damoang/angple-backend/pkg/redis"
 	pkgsphinx "github.com/damoang/angple-backend/pkg/sphinx"
 	pkgstorage "github.com/damoang/angple-backend/pkg/storage"
+	"github.com/damoang/angple-backend/pkg/webpush"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"github.com/gin-contrib/cors"
@@ -76,6 +80,9 @@ import (
 // 이 깊이에 도달한 부모에 답글을 달면 확장 대신 같은 계층(형제)으로 저장한다.
 const maxCommentReplyDepth = 10
 
+// localMediaPath 는 로컬 디스크 저장소(storage.backend: local)의 파일 서빙 경로다
+const localMediaPath = "/api/v2/media/files"
+
 // @title           Angple Backend API
 // @version         2.0
 // @description     Angple Community Platform - Open Source Backend API
@@ -622,6 +629,49 @@ func safeIntToUint64(v int) uint64 {
 	return uint64(v) // #nosec G115 -- IDs are always non-negative
 }
 
+// enrichWithImageVariants swaps each item's `thumbnail` for the "list" variant of the uploaded
+// image at `thumbnail_raw` and adds `thumbnail_blurhash` (v2_media_images). 파생본이 없는 옛 이미지·외부
+// 이미지는 그대로 둔다. enrichWithAuthorImage 처럼 바꾸는 항목만 얕은 복사한다 (캐시 공유).
+func enrichWithImageVariants(repo v2repo.MediaImageRepository, items []map[string]any) []map[string]any {
+	if repo == nil || len(items) == 0 {
+		return items
+	}
+	urls := make([]string, 0, len(items))
+	for _, item := range items {
+		if raw, ok := item["thumbnail_raw"].(string); ok && raw != "" {
+			urls = append(urls, raw)
+		}
+	}
+	if len(urls) == 0 {
+		return items
+	}
+	images, err := repo.FindByURLs(urls)
+	if err != nil || len(images) == 0 {
+		return items
+	}
+	result := make([]map[string]any, len(items))
+	for i, item := range items {
+		raw, _ := item["thumbnail_raw"].(string)
+		img, ok := images[raw]
+		if !ok {
+			result[i] = item
+			continue
+		}
+		copied := make(map[string]any, len(item)+1)
+		for k, v := range item {
+			copied[k] = v
+		}
+		if variant := img.Variant("list"); variant != nil {
+			copied["thumbnail"] = variant.URL
+		}
+		if img.Blurhash != "" {
+			copied["thumbnail_blurhash"] = img.Blurhash
+		}
+		result[i] = copied
+	}
+	return result
+}
+
 // enrichWithAuthorImage augments items with each author's mb_image_url + mb_image_updated_at
 // as `author_image` + `author_image_updated_at` fields. Matches the shape consumed by
 // frontend `classic.svelte` layout (post.author_image / post.author_image_updated_at).
@@ -1173,26 +1223,49 @@ func main() {
 			pkglogger.Info("Connected to Elasticsearch")
 		}
 	}
+	// 검색 서비스는 쓰기 후 워커(색인)·cron(정합성 검사)·검색 라우트가 같이 쓴다
+	var searchSvc *service.SearchService
+	if db != nil {
+		switch cfg.Search.Backend {
+		case "embedded":
+			searchSvc = service.NewSearchService(service.NewEmbeddedSearchBackend(), db)
+			// 메모리 색인이라 기동할 때마다 DB 에서 다시 만든다 (빌드 중엔 빈 결과, 끝나면 alias 교체)
+			if err := searchSvc.StartReindex(); err != nil {
+				pkglogger.Info("Warning: embedded search reindex failed to start: %v", err)
+			}
+		case "", "auto", "elasticsearch":
+			if esClient != nil {
+				searchSvc = service.NewSearchService(service.NewElasticsearchBackend(esClient), db)
+			}
+		default:
+			pkglogger.Info("Warning: unknown SEARCH_BACKEND %q (using DB fallback search)", cfg.Search.Backend)
+		}
+		if searchSvc != nil {
+			pkglogger.Info("Search backend: %s", searchSvc.BackendName())
+		}
+	}
 
-	// S3-compatible storage
-	var s3Client *pkgstorage.S3Client
-	if cfg.Storage.Enabled && cfg.Storage.Bucket != "" {
-		var s3Err error
-		s3Client, s3Err = pkgstorage.NewS3Client(pkgstorage.S3Config{
-			Endpoint:        cfg.Storage.Endpoint,
-			Region:          cfg.Storage.Region,
-			AccessKeyID:     cfg.Storage.AccessKeyID,
-			SecretAccessKey: cfg.Storage.SecretAccessKey,
-			Bucket:          cfg.Storage.Bucket,
-			CDNURL:          cfg.Storage.CDNURL,
-			BasePath:        cfg.Storage.BasePath,
-			ForcePathStyle:  cfg.Storage.ForcePathStyle,
-		})
-		if s3Err != nil {
-			pkglogger.Info("Warning: S3 storage init failed: %v (continuing without S3)", s3Err)
-			s3Client = nil
+	// 미디어 저장소 — storage.backend: s3(기본, storage.enabled 일 때) | local(S3·MinIO 없는 자체 호스팅·개발용)
+	var mediaStore pkgstorage.Backend
+	var localStore *pkgstorage.LocalBackend
+	if cfg.Storage.Backend == "local" || (cfg.Storage.Enabled && cfg.Storage.Bucket != "") {
+		storeCfg := cfg.MediaStorage("")
+		if storeCfg.Backend == "local" && storeCfg.Local.PublicURL == "" {
+			// 같은 오리진에서만 열리는 상대 주소 — 앱·다른 도메인 프론트엔드가 있으면 STORAGE_PUBLIC_URL 을 둔다
+			storeCfg.Local.PublicURL = localMediaPath
+			pkglogger.Info("Warning: STORAGE_PUBLIC_URL not set (local media URLs are relative: %s)", localMediaPath)
+		}
+		store, storeErr := pkgstorage.New(storeCfg)
+		if storeErr != nil {
+			pkglogger.Info("Warning: media storage init failed: %v (continuing without media uploads)", storeErr)
 		} else {
-			pkglogger.Info("Connected to S3 storage")
+			mediaStore = store
+			localStore, _ = store.(*pkgstorage.LocalBackend)
+			if localStore != nil {
+				pkglogger.Info("Media storage: local disk")
+			} else {
+				pkglogger.Info("Connected to S3 storage")
+			}
 		}
 	}
 
@@ -1206,7 +1279,8 @@ func main() {
 		cfg.JWT.ExpiresIn,
 		cfg.JWT.RefreshIn,
 	)
-	jwtManager.SetNextKey(cfg.JWT.SecretNext) // 무중단 키 롤오버: 보조키(설정 시)를 검증에 추가 수용
+	jwtManager.SetNextKey(cfg.JWT.SecretNext)         // 무중단 키 롤오버: 보조키(설정 시)를 검증에 추가 수용
+	jwtManager.SetAcceptHS256(cfg.JWT.AcceptsHS256()) // 비대칭 서명키는 DB 연결 후 SigningKeyService 가 싣는다
 
 	// IP Protection
 	ipProtectCfg := middleware.LoadIPProtectionConfig()
@@ -1214,9 +1288,10 @@ func main() {
 	// Ban check middleware (제재 회원 글/댓글 작성 차단)
 	banCheck := middleware.BanCheck(db)
 
-	// Plugin HookManager
+	// Plugin HookManager — 코어 게시글/댓글 경로가 플러그인 매니저 생성 전에 참조하므로 여기서 만들어 공유한다
 	pluginLogger := plugin.NewDefaultLogger("plugin")
-	_ = plugin.NewHookManager(pluginLogger)
+	pluginHooks := plugin.NewHookManager(pluginLogger)
+	writeHooks := contentHooks{hm: pluginHooks}
 
 	// OpenTelemetry 초기화 (OTEL_ENABLED=true 시에만)
 	otelShutdown, otelErr := telemetry.Init(context.Background(), "damoang-api", "v1")
@@ -1246,10 +1321,14 @@ func main() {
 		AllowOrigins:     []string{allowOrigins},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "X-CSRF-Token", "X-Request-ID"},
 		AllowCredentials: true,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		ExposeHeaders:    []string{"X-Request-ID", "X-RateLimit-Remaining", "X-Cache"},
 		MaxAge:           86400,
 	}
+	// 이어 올리기 (tus 1.0.0, /api/v2/media/uploads) — 브라우저 tus 클라이언트가 보내고 읽는 헤더
+	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "Tus-Resumable", "Upload-Length", "Upload-Offset", "Upload-Metadata")
+	corsConfig.ExposeHeaders = append(corsConfig.ExposeHeaders, "Location", "Tus-Resumable", "Tus-Version", "Tus-Extension",
+		"Tus-Max-Size", "Tus-Max-Chunk-Size", "Upload-Offset", "Upload-Length", "Upload-Expires")
 	if len(corsConfig.AllowOrigins) == 1 && corsConfig.AllowOrigins[0] != "" {
 		corsConfig.AllowOrigins = splitAndTrim(allowOrigins, ",")
 	}
@@ -1265,7 +1344,6 @@ func main() {
 			log.Printf("warning: i18n LoadDir failed: %v", err)
 		}
 	}
-	_ = i18nBundle
 
 	// Middleware
 	router.Use(middleware.I18n())
@@ -1283,6 +1361,9 @@ func main() {
 	// Prometheus metrics (localhost only)
 	router.GET("/metrics", middleware.RequireLocalhost(), gin.WrapH(promhttp.Handler()))
 
+	// JWT 공개키 (ES256·EdDSA 서명키, HS256 만 쓰면 빈 목록)
+	v2routes.SetupJWKS(router, v2handler.NewJWKSHandler(jwtManager))
+
 	// Health Check (DB ping 포함 — 커넥션 죽으면 K8s가 파드 재시작)
 	router.GET("/health", func(c *gin.Context) {
 		if db == nil {
@@ -1366,6 +1447,20 @@ func main() {
 			gnuWriteRepo = gnurepo.NewWriteRepository(db)
 		}
 		gnuFileRepo := gnurepo.NewFileRepository(db)
+		// 업로드 이미지의 크기별 파생본 (MediaService 가 기록, 목록 썸네일이 읽는다)
+		mediaImageRepo := v2repo.NewMediaImageRepository(db)
+		// 내용 주소(SHA-256) 파일 기록과 참조 — 같은 파일은 한 번만 저장하고 media-gc 가 고아 파일을 치운다
+		mediaObjectRepo := v2repo.NewMediaObjectRepository(db)
+		// 이어 올리기(tus) 진행 상태와 받은 조각
+		mediaUploadRepo := v2repo.NewMediaUploadRepository(db)
+		for _, model := range []interface{}{&v2domain.V2MediaImage{}, &v2domain.V2MediaVariant{}, &v2domain.V2MediaObject{}, &v2domain.V2MediaRef{},
+			&v2domain.V2MediaUpload{}, &v2domain.V2MediaUploadPart{}} {
+			if !db.Migrator().HasTable(model) {
+				if err := db.AutoMigrate(model); err != nil {
+					log.Printf("warning: media image AutoMigrate failed: %v", err)
+				}
+			}
+		}
 		gnuTagRepo := gnurepo.NewTagRepository(db)
 		gnuMemberRepo := gnurepo.NewMemberRepository(db)
 		scheduledDeleteRepo := gnurepo.NewScheduledDeleteRepository(db)
@@ -1401,11 +1496,13 @@ func main() {
 		v2Handler.SetRevisionRepository(v2RevisionRepo)
 		v2Handler.SetNotiRepository(gnurepo.NewNotiRepository(db))
 		v2Handler.SetNotiPreferenceRepository(gnurepo.NewNotiPreferenceRepository(db))
+		v2Handler.SetNotiRuleRepository(gnurepo.NewNotiRuleRepository(db))
 		v2Handler.SetGnuDB(db)
 		v2Handler.SetBlockRepository(v2repo.NewBlockRepository(db))
 		v2Handler.SetTagRepository(gnurepo.NewTagRepository(db))
 		// 라이브 브리지: v2 게시글/댓글 읽기를 현세대 g5_ 라이브 스토어로 서빙 (v2_posts 죽은 스냅샷 대체)
 		v2Handler.SetLiveReadRepos(gnuWriteRepo, gnuBoardRepo)
+		v2Handler.SetTopicPublisher(wsHub)
 
 		// XP: DI into V2Handler (set after expRepo is created below)
 
@@ -1463,6 +1560,38 @@ func main() {
 		v2AuthSvc.SetPromotionDeps(db, gnurepo.NewNotiRepository(db))
 		v2AuthSvc.SetRedis(redisClient) // app-login 코드 1회용(jti) replay 차단
 
+		// 서버 세션(리프레시 토큰 패밀리) — 회전·재사용 탐지·기기별 로그아웃.
+		// access token 의 sid 폐기 여부는 JWTAuth 세션 게이트가 본다(Redis 캐시).
+		v2SessionRepo := v2repo.NewSessionRepository(db)
+		if err := v2SessionRepo.AutoMigrate(); err != nil {
+			log.Printf("warning: v2_auth_sessions AutoMigrate failed: %v", err)
+		}
+		v2SessionSvc := v2svc.NewSessionService(v2SessionRepo, jwtManager)
+		v2SessionSvc.SetRedis(redisClient)
+		v2AuthSvc.SetSessionService(v2SessionSvc)
+		middleware.SetSessionCheck(v2SessionSvc.IsRevoked)
+		v2routes.SetupSessions(router, v2handler.NewSessionHandler(v2SessionSvc), jwtManager)
+
+		// 비대칭 서명키(ES256·EdDSA) — DB 에 봉인해 두고 레플리카가 5분마다 다시 읽는다.
+		// HS256 설정이어도 남은 키는 검증에 쓴다(되돌린 뒤에도 발급된 토큰이 만료까지 통하도록).
+		v2SigningKeyRepo := v2repo.NewSigningKeyRepository(db)
+		if err := v2SigningKeyRepo.AutoMigrate(); err != nil {
+			log.Printf("warning: v2_jwt_signing_keys AutoMigrate failed: %v", err)
+		}
+		signingKeySecret := cfg.JWT.KeySecret
+		if signingKeySecret == "" {
+			signingKeySecret = cfg.JWT.Secret
+		}
+		v2SigningKeySvc := v2svc.NewSigningKeyService(v2SigningKeyRepo, jwtManager, cfg.JWT.SigningAlg,
+			time.Duration(cfg.JWT.KeyRotateDays)*24*time.Hour, signingKeySecret)
+		if err := v2SigningKeySvc.Start(context.Background()); err != nil {
+			// 서명 설정인데 키를 못 열면 조용히 HS256 으로 서명하지 않는다 — 공개키만 받는 게임 서버가 거절한다
+			if v2SigningKeySvc.Signing() {
+				log.Fatalf("jwt signing keys: %v", err)
+			}
+			log.Printf("warning: jwt signing keys: %v", err)
+		}
+
 		v2AuthHandler := v2handler.NewV2AuthHandler(v2AuthSvc)
 		// auth_domain_groups DB-backed cookie domain cache (5min TTL).
 		// 새 도메인 추가 시 SQL/admin UI 만으로 적용 (코드 변경 X) — multi-tenant SaaS 확장.
@@ -1476,6 +1605,37 @@ func main() {
 		})
 		v2routes.SetupAuth(router, v2AuthHandler, jwtManager, loginRateLimit)
 
+		// 2단계 인증(TOTP + 복구 코드). 켠 회원의 로그인은 mfa_ticket → /auth/mfa/verify 로 끝난다.
+		v2MFARepo := v2repo.NewMFARepository(db)
+		if err := v2MFARepo.AutoMigrate(); err != nil {
+			log.Printf("warning: v2_user_mfa AutoMigrate failed: %v", err)
+		}
+		if cfg.MFA.SecretKey == "" {
+			log.Printf("warning: MFA_SECRET_KEY is not set — TOTP secrets are stored unsealed")
+		}
+		v2MFASvc := v2svc.NewMFAService(v2MFARepo, cfg.MFA.Issuer, cfg.MFA.SecretKey)
+		v2AuthSvc.SetMFAService(v2MFASvc)
+		v2routes.SetupMFA(router, v2handler.NewMFAHandler(v2MFASvc, v2AuthSvc), v2AuthHandler, jwtManager, loginRateLimit)
+		middleware.SetAdminMFARequired(cfg.MFA.RequireForAdmins)
+
+		// 패스키(WebAuthn). rp_id 가 없으면 라우트를 열지 않는다 — 잘못된 도메인으로 등록된 패스키는 되돌릴 수 없다.
+		if cfg.WebAuthn.RPID != "" {
+			v2WebAuthnRepo := v2repo.NewWebAuthnRepository(db)
+			if err := v2WebAuthnRepo.AutoMigrate(); err != nil {
+				log.Printf("warning: v2_webauthn_credentials AutoMigrate failed: %v", err)
+			}
+			v2WebAuthnSvc, err := v2svc.NewWebAuthnService(v2WebAuthnRepo, v2UserRepo, cfg.WebAuthn.RPID, cfg.WebAuthn.RPDisplayName, cfg.WebAuthn.Origins)
+			if err != nil {
+				log.Printf("warning: passkeys disabled: %v", err)
+			} else {
+				v2WebAuthnSvc.SetRedis(redisClient)
+				v2WebAuthnSvc.SetMFAService(v2MFASvc)
+				v2routes.SetupWebAuthn(router, v2handler.NewWebAuthnHandler(v2WebAuthnSvc, v2AuthSvc), jwtManager, loginRateLimit)
+			}
+		} else {
+			log.Printf("[webauthn] rp_id is not set — passkey routes disabled")
+		}
+
 		// v1 compatibility routes (frontend calls /api/v1/*)
 		v1Auth := router.Group("/api/v1/auth")
 		v1Auth.POST("/login", loginRateLimit, v2AuthHandler.Login)
@@ -1851,6 +2011,34 @@ func main() {
 		notiPrefRepo := gnurepo.NewNotiPreferenceRepository(db)
 		memberActivitySync := service.NewMemberActivitySyncService(db)
 		notiHandler := handler.NewNotiHandler(notiRepo, notiPrefRepo)
+
+		// 알림 세부 설정 — 글·게시판 규칙(g5_noti_rule)과 방해 금지 시간·멘션 팔로우 한정(g5_noti_preference 컬럼).
+		// g5_noti_preference 는 레거시 테이블이라 AutoMigrate 하지 않고 빠진 컬럼만 더한다 (migrations/016).
+		notiRuleRepo := gnurepo.NewNotiRuleRepository(db)
+		if !db.Migrator().HasTable(&gnurepo.NotiRule{}) {
+			if err := db.AutoMigrate(&gnurepo.NotiRule{}); err != nil {
+				log.Printf("warning: g5_noti_rule AutoMigrate failed: %v", err)
+			}
+		}
+		if db.Migrator().HasTable(&gnurepo.NotiPreference{}) {
+			for _, field := range []string{"MentionFollowingOnly", "QuietStart", "QuietEnd", "TimeZone"} {
+				if !db.Migrator().HasColumn(&gnurepo.NotiPreference{}, field) {
+					if err := db.Migrator().AddColumn(&gnurepo.NotiPreference{}, field); err != nil {
+						log.Printf("warning: g5_noti_preference add column %s failed: %v", field, err)
+					}
+				}
+			}
+		}
+		notiRuleHandler := handler.NewNotiRuleHandler(notiRuleRepo)
+
+		// 실시간 알림 — g5_na_noti 삽입마다 수신자의 WebSocket 으로 notification·unread_count 를 보낸다.
+		// 삽입은 Notification.AfterCreate 훅으로 잡으므로 알림을 만드는 곳(워커·쪽지·cron)은 손대지 않는다.
+		notiRealtime := worker.NewNotiRealtimeWorker(db, wsHub, notiRepo, notiPrefRepo, redisClient)
+		notiRealtime.SetNotiRules(notiRuleRepo)
+		gnurepo.SetNotificationCreatedHook(notiRealtime.Enqueue)
+		notiHandler.SetBroadcastNotifier(notiRealtime.Broadcast)
+		notiRealtime.Start()
+		defer notiRealtime.Stop()
 		notiGroup := router.Group("/api/v1/notifications", middleware.JWTAuth(jwtManager))
 		notiGroup.GET("/unread-count", notiHandler.GetUnreadCount)
 		notiGroup.POST("/seen", notiHandler.MarkSeen)
@@ -1858,6 +2046,9 @@ func main() {
 		notiGroup.GET("/grouped", notiHandler.GetGroupedNotifications)
 		notiGroup.GET("/preferences", notiHandler.GetPreferences)
 		notiGroup.PUT("/preferences", notiHandler.UpdatePreferences)
+		notiGroup.GET("/rules", notiRuleHandler.List)
+		notiGroup.PUT("/rules", notiRuleHandler.Set)
+		notiGroup.DELETE("/rules", notiRuleHandler.Delete)
 		notiGroup.POST("/:id/read", notiHandler.MarkAsRead)
 		notiGroup.POST("/read-all", notiHandler.MarkAllAsRead)
 		notiGroup.POST("/group/read", notiHandler.MarkGroupAsRead)
@@ -1875,12 +2066,39 @@ func main() {
 		notiGroupV2.GET("/grouped", notiHandler.GetGroupedNotifications)
 		notiGroupV2.GET("/preferences", notiHandler.GetPreferences)
 		notiGroupV2.PUT("/preferences", notiHandler.UpdatePreferences)
+		notiGroupV2.GET("/rules", notiRuleHandler.List)
+		notiGroupV2.PUT("/rules", notiRuleHandler.Set)
+		notiGroupV2.DELETE("/rules", notiRuleHandler.Delete)
 		notiGroupV2.POST("/:id/read", notiHandler.MarkAsRead)
 		notiGroupV2.POST("/read-all", notiHandler.MarkAllAsRead)
 		notiGroupV2.POST("/group/read", notiHandler.MarkGroupAsRead)
 		notiGroupV2.DELETE("/:id", notiHandler.Delete)
 		notiGroupV2.DELETE("/group", notiHandler.DeleteGroup)
 
+		// 이메일 알림 — 분류별 수신 설정(g5_noti_preference 옆)은 항상, 발송은 MAIL_TRANSPORT 가 있을 때만.
+		// 알림을 만드는 cron 이 g5_noti_email_queue 에 쌓고 email-outbox·email-digest-* 잡이 보낸다.
+		notiEmailRepo := gnurepo.NewNotiEmailRepository(db)
+		for _, model := range []interface{}{&gnurepo.EmailPreference{}, &gnurepo.EmailOutbox{}, &gnurepo.EmailSuppression{}} {
+			if !db.Migrator().HasTable(model) {
+				if err := db.AutoMigrate(model); err != nil {
+					log.Printf("warning: email notification AutoMigrate failed: %v", err)
+				}
+			}
+		}
+		emailNotiSvc := newEmailNotificationService(db, notiEmailRepo, i18nBundle, cfg.JWT.Secret)
+		emailNotiHandler := handler.NewEmailNotiHandler(notiEmailRepo, emailNotiSvc)
+		notiGroup.GET("/preferences/email", emailNotiHandler.GetPreferences)
+		notiGroup.PUT("/preferences/email", emailNotiHandler.UpdatePreferences)
+		notiGroupV2.GET("/preferences/email", emailNotiHandler.GetPreferences)
+		notiGroupV2.PUT("/preferences/email", emailNotiHandler.UpdatePreferences)
+		// 수신 거부 — 로그인 없이 토큰으로 (POST 가 RFC 8058 one-click)
+		router.GET("/api/v2/email/unsubscribe", emailNotiHandler.UnsubscribeInfo)
+		router.POST("/api/v2/email/unsubscribe", emailNotiHandler.Unsubscribe)
+		adminEmailGroup := router.Group("/api/v1/admin/email", middleware.JWTAuth(jwtManager), middleware.RequireAdmin())
+		adminEmailGroup.GET("/suppressions", emailNotiHandler.ListSuppressions)
+		adminEmailGroup.POST("/suppressions", emailNotiHandler.AddSuppression)
+		adminEmailGroup.DELETE("/suppressions", emailNotiHandler.RemoveSuppression)
+
 		// 관리자 전체 알림 방송(fan-out-on-read) — ops 콘솔이 Bearer 로 호출. RequireAdmin(level>=10).
 		adminNotiGroup := router.Group("/api/v1/admin/notifications", middleware.JWTAuth(jwtManager), middleware.RequireAdmin())
 		adminNotiGroup.POST("/broadcast", notiHandler.CreateBroadcast)
@@ -2077,6 +2295,7 @@ func main() {
 
 		// Admin member management + memo CRUD
 		adminMemberHandler := handler.NewAdminMemberHandler(db)
+		adminMemberHandler.SetSessionRevoker(v2SessionSvc.RevokeAllForMember) // 차단 = 강제 로그아웃
 		// ── 관리자 대시보드 통계 ─────────────────────────────────────────
 		//
 		// ⛔ 2026-08-18: 프런트는 예전부터 /api/v1/admin/stats 를 불렀는데 그 라우트가
@@ -2448,13 +2667,34 @@ func main() {
 			getBlockedIDs,
 			worker.ClearPostMemCache(&postMemCache),
 		)
+		if searchSvc != nil {
+			// 검색 색인은 쓰기 후 이벤트에서 파생된 search_* 이벤트로 따라간다
+			writeAfterWorker.SetSearchIndexer(searchSvc)
+		}
+		// 글 상세·게시판 목록 토픽 — 구독 권한은 REST 조회 규칙, 이벤트 숨김은 같은 차단 캐시를 쓴다
+		wsHub.SetTopicAccess(handler.NewWSTopicAccess(db, gnuBoardRepo, getBlockedIDs))
+		writeAfterWorker.SetTopicPublisher(wsHub)
+		writeAfterWorker.SetNotiRules(notiRuleRepo)
 		writeAfterWorker.Start(4)
 		defer writeAfterWorker.Stop()
 
 		// 푸시 알림 outbox 폴러 — g5_na_noti 를 폴링해 Expo Push 로 발송.
 		// PUSH_ENABLED=true 일 때만 가동(canary 검증 후 prod on).
+		// 웹 푸시(VAPID) — 키 두 개와 subject 가 모두 있어야 켠다 (키는 cmd/generate_vapid_keys)
+		var webPushVAPID *webpush.VAPID
+		if pub, priv := os.Getenv("VAPID_PUBLIC_KEY"), os.Getenv("VAPID_PRIVATE_KEY"); pub != "" && priv != "" {
+			if v, err := webpush.NewVAPID(pub, priv, os.Getenv("VAPID_SUBJECT")); err != nil {
+				log.Printf("warning: web push disabled: %v", err)
+			} else {
+				webPushVAPID = v
+			}
+		}
 		if v := os.Getenv("PUSH_ENABLED"); v == "true" || v == "1" {
 			pushWorker := worker.NewPushNotifyWorker(db)
+			pushWorker.SetNotiRules(notiPrefRepo, notiRuleRepo)
+			if webPushVAPID != nil {
+				pushWorker.EnableWebPush(webPushVAPID)
+			}
 			pushWorker.Start()
 			defer pushWorker.Stop()
 		}
@@ -2468,6 +2708,20 @@ func main() {
 		//    문제가 생기면 **이미지 롤백 없이 이 한 줄만 빼서** 끌 수 있다.
 		middleware.SetWithdrawalCheck(db, redisClient)
 
+		// API 키 인증 (Bearer ak_... / X-API-Key) — JWTAuth 가 키를 알아보면 스코프·키별 한도를 건다.
+		// 평문 키가 남아 있으면 기동 시 해시로 옮긴다(migrations/008).
+		apiKeyRepo := repository.NewAPIKeyRepository(db)
+		if err := apiKeyRepo.AutoMigrate(); err != nil {
+			log.Printf("warning: api_keys AutoMigrate failed: %v", err)
+		}
+		apiKeySvc := service.NewAPIKeyService(apiKeyRepo, db)
+		if n, err := apiKeySvc.MigrateLegacyKeys(context.Background()); err != nil {
+			log.Printf("warning: API key hashing migration failed: %v", err)
+		} else if n > 0 {
+			pkglogger.Info("API keys: hashed %d legacy plaintext keys", n)
+		}
+		middleware.SetAPIKeyAuth(apiKeySvc.Authenticate, redisClient)
+
 		// 탈퇴 파기 시 web 세션·회원 캐시(L2)까지 지우기 위한 Redis 주입.
 		// nil 이면 DB 파기만 수행한다(캐시는 TTL 로 자연 만료).
 		handler.SetAuthCacheRedis(redisClient)
@@ -2937,6 +3191,9 @@ func main() {
 			// classic.svelte layout 의 author_image / author_image_updated_at 필드 채움.
 			items = enrichWithAuthorImage(db, items)
 
+			// 목록 썸네일 → 업로드 때 만든 목록 크기 파생본 (원본 대신). cache 저장 전 적용.
+			items = enrichWithImageVariants(mediaImageRepo, items)
+
 			// is_discipline_related enrich (이용제한 근거 글 표시용).
 			// g5_na_singo.discipline_log_id IS NOT NULL 매칭. cache 저장 전 적용 → cache 자동 포함.
 			// ⛔ 실패하면 마스킹이 벗겨진 채로 나간다. 그 자체도 노출이지만,
@@ -3287,6 +3544,9 @@ func main() {
 				}
 			}
 
+			// 플러그인 post.content 필터 — 마스킹·게이트가 끝난 최종 본문에만 적용한다.
+			writeHooks.filterContent(plugin.HookPostContent, slug, postDetail)
+
 			c.JSON(http.StatusOK, gin.H{
 				"success": true,
 				"data":    postDetail,
@@ -3474,6 +3734,9 @@ func main() {
 				gateDisciplinedComments(transformed, isAnonComment, false)
 			}
 
+			// 플러그인 comment.content 필터 — 마스킹·게이트가 끝난 최종 본문에만 적용한다.
+			writeHooks.filterContents(plugin.HookCommentContent, slug, transformed)
+
 			// 댓글 수정 정책 메타 — 프론트엔드 confirm 다이얼로그에서 사용 (단일 진실 근원: 백엔드 env)
 			editCost, editGraceSeconds := getCommentEditPolicy()
 			c.JSON(http.StatusOK, gin.H{
@@ -3861,6 +4124,30 @@ func main() {
 			mbID := middleware.GetUserID(c)
 			userLevel := middleware.GetUserLevel(c)
 
+			// 플러그인 before_create — 스팸 차단(거부)·자동 태깅·본문 변환(재작성).
+			// 아래 코어 검증(제휴 링크·카테고리 등)이 재작성된 값에 그대로 적용되도록 맨 앞에서 실행한다.
+			hooked, hookErr := writeHooks.before(plugin.HookPostBeforeCreate, map[string]interface{}{
+				"board_id":  slug,
+				"member_id": mbID,
+				"level":     userLevel,
+				"title":     req.Title,
+				"content":   req.Content,
+				"category":  derefString(req.Category),
+				"link1":     derefString(req.Link1),
+				"link2":     derefString(req.Link2),
+				"tags":      req.Tags,
+			})
+			if hookErr != nil {
+				respondHookAbort(c, hookErr)
+				return
+			}
+			hookString(hooked, "title", &req.Title)
+			hookString(hooked, "content", &req.Content)
+			hookStringPtr(hooked, "category", &req.Category)
+			hookStringPtr(hooked, "link1", &req.Link1)
+			hookStringPtr(hooked, "link2", &req.Link2)
+			hookStrings(hooked, "tags", &req.Tags)
+
 			// 제휴 링크 차단 검증
 			if err := common.ValidateAffiliateLinks(req.Content, slug, userLevel, false); err != nil {
 				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
@@ -4216,6 +4503,16 @@ func main() {
 				"data":    v1handler.TransformToV1PostDetail(&post, false, slug),
 			}
 			storeIdempotentWriteResponse(c.Request.Context(), redisClient, idempotencyBaseKey, http.StatusCreated, payload)
+			writeHooks.after(plugin.HookPostAfterCreate, map[string]interface{}{
+				"board_id":  slug,
+				"post_id":   post.WrID,
+				"member_id": mbID,
+				"author":    post.WrName,
+				"title":     post.WrSubject,
+				"content":   post.WrContent,
+				"category":  post.CaName,
+				"tags":      req.Tags,
+			})
 			phaseDurations["after_write"] = time.Since(phaseStart)
 			logWritePhase(c, "create_post", slug, post.WrID, startedAt, phaseDurations)
 			c.JSON(http.StatusCreated, payload)
@@ -4317,6 +4614,21 @@ func main() {
 			mbID := middleware.GetUserID(c)
 			userLevel := middleware.GetUserLevel(c)
 
+			// 플러그인 before_create — 글 작성과 동일하게 코어 검증보다 먼저 실행한다.
+			hooked, hookErr := writeHooks.before(plugin.HookCommentBeforeCreate, map[string]interface{}{
+				"board_id":  slug,
+				"post_id":   postID,
+				"parent_id": req.ParentID,
+				"member_id": mbID,
+				"level":     userLevel,
+				"content":   req.Content,
+			})
+			if hookErr != nil {
+				respondHookAbort(c, hookErr)
+				return
+			}
+			hookString(hooked, "content", &req.Content)
+
 			// 제휴 링크 차단 검증
 			if err := common.ValidateAffiliateLinks(req.Content, slug, userLevel, false); err != nil {
 				c.JSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error()})
@@ -4609,6 +4921,15 @@ func main() {
 				},
 			}
 			storeIdempotentWriteResponse(c.Request.Context(), redisClient, idempotencyBaseKey, http.StatusCreated, payload)
+			writeHooks.after(plugin.HookCommentAfterCreate, map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    postID,
+				"comment_id": comment.WrID,
+				"parent_id":  req.ParentID,
+				"member_id":  mbID,
+				"author":     authorName,
+				"content":    comment.WrContent,
+			})
 			phaseDurations["after_write"] = time.Since(phaseStart)
 			logWritePhase(c, "create_comment", slug, comment.WrID, startedAt, phaseDurations)
 			c.JSON(http.StatusCreated, payload)
@@ -4725,6 +5046,37 @@ func main() {
 				return
 			}
 
+			// 플러그인 before_update — 요청에 실린 필드만 넘기고, 플러그인이 돌려준 키만 반영한다.
+			// (키가 없던 필드를 플러그인이 채우면 그 필드도 수정 대상이 된다)
+			beforeUpdate := map[string]interface{}{
+				"board_id":  slug,
+				"post_id":   postID,
+				"member_id": userID,
+				"author_id": post.MbID,
+				"level":     userLevel,
+			}
+			if req.Title != nil {
+				beforeUpdate["title"] = *req.Title
+			}
+			if req.Content != nil {
+				beforeUpdate["content"] = *req.Content
+			}
+			if req.Category != nil {
+				beforeUpdate["category"] = *req.Category
+			}
+			if req.Tags != nil {
+				beforeUpdate["tags"] = req.Tags
+			}
+			hooked, hookErr := writeHooks.before(plugin.HookPostBeforeUpdate, beforeUpdate)
+			if hookErr != nil {
+				respondHookAbort(c, hookErr)
+				return
+			}
+			hookStringPtr(hooked, "title", &req.Title)
+			hookStringPtr(hooked, "content", &req.Content)
+			hookStringPtr(hooked, "category", &req.Category)
+			hookStrings(hooked, "tags", &req.Tags)
+
 			// 수정 전 내용을 리비전에 저장 — 양쪽 테이블 모두 기록
 			var nextVersion int
 			db.Raw("SELECT COALESCE(MAX(version), 0) + 1 FROM g5_write_revisions WHERE board_id = ? AND wr_id = ?", slug, postID).Scan(&nextVersion)
@@ -4968,6 +5320,27 @@ func main() {
 				redisClient.Del(c.Request.Context(), "promotion:board_posts", "promotion:posts")
 			}
 
+			afterUpdate := map[string]interface{}{
+				"board_id":  slug,
+				"post_id":   postID,
+				"member_id": userID,
+				"author_id": post.MbID,
+			}
+			for k, v := range updates {
+				switch k {
+				case "wr_subject":
+					afterUpdate["title"] = v
+				case "wr_content":
+					afterUpdate["content"] = v
+				case "ca_name":
+					afterUpdate["category"] = v
+				}
+			}
+			if req.Tags != nil {
+				afterUpdate["tags"] = req.Tags
+			}
+			writeHooks.after(plugin.HookPostAfterUpdate, afterUpdate)
+
 			payload := gin.H{"success": true, "message": "수정 완료"}
 			storeIdempotentWriteResponse(c.Request.Context(), redisClient, idempotencyBaseKey, http.StatusOK, payload)
 			c.JSON(http.StatusOK, payload)
@@ -5050,6 +5423,22 @@ func main() {
 				return
 			}
 
+			// 플러그인 before_update — 포인트 차감보다 먼저 실행해야 거부 시 환불이 필요 없다.
+			hooked, hookErr := writeHooks.before(plugin.HookCommentBeforeUpdate, map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    comment.WrParent,
+				"comment_id": commentID,
+				"member_id":  userID,
+				"author_id":  comment.MbID,
+				"level":      userLevel,
+				"content":    req.Content,
+			})
+			if hookErr != nil {
+				respondHookAbort(c, hookErr)
+				return
+			}
+			hookString(hooked, "content", &req.Content)
+
 			idempotencyBaseKey := getIdempotencyBaseKey(c, userID)
 			switch state, cached := beginIdempotentWrite(c.Request.Context(), redisClient, idempotencyBaseKey); state {
 			case "cached":
@@ -5161,6 +5550,15 @@ func main() {
 				return
 			}
 
+			writeHooks.after(plugin.HookCommentAfterUpdate, map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    postID,
+				"comment_id": commentID,
+				"member_id":  userID,
+				"author_id":  comment.MbID,
+				"content":    req.Content,
+			})
+
 			payload := gin.H{"success": true, "message": "수정 완료"}
 			storeIdempotentWriteResponse(c.Request.Context(), redisClient, idempotencyBaseKey, http.StatusOK, payload)
 			c.JSON(http.StatusOK, payload)
@@ -5272,6 +5670,20 @@ func main() {
 			// 운영 이력이 보존되지만, 수정은 다음 사람이 볼 내용 자체를 바꿔버린다.
 			// 실측(2026-07-26)도 수정 182건 대 삭제 23건으로 수정이 8배였다.
 
+			// 플러그인 before_delete — 거부 시 즉시 삭제·지연 삭제 예약 모두 하지 않는다.
+			postDeleteHook := map[string]interface{}{
+				"board_id":  slug,
+				"post_id":   postID,
+				"member_id": userID,
+				"author_id": post.MbID,
+				"level":     userLevel,
+				"title":     post.WrSubject,
+			}
+			if _, hookErr := writeHooks.before(plugin.HookPostBeforeDelete, postDeleteHook); hookErr != nil {
+				respondHookAbort(c, hookErr)
+				return
+			}
+
 			// 관리자(level >= 10)는 즉시 삭제
 			if userLevel >= 10 {
 				txErr := db.Transaction(func(tx *gorm.DB) error {
@@ -5311,6 +5723,7 @@ func main() {
 					c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "게시글 삭제 실패"})
 					return
 				}
+				writeHooks.after(plugin.HookPostAfterDelete, postDeleteHook)
 				// 직접홍보 게시판: Redis 캐시 무효화
 				if slug == "promotion" && redisClient != nil {
 					redisClient.Del(c.Request.Context(), "promotion:board_posts", "promotion:posts")
@@ -5359,6 +5772,7 @@ func main() {
 					c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "게시글 삭제 실패"})
 					return
 				}
+				writeHooks.after(plugin.HookPostAfterDelete, postDeleteHook)
 				// 직접홍보 게시판: Redis 캐시 무효화
 				if slug == "promotion" && redisClient != nil {
 					redisClient.Del(c.Request.Context(), "promotion:board_posts", "promotion:posts")
@@ -5441,6 +5855,18 @@ func main() {
 				return
 			}
 
+			postRestoreHook := map[string]interface{}{
+				"board_id":  slug,
+				"post_id":   postID,
+				"member_id": middleware.GetUserID(c),
+				"author_id": post.MbID,
+				"title":     post.WrSubject,
+			}
+			if _, hookErr := writeHooks.before(plugin.HookPostBeforeRestore, postRestoreHook); hookErr != nil {
+				respondHookAbort(c, hookErr)
+				return
+			}
+
 			// 게시글 복구
 			txErr := db.Transaction(func(tx *gorm.DB) error {
 				if err := tx.Table(fmt.Sprintf("g5_write_%s", slug)).Where("wr_id = ?", postID).Updates(map[string]interface{}{
@@ -5472,6 +5898,7 @@ func main() {
 				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "게시글 복구 실패"})
 				return
 			}
+			writeHooks.after(plugin.HookPostAfterRestore, postRestoreHook)
 
 			c.JSON(http.StatusOK, gin.H{"success": true, "message": "복구 완료"})
 		})
@@ -5532,8 +5959,23 @@ func main() {
 				}
 			}
 
-			// 관리자(level >= 10)는 즉시 삭제
 			postID, _ := strconv.Atoi(c.Param("id"))
+
+			// 플러그인 before_delete — 거부 시 즉시 삭제·지연 삭제 예약 모두 하지 않는다.
+			commentDeleteHook := map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    postID,
+				"comment_id": commentID,
+				"member_id":  userID,
+				"author_id":  comment.MbID,
+				"level":      userLevel,
+			}
+			if _, hookErr := writeHooks.before(plugin.HookCommentBeforeDelete, commentDeleteHook); hookErr != nil {
+				respondHookAbort(c, hookErr)
+				return
+			}
+
+			// 관리자(level >= 10)는 즉시 삭제
 			if userLevel >= 10 {
 				txErr := db.Transaction(func(tx *gorm.DB) error {
 					now := time.Now()
@@ -5569,6 +6011,7 @@ func main() {
 					c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "댓글 삭제 실패"})
 					return
 				}
+				writeHooks.after(plugin.HookCommentAfterDelete, commentDeleteHook)
 				c.JSON(http.StatusOK, gin.H{"success": true, "message": "삭제 완료"})
 				return
 			}
@@ -5618,6 +6061,7 @@ func main() {
 					c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "댓글 삭제 실패"})
 					return
 				}
+				writeHooks.after(plugin.HookCommentAfterDelete, commentDeleteHook)
 				c.JSON(http.StatusOK, gin.H{"success": true, "message": "삭제 완료"})
 				return
 			}
@@ -5681,6 +6125,17 @@ func main() {
 
 			// 댓글 복구
 			postID, _ := strconv.Atoi(c.Param("id"))
+			commentRestoreHook := map[string]interface{}{
+				"board_id":   slug,
+				"post_id":    postID,
+				"comment_id": commentID,
+				"member_id":  middleware.GetUserID(c),
+			}
+			if _, hookErr := writeHooks.before(plugin.HookCommentBeforeRestore, commentRestoreHook); hookErr != nil {
+				respondHookAbort(c, hookErr)
+				return
+			}
+			restored := false
 			txErr := db.Transaction(func(tx *gorm.DB) error {
 				changed, err := restoreCommentAndAdjust(tx, slug, postID, commentID)
 				if err != nil {
@@ -5689,6 +6144,7 @@ func main() {
 				if !changed {
 					return nil
 				}
+				restored = true
 				return createWriteAfterEvent(
 					tx,
 					writeAfterEventRepo,
@@ -5712,6 +6168,9 @@ func main() {
 				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "댓글 복구 실패"})
 				return
 			}
+			if restored {
+				writeHooks.after(plugin.HookCommentAfterRestore, commentRestoreHook)
+			}
 
 			c.JSON(http.StatusOK, gin.H{"success": true, "message": "댓글 복구 완료"})
 		})
@@ -6073,6 +6532,36 @@ func main() {
 				"wr_content":    fmt.Sprintf("이 게시물은 <a href=\"/%s/%d\">%s 게시판</a>으로 이동되었습니다.", req.TargetBoardID, newPost.WrID, req.TargetBoardID),
 			})
 
+			// 5. 쓰기 후 이벤트 — 원래 위치는 삭제, 새 위치는 이동으로 남겨 캐시·검색 색인이 따라오게 한다
+			if err := createWriteAfterEvent(
+				tx,
+				writeAfterEventRepo,
+				gnuboard.WriteAfterEventTypePostDeleted,
+				srcBoard,
+				postID,
+				withWriteAfterMember(post.MbID, post.WrName),
+				withWriteAfterSubject(post.WrSubject),
+				withWriteAfterOccurredAt(now),
+			); err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "게시글 이동 실패 (event)"})
+				return
+			}
+			if err := createWriteAfterEvent(
+				tx,
+				writeAfterEventRepo,
+				gnuboard.WriteAfterEventTypePostMoved,
+				req.TargetBoardID,
+				newPost.WrID,
+				withWriteAfterMember(post.MbID, post.WrName),
+				withWriteAfterSubject(post.WrSubject),
+				withWriteAfterOccurredAt(now),
+			); err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "게시글 이동 실패 (event)"})
+				return
+			}
+
 			if err := tx.Commit().Error; err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "이동 트랜잭션 커밋 실패"})
 				return
@@ -6741,6 +7230,11 @@ func main() {
 		v2routes.SetupBlock(router, v2handler.NewBlockHandler(v2BlockRepo, cacheService), jwtManager, db)
 		v2routes.SetupMessage(router, v2handler.NewMessageHandler(v2MessageRepo), jwtManager, db)
 		v2routes.SetupDevices(router, v2handler.NewDeviceHandler(v2DeviceRepo), jwtManager)
+		webPushPublicKey := ""
+		if webPushVAPID != nil {
+			webPushPublicKey = webPushVAPID.PublicKey()
+		}
+		v2routes.SetupWebPush(router, v2handler.NewWebPushHandler(v2repo.NewWebPushRepository(db), webPushPublicKey), jwtManager)
 		v2routes.SetupFavorite(router, v2handler.NewFavoriteHandler(db), jwtManager)
 
 		// v1 message routes (uses g5_memo table directly)
@@ -6752,10 +7246,28 @@ func main() {
 		//    빠뜨리면 아무 일도 안 한다. 배포 후 실제로 거부가 먹는지 확인할 것.
 		// ⚠️ 읽는 테이블(g5_da_member_ui_settings)의 소유자는 web 이다. 읽기 전용이다.
 		v1MsgHandler.SetUISettingsRepo(gnurepo.NewMemberUISettingsRepository(db))
+		// 대화방 보기 — 상대별 묶음·보관·알림 끄기·이미지 첨부 (첨부 업로드는 아래 S3 블록에서 켠다)
+		for _, model := range []interface{}{&gnuboard.MemoThreadState{}, &gnuboard.MemoAttachment{}} {
+			if !db.Migrator().HasTable(model) {
+				if err := db.AutoMigrate(model); err != nil {
+					log.Printf("warning: memo thread AutoMigrate failed: %v", err)
+				}
+			}
+		}
+		v1MsgHandler.SetThreadRepo(gnurepo.NewMemoThreadRepository(db))
+		v1MsgHandler.SetEventSender(wsHub)
 		v1Messages := router.Group("/api/v1/messages", middleware.JWTAuth(jwtManager))
 		v1Messages.GET("", v1MsgHandler.GetMessages)
 		v1Messages.GET("/unread-count", v1MsgHandler.GetUnreadCount)
 		v1Messages.POST("/read-all", v1MsgHandler.ReadAllMessages)
+		v1Messages.GET("/threads", v1MsgHandler.GetThreads)
+		v1Messages.GET("/threads/:peer", v1MsgHandler.GetThread)
+		v1Messages.POST("/threads/:peer/read", v1MsgHandler.MarkThreadRead)
+		v1Messages.POST("/threads/:peer/archive", v1MsgHandler.ArchiveThread)
+		v1Messages.DELETE("/threads/:peer/archive", v1MsgHandler.ArchiveThread)
+		v1Messages.POST("/threads/:peer/mute", v1MsgHandler.MuteThread)
+		v1Messages.DELETE("/threads/:peer/mute", v1MsgHandler.MuteThread)
+		v1Messages.POST("/attachments", banCheck, v1MsgHandler.UploadAttachment)
 		v1Messages.GET("/:id", v1MsgHandler.GetMessage)
 		v1Messages.POST("", banCheck, v1MsgHandler.SendMessage)
 		v1Messages.DELETE("/:id", v1MsgHandler.DeleteMessage)
@@ -6874,6 +7386,11 @@ func main() {
 			log.Printf("warning: subscription AutoMigrate failed: %v", err)
 		}
 		provisioningSvc := service.NewProvisioningService(siteRepo, subRepo, tenantDBResolver, db, "angple.com")
+		siteIdPRepo := repository.NewSiteIdentityProviderRepository(db)
+		if err := siteIdPRepo.AutoMigrate(); err != nil {
+			log.Printf("warning: site identity provider AutoMigrate failed: %v", err)
+		}
+		provisioningSvc.SetIdentityProviderRepository(siteIdPRepo)
 		provisioningHandler := handler.NewProvisioningHandler(provisioningSvc)
 
 		saas := router.Group("/api/v2/saas")
@@ -6889,6 +7406,9 @@ func main() {
 		saasCommunities.PUT("/:id/subscription/plan", provisioningHandler.ChangePlan)
 		saasCommunities.POST("/:id/subscription/cancel", provisioningHandler.CancelSubscription)
 		saasCommunities.GET("/:id/invoices", provisioningHandler.GetInvoices)
+		saasCommunities.GET("/:id/idp", provisioningHandler.GetIdentityProvider)
+		saasCommunities.PUT("/:id/idp", provisioningHandler.SetIdentityProvider)
+		saasCommunities.DELETE("/:id/idp", provisioningHandler.DeleteIdentityProvider)
 
 		// OAuth2 Social Login
 		oauthService := service.NewOAuthService(db, jwtManager)
@@ -6907,33 +7427,86 @@ func main() {
 			})
 		}
 		if clientID := os.Getenv("GOOGLE_CLIENT_ID"); clientID != "" {
-			oauthService.RegisterProvider(domain.OAuthProviderGoogle, &domain.OAuthConfig{
+			if err := oauthService.RegisterOIDCProvider(domain.OAuthProviderGoogle, oidc.Config{
+				Issuer:       "https://accounts.google.com",
 				ClientID:     clientID,
 				ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
 				RedirectURL:  os.Getenv("GOOGLE_REDIRECT_URL"),
-				Scopes:       []string{"openid", "email", "profile"},
-			})
+			}); err != nil {
+				log.Printf("warning: oauth provider google: %v", err)
+			}
+		}
+		// 설정 파일의 OpenID Connect 제공자 (Apple·GitHub·사내 IdP)
+		for _, p := range cfg.OAuth.Providers {
+			if err := oauthService.RegisterOIDCProvider(domain.OAuthProvider(p.Name), oidc.Config{
+				Issuer:       p.Issuer,
+				ClientID:     p.ClientID,
+				ClientSecret: p.ClientSecret,
+				RedirectURL:  p.RedirectURL,
+				Scopes:       p.Scopes,
+				AuthURL:      p.AuthURL,
+				TokenURL:     p.TokenURL,
+				UserInfoURL:  p.UserInfoURL,
+				JWKSURL:      p.JWKSURL,
+				SubjectClaim: p.SubjectClaim,
+				EmailClaim:   p.EmailClaim,
+				NameClaim:    p.NameClaim,
+				PictureClaim: p.PictureClaim,
+				ResponseMode: p.ResponseMode,
+			}); err != nil {
+				log.Printf("warning: oauth provider %s: %v", p.Name, err)
+			}
 		}
+		// 테넌트 IdP: /api/v2/auth/oauth/sso-<subdomain>
+		oauthService.SetTenantIdentityProviders(provisioningSvc.FindIdentityProviderBySubdomain)
 		oauthHandler := handler.NewOAuthHandler(oauthService)
+		oauthHandler.SetMemberLogin(v2AuthHandler.OAuthMemberLogin)
 
 		oauth := router.Group("/api/v2/auth/oauth")
 		oauth.GET("/:provider", oauthHandler.Redirect)
 		oauth.GET("/:provider/callback", oauthHandler.Callback)
+		oauth.POST("/:provider/callback", oauthHandler.Callback)
 
-		apiKeys := router.Group("/api/v2/auth/api-keys", middleware.JWTAuth(jwtManager))
-		apiKeys.POST("", oauthHandler.GenerateAPIKey)
+		// 소셜·OIDC 계정 연동 관리 — 마지막 로그인 수단은 해제할 수 없다
+		oauthAccounts := router.Group("/api/v2/me/oauth-accounts", middleware.JWTAuth(jwtManager))
+		oauthAccounts.GET("", oauthHandler.ListAccounts)
+		oauthAccounts.POST("", oauthHandler.Link)
+		oauthAccounts.DELETE("/:provider", oauthHandler.Unlink)
 
-		// Elasticsearch Search (optional)
-		if esClient != nil {
-			searchSvc := service.NewSearchService(esClient, db)
+		// API 키 발급·교체·폐기 (인증 배선은 SetAPIKeyAuth — 탈퇴 게이트 옆)
+		apiKeyHandler := handler.NewAPIKeyHandler(apiKeySvc)
+		apiKeys := router.Group("/api/v2/auth/api-keys", middleware.JWTAuth(jwtManager))
+		apiKeys.GET("", apiKeyHandler.List)
+		apiKeys.POST("", apiKeyHandler.Create)
+		apiKeys.POST("/:id/rotate", apiKeyHandler.Rotate)
+		apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+
+		// 통합 검색 (Elasticsearch 또는 내장 색인, optional)
+		var savedSearchSvc *service.SavedSearchService
+		if searchSvc != nil {
+			// exclude_blocked=1 은 로그인 회원의 차단 목록을 쓴다 (비로그인은 무시)
+			searchSvc.SetBlockRepository(v2repo.NewBlockRepository(db))
 			searchHandler := handler.NewSearchHandler(searchSvc)
 
-			search := router.Group("/api/v2/search")
+			search := router.Group("/api/v2/search", middleware.OptionalJWTAuth(jwtManager))
 			search.GET("", searchHandler.Search)
 			search.GET("/autocomplete", searchHandler.Autocomplete)
 
+			// 저장한 검색 + 새 글 알림 (cron: saved-search-alerts)
+			savedSearchRepo := repository.NewSavedSearchRepository(db)
+			if err := savedSearchRepo.AutoMigrate(); err != nil {
+				log.Printf("warning: saved_searches AutoMigrate failed: %v", err)
+			}
+			savedSearchSvc = service.NewSavedSearchService(savedSearchRepo, searchSvc, db)
+			savedSearchHandler := handler.NewSavedSearchHandler(savedSearchSvc)
+			savedSearches := router.Group("/api/v2/search/saved", middleware.JWTAuth(jwtManager))
+			savedSearches.GET("", savedSearchHandler.List)
+			savedSearches.POST("", savedSearchHandler.Create)
+			savedSearches.PATCH("/:id", savedSearchHandler.Update)
+			savedSearches.DELETE("/:id", savedSearchHandler.Delete)
+
 			// v1 검색 라우트 (프론트엔드 호환)
-			searchV1 := router.Group("/api/v1/search")
+			searchV1 := router.Group("/api/v1/search", middleware.OptionalJWTAuth(jwtManager))
 			searchV1.GET("", searchHandler.Search)
 			searchV1.GET("/autocomplete", searchHandler.Autocomplete)
 
@@ -6945,20 +7518,33 @@ func main() {
 			adminSearch.POST("/index", searchHandler.BulkIndex)
 			adminSearch.POST("/index-post", searchHandler.IndexPost)
 			adminSearch.DELETE("/index/:board_id/:post_id", searchHandler.DeletePostIndex)
+			adminSearch.POST("/reindex", searchHandler.StartReindex)
+			adminSearch.GET("/reindex", searchHandler.ReindexStatus)
 		} else {
-			// Elasticsearch 미가용: DB 폴백 검색 (앱/웹 통합 검색이 항상 동작하도록).
+			// 검색 백엔드 미가용: DB 폴백 검색 (앱/웹 통합 검색이 항상 동작하도록).
 			// 응답 형태는 게시글 목록과 동일(V2Post[] + meta).
 			searchFallback := router.Group("/api/v2/search", middleware.OptionalJWTAuth(jwtManager))
 			searchFallback.GET("", v2Handler.SearchPosts)
 			searchFallbackV1 := router.Group("/api/v1/search", middleware.OptionalJWTAuth(jwtManager))
 			searchFallbackV1.GET("", v2Handler.SearchPosts)
-			pkglogger.Info("Search: Elasticsearch unavailable, using DB fallback search")
+			pkglogger.Info("Search: no search backend, using DB fallback search")
 		}
 
-		// Media Pipeline (S3 storage, optional)
-		if s3Client != nil {
-			mediaSvc := service.NewMediaService(s3Client)
+		// Media Pipeline (S3 또는 로컬 디스크, optional)
+		var mediaSvc *service.MediaService
+		if mediaStore != nil {
+			mediaSvc = service.NewMediaService(mediaStore)
+			mediaSvc.SetImageRepository(mediaImageRepo)
+			mediaSvc.SetObjectRepository(mediaObjectRepo)
+			mediaSvc.SetUploadRepository(mediaUploadRepo)
+			if variants, err := service.ParseImageVariants(cfg.Storage.ImageVariants); err != nil {
+				pkglogger.Info("Warning: %v (using default image variants)", err)
+			} else {
+				mediaSvc.SetImageVariants(variants)
+			}
 			mediaHandler := handler.NewMediaHandler(mediaSvc)
+			mediaHandler.SetUploadPlan(cfg.Storage.UploadPlan)
+			v1MsgHandler.SetMediaService(mediaSvc)
 
 			// TODO: UploadRateLimitConfig 구현 후 활성화
 			// uploadRateLimit := middleware.RateLimit(redisClient, middleware.UploadRateLimitConfig())
@@ -6966,6 +7552,14 @@ func main() {
 			media.POST("/images", mediaHandler.UploadImage)
 			media.POST("/attachments", mediaHandler.UploadAttachment)
 			media.POST("/videos", mediaHandler.UploadVideo)
+			// 이어 올리기 (tus 1.0.0) — 모바일 등 끊기는 연결에서 큰 동영상·첨부를 조각으로 올린다
+			// OPTIONS 는 tus 서버 정보 조회라 인증 없이 받는다
+			router.OPTIONS("/api/v2/media/uploads", mediaHandler.UploadOptions)
+			media.POST("/uploads", mediaHandler.CreateUpload)
+			media.HEAD("/uploads/:id", mediaHandler.UploadOffset)
+			media.PATCH("/uploads/:id", mediaHandler.PatchUpload)
+			media.POST("/uploads/:id/complete", mediaHandler.CompleteUpload)
+			media.DELETE("/uploads/:id", mediaHandler.CancelUpload)
 			// ⛔ 2026-08-08: DeleteFile 은 key prefix 화이트리스트만 검사하고 소유자
 			//    확인이 없어, 인증된 아무 회원이나 key 를 알면 타인 파일을 지울 수 있었다.
 			//    media 키에 업로더 정보가 없어 소유 검증이 불가하고, 웹·앱 소비처가 0
@@ -6973,16 +7567,29 @@ func main() {
 			media.DELETE("/files", middleware.RequireAdmin(), mediaHandler.DeleteFile)
 
 			// Member profile image
-			memberSvc := service.NewMemberService(s3Client, gnuMemberRepo)
+			memberSvc := service.NewMemberService(mediaStore, gnuMemberRepo)
+			memberSvc.SetObjectRepository(mediaObjectRepo)
 			memberHandler := handler.NewMemberHandler(memberSvc)
 			memberImage := router.Group("/api/v2/members/me", middleware.JWTAuth(jwtManager), middleware.BanCheck(db))
 			memberImage.POST("/image", memberHandler.UploadImage)
 			memberImage.DELETE("/image", memberHandler.DeleteImage)
+
+			// 로컬 디스크 파일 서빙 — 서명 URL 이라 인증 없이 열린다 (<img> 태그가 토큰을 못 보낸다)
+			if localStore != nil {
+				localFiles := handler.NewLocalStorageHandler(localStore)
+				router.GET(localMediaPath+"/*key", localFiles.ServeFile)
+				router.HEAD(localMediaPath+"/*key", localFiles.ServeFile)
+			}
 		}
 
 		// WebSocket
 		wsHandler := handler.NewWSHandler(wsHub, cfg.CORS.AllowOrigins)
-		router.GET("/ws/notifications", middleware.JWTAuth(jwtManager), wsHandler.Connect)
+		wsHandler.SetResumer(notiRealtime)
+		// RemapUserIDToMbID: 연결을 mb_id 로 묶어야 g5_na_noti.mb_id 로 보내는 알림이 앱(Bearer) 연결에도 닿는다
+		router.GET("/ws/notifications", middleware.JWTAuth(jwtManager), middleware.RemapUserIDToMbID(), wsHandler.Connect)
+		// SSE 대체 통로 — 같은 Hub·같은 이벤트. 재접속(Last-Event-ID)용으로 발행 이벤트를 짧은 스트림에 남긴다
+		wsHub.EnableReplay()
+		router.GET("/api/v2/stream", middleware.JWTAuth(jwtManager), middleware.RemapUserIDToMbID(), wsHandler.Stream)
 
 		// ========================================
 		// 투표/설문 (Poll) API — g5_poll / g5_poll_etc
@@ -7234,6 +7841,40 @@ func main() {
 		permSvc := pluginstoreSvc.NewPermissionService(permRepo, catalogSvc)
 
 		pluginManager := plugin.NewManager("plugins", db, redisClient, pluginLogger, settingSvc, permSvc)
+		pluginManager.SetHookManager(pluginHooks)
+		// 이벤트 버스: 플러그인 활성화(구독 등록) 전에 백엔드를 정해야 한다
+		if n := cfg.Plugins.EventBus.MaxAttempts; n > 0 {
+			policy := plugin.DefaultRetryPolicy()
+			policy.MaxAttempts = n
+			pluginManager.GetEventBus().SetRetryPolicy(policy)
+		}
+		if cfg.Plugins.EventBus.Backend == "redis" {
+			if redisClient != nil {
+				pluginManager.GetEventBus().SetBackend(plugin.NewRedisEventBackend(redisClient, pluginLogger))
+			} else {
+				pkglogger.Info("Warning: plugin event bus backend=redis but Redis is unavailable (using memory)")
+			}
+		}
+		// 스케줄 실행 이력은 DB 에 남긴다 (없으면 메모리)
+		if !db.Migrator().HasTable(&plugin.ScheduleRun{}) || !db.Migrator().HasColumn(&plugin.ScheduleRun{}, "trigger_type") {
+			if err := db.AutoMigrate(&plugin.ScheduleRun{}); err != nil {
+				log.Printf("warning: plugin_schedule_runs AutoMigrate failed: %v", err)
+			}
+		}
+		if db.Migrator().HasTable(&plugin.ScheduleRun{}) {
+			pluginManager.GetScheduler().SetRunStore(plugin.NewGormScheduleRunStore(db))
+		}
+		// Redis 가 없으면 DB 락으로 레플리카 간 단일 실행
+		if redisClient == nil {
+			if !db.Migrator().HasTable(&plugin.ScheduleLock{}) {
+				if err := db.AutoMigrate(&plugin.ScheduleLock{}); err != nil {
+					log.Printf("warning: plugin_schedule_locks AutoMigrate failed: %v", err)
+				}
+			}
+			if db.Migrator().HasTable(&plugin.ScheduleLock{}) {
+				pluginManager.GetScheduler().SetLocker(plugin.NewGormTaskLocker(db))
+			}
+		}
 		pluginManager.GetRegistry().SetRouter(router)
 		pluginManager.GetRegistry().SetJWTVerifier(plugin.NewDefaultJWTVerifier(
 			func(token string) (string, string, int, error) {
@@ -7241,6 +7882,9 @@ func main() {
 				if err != nil {
 					return "", "", 0, err
 				}
+				if claims.Ticket {
+					return "", "", 0, jwt.ErrInvalidToken
+				}
 				return claims.UserID, claims.Nickname, claims.Level, nil
 			},
 		))
@@ -7250,6 +7894,15 @@ func main() {
 		if err := pluginManager.RegisterAllFactories(); err != nil {
 			pkglogger.Info("Failed to register plugin factories: %v", err)
 		}
+		// plugins/ 디렉터리의 외부 플러그인 (runtime.type=process 는 별도 프로세스로 구동)
+		if err := pluginManager.LoadAll(); err != nil {
+			pkglogger.Info("Failed to load plugins directory: %v", err)
+		}
+		for _, info := range pluginManager.GetAllPlugins() {
+			if !info.IsBuiltIn && info.Manifest != nil {
+				catalogSvc.RegisterManifest(info.Manifest)
+			}
+		}
 		if err := storeSvc.BootEnabledPlugins(pluginManager); err != nil {
 			pkglogger.Info("Failed to boot enabled plugins: %v", err)
 		}
@@ -7265,9 +7918,14 @@ func main() {
 			adminPlugins.GET("/dashboard", storeHandler.Dashboard)
 			adminPlugins.GET("/health", storeHandler.HealthCheck)
 			adminPlugins.GET("/schedules", storeHandler.ScheduledTasks)
+			adminPlugins.GET("/schedules/runs", storeHandler.ScheduleRuns)
+			adminPlugins.POST("/schedules/:plugin/:task/run", storeHandler.RunSchedule)
 			adminPlugins.GET("/rate-limits", storeHandler.RateLimitConfigs)
 			adminPlugins.GET("/metrics", storeHandler.PluginMetrics)
 			adminPlugins.GET("/event-subscriptions", storeHandler.EventSubscriptions)
+			adminPlugins.POST("/event-subscriptions/replay", storeHandler.ReplayEvents)
+			adminPlugins.POST("/event-subscriptions/dead-letters/:id/redrive", storeHandler.RedriveDeadLetter)
+			adminPlugins.DELETE("/event-subscriptions/dead-letters/:id", storeHandler.DiscardDeadLetter)
 			adminPlugins.GET("/overview", storeHandler.PluginOverview)
 			adminPlugins.GET("/settings/export", settingHandler.ExportAllSettings)
 			adminPlugins.POST("/settings/import", settingHandler.ImportSettings)
@@ -7343,10 +8001,20 @@ func main() {
 		mpAdmin.POST("/submissions/:id/review", marketplaceHandler.ReviewSubmission)
 
 		pluginManager.StartScheduler()
+		// 외부 프로세스 플러그인 종료 + 이벤트 버스 컨슈머 정리 (미ack 이벤트는 다른 파드가 회수)
+		defer func() {
+			if err := pluginManager.Shutdown(); err != nil {
+				pkglogger.Error("Plugin shutdown error: %v", err)
+			}
+		}()
+		// 스케줄러는 플러그인보다 먼저 멈춘다 (defer 역순)
+		defer pluginManager.StopScheduler()
 		pkglogger.Info("Plugin Store & Marketplace initialized")
 
 		// Giving plugin API
 		givingHandler := handler.NewGivingHandler(db, gnuFileRepo, cfg.Storage.CDNURL, pointConfigRepo)
+		givingHandler.SetTopicPublisher(wsHub)
+		givingHandler.SetMediaImages(mediaImageRepo)
 		givingGroup := router.Group("/api/plugins/giving")
 		{
 			givingGroup.GET("/list", givingHandler.List)
@@ -7376,11 +8044,52 @@ func main() {
 			pollPluginAuthed.POST("/:id/close", pollPluginHandler.Close)   // 작성자·관리자 조기 마감
 		}
 
-		// Internal cron endpoints (curl-based cron jobs, localhost only)
+		// Cron jobs: 플러그인 스케줄러로 in-process 실행 (cron.enabled) + 외부 crontab 호환 엔드포인트 (localhost only)
 		cronHandler := cron.NewHandler(db)
 		cronHandler.SetPointExpiryDeps(pointConfigRepo, gnuPointWriteRepo, gnurepo.NewNotiRepository(db))
 		// 나눔 마감 스윕 — cron 패키지가 handler 를 import 하지 않도록 클로저 주입
 		cronHandler.SetGivingSweep(func() (interface{}, error) { return givingHandler.RunDueDrawSweep() })
+		if searchSvc != nil {
+			cronHandler.SetSearchReconcile(func(ctx context.Context) (interface{}, error) { return searchSvc.ReconcileBoards(ctx) })
+		}
+		if savedSearchSvc != nil {
+			cronHandler.SetSavedSearchAlerts(func(ctx context.Context) (interface{}, error) { return savedSearchSvc.RunAlerts(ctx) })
+		}
+		if v2SigningKeySvc.Signing() {
+			cronHandler.SetJWTKeyRotation(func(ctx context.Context) (interface{}, error) { return v2SigningKeySvc.Rotate(ctx) })
+		}
+		if emailNotiSvc != nil {
+			cronHandler.SetEmailDelivery(
+				func(ctx context.Context) (interface{}, error) { return emailNotiSvc.RunInstant(ctx) },
+				func(ctx context.Context, frequency string) (interface{}, error) {
+					return emailNotiSvc.RunDigest(ctx, frequency)
+				},
+			)
+		}
+		if mediaStore != nil {
+			urlBases := []string{cfg.Storage.PublicURL}
+			if cfg.Storage.CDNURL != "" {
+				urlBases = append(urlBases, strings.TrimRight(cfg.Storage.CDNURL, "/")+"/")
+			}
+			if cfg.Storage.Bucket != "" {
+				urlBases = append(urlBases, fmt.Sprintf("https://%s.s3.amazonaws.com/", cfg.Storage.Bucket))
+			}
+			cronHandler.SetMediaGC(cron.MediaGCConfig{
+				Store:    mediaStore,
+				URLBases: urlBases,
+				Grace:    time.Duration(cfg.Storage.GCGraceDays) * 24 * time.Hour,
+			})
+			cronHandler.SetMediaUploadCleanup(func(ctx context.Context) (interface{}, error) { return mediaSvc.CleanupUploads(ctx) })
+		}
+		// 내장 잡은 플러그인 스케줄러에 등록한다 — 락·실행 이력·관리 API(/api/v2/admin/plugins/schedules, plugin=cron)를 공유
+		cronJobs, err := cron.WithSchedules(cronHandler.Jobs(), cfg.Cron.Jobs)
+		if err != nil {
+			log.Fatalf("cron schedule config: %v", err)
+		}
+		if err := cronHandler.RegisterJobs(pluginManager.GetScheduler(), cronJobs, cfg.Cron.Enabled); err != nil {
+			log.Fatalf("cron jobs: %v", err)
+		}
+
 		cronGroup := router.Group("/api/internal/cron")
 		cronGroup.Use(middleware.RequireInternalCron())
 		cronGroup.POST("/member-lock-release", cronHandler.MemberLockRelease)
@@ -7402,6 +8111,7 @@ func main() {
 
 		// Start delete worker for delayed deletion processing
 		deleteWorker := worker.NewDeleteWorker(db, gnuWriteRepo, scheduledDeleteRepo, writeAfterEventRepo)
+		deleteWorker.SetHooks(pluginHooks)
 		deleteWorker.Start()
 		defer deleteWorker.Stop()
 	} else {
@@ -7472,6 +8182,66 @@ func main() {
 }
 
 // maskIP masks the second octet of an IPv4 address with ♡ (e.g. 222.114.55.158 → 222.♡.55.158)
+// newEmailNotificationService builds the email sender from MAIL_* / SMTP_* env (MAIL_TRANSPORT 가 비면 nil — 발송 끔).
+//
+//	MAIL_TRANSPORT=smtp  SMTP_HOST·SMTP_PORT(587)·SMTP_USERNAME·SMTP_PASSWORD
+//	MAIL_TRANSPORT=file  MAIL_FILE_DIR(./tmp/mail) 에 .eml 로 쓴다 (로컬 개발)
+//
+// 수신 거부 토큰 키는 MAIL_UNSUBSCRIBE_SECRET, 없으면 JWT 시크릿에서 파생한다 — 바꾸면 이미 보낸 메일의 링크가 죽는다.
+func newEmailNotificationService(db *gorm.DB, repo gnurepo.NotiEmailRepository, bundle *i18n.Bundle, jwtSecret string) *service.EmailNotificationService {
+	var transport mail.Transport
+	switch kind := os.Getenv("MAIL_TRANSPORT"); kind {
+	case "":
+		return nil
+	case "smtp":
+		port, _ := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		transport = mail.NewSMTPTransport(mail.SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		})
+	case "file":
+		dir := os.Getenv("MAIL_FILE_DIR")
+		if dir == "" {
+			dir = "./tmp/mail"
+		}
+		fileTransport, err := mail.NewFileTransport(dir)
+		if err != nil {
+			log.Printf("warning: mail file transport disabled: %v", err)
+			return nil
+		}
+		transport = fileTransport
+	default:
+		log.Printf("warning: unknown MAIL_TRANSPORT %q — email notifications disabled", kind)
+		return nil
+	}
+
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		log.Printf("warning: MAIL_FROM is empty — email notifications disabled")
+		return nil
+	}
+	baseURL := strings.TrimSpace(os.Getenv("WEB_BASE_URL"))
+	if baseURL == "" {
+		baseURL = "https://damoang.net"
+		log.Printf("[EmailNoti] WEB_BASE_URL 미설정 — 메일 속 링크를 %s 로 만든다", baseURL)
+	}
+	secret := []byte(os.Getenv("MAIL_UNSUBSCRIBE_SECRET"))
+	if len(secret) == 0 {
+		mac := hmac.New(sha256.New, []byte(jwtSecret))
+		mac.Write([]byte("email-unsubscribe"))
+		secret = mac.Sum(nil)
+	}
+	log.Printf("[EmailNoti] email notifications enabled (transport=%s)", os.Getenv("MAIL_TRANSPORT"))
+	return service.NewEmailNotificationService(db, repo, transport, bundle, service.EmailNotificationConfig{
+		From:           from,
+		BaseURL:        baseURL,
+		UnsubscribeURL: os.Getenv("MAIL_UNSUBSCRIBE_URL"),
+		Secret:         secret,
+	})
+}
+
 func maskIP(ip string) string {
 	if ip == "" {
 		return ""
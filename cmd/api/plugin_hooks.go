@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+// contentHooks 는 게시글/댓글 쓰기·읽기 경로와 플러그인 HookManager 사이의 얇은 어댑터다.
+//
+// 규칙:
+//   - before_* 는 ApplyBefore 로 동기 실행한다. 플러그인은 페이로드를 고쳐 쓰거나
+//     plugin.Abort 로 작업을 거부할 수 있다. 거부되면 DB 에 아무것도 쓰지 않는다.
+//   - after_* 는 커밋 이후 비동기로 실행한다. 플러그인 지연이 응답 시간에 얹히면 안 된다.
+//   - post.content / comment.content 는 응답 직전 content 필드에만 적용한다.
+//
+// hm 이 nil 이거나 해당 이벤트에 등록된 Hook 이 없으면 전부 no-op 이다 —
+// 핫패스(댓글 목록 등)에서 페이로드 map 을 만들지도 않는다.
+type contentHooks struct {
+	hm *plugin.HookManager
+}
+
+// before 는 before_* Hook 을 실행해 (필요 시 재작성된) 페이로드를 돌려준다.
+// 반환 에러는 항상 *plugin.HookAbortError 다.
+func (h contentHooks) before(event string, payload map[string]interface{}) (map[string]interface{}, error) {
+	if h.hm == nil || !h.hm.HasHooks(event) {
+		return payload, nil
+	}
+	return h.hm.ApplyBefore(event, payload)
+}
+
+// after 는 after_* Hook 을 백그라운드에서 실행한다 (panic 은 HookManager 가 흡수).
+func (h contentHooks) after(event string, payload map[string]interface{}) {
+	if h.hm == nil || !h.hm.HasHooks(event) {
+		return
+	}
+	go h.hm.Do(event, payload)
+}
+
+// filterContent 는 응답 아이템의 content 에 *.content 필터를 적용한다.
+// 비어 있는 content(삭제 tombstone, 잠금 마스킹)는 건드리지 않는다.
+func (h contentHooks) filterContent(event, boardID string, item map[string]any) {
+	if h.hm == nil || item == nil || !h.hm.HasHooks(event) {
+		return
+	}
+	content, ok := item["content"].(string)
+	if !ok || content == "" {
+		return
+	}
+	out := h.hm.Apply(event, map[string]interface{}{
+		"board_id": boardID,
+		"id":       item["id"],
+		"content":  content,
+	})
+	if v, ok := out["content"].(string); ok {
+		item["content"] = v
+	}
+}
+
+// filterContents 는 목록 응답 전체에 filterContent 를 적용한다.
+func (h contentHooks) filterContents(event, boardID string, items []map[string]any) {
+	if h.hm == nil || !h.hm.HasHooks(event) {
+		return
+	}
+	for _, item := range items {
+		h.filterContent(event, boardID, item)
+	}
+}
+
+// respondHookAbort 는 before Hook 거부를 403 으로 응답한다.
+// 사유 문구는 플러그인이 사용자에게 보여줄 목적으로 넘긴 것이므로 그대로 노출한다.
+func respondHookAbort(c *gin.Context, err error) {
+	msg := "플러그인 정책에 의해 요청이 거부되었습니다"
+	var abort *plugin.HookAbortError
+	if errors.As(err, &abort) && abort.Reason != "" {
+		msg = abort.Reason
+	}
+	c.JSON(http.StatusForbidden, gin.H{"success": false, "error": msg})
+}
+
+// hookString 은 before Hook 이 재작성한 문자열 필드를 dst 에 반영한다.
+// 키가 없거나 타입이 다르면 원래 값을 유지한다 — 플러그인이 필드를 지워도 코어 검증이 깨지지 않게.
+func hookString(payload map[string]interface{}, key string, dst *string) {
+	if v, ok := payload[key].(string); ok {
+		*dst = v
+	}
+}
+
+// hookStringPtr 는 포인터 필드용 hookString. 원래 nil(요청에 없던 필드)이어도
+// 플러그인이 값을 채우면 반영한다.
+func hookStringPtr(payload map[string]interface{}, key string, dst **string) {
+	if v, ok := payload[key].(string); ok {
+		*dst = &v
+	}
+}
+
+// hookStrings 는 before Hook 이 재작성한 문자열 목록(tags 등)을 dst 에 반영한다.
+// 플러그인은 []string 또는 JSON 디코딩 결과인 []interface{} 어느 쪽으로 돌려줘도 된다.
+func hookStrings(payload map[string]interface{}, key string, dst *[]string) {
+	switch v := payload[key].(type) {
+	case []string:
+		*dst = v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		*dst = out
+	}
+}
+
+// derefString 은 nil 포인터를 빈 문자열로 바꾼다 (Hook 페이로드 구성용).
+func derefString(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/gin-gonic/gin"
+)
+
+type silentPluginLogger struct{}
+
+func (silentPluginLogger) Debug(string, ...interface{}) {}
+func (silentPluginLogger) Info(string, ...interface{})  {}
+func (silentPluginLogger) Warn(string, ...interface{})  {}
+func (silentPluginLogger) Error(string, ...interface{}) {}
+
+// nil HookManager(플러그인 미초기화)에서도 쓰기·읽기 경로가 그대로 동작해야 한다.
+func TestContentHooksNilIsNoop(t *testing.T) {
+	h := contentHooks{}
+
+	payload := map[string]interface{}{"title": "t"}
+	out, err := h.before(plugin.HookPostBeforeCreate, payload)
+	if err != nil || out["title"] != "t" {
+		t.Fatalf("nil hooks should pass payload through, got %v %v", out, err)
+	}
+	h.after(plugin.HookPostAfterCreate, payload)
+
+	item := map[string]any{"content": "원문"}
+	h.filterContent(plugin.HookPostContent, "free", item)
+	if item["content"] != "원문" {
+		t.Errorf("content changed without hooks: %v", item["content"])
+	}
+}
+
+func TestContentHooksBeforeRewriteAppliesToRequest(t *testing.T) {
+	hm := plugin.NewHookManager(silentPluginLogger{})
+	hm.Register(plugin.HookPostBeforeCreate, "tagger", func(ctx *plugin.HookContext) error {
+		out := map[string]interface{}{}
+		for k, v := range ctx.Input {
+			out[k] = v
+		}
+		out["content"] = strings.ReplaceAll(ctx.Input["content"].(string), "나쁜말", "***")
+		out["tags"] = []interface{}{"자동", 3, "태그"}
+		ctx.SetOutput(out)
+		return nil
+	}, 10)
+	h := contentHooks{hm: hm}
+
+	req := struct {
+		Title    string
+		Content  string
+		Category *string
+		Tags     []string
+	}{Title: "제목", Content: "이건 나쁜말 입니다"}
+
+	hooked, err := h.before(plugin.HookPostBeforeCreate, map[string]interface{}{
+		"title":    req.Title,
+		"content":  req.Content,
+		"category": derefString(req.Category),
+		"tags":     req.Tags,
+	})
+	if err != nil {
+		t.Fatalf("unexpected abort: %v", err)
+	}
+	hookString(hooked, "title", &req.Title)
+	hookString(hooked, "content", &req.Content)
+	hookStringPtr(hooked, "category", &req.Category)
+	hookStrings(hooked, "tags", &req.Tags)
+
+	if req.Content != "이건 *** 입니다" {
+		t.Errorf("content = %q", req.Content)
+	}
+	if req.Title != "제목" {
+		t.Errorf("title should be untouched, got %q", req.Title)
+	}
+	if req.Category == nil || *req.Category != "" {
+		t.Errorf("category = %v", req.Category)
+	}
+	if len(req.Tags) != 2 || req.Tags[0] != "자동" || req.Tags[1] != "태그" {
+		t.Errorf("non-string tags should be dropped, got %v", req.Tags)
+	}
+}
+
+func TestRespondHookAbortUsesPluginReason(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hm := plugin.NewHookManager(silentPluginLogger{})
+	hm.Register(plugin.HookCommentBeforeCreate, "spam", func(_ *plugin.HookContext) error {
+		return plugin.Abort("광고성 댓글은 등록할 수 없습니다")
+	}, 10)
+	h := contentHooks{hm: hm}
+
+	_, err := h.before(plugin.HookCommentBeforeCreate, map[string]interface{}{"content": "광고"})
+	if err == nil {
+		t.Fatal("expected abort")
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	respondHookAbort(c, err)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", w.Code)
+	}
+	var body struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Success || body.Error != "광고성 댓글은 등록할 수 없습니다" {
+		t.Errorf("unexpected body: %+v", body)
+	}
+}
+
+func TestFilterContentsSkipsMaskedItems(t *testing.T) {
+	hm := plugin.NewHookManager(silentPluginLogger{})
+	calls := 0
+	hm.RegisterFilter(plugin.HookCommentContent, "emoji", func(ctx *plugin.HookContext) error {
+		calls++
+		if ctx.Input["board_id"] != "free" {
+			t.Errorf("board_id = %v", ctx.Input["board_id"])
+		}
+		ctx.SetOutput(map[string]interface{}{"content": ctx.Input["content"].(string) + " :)"})
+		return nil
+	}, 10)
+	h := contentHooks{hm: hm}
+
+	items := []map[string]any{
+		{"id": 1, "content": "안녕"},
+		{"id": 2, "content": ""}, // 삭제 tombstone / 잠금 마스킹
+	}
+	h.filterContents(plugin.HookCommentContent, "free", items)
+
+	if items[0]["content"] != "안녕 :)" {
+		t.Errorf("content = %v", items[0]["content"])
+	}
+	if items[1]["content"] != "" {
+		t.Errorf("masked content must stay empty, got %v", items[1]["content"])
+	}
+	if calls != 1 {
+		t.Errorf("filter calls = %d, want 1", calls)
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/damoang/angple-backend/pkg/webpush"
+)
+
+// 웹 푸시(VAPID) 키 쌍을 만든다 — 한 번 만들어 환경변수로 둔다.
+// 키를 바꾸면 기존 브라우저 구독은 모두 무효가 된다(브라우저가 다시 구독해야 한다).
+func main() {
+	pub, priv, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("VAPID_PUBLIC_KEY=%s\nVAPID_PRIVATE_KEY=%s\n", pub, priv)
+}
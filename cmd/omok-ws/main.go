@@ -6,6 +6,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -39,13 +40,33 @@ func main() {
 		log.Printf("[omok] 중단 대국 정리 — 참가비 %d건 환불", n)
 	}
 
+	// 공개키 검증: API 의 /.well-known/jwks.json(JWT_JWKS_URL)으로 ES256·EdDSA 토큰을 검증한다 — 이 서버는 토큰을 만들 수 없다.
+	// JWT_SECRET 은 API 가 아직 HS256 으로 서명하는 전환 기간에만 둔다(시크릿이 있으면 토큰 위조가 가능하다).
+	jwksURL := os.Getenv("JWT_JWKS_URL")
 	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		log.Fatal("[omok] JWT_SECRET 이 없습니다 — 익명 대국은 허용하지 않습니다")
+	if jwksURL == "" && secret == "" {
+		log.Fatal("[omok] JWT_JWKS_URL 도 JWT_SECRET 도 없습니다 — 익명 대국은 허용하지 않습니다")
 	}
 	jwtManager := jwt.NewManager(secret, 900, 604800)
-	if next := os.Getenv("JWT_SECRET_NEXT"); next != "" {
-		jwtManager.SetNextKey(next) // 키 롤인 중에도 기존 토큰을 받아준다
+	if secret != "" {
+		if next := os.Getenv("JWT_SECRET_NEXT"); next != "" {
+			jwtManager.SetNextKey(next) // 키 롤인 중에도 기존 토큰을 받아준다
+		}
+		log.Printf("[omok] JWT_SECRET 으로 HS256 토큰도 받습니다 — API 가 비대칭 서명으로 옮기면 지울 것")
+	}
+	if jwksURL != "" {
+		jwtManager.SetKeySource(jwt.JWKSSource(jwksURL, nil))
+		if kerr := jwtManager.ReloadKeys(context.Background()); kerr != nil {
+			log.Printf("[omok] JWKS 로드 실패(모르는 kid 가 오면 다시 받는다): %v", kerr)
+		}
+		// 은퇴한 키를 내려놓고 미리 공개된 다음 키를 받아 둔다
+		go func() {
+			for range time.Tick(5 * time.Minute) {
+				if kerr := jwtManager.ReloadKeys(context.Background()); kerr != nil {
+					log.Printf("[omok] JWKS 갱신 실패: %v", kerr)
+				}
+			}
+		}()
 	}
 
 	verify := func(token string) (string, string, error) {
@@ -53,6 +74,9 @@ func main() {
 		if verr != nil {
 			return "", "", verr
 		}
+		if claims.Ticket {
+			return "", "", jwt.ErrInvalidToken
+		}
 		return claims.UserID, claims.Nickname, nil
 	}
 
@@ -0,0 +1,227 @@
+// storage-migrate copies media objects between storage backends (s3 ↔ local).
+//
+// key 는 그대로 옮긴다 — DB 와 본문에는 key 또는 그 주소가 남아 있으므로, 옮긴 뒤 본문 주소는
+// cmd/rewrite-media-urls 로 바꾼다. 기본은 dry-run 이고 --apply 를 줘야 실제로 복사한다.
+//
+//	go run ./cmd/storage-migrate --from s3 --to local --prefix images/ --apply
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/config"
+	"github.com/damoang/angple-backend/pkg/storage"
+)
+
+type options struct {
+	configPath   string
+	from         string
+	to           string
+	prefix       string
+	limit        int
+	concurrency  int
+	apply        bool
+	overwrite    bool
+	deleteSource bool
+	reportFile   string
+}
+
+type result struct {
+	Listed   int64    `json:"listed"`
+	Existing int64    `json:"existing"`
+	Copied   int64    `json:"copied"`
+	Deleted  int64    `json:"deleted"`
+	Failed   int64    `json:"failed"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// errLimitReached stops List once --limit keys are collected
+var errLimitReached = errors.New("limit reached")
+
+func main() {
+	opts := parseFlags()
+	if opts.from == opts.to {
+		log.Fatal("--from and --to must differ")
+	}
+
+	if loaded := config.LoadDotEnv(); len(loaded) > 0 {
+		log.Printf("Loaded env files: %v", loaded)
+	}
+	cfg, err := config.Load(opts.configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// S3 Upload 는 storage.base_path 를 key 앞에 붙인다 — 옮기면서 key 가 바뀌면 DB·본문의 key 와 어긋난다
+	if opts.to == "s3" && cfg.Storage.BasePath != "" {
+		log.Fatalf("target s3 has base_path %q: keys would change on upload (clear storage.base_path for the migration)", cfg.Storage.BasePath)
+	}
+
+	src, err := storage.New(cfg.MediaStorage(opts.from))
+	if err != nil {
+		log.Fatalf("source backend: %v", err)
+	}
+	dst, err := storage.New(cfg.MediaStorage(opts.to))
+	if err != nil {
+		log.Fatalf("target backend: %v", err)
+	}
+
+	log.Printf("[config] from=%s to=%s prefix=%q apply=%v overwrite=%v delete-source=%v concurrency=%d",
+		opts.from, opts.to, opts.prefix, opts.apply, opts.overwrite, opts.deleteSource, opts.concurrency)
+
+	ctx := context.Background()
+	var keys []string
+	err = src.List(ctx, opts.prefix, func(key string) error {
+		keys = append(keys, key)
+		if opts.limit > 0 && len(keys) >= opts.limit {
+			return errLimitReached
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLimitReached) {
+		log.Fatalf("failed to list source: %v", err)
+	}
+	log.Printf("[scan] found %d objects", len(keys))
+
+	start := time.Now()
+	res := migrate(ctx, opts, src, dst, keys)
+	log.Printf("[summary] listed=%d existing=%d copied=%d deleted=%d failed=%d apply=%v elapsed=%s",
+		res.Listed, res.Existing, res.Copied, res.Deleted, res.Failed, opts.apply, time.Since(start).Round(time.Second))
+
+	if opts.reportFile != "" {
+		writeReport(opts.reportFile, res)
+	}
+	if res.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func parseFlags() options {
+	var opts options
+	flag.StringVar(&opts.configPath, "config", "configs/config.dev.yaml", "config file path")
+	flag.StringVar(&opts.from, "from", "s3", "source backend: s3 | local")
+	flag.StringVar(&opts.to, "to", "local", "target backend: s3 | local")
+	flag.StringVar(&opts.prefix, "prefix", "", "only keys with this prefix (e.g. images/2026/)")
+	flag.IntVar(&opts.limit, "limit", 0, "max objects to process (0 = unlimited)")
+	flag.IntVar(&opts.concurrency, "concurrency", 8, "parallel copies")
+	flag.BoolVar(&opts.apply, "apply", false, "actually copy objects (default: dry-run)")
+	flag.BoolVar(&opts.overwrite, "overwrite", false, "copy even if the target already has the key")
+	flag.BoolVar(&opts.deleteSource, "delete-source", false, "delete each object from the source after a successful copy (move)")
+	flag.StringVar(&opts.reportFile, "report", "", "write JSON report to file")
+	flag.Parse()
+	return opts
+}
+
+func migrate(ctx context.Context, opts options, src, dst storage.Backend, keys []string) result {
+	var res result
+	var mu sync.Mutex
+	fail := func(stage, key string, err error) {
+		atomic.AddInt64(&res.Failed, 1)
+		mu.Lock()
+		res.Errors = append(res.Errors, fmt.Sprintf("[%s] %s: %v", stage, key, err))
+		mu.Unlock()
+		log.Printf("[error] %s %s: %v", stage, key, err)
+	}
+
+	sem := make(chan struct{}, max(opts.concurrency, 1))
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if n := atomic.AddInt64(&res.Listed, 1); n%1000 == 0 {
+				log.Printf("[progress] %d/%d", n, len(keys))
+			}
+
+			if !opts.overwrite {
+				exists, err := dst.Exists(ctx, key)
+				if err != nil {
+					fail("check", key, err)
+					return
+				}
+				if exists {
+					atomic.AddInt64(&res.Existing, 1)
+					// 이미 옮긴 것도 --delete-source 면 원본을 지운다 (중단 뒤 재실행)
+					if opts.apply && opts.deleteSource {
+						deleteSource(ctx, src, key, &res, fail)
+					}
+					return
+				}
+			}
+			if !opts.apply {
+				log.Printf("[would copy] %s", key)
+				return
+			}
+
+			if err := copyObject(ctx, src, dst, key); err != nil {
+				fail("copy", key, err)
+				return
+			}
+			atomic.AddInt64(&res.Copied, 1)
+			if opts.deleteSource {
+				deleteSource(ctx, src, key, &res, fail)
+			}
+		}(key)
+	}
+	wg.Wait()
+	return res
+}
+
+func copyObject(ctx context.Context, src, dst storage.Backend, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	obj, err := src.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer obj.Body.Close()
+
+	contentType := obj.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	_, err = dst.Upload(ctx, key, obj.Body, contentType, obj.Size)
+	return err
+}
+
+func deleteSource(ctx context.Context, src storage.Backend, key string, res *result, fail func(stage, key string, err error)) {
+	if err := src.Delete(ctx, key); err != nil {
+		fail("delete", key, err)
+		return
+	}
+	atomic.AddInt64(&res.Deleted, 1)
+}
+
+func writeReport(file string, res result) {
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		log.Printf("[report] failed to marshal: %v", err)
+		return
+	}
+
+	cleanPath := filepath.Clean(file)
+	if err := os.WriteFile(cleanPath, data, 0600); err != nil {
+		log.Printf("[report] failed to write %s: %v", cleanPath, err)
+		return
+	}
+	log.Printf("[report] written to %s", cleanPath)
+}
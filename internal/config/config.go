@@ -1,10 +1,15 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/damoang/angple-backend/pkg/storage"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,11 +23,85 @@ type Config struct {
 	Database      DatabaseConfig      `yaml:"database"`
 	Plugins       PluginsConfig       `yaml:"plugins"`
 	Elasticsearch ElasticsearchConfig `yaml:"elasticsearch"`
+	Search        SearchConfig        `yaml:"search"`
 	Storage       StorageConfig       `yaml:"storage"`
+	Cron          CronConfig          `yaml:"cron"`
+	MFA           MFAConfig           `yaml:"mfa"`
+	WebAuthn      WebAuthnConfig      `yaml:"webauthn"`
+	OAuth         OAuthConfig         `yaml:"oauth"`
 }
 
-// StorageConfig S3-compatible object storage 설정
+// OAuthConfig 설정 파일로 추가하는 OpenID Connect 로그인 제공자 (naver·kakao 는 환경변수로 따로 등록)
+type OAuthConfig struct {
+	Providers []OAuthProviderConfig `yaml:"providers"`
+}
+
+// OAuthProviderConfig 제공자 하나 — issuer 가 있으면 디스커버리로 엔드포인트를 채운다.
+// 로그인 경로는 /api/v2/auth/oauth/<name> 이고, "sso-" 로 시작하는 이름은 테넌트 IdP 용으로 예약돼 있다.
+type OAuthProviderConfig struct {
+	Name         string   `yaml:"name"`
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"` // OAUTH_<NAME>_CLIENT_SECRET 로 오버라이드
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+	// 디스커버리가 없는 제공자(GitHub 등)는 엔드포인트를 직접 적는다
+	AuthURL      string `yaml:"auth_url"`
+	TokenURL     string `yaml:"token_url"`
+	UserInfoURL  string `yaml:"userinfo_url"`
+	JWKSURL      string `yaml:"jwks_url"`
+	SubjectClaim string `yaml:"subject_claim"` // 기본 sub (GitHub 은 id)
+	EmailClaim   string `yaml:"email_claim"`
+	NameClaim    string `yaml:"name_claim"`
+	PictureClaim string `yaml:"picture_claim"`
+	ResponseMode string `yaml:"response_mode"` // form_post: 콜백이 POST 로 온다 (Apple)
+}
+
+// WebAuthnConfig 패스키 로그인 설정 — RPID 가 비어 있으면 패스키 라우트를 열지 않는다
+type WebAuthnConfig struct {
+	RPID          string   `yaml:"rp_id"`           // 등록 가능 도메인 (예: damoang.net) — 바꾸면 기존 패스키는 쓸 수 없다
+	RPDisplayName string   `yaml:"rp_display_name"` // 패스키 프롬프트에 보이는 이름
+	Origins       []string `yaml:"origins"`         // 허용 출처 (예: https://damoang.net)
+}
+
+// MFAConfig 2단계 인증(TOTP) 설정
+type MFAConfig struct {
+	Issuer           string `yaml:"issuer"`             // 인증 앱에 보이는 서비스 이름
+	SecretKey        string `yaml:"secret_key"`         // TOTP 시크릿 봉인 키 — 비우면 평문 저장
+	RequireForAdmins bool   `yaml:"require_for_admins"` // true 면 RequireAdmin 라우트에 2단계 인증 필수
+}
+
+// CronConfig 내장 잡 설정 (플러그인 스케줄러에 plugin=cron 으로 등록)
+type CronConfig struct {
+	Enabled bool              `yaml:"enabled"` // false 면 자동 실행 없이 수동·/api/internal/cron 호출만
+	Jobs    map[string]string `yaml:"jobs"`    // 잡별 일정 오버라이드 (예: noti-cleanup: "40 4 * * *", off 면 자동 실행 끔)
+}
+
+// SearchConfig 통합 검색 백엔드 설정
+type SearchConfig struct {
+	// auto(기본): ES 가 연결되면 ES, 아니면 DB LIKE 폴백
+	// elasticsearch: ES 만 (연결 실패 시 DB 폴백)
+	// embedded: 프로세스 내 n-gram 색인 — ES 를 못 띄우는 단일 인스턴스 커뮤니티용
+	Backend string `yaml:"backend"`
+}
+
+// StorageConfig 미디어 저장소 설정 — S3-compatible object storage 또는 로컬 디스크
 type StorageConfig struct {
+	// Backend: s3(기본) | local — local 은 S3·MinIO 없이 LocalPath 에 저장하고 API 서버가 서명 URL 로 내려 준다
+	Backend string `yaml:"backend"`
+	// LocalPath 는 local 백엔드의 저장 디렉터리다 (비면 data_paths.upload_path)
+	LocalPath string `yaml:"local_path"`
+	// PublicURL 은 local 백엔드 파일 주소의 앞부분이다 (e.g. https://api.example.com/api/v2/media/files)
+	PublicURL string `yaml:"public_url"`
+	// SigningKey 는 local 파일 URL 서명 키다 (비면 jwt.secret 에서 파생) — 바꾸면 본문에 박힌 주소가 모두 막힌다
+	SigningKey string `yaml:"signing_key"`
+	// ImageVariants 는 이미지 업로드마다 만들 파생본 폭이다 ("thumb:320,list:640,full:1920", 비면 기본값)
+	ImageVariants string `yaml:"image_variants"`
+	// GCGraceDays 는 media-gc 가 참조 없는 파일을 지우기 전 기다리는 일수다 (비면 30) — 휴지통 복구 기간보다 길게
+	GCGraceDays int `yaml:"gc_grace_days"`
+	// UploadPlan 은 테넌트가 아닌 요청의 이어 올리기 한도 요금제다 (PlanLimits.MaxFileSize, 비면 business = 50MB)
+	UploadPlan string `yaml:"upload_plan"`
+
 	Endpoint        string `yaml:"endpoint"`
 	Region          string `yaml:"region"`
 	AccessKeyID     string `yaml:"access_key_id"`
@@ -45,6 +124,13 @@ type ElasticsearchConfig struct {
 // PluginsConfig 플러그인 설정
 type PluginsConfig struct {
 	Commerce CommercePluginConfig `yaml:"commerce"`
+	EventBus EventBusConfig       `yaml:"event_bus"`
+}
+
+// EventBusConfig 플러그인 이벤트 버스 설정
+type EventBusConfig struct {
+	Backend     string `yaml:"backend"`      // memory(기본) | redis — 여러 파드로 운영하면 redis
+	MaxAttempts int    `yaml:"max_attempts"` // 구독자별 시도 횟수 (0이면 기본 5)
 }
 
 // CommercePluginConfig Commerce 플러그인 설정
@@ -93,6 +179,19 @@ type JWTConfig struct {
 	SecretNext string `yaml:"secret_next"` // 키 롤오버용 보조 검증키(선택). 서명엔 미사용.
 	ExpiresIn  int    `yaml:"expires_in"`
 	RefreshIn  int    `yaml:"refresh_in"`
+
+	// SigningAlg: HS256(기본, 공유 시크릿) | ES256 | EdDSA — 비대칭이면 DB 의 서명키로 서명하고
+	// /.well-known/jwks.json 에 공개키를 싣는다. 서명키는 KeyRotateDays 마다 바뀐다(cron jwt-key-rotation).
+	SigningAlg    string `yaml:"signing_alg"`
+	KeyRotateDays int    `yaml:"key_rotate_days"` // 0 이면 30
+	KeySecret     string `yaml:"key_secret"`      // 서명키(개인키) 봉인용. 비우면 secret 을 쓴다
+	// AcceptHS256: nil(미설정)이면 true — 비대칭으로 옮긴 뒤에도 기존 HS256 토큰이 만료될 때까지 받는다
+	AcceptHS256 *bool `yaml:"accept_hs256"`
+}
+
+// AcceptsHS256 reports whether HS256 tokens are still accepted (기본 true)
+func (c JWTConfig) AcceptsHS256() bool {
+	return c.AcceptHS256 == nil || *c.AcceptHS256
 }
 
 // CORSConfig CORS 설정
@@ -163,6 +262,16 @@ func overrideFromEnv(cfg *Config) {
 	if next := os.Getenv("JWT_SECRET_NEXT"); next != "" {
 		cfg.JWT.SecretNext = next
 	}
+	if alg := os.Getenv("JWT_SIGNING_ALG"); alg != "" {
+		cfg.JWT.SigningAlg = alg
+	}
+	if keySecret := os.Getenv("JWT_KEY_SECRET"); keySecret != "" {
+		cfg.JWT.KeySecret = keySecret
+	}
+	if accept := os.Getenv("JWT_ACCEPT_HS256"); accept != "" {
+		v := accept == "true" || accept == "1"
+		cfg.JWT.AcceptHS256 = &v
+	}
 	// 서버 설정
 	if port := os.Getenv("API_PORT"); port != "" {
 		_, _ = fmt.Sscanf(port, "%d", &cfg.Server.Port) //nolint:errcheck // 파싱 실패 시 기본값 유지
@@ -178,6 +287,43 @@ func overrideFromEnv(cfg *Config) {
 		cfg.CORS.AllowOrigins = corsOrigins
 	}
 
+	// 플러그인 이벤트 버스
+	if backend := os.Getenv("PLUGIN_EVENT_BUS"); backend != "" {
+		cfg.Plugins.EventBus.Backend = backend
+	}
+
+	// in-process 잡 러너
+	if v := os.Getenv("CRON_SCHEDULER_ENABLED"); v != "" {
+		cfg.Cron.Enabled = v == "true" || v == "1"
+	}
+
+	// 2단계 인증
+	if key := os.Getenv("MFA_SECRET_KEY"); key != "" {
+		cfg.MFA.SecretKey = key
+	}
+	if v := os.Getenv("MFA_REQUIRE_FOR_ADMINS"); v != "" {
+		cfg.MFA.RequireForAdmins = v == "true" || v == "1"
+	}
+
+	// 패스키
+	if v := os.Getenv("WEBAUTHN_RP_ID"); v != "" {
+		cfg.WebAuthn.RPID = v
+	}
+	for i := range cfg.OAuth.Providers {
+		name := strings.ToUpper(strings.ReplaceAll(cfg.OAuth.Providers[i].Name, "-", "_"))
+		if v := os.Getenv("OAUTH_" + name + "_CLIENT_SECRET"); v != "" {
+			cfg.OAuth.Providers[i].ClientSecret = v
+		}
+	}
+	if v := os.Getenv("WEBAUTHN_ORIGINS"); v != "" {
+		cfg.WebAuthn.Origins = nil
+		for _, origin := range strings.Split(v, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				cfg.WebAuthn.Origins = append(cfg.WebAuthn.Origins, origin)
+			}
+		}
+	}
+
 	// Elasticsearch 설정
 	if esURL := os.Getenv("ELASTICSEARCH_URL"); esURL != "" {
 		cfg.Elasticsearch.Addresses = []string{esURL}
@@ -190,6 +336,11 @@ func overrideFromEnv(cfg *Config) {
 		cfg.Elasticsearch.Password = esPass
 	}
 
+	// 검색 백엔드
+	if backend := os.Getenv("SEARCH_BACKEND"); backend != "" {
+		cfg.Search.Backend = backend
+	}
+
 	// Storage (S3/R2) 설정
 	if endpoint := os.Getenv("S3_ENDPOINT"); endpoint != "" {
 		cfg.Storage.Endpoint = endpoint
@@ -210,6 +361,27 @@ func overrideFromEnv(cfg *Config) {
 	if cdnURL := os.Getenv("CDN_URL"); cdnURL != "" {
 		cfg.Storage.CDNURL = cdnURL
 	}
+	if backend := os.Getenv("STORAGE_BACKEND"); backend != "" {
+		cfg.Storage.Backend = backend
+	}
+	if localPath := os.Getenv("STORAGE_LOCAL_PATH"); localPath != "" {
+		cfg.Storage.LocalPath = localPath
+	}
+	if publicURL := os.Getenv("STORAGE_PUBLIC_URL"); publicURL != "" {
+		cfg.Storage.PublicURL = publicURL
+	}
+	if signingKey := os.Getenv("STORAGE_SIGNING_KEY"); signingKey != "" {
+		cfg.Storage.SigningKey = signingKey
+	}
+	if variants := os.Getenv("MEDIA_IMAGE_VARIANTS"); variants != "" {
+		cfg.Storage.ImageVariants = variants
+	}
+	if plan := os.Getenv("MEDIA_UPLOAD_PLAN"); plan != "" {
+		cfg.Storage.UploadPlan = plan
+	}
+	if days, err := strconv.Atoi(os.Getenv("MEDIA_GC_GRACE_DAYS")); err == nil && days > 0 {
+		cfg.Storage.GCGraceDays = days
+	}
 }
 
 // LogResolved logs the resolved configuration values (secrets masked).
@@ -267,6 +439,42 @@ func (c *RedisConfig) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// MediaStorage 미디어 저장소 설정 — backend 가 비면 storage.backend 를 쓴다 (cmd/storage-migrate 는 양쪽을 따로 연다).
+// local 저장 경로는 storage.local_path → data_paths.upload_path 순이고, 서명 키가 없으면 jwt.secret 에서 파생한다.
+func (c *Config) MediaStorage(backend string) storage.Config {
+	if backend == "" {
+		backend = c.Storage.Backend
+	}
+	localPath := c.Storage.LocalPath
+	if localPath == "" {
+		localPath = c.DataPaths.UploadPath
+	}
+	signingKey := c.Storage.SigningKey
+	if signingKey == "" && c.JWT.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(c.JWT.Secret))
+		mac.Write([]byte("media-url"))
+		signingKey = string(mac.Sum(nil))
+	}
+	return storage.Config{
+		Backend: backend,
+		S3: storage.S3Config{
+			Endpoint:        c.Storage.Endpoint,
+			Region:          c.Storage.Region,
+			AccessKeyID:     c.Storage.AccessKeyID,
+			SecretAccessKey: c.Storage.SecretAccessKey,
+			Bucket:          c.Storage.Bucket,
+			CDNURL:          c.Storage.CDNURL,
+			BasePath:        c.Storage.BasePath,
+			ForcePathStyle:  c.Storage.ForcePathStyle,
+		},
+		Local: storage.LocalConfig{
+			Root:       localPath,
+			PublicURL:  c.Storage.PublicURL,
+			SigningKey: signingKey,
+		},
+	}
+}
+
 // IsDevelopment 개발 환경 여부 확인
 func (c *Config) IsDevelopment() bool {
 	return c.Server.Env == "local" || c.Server.Env == "dev"
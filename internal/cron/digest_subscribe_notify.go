@@ -2,6 +2,8 @@ package cron
 
 import (
 	"fmt"
+	"log"
+	"strconv"
 	"strings"
 	"time"
 
@@ -145,6 +147,11 @@ func runDigestSubscribeNotify(db *gorm.DB) (*DigestSubscribeResult, error) {
 			}
 			if err := db.Create(noti).Error; err == nil {
 				res.NotisCreated++
+				// 게시판 요약 이메일을 켠 회원 — 일간·주간이면 email-digest-* 가 다른 알림과 함께 묶는다
+				if err := gnurepo.EnqueueEmail(db, sid, gnurepo.EmailCategoryDigest, "board_digest", fmt.Sprintf("/%s", board),
+					boardName, strconv.Itoa(count), strings.Join(previews, ", ")); err != nil {
+					log.Printf("[Cron:digest-subscribe-notify] email enqueue failed for %s: %v", sid, err)
+				}
 			}
 		}
 		res.PostsSummarized += count
@@ -0,0 +1,25 @@
+package cron
+
+import "context"
+
+// SetEmailDelivery injects the email notification sender (wired in main.go from
+// service.EmailNotificationService — MAIL_TRANSPORT 가 비어 있으면 주입하지 않고, 잡은 건너뛴 것으로 기록된다).
+//
+// 알림을 만드는 잡(point-expiry-notify·digest-subscribe-notify·withdrawal-grace-anonymize·process-approved-reports)은
+// gnurepo.EnqueueEmail 로 g5_noti_email_queue 에 쌓기만 한다. email-outbox 가 즉시 분류를 한 건씩,
+// email-digest-daily·weekly 가 요약 분류를 회원별 한 통으로 보낸다.
+func (h *Handler) SetEmailDelivery(
+	instant func(ctx context.Context) (interface{}, error),
+	digest func(ctx context.Context, frequency string) (interface{}, error),
+) {
+	h.emailInstant = instant
+	h.emailDigest = digest
+}
+
+// runEmailDigest runs the email-digest-daily·weekly jobs
+func (h *Handler) runEmailDigest(jc *JobContext, frequency string) (interface{}, error) {
+	if h.emailDigest == nil {
+		return map[string]string{"skipped": "mail transport not configured"}, nil
+	}
+	return h.emailDigest(jc.Ctx, frequency)
+}
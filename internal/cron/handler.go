@@ -1,11 +1,14 @@
 package cron
 
 import (
-	"log"
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/damoang/angple-backend/internal/plugin"
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
 	"github.com/gin-gonic/gin"
@@ -13,22 +16,30 @@ import (
 )
 
 // Handler handles internal cron job endpoints
+//
+// 잡은 plugin.Scheduler 가 in-process 로 돌린다. /api/internal/cron/* 는 외부 crontab 호환용으로 남겨 두며,
+// 같은 스케줄러를 거치므로 락·실행 기록·메트릭이 동일하게 적용된다.
 type Handler struct {
 	db                *gorm.DB
 	secret            string
+	scheduler         *plugin.Scheduler
 	pointConfigRepo   v2repo.PointConfigRepository
 	gnuPointWriteRepo v2repo.GnuboardPointWriteRepository
 	notiRepo          gnurepo.NotiRepository
 	givingSweep       func() (interface{}, error)
+	searchReconcile   func(ctx context.Context) (interface{}, error)
+	savedSearchAlerts func(ctx context.Context) (interface{}, error)
+	jwtKeyRotation    func(ctx context.Context) (interface{}, error)
+	emailInstant      func(ctx context.Context) (interface{}, error)
+	emailDigest       func(ctx context.Context, frequency string) (interface{}, error)
+	mediaGC           *MediaGCConfig
+	mediaUploadClean  func(ctx context.Context) (interface{}, error)
 }
 
 // NewHandler creates a new cron Handler
+// CRON_SECRET 이 비어 있으면 ?secret 검사는 생략하고 RequireInternalCron 미들웨어에만 맡긴다.
 func NewHandler(db *gorm.DB) *Handler {
-	secret := os.Getenv("CRON_SECRET")
-	if secret == "" {
-		secret = "angple_cron_2024"
-	}
-	return &Handler{db: db, secret: secret}
+	return &Handler{db: db, secret: os.Getenv("CRON_SECRET")}
 }
 
 // SetPointExpiryDeps sets dependencies for point expiry cron jobs
@@ -44,224 +55,142 @@ func (h *Handler) SetPointExpiryDeps(
 
 // verifySecret checks the secret query parameter
 func (h *Handler) verifySecret(c *gin.Context) bool {
-	if c.Query("secret") != h.secret {
+	if h.secret != "" && c.Query("secret") != h.secret {
 		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "invalid secret"})
 		return false
 	}
 	return true
 }
 
-// MemberLockRelease handles POST /api/internal/cron/member-lock-release
-func (h *Handler) MemberLockRelease(c *gin.Context) {
+// trigger runs the named job through the scheduler and writes the legacy response
+func (h *Handler) trigger(c *gin.Context, job string, now time.Time) {
 	if !h.verifySecret(c) {
 		return
 	}
-
-	result, err := runMemberLockRelease(h.db)
-	if err != nil {
-		log.Printf("[Cron:member-lock-release] error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "error": "cron jobs not registered"})
 		return
 	}
 
-	log.Printf("[Cron:member-lock-release] released %d members: %v", result.ReleasedCount, result.ReleasedIDs)
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
-}
-
-// UpdateMemberLevels handles POST /api/internal/cron/update-member-levels
-func (h *Handler) UpdateMemberLevels(c *gin.Context) {
-	if !h.verifySecret(c) {
-		return
+	ctx := c.Request.Context()
+	if !now.IsZero() {
+		ctx = context.WithValue(ctx, nowKey{}, now)
 	}
-
-	result, err := runUpdateMemberLevels(h.db)
+	run, err := h.scheduler.RunNow(ctx, SchedulerPlugin, job, plugin.RunRequest{Trigger: plugin.TriggerHTTP})
 	if err != nil {
-		log.Printf("[Cron:update-member-levels] error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		status := http.StatusInternalServerError
+		if errors.Is(err, plugin.ErrTaskBusy) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"success": false, "error": err.Error()})
 		return
 	}
-
-	log.Printf("[Cron:update-member-levels] updated %d members", result.UpdatedCount)
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
-}
-
-// ProcessApprovedReports handles POST /api/internal/cron/process-approved-reports
-func (h *Handler) ProcessApprovedReports(c *gin.Context) {
-	if !h.verifySecret(c) {
-		return
+	resp := gin.H{"success": true, "data": run.ResultJSON}
+	// 기존 응답은 "설정 안 됨"·"expiry disabled" 같은 안내를 최상위 message 로 돌려줬다 — 외부 crontab 스크립트 호환
+	var legacy struct {
+		Message string `json:"message"`
 	}
-
-	result, err := runProcessApprovedReports(h.db)
-	if err != nil {
-		log.Printf("[Cron:process-approved-reports] error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
-		return
+	if json.Unmarshal(run.ResultJSON, &legacy) == nil && legacy.Message != "" {
+		resp["message"] = legacy.Message
 	}
-
-	log.Printf("[Cron:process-approved-reports] processed %d, errors %d", result.Processed, result.Errors)
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+	c.JSON(http.StatusOK, resp)
 }
 
-func (h *Handler) runCronTask(
-	c *gin.Context,
-	label string,
-	run func() (interface{}, error),
-	logSuccess func(interface{}),
-) {
-	if !h.verifySecret(c) {
-		return
-	}
+// MemberLockRelease handles POST /api/internal/cron/member-lock-release
+func (h *Handler) MemberLockRelease(c *gin.Context) {
+	h.trigger(c, "member-lock-release", time.Time{})
+}
 
-	result, err := run()
-	if err != nil {
-		log.Printf("[Cron:%s] error: %v", label, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
-		return
-	}
+// UpdateMemberLevels handles POST /api/internal/cron/update-member-levels
+func (h *Handler) UpdateMemberLevels(c *gin.Context) {
+	h.trigger(c, "update-member-levels", time.Time{})
+}
 
-	logSuccess(result)
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+// ProcessApprovedReports handles POST /api/internal/cron/process-approved-reports
+func (h *Handler) ProcessApprovedReports(c *gin.Context) {
+	h.trigger(c, "process-approved-reports", time.Time{})
 }
 
 // DisciplineRelease handles POST /api/internal/cron/discipline-release
 // Restores levels and clears intercept dates for expired disciplines
 func (h *Handler) DisciplineRelease(c *gin.Context) {
-	h.runCronTask(c, "discipline-release", func() (interface{}, error) {
-		return runDisciplineRelease(h.db)
-	}, func(result interface{}) {
-		typed := result.(*DisciplineReleaseResult)
-		log.Printf("[Cron:discipline-release] levels restored: %d %v, intercepts released: %d %v",
-			typed.LevelRestoredCount, typed.LevelRestoredIDs,
-			typed.InterceptReleasedCount, typed.InterceptReleasedIDs)
-	})
+	h.trigger(c, "discipline-release", time.Time{})
 }
 
 // UpdateReportPattern handles POST /api/internal/cron/update-report-pattern
 // Optional query param: ?date=2026-03-22 to override reference date
 func (h *Handler) UpdateReportPattern(c *gin.Context) {
-	if !h.verifySecret(c) {
-		return
-	}
-
-	var result *ReportPatternResult
-	var err error
+	var now time.Time
 	if dateStr := c.Query("date"); dateStr != "" {
 		t, parseErr := time.Parse("2006-01-02", dateStr)
 		if parseErr != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "invalid date format, use YYYY-MM-DD"})
 			return
 		}
-		result, err = runUpdateReportPatternAt(h.db, t)
-	} else {
-		result, err = runUpdateReportPattern(h.db)
-	}
-	if err != nil {
-		log.Printf("[Cron:update-report-pattern] error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
-		return
+		now = t
 	}
+	h.trigger(c, "update-report-pattern", now)
+}
+
+// PointExpiry handles POST /api/internal/cron/point-expiry
+func (h *Handler) PointExpiry(c *gin.Context) {
+	h.trigger(c, "point-expiry", time.Time{})
+}
 
-	log.Printf("[Cron:update-report-pattern] report generated: %s", result.Subject)
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+// PointExpiryNotify handles POST /api/internal/cron/point-expiry-notify
+func (h *Handler) PointExpiryNotify(c *gin.Context) {
+	h.trigger(c, "point-expiry-notify", time.Time{})
 }
 
 // AutoPromote handles POST /api/internal/cron/auto-promote
 // Promotes members from mb_level 2 to 3 when conditions are met
 func (h *Handler) AutoPromote(c *gin.Context) {
-	if !h.verifySecret(c) {
-		return
-	}
-
-	result, err := runAutoPromote(h.db, h.notiRepo)
-	if err != nil {
-		log.Printf("[Cron:auto-promote] error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
-		return
-	}
-
-	log.Printf("[Cron:auto-promote] promoted %d members: %v", result.PromotedCount, result.PromotedIDs)
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+	h.trigger(c, "auto-promote", time.Time{})
 }
 
 // SyncVisibleCommentCounts handles POST /api/internal/cron/sync-visible-comment-counts
 func (h *Handler) SyncVisibleCommentCounts(c *gin.Context) {
-	h.runCronTask(c, "sync-visible-comment-counts", func() (interface{}, error) {
-		return runSyncVisibleCommentCounts(h.db)
-	}, func(result interface{}) {
-		typed := result.(*SyncVisibleCommentCountsResult)
-		log.Printf("[Cron:sync-visible-comment-counts] checked=%d synced=%d rows=%d errors=%d",
-			typed.BoardsChecked, typed.BoardsSynced, typed.RowsUpdated, typed.Errors)
-	})
+	h.trigger(c, "sync-visible-comment-counts", time.Time{})
 }
 
 // PopularSubscribeNotify handles POST /api/internal/cron/popular-subscribe-notify
 // level=2(인기글만) 게시판 구독자에게 추천 임계값 도달 글을 1회 알림 (#12607).
 func (h *Handler) PopularSubscribeNotify(c *gin.Context) {
-	h.runCronTask(c, "popular-subscribe-notify", func() (interface{}, error) {
-		return runPopularSubscribeNotify(h.db)
-	}, func(result interface{}) {
-		typed := result.(*PopularSubscribeResult)
-		log.Printf("[Cron:popular-subscribe-notify] boards=%d posts_notified=%d notis=%d",
-			typed.Boards, typed.PostsNotified, typed.NotisCreated)
-	})
+	h.trigger(c, "popular-subscribe-notify", time.Time{})
 }
 
 // DigestSubscribeNotify handles POST /api/internal/cron/digest-subscribe-notify
 // level=3(요약) 게시판 구독자에게 주기마다 새 글을 묶어 1건 알림 (#12607 P1).
 func (h *Handler) DigestSubscribeNotify(c *gin.Context) {
-	h.runCronTask(c, "digest-subscribe-notify", func() (interface{}, error) {
-		return runDigestSubscribeNotify(h.db)
-	}, func(result interface{}) {
-		typed := result.(*DigestSubscribeResult)
-		log.Printf("[Cron:digest-subscribe-notify] boards=%d seeded=%d posts=%d notis=%d",
-			typed.Boards, typed.Seeded, typed.PostsSummarized, typed.NotisCreated)
-	})
+	h.trigger(c, "digest-subscribe-notify", time.Time{})
 }
 
 // NotiCleanup handles POST /api/internal/cron/noti-cleanup
 // Prunes g5_na_noti (read + old, then very old regardless) to curb table bloat (#12607).
 func (h *Handler) NotiCleanup(c *gin.Context) {
-	h.runCronTask(c, "noti-cleanup", func() (interface{}, error) {
-		return runNotiCleanup(h.db)
-	}, func(result interface{}) {
-		typed := result.(*NotiCleanupResult)
-		log.Printf("[Cron:noti-cleanup] deleted=%d batches=%d last_id=%d capped=%v",
-			typed.Deleted, typed.Batches, typed.LastID, typed.Capped)
-	})
+	h.trigger(c, "noti-cleanup", time.Time{})
 }
 
 // WithdrawalGraceAnonymize handles POST /api/internal/cron/withdrawal-grace-anonymize
 // 숙려기간(30일) 경과한 탈퇴 신청 계정을 확정 익명화(닉네임만, 식별자 보존). 멱등.
 func (h *Handler) WithdrawalGraceAnonymize(c *gin.Context) {
-	h.runCronTask(c, "withdrawal-grace-anonymize", func() (interface{}, error) {
-		return runWithdrawalGraceAnonymize(h.db)
-	}, func(result interface{}) {
-		typed := result.(*WithdrawalGraceResult)
-		log.Printf("[Cron:withdrawal-grace-anonymize] candidates=%d anonymized=%d posts=%d skipped=%d errors=%d ids=%v",
-			typed.CandidateCount, typed.AnonymizedCount, typed.PostsUpdated, typed.SkippedCount, typed.Errors, typed.AnonymizedIDs)
-	})
+	h.trigger(c, "withdrawal-grace-anonymize", time.Time{})
 }
 
 // AutoDismissReports handles POST /api/internal/cron/auto-dismiss-reports
 // 만장일치 미처리(2명 이상 dismiss + action 0건) 신고를 처리자 'system'으로 자동 기각.
 // singo_settings.auto_dismiss_enabled = 'true' 일 때만 동작.
 func (h *Handler) AutoDismissReports(c *gin.Context) {
-	h.runCronTask(c, "auto-dismiss-reports", func() (interface{}, error) {
-		return runAutoDismissReports(h.db)
-	}, func(result interface{}) {
-		typed := result.(*AutoDismissResult)
-		log.Printf("[Cron:auto-dismiss-reports] enabled=%v min=%d candidates=%d dismissed=%d errors=%d",
-			typed.Enabled, typed.MinOpinions, typed.CandidateCount, typed.DismissedRows, typed.Errors)
-	})
+	h.trigger(c, "auto-dismiss-reports", time.Time{})
 }
 
 // VerificationGuide assigns a 6-digit 난수 and posts an AI(다모앙) guide comment to new
 // posts on the verification(해외 실명인증) board. Idempotent via wr_1.
 func (h *Handler) VerificationGuide(c *gin.Context) {
-	h.runCronTask(c, "verification-guide", func() (interface{}, error) {
-		return runVerificationGuide(h.db)
-	}, func(result interface{}) {
-		typed := result.(*VerificationGuideResult)
-		log.Printf("[Cron:verification-guide] processed=%d errors=%d", typed.Processed, typed.Errors)
-	})
+	h.trigger(c, "verification-guide", time.Time{})
+}
+
+// GivingDrawSweep handles POST /api/internal/cron/giving-draw-sweep
+func (h *Handler) GivingDrawSweep(c *gin.Context) {
+	h.trigger(c, "giving-draw-sweep", time.Time{})
 }
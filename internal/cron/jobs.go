@@ -0,0 +1,370 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// JobContext is passed to every job run.
+// dry-run 이면 DB 는 실행 후 롤백되는 트랜잭션이다.
+type JobContext struct {
+	Ctx    context.Context
+	DB     *gorm.DB
+	Now    time.Time // 기준 시각 override (zero 면 현재)
+	DryRun bool
+}
+
+// Job is one entry of the declarative schedule table.
+type Job struct {
+	Name        string
+	Description string
+	Schedule    string        // 5필드 cron 표현식 (Asia/Seoul 기준), 빈 값이면 수동 실행만
+	Timeout     time.Duration // 초과 시 ctx 취소 → 진행 중 쿼리 중단
+	DryRun      bool          // DB 밖 부작용이 없어 트랜잭션 롤백으로 dry-run 가능한지
+	Run         func(jc *JobContext) (interface{}, error)
+	Summary     func(result interface{}) string // 로그 한 줄 요약
+}
+
+// ErrGivingSweepNotWired is returned when the giving sweep closure was not injected.
+var ErrGivingSweepNotWired = errors.New("giving sweep not wired")
+
+// Jobs returns the built-in schedule table.
+// 기존 run* 함수는 그대로 두고 JobContext.DB 만 넘긴다.
+func (h *Handler) Jobs() []Job {
+	return []Job{
+		{
+			Name:        "member-lock-release",
+			Description: "잠금(mb_4=lock) 회원 해제",
+			Schedule:    "*/10 * * * *",
+			Timeout:     5 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runMemberLockRelease(jc.DB) },
+			Summary: func(result interface{}) string {
+				typed := result.(*MemberLockResult)
+				return fmt.Sprintf("released %d members: %v", typed.ReleasedCount, typed.ReleasedIDs)
+			},
+		},
+		{
+			Name:        "update-member-levels",
+			Description: "광고주 회원 레벨·게시글 공개 상태 갱신",
+			Schedule:    "5 0 * * *",
+			Timeout:     10 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runUpdateMemberLevels(jc.DB) },
+			Summary: func(result interface{}) string {
+				return fmt.Sprintf("updated %d members", result.(*MemberLevelsResult).UpdatedCount)
+			},
+		},
+		{
+			Name:        "process-approved-reports",
+			Description: "승인된 신고 제재 처리",
+			Schedule:    "*/5 * * * *",
+			Timeout:     10 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runProcessApprovedReports(jc.DB) },
+			Summary: func(result interface{}) string {
+				typed := result.(*ProcessReportsResult)
+				return fmt.Sprintf("processed %d, errors %d", typed.Processed, typed.Errors)
+			},
+		},
+		{
+			Name:        "update-report-pattern",
+			Description: "지난주 신고 패턴 리포트 작성",
+			Schedule:    "0 9 * * MON",
+			Timeout:     15 * time.Minute,
+			DryRun:      true,
+			Run: func(jc *JobContext) (interface{}, error) {
+				if jc.Now.IsZero() {
+					return runUpdateReportPattern(jc.DB)
+				}
+				return runUpdateReportPatternAt(jc.DB, jc.Now)
+			},
+			Summary: func(result interface{}) string {
+				return "report generated: " + result.(*ReportPatternResult).Subject
+			},
+		},
+		{
+			Name:        "discipline-release",
+			Description: "만료된 이용제한 해제·레벨 복구",
+			Schedule:    "*/10 * * * *",
+			Timeout:     5 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runDisciplineRelease(jc.DB) },
+			Summary: func(result interface{}) string {
+				typed := result.(*DisciplineReleaseResult)
+				return fmt.Sprintf("levels restored: %d %v, intercepts released: %d %v",
+					typed.LevelRestoredCount, typed.LevelRestoredIDs,
+					typed.InterceptReleasedCount, typed.InterceptReleasedIDs)
+			},
+		},
+		{
+			Name:        "point-expiry",
+			Description: "유효기간 지난 포인트 만료",
+			Schedule:    "30 3 * * *",
+			Timeout:     10 * time.Minute,
+			Run: func(*JobContext) (interface{}, error) {
+				return runPointExpiry(h.pointConfigRepo, h.gnuPointWriteRepo)
+			},
+			Summary: func(result interface{}) string {
+				typed := result.(*PointExpiryResult)
+				return fmt.Sprintf("expired %d point entries %s", typed.ExpiredCount, typed.Message)
+			},
+		},
+		{
+			Name:        "point-expiry-notify",
+			Description: "7일 내 만료 예정 포인트 알림",
+			Schedule:    "0 10 * * *",
+			Timeout:     10 * time.Minute,
+			Run: func(jc *JobContext) (interface{}, error) {
+				return runPointExpiryNotify(jc.DB, h.pointConfigRepo, h.gnuPointWriteRepo, h.notiRepo)
+			},
+			Summary: func(result interface{}) string {
+				typed := result.(*PointExpiryNotifyResult)
+				return fmt.Sprintf("notified %d members %s", typed.NotifiedCount, typed.Message)
+			},
+		},
+		{
+			Name:        "auto-promote",
+			Description: "조건 충족 회원 2→3 레벨 자동 승급",
+			Schedule:    "0 * * * *",
+			Timeout:     10 * time.Minute,
+			Run:         func(jc *JobContext) (interface{}, error) { return runAutoPromote(jc.DB, h.notiRepo) },
+			Summary: func(result interface{}) string {
+				typed := result.(*AutoPromoteResult)
+				return fmt.Sprintf("promoted %d members: %v", typed.PromotedCount, typed.PromotedIDs)
+			},
+		},
+		{
+			Name:        "sync-visible-comment-counts",
+			Description: "게시판별 노출 댓글 수 재집계",
+			Schedule:    "15 4 * * *",
+			Timeout:     30 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runSyncVisibleCommentCounts(jc.DB) },
+			Summary: func(result interface{}) string {
+				typed := result.(*SyncVisibleCommentCountsResult)
+				return fmt.Sprintf("checked=%d synced=%d rows=%d errors=%d",
+					typed.BoardsChecked, typed.BoardsSynced, typed.RowsUpdated, typed.Errors)
+			},
+		},
+		{
+			Name:        "popular-subscribe-notify",
+			Description: "인기글 구독자 알림 (#12607)",
+			Schedule:    "*/10 * * * *",
+			Timeout:     5 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runPopularSubscribeNotify(jc.DB) },
+			Summary: func(result interface{}) string {
+				typed := result.(*PopularSubscribeResult)
+				return fmt.Sprintf("boards=%d posts_notified=%d notis=%d",
+					typed.Boards, typed.PostsNotified, typed.NotisCreated)
+			},
+		},
+		{
+			Name:        "digest-subscribe-notify",
+			Description: "요약 구독자 새 글 묶음 알림 (#12607 P1)",
+			Schedule:    "0 8,20 * * *",
+			Timeout:     10 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runDigestSubscribeNotify(jc.DB) },
+			Summary: func(result interface{}) string {
+				typed := result.(*DigestSubscribeResult)
+				return fmt.Sprintf("boards=%d seeded=%d posts=%d notis=%d",
+					typed.Boards, typed.Seeded, typed.PostsSummarized, typed.NotisCreated)
+			},
+		},
+		{
+			Name:        "noti-cleanup",
+			Description: "오래된 알림(g5_na_noti) 정리",
+			Schedule:    "40 4 * * *",
+			Timeout:     30 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runNotiCleanup(jc.DB) },
+			Summary: func(result interface{}) string {
+				typed := result.(*NotiCleanupResult)
+				return fmt.Sprintf("deleted=%d batches=%d last_id=%d capped=%v",
+					typed.Deleted, typed.Batches, typed.LastID, typed.Capped)
+			},
+		},
+		{
+			Name:        "auto-dismiss-reports",
+			Description: "만장일치 미처리 신고 자동 기각",
+			Schedule:    "*/30 * * * *",
+			Timeout:     5 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runAutoDismissReports(jc.DB) },
+			Summary: func(result interface{}) string {
+				typed := result.(*AutoDismissResult)
+				return fmt.Sprintf("enabled=%v min=%d candidates=%d dismissed=%d errors=%d",
+					typed.Enabled, typed.MinOpinions, typed.CandidateCount, typed.DismissedRows, typed.Errors)
+			},
+		},
+		{
+			Name:        "withdrawal-grace-anonymize",
+			Description: "숙려기간 경과 탈퇴 계정 확정 익명화",
+			Schedule:    "0 5 * * *",
+			Timeout:     30 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runWithdrawalGraceAnonymize(jc.DB) },
+			Summary: func(result interface{}) string {
+				typed := result.(*WithdrawalGraceResult)
+				return fmt.Sprintf("candidates=%d anonymized=%d posts=%d skipped=%d warned=%d errors=%d ids=%v",
+					typed.CandidateCount, typed.AnonymizedCount, typed.PostsUpdated, typed.SkippedCount, typed.WarnedCount, typed.Errors, typed.AnonymizedIDs)
+			},
+		},
+		{
+			Name:        "verification-guide",
+			Description: "해외 실명인증 게시판 난수·안내 댓글",
+			Schedule:    "*/5 * * * *",
+			Timeout:     5 * time.Minute,
+			DryRun:      true,
+			Run:         func(jc *JobContext) (interface{}, error) { return runVerificationGuide(jc.DB) },
+			Summary: func(result interface{}) string {
+				typed := result.(*VerificationGuideResult)
+				return fmt.Sprintf("processed=%d errors=%d", typed.Processed, typed.Errors)
+			},
+		},
+		{
+			Name:        "giving-draw-sweep",
+			Description: "마감 지난 나눔 자동 개표·개표 독촉",
+			Schedule:    "*/10 * * * *",
+			Timeout:     10 * time.Minute,
+			Run: func(*JobContext) (interface{}, error) {
+				if h.givingSweep == nil {
+					return nil, ErrGivingSweepNotWired
+				}
+				return h.givingSweep()
+			},
+			Summary: func(result interface{}) string { return fmt.Sprintf("%+v", result) },
+		},
+		{
+			Name:        "search-reconcile",
+			Description: "검색 색인 정합성 검사·어긋난 게시판 재색인",
+			Schedule:    "50 4 * * *",
+			Timeout:     time.Hour,
+			Run: func(jc *JobContext) (interface{}, error) {
+				if h.searchReconcile == nil {
+					return map[string]string{"skipped": "search not configured"}, nil
+				}
+				return h.searchReconcile(jc.Ctx)
+			},
+			Summary: func(result interface{}) string { return fmt.Sprintf("%+v", result) },
+		},
+		{
+			Name:        "saved-search-alerts",
+			Description: "저장한 검색 새 글 알림",
+			Schedule:    "*/15 * * * *",
+			Timeout:     10 * time.Minute,
+			Run: func(jc *JobContext) (interface{}, error) {
+				if h.savedSearchAlerts == nil {
+					return map[string]string{"skipped": "search not configured"}, nil
+				}
+				return h.savedSearchAlerts(jc.Ctx)
+			},
+			Summary: func(result interface{}) string { return fmt.Sprintf("%+v", result) },
+		},
+		{
+			Name:        "jwt-key-rotation",
+			Description: "JWT 서명키 미리 공개·이전 키 은퇴",
+			Schedule:    "17 * * * *",
+			Timeout:     time.Minute,
+			Run: func(jc *JobContext) (interface{}, error) {
+				if h.jwtKeyRotation == nil {
+					return map[string]string{"skipped": "jwt signing_alg is HS256"}, nil
+				}
+				return h.jwtKeyRotation(jc.Ctx)
+			},
+			Summary: func(result interface{}) string { return fmt.Sprintf("%+v", result) },
+		},
+		{
+			Name:        "email-outbox",
+			Description: "이메일 알림 즉시 발송",
+			Schedule:    "*/2 * * * *",
+			Timeout:     5 * time.Minute,
+			Run: func(jc *JobContext) (interface{}, error) {
+				if h.emailInstant == nil {
+					return map[string]string{"skipped": "mail transport not configured"}, nil
+				}
+				return h.emailInstant(jc.Ctx)
+			},
+			Summary: func(result interface{}) string { return fmt.Sprintf("%+v", result) },
+		},
+		{
+			Name:        "email-digest-daily",
+			Description: "이메일 알림 일간 요약 발송",
+			Schedule:    "0 8 * * *",
+			Timeout:     30 * time.Minute,
+			Run:         func(jc *JobContext) (interface{}, error) { return h.runEmailDigest(jc, "daily") },
+			Summary:     func(result interface{}) string { return fmt.Sprintf("%+v", result) },
+		},
+		{
+			Name:        "email-digest-weekly",
+			Description: "이메일 알림 주간 요약 발송",
+			Schedule:    "0 8 * * MON",
+			Timeout:     30 * time.Minute,
+			Run:         func(jc *JobContext) (interface{}, error) { return h.runEmailDigest(jc, "weekly") },
+			Summary:     func(result interface{}) string { return fmt.Sprintf("%+v", result) },
+		},
+		{
+			Name:        "media-upload-cleanup",
+			Description: "만료된 이어 올리기 업로드·조각 정리",
+			Schedule:    "20 * * * *",
+			Timeout:     15 * time.Minute,
+			Run: func(jc *JobContext) (interface{}, error) {
+				if h.mediaUploadClean == nil {
+					return map[string]string{"skipped": "media storage not configured"}, nil
+				}
+				return h.mediaUploadClean(jc.Ctx)
+			},
+			Summary: func(result interface{}) string { return fmt.Sprintf("%+v", result) },
+		},
+		{
+			Name:        "media-gc",
+			Description: "미디어 참조 재집계·유예기간 지난 고아 파일 삭제",
+			Schedule:    "30 5 * * *",
+			Timeout:     2 * time.Hour,
+			DryRun:      true,
+			Run: func(jc *JobContext) (interface{}, error) {
+				if h.mediaGC == nil {
+					return map[string]string{"skipped": "media storage not configured"}, nil
+				}
+				return runMediaGC(jc, h.mediaGC)
+			},
+			Summary: func(result interface{}) string {
+				typed, ok := result.(*MediaGCResult)
+				if !ok {
+					return fmt.Sprintf("%+v", result)
+				}
+				return fmt.Sprintf("dry_run=%v scanned=%d refs=%d stale=%d orphaned=%d candidates=%d(%dB) deleted=%d(%dB) errors=%d %s",
+					typed.DryRun, typed.RowsScanned, typed.RefsLinked, typed.StaleRefs, typed.Orphaned,
+					typed.Candidates, typed.CandidateBytes, typed.Deleted, typed.DeletedBytes, typed.Errors, typed.Skipped)
+			},
+		},
+	}
+}
+
+// WithSchedules overrides job schedules from config.
+// 값이 "off" 면 자동 실행만 끄고 수동 실행은 남긴다. 모르는 잡 이름은 에러.
+func WithSchedules(jobs []Job, overrides map[string]string) ([]Job, error) {
+	index := make(map[string]int, len(jobs))
+	for i, j := range jobs {
+		index[j.Name] = i
+	}
+	for name, spec := range overrides {
+		i, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("cron: unknown job %q in schedule overrides", name)
+		}
+		spec = strings.TrimSpace(spec)
+		if strings.EqualFold(spec, "off") {
+			spec = ""
+		}
+		jobs[i].Schedule = spec
+	}
+	return jobs, nil
+}
@@ -0,0 +1,12 @@
+package cron
+
+import "context"
+
+// SetJWTKeyRotation injects the JWT signing key rotation (wired in main.go from
+// v2 SigningKeyService — jwt.signing_alg 가 HS256 이면 주입하지 않고, 잡은 건너뛴 것으로 기록된다).
+//
+// jwt-key-rotation 잡: 활성 키 수명이 하루 남으면 다음 키를 만들어 JWKS 에 미리 싣고, 새 키가 활성되면
+// 이전 키의 은퇴 시각(리프레시 토큰 수명 뒤)을 정하고, 은퇴한 키를 지운다.
+func (h *Handler) SetJWTKeyRotation(fn func(ctx context.Context) (interface{}, error)) {
+	h.jwtKeyRotation = fn
+}
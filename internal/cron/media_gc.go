@@ -0,0 +1,466 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/damoang/angple-backend/pkg/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MediaGCConfig wires the media-gc job
+type MediaGCConfig struct {
+	Store storage.Backend
+	// URLBases 는 본문의 절대 주소 중 우리 저장소 파일로 볼 앞부분이다 (CDN_URL, local public_url, S3 origin).
+	// /data/, data/ 로 시작하는 상대 주소는 늘 우리 파일이다 (cmd/rewrite-media-urls 와 같은 규칙).
+	URLBases []string
+	// Grace 는 참조가 없어진 뒤(또는 올린 뒤) 지우기까지 기다리는 기간이다 — 휴지통 복구 기간보다 길어야 한다
+	Grace time.Duration
+}
+
+// DefaultMediaGCGrace 는 Grace 를 정하지 않았을 때의 유예기간이다
+const DefaultMediaGCGrace = 30 * 24 * time.Hour
+
+const (
+	mediaScanBatch = 500
+	// mediaGCMaxDeletes 는 한 번에 지우는 최대 객체 수다 — 스캔이 크게 어긋났을 때 피해를 제한한다
+	mediaGCMaxDeletes = 5000
+	mediaGCSampleSize = 100
+)
+
+// MediaGCCandidate is one object the job deletes (dry-run 이면 지울 예정인 객체)
+type MediaGCCandidate struct {
+	Key        string    `json:"key"`
+	Size       int64     `json:"size"`
+	OrphanedAt time.Time `json:"orphaned_at"`
+}
+
+// MediaGCResult is the media-gc report
+type MediaGCResult struct {
+	DryRun         bool               `json:"dry_run"`
+	RowsScanned    int                `json:"rows_scanned"`
+	RefsLinked     int                `json:"refs_linked"`
+	StaleRefs      int64              `json:"stale_refs"`
+	Orphaned       int64              `json:"orphaned"`
+	Candidates     int                `json:"candidates"`
+	CandidateBytes int64              `json:"candidate_bytes"`
+	Deleted        int                `json:"deleted"`
+	DeletedBytes   int64              `json:"deleted_bytes"`
+	Errors         int                `json:"errors"`
+	ErrorSources   []string           `json:"error_sources,omitempty"`
+	Skipped        string             `json:"skipped,omitempty"`
+	Sample         []MediaGCCandidate `json:"sample,omitempty"`
+}
+
+// SetMediaGC wires the media-gc job (저장소가 없으면 호출하지 않는다 — 잡은 건너뛴다)
+func (h *Handler) SetMediaGC(cfg MediaGCConfig) {
+	if cfg.Grace <= 0 {
+		cfg.Grace = DefaultMediaGCGrace
+	}
+	h.mediaGC = &cfg
+}
+
+// SetMediaUploadCleanup wires media-upload-cleanup (만료된 이어 올리기 조각 정리 — MediaService.CleanupUploads)
+func (h *Handler) SetMediaUploadCleanup(fn func(ctx context.Context) (interface{}, error)) {
+	h.mediaUploadClean = fn
+}
+
+// mediaAttrPattern 은 본문의 src·href 주소다 (cmd/rewrite-media-urls 가 다루는 것과 같은 따옴표 형태)
+var mediaAttrPattern = regexp.MustCompile(`(?:src|href)=["']([^"']+)["']`)
+
+// extractMediaHashes returns the content hashes of our media referenced in an HTML body
+func extractMediaHashes(content string, bases []string) []string {
+	var sums []string
+	for _, m := range mediaAttrPattern.FindAllStringSubmatch(content, -1) {
+		if sum, ok := mediaURLHash(m[1], bases); ok {
+			sums = append(sums, sum)
+		}
+	}
+	return sums
+}
+
+// mediaURLHash returns the content hash of a media URL or key when it points at our storage
+func mediaURLHash(raw string, bases []string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false
+	}
+	ours := !strings.Contains(raw, "://") && !strings.HasPrefix(raw, "//") // 상대 주소·저장소 key
+	for _, base := range bases {
+		if base != "" && strings.HasPrefix(raw, base) {
+			ours = true
+			break
+		}
+	}
+	if !ours {
+		return "", false
+	}
+	// GetCDNURL 은 key 를 PathEscape 한다 (images%2Fab%2F…)
+	if unescaped, err := url.PathUnescape(raw); err == nil {
+		raw = unescaped
+	}
+	return storage.ContentHash(raw)
+}
+
+// mediaScan collects refs of one source and links them to known objects
+type mediaScan struct {
+	db        *gorm.DB
+	bases     []string
+	scannedAt time.Time
+	result    *MediaGCResult
+}
+
+// link upserts refs (refKey → hashes) of refType, touching scanned_at of refs that still exist
+func (s *mediaScan) link(refType string, refs map[string][]string) error {
+	unique := make(map[string]bool)
+	for _, sums := range refs {
+		for _, sum := range sums {
+			unique[sum] = true
+		}
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+	sums := make([]string, 0, len(unique))
+	for sum := range unique {
+		sums = append(sums, sum)
+	}
+	var objects []v2domain.V2MediaObject
+	if err := s.db.Select("id, sha256").Where("sha256 IN ?", sums).Find(&objects).Error; err != nil {
+		return err
+	}
+	ids := make(map[string]uint64, len(objects))
+	for _, o := range objects {
+		ids[o.SHA256] = o.ID
+	}
+
+	var rows []v2domain.V2MediaRef
+	seen := make(map[string]bool)
+	for refKey, sums := range refs {
+		for _, sum := range sums {
+			id, ok := ids[sum]
+			if !ok {
+				continue // 기록 없는 파일 (dedup 이전 업로드·외부 파일) — GC 대상이 아니다
+			}
+			k := fmt.Sprintf("%d/%s", id, refKey)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			rows = append(rows, v2domain.V2MediaRef{ObjectID: id, RefType: refType, RefKey: refKey, ScannedAt: s.scannedAt})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "object_id"}, {Name: "ref_type"}, {Name: "ref_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scanned_at"}),
+	}).CreateInBatches(rows, mediaScanBatch).Error
+	if err == nil {
+		s.result.RefsLinked += len(rows)
+	}
+	return err
+}
+
+func (s *mediaScan) fail(source string, err error) {
+	s.result.Errors++
+	s.result.ErrorSources = append(s.result.ErrorSources, source)
+	log.Printf("[Cron:media-gc] scan %s error=%v", source, err)
+}
+
+// scanBoards links post·comment bodies and wr_10 of every board. 삭제된 글(wr_deleted_at)은 참조로 치지 않는다.
+func (s *mediaScan) scanBoards() {
+	var boards []string
+	if err := s.db.Table("g5_board").Select("bo_table").Where("bo_table <> ''").Scan(&boards).Error; err != nil {
+		s.fail("g5_board", err)
+		return
+	}
+	for _, boardID := range boards {
+		if !safeBoardTable.MatchString(boardID) {
+			continue
+		}
+		table := "g5_write_" + boardID
+		if !s.db.Migrator().HasTable(table) {
+			continue
+		}
+		if err := s.scanBoard(boardID, table); err != nil {
+			s.fail(table, err)
+		}
+	}
+}
+
+func (s *mediaScan) scanBoard(boardID, table string) error {
+	type row struct {
+		WrID        int    `gorm:"column:wr_id"`
+		WrIsComment int    `gorm:"column:wr_is_comment"`
+		WrContent   string `gorm:"column:wr_content"`
+		Wr10        string `gorm:"column:wr_10"`
+	}
+	lastID := 0
+	for {
+		var rows []row
+		if err := s.db.Table(table).
+			Select("wr_id, wr_is_comment, wr_content, wr_10").
+			Where("wr_id > ? AND wr_deleted_at IS NULL", lastID).
+			Order("wr_id").Limit(mediaScanBatch).
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		posts := make(map[string][]string)
+		comments := make(map[string][]string)
+		for _, r := range rows {
+			lastID = r.WrID
+			sums := extractMediaHashes(r.WrContent, s.bases)
+			if sum, ok := mediaURLHash(r.Wr10, s.bases); ok {
+				sums = append(sums, sum)
+			}
+			if len(sums) == 0 {
+				continue
+			}
+			key := fmt.Sprintf("%s:%d", boardID, r.WrID)
+			if r.WrIsComment == 1 {
+				comments[key] = sums
+			} else {
+				posts[key] = sums
+			}
+		}
+		s.result.RowsScanned += len(rows)
+		if err := s.link(v2domain.MediaRefPost, posts); err != nil {
+			return err
+		}
+		if err := s.link(v2domain.MediaRefComment, comments); err != nil {
+			return err
+		}
+	}
+}
+
+// mediaRefSource is one column that can hold our media (주소·key 하나, 또는 src·href 가 든 HTML 본문)
+type mediaRefSource struct {
+	Table   string
+	ID      string // ref_key 가 되는 식 (Prefix 가 앞에 붙는다)
+	Prefix  string // 다른 원본과 ref_key 가 겹치지 않게 ("v2_posts:")
+	Column  string
+	RefType string
+	HTML    bool   // 본문이면 src·href 를 모두 찾는다
+	Where   string // 살아 있는 행만 (삭제된 글 제외) — 비면 전부
+}
+
+// mediaRefSources lists every column outside g5_write_* that can point at stored media.
+// ⛔ 새 테이블·컬럼에 업로드 주소를 저장하면 여기에 더해야 한다 — 빠진 곳의 파일은 유예기간 뒤 지워진다.
+func mediaRefSources(db *gorm.DB) []mediaRefSource {
+	boardFileKey := "CONCAT(bo_table, ':', wr_id)"
+	if db.Dialector.Name() == "sqlite" {
+		boardFileKey = "bo_table || ':' || wr_id"
+	}
+	return []mediaRefSource{
+		{Table: "g5_board_file", ID: boardFileKey, Column: "bf_fileurl", RefType: v2domain.MediaRefPost},
+		{Table: "g5_member", ID: "mb_id", Column: "mb_image_url", RefType: v2domain.MediaRefProfile},
+		{Table: "site_logos", ID: "id", Column: "logo_url", RefType: v2domain.MediaRefSiteLogo},
+		{Table: "g5_memo_attachment", ID: "id", Column: "file_key", RefType: v2domain.MediaRefMessage},
+		{Table: "g5_board", ID: "bo_table", Prefix: "g5_board:", Column: "bo_insert_content", RefType: v2domain.MediaRefPage, HTML: true},
+		{Table: "g5_content", ID: "co_id", Prefix: "g5_content:", Column: "co_content", RefType: v2domain.MediaRefPage, HTML: true},
+		{Table: "g5_content", ID: "co_id", Prefix: "g5_content:", Column: "co_mobile_content", RefType: v2domain.MediaRefPage, HTML: true},
+		{Table: "v2_posts", ID: "id", Prefix: "v2_posts:", Column: "content", RefType: v2domain.MediaRefPost, HTML: true,
+			Where: "status <> 'deleted' AND deleted_at IS NULL"},
+		{Table: "v2_comments", ID: "id", Prefix: "v2_comments:", Column: "content", RefType: v2domain.MediaRefComment, HTML: true,
+			Where: "status <> 'deleted' AND deleted_at IS NULL"},
+		{Table: "v2_files", ID: "id", Prefix: "v2_files:", Column: "storage_path", RefType: v2domain.MediaRefPost},
+		{Table: "v2_content_revisions", ID: "id", Prefix: "v2_content_revisions:", Column: "content", RefType: v2domain.MediaRefRevision, HTML: true},
+		{Table: "v2_users", ID: "id", Prefix: "v2_users:", Column: "avatar_url", RefType: v2domain.MediaRefProfile},
+		{Table: "banners", ID: "id", Prefix: "banners:", Column: "image_url", RefType: v2domain.MediaRefBanner},
+		{Table: "promotion_posts", ID: "id", Prefix: "promotion_posts:", Column: "image_url", RefType: v2domain.MediaRefPromotion},
+		{Table: "promotion_posts", ID: "id", Prefix: "promotion_posts:", Column: "content", RefType: v2domain.MediaRefPromotion, HTML: true},
+		{Table: "site_settings", ID: "site_id", Prefix: "site_settings:", Column: "logo_url", RefType: v2domain.MediaRefSiteLogo},
+		{Table: "site_settings", ID: "site_id", Prefix: "site_settings:", Column: "favicon_url", RefType: v2domain.MediaRefSiteLogo},
+	}
+}
+
+// scanSource links one column of mediaRefSources. 테이블·컬럼이 없는 설치는 건너뛴다.
+func (s *mediaScan) scanSource(src mediaRefSource) {
+	if !s.db.Migrator().HasColumn(src.Table, src.Column) {
+		return
+	}
+	type row struct {
+		ID  string `gorm:"column:ref_id"`
+		Ref string `gorm:"column:ref"`
+	}
+	source := src.Table + "." + src.Column
+	offset := 0
+	for {
+		q := s.db.Table(src.Table).
+			Select(fmt.Sprintf("%s AS ref_id, %s AS ref", src.ID, src.Column)).
+			Where(src.Column + " IS NOT NULL AND " + src.Column + " <> ''")
+		if src.Where != "" {
+			q = q.Where(src.Where)
+		}
+		var rows []row
+		if err := q.Order(src.ID).Offset(offset).Limit(mediaScanBatch).Scan(&rows).Error; err != nil {
+			s.fail(source, err)
+			return
+		}
+		if len(rows) == 0 {
+			return
+		}
+		offset += len(rows)
+		refs := make(map[string][]string)
+		for _, r := range rows {
+			var sums []string
+			if src.HTML {
+				sums = extractMediaHashes(r.Ref, s.bases)
+			} else if sum, ok := mediaURLHash(r.Ref, s.bases); ok {
+				sums = []string{sum}
+			}
+			if len(sums) > 0 {
+				key := src.Prefix + r.ID
+				refs[key] = append(refs[key], sums...)
+			}
+		}
+		s.result.RowsScanned += len(rows)
+		if err := s.link(src.RefType, refs); err != nil {
+			s.fail(source, err)
+			return
+		}
+	}
+}
+
+// runMediaGC rescans every reference to stored media, recounts v2_media_objects.ref_count and deletes
+// objects left unreferenced longer than the grace period.
+//
+// 스캔 중 한 곳이라도 실패하면 참조를 덜 센 것일 수 있으므로 오래된 참조도 지우지 않고 아무것도 삭제하지 않는다.
+// dry-run 은 DB 변경이 롤백되고 저장소는 건드리지 않으며, 지울 객체 목록(Sample)만 보고한다.
+//
+// ⛔ 삭제된 글(wr_deleted_at)의 파일은 유예기간 뒤에 지워진다 — 그보다 늦게 복구한 글은 이미지가 깨진다.
+// ⛔ 행을 지운 뒤 저장소에서 지우는 사이에 같은 파일이 다시 올라오면 새 업로드가 깨질 수 있다 (수 ms 창).
+func runMediaGC(jc *JobContext, cfg *MediaGCConfig) (*MediaGCResult, error) {
+	db := jc.DB
+	now := jc.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	result := &MediaGCResult{DryRun: jc.DryRun}
+	scan := &mediaScan{db: db, bases: cfg.URLBases, scannedAt: time.Now(), result: result}
+
+	scan.scanBoards()
+	for _, src := range mediaRefSources(db) {
+		scan.scanSource(src)
+	}
+	if result.Errors > 0 {
+		result.Skipped = "scan incomplete; refs kept and nothing deleted"
+		return result, nil
+	}
+
+	// 이번 스캔에서 다시 보지 못한 참조는 글·파일이 없어진 것이다
+	res := db.Where("scanned_at < ?", scan.scannedAt).Delete(&v2domain.V2MediaRef{})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	result.StaleRefs = res.RowsAffected
+	if err := db.Exec(`UPDATE v2_media_objects SET ref_count =
+		(SELECT COUNT(*) FROM v2_media_refs r WHERE r.object_id = v2_media_objects.id)`).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Exec("UPDATE v2_media_objects SET orphaned_at = NULL WHERE ref_count > 0 AND orphaned_at IS NOT NULL").Error; err != nil {
+		return nil, err
+	}
+	if err := db.Exec("UPDATE v2_media_objects SET orphaned_at = ? WHERE ref_count = 0 AND orphaned_at IS NULL", now).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&v2domain.V2MediaObject{}).Where("ref_count = 0").Count(&result.Orphaned).Error; err != nil {
+		return nil, err
+	}
+
+	cutoff := now.Add(-cfg.Grace)
+	expired := func(q *gorm.DB) *gorm.DB {
+		return q.Where("ref_count = 0 AND orphaned_at <= ? AND last_uploaded_at <= ?", cutoff, cutoff)
+	}
+	var candidates []v2domain.V2MediaObject
+	if err := expired(db).Order("id").Limit(mediaGCMaxDeletes).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	result.Candidates = len(candidates)
+	for _, obj := range candidates {
+		result.CandidateBytes += obj.Size
+		if len(result.Sample) < mediaGCSampleSize {
+			result.Sample = append(result.Sample, MediaGCCandidate{Key: obj.Key, Size: obj.Size, OrphanedAt: *obj.OrphanedAt})
+		}
+		if jc.DryRun {
+			continue
+		}
+		deleted, err := deleteMediaObject(jc, cfg.Store, expired, obj)
+		if err != nil {
+			result.Errors++
+			log.Printf("[Cron:media-gc] delete key=%s error=%v", obj.Key, err)
+			continue
+		}
+		if deleted {
+			result.Deleted++
+			result.DeletedBytes += obj.Size
+		}
+	}
+	return result, nil
+}
+
+// deleteMediaObject removes the object row (조건을 다시 걸어 그 사이 다시 올라온 파일은 건너뛴다),
+// its image variant rows, then the stored files
+func deleteMediaObject(jc *JobContext, store storage.Backend, expired func(*gorm.DB) *gorm.DB, obj v2domain.V2MediaObject) (bool, error) {
+	keys := []string{obj.Key}
+	err := jc.DB.Transaction(func(tx *gorm.DB) error {
+		res := expired(tx.Where("id = ?", obj.ID)).Delete(&v2domain.V2MediaObject{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errMediaReclaimed
+		}
+		var image v2domain.V2MediaImage
+		if err := tx.Preload("Variants").Where("file_key = ?", obj.Key).Limit(1).Find(&image).Error; err != nil {
+			return err
+		}
+		if image.ID == 0 {
+			return nil
+		}
+		for _, v := range image.Variants {
+			if v.Key != obj.Key {
+				keys = append(keys, v.Key)
+			}
+		}
+		if err := tx.Where("image_id = ?", image.ID).Delete(&v2domain.V2MediaVariant{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&image).Error
+	})
+	if errors.Is(err, errMediaReclaimed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if err := store.Delete(jc.Ctx, key); err != nil {
+			// 행은 이미 지웠다 — 남은 파일은 로그의 key 로 손으로 치운다
+			return true, fmt.Errorf("storage delete %s: %w", key, err)
+		}
+	}
+	return true, nil
+}
+
+// errMediaReclaimed 는 지우려던 객체가 그 사이 다시 올라왔거나 참조된 경우다
+var errMediaReclaimed = errors.New("media object reclaimed")
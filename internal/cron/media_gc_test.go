@@ -0,0 +1,217 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/damoang/angple-backend/pkg/storage"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestExtractMediaHashes(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	bases := []string{"https://cdn.test/"}
+	content := fmt.Sprintf(`<img src="https://cdn.test/images/ab/%[1]s.jpg">`+
+		`<img src='/data/editor/ab/%[1]s_list.webp'>`+
+		`<a href="https://cdn.test/images%%2Fab%%2F%[1]s.png">file</a>`+
+		`<img src="https://elsewhere.test/images/ab/%[1]s.jpg">`+
+		`<img src="https://cdn.test/data/editor/2024/photo_1700000000.jpg">`, sum)
+
+	got := extractMediaHashes(content, bases)
+	if len(got) != 3 {
+		t.Fatalf("want 3 hashes (cdn, relative variant, escaped), got %v", got)
+	}
+	for _, h := range got {
+		if h != sum {
+			t.Fatalf("unexpected hash %q", h)
+		}
+	}
+	if _, ok := mediaURLHash("data/member_image/ab/"+sum+".jpg", nil); !ok {
+		t.Fatal("stored key should resolve")
+	}
+}
+
+func setupMediaGCDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE g5_board (bo_table TEXT PRIMARY KEY)`,
+		`INSERT INTO g5_board (bo_table) VALUES ('free')`,
+		`CREATE TABLE g5_write_free (
+			wr_id INTEGER PRIMARY KEY,
+			wr_is_comment INTEGER NOT NULL DEFAULT 0,
+			wr_content TEXT NOT NULL DEFAULT '',
+			wr_10 TEXT NOT NULL DEFAULT '',
+			wr_deleted_at DATETIME NULL
+		)`,
+		`CREATE TABLE g5_member (mb_no INTEGER PRIMARY KEY AUTOINCREMENT, mb_id TEXT, mb_image_url TEXT NOT NULL DEFAULT '')`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	if err := db.AutoMigrate(&v2domain.V2MediaObject{}, &v2domain.V2MediaRef{}, &v2domain.V2MediaImage{}, &v2domain.V2MediaVariant{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// seedMediaObject stores a content-addressed file and its row, orphaned since `orphaned`
+func seedMediaObject(t *testing.T, db *gorm.DB, store storage.Backend, prefix string, n int, orphaned time.Time) v2domain.V2MediaObject {
+	t.Helper()
+	sum := fmt.Sprintf("%064x", n)
+	res, err := store.Upload(context.Background(), storage.ContentKey(prefix, sum, ".jpg"), strings.NewReader("x"), "image/jpeg", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := v2domain.V2MediaObject{SHA256: sum, Key: res.Key, URL: res.URL, Size: 1, OrphanedAt: &orphaned, LastUploadedAt: orphaned}
+	if err := db.Create(&obj).Error; err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func TestRunMediaGC(t *testing.T) {
+	db := setupMediaGCDB(t)
+	store, err := storage.NewLocalBackend(storage.LocalConfig{Root: t.TempDir(), PublicURL: "https://api.test/files", SigningKey: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	old := now.Add(-40 * 24 * time.Hour)
+
+	live := seedMediaObject(t, db, store, "images", 1, old)    // 살아 있는 글 본문
+	deleted := seedMediaObject(t, db, store, "images", 2, old) // 삭제된 글에만 있다 → 지운다
+	profile := seedMediaObject(t, db, store, "data/member_image", 3, old)
+	fresh := seedMediaObject(t, db, store, "images", 4, now) // 방금 올라와 아직 글이 없다 → 유예
+	variantKey := strings.TrimSuffix(deleted.Key, ".jpg") + "_list.webp"
+	if _, err := store.Upload(context.Background(), variantKey, strings.NewReader("v"), "image/webp", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&v2domain.V2MediaImage{Key: deleted.Key, URL: deleted.URL,
+		Variants: []v2domain.V2MediaVariant{{Name: "list", Format: "webp", Key: variantKey, URL: "u"}}}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	seed := []string{
+		fmt.Sprintf(`INSERT INTO g5_write_free (wr_id, wr_content) VALUES (1, '<img src="%s">')`, live.URL),
+		fmt.Sprintf(`INSERT INTO g5_write_free (wr_id, wr_content, wr_deleted_at) VALUES (2, '<img src="%s">', '2026-01-01 00:00:00')`, deleted.URL),
+		fmt.Sprintf(`INSERT INTO g5_member (mb_id, mb_image_url) VALUES ('alice', '%s')`, profile.Key),
+	}
+	for _, stmt := range seed {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &MediaGCConfig{Store: store, URLBases: []string{"https://api.test/files/"}, Grace: DefaultMediaGCGrace}
+
+	// dry-run: 보고만 하고 롤백·저장소 유지
+	tx := db.Begin()
+	report, err := runMediaGC(&JobContext{Ctx: context.Background(), DB: tx, Now: now, DryRun: true}, cfg)
+	tx.Rollback()
+	if err != nil {
+		t.Fatalf("dry-run: %v", err)
+	}
+	if report.Candidates != 1 || len(report.Sample) != 1 || report.Sample[0].Key != deleted.Key || report.Deleted != 0 {
+		t.Fatalf("unexpected dry-run report %+v", report)
+	}
+	if ok, _ := store.Exists(context.Background(), deleted.Key); !ok {
+		t.Fatal("dry-run must not delete files")
+	}
+
+	result, err := runMediaGC(&JobContext{Ctx: context.Background(), DB: db, Now: now}, cfg)
+	if err != nil {
+		t.Fatalf("runMediaGC: %v", err)
+	}
+	if result.Deleted != 1 || result.RefsLinked != 2 || result.Errors != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	for key, want := range map[string]bool{live.Key: true, profile.Key: true, fresh.Key: true, deleted.Key: false, variantKey: false} {
+		if ok, _ := store.Exists(context.Background(), key); ok != want {
+			t.Errorf("%s exists=%v, want %v", key, ok, want)
+		}
+	}
+
+	var objs []v2domain.V2MediaObject
+	db.Order("id").Find(&objs)
+	if len(objs) != 3 || objs[0].RefCount != 1 || objs[0].OrphanedAt != nil || objs[2].RefCount != 0 {
+		t.Fatalf("unexpected objects %+v", objs)
+	}
+	var images int64
+	db.Model(&v2domain.V2MediaImage{}).Count(&images)
+	if images != 0 {
+		t.Fatal("image record of the deleted object should be removed")
+	}
+
+	// 글이 지워지면 다음 스캔에서 참조가 빠지고 고아로 바뀐다
+	db.Exec("UPDATE g5_write_free SET wr_deleted_at = '2026-01-01 00:00:00' WHERE wr_id = 1")
+	result, err = runMediaGC(&JobContext{Ctx: context.Background(), DB: db, Now: now}, cfg)
+	if err != nil || result.StaleRefs != 1 || result.Deleted != 0 {
+		t.Fatalf("second run: %+v %v", result, err)
+	}
+	var orphaned v2domain.V2MediaObject
+	db.First(&orphaned, live.ID)
+	if orphaned.RefCount != 0 || orphaned.OrphanedAt == nil {
+		t.Fatalf("live object should be orphaned now: %+v", orphaned)
+	}
+}
+
+func TestRunMediaGCScansV2Sources(t *testing.T) {
+	db := setupMediaGCDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE v2_posts (id INTEGER PRIMARY KEY, content TEXT NOT NULL DEFAULT '', status TEXT NOT NULL DEFAULT 'published', deleted_at DATETIME NULL)`,
+		`CREATE TABLE banners (id INTEGER PRIMARY KEY, image_url TEXT NULL)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	store, err := storage.NewLocalBackend(storage.LocalConfig{Root: t.TempDir(), PublicURL: "https://api.test/files", SigningKey: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	old := now.Add(-40 * 24 * time.Hour)
+
+	inPost := seedMediaObject(t, db, store, "images", 1, old)
+	inBanner := seedMediaObject(t, db, store, "images", 2, old)
+	inDeletedPost := seedMediaObject(t, db, store, "images", 3, old)
+	for _, stmt := range []string{
+		fmt.Sprintf(`INSERT INTO v2_posts (id, content) VALUES (1, '<p><img src="%s"></p>')`, inPost.URL),
+		fmt.Sprintf(`INSERT INTO v2_posts (id, content, status, deleted_at) VALUES (2, '<img src="%s">', 'deleted', '2026-01-01 00:00:00')`, inDeletedPost.URL),
+		fmt.Sprintf(`INSERT INTO banners (id, image_url) VALUES (1, '%s')`, inBanner.URL),
+		`INSERT INTO banners (id, image_url) VALUES (2, NULL)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &MediaGCConfig{Store: store, URLBases: []string{"https://api.test/files/"}, Grace: DefaultMediaGCGrace}
+	result, err := runMediaGC(&JobContext{Ctx: context.Background(), DB: db, Now: now}, cfg)
+	if err != nil {
+		t.Fatalf("runMediaGC: %v", err)
+	}
+	if result.Errors != 0 || result.RefsLinked != 2 || result.Deleted != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	for key, want := range map[string]bool{inPost.Key: true, inBanner.Key: true, inDeletedPost.Key: false} {
+		if ok, _ := store.Exists(context.Background(), key); ok != want {
+			t.Errorf("%s exists=%v, want %v", key, ok, want)
+		}
+	}
+	var refs []v2domain.V2MediaRef
+	db.Order("ref_key").Find(&refs)
+	if len(refs) != 2 || refs[0].RefKey != "banners:1" || refs[0].RefType != v2domain.MediaRefBanner || refs[1].RefKey != "v2_posts:1" {
+		t.Fatalf("unexpected refs %+v", refs)
+	}
+}
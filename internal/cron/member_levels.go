@@ -2,9 +2,12 @@ package cron
 
 import (
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/damoang/angple-backend/internal/common"
+	gnudomain "github.com/damoang/angple-backend/internal/domain/gnuboard"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	"gorm.io/gorm"
 )
 
@@ -193,5 +196,11 @@ func updatePostVisibility(db *gorm.DB, memberID string, makeSecret bool) int64 {
 	if result.Error != nil {
 		return 0
 	}
+	if result.RowsAffected > 0 {
+		// 비밀글 전환은 검색 노출도 바꾼다 — 색인이 따라가도록 회원 단위 동기화 이벤트
+		if err := gnurepo.EnqueueSearchSync(db, gnudomain.WriteAfterEventTypeSearchMemberSync, "promotion", 0, memberID); err != nil {
+			log.Printf("[Cron:update-member-levels] search sync enqueue failed for %s: %v", memberID, err)
+		}
+	}
 	return result.RowsAffected
 }
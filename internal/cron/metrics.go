@@ -0,0 +1,47 @@
+package cron
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	cronJobRunsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cron_job_runs_total",
+			Help: "Total number of cron job runs by result",
+		},
+		[]string{"job", "status"},
+	)
+	cronJobDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cron_job_duration_seconds",
+			Help:    "Cron job run duration",
+			Buckets: []float64{0.1, 0.5, 1, 5, 15, 30, 60, 120, 300, 600, 1800},
+		},
+		[]string{"job"},
+	)
+	cronJobRunning = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cron_job_running",
+			Help: "Whether the cron job is currently running on this instance",
+		},
+		[]string{"job"},
+	)
+	// 마지막 성공 시각. ⛔ 알람은 이 값 기준으로 건다 — 실패뿐 아니라 "아예 안 돈" 경우도 잡힌다.
+	cronJobLastSuccess = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cron_job_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful cron job run",
+		},
+		[]string{"job"},
+	)
+	// 다른 레플리카가 락을 잡고 있거나(locked) 이전 실행이 안 끝나(running) 건너뛴 횟수
+	cronJobSkippedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cron_job_skipped_total",
+			Help: "Total number of scheduled cron job runs skipped",
+		},
+		[]string{"job", "reason"},
+	)
+)
@@ -3,114 +3,91 @@ package cron
 import (
 	"fmt"
 	"log"
-	"net/http"
+	"strconv"
 	"time"
 
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
-	"github.com/gin-gonic/gin"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"gorm.io/gorm"
 )
 
 // PointExpiryResult contains the result of a point expiry batch run
 type PointExpiryResult struct {
 	ExpiredCount int    `json:"expired_count"`
 	ExecutedAt   string `json:"executed_at"`
+	Message      string `json:"message,omitempty"`
 }
 
 // PointExpiryNotifyResult contains the result of a point expiry notification run
 type PointExpiryNotifyResult struct {
 	NotifiedCount int    `json:"notified_count"`
 	ExecutedAt    string `json:"executed_at"`
+	Message       string `json:"message,omitempty"`
 }
 
-// PointExpiry handles POST /api/internal/cron/point-expiry
-func (h *Handler) PointExpiry(c *gin.Context) {
-	if !h.verifySecret(c) {
-		return
-	}
-
-	if h.pointConfigRepo == nil || h.gnuPointWriteRepo == nil {
-		c.JSON(http.StatusOK, gin.H{"success": true, "data": PointExpiryResult{
-			ExpiredCount: 0,
-			ExecutedAt:   time.Now().Format(time.RFC3339),
-		}, "message": "point expiry not configured"})
-		return
+// runPointExpiry expires up to 1000 point entries when expiry is enabled
+func runPointExpiry(
+	pointConfigRepo v2repo.PointConfigRepository,
+	gnuPointWriteRepo v2repo.GnuboardPointWriteRepository,
+) (*PointExpiryResult, error) {
+	result := &PointExpiryResult{ExecutedAt: time.Now().Format(time.RFC3339)}
+	if pointConfigRepo == nil || gnuPointWriteRepo == nil {
+		result.Message = "point expiry not configured"
+		return result, nil
 	}
 
 	// Check if expiry is enabled
-	config, err := h.pointConfigRepo.GetPointConfig()
+	config, err := pointConfigRepo.GetPointConfig()
 	if err != nil {
-		log.Printf("[Cron:point-expiry] config error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
-		return
+		return nil, fmt.Errorf("point config: %w", err)
 	}
-
 	if !config.ExpiryEnabled {
-		c.JSON(http.StatusOK, gin.H{"success": true, "data": PointExpiryResult{
-			ExpiredCount: 0,
-			ExecutedAt:   time.Now().Format(time.RFC3339),
-		}, "message": "expiry disabled"})
-		return
+		result.Message = "expiry disabled"
+		return result, nil
 	}
 
-	expired, err := h.gnuPointWriteRepo.ExpireBatch(1000)
+	expired, err := gnuPointWriteRepo.ExpireBatch(1000)
 	if err != nil {
-		log.Printf("[Cron:point-expiry] error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
-		return
+		return nil, err
 	}
-
-	result := PointExpiryResult{
-		ExpiredCount: expired,
-		ExecutedAt:   time.Now().Format(time.RFC3339),
-	}
-	log.Printf("[Cron:point-expiry] expired %d point entries", expired)
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+	result.ExpiredCount = expired
+	return result, nil
 }
 
-// PointExpiryNotify handles POST /api/internal/cron/point-expiry-notify
-func (h *Handler) PointExpiryNotify(c *gin.Context) {
-	if !h.verifySecret(c) {
-		return
+// runPointExpiryNotify notifies members whose points expire within 7 days (once per day)
+func runPointExpiryNotify(
+	db *gorm.DB,
+	pointConfigRepo v2repo.PointConfigRepository,
+	gnuPointWriteRepo v2repo.GnuboardPointWriteRepository,
+	notiRepo gnurepo.NotiRepository,
+) (*PointExpiryNotifyResult, error) {
+	result := &PointExpiryNotifyResult{ExecutedAt: time.Now().Format(time.RFC3339)}
+	if pointConfigRepo == nil || gnuPointWriteRepo == nil || notiRepo == nil {
+		result.Message = "point expiry notify not configured"
+		return result, nil
 	}
 
-	if h.pointConfigRepo == nil || h.gnuPointWriteRepo == nil || h.notiRepo == nil {
-		c.JSON(http.StatusOK, gin.H{"success": true, "data": PointExpiryNotifyResult{
-			NotifiedCount: 0,
-			ExecutedAt:    time.Now().Format(time.RFC3339),
-		}, "message": "point expiry notify not configured"})
-		return
-	}
-
-	config, err := h.pointConfigRepo.GetPointConfig()
+	config, err := pointConfigRepo.GetPointConfig()
 	if err != nil {
-		log.Printf("[Cron:point-expiry-notify] config error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
-		return
+		return nil, fmt.Errorf("point config: %w", err)
 	}
-
 	if !config.ExpiryEnabled {
-		c.JSON(http.StatusOK, gin.H{"success": true, "data": PointExpiryNotifyResult{
-			NotifiedCount: 0,
-			ExecutedAt:    time.Now().Format(time.RFC3339),
-		}, "message": "expiry disabled"})
-		return
+		result.Message = "expiry disabled"
+		return result, nil
 	}
 
 	// Get members with points expiring within 7 days
-	expiringMembers, err := h.gnuPointWriteRepo.GetExpiringPoints(7, 500)
+	expiringMembers, err := gnuPointWriteRepo.GetExpiringPoints(7, 500)
 	if err != nil {
-		log.Printf("[Cron:point-expiry-notify] error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
-		return
+		return nil, err
 	}
 
-	notified := 0
 	today := time.Now().Format("2006-01-02")
 	for _, m := range expiringMembers {
 		// Check for duplicate notification today
 		dedupeKey := fmt.Sprintf("point_expiry_%s", today)
 		var count int64
-		h.db.Table("g5_na_noti").
+		db.Table("g5_na_noti").
 			Where("mb_id = ? AND ph_from_case = ? AND parent_subject = ?", m.MbID, "point_expiry", dedupeKey).
 			Count(&count)
 		if count > 0 {
@@ -130,17 +107,15 @@ func (h *Handler) PointExpiryNotify(c *gin.Context) {
 			PhReaded:      "N",
 			ParentSubject: dedupeKey,
 		}
-		if err := h.notiRepo.Create(noti); err != nil {
+		if err := notiRepo.Create(noti); err != nil {
 			log.Printf("[Cron:point-expiry-notify] notification failed for %s: %v", m.MbID, err)
 			continue
 		}
-		notified++
-	}
-
-	result := PointExpiryNotifyResult{
-		NotifiedCount: notified,
-		ExecutedAt:    time.Now().Format(time.RFC3339),
+		result.NotifiedCount++
+		// 이메일은 포인트 분류를 켠 회원에게만 (기본 꺼짐)
+		if err := gnurepo.EnqueueEmail(db, m.MbID, gnurepo.EmailCategoryPoint, "point_expiry", "/point", strconv.Itoa(m.ExpiringAmount)); err != nil {
+			log.Printf("[Cron:point-expiry-notify] email enqueue failed for %s: %v", m.MbID, err)
+		}
 	}
-	log.Printf("[Cron:point-expiry-notify] notified %d members", notified)
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+	return result, nil
 }
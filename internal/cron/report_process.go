@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/damoang/angple-backend/internal/common"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	"gorm.io/gorm"
 )
 
@@ -737,7 +738,13 @@ func sendDisciplineMemo(tx *gorm.DB, targetMbID, targetNick string, disciplineDa
 		VALUES (?, 'police', ?, ?, '0000-00-00 00:00:00', ?, 'send', '127.0.0.1')
 	`, targetMbID, nowStr, memo, meID)
 
-	// 3. 실시간 쪽지 알림 업데이트
+	// 3. 이메일 (계정 안내 — 기본 켜짐). 같은 tx 라 제재가 롤백되면 메일도 남지 않는다
+	if err := gnurepo.EnqueueEmail(tx, targetMbID, gnurepo.EmailCategoryAccount, "discipline", "/messages",
+		disciplinePeriodLabel(disciplineDays)); err != nil {
+		log.Printf("[Cron:report-process] discipline email enqueue failed for %s: %v", targetMbID, err)
+	}
+
+	// 4. 실시간 쪽지 알림 업데이트
 	tx.Exec(`
 		UPDATE g5_member
 		SET mb_memo_call = 'police',
@@ -748,17 +755,22 @@ func sendDisciplineMemo(tx *gorm.DB, targetMbID, targetNick string, disciplineDa
 	return nil
 }
 
+// disciplinePeriodLabel is the period text of a discipline (쪽지·이메일 공용)
+func disciplinePeriodLabel(disciplineDays int) string {
+	switch {
+	case disciplineDays < 0 || disciplineDays == 9999:
+		return "영구"
+	case disciplineDays == 0:
+		return "주의(이용제한 없음)"
+	default:
+		return fmt.Sprintf("%d일", disciplineDays)
+	}
+}
+
 // buildMemoContent generates the discipline notification memo content
 func buildMemoContent(targetMbID, targetNick string, disciplineDays int, disciplineType string, sgTypes []int, disciplineDetail string, wrID int, now time.Time) string {
 	// 기간 텍스트
-	var penaltyDay string
-	if disciplineDays < 0 || disciplineDays == 9999 {
-		penaltyDay = "영구"
-	} else if disciplineDays == 0 {
-		penaltyDay = "주의(이용제한 없음)"
-	} else {
-		penaltyDay = fmt.Sprintf("%d일", disciplineDays)
-	}
+	penaltyDay := disciplinePeriodLabel(disciplineDays)
 
 	// 종료일
 	var endDateStr string
@@ -0,0 +1,12 @@
+package cron
+
+import "context"
+
+// SetSavedSearchAlerts injects the saved search alert check (wired in main.go from
+// service.SavedSearchService — 검색 백엔드가 없으면 주입하지 않고, 잡은 건너뛴 것으로 기록된다).
+//
+// saved-search-alerts 잡: 알림을 켠 저장 검색마다 마지막 알림 이후 새 글을 찾아, 검색 하나당
+// g5_na_noti 한 건(최신 글 링크 + 건수)을 남긴다.
+func (h *Handler) SetSavedSearchAlerts(fn func(ctx context.Context) (interface{}, error)) {
+	h.savedSearchAlerts = fn
+}
@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/plugin"
+)
+
+// SchedulerPlugin is the plugin name the built-in jobs are registered under
+// 실행 이력은 plugin_schedule_runs(plugin_name='cron'), 락은 스케줄러 락을 그대로 쓴다.
+const SchedulerPlugin = "cron"
+
+const (
+	defaultJobTimeout = 10 * time.Minute
+	jobTimezone       = "Asia/Seoul"
+)
+
+// nowKey carries the reference time override of a legacy HTTP trigger
+type nowKey struct{}
+
+// RegisterJobs registers the schedule table with the plugin scheduler.
+//
+// scheduled 가 false 면 자동 실행 없이 수동 실행(관리자 API, /api/internal/cron/*)만 가능하다.
+// 잡마다 이전 실행이 끝나기 전에는 다시 시작하지 않고, 레플리카가 여러 대여도 한 곳에서만 실행된다.
+func (h *Handler) RegisterJobs(scheduler *plugin.Scheduler, jobs []Job, scheduled bool) error {
+	seen := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		if seen[j.Name] {
+			return fmt.Errorf("cron: duplicate job %q", j.Name)
+		}
+		seen[j.Name] = true
+		// 등록 도중 실패해 일부 잡만 남지 않도록 먼저 전부 검증한다
+		if j.Schedule == "" {
+			continue
+		}
+		if _, err := plugin.ParseCron(j.Schedule, time.UTC); err != nil {
+			return fmt.Errorf("cron: job %s: %w", j.Name, err)
+		}
+	}
+
+	for _, j := range jobs {
+		if j.Timeout <= 0 {
+			j.Timeout = defaultJobTimeout
+		}
+		opts := plugin.TaskOptions{
+			Cron:        j.Schedule,
+			Timezone:    jobTimezone,
+			Timeout:     j.Timeout,
+			Manual:      !scheduled || j.Schedule == "",
+			DryRun:      j.DryRun,
+			Description: j.Description,
+		}
+		if err := scheduler.RegisterTaskFunc(SchedulerPlugin, j.Name, opts, h.task(j)); err != nil {
+			return fmt.Errorf("cron: job %s: %w", j.Name, err)
+		}
+	}
+	scheduler.AddRunHook(recordRun)
+	h.scheduler = scheduler
+	return nil
+}
+
+// task adapts a Job to the scheduler; dry-run 이면 트랜잭션 안에서 돌리고 항상 롤백한다
+func (h *Handler) task(job Job) plugin.TaskFunc {
+	return func(ctx context.Context, run *plugin.ScheduleRun) error {
+		jc := &JobContext{Ctx: ctx, DB: h.db.WithContext(ctx), DryRun: run.DryRun}
+		if now, ok := ctx.Value(nowKey{}).(time.Time); ok {
+			jc.Now = now
+		}
+		if run.DryRun {
+			tx := jc.DB.Begin()
+			if tx.Error != nil {
+				return tx.Error
+			}
+			defer tx.Rollback()
+			jc.DB = tx
+		}
+
+		cronJobRunning.WithLabelValues(job.Name).Set(1)
+		defer cronJobRunning.WithLabelValues(job.Name).Set(0)
+
+		result, err := job.Run(jc)
+		if err != nil {
+			return err
+		}
+		run.SetResult(result)
+		if job.Summary != nil && result != nil {
+			prefix := ""
+			if run.DryRun {
+				prefix = "(dry-run) "
+			}
+			log.Printf("[Cron:%s] %s%s", job.Name, prefix, job.Summary(result))
+		}
+		return nil
+	}
+}
+
+// recordRun exports the cron_job_* metrics for built-in jobs (dry-run 제외)
+func recordRun(run *plugin.ScheduleRun) {
+	if run.PluginName != SchedulerPlugin || run.DryRun {
+		return
+	}
+	if run.Status == plugin.ScheduleRunSkipped {
+		cronJobSkippedTotal.WithLabelValues(run.TaskName, run.Error).Inc()
+		return
+	}
+	cronJobRunsTotal.WithLabelValues(run.TaskName, run.Status).Inc()
+	cronJobDuration.WithLabelValues(run.TaskName).Observe(run.FinishedAt.Sub(run.StartedAt).Seconds())
+	if run.Status == plugin.ScheduleRunSuccess {
+		cronJobLastSuccess.WithLabelValues(run.TaskName).Set(float64(run.FinishedAt.Unix()))
+	}
+}
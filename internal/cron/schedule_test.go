@@ -0,0 +1,250 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/plugin"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type counterRow struct {
+	ID    int64 `gorm:"primaryKey"`
+	Count int
+}
+
+func newScheduleTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&plugin.ScheduleRun{}, &plugin.ScheduleLock{}, &counterRow{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := db.Create(&counterRow{ID: 1}).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	return db
+}
+
+// newTestScheduler registers jobs on a scheduler backed by the DB run store and locker
+func newTestScheduler(t *testing.T, db *gorm.DB, jobs ...Job) *plugin.Scheduler {
+	t.Helper()
+	s := plugin.NewScheduler(plugin.NewDefaultLogger("cron-test"))
+	s.SetRunStore(plugin.NewGormScheduleRunStore(db))
+	s.SetLocker(plugin.NewGormTaskLocker(db))
+	if err := NewHandler(db).RegisterJobs(s, jobs, false); err != nil {
+		t.Fatalf("RegisterJobs: %v", err)
+	}
+	return s
+}
+
+func incrementJob(name string) Job {
+	return Job{
+		Name:     name,
+		Schedule: "* * * * *",
+		DryRun:   true,
+		Run: func(jc *JobContext) (interface{}, error) {
+			if err := jc.DB.Model(&counterRow{}).Where("id = 1").
+				Update("count", gorm.Expr("count + 1")).Error; err != nil {
+				return nil, err
+			}
+			return map[string]int{"incremented": 1}, nil
+		},
+	}
+}
+
+func readCount(t *testing.T, db *gorm.DB) int {
+	t.Helper()
+	var row counterRow
+	if err := db.First(&row, 1).Error; err != nil {
+		t.Fatalf("read counter: %v", err)
+	}
+	return row.Count
+}
+
+func TestRunNowRecordsRun(t *testing.T) {
+	db := newScheduleTestDB(t)
+	s := newTestScheduler(t, db, incrementJob("inc"))
+
+	run, err := s.RunNow(context.Background(), SchedulerPlugin, "inc", plugin.RunRequest{TriggeredBy: "admin"})
+	if err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	if run.Status != plugin.ScheduleRunSuccess {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if readCount(t, db) != 1 {
+		t.Fatalf("expected job to run once")
+	}
+
+	runs, err := s.GetRuns(SchedulerPlugin, "inc", 10)
+	if err != nil {
+		t.Fatalf("GetRuns: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Trigger != plugin.TriggerManual || runs[0].TriggeredBy != "admin" {
+		t.Fatalf("unexpected runs: %+v", runs)
+	}
+	if string(runs[0].ResultJSON) != `{"incremented":1}` {
+		t.Errorf("unexpected result JSON: %s", runs[0].ResultJSON)
+	}
+}
+
+func TestRunNowDryRunRollsBack(t *testing.T) {
+	db := newScheduleTestDB(t)
+	s := newTestScheduler(t, db, incrementJob("inc"))
+
+	run, err := s.RunNow(context.Background(), SchedulerPlugin, "inc", plugin.RunRequest{DryRun: true})
+	if err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	if !run.DryRun || run.Status != plugin.ScheduleRunSuccess {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if got := readCount(t, db); got != 0 {
+		t.Fatalf("dry-run should roll back, counter = %d", got)
+	}
+}
+
+func TestRunNowDryRunUnsupported(t *testing.T) {
+	db := newScheduleTestDB(t)
+	job := incrementJob("inc")
+	job.DryRun = false
+	s := newTestScheduler(t, db, job)
+
+	if _, err := s.RunNow(context.Background(), SchedulerPlugin, "inc", plugin.RunRequest{DryRun: true}); !errors.Is(err, plugin.ErrDryRunUnsupported) {
+		t.Fatalf("expected ErrDryRunUnsupported, got %v", err)
+	}
+	if _, err := s.RunNow(context.Background(), SchedulerPlugin, "missing", plugin.RunRequest{}); !errors.Is(err, plugin.ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestRunNowFailedRunKeepsError(t *testing.T) {
+	db := newScheduleTestDB(t)
+	s := newTestScheduler(t, db, Job{
+		Name: "boom",
+		Run:  func(*JobContext) (interface{}, error) { return nil, errors.New("boom") },
+	})
+
+	run, err := s.RunNow(context.Background(), SchedulerPlugin, "boom", plugin.RunRequest{})
+	if err == nil || run == nil {
+		t.Fatalf("expected failed run, got run=%+v err=%v", run, err)
+	}
+	if run.Status != plugin.ScheduleRunFailed || run.Error != "boom" {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+}
+
+func TestRegisterJobsManualWhenDisabled(t *testing.T) {
+	db := newScheduleTestDB(t)
+	s := plugin.NewScheduler(plugin.NewDefaultLogger("cron-test"))
+	jobs := []Job{incrementJob("scheduled"), {Name: "manual", Run: incrementJob("manual").Run}}
+	if err := NewHandler(db).RegisterJobs(s, jobs, true); err != nil {
+		t.Fatalf("RegisterJobs: %v", err)
+	}
+
+	tasks := s.GetTasks()
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(tasks))
+	}
+	if tasks[0].Manual || tasks[0].Timezone != jobTimezone || tasks[0].Timeout != defaultJobTimeout.String() {
+		t.Errorf("unexpected scheduled task: %+v", tasks[0])
+	}
+	if !tasks[1].Manual {
+		t.Errorf("job without schedule must be manual: %+v", tasks[1])
+	}
+
+	disabled := plugin.NewScheduler(plugin.NewDefaultLogger("cron-test"))
+	if err := NewHandler(db).RegisterJobs(disabled, jobs[:1], false); err != nil {
+		t.Fatalf("RegisterJobs: %v", err)
+	}
+	if !disabled.GetTasks()[0].Manual {
+		t.Error("jobs must be manual when scheduling is disabled")
+	}
+
+	if err := NewHandler(db).RegisterJobs(plugin.NewScheduler(plugin.NewDefaultLogger("cron-test")),
+		[]Job{{Name: "bad", Schedule: "bogus"}}, true); err == nil {
+		t.Fatal("expected error for invalid schedule")
+	}
+}
+
+func TestWithSchedules(t *testing.T) {
+	jobs := []Job{{Name: "a", Schedule: "* * * * *"}, {Name: "b", Schedule: "0 * * * *"}}
+
+	got, err := WithSchedules(jobs, map[string]string{"a": "off", "b": " 30 4 * * * "})
+	if err != nil {
+		t.Fatalf("WithSchedules: %v", err)
+	}
+	if got[0].Schedule != "" || got[1].Schedule != "30 4 * * *" {
+		t.Fatalf("unexpected schedules: %+v", got)
+	}
+	if _, err := WithSchedules(jobs, map[string]string{"c": "off"}); err == nil {
+		t.Fatal("expected error for unknown job")
+	}
+}
+
+func TestHandlerJobsRegister(t *testing.T) {
+	h := &Handler{}
+	if err := h.RegisterJobs(plugin.NewScheduler(plugin.NewDefaultLogger("cron-test")), h.Jobs(), true); err != nil {
+		t.Fatalf("built-in schedule table: %v", err)
+	}
+}
+
+func TestLegacyTriggerPassesReferenceTime(t *testing.T) {
+	db := newScheduleTestDB(t)
+	var got time.Time
+	s := newTestScheduler(t, db, Job{
+		Name: "report",
+		Run: func(jc *JobContext) (interface{}, error) {
+			got = jc.Now
+			return nil, nil
+		},
+	})
+
+	ref := time.Date(2026, 3, 22, 0, 0, 0, 0, time.UTC)
+	if _, err := s.RunNow(context.WithValue(context.Background(), nowKey{}, ref), SchedulerPlugin, "report",
+		plugin.RunRequest{Trigger: plugin.TriggerHTTP}); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	if !got.Equal(ref) {
+		t.Fatalf("expected reference time %s, got %s", ref, got)
+	}
+}
+
+func TestLegacyTriggerKeepsTopLevelMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newScheduleTestDB(t)
+	h := NewHandler(db)
+	h.secret = ""
+	if err := h.RegisterJobs(plugin.NewScheduler(plugin.NewDefaultLogger("cron-test")), h.Jobs(), false); err != nil {
+		t.Fatalf("RegisterJobs: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/internal/cron/point-expiry", nil)
+	h.PointExpiry(c)
+
+	var body struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+		Data    struct {
+			ExpiredCount int `json:"expired_count"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v (%s)", err, w.Body.String())
+	}
+	if w.Code != http.StatusOK || !body.Success || body.Message != "point expiry not configured" {
+		t.Fatalf("unexpected response %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,13 @@
+package cron
+
+import "context"
+
+// SetSearchReconcile injects the search index reconciliation (wired in main.go from
+// service.SearchService — ES 가 없으면 주입하지 않고, 잡은 건너뛴 것으로 기록된다).
+//
+// search-reconcile 잡: 게시판별 노출 글·댓글 수와 CRC32 합계를 DB 와 색인에서 비교해, 어긋난
+// 게시판만 통째로 다시 색인한다. 쓰기 후 이벤트가 재시도 상한을 넘겼거나 ES 밖에서 바뀐
+// 데이터(직접 SQL 등)를 메우는 그물이다.
+func (h *Handler) SetSearchReconcile(fn func(ctx context.Context) (interface{}, error)) {
+	h.searchReconcile = fn
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/damoang/angple-backend/internal/common"
+	gnudomain "github.com/damoang/angple-backend/internal/domain/gnuboard"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	"gorm.io/gorm"
 )
 
@@ -19,12 +21,16 @@ type WithdrawalGraceResult struct {
 	CandidateCount  int      `json:"candidate_count"`  // 숙려 경과 후보 수
 	AnonymizedCount int      `json:"anonymized_count"` // 이번 실행에서 익명화한 수
 	SkippedCount    int      `json:"skipped_count"`    // 이미 익명화되어 스킵한 수(멱등)
+	WarnedCount     int      `json:"warned_count"`     // 확정 예고 이메일 대상 수 (이메일을 끈 회원 포함)
 	PostsUpdated    int      `json:"posts_updated"`    // 작성자명/본문 익명화된 게시물 행 수
 	Errors          int      `json:"errors"`
 	AnonymizedIDs   []string `json:"anonymized_ids"`
 	ExecutedAt      string   `json:"executed_at"`
 }
 
+// withdrawalWarnDays 는 확정 며칠 전에 예고 이메일을 보낼지다 (숙려중 본인 취소를 놓치지 않게)
+const withdrawalWarnDays = 3
+
 // withdrawalCandidate 는 g5_member 후보 행이다.
 type withdrawalCandidate struct {
 	MbNo        int    `gorm:"column:mb_no"`
@@ -57,7 +63,12 @@ func runWithdrawalGraceAnonymize(db *gorm.DB) (*WithdrawalGraceResult, error) {
 	}
 
 	for _, cand := range candidates {
-		state, _ := common.ClassifyWithdrawal(cand.MbLeaveDate, now)
+		state, deadline := common.ClassifyWithdrawal(cand.MbLeaveDate, now)
+		if state == common.WithdrawalGrace && deadline.Sub(now) <= withdrawalWarnDays*24*time.Hour {
+			if warnWithdrawalDeadline(db, cand.MbID, deadline) {
+				result.WarnedCount++
+			}
+		}
 		if state != common.WithdrawalConfirmed {
 			continue // 아직 숙려중 → 대상 아님
 		}
@@ -83,6 +94,28 @@ func runWithdrawalGraceAnonymize(db *gorm.DB) (*WithdrawalGraceResult, error) {
 	return result, nil
 }
 
+// warnWithdrawalDeadline 은 숙려 만료 예고 이메일을 한 번만 쌓는다 (이번 탈퇴 신청 이후 쌓은 예고가 있으면 생략).
+// 메일 실패는 익명화를 막지 않는다 — 로그만 남긴다.
+func warnWithdrawalDeadline(db *gorm.DB, mbID string, deadline time.Time) bool {
+	var count int64
+	since := deadline.AddDate(0, 0, -common.WithdrawalGraceDays)
+	if err := db.Model(&gnurepo.EmailOutbox{}).
+		Where("mb_id = ? AND template = ? AND created_at >= ?", mbID, "withdrawal_grace", since).
+		Count(&count).Error; err != nil {
+		log.Printf("[Cron:withdrawal-grace] warning lookup failed for %s: %v", mbID, err)
+		return false
+	}
+	if count > 0 {
+		return false
+	}
+	if err := gnurepo.EnqueueEmail(db, mbID, gnurepo.EmailCategoryAccount, "withdrawal_grace", "/login",
+		deadline.Format("2006-01-02 15:04")); err != nil {
+		log.Printf("[Cron:withdrawal-grace] warning enqueue failed for %s: %v", mbID, err)
+		return false
+	}
+	return true
+}
+
 // anonymizeWithdrawnMember 는 한 회원의 신원을 익명화한다(닉네임 + 과거 게시물 작성자명/본문 내 닉).
 // 행은 보존하며 DI(mb_dupinfo)·IP·mb_intercept_date·mb_leave_date 등 식별자는 변경/삭제하지 않는다.
 // 반환값은 익명화된 게시물 행 수.
@@ -113,8 +146,12 @@ func anonymizeWithdrawnMember(db *gorm.DB, cand withdrawalCandidate) (int, error
 			return err
 		}
 		// v2_users 미러 닉네임 동기화 (있으면). username = mb_id.
-		return tx.Table("v2_users").Where("username = ?", cand.MbID).
-			Update("nickname", replacement).Error
+		if err := tx.Table("v2_users").Where("username = ?", cand.MbID).
+			Update("nickname", replacement).Error; err != nil {
+			return err
+		}
+		// 검색 색인의 작성자명·본문도 익명화된 값으로 다시 색인한다 (마커와 같은 tx — 마커가 서면 반드시 남는다)
+		return gnurepo.EnqueueSearchSync(tx, gnudomain.WriteAfterEventTypeSearchMemberSync, "", 0, cand.MbID)
 	}); err != nil {
 		return 0, err
 	}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	gnudomain "github.com/damoang/angple-backend/internal/domain/gnuboard"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -50,6 +51,10 @@ func setupWithdrawalDB(t *testing.T) *gorm.DB {
 	)`).Error; err != nil {
 		t.Fatalf("create g5_write_free: %v", err)
 	}
+	// 익명화는 같은 트랜잭션에서 검색 재색인 이벤트(search_member_sync)를 쌓는다
+	if err := db.AutoMigrate(&gnudomain.WriteAfterEvent{}); err != nil {
+		t.Fatalf("migrate g5_write_after_events: %v", err)
+	}
 	db.Exec(`INSERT INTO g5_board (bo_table) VALUES ('free')`)
 	return db
 }
@@ -152,6 +157,13 @@ func TestRunWithdrawalGraceAnonymize(t *testing.T) {
 	if vnick[:len("탈퇴")] != "탈퇴" {
 		t.Errorf("v2_users nickname should mirror anonymized nick, got %q", vnick)
 	}
+
+	// 검색 색인의 작성자명도 바뀌도록 재색인 이벤트가 남는다
+	var events int64
+	db.Model(&gnudomain.WriteAfterEvent{}).Where("event_type = ? AND member_id = ?", gnudomain.WriteAfterEventTypeSearchMemberSync, "gone").Count(&events)
+	if events != 1 {
+		t.Errorf("search member sync events = %d, want 1", events)
+	}
 }
 
 // MEDIUM(재검): 특정 게시판 UPDATE 실패 시 그 회원은 익명화 마킹되면 안 되고(PII 잔존 방지),
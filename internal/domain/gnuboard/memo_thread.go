@@ -0,0 +1,59 @@
+package gnuboard
+
+import "time"
+
+// MemoThread is one conversation in a member's message list — 두 회원 사이의 g5_memo 를 상대별로 묶은 것.
+// g5_memo 에서 매번 계산한다 (레거시 PHP 도 g5_memo 에 직접 쓰므로 따로 쌓아 두면 어긋난다).
+type MemoThread struct {
+	PeerMbID  string `gorm:"column:peer_mb_id" json:"peer_id"`
+	LastMeID  int    `gorm:"column:last_me_id" json:"last_message_id"`
+	Unread    int64  `gorm:"column:unread" json:"unread_count"`
+	Muted     bool   `gorm:"column:muted" json:"muted"`
+	Archived  bool   `gorm:"column:archived" json:"archived"`
+	PeerNick  string `gorm:"-" json:"peer_name"`
+	LastMemo  string `gorm:"-" json:"last_message"`
+	LastAt    string `gorm:"-" json:"last_datetime"`
+	LastMine  bool   `gorm:"-" json:"last_is_mine"`
+	IsBlocked bool   `gorm:"-" json:"is_blocked"` // 내가 상대를 차단함
+}
+
+// MemoThreadState represents a row in g5_memo_thread — 회원 × 상대 대화방의 보관·알림 끄기 상태
+type MemoThreadState struct {
+	MbID     string `gorm:"column:mb_id;primaryKey;size:20"`
+	PeerMbID string `gorm:"column:peer_mb_id;primaryKey;size:20"`
+	// ArchivedMeID 는 보관할 때의 마지막 쪽지 id 다. 이보다 새 쪽지가 오면 대화방이 다시 목록에 올라온다 (0 = 보관 안 함).
+	ArchivedMeID int       `gorm:"column:archived_me_id;not null;default:0"`
+	Muted        bool      `gorm:"column:muted;not null;default:0"`
+	UpdatedAt    time.Time `gorm:"column:updated_at"`
+}
+
+// TableName returns the g5_memo_thread table name
+func (MemoThreadState) TableName() string { return "g5_memo_thread" }
+
+// MemoAttachment represents a row in g5_memo_attachment — 쪽지에 붙인 이미지 (MediaService 업로드).
+// MeID 는 쪽지 한 쌍의 대표 id(받은 쪽 행의 me_id, G5Memo.PairKey)이고, 0 이면 아직 보내지 않은 업로드다.
+type MemoAttachment struct {
+	ID          int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	MeID        int       `gorm:"column:me_id;not null;default:0;index:idx_memo_attachment_me" json:"-"`
+	MbID        string    `gorm:"column:mb_id;size:20;not null;index:idx_memo_attachment_mb" json:"-"`
+	FileKey     string    `gorm:"column:file_key;size:255;not null" json:"-"`
+	URL         string    `gorm:"column:url;size:500;not null" json:"url"`
+	Filename    string    `gorm:"column:filename;size:255" json:"filename"`
+	ContentType string    `gorm:"column:content_type;size:100" json:"content_type"`
+	Size        int64     `gorm:"column:size" json:"size"`
+	Width       int       `gorm:"column:width" json:"width,omitempty"`
+	Height      int       `gorm:"column:height" json:"height,omitempty"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// TableName returns the g5_memo_attachment table name
+func (MemoAttachment) TableName() string { return "g5_memo_attachment" }
+
+// PairKey returns the id shared by both rows of one message — 받은 쪽(recv) 행의 me_id.
+// 보낸 쪽(send) 행은 me_send_id 로 짝을 가리킨다. 짝이 없는 옛 행은 자기 id 다.
+func (m *G5Memo) PairKey() int {
+	if m.MeType == "send" && m.MeSendID > 0 {
+		return m.MeSendID
+	}
+	return m.MeID
+}
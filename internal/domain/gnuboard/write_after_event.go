@@ -15,6 +15,17 @@ const (
 	WriteAfterEventTypeAffiliateCommentSync   = "affiliate_comment_sync"
 	WriteAfterEventTypeAffiliatePostDelete    = "affiliate_post_delete"
 	WriteAfterEventTypeAffiliateCommentDelete = "affiliate_comment_delete"
+	// WriteAfterEventTypePostMoved 는 다른 게시판으로 옮겨진 글(board_slug/write_id 가 새 위치)이다.
+	// 원래 위치는 같은 트랜잭션에서 post_deleted 로 남는다.
+	WriteAfterEventTypePostMoved = "post_moved"
+
+	// 검색 색인 동기화 이벤트. 워커가 글/댓글 이벤트를 처리한 뒤 파생시키며(회원 단위는 익명화·
+	// 일괄 비공개 쪽에서 직접 넣는다), DB 의 현재 상태를 다시 읽어 색인하거나 지우므로 멱등이다.
+	// 알림·캐시와 재시도 경계를 나누려고 별도 이벤트로 둔다 — ES 장애가 알림을 재발행시키지 않게.
+	WriteAfterEventTypeSearchPostSync    = "search_post_sync"    // 글만
+	WriteAfterEventTypeSearchThreadSync  = "search_thread_sync"  // 글 + 딸린 댓글 (삭제·복구·이동·비밀글 전환)
+	WriteAfterEventTypeSearchCommentSync = "search_comment_sync" // 댓글 하나
+	WriteAfterEventTypeSearchMemberSync  = "search_member_sync"  // member_id 가 쓴 글·댓글 전부
 
 	WriteAfterEventStatusPending    = "pending"
 	WriteAfterEventStatusProcessing = "processing"
@@ -55,19 +55,37 @@ type OAuthLoginResponse struct {
 	RefreshToken string `json:"refresh_token"`
 	IsNewUser    bool   `json:"is_new_user"`
 	UserID       string `json:"user_id"`
+	// Member 면 UserID 는 연동된 실제 회원(mb_id)이다 — 토큰은 여기서 만들지 않고
+	// 회원 로그인 경로(2단계 인증·숙려 분기·세션)가 발급한다.
+	Member bool `json:"-"`
+	// Linked 는 회원 화면에서 시작한 연동이 끝났을 때만 채워진다(토큰 없음)
+	Linked *OAuthAccount `json:"linked,omitempty"`
 }
 
-// APIKey represents an API key for external integrations
+// APIKey is a long-lived credential for bots and server-side jobs (middleware.JWTAuth 가 받는다)
+//
+// 평문 키는 발급·교체 응답에서 한 번만 보여 준다. DB 에는 SHA-256 해시와 식별용 앞부분(prefix)만 남는다.
 type APIKey struct {
-	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
-	Key       string     `gorm:"column:api_key;uniqueIndex;size:64" json:"key"`
-	Name      string     `gorm:"column:name" json:"name"`
-	UserID    string     `gorm:"column:user_id;index" json:"user_id"`
-	Scopes    string     `gorm:"column:scopes" json:"scopes"` // comma-separated: read,write,admin
-	Active    bool       `gorm:"column:active;default:true" json:"active"`
-	ExpiresAt *time.Time `gorm:"column:expires_at" json:"expires_at"`
-	LastUsed  *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
-	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	ID        int64   `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Key       string  `gorm:"-" json:"key,omitempty"` // 발급·교체 직후에만 채워진다
+	KeyHash   string  `gorm:"column:key_hash;uniqueIndex;size:64" json:"-"`
+	Prefix    string  `gorm:"column:key_prefix;size:16" json:"prefix"`
+	LegacyKey *string `gorm:"column:api_key;size:64" json:"-"` // 해시 도입 전 평문 — 마이그레이션이 해시로 옮기고 비운다
+	Name      string  `gorm:"column:name" json:"name"`
+	// UserID 는 g5_member.mb_id 다 (해시 도입 전 키는 v2_users.id 가 들어 있을 수 있다)
+	UserID     string     `gorm:"column:user_id;index" json:"user_id"`
+	Scopes     string     `gorm:"column:scopes" json:"scopes"`                    // comma-separated: posts:read,posts:write,notifications:read,admin:*
+	RateLimit  int        `gorm:"column:rate_limit;default:60" json:"rate_limit"` // 분당 요청 수
+	Active     bool       `gorm:"column:active;default:true" json:"active"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at" json:"expires_at"`
+	LastUsed   *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+	LastUsedIP string     `gorm:"column:last_used_ip;size:45" json:"last_used_ip,omitempty"`
+	UsageCount int64      `gorm:"column:usage_count;default:0" json:"usage_count"`
+	RotatedAt  *time.Time `gorm:"column:rotated_at" json:"rotated_at,omitempty"`
+	// MFAVerifiedAt 은 2단계 인증을 거친 세션에서 발급·교체됐을 때만 채워진다 — 관리자 MFA 정책이 본다
+	MFAVerifiedAt *time.Time `gorm:"column:mfa_verified_at" json:"mfa_verified_at,omitempty"`
+	RevokedAt     *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 }
 
 func (APIKey) TableName() string {
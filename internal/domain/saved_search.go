@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// SavedSearch is a member's named search (고급 검색 조건 저장 + 새 글 알림)
+type SavedSearch struct {
+	ID     int64  `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	MbID   string `gorm:"column:mb_id;type:varchar(20);index:idx_saved_searches_mb" json:"-"`
+	Name   string `gorm:"column:name;type:varchar(100)" json:"name"`
+	Params string `gorm:"column:params;type:text" json:"-"` // service.SearchParams JSON
+	Notify bool   `gorm:"column:notify;index:idx_saved_searches_notify" json:"notify"`
+	// LastMatchAt 은 마지막으로 알린 글의 작성 시각 — 다음 알림은 이보다 뒤에 쓴 글만 센다
+	LastMatchAt   *time.Time `gorm:"column:last_match_at" json:"last_match_at,omitempty"`
+	LastCheckedAt *time.Time `gorm:"column:last_checked_at" json:"last_checked_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table name for SavedSearch
+func (SavedSearch) TableName() string { return "saved_searches" }
@@ -35,6 +35,41 @@ func (Site) TableName() string {
 	return "sites"
 }
 
+// SiteIdentityProvider is a tenant's own OpenID Connect IdP.
+// 회원은 /api/v2/auth/oauth/sso-<subdomain> 으로 로그인한다 (사이트가 활성·미정지일 때만).
+type SiteIdentityProvider struct {
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	SiteID       string `gorm:"column:site_id;type:varchar(64);uniqueIndex" json:"site_id"`
+	DisplayName  string `gorm:"column:display_name;size:100" json:"display_name"`
+	Issuer       string `gorm:"column:issuer;size:255" json:"issuer"`
+	ClientID     string `gorm:"column:client_id;size:255" json:"client_id"`
+	ClientSecret string `gorm:"column:client_secret;size:512" json:"-"`
+	RedirectURL  string `gorm:"column:redirect_url;size:512" json:"redirect_url"`
+	Scopes       string `gorm:"column:scopes;size:255" json:"scopes"` // 공백 구분 (비우면 openid email profile)
+
+	ID int64 `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+
+	Enabled bool `gorm:"column:enabled;default:true" json:"enabled"`
+}
+
+func (SiteIdentityProvider) TableName() string {
+	return "site_identity_providers"
+}
+
+// SiteIdentityProviderRequest is the body of PUT /api/v2/saas/communities/:id/idp
+// client_secret 을 비우면 저장된 값을 그대로 둔다.
+type SiteIdentityProviderRequest struct {
+	DisplayName  string `json:"display_name" binding:"max=100"`
+	Issuer       string `json:"issuer" binding:"required,url"`
+	ClientID     string `json:"client_id" binding:"required"`
+	ClientSecret string `json:"client_secret"`
+	RedirectURL  string `json:"redirect_url" binding:"required,url"`
+	Scopes       string `json:"scopes"`
+	Enabled      *bool  `json:"enabled"`
+}
+
 // SiteSettings represents site-specific configuration
 type SiteSettings struct {
 	// time.Time 필드들
@@ -0,0 +1,30 @@
+package v2
+
+import "time"
+
+// V2AuthSession is one logged-in device: a refresh token family that rotates on every refresh.
+//
+// 리프레시 토큰에는 sid(FamilyID)와 jti(TokenID)가 실린다. 갱신할 때마다 TokenID 가 바뀌고,
+// 이미 바뀐 옛 토큰이 다시 오면 탈취로 보고 패밀리 전체를 폐기한다.
+type V2AuthSession struct {
+	ID         uint64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	FamilyID   string     `gorm:"column:family_id;type:varchar(32);not null;uniqueIndex" json:"-"`
+	UserID     uint64     `gorm:"column:user_id;not null;index" json:"-"`
+	Username   string     `gorm:"column:username;type:varchar(50);not null;index" json:"-"` // g5_member.mb_id (관리자 강제 로그아웃)
+	TokenID    string     `gorm:"column:token_id;type:varchar(32);not null" json:"-"`       // 지금 유효한 리프레시 토큰의 jti
+	Generation int        `gorm:"column:generation;not null;default:0" json:"-"`
+	DeviceName string     `gorm:"column:device_name;type:varchar(100)" json:"device_name"`
+	Platform   string     `gorm:"column:platform;type:varchar(16)" json:"platform"`
+	UserAgent  string     `gorm:"column:user_agent;type:varchar(255)" json:"user_agent"`
+	IP         string     `gorm:"column:ip;type:varchar(45)" json:"ip"` // 마지막으로 본 IP
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	LastSeenAt time.Time  `gorm:"column:last_seen_at" json:"last_seen_at"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;index" json:"expires_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"-"`
+	// MFAVerifiedAt: 이 세션이 2단계 인증을 거친 시각(로그인 때 또는 step-up). 회전해도 유지된다
+	MFAVerifiedAt *time.Time `gorm:"column:mfa_verified_at" json:"mfa_verified_at"`
+	// RevokeReason: logout | user | user_all | reuse_detected | admin_ban
+	RevokeReason string `gorm:"column:revoke_reason;type:varchar(30)" json:"-"`
+}
+
+func (V2AuthSession) TableName() string { return "v2_auth_sessions" }
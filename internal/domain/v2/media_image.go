@@ -0,0 +1,53 @@
+package v2
+
+import "time"
+
+// V2MediaImage is one uploaded image (MediaService.UploadImage) with its resized variants.
+// 목록 썸네일은 본문·wr_10 에 박힌 원본 주소(URL)로 찾아 목록 크기 파생본으로 바꿔 내보낸다.
+type V2MediaImage struct {
+	ID          uint64 `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Key         string `gorm:"column:file_key;type:varchar(255);not null;uniqueIndex" json:"key"`
+	URL         string `gorm:"column:url;type:varchar(500);not null;index" json:"url"`
+	ContentType string `gorm:"column:content_type;type:varchar(100)" json:"content_type"`
+	Width       int    `gorm:"column:width" json:"width"`
+	Height      int    `gorm:"column:height" json:"height"`
+	// Blurhash 는 로딩 중 보여 줄 흐린 자리표시 이미지다 (https://blurha.sh)
+	Blurhash  string           `gorm:"column:blurhash;type:varchar(64)" json:"blurhash,omitempty"`
+	CreatedAt time.Time        `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	Variants  []V2MediaVariant `gorm:"foreignKey:ImageID" json:"variants,omitempty"`
+}
+
+func (V2MediaImage) TableName() string { return "v2_media_images" }
+
+// V2MediaVariant is one resized copy of an image — 크기(thumb·list·full) × 형식(webp, 원본 형식)
+type V2MediaVariant struct {
+	ID      uint64 `gorm:"column:id;primaryKey;autoIncrement" json:"-"`
+	ImageID uint64 `gorm:"column:image_id;not null;index" json:"-"`
+	Name    string `gorm:"column:name;type:varchar(16);not null" json:"name"`
+	Format  string `gorm:"column:format;type:varchar(8);not null" json:"format"`
+	Key     string `gorm:"column:file_key;type:varchar(255);not null" json:"key"`
+	URL     string `gorm:"column:url;type:varchar(500);not null" json:"url"`
+	Width   int    `gorm:"column:width" json:"width"`
+	Height  int    `gorm:"column:height" json:"height"`
+	Size    int64  `gorm:"column:size" json:"size"`
+}
+
+func (V2MediaVariant) TableName() string { return "v2_media_image_variants" }
+
+// Variant returns the named variant, WebP first (원본 형식은 WebP 를 못 만든 빌드·이미지용) — 없으면 nil
+func (m *V2MediaImage) Variant(name string) *V2MediaVariant {
+	var fallback *V2MediaVariant
+	for i := range m.Variants {
+		v := &m.Variants[i]
+		if v.Name != name {
+			continue
+		}
+		if v.Format == "webp" {
+			return v
+		}
+		if fallback == nil {
+			fallback = v
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,47 @@
+package v2
+
+import "time"
+
+// V2MediaObject is one stored file, addressed by the SHA-256 of its content (storage.ContentKey).
+// 같은 내용을 다시 올리면 새로 저장하지 않고 이 행을 돌려준다.
+// RefCount 는 media-gc 잡이 v2_media_refs 로 다시 센 값이고, 0 이 된 시각이 OrphanedAt 이다.
+type V2MediaObject struct {
+	ID          uint64 `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	SHA256      string `gorm:"column:sha256;type:char(64);not null;uniqueIndex" json:"sha256"`
+	Key         string `gorm:"column:file_key;type:varchar(255);not null;uniqueIndex" json:"key"`
+	URL         string `gorm:"column:url;type:varchar(500);not null" json:"url"`
+	ContentType string `gorm:"column:content_type;type:varchar(100)" json:"content_type"`
+	Size        int64  `gorm:"column:size" json:"size"`
+	RefCount    int    `gorm:"column:ref_count;not null;default:0" json:"ref_count"`
+	// OrphanedAt 은 참조가 없어진(또는 올린 뒤 아직 어디에도 쓰이지 않은) 시각이다 — 참조가 있으면 NULL
+	OrphanedAt *time.Time `gorm:"column:orphaned_at;index" json:"orphaned_at,omitempty"`
+	// LastUploadedAt 은 마지막으로 같은 내용이 올라온 시각이다 — 유예기간은 이 시각부터도 센다
+	LastUploadedAt time.Time `gorm:"column:last_uploaded_at;not null" json:"last_uploaded_at"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (V2MediaObject) TableName() string { return "v2_media_objects" }
+
+// Media reference types (V2MediaRef.RefType)
+const (
+	MediaRefPost      = "post"      // RefKey: bo_table:wr_id (본문·wr_10·첨부), v2_posts:id, v2_files:id
+	MediaRefComment   = "comment"   // RefKey: bo_table:wr_id, v2_comments:id
+	MediaRefProfile   = "profile"   // RefKey: mb_id (g5_member.mb_image_url), v2_users:id (avatar_url)
+	MediaRefSiteLogo  = "site_logo" // RefKey: site_logos.id, site_settings:site_id (logo·favicon)
+	MediaRefMessage   = "message"   // RefKey: g5_memo_attachment.id
+	MediaRefBanner    = "banner"    // RefKey: banners:id
+	MediaRefPromotion = "promotion" // RefKey: promotion_posts:id (이미지·본문)
+	MediaRefPage      = "page"      // RefKey: g5_content:co_id, g5_board:bo_table (글쓰기 기본 내용)
+	MediaRefRevision  = "revision"  // RefKey: v2_content_revisions:id — 되돌릴 수 있는 동안 파일을 지킨다
+)
+
+// V2MediaRef links an object to one place that uses it — media-gc 가 매번 전부 다시 스캔해 채운다
+type V2MediaRef struct {
+	ID        uint64    `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	ObjectID  uint64    `gorm:"column:object_id;not null;uniqueIndex:uk_media_ref,priority:1" json:"object_id"`
+	RefType   string    `gorm:"column:ref_type;type:varchar(16);not null;uniqueIndex:uk_media_ref,priority:2" json:"ref_type"`
+	RefKey    string    `gorm:"column:ref_key;type:varchar(100);not null;uniqueIndex:uk_media_ref,priority:3" json:"ref_key"`
+	ScannedAt time.Time `gorm:"column:scanned_at;not null;index" json:"scanned_at"`
+}
+
+func (V2MediaRef) TableName() string { return "v2_media_refs" }
@@ -0,0 +1,41 @@
+package v2
+
+import "time"
+
+// Media upload states (V2MediaUpload.Status)
+const (
+	MediaUploadPending   = "pending"
+	MediaUploadCompleted = "completed"
+)
+
+// V2MediaUpload is one resumable (tus) upload under /api/v2/media/uploads.
+// 조각은 저장소에 따로 올려 두고(V2MediaUploadPart) 완료할 때 한 파일로 합친다 — 어느 API 인스턴스가 받아도 이어진다.
+type V2MediaUpload struct {
+	ID       string `gorm:"column:id;type:varchar(32);primaryKey" json:"id"`
+	MbID     string `gorm:"column:mb_id;type:varchar(20);not null;index" json:"-"`
+	Kind     string `gorm:"column:kind;type:varchar(16);not null" json:"kind"` // video | attachment
+	Filename string `gorm:"column:filename;type:varchar(255);not null" json:"filename"`
+	Length   int64  `gorm:"column:upload_length;not null" json:"length"`
+	Offset   int64  `gorm:"column:upload_offset;not null;default:0" json:"offset"`
+	Status   string `gorm:"column:status;type:varchar(16);not null;default:pending" json:"status"`
+	// 완료된 업로드의 결과 — 응답을 놓친 클라이언트가 complete 를 다시 불러도 같은 결과를 준다
+	FileKey     string    `gorm:"column:file_key;type:varchar(255)" json:"key,omitempty"`
+	URL         string    `gorm:"column:url;type:varchar(500)" json:"url,omitempty"`
+	ContentType string    `gorm:"column:content_type;type:varchar(100)" json:"content_type,omitempty"`
+	ExpiresAt   time.Time `gorm:"column:expires_at;not null;index" json:"expires_at"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (V2MediaUpload) TableName() string { return "v2_media_uploads" }
+
+// V2MediaUploadPart is one received chunk stored at FileKey (PATCH 한 번 = 조각 하나)
+type V2MediaUploadPart struct {
+	ID       uint64 `gorm:"column:id;primaryKey;autoIncrement" json:"-"`
+	UploadID string `gorm:"column:upload_id;type:varchar(32);not null;uniqueIndex:uk_media_upload_part,priority:1" json:"-"`
+	Offset   int64  `gorm:"column:part_offset;not null;uniqueIndex:uk_media_upload_part,priority:2" json:"offset"`
+	Size     int64  `gorm:"column:size;not null" json:"size"`
+	FileKey  string `gorm:"column:file_key;type:varchar(255);not null" json:"-"`
+}
+
+func (V2MediaUploadPart) TableName() string { return "v2_media_upload_parts" }
@@ -0,0 +1,32 @@
+package v2
+
+import "time"
+
+// V2UserMFA is a member's TOTP second factor (사용자당 한 행)
+//
+// 등록을 시작하면 Enabled=false 로 시크릿만 저장되고, 첫 코드를 확인해야 Enabled 가 된다.
+type V2UserMFA struct {
+	UserID   uint64 `gorm:"column:user_id;primaryKey;autoIncrement:false" json:"-"`
+	Username string `gorm:"column:username;type:varchar(50);not null;index" json:"-"`
+	// Secret: base32 TOTP 시크릿. MFA_SECRET_KEY 가 있으면 "v1:" 접두 AES-GCM 봉인본이다
+	Secret    string     `gorm:"column:secret;type:varchar(255);not null" json:"-"`
+	Enabled   bool       `gorm:"column:enabled;not null;default:false" json:"enabled"`
+	EnabledAt *time.Time `gorm:"column:enabled_at" json:"enabled_at"`
+	// LastStep: 마지막으로 받은 TOTP time step — 같은 코드를 두 번 받지 않는다
+	LastStep  int64     `gorm:"column:last_step;not null;default:0" json:"-"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (V2UserMFA) TableName() string { return "v2_user_mfa" }
+
+// V2MFARecoveryCode is one single-use recovery code (sha256 해시만 저장)
+type V2MFARecoveryCode struct {
+	ID        uint64     `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID    uint64     `gorm:"column:user_id;not null;index"`
+	CodeHash  string     `gorm:"column:code_hash;type:char(64);not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (V2MFARecoveryCode) TableName() string { return "v2_mfa_recovery_codes" }
@@ -0,0 +1,19 @@
+package v2
+
+import "time"
+
+// V2JWTSigningKey is one asymmetric JWT signing key (pkg/jwt 키링의 영속본).
+//
+// 다음 키는 ActivatesAt 보다 먼저 만들어 JWKS 에 미리 싣고, 이전 키는 RetiresAt 까지 검증에만 쓴다
+// (겹치는 기간 = 리프레시 토큰 수명). 은퇴한 키는 로테이션 잡이 지운다.
+type V2JWTSigningKey struct {
+	KID         string     `gorm:"column:kid;type:varchar(64);primaryKey" json:"kid"` // RFC 7638 thumbprint
+	Algorithm   string     `gorm:"column:algorithm;type:varchar(10);not null" json:"algorithm"`
+	PrivateKey  string     `gorm:"column:private_key;type:text;not null" json:"-"` // PKCS#8, AES-GCM 봉인 + base64
+	PublicKey   string     `gorm:"column:public_key;type:text;not null" json:"-"`  // PKIX, base64
+	ActivatesAt time.Time  `gorm:"column:activates_at;not null;index" json:"activates_at"`
+	RetiresAt   *time.Time `gorm:"column:retires_at" json:"retires_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (V2JWTSigningKey) TableName() string { return "v2_jwt_signing_keys" }
@@ -0,0 +1,18 @@
+package v2
+
+import "time"
+
+// V2WebPushSubscription is a browser Web Push subscription (PushSubscription) of a user.
+// 앱 기기(V2Device, Expo)와 따로 둔다 — 주소가 토큰이 아니라 엔드포인트 URL + 암호화 키다.
+type V2WebPushSubscription struct {
+	ID        uint64    `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID    uint64    `gorm:"column:user_id;not null;index" json:"user_id"`
+	Endpoint  string    `gorm:"column:endpoint;type:varchar(512);not null;uniqueIndex" json:"endpoint"`
+	P256dh    string    `gorm:"column:p256dh;type:varchar(128);not null" json:"-"`
+	Auth      string    `gorm:"column:auth;type:varchar(64);not null" json:"-"`
+	UserAgent string    `gorm:"column:user_agent;type:varchar(255)" json:"user_agent,omitempty"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (V2WebPushSubscription) TableName() string { return "v2_web_push_subscriptions" }
@@ -0,0 +1,28 @@
+package v2
+
+import "time"
+
+// V2WebAuthnCredential is one passkey (WebAuthn 공개키 자격증명) of a member
+//
+// 회원마다 여러 개를 둘 수 있다 — 휴대폰·노트북·보안 키를 각각 등록한다.
+type V2WebAuthnCredential struct {
+	ID       uint64 `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID   uint64 `gorm:"column:user_id;not null;index" json:"-"`
+	Username string `gorm:"column:username;type:varchar(50);not null" json:"-"`
+	// CredentialID: 인증기가 준 credential id (base64url, 패딩 없음)
+	CredentialID    string `gorm:"column:credential_id;type:varchar(255);not null;uniqueIndex" json:"-"`
+	PublicKey       []byte `gorm:"column:public_key;type:blob;not null" json:"-"`
+	AttestationType string `gorm:"column:attestation_type;type:varchar(32)" json:"-"`
+	AAGUID          string `gorm:"column:aaguid;type:char(32)" json:"aaguid"` // 인증기 모델 (hex)
+	// SignCount: 인증기 서명 카운터 — 줄어들면 복제된 키로 본다(0 만 보내는 인증기는 예외)
+	SignCount      uint32 `gorm:"column:sign_count;not null;default:0" json:"-"`
+	Transports     string `gorm:"column:transports;type:varchar(100)" json:"transports"` // 쉼표 구분 (usb,nfc,ble,internal,hybrid)
+	BackupEligible bool   `gorm:"column:backup_eligible;not null;default:false" json:"backup_eligible"`
+	BackupState    bool   `gorm:"column:backup_state;not null;default:false" json:"synced"`
+	Name           string `gorm:"column:name;type:varchar(100);not null" json:"name"`
+
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+}
+
+func (V2WebAuthnCredential) TableName() string { return "v2_webauthn_credentials" }
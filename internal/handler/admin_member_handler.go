@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -17,7 +18,8 @@ import (
 
 // AdminMemberHandler handles admin member management (g5_member based)
 type AdminMemberHandler struct {
-	db *gorm.DB
+	db             *gorm.DB
+	revokeSessions func(ctx context.Context, mbID, reason string) (int64, error)
 }
 
 // NewAdminMemberHandler creates a new AdminMemberHandler
@@ -25,6 +27,11 @@ func NewAdminMemberHandler(db *gorm.DB) *AdminMemberHandler {
 	return &AdminMemberHandler{db: db}
 }
 
+// SetSessionRevoker enables forced logout on ban (v2 SessionService.RevokeAllForMember 주입)
+func (h *AdminMemberHandler) SetSessionRevoker(fn func(ctx context.Context, mbID, reason string) (int64, error)) {
+	h.revokeSessions = fn
+}
+
 // adminMemberResponse is the response DTO matching frontend AdminMember type
 type adminMemberResponse struct {
 	MbID            string  `json:"mb_id"`
@@ -329,7 +336,17 @@ func (h *AdminMemberHandler) BanMember(c *gin.Context) {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "차단 실패", err)
 		return
 	}
-	common.V2Success(c, gin.H{"message": "차단 완료"})
+	// 강제 로그아웃 — 모든 기기의 세션을 폐기한다. 차단 자체는 이미 반영됐으므로 실패해도 되돌리지 않는다
+	// (남은 토큰은 BanCheck 가 쓰기를 막고, access TTL 안에 끝난다).
+	var revoked int64
+	if h.revokeSessions != nil {
+		n, err := h.revokeSessions(c.Request.Context(), mbID, "admin_ban")
+		if err != nil {
+			log.Printf("[admin] 차단 회원 세션 폐기 실패 (%s): %v", mbID, err)
+		}
+		revoked = n
+	}
+	common.V2Success(c, gin.H{"message": "차단 완료", "revoked_sessions": revoked})
 }
 
 // UnbanMember handles POST /api/v1/admin/members/:id/unban
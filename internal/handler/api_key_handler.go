@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles a member's API keys (/api/v2/auth/api-keys)
+//
+// ⛔ 이 경로는 API 키로는 들어올 수 없다(middleware.apiKeyRouteScopes 에 없다) —
+// 키가 키를 만들거나 교체하면 폐기해도 계속 살아남는다.
+type APIKeyHandler struct {
+	service *service.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(svc *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: svc}
+}
+
+// List handles GET /api/v2/auth/api-keys
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.service.List(c.Request.Context(), middleware.GetUsername(c))
+	if err != nil {
+		common.ErrorResponse(c, http.StatusInternalServerError, "Failed to load API keys", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": keys})
+}
+
+// Create handles POST /api/v2/auth/api-keys
+// Body: {"name": "딜 알림 봇", "scopes": ["posts:read"], "rate_limit": 60, "expires_in_days": 90}
+// 응답의 key 는 이때 한 번만 보인다.
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var in service.APIKeyInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+	if !h.requireAdminMFA(c) {
+		return
+	}
+	key, err := h.service.Create(c.Request.Context(), middleware.GetUsername(c), middleware.GetUserLevel(c), in, middleware.HasMFA(c))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": key})
+}
+
+// Rotate handles POST /api/v2/auth/api-keys/:id/rotate
+func (h *APIKeyHandler) Rotate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid API key ID", nil)
+		return
+	}
+	if !h.requireAdminMFA(c) {
+		return
+	}
+	key, err := h.service.Rotate(c.Request.Context(), middleware.GetUsername(c), id, middleware.HasMFA(c))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": key})
+}
+
+// Revoke handles DELETE /api/v2/auth/api-keys/:id
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid API key ID", nil)
+		return
+	}
+	if err := h.service.Revoke(c.Request.Context(), middleware.GetUsername(c), id); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *APIKeyHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAPIKey):
+		common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, service.ErrAPIKeyLimit):
+		common.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		common.ErrorResponse(c, http.StatusNotFound, "API key not found", nil)
+	default:
+		common.ErrorResponse(c, http.StatusInternalServerError, "API key request failed", err)
+	}
+}
+
+// requireAdminMFA — 관리자 MFA 정책이 켜져 있으면 관리자는 2단계를 거친 세션에서만 키를 만들고 바꾼다.
+// 2단계 없이 만든 키는 어차피 RequireAdmin 에서 막히지만, 쓸 수 없는 관리자 키를 내주지 않도록 여기서 먼저 거절한다.
+func (h *APIKeyHandler) requireAdminMFA(c *gin.Context) bool {
+	if middleware.AdminMFARequired() && middleware.GetUserLevel(c) >= 10 && !middleware.HasMFA(c) {
+		c.JSON(http.StatusForbidden, common.V2Response{
+			Success: false,
+			Error:   &common.V2Error{Code: "mfa_required", Message: "관리자 계정의 API 키는 2단계 인증 후 발급할 수 있습니다"},
+		})
+		return false
+	}
+	return true
+}
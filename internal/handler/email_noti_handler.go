@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/middleware"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"github.com/damoang/angple-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// EmailNotiHandler handles email notification settings, unsubscribe links and the suppression list
+type EmailNotiHandler struct {
+	repo gnurepo.NotiEmailRepository
+	// svc 가 nil 이면 메일 발송이 꺼져 있다 — 설정은 저장되지만 수신 거부 토큰은 검증할 수 없다
+	svc *service.EmailNotificationService
+}
+
+// NewEmailNotiHandler creates a new EmailNotiHandler
+func NewEmailNotiHandler(repo gnurepo.NotiEmailRepository, svc *service.EmailNotificationService) *EmailNotiHandler {
+	return &EmailNotiHandler{repo: repo, svc: svc}
+}
+
+// GetPreferences handles GET /api/v1/notifications/preferences/email
+// 분류별 받는 주기 — off | instant | daily | weekly
+func (h *EmailNotiHandler) GetPreferences(c *gin.Context) {
+	mbID := middleware.GetUserID(c)
+	if mbID == "" {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증이 필요합니다", nil)
+		return
+	}
+	prefs, err := h.repo.Preferences(mbID)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "이메일 알림 설정 조회 실패", err)
+		return
+	}
+	common.V2Success(c, prefs)
+}
+
+// UpdatePreferences handles PUT /api/v1/notifications/preferences/email
+// 본문은 {"point":"daily","digest":"off"} 처럼 바꿀 분류만 보낸다.
+func (h *EmailNotiHandler) UpdatePreferences(c *gin.Context) {
+	mbID := middleware.GetUserID(c)
+	if mbID == "" {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증이 필요합니다", nil)
+		return
+	}
+	var req map[string]string
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 요청", err)
+		return
+	}
+	for category, frequency := range req {
+		if !gnurepo.ValidEmailCategory(category) || !gnurepo.ValidEmailFrequency(frequency) {
+			common.V2ErrorResponse(c, http.StatusBadRequest, "알 수 없는 분류 또는 주기: "+category+"="+frequency, nil)
+			return
+		}
+	}
+	for category, frequency := range req {
+		if err := h.repo.SetPreference(mbID, category, frequency); err != nil {
+			common.V2ErrorResponse(c, http.StatusInternalServerError, "이메일 알림 설정 저장 실패", err)
+			return
+		}
+	}
+	h.GetPreferences(c)
+}
+
+// UnsubscribeInfo handles GET /api/v2/email/unsubscribe?token= — 확인 페이지가 무엇을 끄는지 보여 준다.
+// ⛔ GET 으로 끄지 않는다: 메일 보안 스캐너·미리보기가 링크를 먼저 열어 본다 (RFC 8058 §1).
+func (h *EmailNotiHandler) UnsubscribeInfo(c *gin.Context) {
+	if h.svc == nil {
+		common.V2ErrorResponse(c, http.StatusServiceUnavailable, "이메일 알림이 꺼져 있습니다", nil)
+		return
+	}
+	_, categories, err := h.svc.ParseUnsubscribeToken(c.Query("token"))
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "유효하지 않은 수신 거부 링크입니다", err)
+		return
+	}
+	common.V2Success(c, gin.H{"categories": categories})
+}
+
+// Unsubscribe handles POST /api/v2/email/unsubscribe?token= — RFC 8058 one-click.
+// 메일 앱은 본문 List-Unsubscribe=One-Click 으로, 사이트 확인 페이지는 그냥 POST 한다. 로그인은 필요 없다(토큰이 증명).
+func (h *EmailNotiHandler) Unsubscribe(c *gin.Context) {
+	if h.svc == nil {
+		common.V2ErrorResponse(c, http.StatusServiceUnavailable, "이메일 알림이 꺼져 있습니다", nil)
+		return
+	}
+	token := c.Query("token")
+	if token == "" {
+		token = c.PostForm("token")
+	}
+	categories, err := h.svc.Unsubscribe(token)
+	if errors.Is(err, service.ErrInvalidUnsubscribeToken) {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "유효하지 않은 수신 거부 링크입니다", err)
+		return
+	}
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "수신 거부 처리 실패", err)
+		return
+	}
+	common.V2Success(c, gin.H{"categories": categories})
+}
+
+// ListSuppressions handles GET /api/v1/admin/email/suppressions
+func (h *EmailNotiHandler) ListSuppressions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "50"))
+	page = max(page, 1)
+	perPage = min(max(perPage, 1), 200)
+	rows, total, err := h.repo.ListSuppressions((page-1)*perPage, perPage)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "수신 거부 목록 조회 실패", err)
+		return
+	}
+	common.V2SuccessWithMeta(c, rows, common.NewV2Meta(page, perPage, total))
+}
+
+// AddSuppression handles POST /api/v1/admin/email/suppressions — 스팸 신고·요청으로 주소를 막는다
+func (h *EmailNotiHandler) AddSuppression(c *gin.Context) {
+	var req struct {
+		Email  string `json:"email" binding:"required"`
+		Reason string `json:"reason"`
+		Detail string `json:"detail"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 요청", err)
+		return
+	}
+	addr, err := mail.ParseAddress(req.Email)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "이메일 주소 형식이 올바르지 않습니다", err)
+		return
+	}
+	switch req.Reason {
+	case "":
+		req.Reason = gnurepo.EmailSuppressionManual
+	case gnurepo.EmailSuppressionManual, gnurepo.EmailSuppressionComplaint, gnurepo.EmailSuppressionBounce:
+	default:
+		common.V2ErrorResponse(c, http.StatusBadRequest, "알 수 없는 사유", nil)
+		return
+	}
+	if err := h.repo.Suppress(addr.Address, req.Reason, strings.TrimSpace(req.Detail)); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "수신 거부 등록 실패", err)
+		return
+	}
+	common.V2Created(c, gin.H{"email": addr.Address, "reason": req.Reason})
+}
+
+// RemoveSuppression handles DELETE /api/v1/admin/email/suppressions?email= — 주소를 고친 회원 등
+func (h *EmailNotiHandler) RemoveSuppression(c *gin.Context) {
+	email := strings.TrimSpace(c.Query("email"))
+	if email == "" {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "email 이 필요합니다", nil)
+		return
+	}
+	if err := h.repo.Unsuppress(email); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "수신 거부 해제 실패", err)
+		return
+	}
+	common.V2Success(c, gin.H{"email": email})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/damoang/angple-backend/internal/middleware"
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/internal/ws"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -644,9 +645,49 @@ func (h *GivingHandler) bidFreeEntry(c *gin.Context, post *givingPostRow, meta g
 		givingErr(c, http.StatusInternalServerError, "참가 처리에 실패했습니다.")
 		return
 	}
+	h.publishBidCounts(post.WrID)
 	givingOK(c, gin.H{"joined": true, "method": meta.Method, "points_spent": cost})
 }
 
+// publishBidCounts sends the giving's participant·number counts (Detail 의 participant_count·total_numbers·total_bids)
+// to its readers. 누가 몇 번을 골랐는지는 싣지 않는다 — 최저 고유 숫자 방식에서 그건 정답 힌트다.
+func (h *GivingHandler) publishBidCounts(wrID int) {
+	if h.topics == nil {
+		return
+	}
+	var counts struct {
+		Participants int `gorm:"column:participants"`
+		Numbers      int `gorm:"column:numbers"`
+		Bids         int `gorm:"column:bids"`
+	}
+	if err := h.db.Table("g5_giving_bid").
+		Select("COUNT(DISTINCT mb_id) AS participants, COALESCE(SUM(bid_count), 0) AS numbers, COUNT(*) AS bids").
+		Where("bo_table = ? AND wr_id = ? AND bid_status = 1", givingBoardSlug, wrID).
+		Scan(&counts).Error; err != nil {
+		return
+	}
+	h.topics.PublishTopic(ws.GivingTopic(wrID), &ws.Event{
+		Type: "giving_bids",
+		Payload: gin.H{
+			"wr_id":             wrID,
+			"participant_count": counts.Participants,
+			"total_numbers":     counts.Numbers,
+			"total_bids":        counts.Bids,
+		},
+	}, "")
+}
+
+// publishDrawn tells the giving's readers that a result is out — 화면은 Detail 을 다시 읽는다
+func (h *GivingHandler) publishDrawn(wrID int, redraw bool) {
+	if h.topics == nil {
+		return
+	}
+	h.topics.PublishTopic(ws.GivingTopic(wrID), &ws.Event{
+		Type:    "giving_drawn",
+		Payload: gin.H{"wr_id": wrID, "redraw": redraw},
+	}, "")
+}
+
 // bidLowestUnique parses numbers, blocks duplicates, and settles points
 // atomically: full deduction from the bidder + 50% credit to the host, both in
 // one transaction with the bid insert (근본적 원자화 — 레거시 fix_missing_points 재발 방지).
@@ -769,6 +810,7 @@ func (h *GivingHandler) bidLowestUnique(c *gin.Context, post *givingPostRow, met
 		givingErr(c, http.StatusInternalServerError, "응모 처리에 실패했습니다.")
 		return
 	}
+	h.publishBidCounts(post.WrID)
 	givingOK(c, gin.H{
 		"joined":       true,
 		"numbers":      givingdomain.FormatNumbers(parsed),
@@ -1283,6 +1325,7 @@ func (h *GivingHandler) RunDueDrawSweep() (*GivingSweepResult, error) {
 			continue
 		}
 		res.Redrawn++
+		h.publishDrawn(r.WrID, true)
 	}
 
 	return res, nil
@@ -1434,6 +1477,7 @@ func (h *GivingHandler) ClaimGiving(c *gin.Context) {
 // cron 스윕과 수동 개표(Draw)·강제종료(AdminAction) 모두에서 호출된다.
 // 알림 실패는 개표 결과에 영향을 주면 안 되므로 전부 흡수한다.
 func (h *GivingHandler) NotifyDrawResult(wrID int) {
+	h.publishDrawn(wrID, false)
 	post, err := h.loadGivingPost(wrID)
 	if err != nil || post == nil {
 		return
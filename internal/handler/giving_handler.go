@@ -11,6 +11,7 @@ import (
 	givingdomain "github.com/damoang/angple-backend/internal/domain/giving"
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/internal/ws"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -30,6 +31,10 @@ type GivingHandler struct {
 	fileRepo        *gnurepo.FileRepository
 	cdnURL          string
 	pointConfigRepo v2repo.PointConfigRepository
+	// topics 는 응모 수·개표 결과를 나눔 토픽(ws.GivingTopic) 구독자에게 흘린다 (nil 이면 생략)
+	topics ws.TopicPublisher
+	// mediaImages 는 썸네일을 업로드 때 만든 목록 크기 파생본으로 바꾼다 (nil 이면 원본 그대로)
+	mediaImages v2repo.MediaImageRepository
 }
 
 // NewGivingHandler creates a new GivingHandler.
@@ -45,6 +50,16 @@ func NewGivingHandler(db *gorm.DB, fileRepo *gnurepo.FileRepository, cdnURL stri
 	}
 }
 
+// SetTopicPublisher enables live bid counts and draw results on giving topics
+func (h *GivingHandler) SetTopicPublisher(topics ws.TopicPublisher) {
+	h.topics = topics
+}
+
+// SetMediaImages serves list-size image variants as thumbnails
+func (h *GivingHandler) SetMediaImages(images v2repo.MediaImageRepository) {
+	h.mediaImages = images
+}
+
 // GivingListItem represents a giving item in list response
 type GivingListItem struct {
 	ID               int    `json:"id"`
@@ -137,7 +152,9 @@ func givingListLess(a, b GivingListItem, sortBy string) bool {
 
 // enrichThumbnails fills Thumbnail when Extra10 is empty.
 // Priority: extra_10 (already set) > 본문 첫 <img> > g5_board_file 첫 이미지.
+// 끝으로 업로드 파생본이 있는 이미지는 목록 크기(list)로 바꾼다.
 func (h *GivingHandler) enrichThumbnails(items []GivingListItem, contentByID map[int]string) {
+	defer h.useListVariants(items)
 	needFileLookup := make([]int, 0, len(items))
 	for i := range items {
 		if items[i].Extra10 != "" || items[i].Thumbnail != "" {
@@ -175,6 +192,45 @@ func (h *GivingHandler) enrichThumbnails(items []GivingListItem, contentByID map
 	}
 }
 
+// useListVariants swaps extra_10·thumbnail for the "list" variant of uploaded images.
+// 목록 카드 전용 응답이라 extra_10 도 바꾼다 — 프론트가 extra_10 을 먼저 쓰므로 그래야 원본을 안 받는다.
+func (h *GivingHandler) useListVariants(items []GivingListItem) {
+	if h.mediaImages == nil {
+		return
+	}
+	urls := make([]string, 0, 2*len(items))
+	for i := range items {
+		for _, u := range []string{items[i].Extra10, items[i].Thumbnail} {
+			if u != "" {
+				urls = append(urls, u)
+			}
+		}
+	}
+	if len(urls) == 0 {
+		return
+	}
+	images, err := h.mediaImages.FindByURLs(urls)
+	if err != nil || len(images) == 0 {
+		return
+	}
+	listURL := func(u string) string {
+		if img, ok := images[u]; ok {
+			if v := img.Variant("list"); v != nil {
+				return v.URL
+			}
+		}
+		return u
+	}
+	for i := range items {
+		if items[i].Extra10 != "" {
+			items[i].Extra10 = listURL(items[i].Extra10)
+		}
+		if items[i].Thumbnail != "" {
+			items[i].Thumbnail = listURL(items[i].Thumbnail)
+		}
+	}
+}
+
 // List returns giving posts filtered by tab (active/ended)
 // GET /api/plugins/giving/list?tab=active&limit=8&sort=urgent
 func (h *GivingHandler) List(c *gin.Context) {
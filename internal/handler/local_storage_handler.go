@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/damoang/angple-backend/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// localFileMaxAge 는 만료 없는 주소의 캐시 기간이다 — key 에 업로드 시각이 들어가 내용이 바뀌지 않는다
+const localFileMaxAge = 365 * 24 * time.Hour
+
+// LocalStorageHandler serves files of storage.LocalBackend through signed URLs
+type LocalStorageHandler struct {
+	store *storage.LocalBackend
+}
+
+// NewLocalStorageHandler creates a new LocalStorageHandler
+func NewLocalStorageHandler(store *storage.LocalBackend) *LocalStorageHandler {
+	return &LocalStorageHandler{store: store}
+}
+
+// ServeFile handles GET|HEAD /api/v2/media/files/*key?exp=&sig=
+// Range·If-Range·If-None-Match·If-Modified-Since 는 http.ServeContent 가 처리한다 (동영상 탐색, 이어받기).
+func (h *LocalStorageHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	expiresAt, err := h.store.Verify(key, c.Request.URL.Query())
+	switch {
+	case errors.Is(err, storage.ErrURLExpired):
+		c.AbortWithStatus(http.StatusGone)
+		return
+	case err != nil:
+		// 서명이 틀린 주소와 없는 파일을 구분해 주지 않는다
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	f, info, err := h.store.OpenFile(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	header := c.Writer.Header()
+	if expiresAt.IsZero() {
+		header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d, immutable", int(localFileMaxAge.Seconds())))
+	} else {
+		// 만료 주소는 공유 캐시에 남기지 않는다
+		header.Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(time.Until(expiresAt).Seconds())))
+	}
+	header.Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	header.Set("X-Content-Type-Options", "nosniff")
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		header.Set("Content-Type", ct)
+	} else {
+		header.Set("Content-Type", "application/octet-stream")
+	}
+
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime(), f)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLocalStorageServeFile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store, err := storage.NewLocalBackend(storage.LocalConfig{Root: t.TempDir(), PublicURL: "/files", SigningKey: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocalBackend: %v", err)
+	}
+	res, err := store.Upload(context.Background(), "videos/2026/10/17/clip.mp4", strings.NewReader("0123456789"), "video/mp4", 10)
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	r := gin.New()
+	h := NewLocalStorageHandler(store)
+	r.GET("/files/*key", h.ServeFile)
+	r.HEAD("/files/*key", h.ServeFile)
+
+	get := func(target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get(res.URL, nil)
+	if w.Code != http.StatusOK || w.Body.String() != "0123456789" || w.Header().Get("Content-Type") != "video/mp4" {
+		t.Fatalf("full GET: %d %q %q", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+	if cc := w.Header().Get("Cache-Control"); !strings.HasPrefix(cc, "public") || !strings.Contains(cc, "immutable") {
+		t.Fatalf("non-expiring URL should be publicly cacheable, got %q", cc)
+	}
+	etag := w.Header().Get("ETag")
+
+	w = get(res.URL, http.Header{"Range": {"bytes=2-5"}})
+	if w.Code != http.StatusPartialContent || w.Body.String() != "2345" || w.Header().Get("Content-Range") != "bytes 2-5/10" {
+		t.Fatalf("range GET: %d %q %q", w.Code, w.Body.String(), w.Header().Get("Content-Range"))
+	}
+	if w = get(res.URL, http.Header{"If-None-Match": {etag}}); w.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match: want 304, got %d", w.Code)
+	}
+
+	presigned, _ := store.GetPresignedURL(context.Background(), res.Key, time.Hour)
+	if w = get(presigned, nil); w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Cache-Control"), "private") {
+		t.Fatalf("presigned GET: %d %q", w.Code, w.Header().Get("Cache-Control"))
+	}
+	expired, _ := store.GetPresignedURL(context.Background(), res.Key, -time.Hour)
+	if w = get(expired, nil); w.Code != http.StatusGone {
+		t.Fatalf("expired URL: want 410, got %d", w.Code)
+	}
+
+	u, _ := url.Parse(res.URL)
+	if w = get(u.Path, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("unsigned URL: want 404, got %d", w.Code)
+	}
+	if w = get("/files/videos/2026/10/17/other.mp4?"+u.RawQuery, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("signature for another key: want 404, got %d", w.Code)
+	}
+}
@@ -12,11 +12,19 @@ import (
 // MediaHandler handles media upload/download endpoints
 type MediaHandler struct {
 	mediaService *service.MediaService
+	uploadPlan   string // 테넌트가 없는 요청의 요금제 (이어 올리기 크기 한도)
 }
 
 // NewMediaHandler creates a new MediaHandler
 func NewMediaHandler(mediaService *service.MediaService) *MediaHandler {
-	return &MediaHandler{mediaService: mediaService}
+	return &MediaHandler{mediaService: mediaService, uploadPlan: "business"}
+}
+
+// SetUploadPlan sets the plan whose MaxFileSize limits resumable uploads outside a tenant site
+func (h *MediaHandler) SetUploadPlan(plan string) {
+	if plan != "" {
+		h.uploadPlan = plan
+	}
 }
 
 // UploadImage handles editor image upload with optional resize
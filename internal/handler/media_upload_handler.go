@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// 이어 올리기는 tus 1.0.0 (https://tus.io/protocols/resumable-upload) 의 core·creation·expiration·termination 을 따른다.
+// tus 클라이언트는 endpoint 만 주면 만들기·PATCH·HEAD 를 그대로 쓸 수 있고, 끝나면 POST .../complete 를 한 번 더 부른다.
+// PATCH 한 번은 service.MaxUploadChunkSize 까지다 — 클라이언트 chunkSize 를 Tus-Max-Chunk-Size(비표준) 이하로 둔다.
+const (
+	tusVersion          = "1.0.0"
+	tusExtensions       = "creation,expiration,termination"
+	tusOffsetStreamType = "application/offset+octet-stream"
+	tusMaxChunkHeader   = "Tus-Max-Chunk-Size"
+)
+
+// uploadMaxFileSize returns PlanLimits.MaxFileSize in bytes — 테넌트 사이트면 그 요금제, 아니면 uploadPlan
+func (h *MediaHandler) uploadMaxFileSize(c *gin.Context) int64 {
+	plan := h.uploadPlan
+	if _, ok := c.Get("tenant_plan"); ok {
+		plan = middleware.GetTenantPlan(c)
+	}
+	return middleware.GetPlanLimits(plan).MaxFileSize * 1024 * 1024
+}
+
+// tusHeaders checks Tus-Resumable and sets the protocol headers. false 면 응답을 이미 썼다.
+func (h *MediaHandler) tusHeaders(c *gin.Context) bool {
+	c.Header("Tus-Resumable", tusVersion)
+	if v := c.GetHeader("Tus-Resumable"); v != "" && v != tusVersion {
+		c.Header("Tus-Version", tusVersion)
+		c.AbortWithStatus(http.StatusPreconditionFailed)
+		return false
+	}
+	if middleware.GetUsername(c) == "" {
+		common.ErrorResponse(c, http.StatusUnauthorized, "로그인이 필요합니다", nil)
+		return false
+	}
+	if !h.mediaService.ResumableUploads() {
+		common.ErrorResponse(c, http.StatusServiceUnavailable, "이어 올리기를 사용할 수 없습니다", nil)
+		return false
+	}
+	return true
+}
+
+// uploadError maps resumable upload errors to HTTP statuses
+func uploadError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	message := "업로드 처리 실패"
+	switch {
+	case errors.Is(err, service.ErrUploadNotFound):
+		status, message = http.StatusNotFound, "업로드를 찾을 수 없습니다"
+	case errors.Is(err, service.ErrUploadExpired):
+		status, message = http.StatusGone, "만료된 업로드입니다"
+	case errors.Is(err, service.ErrUploadOffsetMismatch), errors.Is(err, service.ErrUploadIncomplete):
+		status, message = http.StatusConflict, err.Error()
+	case errors.Is(err, service.ErrUploadTooLarge), errors.Is(err, service.ErrUploadChunkTooLarge):
+		status, message = http.StatusRequestEntityTooLarge, err.Error()
+	case errors.Is(err, service.ErrUploadQuotaExceeded):
+		status, message = http.StatusTooManyRequests, err.Error()
+	case errors.Is(err, service.ErrUploadRejected):
+		status, message = http.StatusBadRequest, err.Error()
+	}
+	if c.Request.Method == http.MethodHead {
+		c.AbortWithStatus(status)
+		return
+	}
+	common.ErrorResponse(c, status, message, nil)
+}
+
+// UploadOptions advertises the tus version, extensions and size limits
+// OPTIONS /api/v2/media/uploads (tus 규약상 Tus-Resumable·인증 없이 부른다)
+func (h *MediaHandler) UploadOptions(c *gin.Context) {
+	if !h.mediaService.ResumableUploads() {
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+		return
+	}
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	c.Header("Tus-Max-Size", strconv.FormatInt(h.uploadMaxFileSize(c), 10))
+	c.Header(tusMaxChunkHeader, strconv.Itoa(service.MaxUploadChunkSize))
+	c.Status(http.StatusNoContent)
+}
+
+// parseUploadMetadata parses the tus Upload-Metadata header ("filename d29ybGQ=,kind dmlkZW8=")
+func parseUploadMetadata(header string) map[string]string {
+	meta := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		meta[key] = string(decoded)
+	}
+	return meta
+}
+
+// CreateUpload starts a resumable upload
+// POST /api/v2/media/uploads (Upload-Length, Upload-Metadata: filename, kind=video|attachment)
+func (h *MediaHandler) CreateUpload(c *gin.Context) {
+	if !h.tusHeaders(c) {
+		return
+	}
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Upload-Length is required", nil)
+		return
+	}
+	meta := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	filename := meta["filename"]
+	if filename == "" {
+		filename = meta["name"] // tus-js-client 기본 메타데이터
+	}
+	kind := meta["kind"] // 비면 확장자로 정한다
+
+	maxSize := h.uploadMaxFileSize(c)
+	c.Header("Tus-Max-Size", strconv.FormatInt(maxSize, 10))
+	c.Header(tusMaxChunkHeader, strconv.Itoa(service.MaxUploadChunkSize))
+	upload, err := h.mediaService.CreateUpload(middleware.GetUsername(c), kind, filename, length, maxSize)
+	if err != nil {
+		uploadError(c, err)
+		return
+	}
+
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+upload.ID)
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": upload})
+}
+
+// UploadOffset reports how much of the upload the server has
+// HEAD /api/v2/media/uploads/:id
+func (h *MediaHandler) UploadOffset(c *gin.Context) {
+	if !h.tusHeaders(c) {
+		return
+	}
+	upload, err := h.mediaService.UploadStatus(middleware.GetUsername(c), c.Param("id"))
+	if err != nil {
+		uploadError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusOK)
+}
+
+// PatchUpload appends a chunk at Upload-Offset (한 번에 service.MaxUploadChunkSize 까지 — 넘으면 413, 받은 것은 버린다)
+// PATCH /api/v2/media/uploads/:id
+func (h *MediaHandler) PatchUpload(c *gin.Context) {
+	if !h.tusHeaders(c) {
+		return
+	}
+	if c.ContentType() != tusOffsetStreamType {
+		common.ErrorResponse(c, http.StatusUnsupportedMediaType, "Content-Type must be "+tusOffsetStreamType, nil)
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		common.ErrorResponse(c, http.StatusBadRequest, "Upload-Offset is required", nil)
+		return
+	}
+	c.Header(tusMaxChunkHeader, strconv.Itoa(service.MaxUploadChunkSize))
+	if c.Request.ContentLength > service.MaxUploadChunkSize {
+		uploadError(c, service.ErrUploadChunkTooLarge)
+		return
+	}
+
+	upload, err := h.mediaService.WriteUploadChunk(c.Request.Context(), middleware.GetUsername(c), c.Param("id"), offset, c.Request.Body)
+	if err != nil {
+		if upload != nil {
+			c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+		}
+		uploadError(c, err)
+		return
+	}
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusNoContent)
+}
+
+// CompleteUpload stores the received file and returns it like POST /videos·/attachments
+// POST /api/v2/media/uploads/:id/complete
+func (h *MediaHandler) CompleteUpload(c *gin.Context) {
+	if !h.tusHeaders(c) {
+		return
+	}
+	result, err := h.mediaService.CompleteUpload(c.Request.Context(), middleware.GetUsername(c), c.Param("id"))
+	if err != nil {
+		uploadError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+}
+
+// CancelUpload discards an upload and its chunks
+// DELETE /api/v2/media/uploads/:id
+func (h *MediaHandler) CancelUpload(c *gin.Context) {
+	if !h.tusHeaders(c) {
+		return
+	}
+	if err := h.mediaService.CancelUpload(c.Request.Context(), middleware.GetUsername(c), c.Param("id")); err != nil {
+		uploadError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
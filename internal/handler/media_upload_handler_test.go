@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/internal/service"
+	"github.com/damoang/angple-backend/pkg/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupUploadRouter(t *testing.T) (*gin.Engine, *gorm.DB, *service.MediaService, storage.Backend) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&v2domain.V2MediaUpload{}, &v2domain.V2MediaUploadPart{}, &v2domain.V2MediaObject{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	store, err := storage.NewLocalBackend(storage.LocalConfig{Root: t.TempDir(), PublicURL: "/files", SigningKey: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocalBackend: %v", err)
+	}
+	svc := service.NewMediaService(store)
+	svc.SetObjectRepository(v2repo.NewMediaObjectRepository(db))
+	svc.SetUploadRepository(v2repo.NewMediaUploadRepository(db))
+	h := NewMediaHandler(svc)
+	h.SetUploadPlan("free") // 5MB
+
+	r := gin.New()
+	r.OPTIONS("/api/v2/media/uploads", h.UploadOptions)
+	media := r.Group("/api/v2/media", func(c *gin.Context) { c.Set("username", "alice") })
+	media.POST("/uploads", h.CreateUpload)
+	media.HEAD("/uploads/:id", h.UploadOffset)
+	media.PATCH("/uploads/:id", h.PatchUpload)
+	media.POST("/uploads/:id/complete", h.CompleteUpload)
+	media.DELETE("/uploads/:id", h.CancelUpload)
+	return r, db, svc, store
+}
+
+func tusRequest(r *gin.Engine, method, target, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Tus-Resumable", "1.0.0")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func createTusUpload(t *testing.T, r *gin.Engine, filename string, length int) string {
+	t.Helper()
+	meta := "filename " + base64.StdEncoding.EncodeToString([]byte(filename))
+	w := tusRequest(r, http.MethodPost, "/api/v2/media/uploads", "", map[string]string{
+		"Upload-Length": fmt.Sprint(length), "Upload-Metadata": meta,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: %d %s", w.Code, w.Body.String())
+	}
+	return w.Header().Get("Location")
+}
+
+func patchChunk(r *gin.Engine, location string, offset int, chunk string) *httptest.ResponseRecorder {
+	return tusRequest(r, http.MethodPatch, location, chunk, map[string]string{
+		"Content-Type": "application/offset+octet-stream", "Upload-Offset": fmt.Sprint(offset),
+	})
+}
+
+func TestResumableUpload(t *testing.T) {
+	r, _, _, store := setupUploadRouter(t)
+	content := "%PDF-1.4\n" + strings.Repeat("resumable ", 100)
+	location := createTusUpload(t, r, "report.pdf", len(content))
+	if !strings.HasPrefix(location, "/api/v2/media/uploads/") {
+		t.Fatalf("unexpected Location %q", location)
+	}
+
+	half := len(content) / 2
+	if w := patchChunk(r, location, 0, content[:half]); w.Code != http.StatusNoContent || w.Header().Get("Upload-Offset") != fmt.Sprint(half) {
+		t.Fatalf("first chunk: %d offset=%s", w.Code, w.Header().Get("Upload-Offset"))
+	}
+	// 끊긴 뒤 같은 조각을 다시 보내면 409 와 서버 offset
+	if w := patchChunk(r, location, 0, content[:half]); w.Code != http.StatusConflict || w.Header().Get("Upload-Offset") != fmt.Sprint(half) {
+		t.Fatalf("stale offset: %d offset=%s", w.Code, w.Header().Get("Upload-Offset"))
+	}
+	if w := tusRequest(r, http.MethodPost, location+"/complete", "", nil); w.Code != http.StatusConflict {
+		t.Fatalf("complete before the last chunk: %d", w.Code)
+	}
+	if w := tusRequest(r, http.MethodHead, location, "", nil); w.Code != http.StatusOK || w.Header().Get("Upload-Offset") != fmt.Sprint(half) {
+		t.Fatalf("head: %d offset=%s", w.Code, w.Header().Get("Upload-Offset"))
+	}
+	if w := patchChunk(r, location, half, content[half:]); w.Code != http.StatusNoContent {
+		t.Fatalf("last chunk: %d %s", w.Code, w.Body.String())
+	}
+
+	w := tusRequest(r, http.MethodPost, location+"/complete", "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("complete: %d %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data service.MediaUploadResult `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.ContentType != "application/pdf" || resp.Data.Size != int64(len(content)) {
+		t.Fatalf("unexpected result %+v", resp.Data)
+	}
+	obj, err := store.Open(context.Background(), resp.Data.Key)
+	if err != nil {
+		t.Fatalf("open stored file: %v", err)
+	}
+	defer obj.Body.Close()
+	if got, err := io.ReadAll(obj.Body); err != nil || string(got) != content {
+		t.Fatal("stored file differs from the uploaded chunks")
+	}
+
+	// 응답을 놓친 클라이언트가 다시 불러도 같은 결과
+	if w := tusRequest(r, http.MethodPost, location+"/complete", "", nil); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), resp.Data.Key) {
+		t.Fatalf("repeat complete: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestResumableUploadLimits(t *testing.T) {
+	r, db, svc, _ := setupUploadRouter(t)
+
+	meta := "filename " + base64.StdEncoding.EncodeToString([]byte("big.mp4"))
+	w := tusRequest(r, http.MethodPost, "/api/v2/media/uploads", "", map[string]string{
+		"Upload-Length": fmt.Sprint(6 * 1024 * 1024), "Upload-Metadata": meta,
+	})
+	if w.Code != http.StatusRequestEntityTooLarge || w.Header().Get("Tus-Max-Size") != fmt.Sprint(5*1024*1024) {
+		t.Fatalf("free plan limit: %d max=%s", w.Code, w.Header().Get("Tus-Max-Size"))
+	}
+	if w := tusRequest(r, http.MethodPost, "/api/v2/media/uploads", "", map[string]string{"Tus-Resumable": "0.2.2", "Upload-Length": "1"}); w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("tus version: %d", w.Code)
+	}
+
+	// tus 서버 정보 조회 (Tus-Resumable 없이)
+	req := httptest.NewRequest(http.MethodOptions, "/api/v2/media/uploads", nil)
+	opts := httptest.NewRecorder()
+	r.ServeHTTP(opts, req)
+	if opts.Code != http.StatusNoContent || opts.Header().Get("Tus-Version") != "1.0.0" ||
+		opts.Header().Get("Tus-Extension") != "creation,expiration,termination" ||
+		opts.Header().Get("Tus-Max-Size") != fmt.Sprint(5*1024*1024) ||
+		opts.Header().Get("Tus-Max-Chunk-Size") != fmt.Sprint(service.MaxUploadChunkSize) {
+		t.Fatalf("options: %d %v", opts.Code, opts.Header())
+	}
+
+	// 조각이 한도나 선언한 길이를 넘으면 잘라 받지 않고 413 — offset 은 그대로다
+	chunked := createTusUpload(t, r, "notes.pdf", 8)
+	if w := patchChunk(r, chunked, 0, "%PDF-1.4 and more"); w.Code != http.StatusRequestEntityTooLarge || w.Header().Get("Upload-Offset") != "0" {
+		t.Fatalf("chunk past Upload-Length: %d offset=%s", w.Code, w.Header().Get("Upload-Offset"))
+	}
+	req = httptest.NewRequest(http.MethodPatch, chunked, strings.NewReader("%PDF"))
+	req.Header.Set("Tus-Resumable", "1.0.0")
+	req.Header.Set("Content-Type", "application/offset+octet-stream")
+	req.Header.Set("Upload-Offset", "0")
+	req.ContentLength = service.MaxUploadChunkSize + 1
+	big := httptest.NewRecorder()
+	r.ServeHTTP(big, req)
+	if big.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("chunk over Tus-Max-Chunk-Size: %d", big.Code)
+	}
+	if w := tusRequest(r, http.MethodDelete, chunked, "", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("cancel: %d", w.Code)
+	}
+
+	// 확장자만 .pdf 인 HTML 은 완료할 때 거부한다
+	html := "<!DOCTYPE html><html><script>alert(1)</script></html>"
+	location := createTusUpload(t, r, "fake.pdf", len(html))
+	if w := patchChunk(r, location, 0, html); w.Code != http.StatusNoContent {
+		t.Fatalf("patch: %d", w.Code)
+	}
+	if w := tusRequest(r, http.MethodPost, location+"/complete", "", nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("sniffed html: %d %s", w.Code, w.Body.String())
+	}
+
+	// 버려진 업로드는 만료 후 조각과 함께 정리된다
+	abandoned := createTusUpload(t, r, "clip.pdf", 100)
+	if w := patchChunk(r, abandoned, 0, "%PDF-1.4"); w.Code != http.StatusNoContent {
+		t.Fatalf("patch: %d", w.Code)
+	}
+	db.Model(&v2domain.V2MediaUpload{}).Where("1 = 1").Update("expires_at", time.Now().Add(-time.Minute))
+	if w := tusRequest(r, http.MethodHead, abandoned, "", nil); w.Code != http.StatusGone {
+		t.Fatalf("expired head: %d", w.Code)
+	}
+	result, err := svc.CleanupUploads(context.Background())
+	if err != nil || result.Expired != 1 || result.Parts != 1 {
+		t.Fatalf("cleanup: %+v %v", result, err)
+	}
+	var parts int64
+	db.Model(&v2domain.V2MediaUploadPart{}).Count(&parts)
+	if parts != 0 {
+		t.Fatalf("%d part rows left", parts)
+	}
+}
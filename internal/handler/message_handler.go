@@ -5,6 +5,7 @@ import (
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/damoang/angple-backend/internal/common"
@@ -24,6 +25,12 @@ type V1MessageHandler struct {
 	// uiRepo 는 web 소유 UI 설정을 **읽기 전용**으로 본다(쪽지 수신 거부, bug/13664).
 	// nil 이면 게이트가 없는 것과 같다 — 주입 안 된 환경에서도 쪽지는 정상 동작한다.
 	uiRepo gnurepo.MemberUISettingsRepository
+	// threadRepo 는 대화방(상대별 묶음)·보관/알림 끄기·이미지 첨부를 맡는다 (nil 이면 대화방 API 를 쓸 수 없다)
+	threadRepo gnurepo.MemoThreadRepository
+	// media 는 첨부 이미지 업로드 (S3 가 없으면 nil — 첨부만 막힌다)
+	media memoImageUploader
+	// events 는 읽음 확인을 보낸 사람의 WebSocket 으로 알린다 (nil 이면 생략)
+	events memberEventSender
 }
 
 // NewV1MessageHandler creates a new V1MessageHandler using g5_memo
@@ -37,6 +44,21 @@ func (h *V1MessageHandler) SetUISettingsRepo(r gnurepo.MemberUISettingsRepositor
 	h.uiRepo = r
 }
 
+// SetThreadRepo enables the conversation view (/api/v1/messages/threads) and image attachments
+func (h *V1MessageHandler) SetThreadRepo(r gnurepo.MemoThreadRepository) {
+	h.threadRepo = r
+}
+
+// SetMediaService enables image attachment uploads (service.MediaService)
+func (h *V1MessageHandler) SetMediaService(m memoImageUploader) {
+	h.media = m
+}
+
+// SetEventSender enables realtime read receipts (ws.Hub)
+func (h *V1MessageHandler) SetEventSender(events memberEventSender) {
+	h.events = events
+}
+
 // v1MessageResponse matches frontend Message type
 type v1MessageResponse struct {
 	ID           int     `json:"id"`
@@ -48,6 +70,8 @@ type v1MessageResponse struct {
 	IsRead       bool    `json:"is_read"`
 	ReadDatetime *string `json:"read_datetime,omitempty"`
 	SendDatetime string  `json:"send_datetime"`
+	// Attachments 는 쪽지에 붙은 이미지다 (대화방 API 가 켜져 있을 때만)
+	Attachments []gnuboard.MemoAttachment `json:"attachments,omitempty"`
 }
 
 // v1MessageListResponse matches frontend MessageListResponse type
@@ -145,9 +169,12 @@ func (h *V1MessageHandler) GetMessages(c *gin.Context) {
 		nickMap = make(map[string]string)
 	}
 
+	attachments := h.attachmentsFor(memos)
 	items := make([]v1MessageResponse, 0, len(memos))
 	for _, memo := range memos {
-		items = append(items, h.toV1Message(memo, nickMap))
+		item := h.toV1Message(memo, nickMap)
+		item.Attachments = attachments[memo.PairKey()]
+		items = append(items, item)
 	}
 
 	totalPages := int64(math.Ceil(float64(total) / float64(limit)))
@@ -184,7 +211,9 @@ func (h *V1MessageHandler) GetMessage(c *gin.Context) {
 
 	// Mark as read if it's a received memo and not yet read
 	if memo.MeRecvMbID == mbID && memo.MeType == "recv" && !memo.IsRead() {
-		_ = h.memoRepo.MarkAsRead(memo.MeID)
+		if err := h.memoRepo.MarkAsRead(memo.MeID); err == nil {
+			h.sendReadReceipt(memo.MeSendMbID, mbID)
+		}
 	}
 
 	nickMap, _ := h.memberRepo.FindNicksByIDs([]string{memo.MeSendMbID, memo.MeRecvMbID})
@@ -192,7 +221,9 @@ func (h *V1MessageHandler) GetMessage(c *gin.Context) {
 		nickMap = make(map[string]string)
 	}
 
-	common.V2Success(c, h.toV1Message(memo, nickMap))
+	resp := h.toV1Message(memo, nickMap)
+	resp.Attachments = h.attachmentsFor([]*gnuboard.G5Memo{memo})[memo.PairKey()]
+	common.V2Success(c, resp)
 }
 
 // SendMessage handles POST /api/v1/messages
@@ -205,12 +236,34 @@ func (h *V1MessageHandler) SendMessage(c *gin.Context) {
 
 	var req struct {
 		ReceiverID string `json:"receiver_id" binding:"required"`
-		Content    string `json:"content" binding:"required"`
+		Content    string `json:"content"`
+		// AttachmentIDs 는 POST /api/v1/messages/attachments 로 미리 올린 이미지다
+		AttachmentIDs []int64 `json:"attachment_ids"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.V2ErrorResponse(c, http.StatusBadRequest, "요청 형식이 올바르지 않습니다", err)
 		return
 	}
+	// 이미지만 보내는 쪽지는 본문이 비어도 된다
+	if strings.TrimSpace(req.Content) == "" && len(req.AttachmentIDs) == 0 {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "내용을 입력해 주세요", nil)
+		return
+	}
+	if len(req.AttachmentIDs) > 0 {
+		if h.threadRepo == nil {
+			common.V2ErrorResponse(c, http.StatusServiceUnavailable, "쪽지 첨부를 사용할 수 없습니다", nil)
+			return
+		}
+		if len(req.AttachmentIDs) > memoMaxAttachments {
+			common.V2ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("이미지는 %d장까지 첨부할 수 있습니다", memoMaxAttachments), nil)
+			return
+		}
+		// 내가 올렸고 아직 보내지 않은 업로드만 붙일 수 있다
+		if n, err := h.threadRepo.CountPending(mbID, req.AttachmentIDs); err != nil || n != int64(len(req.AttachmentIDs)) {
+			common.V2ErrorResponse(c, http.StatusBadRequest, "첨부 이미지를 찾을 수 없습니다", err)
+			return
+		}
+	}
 
 	// Block messages to admin account
 	if req.ReceiverID == adminMemberID {
@@ -279,6 +332,14 @@ func (h *V1MessageHandler) SendMessage(c *gin.Context) {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "쪽지 보내기 실패", err)
 		return
 	}
+	var attachments []gnuboard.MemoAttachment
+	if len(req.AttachmentIDs) > 0 {
+		if err := h.threadRepo.AttachPending(mbID, req.AttachmentIDs, memo.PairKey()); err != nil {
+			common.V2ErrorResponse(c, http.StatusInternalServerError, "첨부 이미지 연결 실패", err)
+			return
+		}
+		attachments = h.attachmentsFor([]*gnuboard.G5Memo{memo})[memo.PairKey()]
+	}
 
 	nickMap, _ := h.memberRepo.FindNicksByIDs([]string{mbID, req.ReceiverID})
 	if nickMap == nil {
@@ -294,6 +355,13 @@ func (h *V1MessageHandler) SendMessage(c *gin.Context) {
 		if exists, _ := h.notiRepo.Exists(req.ReceiverID, "", 0, "memo", mbID); exists {
 			return
 		}
+		// 받는 사람이 이 대화방 알림을 껐으면 읽음으로만 남긴다 — 실시간·푸시가 울리지 않는다
+		readed := "N"
+		if h.threadRepo != nil {
+			if state, err := h.threadRepo.State(req.ReceiverID, mbID); err == nil && state.Muted {
+				readed = "Y"
+			}
+		}
 		_ = h.notiRepo.Create(&gnurepo.Notification{
 			PhToCase:   "memo",
 			PhFromCase: "memo",
@@ -302,12 +370,14 @@ func (h *V1MessageHandler) SendMessage(c *gin.Context) {
 			RelMbNick:  senderNick,
 			RelMsg:     fmt.Sprintf("%s님이 쪽지를 보냈습니다.", senderNick),
 			RelURL:     "/messages",
-			PhReaded:   "N",
+			PhReaded:   readed,
 			PhDatetime: time.Now(),
 		})
 	}()
 
-	common.V2Created(c, h.toV1Message(memo, nickMap))
+	resp := h.toV1Message(memo, nickMap)
+	resp.Attachments = attachments
+	common.V2Created(c, resp)
 }
 
 // DeleteMessage handles DELETE /api/v1/messages/:id
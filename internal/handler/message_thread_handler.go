@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/domain/gnuboard"
+	"github.com/damoang/angple-backend/internal/service"
+	"github.com/damoang/angple-backend/internal/ws"
+	"github.com/gin-gonic/gin"
+)
+
+// memoMaxAttachments 는 쪽지 하나에 붙일 수 있는 이미지 수다
+const memoMaxAttachments = 10
+
+// memoImageMaxWidth 는 첨부 이미지를 줄일 폭이다 (에디터 업로드와 같다)
+const memoImageMaxWidth = 1920
+
+// memoImageUploader uploads one image (service.MediaService)
+type memoImageUploader interface {
+	UploadImage(ctx context.Context, file *multipart.FileHeader, maxWidth int) (*service.MediaUploadResult, error)
+}
+
+// memberEventSender delivers an event to one member's connections (ws.Hub)
+type memberEventSender interface {
+	SendToMember(memberID string, event *ws.Event)
+}
+
+// memoThreadMessage is one message in the conversation view
+type memoThreadMessage struct {
+	ID           int    `json:"id"`
+	IsMine       bool   `json:"is_mine"`
+	Content      string `json:"content"`
+	SendDatetime string `json:"send_datetime"`
+	// IsRead·ReadDatetime — 내가 보낸 쪽지는 상대가 읽었는지(읽음 확인), 받은 쪽지는 내가 읽었는지다
+	IsRead       bool                      `json:"is_read"`
+	ReadDatetime *string                   `json:"read_datetime,omitempty"`
+	Attachments  []gnuboard.MemoAttachment `json:"attachments,omitempty"`
+}
+
+// threadPage parses ?cursor=&limit= — cursor 는 이전 페이지 next_cursor (id, 그보다 오래된 것부터)
+func threadPage(c *gin.Context) (before, limit int) {
+	before, _ = strconv.Atoi(c.Query("cursor"))
+	limit, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return max(before, 0), limit
+}
+
+// cursorJSON writes a cursor-paginated list (GET /api/v2/feed 와 같은 모양)
+func cursorJSON(c *gin.Context, data any, nextCursor int, hasMore bool) {
+	next := ""
+	if hasMore {
+		next = strconv.Itoa(nextCursor)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+		"meta":    gin.H{"next_cursor": next, "has_more": hasMore},
+	})
+}
+
+// threadReady checks login and that the conversation view is wired
+func (h *V1MessageHandler) threadReady(c *gin.Context) (string, bool) {
+	mbID := h.getMbID(c)
+	if mbID == "" {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증이 필요합니다", nil)
+		return "", false
+	}
+	if h.threadRepo == nil {
+		common.V2ErrorResponse(c, http.StatusServiceUnavailable, "대화방 보기를 사용할 수 없습니다", nil)
+		return "", false
+	}
+	return mbID, true
+}
+
+// GetThreads handles GET /api/v1/messages/threads?archived=1&cursor=&limit=
+// 상대별 대화방 목록 — 마지막 쪽지가 새로운 순. 보관한 대화방은 새 쪽지가 오면 다시 올라온다.
+func (h *V1MessageHandler) GetThreads(c *gin.Context) {
+	mbID, ok := h.threadReady(c)
+	if !ok {
+		return
+	}
+	before, limit := threadPage(c)
+	archived := c.Query("archived") == "1" || c.Query("archived") == "true"
+
+	threads, err := h.threadRepo.Threads(mbID, archived, before, limit+1)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "대화방 목록 조회 실패", err)
+		return
+	}
+	hasMore := len(threads) > limit
+	if hasMore {
+		threads = threads[:limit]
+	}
+
+	peers := make([]string, 0, len(threads))
+	for _, t := range threads {
+		peers = append(peers, t.PeerMbID)
+	}
+	nickMap, _ := h.memberRepo.FindNicksByIDs(peers)
+	var blocked []string
+	if h.blockRepo != nil {
+		blocked, _ = h.blockRepo.GetBlockedUserIDs(mbID)
+	}
+	next := 0
+	for _, t := range threads {
+		t.PeerNick = nickMap[t.PeerMbID]
+		if t.PeerNick == "" {
+			t.PeerNick = t.PeerMbID
+		}
+		t.IsBlocked = slices.Contains(blocked, t.PeerMbID)
+		next = t.LastMeID
+	}
+	if threads == nil {
+		threads = []*gnuboard.MemoThread{}
+	}
+	cursorJSON(c, threads, next, hasMore)
+}
+
+// GetThread handles GET /api/v1/messages/threads/:peer?cursor=&limit= — 대화 내용, 새 쪽지부터.
+// 읽음 처리는 하지 않는다 (POST /threads/:peer/read).
+func (h *V1MessageHandler) GetThread(c *gin.Context) {
+	mbID, ok := h.threadReady(c)
+	if !ok {
+		return
+	}
+	peer := c.Param("peer")
+	before, limit := threadPage(c)
+
+	memos, err := h.threadRepo.Messages(mbID, peer, before, limit+1)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "대화 내용 조회 실패", err)
+		return
+	}
+	hasMore := len(memos) > limit
+	if hasMore {
+		memos = memos[:limit]
+	}
+
+	// 내가 보낸 쪽지의 읽음 확인은 상대 쪽(recv) 행에 있다
+	var sentPairs []int
+	for _, m := range memos {
+		if m.MeType == "send" {
+			sentPairs = append(sentPairs, m.PairKey())
+		}
+	}
+	receipts, err := h.threadRepo.ReadTimes(sentPairs)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "읽음 확인 조회 실패", err)
+		return
+	}
+	attachments := h.attachmentsFor(memos)
+
+	items := make([]memoThreadMessage, 0, len(memos))
+	next := 0
+	for _, m := range memos {
+		item := memoThreadMessage{
+			ID:           m.MeID,
+			IsMine:       m.MeType == "send",
+			Content:      m.MeMemo,
+			SendDatetime: m.MeSendDatetime.Format("2006-01-02 15:04:05"),
+			Attachments:  attachments[m.PairKey()],
+		}
+		if item.IsMine {
+			if readAt, ok := receipts[m.PairKey()]; ok {
+				item.IsRead, item.ReadDatetime = true, &readAt
+			}
+		} else if m.IsRead() {
+			readAt := m.MeReadDatetime
+			item.IsRead, item.ReadDatetime = true, &readAt
+		}
+		items = append(items, item)
+		next = m.MeID
+	}
+	cursorJSON(c, items, next, hasMore)
+}
+
+// MarkThreadRead handles POST /api/v1/messages/threads/:peer/read — 상대에게 받은 쪽지를 모두 읽음 처리
+func (h *V1MessageHandler) MarkThreadRead(c *gin.Context) {
+	mbID, ok := h.threadReady(c)
+	if !ok {
+		return
+	}
+	peer := c.Param("peer")
+	updated, err := h.threadRepo.MarkThreadRead(mbID, peer)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "대화방 읽음 처리 실패", err)
+		return
+	}
+	if updated > 0 {
+		h.sendReadReceipt(peer, mbID)
+	}
+	common.V2Success(c, gin.H{"updated": updated})
+}
+
+// ArchiveThread handles POST /api/v1/messages/threads/:peer/archive (DELETE 는 보관 해제)
+func (h *V1MessageHandler) ArchiveThread(c *gin.Context) {
+	mbID, ok := h.threadReady(c)
+	if !ok {
+		return
+	}
+	peer := c.Param("peer")
+	archivedMeID := 0
+	if c.Request.Method == http.MethodPost {
+		// 지금까지의 마지막 쪽지까지 보관한다 — 새 쪽지가 오면 목록으로 돌아온다
+		last, err := h.threadRepo.Messages(mbID, peer, 0, 1)
+		if err != nil {
+			common.V2ErrorResponse(c, http.StatusInternalServerError, "대화방 조회 실패", err)
+			return
+		}
+		if len(last) == 0 {
+			common.V2ErrorResponse(c, http.StatusNotFound, "대화방을 찾을 수 없습니다", nil)
+			return
+		}
+		archivedMeID = last[0].MeID
+	}
+	if err := h.threadRepo.SetArchived(mbID, peer, archivedMeID); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "대화방 보관 실패", err)
+		return
+	}
+	common.V2Success(c, gin.H{"peer_id": peer, "archived": archivedMeID > 0})
+}
+
+// MuteThread handles POST /api/v1/messages/threads/:peer/mute (DELETE 는 해제).
+// 알림 끄기는 쪽지 알림을 읽음으로만 남긴다 — 쪽지 자체는 그대로 받는다 (막으려면 차단).
+func (h *V1MessageHandler) MuteThread(c *gin.Context) {
+	mbID, ok := h.threadReady(c)
+	if !ok {
+		return
+	}
+	peer := c.Param("peer")
+	muted := c.Request.Method == http.MethodPost
+	if err := h.threadRepo.SetMuted(mbID, peer, muted); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "대화방 알림 설정 실패", err)
+		return
+	}
+	common.V2Success(c, gin.H{"peer_id": peer, "muted": muted})
+}
+
+// UploadAttachment handles POST /api/v1/messages/attachments (multipart "file", 이미지만).
+// 돌려준 id 를 쪽지 보내기의 attachment_ids 로 넘기면 그 쪽지에 붙는다.
+func (h *V1MessageHandler) UploadAttachment(c *gin.Context) {
+	mbID, ok := h.threadReady(c)
+	if !ok {
+		return
+	}
+	if h.media == nil {
+		common.V2ErrorResponse(c, http.StatusServiceUnavailable, "쪽지 첨부를 사용할 수 없습니다", nil)
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "파일이 필요합니다", err)
+		return
+	}
+	result, err := h.media.UploadImage(c.Request.Context(), file, memoImageMaxWidth)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	url := result.CDNURL
+	if url == "" {
+		url = result.URL
+	}
+	attachment := &gnuboard.MemoAttachment{
+		MbID:        mbID,
+		FileKey:     result.Key,
+		URL:         url,
+		Filename:    result.Filename,
+		ContentType: result.ContentType,
+		Size:        result.Size,
+		Width:       result.Width,
+		Height:      result.Height,
+	}
+	if err := h.threadRepo.CreateAttachment(attachment); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "첨부 저장 실패", err)
+		return
+	}
+	common.V2Created(c, attachment)
+}
+
+// attachmentsFor loads attachments keyed by G5Memo.PairKey (대화방 API 가 꺼져 있으면 빈 값)
+func (h *V1MessageHandler) attachmentsFor(memos []*gnuboard.G5Memo) map[int][]gnuboard.MemoAttachment {
+	if h.threadRepo == nil || len(memos) == 0 {
+		return nil
+	}
+	keys := make([]int, 0, len(memos))
+	for _, m := range memos {
+		keys = append(keys, m.PairKey())
+	}
+	attachments, err := h.threadRepo.AttachmentsFor(keys)
+	if err != nil {
+		return nil
+	}
+	return attachments
+}
+
+// sendReadReceipt tells the sender that reader opened their messages (memo_read 이벤트)
+func (h *V1MessageHandler) sendReadReceipt(sender, reader string) {
+	if h.events == nil || sender == "" {
+		return
+	}
+	h.events.SendToMember(sender, &ws.Event{
+		Type:    "memo_read",
+		Payload: gin.H{"peer_id": reader, "read_datetime": time.Now().Format("2006-01-02 15:04:05")},
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
@@ -19,6 +20,8 @@ import (
 type NotiHandler struct {
 	repo     gnurepo.NotiRepository
 	prefRepo gnurepo.NotiPreferenceRepository
+	// onBroadcast 는 방송 발송 직후 접속 중인 회원에게 알린다 (nil 이면 생략)
+	onBroadcast func(title, body, url string)
 }
 
 // NewNotiHandler creates a new NotiHandler
@@ -26,6 +29,12 @@ func NewNotiHandler(repo gnurepo.NotiRepository, prefRepo gnurepo.NotiPreference
 	return &NotiHandler{repo: repo, prefRepo: prefRepo}
 }
 
+// SetBroadcastNotifier sets the realtime fan-out for new broadcasts (worker.NotiRealtimeWorker.Broadcast).
+// 방송은 회원별 g5_na_noti 행이 없어 삽입 훅을 지나지 않는다.
+func (h *NotiHandler) SetBroadcastNotifier(fn func(title, body, url string)) {
+	h.onBroadcast = fn
+}
+
 // v1NotificationResponse matches frontend Notification type
 type v1NotificationResponse struct {
 	ID            int    `json:"id"`
@@ -154,6 +163,17 @@ func NotificationTitle(fromCase, toCase, relMbNick string, wrID, wrParent int) s
 	return generateTitle(fromCase, toCase, relMbNick, wrID, wrParent)
 }
 
+// NotificationPayload renders a row exactly like an item of GET /api/v1/notifications —
+// WebSocket notification 이벤트가 같은 모양을 쓰도록(클라이언트가 목록에 그대로 끼워 넣는다).
+func NotificationPayload(n gnurepo.Notification) interface{} {
+	return toV1Notification(n)
+}
+
+// NotificationURL returns the web link of a notification (목록 항목의 url 과 같다 — Web Push 클릭 이동용)
+func NotificationURL(n gnurepo.Notification) string {
+	return convertLegacyURL(n.RelURL)
+}
+
 // convertLegacyURL converts Gnuboard PHP URLs to SvelteKit URLs
 // /bbs/board.php?bo_table=free&wr_id=123#c_456 → /free/123#c_456
 func convertLegacyURL(rawURL string) string {
@@ -245,6 +265,9 @@ func (h *NotiHandler) CreateBroadcast(c *gin.Context) {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "방송 발송 실패", err)
 		return
 	}
+	if h.onBroadcast != nil {
+		h.onBroadcast(req.Title, req.Body, req.URL)
+	}
 	common.V2Success(c, gin.H{"message": "방송을 발송했습니다"})
 }
 
@@ -789,6 +812,12 @@ type notiPreferenceResponse struct {
 	NotiFollow         bool `json:"noti_follow"`
 	NotiBoardSubscribe bool `json:"noti_board_subscribe"`
 	LikeThreshold      int  `json:"like_threshold"`
+	// 내가 팔로우하는 회원의 멘션만 울린다
+	MentionFollowingOnly bool `json:"mention_following_only"`
+	// 방해 금지 시간 "HH:MM" — 둘 다 빈 문자열이면 끔. 이 동안은 푸시만 보내지 않는다.
+	QuietStart string `json:"quiet_start"`
+	QuietEnd   string `json:"quiet_end"`
+	TimeZone   string `json:"time_zone"`
 }
 
 func toNotiPreferenceResponse(pref *gnurepo.NotiPreference) notiPreferenceResponse {
@@ -800,6 +829,11 @@ func toNotiPreferenceResponse(pref *gnurepo.NotiPreference) notiPreferenceRespon
 		NotiFollow:         pref.NotiFollow,
 		NotiBoardSubscribe: pref.NotiBoardSubscribe,
 		LikeThreshold:      pref.LikeThreshold,
+
+		MentionFollowingOnly: pref.MentionFollowingOnly,
+		QuietStart:           pref.QuietStart,
+		QuietEnd:             pref.QuietEnd,
+		TimeZone:             pref.TimeZone,
 	}
 }
 
@@ -836,6 +870,11 @@ func (h *NotiHandler) UpdatePreferences(c *gin.Context) {
 		NotiFollow         *bool `json:"noti_follow"`
 		NotiBoardSubscribe *bool `json:"noti_board_subscribe"`
 		LikeThreshold      *int  `json:"like_threshold"`
+
+		MentionFollowingOnly *bool   `json:"mention_following_only"`
+		QuietStart           *string `json:"quiet_start"`
+		QuietEnd             *string `json:"quiet_end"`
+		TimeZone             *string `json:"time_zone"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 요청", err)
@@ -872,6 +911,22 @@ func (h *NotiHandler) UpdatePreferences(c *gin.Context) {
 		}
 		pref.LikeThreshold = *req.LikeThreshold
 	}
+	if req.MentionFollowingOnly != nil {
+		pref.MentionFollowingOnly = *req.MentionFollowingOnly
+	}
+	if req.QuietStart != nil {
+		pref.QuietStart = *req.QuietStart
+	}
+	if req.QuietEnd != nil {
+		pref.QuietEnd = *req.QuietEnd
+	}
+	if req.TimeZone != nil {
+		pref.TimeZone = *req.TimeZone
+	}
+	if err := validateQuietHours(pref); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
 
 	if err := h.prefRepo.Upsert(pref); err != nil {
 		common.V2ErrorResponse(c, http.StatusInternalServerError, "알림 설정 저장 실패", err)
@@ -881,6 +936,26 @@ func (h *NotiHandler) UpdatePreferences(c *gin.Context) {
 	common.V2Success(c, toNotiPreferenceResponse(pref))
 }
 
+// validateQuietHours checks the quiet hours pair and time zone (둘 다 비우면 끔)
+func validateQuietHours(pref *gnurepo.NotiPreference) error {
+	if pref.TimeZone == "" {
+		pref.TimeZone = gnurepo.DefaultNotiTimeZone
+	}
+	if _, err := time.LoadLocation(pref.TimeZone); err != nil {
+		return fmt.Errorf("알 수 없는 시간대: %s", pref.TimeZone)
+	}
+	if pref.QuietStart == "" && pref.QuietEnd == "" {
+		return nil
+	}
+	if _, err := gnurepo.ParseQuietClock(pref.QuietStart); err != nil {
+		return errors.New("quiet_start 는 HH:MM 이어야 합니다")
+	}
+	if _, err := gnurepo.ParseQuietClock(pref.QuietEnd); err != nil {
+		return errors.New("quiet_end 는 HH:MM 이어야 합니다")
+	}
+	return nil
+}
+
 // DeleteGroup handles DELETE /api/v1/notifications/group
 func (h *NotiHandler) DeleteGroup(c *gin.Context) {
 	mbID := middleware.GetUserID(c)
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/middleware"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"github.com/gin-gonic/gin"
+)
+
+// NotiRuleHandler handles per-thread and per-board notification rules (g5_noti_rule)
+type NotiRuleHandler struct {
+	repo gnurepo.NotiRuleRepository
+}
+
+// NewNotiRuleHandler creates a new NotiRuleHandler
+func NewNotiRuleHandler(repo gnurepo.NotiRuleRepository) *NotiRuleHandler {
+	return &NotiRuleHandler{repo: repo}
+}
+
+// List handles GET /api/v1/notifications/rules
+func (h *NotiRuleHandler) List(c *gin.Context) {
+	mbID := middleware.GetUserID(c)
+	if mbID == "" {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증이 필요합니다", nil)
+		return
+	}
+	rules, err := h.repo.List(mbID)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "알림 규칙 조회 실패", err)
+		return
+	}
+	common.V2Success(c, rules)
+}
+
+// Set handles PUT /api/v1/notifications/rules
+// 글: {"bo_table":"free","wr_id":123,"mode":"mute|follow"}, 게시판: {"bo_table":"free","mode":"mute|on"}
+func (h *NotiRuleHandler) Set(c *gin.Context) {
+	mbID := middleware.GetUserID(c)
+	if mbID == "" {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증이 필요합니다", nil)
+		return
+	}
+	var req struct {
+		BoTable string `json:"bo_table" binding:"required"`
+		WrID    int    `json:"wr_id"`
+		Mode    string `json:"mode" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 요청", err)
+		return
+	}
+	if !middleware.BoardSlugRegex.MatchString(req.BoTable) || req.WrID < 0 {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 게시판 또는 글", nil)
+		return
+	}
+	if !gnurepo.ValidNotiRuleMode(req.WrID, req.Mode) {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "알 수 없는 규칙: "+req.Mode, nil)
+		return
+	}
+	rule := &gnurepo.NotiRule{MbID: mbID, BoTable: req.BoTable, WrID: req.WrID, Mode: req.Mode}
+	if err := h.repo.Set(rule); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "알림 규칙 저장 실패", err)
+		return
+	}
+	common.V2Success(c, rule)
+}
+
+// Delete handles DELETE /api/v1/notifications/rules?bo_table=&wr_id= — wr_id 가 없으면 게시판 규칙을 지운다
+func (h *NotiRuleHandler) Delete(c *gin.Context) {
+	mbID := middleware.GetUserID(c)
+	if mbID == "" {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증이 필요합니다", nil)
+		return
+	}
+	boTable := c.Query("bo_table")
+	wrID, err := strconv.Atoi(c.DefaultQuery("wr_id", "0"))
+	if err != nil || wrID < 0 || !middleware.BoardSlugRegex.MatchString(boTable) {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 게시판 또는 글", err)
+		return
+	}
+	if err := h.repo.Delete(mbID, boTable, wrID); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "알림 규칙 삭제 실패", err)
+		return
+	}
+	common.V2Success(c, gin.H{"bo_table": boTable, "wr_id": wrID})
+}
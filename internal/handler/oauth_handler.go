@@ -1,8 +1,7 @@
 package handler
 
 import (
-	"crypto/rand"
-	"encoding/hex"
+	"errors"
 	"net/http"
 
 	"github.com/damoang/angple-backend/internal/common"
@@ -12,9 +11,17 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// oauthFlowCookie 는 state·nonce·PKCE 를 되살리는 서명된 흐름 토큰 쿠키다 (콜백 경로에만 실린다)
+const (
+	oauthFlowCookie     = "oauth_flow"
+	oauthFlowCookiePath = "/api/v2/auth/oauth"
+)
+
 // OAuthHandler handles OAuth2 social login endpoints
 type OAuthHandler struct {
 	oauthService *service.OAuthService
+	// memberLogin 은 연동된 실제 회원의 로그인을 끝낸다 (v2 AuthHandler.OAuthMemberLogin)
+	memberLogin func(c *gin.Context, mbID string)
 }
 
 // NewOAuthHandler creates a new OAuthHandler
@@ -22,89 +29,142 @@ func NewOAuthHandler(oauthService *service.OAuthService) *OAuthHandler {
 	return &OAuthHandler{oauthService: oauthService}
 }
 
+// SetMemberLogin sets how a login through a linked account is completed
+func (h *OAuthHandler) SetMemberLogin(fn func(c *gin.Context, mbID string)) {
+	h.memberLogin = fn
+}
+
 // Redirect initiates OAuth flow by redirecting to provider's auth page
 // GET /api/v2/auth/oauth/:provider
 func (h *OAuthHandler) Redirect(c *gin.Context) {
 	provider := domain.OAuthProvider(c.Param("provider"))
 
-	// Generate random state for CSRF protection
-	stateBytes := make([]byte, 16)
-	if _, err := rand.Read(stateBytes); err != nil {
-		common.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate state", nil)
-		return
-	}
-	state := hex.EncodeToString(stateBytes)
-
-	// Store state in cookie for verification
-	c.SetCookie("oauth_state", state, 600, "/", "", true, true)
-
-	authURL, err := h.oauthService.GetAuthURL(provider, state)
+	start, err := h.oauthService.StartFlow(c.Request.Context(), provider, "")
 	if err != nil {
-		common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		h.fail(c, err)
 		return
 	}
+	setOAuthFlowCookie(c, start)
 
-	c.Redirect(http.StatusTemporaryRedirect, authURL)
+	c.Redirect(http.StatusTemporaryRedirect, start.AuthURL)
 }
 
 // Callback handles the OAuth provider callback
-// GET /api/v2/auth/oauth/:provider/callback
+// GET|POST /api/v2/auth/oauth/:provider/callback (POST 는 response_mode=form_post 제공자)
 func (h *OAuthHandler) Callback(c *gin.Context) {
 	provider := domain.OAuthProvider(c.Param("provider"))
 
-	// Verify state
-	state := c.Query("state")
-	savedState, err := c.Cookie("oauth_state")
-	if err != nil || state == "" || state != savedState {
+	flow, err := c.Cookie(oauthFlowCookie)
+	// Clear flow cookie — 성공·실패와 무관하게 한 번만 쓴다
+	c.SetCookie(oauthFlowCookie, "", -1, oauthFlowCookiePath, "", true, true)
+	if err != nil || flow == "" {
 		common.ErrorResponse(c, http.StatusBadRequest, "Invalid OAuth state", nil)
 		return
 	}
 
-	// Clear state cookie
-	c.SetCookie("oauth_state", "", -1, "/", "", true, true)
-
-	code := c.Query("code")
+	if errCode := c.Request.FormValue("error"); errCode != "" {
+		common.ErrorResponse(c, http.StatusBadRequest, "OAuth login was canceled: "+errCode, nil)
+		return
+	}
+	code := c.Request.FormValue("code")
 	if code == "" {
 		common.ErrorResponse(c, http.StatusBadRequest, "Missing authorization code", nil)
 		return
 	}
 
-	result, err := h.oauthService.HandleCallback(c.Request.Context(), provider, code)
+	result, err := h.oauthService.HandleCallback(c.Request.Context(), provider, flow, c.Request.FormValue("state"), code)
 	if err != nil {
-		common.ErrorResponse(c, http.StatusInternalServerError, "OAuth login failed: "+err.Error(), nil)
+		h.fail(c, err)
+		return
+	}
+	if result.Member {
+		if h.memberLogin == nil {
+			common.ErrorResponse(c, http.StatusServiceUnavailable, "Member login is not available", nil)
+			return
+		}
+		h.memberLogin(c, result.UserID)
 		return
 	}
 
 	common.SuccessResponse(c, result, nil)
 }
 
-// GenerateAPIKey creates a new API key for the authenticated user
-// POST /api/v2/auth/api-keys
-func (h *OAuthHandler) GenerateAPIKey(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-	if userID == "" {
-		common.ErrorResponse(c, http.StatusUnauthorized, "Authentication required", nil)
+// ListAccounts handles GET /api/v2/me/oauth-accounts
+// providers 는 연동할 수 있는 제공자 목록이다 (테넌트 IdP 는 사이트 화면에서 따로 안내).
+func (h *OAuthHandler) ListAccounts(c *gin.Context) {
+	accounts, err := h.oauthService.ListAccounts(c.Request.Context(), middleware.GetUsername(c))
+	if err != nil {
+		common.ErrorResponse(c, http.StatusInternalServerError, "Failed to load linked accounts", err)
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{
+		"accounts":  accounts,
+		"providers": h.oauthService.ProviderNames(),
+	}})
+}
 
+// Link handles POST /api/v2/me/oauth-accounts
+// Body: {"provider": "github"} → {"auth_url": "..."} — 브라우저를 auth_url 로 보내면 콜백에서 연동이 끝난다.
+func (h *OAuthHandler) Link(c *gin.Context) {
 	var req struct {
-		Name   string `json:"name" binding:"required"`
-		Scopes string `json:"scopes"` // comma-separated: read,write
+		Provider string `json:"provider" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.ErrorResponse(c, http.StatusBadRequest, "Invalid request", nil)
 		return
 	}
-
-	if req.Scopes == "" {
-		req.Scopes = "read"
+	mbID := middleware.GetUsername(c)
+	if mbID == "" {
+		common.ErrorResponse(c, http.StatusUnauthorized, "Login required", nil)
+		return
 	}
+	start, err := h.oauthService.StartFlow(c.Request.Context(), domain.OAuthProvider(req.Provider), mbID)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	setOAuthFlowCookie(c, start)
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"auth_url": start.AuthURL}})
+}
 
-	apiKey, err := h.oauthService.GenerateAPIKey(c.Request.Context(), userID, req.Name, req.Scopes)
+// Unlink handles DELETE /api/v2/me/oauth-accounts/:provider
+func (h *OAuthHandler) Unlink(c *gin.Context) {
+	err := h.oauthService.Unlink(c.Request.Context(), middleware.GetUsername(c), domain.OAuthProvider(c.Param("provider")))
 	if err != nil {
-		common.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate API key", nil)
+		h.fail(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"unlinked": true}})
+}
+
+// setOAuthFlowCookie stores the flow token for the callback.
+// form_post 제공자(Apple)는 콜백이 교차 사이트 POST 라 SameSite=Lax 쿠키가 실리지 않는다.
+func setOAuthFlowCookie(c *gin.Context, start *service.OAuthFlowStart) {
+	sameSite := http.SameSiteLaxMode
+	if start.FormPost {
+		sameSite = http.SameSiteNoneMode
+	}
+	c.SetSameSite(sameSite)
+	c.SetCookie(oauthFlowCookie, start.Cookie, int(service.OAuthFlowTTL.Seconds()), oauthFlowCookiePath, "", true, true)
+}
 
-	c.JSON(http.StatusCreated, gin.H{"success": true, "data": apiKey})
+func (h *OAuthHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnsupportedOAuthProvider):
+		common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, service.ErrOAuthState):
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid OAuth state", nil)
+	case errors.Is(err, common.ErrAccountWithdrawn):
+		c.JSON(http.StatusForbidden, common.V2Response{
+			Success: false,
+			Error:   &common.V2Error{Code: "account_withdrawn", Message: "탈퇴 처리된 계정입니다."},
+		})
+	case errors.Is(err, service.ErrOAuthAccountLinked), errors.Is(err, service.ErrOAuthProviderLinked),
+		errors.Is(err, service.ErrLastLoginMethod):
+		common.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, service.ErrOAuthAccountNotFound):
+		common.ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+	default:
+		common.ErrorResponse(c, http.StatusInternalServerError, "OAuth login failed", err)
+	}
 }
@@ -141,3 +141,54 @@ func (h *ProvisioningHandler) GetPricing(c *gin.Context) {
 	pricing := h.provisioningSvc.GetPricing()
 	common.V2Success(c, pricing)
 }
+
+// GetIdentityProvider godoc
+// @Summary 테넌트 IdP(OIDC) 설정 조회
+// @Tags saas
+// @Param id path string true "사이트 ID"
+// @Success 200 {object} common.V2Response
+// @Router /api/v2/saas/communities/{id}/idp [get]
+func (h *ProvisioningHandler) GetIdentityProvider(c *gin.Context) {
+	idp, err := h.provisioningSvc.GetIdentityProvider(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	common.V2Success(c, idp)
+}
+
+// SetIdentityProvider godoc
+// @Summary 테넌트 IdP(OIDC) 설정 — 회원은 /api/v2/auth/oauth/sso-<subdomain> 으로 로그인한다
+// @Tags saas
+// @Accept json
+// @Param id path string true "사이트 ID"
+// @Param body body domain.SiteIdentityProviderRequest true "IdP 정보"
+// @Success 200 {object} common.V2Response
+// @Router /api/v2/saas/communities/{id}/idp [put]
+func (h *ProvisioningHandler) SetIdentityProvider(c *gin.Context) {
+	var req domain.SiteIdentityProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "요청 형식이 올바르지 않습니다", err)
+		return
+	}
+	idp, err := h.provisioningSvc.SetIdentityProvider(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	common.V2Success(c, idp)
+}
+
+// DeleteIdentityProvider godoc
+// @Summary 테넌트 IdP(OIDC) 설정 삭제
+// @Tags saas
+// @Param id path string true "사이트 ID"
+// @Success 200 {object} common.V2Response
+// @Router /api/v2/saas/communities/{id}/idp [delete]
+func (h *ProvisioningHandler) DeleteIdentityProvider(c *gin.Context) {
+	if err := h.provisioningSvc.DeleteIdentityProvider(c.Request.Context(), c.Param("id")); err != nil {
+		common.V2ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	common.V2Success(c, gin.H{"message": "IdP 설정이 삭제되었습니다"})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SavedSearchHandler handles a member's saved searches (/api/v2/search/saved)
+type SavedSearchHandler struct {
+	service *service.SavedSearchService
+}
+
+// NewSavedSearchHandler creates a new SavedSearchHandler
+func NewSavedSearchHandler(svc *service.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{service: svc}
+}
+
+// List handles GET /api/v2/search/saved
+func (h *SavedSearchHandler) List(c *gin.Context) {
+	searches, err := h.service.List(c.Request.Context(), middleware.GetUsername(c))
+	if err != nil {
+		common.ErrorResponse(c, http.StatusInternalServerError, "Failed to load saved searches", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": searches})
+}
+
+// Create handles POST /api/v2/search/saved
+// Body: {"name": "특가 알림", "params": {"q": "SSD", "board_id": "economy", "min_good": 5}, "notify": true}
+func (h *SavedSearchHandler) Create(c *gin.Context) {
+	var in service.SavedSearchInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid request", err)
+		return
+	}
+	search, err := h.service.Create(c.Request.Context(), middleware.GetUsername(c), in)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": search})
+}
+
+// Update handles PATCH /api/v2/search/saved/:id
+func (h *SavedSearchHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid saved search ID", err)
+		return
+	}
+	var in service.SavedSearchInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid request", err)
+		return
+	}
+	search, err := h.service.Update(c.Request.Context(), middleware.GetUsername(c), id, in)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": search})
+}
+
+// Delete handles DELETE /api/v2/search/saved/:id
+func (h *SavedSearchHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		common.ErrorResponse(c, http.StatusBadRequest, "Invalid saved search ID", err)
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), middleware.GetUsername(c), id); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *SavedSearchHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSearch):
+		common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, service.ErrSavedSearchLimit):
+		common.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, service.ErrSavedSearchNotFound):
+		common.ErrorResponse(c, http.StatusNotFound, "Saved search not found", nil)
+	default:
+		common.ErrorResponse(c, http.StatusInternalServerError, "Failed to save search", err)
+	}
+}
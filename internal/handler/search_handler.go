@@ -1,15 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/middleware"
 	"github.com/damoang/angple-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
-// SearchHandler handles Elasticsearch-based search endpoints
+// SearchHandler handles full-text search endpoints
 type SearchHandler struct {
 	searchService *service.SearchService
 }
@@ -20,16 +22,18 @@ func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
 }
 
 // Search performs unified search across posts and comments
-// GET /api/v2/search?q=keyword&board_id=free&type=posts&page=1&per_page=20
+// GET /api/v2/search?q=keyword&board_id=free&type=posts&author=닉네임&category=a,b&tags=t1&from=2026-01-01&to=2026-02-01
+//
+//	&min_good=10&has_image=1&has_file=1&exclude_blocked=1&sort=recent&page=1&per_page=20
+//
+// 게시글 결과에는 게시판·카테고리·월별 패싯(facets)이 붙는다.
 func (h *SearchHandler) Search(c *gin.Context) {
-	keyword := c.Query("q")
-	if keyword == "" {
+	params := service.ParseSearchParams(c.Request.URL.Query())
+	if params.Keyword == "" {
 		common.ErrorResponse(c, http.StatusBadRequest, "Search query is required", nil)
 		return
 	}
 
-	boardID := c.Query("board_id")
-	searchType := c.DefaultQuery("type", "all") // all, posts, comments
 	page := 1
 	if val, err := strconv.Atoi(c.DefaultQuery("page", "1")); err == nil {
 		page = val
@@ -46,8 +50,26 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		perPage = 20
 	}
 
-	result, err := h.searchService.UnifiedSearch(c.Request.Context(), keyword, boardID, searchType, page, perPage)
+	query, err := h.searchService.BuildQuery(params, middleware.GetUsername(c), page, perPage)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearch) {
+			common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		common.ErrorResponse(c, http.StatusInternalServerError, "Search failed", err)
+		return
+	}
+	searchType := params.Type
+	if searchType == "" {
+		searchType = "all"
+	}
+
+	result, err := h.searchService.UnifiedSearch(c.Request.Context(), query, searchType)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearch) {
+			common.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		common.ErrorResponse(c, http.StatusInternalServerError, "Search failed: "+err.Error(), nil)
 		return
 	}
@@ -56,11 +78,14 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		"success": true,
 		"data":    result,
 		"meta": gin.H{
-			"query":    keyword,
-			"board_id": boardID,
+			"query":    params.Keyword,
+			"board_id": params.BoardID,
 			"type":     searchType,
+			"filters":  params,
+			"sort":     query.Sort,
 			"page":     page,
 			"per_page": perPage,
+			"backend":  h.searchService.BackendName(),
 		},
 	})
 }
@@ -155,3 +180,24 @@ func (h *SearchHandler) DeletePostIndex(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "post removed from index"})
 }
+
+// StartReindex rebuilds both indices into new versioned indices and swaps the aliases (admin)
+// POST /api/v2/admin/search/reindex
+func (h *SearchHandler) StartReindex(c *gin.Context) {
+	if err := h.searchService.StartReindex(); err != nil {
+		if errors.Is(err, service.ErrReindexRunning) {
+			common.ErrorResponse(c, http.StatusConflict, "Reindex already running", err)
+			return
+		}
+		common.ErrorResponse(c, http.StatusInternalServerError, "Reindex failed to start", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "data": h.searchService.ReindexStatus()})
+}
+
+// ReindexStatus returns the running or last reindex (admin)
+// GET /api/v2/admin/search/reindex
+func (h *SearchHandler) ReindexStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": h.searchService.ReindexStatus()})
+}
@@ -48,6 +48,20 @@ type v2RefreshRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// sessionMeta describes the requesting device for the session list.
+// 앱은 X-Device-Name 헤더로 기기 이름(예: "Galaxy S24")을 보낸다.
+func sessionMeta(c *gin.Context, platform string) v2svc.SessionMeta {
+	if platform == "" {
+		platform = "web"
+	}
+	return v2svc.SessionMeta{
+		UserAgent:  c.Request.UserAgent(),
+		IP:         c.ClientIP(),
+		Platform:   platform,
+		DeviceName: c.GetHeader("X-Device-Name"),
+	}
+}
+
 // isSecureCookie returns true if cookies should have the Secure flag.
 // In release mode (production), Secure=true; in debug/local, Secure=false.
 func isSecureCookie() bool {
@@ -183,7 +197,7 @@ func (h *V2AuthHandler) Login(c *gin.Context) {
 		common.V2ErrorResponse(c, http.StatusBadRequest, "아이디를 입력해주세요", nil)
 		return
 	}
-	resp, err := h.authService.Login(username, req.Password)
+	resp, err := h.authService.Login(username, req.Password, sessionMeta(c, req.Platform))
 	if err != nil {
 		if errors.Is(err, common.ErrAccountWithdrawn) {
 			// 숙려기간 경과 → 확정(익명화)된 계정. 로그인 불가.
@@ -196,6 +210,38 @@ func (h *V2AuthHandler) Login(c *gin.Context) {
 		common.V2ErrorResponse(c, http.StatusUnauthorized, "로그인에 실패했습니다", err)
 		return
 	}
+	respondLogin(c, resp, req.Platform == "mobile")
+}
+
+// OAuthMemberLogin finishes a social/OIDC login whose account is linked to member mbID.
+// handler.OAuthHandler 의 콜백이 부른다(SetMemberLogin) — 응답은 POST /auth/login 과 같다.
+func (h *V2AuthHandler) OAuthMemberLogin(c *gin.Context, mbID string) {
+	resp, err := h.authService.OAuthMemberLogin(mbID, sessionMeta(c, "web"))
+	if err != nil {
+		if errors.Is(err, common.ErrAccountWithdrawn) {
+			c.JSON(http.StatusForbidden, common.V2Response{
+				Success: false,
+				Error:   &common.V2Error{Code: "account_withdrawn", Message: "탈퇴 처리된 계정입니다."},
+			})
+			return
+		}
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "로그인에 실패했습니다", err)
+		return
+	}
+	respondLogin(c, resp, false)
+}
+
+// respondLogin writes a successful Login / VerifyMFA / passkey result (mobile: refresh_token 도 body 로)
+func respondLogin(c *gin.Context, resp *v2svc.V2LoginResponse, mobile bool) {
+	// 2단계 인증 대기: 토큰 없이 티켓만 준다. 코드와 함께 POST /auth/mfa/verify 로 보내면 로그인이 끝난다.
+	if resp.MFATicket != "" {
+		common.V2Success(c, gin.H{
+			"status":     "mfa_required",
+			"mfa_ticket": resp.MFATicket,
+			"expires_in": int(v2svc.MFATicketTTL.Seconds()),
+		})
+		return
+	}
 
 	// 탈퇴 숙려중: 정상 로그인 성공이 아니라 취소 가능 상태를 반환. 리프레시 쿠키는 설정하지 않는다.
 	// access_token 은 취소(DELETE /members/me/leave) 호출용으로만 함께 내려준다.
@@ -219,12 +265,43 @@ func (h *V2AuthHandler) Login(c *gin.Context) {
 		"access_token": resp.AccessToken,
 		"user":         resp.User,
 	}
-	if req.Platform == "mobile" {
+	if mobile {
 		data["refresh_token"] = resp.RefreshToken
 	}
 	common.V2Success(c, data)
 }
 
+// VerifyMFA handles POST /api/v2/auth/mfa/verify
+// Body: {"mfa_ticket": "...", "code": "123456" 또는 복구 코드, "platform": "mobile"}
+func (h *V2AuthHandler) VerifyMFA(c *gin.Context) {
+	var req struct {
+		Ticket   string `json:"mfa_ticket" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+		Platform string `json:"platform"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 요청입니다", err)
+		return
+	}
+	resp, err := h.authService.CompleteMFALogin(req.Ticket, req.Code, sessionMeta(c, req.Platform))
+	if err != nil {
+		switch {
+		case errors.Is(err, v2svc.ErrMFAInvalidCode):
+			common.V2ErrorResponse(c, http.StatusUnauthorized, "인증 코드가 올바르지 않습니다", nil)
+		case errors.Is(err, common.ErrAccountWithdrawn):
+			c.JSON(http.StatusForbidden, common.V2Response{
+				Success: false,
+				Error:   &common.V2Error{Code: "account_withdrawn", Message: "탈퇴 처리된 계정입니다."},
+			})
+		default:
+			// 만료·재사용·시도 초과 티켓 — 처음부터 다시 로그인
+			common.V2ErrorResponse(c, http.StatusUnauthorized, "인증 시간이 지났습니다. 다시 로그인해주세요", err)
+		}
+		return
+	}
+	respondLogin(c, resp, req.Platform == "mobile")
+}
+
 // RefreshToken handles POST /api/v2/auth/refresh
 func (h *V2AuthHandler) RefreshToken(c *gin.Context) {
 	// Try cookie first, then JSON body
@@ -240,7 +317,11 @@ func (h *V2AuthHandler) RefreshToken(c *gin.Context) {
 		fromBody = true
 	}
 
-	resp, err := h.authService.RefreshToken(refreshToken)
+	platform := "web"
+	if fromBody {
+		platform = "mobile"
+	}
+	resp, err := h.authService.RefreshToken(refreshToken, sessionMeta(c, platform))
 	if err != nil {
 		if errors.Is(err, common.ErrAccountWithdrawn) {
 			c.JSON(http.StatusForbidden, common.V2Response{
@@ -293,11 +374,15 @@ func (h *V2AuthHandler) AppExchange(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.AppExchangeLogin(req.Code)
+	resp, err := h.authService.AppExchangeLogin(req.Code, sessionMeta(c, "mobile"))
 	if err != nil {
 		common.V2ErrorResponse(c, http.StatusUnauthorized, "앱 로그인 코드가 유효하지 않습니다", err)
 		return
 	}
+	if resp.MFATicket != "" {
+		respondLogin(c, resp, true)
+		return
+	}
 
 	c.SetCookie("refresh_token", resp.RefreshToken, 7*24*3600, "/", cookieDomain(c.Request.Host), isSecureCookie(), true)
 
@@ -313,7 +398,18 @@ func (h *V2AuthHandler) AppExchange(c *gin.Context) {
 // 자동 재로그인 방지: damoang.net 외 도메인(muzia.net 등)도 동일 backend 를 쓰므로
 // 요청 host 기준 cookie 도 함께 만료시킨다. damoang_jwt 는 일부 클라이언트에서
 // access_token 폴백으로 읽히므로 함께 삭제.
+//
+// 서버 세션도 폐기한다 — 쿠키(웹) 또는 body 의 refresh_token(앱)으로 세션을 찾는다.
+// 그 세션의 access token 은 JWTAuth 세션 게이트에서 바로 막힌다.
 func (h *V2AuthHandler) Logout(c *gin.Context) {
+	refreshToken, err := c.Cookie("refresh_token")
+	if err != nil || refreshToken == "" {
+		var req v2RefreshRequest
+		_ = c.ShouldBindJSON(&req) //nolint:errcheck // body 없는 로그아웃도 정상이다
+		refreshToken = req.RefreshToken
+	}
+	h.authService.Logout(refreshToken, "")
+
 	secure := isSecureCookie()
 	hostDomain := c.Request.Host
 	if i := strings.Index(hostDomain, ":"); i >= 0 {
@@ -338,7 +434,7 @@ func (h *V2AuthHandler) ExchangeToken(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.RefreshToken(refreshToken)
+	resp, err := h.authService.RefreshToken(refreshToken, sessionMeta(c, "web"))
 	if err != nil {
 		if errors.Is(err, common.ErrAccountWithdrawn) {
 			c.JSON(http.StatusForbidden, common.V2Response{
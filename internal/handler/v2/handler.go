@@ -15,6 +15,7 @@ import (
 	"github.com/damoang/angple-backend/internal/middleware"
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/internal/ws"
 	pkgredis "github.com/damoang/angple-backend/pkg/redis"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -31,6 +32,7 @@ type V2Handler struct {
 	revisionRepo      v2repo.RevisionRepository
 	notiRepo          gnurepo.NotiRepository
 	notiPrefRepo      gnurepo.NotiPreferenceRepository
+	notiRuleRepo      gnurepo.NotiRuleRepository
 	expRepo           v2repo.ExpRepository
 	gnuDB             *gorm.DB // gnuboard g5_member 조회용
 	gnuPointWriteRepo v2repo.GnuboardPointWriteRepository
@@ -43,6 +45,8 @@ type V2Handler struct {
 	gnuBoardRepo gnurepo.BoardRepository
 	// 크로스보드 통합 피드용 (nil-safe — 주입 시 GET /api/v2/feed 활성)
 	feedRepo gnurepo.MyPageRepository
+	// 실시간 토픽 (nil-safe — 주입 시 리액션 수를 글 토픽 구독자에게 흘린다)
+	topics ws.TopicPublisher
 }
 
 const claimBoardSlug = "claim"
@@ -93,6 +97,11 @@ func (h *V2Handler) SetNotiPreferenceRepository(repo gnurepo.NotiPreferenceRepos
 	h.notiPrefRepo = repo
 }
 
+// SetNotiRuleRepository sets the per-thread/per-board notification rule repository
+func (h *V2Handler) SetNotiRuleRepository(repo gnurepo.NotiRuleRepository) {
+	h.notiRuleRepo = repo
+}
+
 // SetGnuDB sets the gnuboard database connection for mb_id → mb_no lookup
 func (h *V2Handler) SetGnuDB(db *gorm.DB) {
 	h.gnuDB = db
@@ -1355,16 +1364,7 @@ func (h *V2Handler) createCommentNotification(boardSlug string, postID uint64, c
 						isBlockedByParent = slices.Contains(blockedIDs, commenterMbID)
 					}
 				}
-				// 답글 알림 설정 확인
-				sendReply := true
-				if isBlockedByParent {
-					sendReply = false
-				} else if h.notiPrefRepo != nil {
-					if pref, err := h.notiPrefRepo.Get(parentAuthorMbID); err == nil && !pref.NotiReply {
-						sendReply = false
-					}
-				}
-				if sendReply {
+				if !isBlockedByParent {
 					// ⛔ 규약: comment 계열 알림의 wr_id 는 '새 댓글 id' 다 (원글은 rel_url·wr_parent 에).
 					//    worker 경로(write_after_worker)와 동일해야 grouped 키·Exists 중복판정이 맞물린다.
 					commentID := safeUint64ToInt(comment.ID)
@@ -1384,7 +1384,10 @@ func (h *V2Handler) createCommentNotification(boardSlug string, postID uint64, c
 							ParentSubject: post.Title,
 							WrParent:      safeUint64ToInt(postID),
 						}
-						_ = h.notiRepo.Create(noti)
+						// 답글 알림 설정·글/게시판 규칙 확인
+						if h.gateNotification(noti) {
+							_ = h.notiRepo.Create(noti)
+						}
 					}
 				}
 			}
@@ -1398,13 +1401,6 @@ func (h *V2Handler) createCommentNotification(boardSlug string, postID uint64, c
 		}
 	}
 
-	// 댓글 알림 설정 확인
-	if h.notiPrefRepo != nil {
-		if pref, err := h.notiPrefRepo.Get(postAuthorMbID); err == nil && !pref.NotiComment {
-			return
-		}
-	}
-
 	// 게시글 작성자에게 알림
 	// ⛔ 규약: comment 계열 알림의 wr_id 는 '새 댓글 id' 다 (worker 경로와 동일 — 위 답글 블록 주석 참조).
 	commentID := safeUint64ToInt(comment.ID)
@@ -1424,6 +1420,37 @@ func (h *V2Handler) createCommentNotification(boardSlug string, postID uint64, c
 			ParentSubject: post.Title,
 			WrParent:      safeUint64ToInt(postID),
 		}
-		_ = h.notiRepo.Create(noti)
+		// 댓글 알림 설정·글/게시판 규칙 확인
+		if h.gateNotification(noti) {
+			_ = h.notiRepo.Create(noti)
+		}
 	}
 }
+
+// gateNotification applies the recipient's switches and thread·board rules (WriteAfterWorker.createNotification 과 같은 판정).
+// 만들지 말아야 하면 false, 뮤트면 읽음으로 바꿔 true. 설정을 못 읽으면 그대로 만든다.
+func (h *V2Handler) gateNotification(noti *gnurepo.Notification) bool {
+	if h.notiPrefRepo == nil {
+		return true
+	}
+	pref, err := h.notiPrefRepo.Get(noti.MbID)
+	if err != nil {
+		return true
+	}
+	decision := gnurepo.NotiDeliver
+	if !pref.Allows(noti) {
+		decision = gnurepo.NotiDrop
+	}
+	if h.notiRuleRepo != nil {
+		if d, err := h.notiRuleRepo.Decide(pref, noti); err == nil {
+			decision = d
+		}
+	}
+	switch decision {
+	case gnurepo.NotiDrop:
+		return false
+	case gnurepo.NotiSilent:
+		noti.PhReaded = "Y"
+	}
+	return true
+}
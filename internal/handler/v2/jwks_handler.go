@@ -0,0 +1,25 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler publishes the public keys that verify our access tokens (omok·janggi 등 공개키 검증용)
+type JWKSHandler struct {
+	jwtManager *jwt.Manager
+}
+
+// NewJWKSHandler creates a new JWKSHandler
+func NewJWKSHandler(jwtManager *jwt.Manager) *JWKSHandler {
+	return &JWKSHandler{jwtManager: jwtManager}
+}
+
+// Get handles GET /.well-known/jwks.json
+// HS256 만 쓰는 동안은 keys 가 빈 배열이다. 다음 키는 활성 하루 전부터 실리므로 5분 캐시로 충분하다.
+func (h *JWKSHandler) Get(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtManager.JWKS())
+}
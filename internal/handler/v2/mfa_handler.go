@@ -0,0 +1,141 @@
+package v2
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/damoang/angple-backend/internal/common"
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/damoang/angple-backend/internal/middleware"
+	v2svc "github.com/damoang/angple-backend/internal/service/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler handles TOTP enrollment, recovery codes and step-up (/api/v2/auth/mfa)
+type MFAHandler struct {
+	mfa         *v2svc.MFAService
+	authService *v2svc.V2AuthService
+}
+
+// NewMFAHandler creates a new MFAHandler
+func NewMFAHandler(mfa *v2svc.MFAService, authService *v2svc.V2AuthService) *MFAHandler {
+	return &MFAHandler{mfa: mfa, authService: authService}
+}
+
+type mfaCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// Status handles GET /api/v2/auth/mfa
+func (h *MFAHandler) Status(c *gin.Context) {
+	userID, ok := sessionUserID(c)
+	if !ok {
+		return
+	}
+	status, err := h.mfa.Status(userID)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "2단계 인증 상태를 불러오지 못했습니다", err)
+		return
+	}
+	common.V2Success(c, status)
+}
+
+// Enroll handles POST /api/v2/auth/mfa/enroll
+// 응답의 provisioning_uri 를 QR 로 보여 주고, 앱이 만든 첫 코드를 /enroll/confirm 으로 보낸다.
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	userID, ok := sessionUserID(c)
+	if !ok {
+		return
+	}
+	enrollment, err := h.mfa.BeginEnroll(&v2domain.V2User{ID: userID, Username: middleware.GetUsername(c)})
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	common.V2Success(c, enrollment)
+}
+
+// ConfirmEnroll handles POST /api/v2/auth/mfa/enroll/confirm
+// 복구 코드 평문은 이 응답에서 한 번만 내려간다.
+func (h *MFAHandler) ConfirmEnroll(c *gin.Context) {
+	userID, req, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+	codes, err := h.mfa.ConfirmEnroll(userID, req.Code)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"enabled": true, "recovery_codes": codes})
+}
+
+// Disable handles POST /api/v2/auth/mfa/disable
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID, req, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+	if err := h.mfa.Disable(userID, req.Code); err != nil {
+		h.fail(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"enabled": false})
+}
+
+// RegenerateRecoveryCodes handles POST /api/v2/auth/mfa/recovery-codes
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, req, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+	codes, err := h.mfa.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"recovery_codes": codes})
+}
+
+// Challenge handles POST /api/v2/auth/mfa/challenge (step-up)
+// 이미 로그인한 세션에서 코드를 다시 확인하고 mfa 가 실린 access token 을 돌려준다.
+func (h *MFAHandler) Challenge(c *gin.Context) {
+	userID, req, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+	accessToken, err := h.authService.StepUp(userID, middleware.GetSessionID(c), req.Code)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"access_token": accessToken})
+}
+
+func (h *MFAHandler) bindCode(c *gin.Context) (uint64, mfaCodeRequest, bool) {
+	var req mfaCodeRequest
+	userID, ok := sessionUserID(c)
+	if !ok {
+		return 0, req, false
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "인증 코드를 입력해주세요", err)
+		return 0, req, false
+	}
+	return userID, req, true
+}
+
+func (h *MFAHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, v2svc.ErrMFAInvalidCode):
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증 코드가 올바르지 않습니다", nil)
+	case errors.Is(err, v2svc.ErrMFANotEnabled):
+		common.V2ErrorResponse(c, http.StatusBadRequest, "2단계 인증이 설정되어 있지 않습니다", nil)
+	case errors.Is(err, v2svc.ErrMFAAlreadyEnabled):
+		common.V2ErrorResponse(c, http.StatusConflict, "이미 2단계 인증을 사용 중입니다", nil)
+	case errors.Is(err, common.ErrUnauthorized):
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "세션이 만료되었습니다. 다시 로그인해주세요", nil)
+	default:
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "2단계 인증 처리에 실패했습니다", err)
+	}
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/damoang/angple-backend/internal/common"
 	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/ws"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -186,9 +187,30 @@ func (h *V2Handler) PostPostReaction(c *gin.Context) {
 		}
 	}
 
+	h.publishReactionCount(slug, wrID, targetID)
 	c.JSON(http.StatusOK, gin.H{"status": "success", "result": h.fetchReactionsForTarget(targetID, memberID)})
 }
 
+// SetTopicPublisher enables live reaction counts on post topics (ws.PostTopic)
+func (h *V2Handler) SetTopicPublisher(topics ws.TopicPublisher) {
+	h.topics = topics
+}
+
+// publishReactionCount sends the target's counts (choose 없이) to readers of the post.
+// 누가 눌렀는지는 싣지 않으므로 actor 를 두지 않는다 — 차단과 무관하게 모두 같은 수를 본다.
+func (h *V2Handler) publishReactionCount(slug string, wrID int, targetID string) {
+	if h.topics == nil {
+		return
+	}
+	h.topics.PublishTopic(ws.PostTopic(slug, wrID), &ws.Event{
+		Type: "reaction_count",
+		Payload: map[string]any{
+			"target_id": targetID,
+			"reactions": h.fetchReactionsForTarget(targetID, "")[targetID],
+		},
+	}, "")
+}
+
 // postTargetID 는 게시글 target_id 를 만든다. board slug 는 sanitize 하여 주입값 오염을 막는다.
 func postTargetID(slug string, wrID int) string {
 	return "document:" + sanitizeReactionID(slug) + ":" + strconv.Itoa(wrID)
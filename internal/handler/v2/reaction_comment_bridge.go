@@ -115,6 +115,7 @@ func (h *V2Handler) PostCommentReaction(c *gin.Context) {
 		}
 	}
 
+	h.publishReactionCount(slug, postID, targetID)
 	c.JSON(http.StatusOK, gin.H{"status": "success", "result": h.fetchReactionsForTarget(targetID, memberID)})
 }
 
@@ -0,0 +1,88 @@
+package v2
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/middleware"
+	v2svc "github.com/damoang/angple-backend/internal/service/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler lets a member see and log out their devices (/api/v2/me/sessions)
+type SessionHandler struct {
+	sessions *v2svc.SessionService
+}
+
+// NewSessionHandler creates a new SessionHandler
+func NewSessionHandler(sessions *v2svc.SessionService) *SessionHandler {
+	return &SessionHandler{sessions: sessions}
+}
+
+// sessionUserID returns the v2_users.id of the caller.
+// 세션은 Bearer 로그인에만 있으므로 내부 SSR 경로(userID=mb_id)는 여기서 401 이다.
+func sessionUserID(c *gin.Context) (uint64, bool) {
+	userID, err := strconv.ParseUint(middleware.GetUserID(c), 10, 64)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증 정보가 올바르지 않습니다", err)
+		return 0, false
+	}
+	return userID, true
+}
+
+// List handles GET /api/v2/me/sessions
+func (h *SessionHandler) List(c *gin.Context) {
+	userID, ok := sessionUserID(c)
+	if !ok {
+		return
+	}
+	sessions, err := h.sessions.List(userID, middleware.GetSessionID(c))
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "세션 목록을 불러오지 못했습니다", err)
+		return
+	}
+	common.V2Success(c, sessions)
+}
+
+// Revoke handles DELETE /api/v2/me/sessions/:id
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, ok := sessionUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 세션 ID 입니다", err)
+		return
+	}
+	if err := h.sessions.Revoke(c.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, v2svc.ErrSessionNotFound) {
+			common.V2ErrorResponse(c, http.StatusNotFound, "세션을 찾을 수 없습니다", nil)
+			return
+		}
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "세션을 종료하지 못했습니다", err)
+		return
+	}
+	common.V2Success(c, gin.H{"message": "세션을 종료했습니다"})
+}
+
+// RevokeAll handles DELETE /api/v2/me/sessions
+// 기본은 지금 기기를 뺀 나머지를 끊는다. ?all=true 면 지금 기기까지 끊는다.
+func (h *SessionHandler) RevokeAll(c *gin.Context) {
+	userID, ok := sessionUserID(c)
+	if !ok {
+		return
+	}
+	except := middleware.GetSessionID(c)
+	if c.Query("all") == "true" {
+		except = ""
+	}
+	n, err := h.sessions.RevokeAll(c.Request.Context(), userID, except)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "세션을 종료하지 못했습니다", err)
+		return
+	}
+	common.V2Success(c, gin.H{"revoked": n})
+}
@@ -0,0 +1,119 @@
+package v2
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/damoang/angple-backend/internal/common"
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	"github.com/damoang/angple-backend/internal/middleware"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/webpush"
+	"github.com/gin-gonic/gin"
+)
+
+// WebPushHandler handles v2 browser Web Push subscription endpoints
+type WebPushHandler struct {
+	repo v2repo.WebPushRepository
+	// publicKey 는 VAPID 공개키다 — 비어 있으면 웹 푸시가 꺼져 있다
+	publicKey string
+}
+
+// NewWebPushHandler creates a new WebPushHandler
+func NewWebPushHandler(repo v2repo.WebPushRepository, publicKey string) *WebPushHandler {
+	return &WebPushHandler{repo: repo, publicKey: publicKey}
+}
+
+// webPushSubscriptionRequest is PushSubscription.toJSON() as the browser gives it
+type webPushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required"`
+	Keys     struct {
+		P256dh string `json:"p256dh" binding:"required"`
+		Auth   string `json:"auth" binding:"required"`
+	} `json:"keys"`
+}
+
+// GetPublicKey handles GET /api/v2/push/web/public-key
+// 브라우저가 PushManager.subscribe 의 applicationServerKey 로 쓴다.
+func (h *WebPushHandler) GetPublicKey(c *gin.Context) {
+	if h.publicKey == "" {
+		common.V2ErrorResponse(c, http.StatusServiceUnavailable, "웹 푸시가 설정되지 않았습니다", nil)
+		return
+	}
+	common.V2Success(c, gin.H{"public_key": h.publicKey})
+}
+
+// Subscribe handles POST /api/v2/push/web/subscriptions
+// Registers (or reassigns) the browser's subscription for the authenticated user.
+func (h *WebPushHandler) Subscribe(c *gin.Context) {
+	userID, err := strconv.ParseUint(middleware.GetUserID(c), 10, 64)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증이 필요합니다", err)
+		return
+	}
+	if h.publicKey == "" {
+		common.V2ErrorResponse(c, http.StatusServiceUnavailable, "웹 푸시가 설정되지 않았습니다", nil)
+		return
+	}
+
+	var req webPushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "요청 형식이 올바르지 않습니다", err)
+		return
+	}
+	// 워커가 이 URL 로 직접 POST 한다 — 알려진 푸시 서비스·올바른 키만 받는다
+	if err := (webpush.Subscription{Endpoint: req.Endpoint, P256dh: req.Keys.P256dh, Auth: req.Keys.Auth}).Validate(); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "지원하지 않는 푸시 구독입니다", err)
+		return
+	}
+
+	userAgent := c.GetHeader("User-Agent")
+	if r := []rune(userAgent); len(r) > 255 {
+		userAgent = string(r[:255])
+	}
+	sub := &v2domain.V2WebPushSubscription{
+		UserID:    userID,
+		Endpoint:  req.Endpoint,
+		P256dh:    req.Keys.P256dh,
+		Auth:      req.Keys.Auth,
+		UserAgent: userAgent,
+	}
+	if err := h.repo.Upsert(sub); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "웹 푸시 구독 실패", err)
+		return
+	}
+	common.V2Created(c, sub)
+}
+
+// ListSubscriptions handles GET /api/v2/push/web/subscriptions
+func (h *WebPushHandler) ListSubscriptions(c *gin.Context) {
+	userID, err := strconv.ParseUint(middleware.GetUserID(c), 10, 64)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "인증이 필요합니다", err)
+		return
+	}
+	subs, err := h.repo.ListByUser(userID)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "웹 푸시 구독 조회 실패", err)
+		return
+	}
+	common.V2Success(c, subs)
+}
+
+// Unsubscribe handles POST /api/v2/push/web/unsubscribe (무인증).
+// 엔드포인트는 URL 이라 경로 파라미터 대신 본문으로 받는다. 로그아웃된 브라우저도 구독을 풀 수 있도록
+// 엔드포인트 보유를 소유 증명으로 본다 — 기기 토큰의 by-token 해제(#13444)와 같은 이유다.
+func (h *WebPushHandler) Unsubscribe(c *gin.Context) {
+	var req struct {
+		Endpoint string `json:"endpoint" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "endpoint가 필요합니다", err)
+		return
+	}
+	if err := h.repo.DeleteByEndpoint(req.Endpoint); err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "웹 푸시 구독 해제 실패", err)
+		return
+	}
+	common.V2Success(c, gin.H{"message": "웹 푸시 구독 해제 완료"})
+}
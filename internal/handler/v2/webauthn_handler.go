@@ -0,0 +1,201 @@
+package v2
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/damoang/angple-backend/internal/middleware"
+	v2svc "github.com/damoang/angple-backend/internal/service/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// WebAuthnHandler handles passkey registration, login and management (/api/v2/auth/webauthn)
+type WebAuthnHandler struct {
+	webauthn    *v2svc.WebAuthnService
+	authService *v2svc.V2AuthService
+}
+
+// NewWebAuthnHandler creates a new WebAuthnHandler
+func NewWebAuthnHandler(webauthn *v2svc.WebAuthnService, authService *v2svc.V2AuthService) *WebAuthnHandler {
+	return &WebAuthnHandler{webauthn: webauthn, authService: authService}
+}
+
+// BeginRegistration handles POST /api/v2/auth/webauthn/register/begin
+// 응답의 options 를 그대로 navigator.credentials.create() 에 넘긴다(바이너리 필드는 base64url).
+func (h *WebAuthnHandler) BeginRegistration(c *gin.Context) {
+	userID, ok := sessionUserID(c)
+	if !ok {
+		return
+	}
+	user, err := h.authService.GetCurrentUser(userID)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "회원 정보를 찾을 수 없습니다", err)
+		return
+	}
+	options, err := h.webauthn.BeginRegistration(user, middleware.HasMFA(c))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"options": options})
+}
+
+// FinishRegistration handles POST /api/v2/auth/webauthn/register/finish
+// Body: {"name": "내 아이폰", "credential": navigator.credentials.create() 결과}
+func (h *WebAuthnHandler) FinishRegistration(c *gin.Context) {
+	userID, ok := sessionUserID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Name       string          `json:"name"`
+		Credential json.RawMessage `json:"credential" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 요청입니다", err)
+		return
+	}
+	user, err := h.authService.GetCurrentUser(userID)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "회원 정보를 찾을 수 없습니다", err)
+		return
+	}
+	cred, err := h.webauthn.FinishRegistration(user, req.Credential, req.Name)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, common.V2Response{Success: true, Data: cred})
+}
+
+// BeginLogin handles POST /api/v2/auth/webauthn/login/begin
+// 아이디 없이 시작한다 — 브라우저가 이 사이트에 등록된 패스키 중 하나를 고르게 한다.
+func (h *WebAuthnHandler) BeginLogin(c *gin.Context) {
+	options, sessionID, err := h.webauthn.BeginLogin()
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "패스키 로그인을 시작하지 못했습니다", err)
+		return
+	}
+	common.V2Success(c, gin.H{"session_id": sessionID, "options": options})
+}
+
+// FinishLogin handles POST /api/v2/auth/webauthn/login/finish
+// Body: {"session_id": "...", "credential": navigator.credentials.get() 결과, "platform": "mobile"}
+// 응답은 POST /auth/login 과 같다(숙려 분기·mfa_required 포함).
+func (h *WebAuthnHandler) FinishLogin(c *gin.Context) {
+	var req struct {
+		SessionID  string          `json:"session_id" binding:"required"`
+		Credential json.RawMessage `json:"credential" binding:"required"`
+		Platform   string          `json:"platform"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 요청입니다", err)
+		return
+	}
+	user, userVerified, err := h.webauthn.FinishLogin(req.SessionID, req.Credential)
+	if err != nil {
+		if errors.Is(err, v2svc.ErrPasskeyInvalid) {
+			common.V2ErrorResponse(c, http.StatusUnauthorized, "패스키 인증에 실패했습니다", nil)
+			return
+		}
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "패스키 인증을 처리하지 못했습니다", err)
+		return
+	}
+	resp, err := h.authService.PasskeyLogin(user, userVerified, sessionMeta(c, req.Platform))
+	if err != nil {
+		if errors.Is(err, common.ErrAccountWithdrawn) {
+			c.JSON(http.StatusForbidden, common.V2Response{
+				Success: false,
+				Error:   &common.V2Error{Code: "account_withdrawn", Message: "탈퇴 처리된 계정입니다."},
+			})
+			return
+		}
+		common.V2ErrorResponse(c, http.StatusUnauthorized, "로그인에 실패했습니다", err)
+		return
+	}
+	respondLogin(c, resp, req.Platform == "mobile")
+}
+
+// List handles GET /api/v2/auth/webauthn/credentials
+func (h *WebAuthnHandler) List(c *gin.Context) {
+	userID, ok := sessionUserID(c)
+	if !ok {
+		return
+	}
+	creds, err := h.webauthn.List(userID)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "패스키 목록을 불러오지 못했습니다", err)
+		return
+	}
+	common.V2Success(c, creds)
+}
+
+// Rename handles PATCH /api/v2/auth/webauthn/credentials/:id
+// Body: {"name": "회사 노트북"}
+func (h *WebAuthnHandler) Rename(c *gin.Context) {
+	userID, id, ok := h.credentialParam(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "이름을 입력해주세요", err)
+		return
+	}
+	name, err := h.webauthn.Rename(userID, id, req.Name)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"id": id, "name": name})
+}
+
+// Delete handles DELETE /api/v2/auth/webauthn/credentials/:id
+func (h *WebAuthnHandler) Delete(c *gin.Context) {
+	userID, id, ok := h.credentialParam(c)
+	if !ok {
+		return
+	}
+	if err := h.webauthn.Delete(userID, id); err != nil {
+		h.fail(c, err)
+		return
+	}
+	common.V2Success(c, gin.H{"deleted": true})
+}
+
+func (h *WebAuthnHandler) credentialParam(c *gin.Context) (userID, id uint64, ok bool) {
+	if userID, ok = sessionUserID(c); !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.V2ErrorResponse(c, http.StatusBadRequest, "잘못된 패스키 ID 입니다", err)
+		return 0, 0, false
+	}
+	return userID, id, true
+}
+
+func (h *WebAuthnHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, v2svc.ErrPasskeyStepUpRequired):
+		c.JSON(http.StatusForbidden, common.V2Response{
+			Success: false,
+			Error:   &common.V2Error{Code: "mfa_required", Message: "2단계 인증을 다시 확인한 뒤 패스키를 추가할 수 있습니다"},
+		})
+	case errors.Is(err, v2svc.ErrPasskeyInvalid):
+		common.V2ErrorResponse(c, http.StatusBadRequest, "패스키를 확인하지 못했습니다. 다시 시도해주세요", nil)
+	case errors.Is(err, v2svc.ErrPasskeyName):
+		common.V2ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, v2svc.ErrPasskeyLimit), errors.Is(err, v2svc.ErrPasskeyExists):
+		common.V2ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, v2svc.ErrPasskeyNotFound):
+		common.V2ErrorResponse(c, http.StatusNotFound, "패스키를 찾을 수 없습니다", nil)
+	default:
+		common.V2ErrorResponse(c, http.StatusInternalServerError, "패스키 처리에 실패했습니다", err)
+	}
+}
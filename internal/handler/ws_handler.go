@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/damoang/angple-backend/internal/middleware"
@@ -10,9 +11,16 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// WSResumer replays what a reconnecting client missed (worker.NotiRealtimeWorker)
+type WSResumer interface {
+	Missed(mbID string, afterPhID int) []*ws.Event
+	UnreadCount(mbID string) *ws.Event
+}
+
 // WSHandler handles WebSocket connections
 type WSHandler struct {
 	hub            *ws.Hub
+	resumer        WSResumer
 	allowedOrigins []string
 	upgrader       websocket.Upgrader
 }
@@ -31,6 +39,11 @@ func NewWSHandler(hub *ws.Hub, allowedOrigins string) *WSHandler {
 	return h
 }
 
+// SetResumer enables ?resume=<id> and the unread_count sent on connect
+func (h *WSHandler) SetResumer(resumer WSResumer) {
+	h.resumer = resumer
+}
+
 // parseOrigins parses comma-separated origins string
 func parseOrigins(origins string) []string {
 	if origins == "" {
@@ -70,8 +83,11 @@ func (h *WSHandler) checkOrigin(r *http.Request) bool {
 }
 
 // Connect handles GET /ws/notifications — WebSocket upgrade
+// ?resume=<마지막으로 받은 이벤트 id> 를 주면 그 뒤 알림을 먼저 보낸다(많으면 resync 하나).
+// 접속 직후엔 항상 unread_count 를 보낸다 — 끊겨 있던 동안의 뱃지를 맞춘다.
 // @Summary 실시간 알림 WebSocket
 // @Tags notifications
+// @Param resume query int false "마지막으로 받은 notification 이벤트 id"
 // @Router /ws/notifications [get]
 func (h *WSHandler) Connect(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -85,9 +101,79 @@ func (h *WSHandler) Connect(c *gin.Context) {
 		return
 	}
 
-	client := ws.NewClient(h.hub, conn, userID)
+	client := ws.NewClient(h.hub, conn, ws.Viewer{
+		MemberID: userID,
+		Level:    middleware.GetUserLevel(c),
+		Nick:     middleware.GetNickname(c),
+	})
 	h.hub.Register(client)
 
 	go client.WritePump()
 	go client.ReadPump()
+
+	if h.resumer != nil {
+		// 등록 뒤에 읽는다 — 그 사이 생긴 알림은 실시간·재전송 양쪽으로 올 수 있어 클라이언트가 id 로 거른다
+		afterID, _ := strconv.Atoi(c.Query("resume"))
+		events := h.resumer.Missed(userID, afterID)
+		h.hub.SendToClient(client, append(events, h.resumer.UnreadCount(userID))...)
+	}
+}
+
+// Stream handles GET /api/v2/stream — WebSocket 이 막힌 망을 위한 SSE 대체 통로.
+// /ws/notifications 와 같은 이벤트가 같은 봉투로 온다. 토픽은 ?topics=post:free:1,board:free 로 구독한다.
+// 재접속하면 EventSource 가 보내는 Last-Event-ID(또는 ?last_event_id=)로 놓친 이벤트를 먼저 보낸다 —
+// 재생 범위(약 5분)를 넘었으면 resync 하나. 이벤트 id·seq 가 같은 것이 두 번 오면 무시할 것.
+// @Summary 실시간 이벤트 SSE
+// @Tags notifications
+// @Produce text/event-stream
+// @Param topics query string false "구독할 토픽 (쉼표로 구분)"
+// @Param last_event_id query string false "Last-Event-ID 헤더를 보낼 수 없을 때"
+// @Router /api/v2/stream [get]
+func (h *WSHandler) Stream(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "로그인이 필요합니다"})
+		return
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	cursor := ws.ParseSSECursor(lastEventID)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx 버퍼링 끔
+	c.Status(http.StatusOK)
+
+	client := ws.NewClient(h.hub, nil, ws.Viewer{
+		MemberID: userID,
+		Level:    middleware.GetUserLevel(c),
+		Nick:     middleware.GetNickname(c),
+	})
+	h.hub.Register(client)
+
+	var topics []string
+	for _, t := range strings.Split(c.Query("topics"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			topics = append(topics, t)
+		}
+	}
+	topics = client.SubscribeAll(topics)
+
+	// 등록 뒤에 읽는다 — 겹친 이벤트는 클라이언트가 id·seq 로 거른다
+	events, ok := client.Replay(cursor.Seq, topics)
+	if !ok {
+		events = []*ws.Event{{Type: "resync"}}
+	}
+	if h.resumer != nil {
+		events = append(events, h.resumer.Missed(userID, cursor.NotiID)...)
+		events = append(events, h.resumer.UnreadCount(userID))
+	}
+	if len(events) > 0 {
+		h.hub.SendToClient(client, events...)
+	}
+
+	client.ServeSSE(c.Request.Context(), c.Writer, cursor)
 }
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"github.com/damoang/angple-backend/internal/ws"
+	"gorm.io/gorm"
+)
+
+// WSTopicAccess applies the REST read rules to WebSocket topic subscriptions (ws.TopicAccess).
+//
+//   - 게시판 레벨: 목록 토픽(board:)은 bo_list_level, 글·나눔 토픽은 bo_read_level — 비회원=레벨1 (bug/13348 게이트와 같다)
+//   - 글: 댓글·삭제글은 구독할 수 없다. 비밀글은 작성자·관리자만 — REST 는 본문을 비우지만 실시간 댓글은
+//     본문 없이도 흐름을 드러내므로 아예 막는다.
+//   - 차단: 구독자가 글/댓글 차단한 회원이 만든 이벤트는 Hub 가 거른다 (ContentBlocked)
+type WSTopicAccess struct {
+	db     *gorm.DB
+	boards gnurepo.BoardRepository
+	// blockedIDs 는 main 의 getBlockedIDs 다 (Redis 5분 캐시, 콘텐츠 스코프만)
+	blockedIDs func(ctx context.Context, mbID string) []string
+}
+
+// NewWSTopicAccess creates a new WSTopicAccess
+func NewWSTopicAccess(db *gorm.DB, boards gnurepo.BoardRepository, blockedIDs func(ctx context.Context, mbID string) []string) *WSTopicAccess {
+	return &WSTopicAccess{db: db, boards: boards, blockedIDs: blockedIDs}
+}
+
+// Authorize implements ws.TopicAccess
+func (a *WSTopicAccess) Authorize(viewer ws.Viewer, topic ws.Topic) error {
+	board, err := a.boards.FindByID(topic.Board)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ws.ErrTopicForbidden
+	}
+	if err != nil {
+		return err
+	}
+	level := max(viewer.Level, 1) // 비회원=레벨1 (그누보드 규약)
+	required := board.BoReadLevel
+	if topic.Kind == ws.TopicBoard {
+		required = board.BoListLevel
+	}
+	if level < required {
+		return ws.ErrTopicForbidden
+	}
+	if topic.Kind == ws.TopicBoard {
+		return nil
+	}
+
+	// ParseTopic 이 slug 형식을, FindByID 가 존재를 확인했다 — 테이블 이름으로 써도 된다
+	var post struct {
+		MbID        string     `gorm:"column:mb_id"`
+		WrIsComment int        `gorm:"column:wr_is_comment"`
+		WrOption    string     `gorm:"column:wr_option"`
+		WrDeletedAt *time.Time `gorm:"column:wr_deleted_at"`
+	}
+	err = a.db.Table("g5_write_"+board.BoTable).
+		Select("mb_id, wr_is_comment, wr_option, wr_deleted_at").
+		Where("wr_id = ?", topic.WrID).Take(&post).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ws.ErrTopicForbidden
+	}
+	if err != nil {
+		return err
+	}
+	if post.WrIsComment != 0 || post.WrDeletedAt != nil {
+		return ws.ErrTopicForbidden
+	}
+	if strings.Contains(post.WrOption, "secret") && viewer.MemberID != post.MbID && viewer.Level < 10 {
+		return ws.ErrTopicForbidden
+	}
+	return nil
+}
+
+// ContentBlocked implements ws.TopicAccess
+func (a *WSTopicAccess) ContentBlocked(mbID string) []string {
+	if a.blockedIDs == nil {
+		return nil
+	}
+	return a.blockedIDs(context.Background(), mbID)
+}
@@ -19,6 +19,31 @@ func RequireLocalhost() gin.HandlerFunc {
 	}
 }
 
+// adminMFARequired — 관리자(레벨 10 이상) 라우트에 2단계 인증을 요구하는 정책 스위치
+var adminMFARequired bool
+
+// SetAdminMFARequired turns the admin 2FA policy on or off (config mfa.require_for_admins).
+//
+// 켜면 RequireAdmin 은 2단계 인증을 거친 토큰(mfa 클레임)만 통과시킨다. 관리자는 회원 익명화·차단·
+// 포인트 지급을 할 수 있어 비밀번호 하나로 지키기엔 무겁다. 아직 등록하지 않은 관리자는
+// /api/v2/auth/mfa 에서 등록한 뒤 /api/v2/auth/mfa/challenge 로 토큰을 올리면 된다
+// (등록·step-up 라우트는 RequireAdmin 밖이라 막히지 않는다).
+//
+// ⛔ 라우팅 개시(router.Run) 전에 부를 것.
+func SetAdminMFARequired(required bool) {
+	adminMFARequired = required
+}
+
+// AdminMFARequired reports whether the admin 2FA policy is on
+func AdminMFARequired() bool {
+	return adminMFARequired
+}
+
+// HasMFA reports whether the request's credentials passed a second factor
+func HasMFA(c *gin.Context) bool {
+	return c.GetBool("mfa")
+}
+
 // RequireAdmin checks that the authenticated user has admin level (>= 10)
 func RequireAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -28,6 +53,16 @@ func RequireAdmin() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		// API 키는 2단계를 거친 세션에서 발급·교체된 것만 mfa 가 실린다(APIKeyPrincipal.MFA) —
+		// 정책을 켜기 전에 만든 키와 평문에서 이관된 키는 2단계 세션에서 교체하기 전까지 막힌다
+		if adminMFARequired && !HasMFA(c) {
+			c.JSON(http.StatusForbidden, common.V2Response{
+				Success: false,
+				Error:   &common.V2Error{Code: "mfa_required", Message: "관리자 기능은 2단계 인증 후 사용할 수 있습니다"},
+			})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
@@ -69,3 +69,45 @@ func TestRequireAdmin_NoLevel(t *testing.T) {
 		t.Errorf("expected 403, got %d", w.Code)
 	}
 }
+
+func TestRequireAdmin_MFAPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	SetAdminMFARequired(true)
+	defer SetAdminMFARequired(false)
+
+	cases := []struct {
+		name       string
+		mfa        bool
+		authMethod string
+		want       int
+	}{
+		{"password-only admin", false, "", http.StatusForbidden},
+		{"admin after second factor", true, "", http.StatusOK},
+		{"admin API key issued without second factor", false, "api_key", http.StatusForbidden},
+		{"admin API key issued after second factor", true, "api_key", http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			r.Use(func(c *gin.Context) {
+				c.Set("level", 10)
+				c.Set("mfa", tc.mfa)
+				if tc.authMethod != "" {
+					c.Set("auth_method", tc.authMethod)
+				}
+				c.Next()
+			})
+			r.Use(RequireAdmin())
+			r.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"ok": true})
+			})
+
+			req, _ := http.NewRequest("GET", "/test", nil)
+			r.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Errorf("expected %d, got %d", tc.want, w.Code)
+			}
+		})
+	}
+}
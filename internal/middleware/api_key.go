@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/damoang/angple-backend/internal/common"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// API 키 인증 — 봇·앱 서버 잡이 회원 JWT 를 빌려 쓰지 않도록 하는 장기 자격증명.
+//
+// 키는 `Authorization: Bearer ak_...` 또는 `X-API-Key: ak_...` 로 받는다. JWTAuth·OptionalJWTAuth 가
+// 키를 알아보면 여기로 넘긴다(탈퇴 게이트와 같은 패키지 레벨 주입 — 호출처 시그니처 불변).
+//
+// ⛔ 키로 들어올 수 있는 경로는 apiKeyRouteScopes 에 적힌 라우트 그룹뿐이다(기본 거부).
+//    새 그룹을 키에 열 때는 여기에 스코프를 함께 적을 것 — JWTAuth 만 붙이면 키로는 403 이다.
+
+// API key scopes
+const (
+	ScopePostsRead          = "posts:read"
+	ScopePostsWrite         = "posts:write"
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+	ScopeAdmin              = "admin:*"
+)
+
+// APIKeyScopes lists every scope a key may be issued with
+var APIKeyScopes = []string{ScopePostsRead, ScopePostsWrite, ScopeNotificationsRead, ScopeNotificationsWrite, ScopeAdmin}
+
+// APIKeyPrefix marks API keys in the Authorization header
+const APIKeyPrefix = "ak_"
+
+// ErrAPIKeyInvalid is returned by an APIKeyAuthenticator for unknown, revoked or expired keys
+var ErrAPIKeyInvalid = errors.New("invalid API key")
+
+// APIKeyPrincipal is the member and grants behind a valid key
+type APIKeyPrincipal struct {
+	KeyID     int64
+	UserID    string // v2_users.id (없으면 mb_id) — Bearer 경로의 userID 와 같은 의미
+	Username  string // g5_member.mb_id
+	Nickname  string
+	Level     int
+	Scopes    []string
+	RateLimit int // 분당 요청 수, 0 이면 제한 없음
+	// MFA 는 키가 2단계 인증을 거친 세션에서 발급·교체됐는지다 — 관리자 MFA 정책(RequireAdmin)이 본다
+	MFA bool
+}
+
+// APIKeyAuthenticator resolves a raw key; clientIP is recorded as the key's last use
+type APIKeyAuthenticator func(ctx context.Context, rawKey, clientIP string) (*APIKeyPrincipal, error)
+
+var (
+	apiKeyAuthenticator APIKeyAuthenticator
+	apiKeyRedis         *redis.Client
+)
+
+// SetAPIKeyAuth enables API key authentication in JWTAuth / OptionalJWTAuth.
+// rdb 가 있으면 키마다 분당 요청 수를 제한한다(RateLimit 과 같은 슬라이딩 윈도).
+//
+// ⛔ 라우팅 개시(router.Run) 전에 부를 것. 주입하지 않으면 ak_ 키는 일반 토큰처럼 검증되어 401 이다.
+func SetAPIKeyAuth(fn APIKeyAuthenticator, rdb *redis.Client) {
+	apiKeyAuthenticator = fn
+	apiKeyRedis = rdb
+}
+
+type apiKeyRouteScope struct {
+	prefix string
+	read   string // GET·HEAD
+	write  string // 그 밖의 메서드, 비어 있으면 키로는 쓰기 불가
+}
+
+// apiKeyRouteScopes maps route groups to the scope a key needs (위에서부터 먼저 맞는 것)
+var apiKeyRouteScopes = []apiKeyRouteScope{
+	{prefix: "/api/v1/admin", read: ScopeAdmin, write: ScopeAdmin},
+	{prefix: "/api/v2/admin", read: ScopeAdmin, write: ScopeAdmin},
+	{prefix: "/api/v1/notifications", read: ScopeNotificationsRead, write: ScopeNotificationsWrite},
+	{prefix: "/api/v2/notifications", read: ScopeNotificationsRead, write: ScopeNotificationsWrite},
+	{prefix: "/api/v1/boards", read: ScopePostsRead, write: ScopePostsWrite},
+	{prefix: "/api/v2/boards", read: ScopePostsRead, write: ScopePostsWrite},
+	{prefix: "/api/v2/feed", read: ScopePostsRead},
+	{prefix: "/api/v1/search", read: ScopePostsRead},
+	{prefix: "/api/v2/search", read: ScopePostsRead},
+}
+
+// requiredAPIKeyScope returns the scope a key needs for the route; false 면 키로는 막힌 경로다.
+// 매칭은 c.FullPath()(라우트 패턴)로 한다 — 탈퇴 게이트 allowlist 와 같은 이유.
+func requiredAPIKeyScope(method, fullPath string) (string, bool) {
+	for _, r := range apiKeyRouteScopes {
+		if fullPath != r.prefix && !strings.HasPrefix(fullPath, r.prefix+"/") {
+			continue
+		}
+		scope := r.read
+		if method != http.MethodGet && method != http.MethodHead {
+			scope = r.write
+		}
+		return scope, scope != ""
+	}
+	return "", false
+}
+
+// ScopeAllows reports whether granted covers required ("admin:*" 는 admin:<무엇이든> 을 덮는다)
+func ScopeAllows(granted []string, required string) bool {
+	resource, _, _ := strings.Cut(required, ":")
+	for _, g := range granted {
+		if g == required || g == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// extractAPIKey returns the key carried by the request, if any
+func extractAPIKey(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader("X-API-Key")); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && strings.HasPrefix(token, APIKeyPrefix) {
+		return token
+	}
+	return ""
+}
+
+// authenticateAPIKey handles a request carrying an API key. handled 가 false 면 키가 없는
+// 요청이라 호출자가 평소 인증을 이어 가고, true 면 여기서 통과(c.Next) 또는 거절(Abort)까지 끝냈다.
+func authenticateAPIKey(c *gin.Context) (handled bool) {
+	if apiKeyAuthenticator == nil {
+		return false
+	}
+	rawKey := extractAPIKey(c)
+	if rawKey == "" {
+		return false
+	}
+
+	principal, err := apiKeyAuthenticator(c.Request.Context(), rawKey, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyInvalid) {
+			common.ErrorResponse(c, http.StatusUnauthorized, "유효하지 않은 API 키입니다", nil)
+		} else {
+			common.ErrorResponse(c, http.StatusInternalServerError, "API 키 확인에 실패했습니다", err)
+		}
+		c.Abort()
+		return true
+	}
+
+	scope, ok := requiredAPIKeyScope(c.Request.Method, c.FullPath())
+	if !ok {
+		common.ErrorResponse(c, http.StatusForbidden, "API 키로는 사용할 수 없는 경로입니다", nil)
+		c.Abort()
+		return true
+	}
+	if !ScopeAllows(principal.Scopes, scope) {
+		c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+		common.ErrorResponse(c, http.StatusForbidden, "API 키에 "+scope+" 권한이 없습니다", nil)
+		c.Abort()
+		return true
+	}
+	if principal.RateLimit > 0 && apiKeyRedis != nil {
+		cfg := RateLimitConfig{
+			RequestsPerMinute: principal.RateLimit,
+			KeyPrefix:         "api:ratelimit:key:",
+			Message:           "API 키 요청 한도를 넘었습니다. 잠시 후 다시 시도해주세요.",
+		}
+		if !allowRequest(c, apiKeyRedis, cfg, strconv.FormatInt(principal.KeyID, 10)) {
+			return true
+		}
+	}
+
+	c.Set("userID", principal.UserID)
+	c.Set("username", principal.Username)
+	c.Set("nickname", principal.Nickname)
+	c.Set("level", principal.Level)
+	c.Set("v2_user_id", principal.UserID)
+	c.Set("auth_method", "api_key")
+	c.Set("api_key_id", principal.KeyID)
+	c.Set("mfa", principal.MFA)
+
+	// 탈퇴 게이트 — 키도 회원 권한으로 움직이므로 JWT 분기와 같이 막는다
+	if blockIfWithdrawn(c, principal.Username) {
+		return true
+	}
+	c.Next()
+	return true
+}
+
+// GetAPIKeyID returns the API key that authenticated the request (0 이면 키 인증이 아니다)
+func GetAPIKeyID(c *gin.Context) int64 {
+	if id, ok := c.Get("api_key_id"); ok {
+		if v, ok := id.(int64); ok {
+			return v
+		}
+	}
+	return 0
+}
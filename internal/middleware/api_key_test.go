@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+func TestScopeAllows(t *testing.T) {
+	cases := []struct {
+		granted  []string
+		required string
+		want     bool
+	}{
+		{[]string{ScopePostsRead}, ScopePostsRead, true},
+		{[]string{ScopePostsRead}, ScopePostsWrite, false},
+		{[]string{ScopeAdmin}, ScopeAdmin, true},
+		{[]string{ScopeAdmin}, ScopePostsRead, false},
+		{[]string{"posts:*"}, ScopePostsWrite, true},
+		{nil, ScopePostsRead, false},
+	}
+	for _, tc := range cases {
+		if got := ScopeAllows(tc.granted, tc.required); got != tc.want {
+			t.Errorf("ScopeAllows(%v, %s) = %v, want %v", tc.granted, tc.required, got, tc.want)
+		}
+	}
+}
+
+func TestRequiredAPIKeyScope(t *testing.T) {
+	cases := []struct {
+		method, path string
+		want         string
+		ok           bool
+	}{
+		{"GET", "/api/v1/boards/:slug/posts", ScopePostsRead, true},
+		{"POST", "/api/v1/boards/:slug/posts", ScopePostsWrite, true},
+		{"GET", "/api/v2/notifications/unread-count", ScopeNotificationsRead, true},
+		{"DELETE", "/api/v2/admin/members/:id", ScopeAdmin, true},
+		{"POST", "/api/v2/search/saved", "", false},     // 검색은 읽기 전용
+		{"GET", "/api/v2/administrators", "", false},    // 접두가 경로 단위로만 맞는다
+		{"POST", "/api/v2/auth/api-keys", "", false},    // 키로 키를 만들 수 없다
+		{"GET", "/api/v2/members/me/blocks", "", false}, // 목록에 없는 그룹은 거부
+	}
+	for _, tc := range cases {
+		got, ok := requiredAPIKeyScope(tc.method, tc.path)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("%s %s = (%q, %v), want (%q, %v)", tc.method, tc.path, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestJWTAuthAcceptsAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	SetAPIKeyAuth(func(_ context.Context, rawKey, _ string) (*APIKeyPrincipal, error) {
+		if rawKey != "ak_good" {
+			return nil, ErrAPIKeyInvalid
+		}
+		return &APIKeyPrincipal{KeyID: 7, UserID: "42", Username: "bot", Level: 2, Scopes: []string{ScopePostsRead}}, nil
+	}, nil)
+	defer SetAPIKeyAuth(nil, nil)
+
+	r := gin.New()
+	r.Use(JWTAuth(jwt.NewManager("test-secret", 900, 3600)))
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"username": GetUsername(c), "key": GetAPIKeyID(c)})
+	}
+	r.GET("/api/v1/boards/:slug/posts", handler)
+	r.POST("/api/v1/boards/:slug/posts", handler)
+	r.GET("/api/v2/members/me/blocks", handler)
+
+	cases := []struct {
+		name, method, path string
+		header, value      string
+		want               int
+	}{
+		{"bearer key", "GET", "/api/v1/boards/free/posts", "Authorization", "Bearer ak_good", http.StatusOK},
+		{"x-api-key", "GET", "/api/v1/boards/free/posts", "X-API-Key", "ak_good", http.StatusOK},
+		{"unknown key", "GET", "/api/v1/boards/free/posts", "X-API-Key", "ak_bad", http.StatusUnauthorized},
+		{"missing scope", "POST", "/api/v1/boards/free/posts", "X-API-Key", "ak_good", http.StatusForbidden},
+		{"closed route", "GET", "/api/v2/members/me/blocks", "X-API-Key", "ak_good", http.StatusForbidden},
+		{"plain jwt still checked", "GET", "/api/v1/boards/free/posts", "Authorization", "Bearer not-a-jwt", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set(tc.header, tc.value)
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: got %d, want %d (%s)", tc.name, w.Code, tc.want, w.Body.String())
+		}
+	}
+}
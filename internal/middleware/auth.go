@@ -76,6 +76,8 @@ func JWTAuth(jwtManager *jwt.Manager) gin.HandlerFunc {
 				c.Set("nickname", "")
 				c.Set("level", level)
 				c.Set("v2_user_id", userID)
+				// web 세션이 2단계 인증을 거쳤으면 web 이 알려 준다(관리자 MFA 정책 — admin.go)
+				c.Set("mfa", c.GetHeader("X-Internal-User-MFA") == "1")
 				// 탈퇴 게이트 — ⛔ 이 분기를 빠뜨리면 우회로가 된다(JWT 검증을 건너뛰는 경로).
 				//    여기서는 X-Internal-User-ID 가 곧 mb_id 다.
 				if blockIfWithdrawn(c, userID) {
@@ -86,6 +88,11 @@ func JWTAuth(jwtManager *jwt.Manager) gin.HandlerFunc {
 			}
 		}
 
+		// API 키 (Bearer ak_... 또는 X-API-Key) — 스코프·키별 한도는 api_key.go
+		if authenticateAPIKey(c) {
+			return
+		}
+
 		var token string
 
 		// 1. Authorization 헤더에서 토큰 확인
@@ -117,7 +124,15 @@ func JWTAuth(jwtManager *jwt.Manager) gin.HandlerFunc {
 
 		// 4. 토큰 검증
 		claims, err := jwtManager.VerifyToken(token)
-		if err != nil {
+		// ⛔ 단일 용도 티켓(2단계 인증 대기 등)은 같은 키로 서명된다 — access token 자리에 받으면
+		//    비밀번호만으로 받은 티켓이 2단계 인증을 건너뛴다.
+		if err != nil || claims.Ticket {
+			common.ErrorResponse(c, 401, "로그인이 필요합니다", nil)
+			c.Abort()
+			return
+		}
+		// 로그아웃·강제 로그아웃된 세션의 토큰 — session_check.go
+		if isSessionRevoked(c, claims.SessionID) {
 			common.ErrorResponse(c, 401, "로그인이 필요합니다", nil)
 			c.Abort()
 			return
@@ -128,6 +143,8 @@ func JWTAuth(jwtManager *jwt.Manager) gin.HandlerFunc {
 		c.Set("nickname", claims.Nickname)
 		c.Set("level", claims.Level)
 		c.Set("v2_user_id", claims.UserID)
+		c.Set("session_id", claims.SessionID)
+		c.Set("mfa", claims.MFA)
 
 		// 탈퇴 게이트 — ⛔ 판정 키는 username(mb_id) 이다.
 		//    claims.UserID 는 v2_users.id(숫자)라 g5_member 조회에 쓸 수 없다.
@@ -169,6 +186,11 @@ func OptionalJWTAuth(jwtManager *jwt.Manager) gin.HandlerFunc {
 			}
 		}
 
+		// API 키 (Bearer ak_... 또는 X-API-Key) — 스코프·키별 한도는 api_key.go
+		if authenticateAPIKey(c) {
+			return
+		}
+
 		var token string
 
 		// 1. Authorization 헤더에서 토큰 확인
@@ -190,14 +212,16 @@ func OptionalJWTAuth(jwtManager *jwt.Manager) gin.HandlerFunc {
 		// 3. 토큰이 있으면 검증
 		if token != "" {
 			claims, err := jwtManager.VerifyToken(token)
-			if err == nil {
+			if err == nil && !claims.Ticket && !isSessionRevoked(c, claims.SessionID) {
 				c.Set("userID", claims.UserID)
 				c.Set("username", claims.Username)
 				c.Set("nickname", claims.Nickname)
 				c.Set("level", claims.Level)
 				c.Set("v2_user_id", claims.UserID)
+				c.Set("session_id", claims.SessionID)
+				c.Set("mfa", claims.MFA)
 			}
-			// 토큰 검증 실패 시 무시 (비인증 상태로 계속)
+			// 토큰 검증 실패·폐기된 세션·티켓은 무시 (비인증 상태로 계속)
 		}
 
 		c.Next()
@@ -98,46 +98,50 @@ func RateLimit(redisClient *redis.Client, cfg RateLimitConfig) gin.HandlerFunc {
 			return
 		}
 
-		clientIP := c.ClientIP()
-		key := cfg.KeyPrefix + clientIP
+		if !allowRequest(c, redisClient, cfg, c.ClientIP()) {
+			return
+		}
+		c.Next()
+	}
+}
 
-		now := time.Now().UnixMilli()
-		windowMs := int64(60 * 1000) // 1 minute
+// allowRequest counts one request against cfg.KeyPrefix+id and sets the X-RateLimit-* headers.
+// 한도를 넘으면 429 로 끊고 false 를 돌려준다. Redis 오류는 통과(fail open)다.
+func allowRequest(c *gin.Context, redisClient *redis.Client, cfg RateLimitConfig, id string) bool {
+	now := time.Now().UnixMilli()
+	windowMs := int64(60 * 1000) // 1 minute
 
-		ctx := context.Background()
-		result, err := rateLimitScript.Run(ctx, redisClient, []string{key},
-			cfg.RequestsPerMinute, windowMs, now,
-		).Int64Slice()
+	ctx := context.Background()
+	result, err := rateLimitScript.Run(ctx, redisClient, []string{cfg.KeyPrefix + id},
+		cfg.RequestsPerMinute, windowMs, now,
+	).Int64Slice()
 
-		if err != nil {
-			// Fail open — allow request if Redis error
-			c.Next()
-			return
-		}
+	if err != nil {
+		// Fail open — allow request if Redis error
+		return true
+	}
 
-		allowed := result[0] == 1
-		remaining := result[1]
-		resetAt := result[2]
+	allowed := result[0] == 1
+	remaining := result[1]
+	resetAt := result[2]
 
-		c.Header("X-RateLimit-Limit", strconv.Itoa(cfg.RequestsPerMinute))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+	c.Header("X-RateLimit-Limit", strconv.Itoa(cfg.RequestsPerMinute))
+	c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
 
-		if !allowed {
-			retryAfter := (resetAt - now) / 1000
-			if retryAfter < 1 {
-				retryAfter = 1
-			}
-			c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", resetAt/1000))
-			c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"success": false,
-				"error":   gin.H{"code": "RATE_LIMITED", "message": cfg.Message},
-			})
-			return
+	if !allowed {
+		retryAfter := (resetAt - now) / 1000
+		if retryAfter < 1 {
+			retryAfter = 1
 		}
-
-		c.Next()
+		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", resetAt/1000))
+		c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"error":   gin.H{"code": "RATE_LIMITED", "message": cfg.Message},
+		})
+		return false
 	}
+	return true
 }
 
 // RateLimitPerUser returns a rate limiter keyed by user ID instead of IP
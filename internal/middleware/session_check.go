@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 서버 세션 폐기 게이트 — 로그아웃·다른 기기 로그아웃·관리자 강제 로그아웃이
+// 이미 발급된 access token(TTL 15분)에도 바로 닿게 한다.
+//
+// 세션에 묶인 토큰에는 sid 클레임이 있다. sid 가 없는 토큰(세션 도입 전 발급, 숙려 로그인)은
+// 여기서 보지 않는다 — 그 토큰은 리프레시 때 세션으로 옮겨지거나 만료로 사라진다.
+
+// SessionRevokedFunc reports whether the session (sid) has been revoked
+type SessionRevokedFunc func(ctx context.Context, sessionID string) bool
+
+var sessionRevoked SessionRevokedFunc
+
+// SetSessionCheck enables the revoked-session gate in JWTAuth / OptionalJWTAuth.
+//
+// ⛔ SetWithdrawalCheck 와 같은 패키지 레벨 주입이다(JWTAuth 호출처 시그니처 불변).
+// 라우팅 개시(router.Run) 전에 부를 것. 주입하지 않으면 sid 는 확인하지 않는다.
+func SetSessionCheck(fn SessionRevokedFunc) {
+	sessionRevoked = fn
+}
+
+// isSessionRevoked checks the token's session; sid 가 없거나 게이트가 꺼져 있으면 false.
+func isSessionRevoked(c *gin.Context, sessionID string) bool {
+	if sessionRevoked == nil || sessionID == "" {
+		return false
+	}
+	return sessionRevoked(c.Request.Context(), sessionID)
+}
+
+// GetSessionID returns the server session (sid) of the request's access token ("" 이면 세션 없는 인증)
+func GetSessionID(c *gin.Context) string {
+	if sid, ok := c.Get("session_id"); ok {
+		if str, ok := sid.(string); ok {
+			return str
+		}
+	}
+	return ""
+}
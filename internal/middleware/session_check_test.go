@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+func TestJWTAuthRejectsRevokedSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	SetSessionCheck(func(_ context.Context, sid string) bool { return sid == "revoked" })
+	defer SetSessionCheck(nil)
+
+	jwtManager := jwt.NewManager("test-secret", 900, 604800)
+	router := gin.New()
+	router.GET("/me", JWTAuth(jwtManager), func(c *gin.Context) {
+		c.String(http.StatusOK, GetSessionID(c))
+	})
+	router.GET("/feed", OptionalJWTAuth(jwtManager), func(c *gin.Context) {
+		c.String(http.StatusOK, GetUsername(c))
+	})
+
+	do := func(path, sid string) *httptest.ResponseRecorder {
+		token, err := jwtManager.GenerateSessionAccessToken("1", "zoe", "조", 2, sid, false)
+		if err != nil {
+			t.Fatalf("gen token: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("/me", "live"); w.Code != http.StatusOK || w.Body.String() != "live" {
+		t.Fatalf("live session: %d %q", w.Code, w.Body.String())
+	}
+	if w := do("/me", "revoked"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("revoked session should be 401, got %d", w.Code)
+	}
+	if w := do("/me", ""); w.Code != http.StatusOK {
+		t.Fatalf("token without sid should pass, got %d", w.Code)
+	}
+	// OptionalJWTAuth 는 폐기된 세션을 비로그인으로 본다
+	if w := do("/feed", "revoked"); w.Code != http.StatusOK || w.Body.String() != "" {
+		t.Fatalf("optional auth with revoked session: %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestJWTAuthRejectsTickets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtManager := jwt.NewManager("test-secret", 900, 604800)
+	router := gin.New()
+	router.GET("/me", JWTAuth(jwtManager), func(c *gin.Context) {
+		c.String(http.StatusOK, GetUserID(c))
+	})
+	router.GET("/feed", OptionalJWTAuth(jwtManager), func(c *gin.Context) {
+		c.String(http.StatusOK, GetUserID(c))
+	})
+
+	// 2단계 인증 대기 티켓은 같은 키로 서명되지만 로그인 토큰이 아니다
+	ticket, err := jwtManager.GenerateTicket("1", "mfa-login", "jti-1", time.Minute)
+	if err != nil {
+		t.Fatalf("gen ticket: %v", err)
+	}
+	for _, path := range []string{"/me", "/feed"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+ticket)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code == http.StatusOK && w.Body.String() != "" {
+			t.Fatalf("%s accepted a ticket as a login token", path)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// taskSchedule 다음 실행 시각 계산기 (cron 또는 고정 간격)
+type taskSchedule interface {
+	Next(after time.Time) time.Time
+}
+
+// CronSchedule 5필드 cron 표현식 (분 시 일 월 요일)
+//
+// 지원 문법: * , - / 와 월·요일 이름(JAN, MON), 그리고 @yearly @monthly @weekly @daily @hourly.
+// 일과 요일이 모두 지정되면 둘 중 하나만 맞아도 실행한다 (vixie cron 규칙).
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+	loc                           *time.Location
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{0, 59, nil}
+	cronHour   = cronField{0, 23, nil}
+	cronDom    = cronField{1, 31, nil}
+	cronMonth  = cronField{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	cronDow = cronField{0, 7, map[string]int{ // 0, 7 모두 일요일
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron cron 표현식 파싱. loc 가 nil 이면 UTC 기준.
+func ParseCron(expr string, loc *time.Location) (*CronSchedule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	spec := strings.TrimSpace(expr)
+	if d, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	c := &CronSchedule{loc: loc}
+	var err error
+	if c.minute, err = parseCronField(fields[0], cronMinute); err != nil {
+		return nil, fmt.Errorf("cron %q minute: %w", expr, err)
+	}
+	if c.hour, err = parseCronField(fields[1], cronHour); err != nil {
+		return nil, fmt.Errorf("cron %q hour: %w", expr, err)
+	}
+	if c.dom, err = parseCronField(fields[2], cronDom); err != nil {
+		return nil, fmt.Errorf("cron %q day-of-month: %w", expr, err)
+	}
+	if c.month, err = parseCronField(fields[3], cronMonth); err != nil {
+		return nil, fmt.Errorf("cron %q month: %w", expr, err)
+	}
+	if c.dow, err = parseCronField(fields[4], cronDow); err != nil {
+		return nil, fmt.Errorf("cron %q day-of-week: %w", expr, err)
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 // 7 → 일요일
+	}
+	c.domStar = strings.HasPrefix(fields[2], "*") || fields[2] == "?"
+	c.dowStar = strings.HasPrefix(fields[4], "*") || fields[4] == "?"
+	return c, nil
+}
+
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], s
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := f.value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			// "5/15" 처럼 step 이 붙으면 5부터 끝까지 15 간격
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q", part)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next after 이후 가장 가까운 실행 시각 (5년 안에 없으면 zero time)
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	t := after.In(c.loc)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, c.loc).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for c.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+	for !c.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+	for c.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.loc)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+	for c.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+	return t
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+// intervalSchedule 고정 간격. 모든 인스턴스가 같은 슬롯을 계산하도록 epoch 기준으로 정렬한다.
+type intervalSchedule time.Duration
+
+func (i intervalSchedule) Next(after time.Time) time.Time {
+	d := time.Duration(i)
+	return after.Truncate(d).Add(d)
+}
+
+// manualSchedule 자동 실행하지 않는 작업 (RunNow 전용)
+type manualSchedule struct{}
+
+func (manualSchedule) Next(time.Time) time.Time {
+	return time.Time{}
+}
@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	base := time.Date(2026, 3, 10, 12, 30, 15, 0, time.UTC) // 화요일
+
+	tests := []struct {
+		expr string
+		loc  *time.Location
+		want time.Time
+	}{
+		{"*/15 * * * *", nil, time.Date(2026, 3, 10, 12, 45, 0, 0, time.UTC)},
+		{"0 4 * * *", kst, time.Date(2026, 3, 11, 4, 0, 0, 0, kst)},
+		{"0 4 * * *", nil, time.Date(2026, 3, 11, 4, 0, 0, 0, time.UTC)},
+		{"@hourly", nil, time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC)},
+		{"@monthly", nil, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * MON-FRI", nil, time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", nil, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"30 2 1 jan *", nil, time.Date(2027, 1, 1, 2, 30, 0, 0, time.UTC)},
+		// 일·요일 모두 지정 → 둘 중 하나 (3/13 금요일이 15일보다 먼저)
+		{"0 0 15 * FRI", nil, time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"5/20 * * * *", nil, time.Date(2026, 3, 10, 12, 45, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr, tt.loc)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := c.Next(base); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * * funday",
+	} {
+		if _, err := ParseCron(expr, nil); err == nil {
+			t.Errorf("ParseCron(%q) expected error", expr)
+		}
+	}
+}
+
+func TestParseCron_NeverMatches(t *testing.T) {
+	c, err := ParseCron("0 0 30 2 *", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := c.Next(time.Now()); !next.IsZero() {
+		t.Errorf("expected zero time for Feb 30, got %s", next)
+	}
+}
+
+func TestIntervalSchedule_Aligned(t *testing.T) {
+	s := intervalSchedule(5 * time.Minute)
+	got := s.Next(time.Date(2026, 3, 10, 12, 31, 10, 0, time.UTC))
+	want := time.Date(2026, 3, 10, 12, 35, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("Next = %s, want %s", got, want)
+	}
+}
@@ -1,49 +1,204 @@
 package plugin
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"sync"
 	"time"
 )
 
 // Event 플러그인 간 이벤트
 type Event struct {
+	ID        string                 `json:"id,omitempty"` // 백엔드 오프셋 (memory: 일련번호, redis: 스트림 엔트리 ID)
 	Topic     string                 `json:"topic"`
 	Source    string                 `json:"source"` // 발행 플러그인
 	Payload   map[string]interface{} `json:"payload"`
 	Timestamp time.Time              `json:"timestamp"`
 }
 
-// EventHandler 이벤트 핸들러 함수
+// EventHandler 이벤트 핸들러 함수 (panic 은 전달 실패로 간주되어 재시도된다)
 type EventHandler func(event Event)
 
+// EventHandlerFunc 에러를 반환하는 이벤트 핸들러 - 에러면 RetryPolicy 에 따라 재시도 후 dead-letter
+type EventHandlerFunc func(event Event) error
+
+// RetryPolicy 구독자별 재시도 정책
+type RetryPolicy struct {
+	MaxAttempts    int           // 첫 시도 포함 총 시도 횟수
+	InitialBackoff time.Duration // 첫 재시도 대기 (이후 2배씩)
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy 기본 재시도 정책 (5회, 0.5s → 최대 30s)
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// backoff attempt 번째 실패 후 대기 시간
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff << (attempt - 1)
+	if d <= 0 || d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return d
+}
+
+// DeadLetter 재시도를 모두 소진한 전달 실패 이벤트
+type DeadLetter struct {
+	ID       string    `json:"id"`
+	Plugin   string    `json:"plugin"`
+	Event    Event     `json:"event"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// SubscriptionStatus 구독자별 전달 현황
+type SubscriptionStatus struct {
+	Plugin       string     `json:"plugin"`
+	Topic        string     `json:"topic"`
+	Delivered    int64      `json:"delivered"`
+	Retried      int64      `json:"retried"`
+	DeadLettered int64      `json:"dead_lettered"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+}
+
+// ErrNoSubscription 플러그인이 해당 토픽을 구독하지 않음
+var ErrNoSubscription = errors.New("plugin is not subscribed to topic")
+
+// EventBackend 이벤트 저장·전달 백엔드
+//
+// 그룹은 구독 플러그인 이름이다. 같은 그룹의 구독자가 여러 인스턴스에 있으면
+// 이벤트는 그중 하나에만 전달된다 (redis: 컨슈머 그룹).
+type EventBackend interface {
+	// Name 백엔드 이름 (memory | redis)
+	Name() string
+	// Publish 이벤트를 저장하고 오프셋(ID)을 반환한다
+	Publish(ctx context.Context, event Event) (string, error)
+	// Attach 그룹·토픽 구독 시작. handle 이 nil 을 반환하면 ack, 에러면 미처리로 남아 재전달된다.
+	Attach(group, topic string, handle func(Event) error) (detach func())
+	// Replay 그룹이 fromID 이후 이벤트를 다시 받도록 되감고, 재전달 대상 건수를 반환한다
+	Replay(ctx context.Context, group, topic, fromID string) (int, error)
+	// PushDeadLetter dead-letter 목록에 추가
+	PushDeadLetter(ctx context.Context, dl DeadLetter) error
+	// DeadLetters 최근 dead-letter 목록 (최신순)
+	DeadLetters(ctx context.Context, limit int) ([]DeadLetter, error)
+	// TakeDeadLetter dead-letter 1건을 꺼낸다 (없으면 nil)
+	TakeDeadLetter(ctx context.Context, id string) (*DeadLetter, error)
+	// Close 전달 루프 종료
+	Close() error
+}
+
 type subscription struct {
 	pluginName string
-	handler    EventHandler
+	topic      string
+	handler    EventHandlerFunc
+	detach     func()
+
+	mu           sync.Mutex
+	removed      bool
+	delivered    int64
+	retried      int64
+	deadLettered int64
+	lastError    string
+	lastErrorAt  time.Time
 }
 
 // EventBus 플러그인 간 이벤트 발행/구독 시스템
+//
+// 전달은 at-least-once 다. 핸들러가 에러를 내거나 panic 하면 구독자별로 backoff 재시도하고,
+// 모두 실패하면 dead-letter 로 옮긴다. 핸들러는 같은 이벤트를 두 번 받아도 안전해야 한다 (Event.ID 로 중복 제거).
 type EventBus struct {
-	subscribers map[string][]subscription // topic -> handlers
+	subscribers map[string][]*subscription // topic -> handlers
 	mu          sync.RWMutex
 	logger      Logger
+	backend     EventBackend
+	retry       RetryPolicy
+	ctx         context.Context
+	cancel      context.CancelFunc
 }
 
-// NewEventBus 생성자
+// NewEventBus 생성자 (in-memory 백엔드)
 func NewEventBus(logger Logger) *EventBus {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &EventBus{
-		subscribers: make(map[string][]subscription),
+		subscribers: make(map[string][]*subscription),
 		logger:      logger,
+		backend:     NewMemoryEventBackend(),
+		retry:       DefaultRetryPolicy(),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// SetBackend 백엔드 교체 - 기존 구독은 새 백엔드에 다시 연결된다
+func (eb *EventBus) SetBackend(backend EventBackend) {
+	eb.mu.Lock()
+	old := eb.backend
+	for _, subs := range eb.subscribers {
+		for _, s := range subs {
+			s.detach()
+		}
+	}
+	eb.backend = backend
+	for _, subs := range eb.subscribers {
+		for _, s := range subs {
+			s.detach = backend.Attach(s.pluginName, s.topic, eb.handlerFor(s))
+		}
+	}
+	eb.mu.Unlock()
+
+	if old != nil {
+		if err := old.Close(); err != nil {
+			eb.logger.Warn("Event backend %s close error: %v", old.Name(), err)
+		}
+	}
+	eb.logger.Info("Event bus backend: %s", backend.Name())
+}
+
+// SetRetryPolicy 재시도 정책 변경
+func (eb *EventBus) SetRetryPolicy(policy RetryPolicy) {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
 	}
+	eb.mu.Lock()
+	eb.retry = policy
+	eb.mu.Unlock()
+}
+
+// BackendName 현재 백엔드 이름
+func (eb *EventBus) BackendName() string {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+	return eb.backend.Name()
 }
 
 // Subscribe 토픽 구독
 func (eb *EventBus) Subscribe(pluginName, topic string, handler EventHandler) {
+	eb.SubscribeFunc(pluginName, topic, func(event Event) error {
+		handler(event)
+		return nil
+	})
+}
+
+// SubscribeFunc 에러를 반환하는 핸들러로 토픽 구독
+func (eb *EventBus) SubscribeFunc(pluginName, topic string, handler EventHandlerFunc) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
-	eb.subscribers[topic] = append(eb.subscribers[topic], subscription{
+	s := &subscription{
 		pluginName: pluginName,
+		topic:      topic,
 		handler:    handler,
-	})
+	}
+	s.detach = eb.backend.Attach(pluginName, topic, eb.handlerFor(s))
+	eb.subscribers[topic] = append(eb.subscribers[topic], s)
 	eb.logger.Debug("Plugin %s subscribed to topic: %s", pluginName, topic)
 }
 
@@ -52,11 +207,16 @@ func (eb *EventBus) Unsubscribe(pluginName string) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 	for topic, subs := range eb.subscribers {
-		var remaining []subscription
+		var remaining []*subscription
 		for _, s := range subs {
 			if s.pluginName != pluginName {
 				remaining = append(remaining, s)
+				continue
 			}
+			s.detach()
+			s.mu.Lock()
+			s.removed = true
+			s.mu.Unlock()
 		}
 		if len(remaining) == 0 {
 			delete(eb.subscribers, topic)
@@ -66,34 +226,26 @@ func (eb *EventBus) Unsubscribe(pluginName string) {
 	}
 }
 
-// Publish 이벤트 발행 (동기: 모든 핸들러 순차 실행)
+// Publish 이벤트 발행
+// 백엔드에 기록만 하고 돌아온다 — 구독자 핸들러·재시도는 구독자별 전달 루프에서 실행된다.
 func (eb *EventBus) Publish(source, topic string, payload map[string]interface{}) {
+	if _, err := eb.PublishEvent(eb.ctx, source, topic, payload); err != nil {
+		eb.logger.Error("Event publish failed [%s/%s]: %v", source, topic, err)
+	}
+}
+
+// PublishEvent 이벤트 발행 후 백엔드 오프셋 반환
+func (eb *EventBus) PublishEvent(ctx context.Context, source, topic string, payload map[string]interface{}) (string, error) {
 	eb.mu.RLock()
-	subs := make([]subscription, len(eb.subscribers[topic]))
-	copy(subs, eb.subscribers[topic])
+	backend := eb.backend
 	eb.mu.RUnlock()
 
-	if len(subs) == 0 {
-		return
-	}
-
-	event := Event{
+	return backend.Publish(ctx, Event{
 		Topic:     topic,
 		Source:    source,
 		Payload:   payload,
 		Timestamp: time.Now(),
-	}
-
-	for _, s := range subs {
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					eb.logger.Error("Event handler panicked [%s/%s → %s]: %v", source, topic, s.pluginName, r)
-				}
-			}()
-			s.handler(event)
-		}()
-	}
+	})
 }
 
 // PublishAsync 이벤트 비동기 발행
@@ -101,6 +253,74 @@ func (eb *EventBus) PublishAsync(source, topic string, payload map[string]interf
 	go eb.Publish(source, topic, payload)
 }
 
+// Replay 플러그인 구독을 fromID 이후로 되감아 재전달한다
+func (eb *EventBus) Replay(ctx context.Context, pluginName, topic, fromID string) (int, error) {
+	if eb.findSubscription(pluginName, topic) == nil {
+		return 0, fmt.Errorf("%w: %s/%s", ErrNoSubscription, pluginName, topic)
+	}
+	eb.mu.RLock()
+	backend := eb.backend
+	eb.mu.RUnlock()
+	return backend.Replay(ctx, pluginName, topic, fromID)
+}
+
+// DeadLetters 최근 dead-letter 목록
+func (eb *EventBus) DeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	eb.mu.RLock()
+	backend := eb.backend
+	eb.mu.RUnlock()
+	return backend.DeadLetters(ctx, limit)
+}
+
+// Redrive dead-letter 1건을 원래 구독자에게 다시 전달한다.
+// 이번에도 실패하면 시도 횟수를 늘려 dead-letter 로 되돌리고 에러를 반환한다.
+func (eb *EventBus) Redrive(ctx context.Context, id string) error {
+	eb.mu.RLock()
+	backend := eb.backend
+	eb.mu.RUnlock()
+
+	dl, err := backend.TakeDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+	if dl == nil {
+		return fmt.Errorf("dead letter %s not found", id)
+	}
+
+	s := eb.findSubscription(dl.Plugin, dl.Event.Topic)
+	if s == nil {
+		_ = backend.PushDeadLetter(ctx, *dl) //nolint:errcheck // restore as-is
+		return fmt.Errorf("%w: %s/%s", ErrNoSubscription, dl.Plugin, dl.Event.Topic)
+	}
+
+	if err := eb.attempt(s, dl.Event); err != nil {
+		dl.Attempts++
+		dl.Error = err.Error()
+		dl.FailedAt = time.Now()
+		if pushErr := backend.PushDeadLetter(ctx, *dl); pushErr != nil {
+			eb.logger.Error("Dead letter restore failed [%s/%s]: %v", dl.Plugin, dl.Event.Topic, pushErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// DiscardDeadLetter dead-letter 1건 삭제
+func (eb *EventBus) DiscardDeadLetter(ctx context.Context, id string) error {
+	eb.mu.RLock()
+	backend := eb.backend
+	eb.mu.RUnlock()
+
+	dl, err := backend.TakeDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+	if dl == nil {
+		return fmt.Errorf("dead letter %s not found", id)
+	}
+	return nil
+}
+
 // GetSubscriptions 구독 현황 조회
 func (eb *EventBus) GetSubscriptions() map[string][]string {
 	eb.mu.RLock()
@@ -113,3 +333,139 @@ func (eb *EventBus) GetSubscriptions() map[string][]string {
 	}
 	return result
 }
+
+// GetSubscriptionStatus 구독자별 전달 통계
+func (eb *EventBus) GetSubscriptionStatus() []SubscriptionStatus {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+	result := make([]SubscriptionStatus, 0)
+	for _, subs := range eb.subscribers {
+		for _, s := range subs {
+			s.mu.Lock()
+			st := SubscriptionStatus{
+				Plugin:       s.pluginName,
+				Topic:        s.topic,
+				Delivered:    s.delivered,
+				Retried:      s.retried,
+				DeadLettered: s.deadLettered,
+				LastError:    s.lastError,
+			}
+			if !s.lastErrorAt.IsZero() {
+				at := s.lastErrorAt
+				st.LastErrorAt = &at
+			}
+			s.mu.Unlock()
+			result = append(result, st)
+		}
+	}
+	return result
+}
+
+// Close 재시도 대기를 중단하고 백엔드 전달 루프 종료
+func (eb *EventBus) Close() error {
+	eb.cancel()
+	eb.mu.RLock()
+	backend := eb.backend
+	eb.mu.RUnlock()
+	return backend.Close()
+}
+
+func (eb *EventBus) findSubscription(pluginName, topic string) *subscription {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+	for _, s := range eb.subscribers[topic] {
+		if s.pluginName == pluginName {
+			return s
+		}
+	}
+	return nil
+}
+
+// handlerFor 백엔드에 넘길 구독자 전달 함수
+// 구독자별 전달 루프(memory: 구독자 고루틴, redis: 컨슈머 루프)에서 호출된다 — 재시도까지 끝낸 뒤 ack
+// (종료로 중단되면 미처리로 남겨 재전달)
+func (eb *EventBus) handlerFor(s *subscription) func(Event) error {
+	return func(event Event) error {
+		err := eb.attempt(s, event)
+		if err == nil {
+			return nil
+		}
+		return eb.retryDelivery(s, event, err)
+	}
+}
+
+// retryDelivery 첫 시도 실패 후 backoff 재시도, 소진 시 dead-letter. 버스 종료로 중단되면 ctx 에러를 반환한다.
+func (eb *EventBus) retryDelivery(s *subscription, event Event, lastErr error) error {
+	eb.mu.RLock()
+	policy := eb.retry
+	eb.mu.RUnlock()
+
+	attempts := 1
+	for attempts < policy.MaxAttempts {
+		select {
+		case <-time.After(policy.backoff(attempts)):
+		case <-eb.ctx.Done():
+			return eb.ctx.Err()
+		}
+
+		s.mu.Lock()
+		removed := s.removed
+		s.retried++
+		s.mu.Unlock()
+		if removed {
+			lastErr = fmt.Errorf("unsubscribed during retry: %w", lastErr)
+			break
+		}
+
+		attempts++
+		if lastErr = eb.attempt(s, event); lastErr == nil {
+			return nil
+		}
+	}
+
+	eb.deadLetter(s, event, attempts, lastErr)
+	return nil
+}
+
+// attempt 핸들러 1회 호출 (panic 은 에러로 변환)
+func (eb *EventBus) attempt(s *subscription, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			eb.logger.Error("Event handler panicked [%s/%s → %s]: %v", event.Source, event.Topic, s.pluginName, r)
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+		s.mu.Lock()
+		if err == nil {
+			s.delivered++
+		} else {
+			s.lastError = err.Error()
+			s.lastErrorAt = time.Now()
+		}
+		s.mu.Unlock()
+	}()
+	return s.handler(event)
+}
+
+func (eb *EventBus) deadLetter(s *subscription, event Event, attempts int, cause error) {
+	s.mu.Lock()
+	s.deadLettered++
+	s.mu.Unlock()
+
+	eb.mu.RLock()
+	backend := eb.backend
+	eb.mu.RUnlock()
+
+	dl := DeadLetter{
+		ID:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		Plugin:   s.pluginName,
+		Event:    event,
+		Error:    cause.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now(),
+	}
+	eb.logger.Warn("Event dead-lettered [%s/%s → %s] after %d attempts: %v", event.Source, event.Topic, s.pluginName, attempts, cause)
+	// 버스가 닫혀도 기록은 남긴다
+	if err := backend.PushDeadLetter(context.Background(), dl); err != nil {
+		eb.logger.Error("Dead letter push failed [%s/%s]: %v", s.pluginName, event.Topic, err)
+	}
+}
@@ -0,0 +1,250 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	memoryEventHistorySize  = 1000 // Replay 용으로 보관하는 최근 이벤트 수
+	memoryDeadLetterSize    = 1000
+	memoryConsumerQueueSize = 10000 // 구독자별 미전달 이벤트 한도 — 넘치면 dead-letter 로 보낸다
+)
+
+// memoryConsumer 구독자 하나 — 자기 큐와 전달 고루틴을 가져서 느린 구독자가 발행자·다른 구독자를 붙잡지 않는다
+type memoryConsumer struct {
+	group  string
+	topic  string
+	handle func(Event) error
+
+	mu    sync.Mutex
+	queue []Event
+	wake  chan struct{} // 버퍼 1 — 큐에 넣었다는 신호
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newMemoryConsumer(group, topic string, handle func(Event) error) *memoryConsumer {
+	c := &memoryConsumer{
+		group:  group,
+		topic:  topic,
+		handle: handle,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// enqueue 큐에 넣는다. 큐가 가득 찼으면 false.
+func (c *memoryConsumer) enqueue(event Event) bool {
+	c.mu.Lock()
+	if len(c.queue) >= memoryConsumerQueueSize {
+		c.mu.Unlock()
+		return false
+	}
+	c.queue = append(c.queue, event)
+	c.mu.Unlock()
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// run 큐를 순서대로 전달한다 (재시도도 이 고루틴에서 — 구독자 안에서는 순서가 유지된다)
+func (c *memoryConsumer) run() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.wake:
+		}
+		for {
+			c.mu.Lock()
+			if len(c.queue) == 0 {
+				c.mu.Unlock()
+				break
+			}
+			event := c.queue[0]
+			c.queue = c.queue[1:]
+			c.mu.Unlock()
+
+			select {
+			case <-c.done:
+				return
+			default:
+			}
+			_ = c.handle(event) //nolint:errcheck // nothing to ack in memory
+		}
+	}
+}
+
+func (c *memoryConsumer) stop() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// MemoryEventBackend 단일 인스턴스용 in-memory 백엔드
+// 재시작하면 이력·미전달 큐·dead-letter 가 사라지고, 다른 인스턴스의 구독자에게는 전달되지 않는다.
+type MemoryEventBackend struct {
+	mu          sync.RWMutex
+	seq         int64
+	history     []Event
+	consumers   []*memoryConsumer
+	deadLetters []DeadLetter // 최신이 앞
+}
+
+// NewMemoryEventBackend 생성자
+func NewMemoryEventBackend() *MemoryEventBackend {
+	return &MemoryEventBackend{}
+}
+
+// Name 백엔드 이름
+func (b *MemoryEventBackend) Name() string {
+	return "memory"
+}
+
+// Publish 이력에 기록 후 구독자별 큐에 넣는다 (핸들러 실행을 기다리지 않는다)
+func (b *MemoryEventBackend) Publish(ctx context.Context, event Event) (string, error) {
+	b.mu.Lock()
+	b.seq++
+	event.ID = strconv.FormatInt(b.seq, 10)
+	b.history = append(b.history, event)
+	if len(b.history) > memoryEventHistorySize {
+		b.history = b.history[len(b.history)-memoryEventHistorySize:]
+	}
+	var targets []*memoryConsumer
+	for _, c := range b.consumers {
+		if c.topic == event.Topic {
+			targets = append(targets, c)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, c := range targets {
+		b.deliver(ctx, c, event)
+	}
+	return event.ID, nil
+}
+
+// deliver 구독자 큐에 넣고, 넘치면 dead-letter 로 남긴다 (Redrive 로 다시 보낼 수 있다)
+func (b *MemoryEventBackend) deliver(ctx context.Context, c *memoryConsumer, event Event) {
+	if c.enqueue(event) {
+		return
+	}
+	_ = b.PushDeadLetter(ctx, DeadLetter{ //nolint:errcheck // memory push cannot fail
+		ID:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		Plugin:   c.group,
+		Event:    event,
+		Error:    "subscriber queue full",
+		FailedAt: time.Now(),
+	})
+}
+
+// Attach 구독 등록
+func (b *MemoryEventBackend) Attach(group, topic string, handle func(Event) error) func() {
+	c := newMemoryConsumer(group, topic, handle)
+	b.mu.Lock()
+	b.consumers = append(b.consumers, c)
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, existing := range b.consumers {
+			if existing == c {
+				b.consumers = append(b.consumers[:i], b.consumers[i+1:]...)
+				break
+			}
+		}
+		// 전달 중인 핸들러는 기다리지 않는다 — Unsubscribe 가 버스 잠금을 쥔 채 부른다
+		c.stop()
+	}
+}
+
+// Replay 보관 중인 이력에서 fromID 이후 이벤트를 그룹의 큐에 다시 넣는다 (빈 값이면 처음부터)
+func (b *MemoryEventBackend) Replay(ctx context.Context, group, topic, fromID string) (int, error) {
+	var from int64
+	if fromID != "" {
+		v, err := strconv.ParseInt(fromID, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid event offset %q", fromID)
+		}
+		from = v
+	}
+
+	b.mu.RLock()
+	var events []Event
+	for _, e := range b.history {
+		seq, _ := strconv.ParseInt(e.ID, 10, 64) //nolint:errcheck // IDs are generated above
+		if e.Topic == topic && seq > from {
+			events = append(events, e)
+		}
+	}
+	var targets []*memoryConsumer
+	for _, c := range b.consumers {
+		if c.group == group && c.topic == topic {
+			targets = append(targets, c)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, e := range events {
+		for _, c := range targets {
+			b.deliver(ctx, c, e)
+		}
+	}
+	return len(events), nil
+}
+
+// PushDeadLetter dead-letter 추가
+func (b *MemoryEventBackend) PushDeadLetter(_ context.Context, dl DeadLetter) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deadLetters = append([]DeadLetter{dl}, b.deadLetters...)
+	if len(b.deadLetters) > memoryDeadLetterSize {
+		b.deadLetters = b.deadLetters[:memoryDeadLetterSize]
+	}
+	return nil
+}
+
+// DeadLetters 최근 dead-letter 목록
+func (b *MemoryEventBackend) DeadLetters(_ context.Context, limit int) ([]DeadLetter, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if limit <= 0 || limit > len(b.deadLetters) {
+		limit = len(b.deadLetters)
+	}
+	result := make([]DeadLetter, limit)
+	copy(result, b.deadLetters[:limit])
+	return result, nil
+}
+
+// TakeDeadLetter dead-letter 1건 꺼내기
+func (b *MemoryEventBackend) TakeDeadLetter(_ context.Context, id string) (*DeadLetter, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, dl := range b.deadLetters {
+		if dl.ID == id {
+			b.deadLetters = append(b.deadLetters[:i], b.deadLetters[i+1:]...)
+			return &dl, nil
+		}
+	}
+	return nil, nil
+}
+
+// Close 구독자 전달 고루틴 종료 (큐에 남은 이벤트는 버린다)
+func (b *MemoryEventBackend) Close() error {
+	b.mu.Lock()
+	consumers := b.consumers
+	b.consumers = nil
+	b.mu.Unlock()
+	for _, c := range consumers {
+		c.stop()
+	}
+	return nil
+}
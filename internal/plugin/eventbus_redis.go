@@ -0,0 +1,260 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisEventStreamPrefix  = "angple:plugin:events:"
+	redisDeadLetterKey      = "angple:plugin:events:deadletter"
+	redisEventStreamMaxLen  = 10000 // 토픽별 보관 상한 (XADD MAXLEN ~)
+	redisDeadLetterMaxLen   = 1000
+	redisEventReadBlock     = 5 * time.Second
+	redisEventReadCount     = 16
+	redisEventClaimMinIdle  = time.Minute // 이 시간 이상 ack 되지 않은 엔트리는 죽은 인스턴스 몫으로 보고 회수
+	redisEventErrorCooldown = time.Second
+)
+
+// RedisEventBackend Redis Streams 백엔드
+//
+// 토픽마다 스트림 1개, 구독 플러그인마다 컨슈머 그룹 1개를 둔다.
+// 모든 API 인스턴스가 같은 그룹에 자기 이름(hostname-pid)으로 참여하므로,
+// 이벤트는 어느 파드에서 발행됐든 플러그인마다 정확히 한 인스턴스가 처리한다.
+type RedisEventBackend struct {
+	client   *redis.Client
+	logger   Logger
+	consumer string
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewRedisEventBackend 생성자
+func NewRedisEventBackend(client *redis.Client, logger Logger) *RedisEventBackend {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RedisEventBackend{
+		client:   client,
+		logger:   logger,
+		consumer: instanceID(),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Name 백엔드 이름
+func (b *RedisEventBackend) Name() string {
+	return "redis"
+}
+
+func eventStreamKey(topic string) string {
+	return redisEventStreamPrefix + topic
+}
+
+// Publish XADD 로 토픽 스트림에 기록
+func (b *RedisEventBackend) Publish(ctx context.Context, event Event) (string, error) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("marshal event: %w", err)
+	}
+	return b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: eventStreamKey(event.Topic),
+		MaxLen: redisEventStreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": raw},
+	}).Result()
+}
+
+// Attach 컨슈머 그룹 읽기 루프 시작
+func (b *RedisEventBackend) Attach(group, topic string, handle func(Event) error) func() {
+	ctx, cancel := context.WithCancel(b.ctx)
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.consume(ctx, group, topic, handle)
+	}()
+	return cancel
+}
+
+func (b *RedisEventBackend) consume(ctx context.Context, group, topic string, handle func(Event) error) {
+	stream := eventStreamKey(topic)
+	b.ensureGroup(ctx, stream, group)
+
+	var lastClaim time.Time
+	for ctx.Err() == nil {
+		if time.Since(lastClaim) >= redisEventClaimMinIdle {
+			b.reclaim(ctx, stream, group, handle)
+			lastClaim = time.Now()
+		}
+
+		streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: b.consumer,
+			Streams:  []string{stream, ">"},
+			Count:    redisEventReadCount,
+			Block:    redisEventReadBlock,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				b.ensureGroup(ctx, stream, group) // 스트림이 삭제된 경우
+				continue
+			}
+			b.logger.Warn("Event stream read failed [%s/%s]: %v", group, topic, err)
+			select {
+			case <-time.After(redisEventErrorCooldown):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				b.process(ctx, stream, group, msg, handle)
+			}
+		}
+	}
+}
+
+// ensureGroup 그룹이 없으면 "$"(지금 이후) 부터 읽는 그룹 생성
+func (b *RedisEventBackend) ensureGroup(ctx context.Context, stream, group string) {
+	err := b.client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") && ctx.Err() == nil {
+		b.logger.Warn("Event consumer group create failed [%s/%s]: %v", stream, group, err)
+	}
+}
+
+// reclaim 다른(죽은) 인스턴스가 가져가 놓고 ack 하지 않은 엔트리를 회수해 처리
+func (b *RedisEventBackend) reclaim(ctx context.Context, stream, group string, handle func(Event) error) {
+	msgs, _, err := b.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: b.consumer,
+		MinIdle:  redisEventClaimMinIdle,
+		Start:    "0-0",
+		Count:    redisEventReadCount,
+	}).Result()
+	if err != nil {
+		if ctx.Err() == nil && !errors.Is(err, redis.Nil) {
+			b.logger.Debug("Event reclaim failed [%s/%s]: %v", stream, group, err)
+		}
+		return
+	}
+	for _, msg := range msgs {
+		b.process(ctx, stream, group, msg, handle)
+	}
+}
+
+func (b *RedisEventBackend) process(ctx context.Context, stream, group string, msg redis.XMessage, handle func(Event) error) {
+	var event Event
+	raw, _ := msg.Values["event"].(string)
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		// 디코딩 불가 엔트리는 재시도해도 소용없다 — ack 로 치운다
+		b.logger.Error("Event decode failed [%s %s]: %v", stream, msg.ID, err)
+		b.ack(stream, group, msg.ID)
+		return
+	}
+	event.ID = msg.ID
+
+	if err := handle(event); err != nil {
+		return // 종료로 중단 — ack 하지 않아 재기동 후 회수된다
+	}
+	b.ack(stream, group, msg.ID)
+}
+
+func (b *RedisEventBackend) ack(stream, group, id string) {
+	// 종료 중에도 처리 완료분은 ack 해야 중복 전달이 줄어든다
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := b.client.XAck(ctx, stream, group, id).Err(); err != nil {
+		b.logger.Warn("Event ack failed [%s %s]: %v", stream, id, err)
+	}
+}
+
+// Replay 그룹의 last-delivered-id 를 fromID 로 되돌린다 (빈 값이면 스트림 처음부터)
+func (b *RedisEventBackend) Replay(ctx context.Context, group, topic, fromID string) (int, error) {
+	if fromID == "" {
+		fromID = "0"
+	}
+	stream := eventStreamKey(topic)
+
+	if err := b.client.XGroupSetID(ctx, stream, group, fromID).Err(); err != nil {
+		return 0, fmt.Errorf("reset consumer group: %w", err)
+	}
+	pending, err := b.client.XRange(ctx, stream, "("+fromID, "+").Result()
+	if err != nil {
+		return 0, err
+	}
+	return len(pending), nil
+}
+
+// PushDeadLetter dead-letter 리스트에 추가 (최신이 앞, 상한 초과분은 버림)
+func (b *RedisEventBackend) PushDeadLetter(ctx context.Context, dl DeadLetter) error {
+	raw, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	pipe := b.client.TxPipeline()
+	pipe.LPush(ctx, redisDeadLetterKey, raw)
+	pipe.LTrim(ctx, redisDeadLetterKey, 0, redisDeadLetterMaxLen-1)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// DeadLetters 최근 dead-letter 목록
+func (b *RedisEventBackend) DeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	if limit <= 0 || limit > redisDeadLetterMaxLen {
+		limit = redisDeadLetterMaxLen
+	}
+	raws, err := b.client.LRange(ctx, redisDeadLetterKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]DeadLetter, 0, len(raws))
+	for _, raw := range raws {
+		var dl DeadLetter
+		if json.Unmarshal([]byte(raw), &dl) == nil {
+			result = append(result, dl)
+		}
+	}
+	return result, nil
+}
+
+// TakeDeadLetter dead-letter 1건 꺼내기
+func (b *RedisEventBackend) TakeDeadLetter(ctx context.Context, id string) (*DeadLetter, error) {
+	raws, err := b.client.LRange(ctx, redisDeadLetterKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, raw := range raws {
+		var dl DeadLetter
+		if json.Unmarshal([]byte(raw), &dl) != nil || dl.ID != id {
+			continue
+		}
+		removed, err := b.client.LRem(ctx, redisDeadLetterKey, 1, raw).Result()
+		if err != nil {
+			return nil, err
+		}
+		if removed == 0 {
+			return nil, nil // 다른 인스턴스가 먼저 꺼내감
+		}
+		return &dl, nil
+	}
+	return nil, nil
+}
+
+// Close 읽기 루프 종료 대기
+func (b *RedisEventBackend) Close() error {
+	b.cancel()
+	b.wg.Wait()
+	return nil
+}
@@ -1,6 +1,8 @@
 package plugin
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -10,13 +12,19 @@ func TestEventBus_PublishSubscribe(t *testing.T) {
 	logger := NewDefaultLogger("test")
 	eb := NewEventBus(logger)
 
-	var received Event
+	got := make(chan Event, 1)
 	eb.Subscribe("plugin-a", "user.created", func(e Event) {
-		received = e
+		got <- e
 	})
 
 	eb.Publish("plugin-b", "user.created", map[string]interface{}{"user_id": "123"})
 
+	var received Event
+	select {
+	case received = <-got:
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not delivered")
+	}
 	if received.Topic != "user.created" {
 		t.Fatalf("expected topic user.created, got %s", received.Topic)
 	}
@@ -47,9 +55,11 @@ func TestEventBus_MultipleSubscribers(t *testing.T) {
 
 	eb.Publish("shop", "order.placed", nil)
 
-	if count != 3 {
-		t.Fatalf("expected 3 handlers called, got %d", count)
-	}
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return count == 3
+	})
 }
 
 func TestEventBus_Unsubscribe(t *testing.T) {
@@ -81,18 +91,20 @@ func TestEventBus_HandlerPanic(t *testing.T) {
 	logger := NewDefaultLogger("test")
 	eb := NewEventBus(logger)
 
-	var secondCalled bool
+	secondCalled := make(chan struct{})
 	eb.Subscribe("bad-plugin", "test", func(_ Event) {
 		panic("handler crash")
 	})
 	eb.Subscribe("good-plugin", "test", func(_ Event) {
-		secondCalled = true
+		close(secondCalled)
 	})
 
 	// Should not panic, and second handler should still run
 	eb.Publish("source", "test", nil)
 
-	if !secondCalled {
+	select {
+	case <-secondCalled:
+	case <-time.After(2 * time.Second):
 		t.Fatal("expected second handler to be called despite first panic")
 	}
 }
@@ -137,3 +149,200 @@ func TestEventBus_PublishAsync(t *testing.T) {
 		t.Fatal("expected async handler to be called")
 	}
 }
+
+func fastRetryBus(maxAttempts int) *EventBus {
+	eb := NewEventBus(&testLogger{})
+	eb.SetRetryPolicy(RetryPolicy{MaxAttempts: maxAttempts, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+	return eb
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+func TestEventBus_RetryUntilSuccess(t *testing.T) {
+	eb := fastRetryBus(5)
+
+	var mu sync.Mutex
+	calls := 0
+	eb.SubscribeFunc("flaky", "post.created", func(_ Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	})
+
+	eb.Publish("core", "post.created", nil)
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls == 3
+	})
+	stats := eb.GetSubscriptionStatus()
+	if len(stats) != 1 || stats[0].Delivered != 1 || stats[0].Retried != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if dls, _ := eb.DeadLetters(context.Background(), 10); len(dls) != 0 {
+		t.Fatalf("expected no dead letters, got %d", len(dls))
+	}
+}
+
+func TestEventBus_DeadLetterAndRedrive(t *testing.T) {
+	eb := fastRetryBus(3)
+
+	var mu sync.Mutex
+	healthy := false
+	received := 0
+	eb.SubscribeFunc("mailer", "user.created", func(_ Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !healthy {
+			return errors.New("smtp down")
+		}
+		received++
+		return nil
+	})
+
+	eb.Publish("core", "user.created", map[string]interface{}{"user_id": "u1"})
+
+	var dls []DeadLetter
+	waitFor(t, func() bool {
+		dls, _ = eb.DeadLetters(context.Background(), 10)
+		return len(dls) == 1
+	})
+	dl := dls[0]
+	if dl.Plugin != "mailer" || dl.Attempts != 3 || dl.Error != "smtp down" || dl.Event.Payload["user_id"] != "u1" {
+		t.Fatalf("unexpected dead letter: %+v", dl)
+	}
+
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+
+	if err := eb.Redrive(context.Background(), dl.ID); err != nil {
+		t.Fatalf("Redrive failed: %v", err)
+	}
+	if received != 1 {
+		t.Errorf("expected redriven event to be delivered once, got %d", received)
+	}
+	if dls, _ := eb.DeadLetters(context.Background(), 10); len(dls) != 0 {
+		t.Errorf("dead letter should be removed after redrive, got %d", len(dls))
+	}
+	if err := eb.Redrive(context.Background(), dl.ID); err == nil {
+		t.Error("expected error redriving missing dead letter")
+	}
+}
+
+func TestEventBus_FailingSubscriberDoesNotBlockPublisher(t *testing.T) {
+	eb := NewEventBus(&testLogger{})
+	eb.SetRetryPolicy(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: time.Second})
+	defer eb.Close()
+
+	eb.SubscribeFunc("broken", "test", func(_ Event) error { return errors.New("fail") })
+
+	start := time.Now()
+	eb.Publish("source", "test", nil)
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Fatalf("publisher blocked by retry backoff for %s", elapsed)
+	}
+}
+
+func TestEventBus_Replay(t *testing.T) {
+	eb := NewEventBus(&testLogger{})
+
+	var mu sync.Mutex
+	var seen []string
+	eb.Subscribe("indexer", "post.updated", func(e Event) {
+		mu.Lock()
+		seen = append(seen, e.ID)
+		mu.Unlock()
+	})
+
+	first, _ := eb.PublishEvent(context.Background(), "core", "post.updated", nil)
+	eb.Publish("core", "other.topic", nil)
+	eb.Publish("core", "post.updated", nil)
+
+	n, err := eb.Replay(context.Background(), "indexer", "post.updated", first)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 replayed event, got %d", n)
+	}
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(seen) == 3
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if seen[2] != seen[1] {
+		t.Errorf("expected last event to be delivered again, got %v", seen)
+	}
+
+	if _, err := eb.Replay(context.Background(), "nobody", "post.updated", ""); !errors.Is(err, ErrNoSubscription) {
+		t.Errorf("expected ErrNoSubscription, got %v", err)
+	}
+}
+
+func TestEventBus_SetBackendKeepsSubscriptions(t *testing.T) {
+	eb := NewEventBus(&testLogger{})
+
+	received := make(chan struct{}, 1)
+	eb.Subscribe("plugin-a", "test", func(_ Event) { received <- struct{}{} })
+
+	eb.SetBackend(NewMemoryEventBackend())
+	eb.Publish("source", "test", nil)
+
+	select {
+	case <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription should survive backend swap")
+	}
+}
+
+func TestEventBus_SlowSubscriberDoesNotBlockPublisher(t *testing.T) {
+	eb := NewEventBus(&testLogger{})
+	defer eb.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	eb.Subscribe("slow", "post.created", func(_ Event) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+	})
+	var mu sync.Mutex
+	fast := 0
+	eb.Subscribe("fast", "post.created", func(_ Event) {
+		mu.Lock()
+		fast++
+		mu.Unlock()
+	})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		eb.Publish("core", "post.created", nil)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Fatalf("publisher blocked by slow subscriber for %s", elapsed)
+	}
+	// 느린 구독자가 첫 이벤트를 붙잡고 있어도 다른 구독자는 전부 받는다
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return fast == 3
+	})
+}
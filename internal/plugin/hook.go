@@ -1,6 +1,8 @@
 package plugin
 
 import (
+	"errors"
+	"fmt"
 	"sort"
 	"sync"
 )
@@ -36,6 +38,27 @@ func (c *HookContext) GetOutput() map[string]interface{} {
 // HookHandler Hook 핸들러 함수
 type HookHandler func(ctx *HookContext) error
 
+// HookAbortError before Hook 이 작업을 거부(veto)할 때 반환하는 에러
+// ApplyBefore 는 이 에러를 받으면 남은 Hook 을 건너뛰고 호출자에게 그대로 돌려준다.
+type HookAbortError struct {
+	Plugin string
+	Reason string
+}
+
+func (e *HookAbortError) Error() string {
+	if e.Plugin == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("%s: %s", e.Plugin, e.Reason)
+}
+
+// Abort before Hook 핸들러에서 작업을 거부할 때 사용
+//
+//	return plugin.Abort("스팸으로 판단되어 등록할 수 없습니다")
+func Abort(reason string) error {
+	return &HookAbortError{Reason: reason}
+}
+
 // hookEntry 등록된 Hook 정보
 type hookEntry struct {
 	pluginName string
@@ -89,17 +112,12 @@ func (hm *HookManager) RegisterFilter(event string, pluginName string, handler H
 
 // Do Action Hook 실행 (에러 로깅만, 블로킹 안 함)
 func (hm *HookManager) Do(event string, data map[string]interface{}) {
-	hm.mu.RLock()
-	entries := make([]hookEntry, len(hm.hooks[event]))
-	copy(entries, hm.hooks[event])
-	hm.mu.RUnlock()
-
-	for _, entry := range entries {
+	for _, entry := range hm.entries(event) {
 		ctx := &HookContext{
 			Event: event,
 			Input: data,
 		}
-		if err := entry.handler(ctx); err != nil {
+		if err := entry.call(ctx); err != nil {
 			hm.logger.Error("Hook error [%s] plugin=%s: %v", event, entry.pluginName, err)
 		}
 	}
@@ -107,18 +125,13 @@ func (hm *HookManager) Do(event string, data map[string]interface{}) {
 
 // Apply Filter Hook 실행 (결과 반환, 체이닝)
 func (hm *HookManager) Apply(event string, data map[string]interface{}) map[string]interface{} {
-	hm.mu.RLock()
-	entries := make([]hookEntry, len(hm.hooks[event]))
-	copy(entries, hm.hooks[event])
-	hm.mu.RUnlock()
-
 	current := data
-	for _, entry := range entries {
+	for _, entry := range hm.entries(event) {
 		ctx := &HookContext{
 			Event: event,
 			Input: current,
 		}
-		if err := entry.handler(ctx); err != nil {
+		if err := entry.call(ctx); err != nil {
 			hm.logger.Error("Filter error [%s] plugin=%s: %v", event, entry.pluginName, err)
 			continue
 		}
@@ -127,6 +140,58 @@ func (hm *HookManager) Apply(event string, data map[string]interface{}) map[stri
 	return current
 }
 
+// ApplyBefore before Hook 실행 - Apply 와 같이 데이터를 체이닝하되,
+// 핸들러가 Abort 로 거부하면 즉시 중단하고 *HookAbortError 를 반환한다.
+// 그 외 에러는 Apply 와 동일하게 로깅 후 다음 핸들러로 넘어간다.
+func (hm *HookManager) ApplyBefore(event string, data map[string]interface{}) (map[string]interface{}, error) {
+	current := data
+	for _, entry := range hm.entries(event) {
+		ctx := &HookContext{
+			Event: event,
+			Input: current,
+		}
+		if err := entry.call(ctx); err != nil {
+			var abort *HookAbortError
+			if errors.As(err, &abort) {
+				if abort.Plugin == "" {
+					abort.Plugin = entry.pluginName
+				}
+				return current, abort
+			}
+			hm.logger.Error("Before hook error [%s] plugin=%s: %v", event, entry.pluginName, err)
+			continue
+		}
+		current = ctx.GetOutput()
+	}
+	return current, nil
+}
+
+// HasHooks 이벤트에 등록된 Hook 존재 여부 (핫패스에서 페이로드 생성을 건너뛰기 위함)
+func (hm *HookManager) HasHooks(event string) bool {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+	return len(hm.hooks[event]) > 0
+}
+
+// entries 이벤트의 Hook 목록 스냅샷 (실행 중 등록/해제와 경합 방지)
+func (hm *HookManager) entries(event string) []hookEntry {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+	entries := make([]hookEntry, len(hm.hooks[event]))
+	copy(entries, hm.hooks[event])
+	return entries
+}
+
+// call 핸들러 실행 - 플러그인 panic 이 요청 처리 경로로 번지지 않도록 에러로 변환
+func (e hookEntry) call(ctx *HookContext) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("hook panicked: %v", r)
+		}
+	}()
+	return e.handler(ctx)
+}
+
 // Unregister 특정 플러그인의 모든 Hook 해제
 func (hm *HookManager) Unregister(pluginName string) {
 	hm.mu.Lock()
@@ -171,3 +171,95 @@ func TestHookApplyNoHandlers(t *testing.T) {
 		t.Error("Apply with no handlers should return original data")
 	}
 }
+
+func TestHookApplyBeforeRewrites(t *testing.T) {
+	hm := newTestHookManager()
+
+	hm.Register(HookPostBeforeCreate, "tagger", func(ctx *HookContext) error {
+		out := map[string]interface{}{}
+		for k, v := range ctx.Input {
+			out[k] = v
+		}
+		out["title"] = "[auto] " + ctx.Input["title"].(string)
+		ctx.SetOutput(out)
+		return nil
+	}, 10)
+
+	result, err := hm.ApplyBefore(HookPostBeforeCreate, map[string]interface{}{"title": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result["title"] != "[auto] hello" {
+		t.Errorf("expected rewritten title, got %v", result["title"])
+	}
+}
+
+func TestHookApplyBeforeAbortStopsChain(t *testing.T) {
+	hm := newTestHookManager()
+
+	laterCalled := false
+	hm.Register(HookCommentBeforeCreate, "spam-filter", func(_ *HookContext) error {
+		return Abort("spam detected")
+	}, 10)
+	hm.Register(HookCommentBeforeCreate, "later", func(_ *HookContext) error {
+		laterCalled = true
+		return nil
+	}, 20)
+
+	_, err := hm.ApplyBefore(HookCommentBeforeCreate, map[string]interface{}{"content": "buy now"})
+
+	var abort *HookAbortError
+	if !errors.As(err, &abort) {
+		t.Fatalf("expected HookAbortError, got %v", err)
+	}
+	if abort.Plugin != "spam-filter" || abort.Reason != "spam detected" {
+		t.Errorf("unexpected abort: %+v", abort)
+	}
+	if laterCalled {
+		t.Error("hooks after an abort should not run")
+	}
+}
+
+func TestHookApplyBeforeIgnoresPlainErrors(t *testing.T) {
+	hm := newTestHookManager()
+
+	hm.Register(HookPostBeforeUpdate, "broken", func(_ *HookContext) error {
+		return errors.New("db down")
+	}, 10)
+
+	result, err := hm.ApplyBefore(HookPostBeforeUpdate, map[string]interface{}{"title": "t"})
+	if err != nil {
+		t.Fatalf("plain errors must not veto, got %v", err)
+	}
+	if result["title"] != "t" {
+		t.Errorf("payload should pass through, got %v", result["title"])
+	}
+}
+
+func TestHookPanicIsRecovered(t *testing.T) {
+	hm := newTestHookManager()
+
+	hm.RegisterFilter(HookPostContent, "panicky", func(_ *HookContext) error {
+		panic("boom")
+	}, 10)
+
+	result := hm.Apply(HookPostContent, map[string]interface{}{"content": "safe"})
+	if result["content"] != "safe" {
+		t.Errorf("expected original content after panic, got %v", result["content"])
+	}
+}
+
+func TestHookHasHooks(t *testing.T) {
+	hm := newTestHookManager()
+	if hm.HasHooks(HookPostContent) {
+		t.Error("expected no hooks")
+	}
+	hm.RegisterFilter(HookPostContent, "p", func(_ *HookContext) error { return nil }, 10)
+	if !hm.HasHooks(HookPostContent) {
+		t.Error("expected hooks to be registered")
+	}
+	hm.Unregister("p")
+	if hm.HasHooks(HookPostContent) {
+		t.Error("expected no hooks after unregister")
+	}
+}
@@ -17,6 +17,8 @@ func TestHookConstants(t *testing.T) {
 		{"HookPostAfterUpdate", HookPostAfterUpdate, "post.after_update"},
 		{"HookPostBeforeDelete", HookPostBeforeDelete, "post.before_delete"},
 		{"HookPostAfterDelete", HookPostAfterDelete, "post.after_delete"},
+		{"HookPostBeforeRestore", HookPostBeforeRestore, "post.before_restore"},
+		{"HookPostAfterRestore", HookPostAfterRestore, "post.after_restore"},
 		{"HookPostContent", HookPostContent, "post.content"},
 		// Comment hooks
 		{"HookCommentBeforeCreate", HookCommentBeforeCreate, "comment.before_create"},
@@ -25,6 +27,8 @@ func TestHookConstants(t *testing.T) {
 		{"HookCommentAfterUpdate", HookCommentAfterUpdate, "comment.after_update"},
 		{"HookCommentBeforeDelete", HookCommentBeforeDelete, "comment.before_delete"},
 		{"HookCommentAfterDelete", HookCommentAfterDelete, "comment.after_delete"},
+		{"HookCommentBeforeRestore", HookCommentBeforeRestore, "comment.before_restore"},
+		{"HookCommentAfterRestore", HookCommentAfterRestore, "comment.after_restore"},
 		{"HookCommentContent", HookCommentContent, "comment.content"},
 		// User hooks
 		{"HookUserAfterLogin", HookUserAfterLogin, "user.after_login"},
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -65,6 +66,50 @@ func (l *Loader) validateManifest(m *PluginManifest) error {
 		return fmt.Errorf("invalid requires.angple %q: %w", m.Requires.Angple, err)
 	}
 
+	if m.Runtime != nil {
+		if err := validateRuntime(m.Runtime); err != nil {
+			return err
+		}
+	}
+	for _, sc := range m.Schedules {
+		if sc.Name == "" {
+			return fmt.Errorf("schedule name is required")
+		}
+		if _, err := sc.TaskOptions(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRuntime runtime 섹션 검증
+func validateRuntime(r *RuntimeConfig) error {
+	switch r.Type {
+	case "", RuntimeBuiltIn:
+		return nil
+	case RuntimeProcess:
+	default:
+		return fmt.Errorf("unknown runtime type %q", r.Type)
+	}
+	if r.Command == "" {
+		return fmt.Errorf("runtime.command is required for process plugins")
+	}
+	switch r.Transport {
+	case "", TransportStdio, TransportUnix:
+	default:
+		return fmt.Errorf("unknown runtime transport %q", r.Transport)
+	}
+	if r.Timeout != "" {
+		if _, err := time.ParseDuration(r.Timeout); err != nil {
+			return fmt.Errorf("invalid runtime.timeout %q: %w", r.Timeout, err)
+		}
+	}
+	if r.Restart.Backoff != "" {
+		if _, err := time.ParseDuration(r.Restart.Backoff); err != nil {
+			return fmt.Errorf("invalid runtime.restart.backoff %q: %w", r.Restart.Backoff, err)
+		}
+	}
 	return nil
 }
 
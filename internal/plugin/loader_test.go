@@ -136,3 +136,41 @@ func TestLoader_NonExistentDirectory(t *testing.T) {
 		t.Errorf("expected 0 plugins, got %d", len(plugins))
 	}
 }
+
+func TestLoader_ValidateManifest_Runtime(t *testing.T) {
+	tests := []struct {
+		name    string
+		runtime string
+		wantErr bool
+	}{
+		{"process stdio", "runtime:\n  type: process\n  command: ./bin/plugin\n  timeout: 5s\n", false},
+		{"process unix", "runtime:\n  type: process\n  command: ./bin/plugin\n  transport: unix\n", false},
+		{"missing command", "runtime:\n  type: process\n", true},
+		{"unknown type", "runtime:\n  type: wasm\n  command: x\n", true},
+		{"unknown transport", "runtime:\n  type: process\n  command: x\n  transport: tcp\n", true},
+		{"bad backoff", "runtime:\n  type: process\n  command: x\n  restart:\n    backoff: soon\n", true},
+		{"bad schedule", "schedules:\n  - name: sync\n    interval: daily\n", true},
+		{"cron schedule", "schedules:\n  - name: report\n    cron: \"0 4 * * *\"\n    timezone: Asia/Seoul\n    jitter: 30s\n    timeout: 5m\n", false},
+		{"bad cron", "schedules:\n  - name: report\n    cron: \"0 25 * * *\"\n", true},
+		{"bad timezone", "schedules:\n  - name: report\n    cron: \"@daily\"\n    timezone: Mars/Olympus\n", true},
+		{"bad timeout", "schedules:\n  - name: report\n    interval: 1h\n    timeout: forever\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pluginDir := filepath.Join(t.TempDir(), "rt-plugin")
+			if err := os.MkdirAll(pluginDir, 0755); err != nil {
+				t.Fatalf("failed to create plugin dir: %v", err)
+			}
+			manifest := "name: rt-plugin\nversion: 1.0.0\ntitle: RT\nrequires:\n  angple: \">=1.0.0\"\n" + tt.runtime
+			if err := os.WriteFile(filepath.Join(pluginDir, "plugin.yaml"), []byte(manifest), 0644); err != nil {
+				t.Fatalf("failed to write manifest: %v", err)
+			}
+
+			_, err := NewLoader(filepath.Dir(pluginDir)).LoadManifest(pluginDir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
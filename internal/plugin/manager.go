@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -33,7 +34,7 @@ type Manager struct {
 
 // NewManager 새 매니저 생성
 func NewManager(pluginsDir string, db *gorm.DB, redisClient *redis.Client, logger Logger, settings SettingGetter, permissions PermissionSyncer) *Manager {
-	return &Manager{
+	m := &Manager{
 		loader:      NewLoader(pluginsDir),
 		registry:    NewRegistry(),
 		hookManager: NewHookManager(logger),
@@ -48,6 +49,11 @@ func NewManager(pluginsDir string, db *gorm.DB, redisClient *redis.Client, logge
 		metrics:     NewMetrics(),
 		eventBus:    NewEventBus(logger),
 	}
+	if redisClient != nil {
+		// 레플리카가 여러 대여도 스케줄 작업은 슬롯마다 한 곳에서만 실행
+		m.scheduler.SetLocker(NewRedisTaskLocker(redisClient))
+	}
+	return m
 }
 
 // GetRegistry 레지스트리 반환
@@ -68,7 +74,17 @@ func (m *Manager) LoadAll() error {
 			continue
 		}
 
+		// runtime.type=process 면 외부 실행 파일을 JSON-RPC 로 구동하는 인스턴스를 붙인다
+		if info.Manifest.Runtime.IsProcess() {
+			info.Instance = NewProcessPlugin(info.Manifest, info.Path, m.logger)
+		}
+
 		m.mu.Lock()
+		if existing, ok := m.plugins[info.Manifest.Name]; ok && existing.IsBuiltIn {
+			m.mu.Unlock()
+			m.logger.Warn("Plugin %s at %s conflicts with built-in plugin, skipped", info.Manifest.Name, info.Path)
+			continue
+		}
 		m.plugins[info.Manifest.Name] = info
 		m.mu.Unlock()
 
@@ -103,6 +119,14 @@ func (m *Manager) SetJWTManager(jm interface{}) {
 	m.jwtManager = jm
 }
 
+// SetHookManager 외부에서 생성한 HookManager 사용
+// 코어 쓰기 경로가 플러그인 매니저보다 먼저 Hook 을 참조하므로, 플러그인 활성화 전에 호출해야 한다.
+func (m *Manager) SetHookManager(hm *HookManager) {
+	if hm != nil {
+		m.hookManager = hm
+	}
+}
+
 // RegisterAllFactories Factory에 등록된 모든 플러그인 자동 등록
 // 각 플러그인 패키지의 init()에서 RegisterFactory로 등록된 플러그인들을 가져와 등록
 func (m *Manager) RegisterAllFactories() error {
@@ -341,11 +365,26 @@ func (m *Manager) StopScheduler() {
 	m.scheduler.Stop()
 }
 
+// GetScheduler 스케줄러 반환
+func (m *Manager) GetScheduler() *Scheduler {
+	return m.scheduler
+}
+
 // GetScheduledTasks 등록된 스케줄 작업 목록
 func (m *Manager) GetScheduledTasks() []ScheduledTaskInfo {
 	return m.scheduler.GetTasks()
 }
 
+// GetScheduleRuns 스케줄 작업 실행 이력 (최신순)
+func (m *Manager) GetScheduleRuns(pluginName, taskName string, limit int) ([]ScheduleRun, error) {
+	return m.scheduler.GetRuns(pluginName, taskName, limit)
+}
+
+// RunScheduledTask 스케줄 작업 즉시 실행 (끝날 때까지 대기)
+func (m *Manager) RunScheduledTask(ctx context.Context, pluginName, taskName string, req RunRequest) (*ScheduleRun, error) {
+	return m.scheduler.RunNow(ctx, pluginName, taskName, req)
+}
+
 // GetRateLimiter 레이트 리미터 반환
 func (m *Manager) GetRateLimiter() *RateLimiter {
 	return m.rateLimiter
@@ -499,6 +538,10 @@ func (m *Manager) Shutdown() error {
 		info.Status = StatusDisabled
 	}
 
+	if err := m.eventBus.Close(); err != nil {
+		m.logger.Warn("Event bus close error: %v", err)
+	}
+
 	m.logger.Info("All plugins shutdown complete")
 	return nil
 }
@@ -0,0 +1,660 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ErrPluginUnavailable 외부 프로세스가 실행 중이 아님 (기동 전·재시작 중·재시작 한도 초과)
+var ErrPluginUnavailable = errors.New("plugin process unavailable")
+
+const (
+	defaultProcessTimeout   = 10 * time.Second
+	defaultRestartBackoff   = time.Second
+	maxRestartBackoff       = time.Minute
+	defaultMaxRestarts      = 5
+	processStableAfter      = time.Minute // 이 시간 이상 살아 있었으면 재시작 카운터 초기화
+	processShutdownGrace    = 5 * time.Second
+	unixSocketAcceptTimeout = 10 * time.Second
+)
+
+// 외부 프로세스 플러그인 RPC 메서드 (호스트 → 플러그인)
+const (
+	ProcessMethodInitialize = "initialize"
+	ProcessMethodHTTP       = "http.request"
+	ProcessMethodHook       = "hook.invoke"
+	ProcessMethodEvent      = "event.deliver"
+	ProcessMethodSchedule   = "schedule.run"
+	ProcessMethodHealth     = "health"
+	ProcessMethodShutdown   = "shutdown"
+)
+
+// 외부 프로세스 플러그인 RPC 알림 (플러그인 → 호스트)
+const (
+	ProcessNotifyLog     = "log"
+	ProcessNotifyPublish = "event.publish"
+)
+
+// ProcessInitParams initialize 요청 파라미터
+type ProcessInitParams struct {
+	Name     string                 `json:"name"`
+	Version  string                 `json:"version"`
+	Config   map[string]interface{} `json:"config"`
+	BasePath string                 `json:"base_path"`
+}
+
+// ProcessUser 라우트 요청의 인증 사용자 (비로그인이면 nil)
+type ProcessUser struct {
+	ID       string `json:"id"`
+	Nickname string `json:"nickname"`
+	Level    int    `json:"level"`
+}
+
+// ProcessHTTPRequest http.request 요청 파라미터
+// Authorization·Cookie 헤더는 넘기지 않는다 — 인증 결과는 User 로만 전달한다.
+type ProcessHTTPRequest struct {
+	Method  string              `json:"method"`
+	Route   string              `json:"route"` // 매니페스트에 선언된 경로 (예: /items/:id)
+	Path    string              `json:"path"`  // 실제 요청 경로
+	Handler string              `json:"handler"`
+	Params  map[string]string   `json:"params"`
+	Query   map[string][]string `json:"query"`
+	Headers map[string]string   `json:"headers"`
+	Body    string              `json:"body"`
+	User    *ProcessUser        `json:"user,omitempty"`
+}
+
+// ProcessHTTPResponse http.request 응답
+// JSON 이 있으면 application/json 으로, 없으면 Body 를 그대로 내보낸다.
+type ProcessHTTPResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+	JSON    json.RawMessage   `json:"json"`
+}
+
+// ProcessHookParams hook.invoke 요청 파라미터
+type ProcessHookParams struct {
+	Event   string                 `json:"event"`
+	Handler string                 `json:"handler"`
+	Input   map[string]interface{} `json:"input"`
+}
+
+// ProcessHookResult hook.invoke 응답 - Abort 가 있으면 before Hook 거부
+type ProcessHookResult struct {
+	Output map[string]interface{} `json:"output,omitempty"`
+	Abort  string                 `json:"abort,omitempty"`
+}
+
+// ProcessHealthResult health 응답
+type ProcessHealthResult struct {
+	Status  string `json:"status"` // ok 이외는 unhealthy
+	Message string `json:"message,omitempty"`
+}
+
+type processLogParams struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+type processPublishParams struct {
+	Topic   string                 `json:"topic"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// ProcessPlugin plugin.yaml 의 runtime.type=process 로 선언된 외부 실행 파일 플러그인
+//
+// 매니페스트의 routes 는 /api/plugins/{name} 아래에 프록시되고, hooks·events·schedules 는
+// 각각 hook.invoke / event.deliver / schedule.run 호출로 전달된다.
+// 프로세스가 비정상 종료하면 restart 정책에 따라 재기동 후 initialize 를 다시 보낸다.
+type ProcessPlugin struct {
+	manifest    *PluginManifest
+	dir         string
+	logger      Logger
+	timeout     time.Duration
+	maxRestarts int
+	backoff     time.Duration
+
+	mu         sync.RWMutex
+	conn       *rpcConn
+	cmd        *exec.Cmd
+	socketDir  string
+	initParams ProcessInitParams
+	bus        *EventBus
+	running    bool
+	stopping   bool
+	failed     error
+	restarts   int
+	exited     chan struct{} // 현재 프로세스의 supervise 루프 종료 신호
+}
+
+// NewProcessPlugin 매니페스트로 외부 프로세스 플러그인 생성 (프로세스는 Initialize 에서 기동)
+func NewProcessPlugin(manifest *PluginManifest, dir string, logger Logger) *ProcessPlugin {
+	p := &ProcessPlugin{
+		manifest:    manifest,
+		dir:         dir,
+		logger:      logger,
+		timeout:     defaultProcessTimeout,
+		maxRestarts: defaultMaxRestarts,
+		backoff:     defaultRestartBackoff,
+	}
+	if rt := manifest.Runtime; rt != nil {
+		if d, err := time.ParseDuration(rt.Timeout); err == nil && d > 0 {
+			p.timeout = d
+		}
+		if rt.Restart.MaxRestarts != 0 {
+			p.maxRestarts = rt.Restart.MaxRestarts
+		}
+		if d, err := time.ParseDuration(rt.Restart.Backoff); err == nil && d > 0 {
+			p.backoff = d
+		}
+	}
+	return p
+}
+
+// Name 플러그인 이름
+func (p *ProcessPlugin) Name() string {
+	return p.manifest.Name
+}
+
+// Migrate 외부 플러그인은 매니페스트 migrations(SQL 파일)로만 스키마를 관리한다
+func (p *ProcessPlugin) Migrate(_ *gorm.DB) error {
+	return nil
+}
+
+// Initialize 프로세스 기동 + initialize 핸드셰이크
+func (p *ProcessPlugin) Initialize(ctx *PluginContext) error {
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.initParams = ProcessInitParams{
+		Name:     p.manifest.Name,
+		Version:  p.manifest.Version,
+		Config:   ctx.Config,
+		BasePath: p.dir,
+	}
+	p.stopping = false
+	p.failed = nil
+	p.restarts = 0
+	p.mu.Unlock()
+
+	return p.start()
+}
+
+// RegisterRoutes 매니페스트 routes 를 프로세스로 프록시
+// 샌드박스 타임아웃이 요청 컨텍스트에 걸리고, 만료 시 진행 중인 RPC 에 $/cancelRequest 가 전달된다.
+func (p *ProcessPlugin) RegisterRoutes(router gin.IRouter) {
+	sandbox := SandboxMiddleware(p.manifest.Name, SandboxConfig{
+		RequestTimeout: p.timeout,
+		RecoverPanics:  true,
+	}, p.logger)
+
+	for _, route := range p.manifest.Routes {
+		method := strings.ToUpper(route.Method)
+		if method == "" {
+			method = http.MethodGet
+		}
+		router.Handle(method, route.Path, sandbox, p.proxyHandler(route))
+	}
+}
+
+// Shutdown shutdown 알림 후 프로세스 종료 (유예 시간 내 종료하지 않으면 kill)
+func (p *ProcessPlugin) Shutdown() error {
+	p.mu.Lock()
+	p.stopping = true
+	conn, cmd, exited := p.conn, p.cmd, p.exited
+	p.mu.Unlock()
+
+	if conn == nil || cmd == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), processShutdownGrace)
+	defer cancel()
+	_ = conn.Call(ctx, ProcessMethodShutdown, struct{}{}, nil) //nolint:errcheck // process may exit before replying
+	_ = conn.Close()                                           //nolint:errcheck // closes stdin → EOF for well-behaved plugins
+
+	select {
+	case <-exited:
+	case <-ctx.Done():
+		if cmd.Process != nil {
+			_ = cmd.Process.Kill() //nolint:errcheck // best-effort
+		}
+		<-exited
+	}
+	return nil
+}
+
+// RegisterHooks 매니페스트 hooks 를 hook.invoke 호출로 연결
+func (p *ProcessPlugin) RegisterHooks(hm *HookManager) {
+	for _, h := range p.manifest.Hooks {
+		reg := h
+		hm.Register(reg.Event, p.manifest.Name, func(ctx *HookContext) error {
+			var res ProcessHookResult
+			if err := p.call(context.Background(), ProcessMethodHook, ProcessHookParams{
+				Event:   ctx.Event,
+				Handler: reg.Handler,
+				Input:   ctx.Input,
+			}, &res); err != nil {
+				return err
+			}
+			if res.Abort != "" {
+				return Abort(res.Abort)
+			}
+			if res.Output != nil {
+				ctx.SetOutput(res.Output)
+			}
+			return nil
+		}, reg.Priority)
+	}
+}
+
+// RegisterEvents 매니페스트 events 토픽을 event.deliver 호출로 연결
+func (p *ProcessPlugin) RegisterEvents(bus *EventBus) {
+	p.mu.Lock()
+	p.bus = bus
+	p.mu.Unlock()
+
+	for _, topic := range p.manifest.Events {
+		// 에러를 돌려주면 EventBus 가 재시도 후 dead-letter 로 옮긴다
+		bus.SubscribeFunc(p.manifest.Name, topic, func(event Event) error {
+			return p.call(context.Background(), ProcessMethodEvent, event, nil)
+		})
+	}
+}
+
+// RegisterSchedules 매니페스트 schedules 를 schedule.run 호출로 연결
+func (p *ProcessPlugin) RegisterSchedules(scheduler *Scheduler) {
+	for _, sc := range p.manifest.Schedules {
+		opts, err := sc.TaskOptions()
+		if err != nil {
+			continue // LoadManifest 에서 이미 검증됨
+		}
+		name := sc.Name
+		if err := scheduler.RegisterTask(p.manifest.Name, name, opts, func(ctx context.Context) error {
+			return p.call(ctx, ProcessMethodSchedule, map[string]string{"name": name}, nil)
+		}); err != nil {
+			p.logger.Error("Plugin %s schedule %s not registered: %v", p.manifest.Name, name, err)
+		}
+	}
+}
+
+// HealthCheck 프로세스 상태 + health 호출
+func (p *ProcessPlugin) HealthCheck() error {
+	p.mu.RLock()
+	failed := p.failed
+	p.mu.RUnlock()
+	if failed != nil {
+		return failed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var res ProcessHealthResult
+	if err := p.call(ctx, ProcessMethodHealth, struct{}{}, &res); err != nil {
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) && rpcErr.Code == RPCErrMethodNotFound {
+			return nil // health 를 구현하지 않은 플러그인은 살아 있으면 정상
+		}
+		return err
+	}
+	if res.Status != "" && res.Status != "ok" {
+		return fmt.Errorf("%s: %s", res.Status, res.Message)
+	}
+	return nil
+}
+
+// Running 프로세스 실행 여부 (모니터링용)
+func (p *ProcessPlugin) Running() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.running
+}
+
+// call 현재 연결로 RPC 호출 (ctx 에 더 짧은 마감이 없으면 런타임 timeout 적용)
+func (p *ProcessPlugin) call(ctx context.Context, method string, params, result interface{}) error {
+	p.mu.RLock()
+	conn := p.conn
+	running := p.running
+	p.mu.RUnlock()
+	if conn == nil || !running {
+		return ErrPluginUnavailable
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	return conn.Call(ctx, method, params, result)
+}
+
+// proxyHandler 매니페스트 라우트 1개에 대한 gin 핸들러
+func (p *ProcessPlugin) proxyHandler(route RouteConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, 10<<20))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"code": "BAD_REQUEST", "message": "요청 본문을 읽을 수 없습니다"}})
+			return
+		}
+
+		req := ProcessHTTPRequest{
+			Method:  c.Request.Method,
+			Route:   route.Path,
+			Path:    c.Request.URL.Path,
+			Handler: route.Handler,
+			Params:  make(map[string]string, len(c.Params)),
+			Query:   c.Request.URL.Query(),
+			Headers: make(map[string]string),
+			Body:    string(body),
+		}
+		for _, prm := range c.Params {
+			req.Params[prm.Key] = prm.Value
+		}
+		for k, v := range c.Request.Header {
+			switch http.CanonicalHeaderKey(k) {
+			case "Authorization", "Cookie":
+				continue
+			}
+			req.Headers[k] = strings.Join(v, ", ")
+		}
+		if uid, ok := c.Get("userID"); ok {
+			user := &ProcessUser{}
+			user.ID, _ = uid.(string)
+			if v, ok := c.Get("nickname"); ok {
+				user.Nickname, _ = v.(string)
+			}
+			if v, ok := c.Get("level"); ok {
+				user.Level, _ = v.(int)
+			}
+			req.User = user
+		}
+
+		var resp ProcessHTTPResponse
+		if err := p.call(c.Request.Context(), ProcessMethodHTTP, req, &resp); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return // SandboxMiddleware 가 504 를 응답한다
+			}
+			status := http.StatusBadGateway
+			if errors.Is(err, ErrPluginUnavailable) || errors.Is(err, ErrRPCClosed) {
+				status = http.StatusServiceUnavailable
+			}
+			p.logger.Warn("Plugin %s route %s %s failed: %v", p.manifest.Name, req.Method, route.Path, err)
+			c.JSON(status, gin.H{"error": gin.H{
+				"code":    "PLUGIN_UNAVAILABLE",
+				"message": fmt.Sprintf("플러그인 %s 이(가) 응답하지 않습니다", p.manifest.Name),
+			}})
+			return
+		}
+
+		if resp.Status == 0 {
+			resp.Status = http.StatusOK
+		}
+		for k, v := range resp.Headers {
+			c.Header(k, v)
+		}
+		if len(resp.JSON) > 0 {
+			c.Data(resp.Status, "application/json; charset=utf-8", resp.JSON)
+			return
+		}
+		contentType := resp.Headers["Content-Type"]
+		if contentType == "" {
+			contentType = "text/plain; charset=utf-8"
+		}
+		c.Data(resp.Status, contentType, []byte(resp.Body))
+	}
+}
+
+// start 프로세스 기동 → 연결 → initialize → supervise 루프 시작
+func (p *ProcessPlugin) start() error {
+	cmd, conn, socketDir, err := p.spawn()
+	if err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+	ready := make(chan error, 1) // initialize 결과 — supervise 는 이걸 보고 재시작 여부를 정한다
+	p.mu.Lock()
+	p.cmd, p.conn, p.socketDir, p.exited = cmd, conn, socketDir, exited
+	p.running = true
+	initParams := p.initParams
+	p.mu.Unlock()
+
+	go p.supervise(cmd, conn, exited, ready, time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	if err := conn.Call(ctx, ProcessMethodInitialize, initParams, nil); err != nil {
+		ready <- err           // 재시작은 호출자(Initialize 또는 supervise 루프)가 결정한다
+		_ = cmd.Process.Kill() //nolint:errcheck // handshake failed
+		<-exited
+		return fmt.Errorf("plugin %s initialize failed: %w", p.manifest.Name, err)
+	}
+	ready <- nil
+
+	p.logger.Info("Plugin process started: %s (pid %d)", p.manifest.Name, cmd.Process.Pid)
+	return nil
+}
+
+// supervise 프로세스 종료를 기다렸다가, 의도한 종료가 아니면 backoff 후 재기동한다.
+// initialize 중에 죽었으면 start 가 에러를 돌려주므로 여기서는 재시작하지 않는다.
+func (p *ProcessPlugin) supervise(cmd *exec.Cmd, conn *rpcConn, exited chan struct{}, ready <-chan error, startedAt time.Time) {
+	waitErr := cmd.Wait()
+	_ = conn.Close() //nolint:errcheck // pending calls fail with ErrRPCClosed
+
+	p.mu.Lock()
+	p.running = false
+	p.conn = nil
+	if p.socketDir != "" {
+		_ = os.RemoveAll(p.socketDir) //nolint:errcheck // temp dir cleanup
+		p.socketDir = ""
+	}
+	stopping := p.stopping
+	if time.Since(startedAt) >= processStableAfter {
+		p.restarts = 0
+	}
+	p.mu.Unlock()
+	close(exited)
+
+	// 핸드셰이크 결과를 기다린다 — 위에서 conn 을 닫았으므로 진행 중인 initialize 는 곧 실패한다
+	if initErr := <-ready; stopping || initErr != nil {
+		return
+	}
+
+	for {
+		p.mu.Lock()
+		if p.stopping {
+			p.mu.Unlock()
+			return
+		}
+		if p.maxRestarts < 0 || p.restarts >= p.maxRestarts {
+			p.failed = fmt.Errorf("plugin process exited (%v); restart limit %d reached", waitErr, p.maxRestarts)
+			p.mu.Unlock()
+			p.logger.Error("Plugin %s: %v", p.manifest.Name, p.failed)
+			return
+		}
+		delay := p.backoff << p.restarts
+		if delay > maxRestartBackoff || delay <= 0 {
+			delay = maxRestartBackoff
+		}
+		p.restarts++
+		attempt := p.restarts
+		p.mu.Unlock()
+
+		p.logger.Warn("Plugin %s process exited (%v); restarting in %s (attempt %d/%d)",
+			p.manifest.Name, waitErr, delay, attempt, p.maxRestarts)
+		time.Sleep(delay)
+
+		if err := p.start(); err != nil {
+			p.logger.Error("Plugin %s restart failed: %v", p.manifest.Name, err)
+			waitErr = err
+			continue
+		}
+		return
+	}
+}
+
+// spawn 실행 파일 기동 후 전송 계층 연결
+func (p *ProcessPlugin) spawn() (*exec.Cmd, *rpcConn, string, error) {
+	rt := p.manifest.Runtime
+	command := rt.Command
+	if !filepath.IsAbs(command) && strings.ContainsRune(command, filepath.Separator) {
+		command = filepath.Join(p.dir, command)
+	}
+
+	cmd := exec.Command(command, rt.Args...) // #nosec G204 -- command comes from an installed plugin manifest
+	cmd.Dir = p.dir
+	cmd.Env = append(os.Environ(),
+		"ANGPLE_PLUGIN_NAME="+p.manifest.Name,
+		"ANGPLE_PLUGIN_VERSION="+p.manifest.Version,
+	)
+	for k, v := range rt.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stderr = &processLogWriter{plugin: p.manifest.Name, logger: p.logger}
+
+	if rt.Transport == TransportUnix {
+		return p.spawnUnix(cmd)
+	}
+
+	cmd.Env = append(cmd.Env, "ANGPLE_PLUGIN_TRANSPORT="+TransportStdio)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, "", fmt.Errorf("start plugin %s: %w", p.manifest.Name, err)
+	}
+	conn := newRPCConn(&stdioPipe{r: stdout, w: stdin}, p.handleNotify)
+	return cmd, conn, "", nil
+}
+
+// spawnUnix 임시 unix 소켓을 열고 플러그인이 ANGPLE_PLUGIN_SOCKET 으로 접속하기를 기다린다
+func (p *ProcessPlugin) spawnUnix(cmd *exec.Cmd) (*exec.Cmd, *rpcConn, string, error) {
+	socketDir, err := os.MkdirTemp("", "angple-plugin-")
+	if err != nil {
+		return nil, nil, "", err
+	}
+	socketPath := filepath.Join(socketDir, "plugin.sock")
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		_ = os.RemoveAll(socketDir) //nolint:errcheck // cleanup
+		return nil, nil, "", err
+	}
+	defer ln.Close()
+
+	cmd.Env = append(cmd.Env,
+		"ANGPLE_PLUGIN_TRANSPORT="+TransportUnix,
+		"ANGPLE_PLUGIN_SOCKET="+socketPath,
+	)
+	cmd.Stdout = &processLogWriter{plugin: p.manifest.Name, logger: p.logger}
+	if err := cmd.Start(); err != nil {
+		_ = os.RemoveAll(socketDir) //nolint:errcheck // cleanup
+		return nil, nil, "", fmt.Errorf("start plugin %s: %w", p.manifest.Name, err)
+	}
+
+	if ul, ok := ln.(*net.UnixListener); ok {
+		_ = ul.SetDeadline(time.Now().Add(unixSocketAcceptTimeout)) //nolint:errcheck // best-effort deadline
+	}
+	sock, err := ln.Accept()
+	if err != nil {
+		_ = cmd.Process.Kill() //nolint:errcheck // never connected
+		_ = cmd.Wait()         //nolint:errcheck // reap
+		_ = os.RemoveAll(socketDir)
+		return nil, nil, "", fmt.Errorf("plugin %s did not connect to %s: %w", p.manifest.Name, socketPath, err)
+	}
+	return cmd, newRPCConn(sock, p.handleNotify), socketDir, nil
+}
+
+// handleNotify 플러그인 → 호스트 알림 처리
+func (p *ProcessPlugin) handleNotify(method string, params json.RawMessage) {
+	switch method {
+	case ProcessNotifyLog:
+		var lp processLogParams
+		if json.Unmarshal(params, &lp) != nil {
+			return
+		}
+		switch lp.Level {
+		case "debug":
+			p.logger.Debug("[%s] %s", p.manifest.Name, lp.Message)
+		case "warn":
+			p.logger.Warn("[%s] %s", p.manifest.Name, lp.Message)
+		case "error":
+			p.logger.Error("[%s] %s", p.manifest.Name, lp.Message)
+		default:
+			p.logger.Info("[%s] %s", p.manifest.Name, lp.Message)
+		}
+	case ProcessNotifyPublish:
+		var pp processPublishParams
+		if json.Unmarshal(params, &pp) != nil || pp.Topic == "" {
+			return
+		}
+		p.mu.RLock()
+		bus := p.bus
+		p.mu.RUnlock()
+		if bus != nil {
+			// 수신 루프를 막지 않도록 비동기 — 구독자가 같은 플러그인이어도 교착이 없다
+			bus.PublishAsync(p.manifest.Name, pp.Topic, pp.Payload)
+		}
+	}
+}
+
+// stdioPipe 자식 프로세스 stdout/stdin 을 하나의 ReadWriteCloser 로 묶음
+type stdioPipe struct {
+	r io.ReadCloser
+	w io.WriteCloser
+}
+
+func (s *stdioPipe) Read(b []byte) (int, error)  { return s.r.Read(b) }
+func (s *stdioPipe) Write(b []byte) (int, error) { return s.w.Write(b) }
+func (s *stdioPipe) Close() error {
+	werr := s.w.Close()
+	// stdout 은 cmd.Wait 가 닫는다 — 여기서 닫으면 Wait 와 경합한다
+	return werr
+}
+
+// processLogWriter 플러그인 stderr 를 줄 단위로 로거에 전달
+type processLogWriter struct {
+	plugin string
+	logger Logger
+	mu     sync.Mutex
+	buf    []byte
+}
+
+func (w *processLogWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break // 개행으로 끝나지 않은 조각은 다음 Write 까지 보관
+		}
+		if line := strings.TrimRight(string(w.buf[:i]), "\r"); line != "" {
+			w.logger.Info("[%s] %s", w.plugin, line)
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(b), nil
+}
@@ -0,0 +1,264 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestProcessPluginHelper 테스트 바이너리를 외부 플러그인으로 재사용한다.
+// ANGPLE_PLUGIN_HELPER=1 로 실행될 때만 동작하고, 일반 테스트 실행에서는 바로 통과한다.
+func TestProcessPluginHelper(_ *testing.T) {
+	if os.Getenv("ANGPLE_PLUGIN_HELPER") != "1" {
+		return
+	}
+
+	var rw io.ReadWriter = struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}
+	if sock := os.Getenv("ANGPLE_PLUGIN_SOCKET"); sock != "" {
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			os.Exit(2)
+		}
+		rw = conn
+	}
+	if path := os.Getenv("ANGPLE_PLUGIN_HELPER_STARTS"); path != "" {
+		if f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600); err == nil {
+			_, _ = f.WriteString("start\n")
+			_ = f.Close()
+		}
+	}
+
+	enc := json.NewEncoder(rw)
+	reply := func(id *int64, result interface{}) {
+		raw, _ := json.Marshal(result)
+		_ = enc.Encode(rpcMessage{JSONRPC: jsonRPCVersion, ID: id, Result: raw})
+	}
+
+	sc := bufio.NewScanner(rw)
+	for sc.Scan() {
+		var msg rpcMessage
+		if json.Unmarshal(sc.Bytes(), &msg) != nil || msg.ID == nil {
+			continue
+		}
+		switch msg.Method {
+		case ProcessMethodInitialize:
+			if os.Getenv("ANGPLE_PLUGIN_HELPER_INIT") == "exit" {
+				os.Exit(4)
+			}
+			reply(msg.ID, struct{}{})
+		case ProcessMethodSchedule:
+			reply(msg.ID, struct{}{})
+		case ProcessMethodHTTP:
+			var req ProcessHTTPRequest
+			_ = json.Unmarshal(msg.Params, &req)
+			if req.Route == "/crash" {
+				os.Exit(3)
+			}
+			body, _ := json.Marshal(map[string]interface{}{
+				"id":       req.Params["id"],
+				"q":        req.Query["q"],
+				"has_auth": req.Headers["Authorization"] != "",
+				"pid":      os.Getpid(),
+			})
+			reply(msg.ID, ProcessHTTPResponse{Status: http.StatusCreated, JSON: body})
+		case ProcessMethodHook:
+			var hp ProcessHookParams
+			_ = json.Unmarshal(msg.Params, &hp)
+			content, _ := hp.Input["content"].(string)
+			if strings.Contains(content, "spam") {
+				reply(msg.ID, ProcessHookResult{Abort: "스팸으로 판단되었습니다"})
+				continue
+			}
+			reply(msg.ID, ProcessHookResult{Output: map[string]interface{}{"content": strings.ToUpper(content)}})
+		case ProcessMethodHealth:
+			reply(msg.ID, ProcessHealthResult{Status: "ok"})
+		case ProcessMethodShutdown:
+			reply(msg.ID, struct{}{})
+			os.Exit(0)
+		default:
+			_ = enc.Encode(rpcMessage{JSONRPC: jsonRPCVersion, ID: msg.ID, Error: &RPCError{Code: RPCErrMethodNotFound, Message: msg.Method}})
+		}
+	}
+	os.Exit(0)
+}
+
+func newHelperProcessPlugin(t *testing.T, transport string) *ProcessPlugin {
+	t.Helper()
+	manifest := &PluginManifest{
+		Name:    "proc-test",
+		Version: "1.0.0",
+		Routes: []RouteConfig{
+			{Path: "/items/:id", Method: "GET", Handler: "getItem"},
+			{Path: "/crash", Method: "POST", Handler: "crash"},
+		},
+		Hooks: []HookRegistration{{Event: HookPostBeforeCreate, Handler: "checkSpam", Priority: 10}},
+		Runtime: &RuntimeConfig{
+			Type:      RuntimeProcess,
+			Command:   os.Args[0],
+			Args:      []string{"-test.run=^TestProcessPluginHelper$"},
+			Env:       map[string]string{"ANGPLE_PLUGIN_HELPER": "1"},
+			Transport: transport,
+			Timeout:   "5s",
+			Restart:   RestartPolicy{MaxRestarts: 3, Backoff: "10ms"},
+		},
+	}
+	p := NewProcessPlugin(manifest, t.TempDir(), &testLogger{})
+	if err := p.Initialize(&PluginContext{Config: map[string]interface{}{}}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	t.Cleanup(func() { _ = p.Shutdown() })
+	return p
+}
+
+func newProcessRouter(p *ProcessPlugin) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	p.RegisterRoutes(r.Group("/api/plugins/proc-test"))
+	return r
+}
+
+func TestProcessPluginRouteProxy(t *testing.T) {
+	for _, transport := range []string{TransportStdio, TransportUnix} {
+		t.Run(transport, func(t *testing.T) {
+			p := newHelperProcessPlugin(t, transport)
+			r := newProcessRouter(p)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/plugins/proc-test/items/42?q=go", nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusCreated {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+			}
+			var body struct {
+				ID      string   `json:"id"`
+				Q       []string `json:"q"`
+				HasAuth bool     `json:"has_auth"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.ID != "42" || len(body.Q) != 1 || body.Q[0] != "go" {
+				t.Errorf("unexpected body: %+v", body)
+			}
+			if body.HasAuth {
+				t.Error("Authorization header must not be forwarded to plugin process")
+			}
+			if err := p.HealthCheck(); err != nil {
+				t.Errorf("HealthCheck: %v", err)
+			}
+		})
+	}
+}
+
+func TestProcessPluginHooks(t *testing.T) {
+	p := newHelperProcessPlugin(t, TransportStdio)
+	hm := newTestHookManager()
+	p.RegisterHooks(hm)
+
+	out, err := hm.ApplyBefore(HookPostBeforeCreate, map[string]interface{}{"content": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected abort: %v", err)
+	}
+	if out["content"] != "HELLO" {
+		t.Errorf("content = %v", out["content"])
+	}
+
+	_, err = hm.ApplyBefore(HookPostBeforeCreate, map[string]interface{}{"content": "buy spam"})
+	abort, ok := err.(*HookAbortError)
+	if !ok || abort.Plugin != "proc-test" || abort.Reason != "스팸으로 판단되었습니다" {
+		t.Errorf("expected abort from proc-test, got %v", err)
+	}
+}
+
+func TestProcessPluginRestartsAfterCrash(t *testing.T) {
+	p := newHelperProcessPlugin(t, TransportStdio)
+	r := newProcessRouter(p)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/plugins/proc-test/crash", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("crash status = %d, want 503", w.Code)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/plugins/proc-test/items/1", nil))
+		if w.Code == http.StatusCreated {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("plugin not restarted, last status %d", w.Code)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestProcessPluginShutdownStopsProcess(t *testing.T) {
+	p := newHelperProcessPlugin(t, TransportStdio)
+	if err := p.Shutdown(); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if p.Running() {
+		t.Error("process should not be running after Shutdown")
+	}
+
+	w := httptest.NewRecorder()
+	newProcessRouter(p).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/plugins/proc-test/items/1", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status after shutdown = %d, want 503", w.Code)
+	}
+}
+
+func TestProcessPluginExitDuringInitializeDoesNotRestart(t *testing.T) {
+	starts := filepath.Join(t.TempDir(), "starts")
+	manifest := &PluginManifest{
+		Name:    "proc-init-exit",
+		Version: "1.0.0",
+		Runtime: &RuntimeConfig{
+			Type:    RuntimeProcess,
+			Command: os.Args[0],
+			Args:    []string{"-test.run=^TestProcessPluginHelper$"},
+			Env: map[string]string{
+				"ANGPLE_PLUGIN_HELPER":        "1",
+				"ANGPLE_PLUGIN_HELPER_INIT":   "exit",
+				"ANGPLE_PLUGIN_HELPER_STARTS": starts,
+			},
+			Timeout: "5s",
+			Restart: RestartPolicy{MaxRestarts: 3, Backoff: "10ms"},
+		},
+	}
+	p := NewProcessPlugin(manifest, t.TempDir(), &testLogger{})
+	t.Cleanup(func() { _ = p.Shutdown() })
+
+	if err := p.Initialize(&PluginContext{Config: map[string]interface{}{}}); err == nil {
+		t.Fatal("Initialize should fail when the process exits during the handshake")
+	}
+	// 실패를 보고한 플러그인이 뒤에서 다시 뜨면 안 된다 (backoff 10ms × 재시작 3회보다 넉넉히 기다린다)
+	time.Sleep(300 * time.Millisecond)
+	raw, err := os.ReadFile(starts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(raw), "start"); n != 1 {
+		t.Errorf("process started %d times, want 1", n)
+	}
+	if p.Running() {
+		t.Error("plugin should not be running after a failed initialize")
+	}
+}
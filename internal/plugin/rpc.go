@@ -0,0 +1,205 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// 외부 프로세스 플러그인과 주고받는 JSON-RPC 2.0 메시지.
+// 전송 형식은 줄 단위 JSON(한 줄 = 한 메시지)이다. stdio·unix 소켓 모두 동일하다.
+
+// ErrRPCClosed 연결이 끊겨 응답을 받을 수 없음
+var ErrRPCClosed = errors.New("plugin rpc connection closed")
+
+const jsonRPCVersion = "2.0"
+
+// JSON-RPC 표준 에러 코드
+const (
+	RPCErrParse          = -32700
+	RPCErrInvalidRequest = -32600
+	RPCErrMethodNotFound = -32601
+	RPCErrInternal       = -32603
+)
+
+// rpcMessage 요청·응답·알림 공용 구조 (id 가 없으면 알림)
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError JSON-RPC 에러 객체
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// rpcNotifyHandler 상대가 보낸 알림(id 없는 메시지) 처리기
+type rpcNotifyHandler func(method string, params json.RawMessage)
+
+// rpcConn 양방향 JSON-RPC 연결
+// 호스트 → 플러그인 호출(Call/Notify)과 플러그인 → 호스트 알림(log, event.publish 등)을 한 연결에서 처리한다.
+type rpcConn struct {
+	rwc      io.ReadWriteCloser
+	writeMu  sync.Mutex
+	enc      *json.Encoder
+	nextID   atomic.Int64
+	mu       sync.Mutex
+	pending  map[int64]chan *rpcMessage
+	closed   bool
+	done     chan struct{}
+	onNotify rpcNotifyHandler
+}
+
+// newRPCConn 연결 생성 후 수신 루프 시작
+func newRPCConn(rwc io.ReadWriteCloser, onNotify rpcNotifyHandler) *rpcConn {
+	c := &rpcConn{
+		rwc:      rwc,
+		enc:      json.NewEncoder(rwc),
+		pending:  make(map[int64]chan *rpcMessage),
+		done:     make(chan struct{}),
+		onNotify: onNotify,
+	}
+	go c.readLoop()
+	return c
+}
+
+// Call 요청 전송 후 응답 대기. ctx 가 끝나면 상대에게 $/cancelRequest 를 알리고 ctx 에러를 반환한다.
+func (c *rpcConn) Call(ctx context.Context, method string, params, result interface{}) error {
+	id := c.nextID.Add(1)
+	ch := make(chan *rpcMessage, 1)
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrRPCClosed
+	}
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("marshal %s params: %w", method, err)
+	}
+	if err := c.write(&rpcMessage{JSONRPC: jsonRPCVersion, ID: &id, Method: method, Params: raw}); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		_ = c.Notify("$/cancelRequest", map[string]int64{"id": id}) //nolint:errcheck // best-effort cancel
+		return ctx.Err()
+	case <-c.done:
+		return ErrRPCClosed
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && len(resp.Result) > 0 {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("unmarshal %s result: %w", method, err)
+			}
+		}
+		return nil
+	}
+}
+
+// Notify 응답을 기다리지 않는 알림 전송
+func (c *rpcConn) Notify(method string, params interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("marshal %s params: %w", method, err)
+	}
+	return c.write(&rpcMessage{JSONRPC: jsonRPCVersion, Method: method, Params: raw})
+}
+
+// Close 연결 종료 (대기 중인 Call 은 ErrRPCClosed 로 끝난다)
+func (c *rpcConn) Close() error {
+	c.shutdown()
+	return c.rwc.Close()
+}
+
+// Done 연결이 끊기면 닫히는 채널
+func (c *rpcConn) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *rpcConn) write(msg *rpcMessage) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := c.enc.Encode(msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrRPCClosed, err)
+	}
+	return nil
+}
+
+func (c *rpcConn) readLoop() {
+	defer c.shutdown()
+
+	scanner := bufio.NewScanner(c.rwc)
+	// 라우트 응답 본문이 실리므로 기본 64KB 로는 부족하다
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var msg rpcMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue // 플러그인이 stdout 에 찍은 잡음은 무시
+		}
+
+		switch {
+		case msg.ID != nil && msg.Method == "":
+			// 꺼내면서 지운다 — 같은 id 의 중복·늦은 응답은 버려지고, 버퍼 1 인 ch 에 두 번 보내다 막히지 않는다
+			c.mu.Lock()
+			ch, ok := c.pending[*msg.ID]
+			delete(c.pending, *msg.ID)
+			c.mu.Unlock()
+			if ok {
+				ch <- &msg
+			}
+		case msg.ID == nil && msg.Method != "":
+			if c.onNotify != nil {
+				c.onNotify(msg.Method, msg.Params)
+			}
+		case msg.ID != nil:
+			// 호스트는 플러그인 → 호스트 요청을 받지 않는다 (알림만 허용)
+			_ = c.write(&rpcMessage{ //nolint:errcheck // peer may already be gone
+				JSONRPC: jsonRPCVersion,
+				ID:      msg.ID,
+				Error:   &RPCError{Code: RPCErrMethodNotFound, Message: "host accepts notifications only"},
+			})
+		}
+	}
+}
+
+func (c *rpcConn) shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.done)
+}
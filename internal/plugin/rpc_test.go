@@ -0,0 +1,139 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakePeer net.Pipe 반대편에서 플러그인 역할을 한다
+func fakePeer(t *testing.T, conn net.Conn, handle func(msg rpcMessage, enc *json.Encoder)) {
+	t.Helper()
+	go func() {
+		enc := json.NewEncoder(conn)
+		sc := bufio.NewScanner(conn)
+		for sc.Scan() {
+			var msg rpcMessage
+			if err := json.Unmarshal(sc.Bytes(), &msg); err != nil {
+				continue
+			}
+			handle(msg, enc)
+		}
+	}()
+}
+
+func TestRPCConnCallAndNotify(t *testing.T) {
+	host, peer := net.Pipe()
+	defer peer.Close()
+
+	notified := make(chan string, 1)
+	conn := newRPCConn(host, func(method string, params json.RawMessage) {
+		notified <- method + ":" + string(params)
+	})
+	defer conn.Close()
+
+	fakePeer(t, peer, func(msg rpcMessage, enc *json.Encoder) {
+		switch msg.Method {
+		case "echo":
+			_ = enc.Encode(rpcMessage{JSONRPC: jsonRPCVersion, ID: msg.ID, Result: msg.Params})
+			_ = enc.Encode(rpcMessage{JSONRPC: jsonRPCVersion, Method: "log", Params: json.RawMessage(`"hi"`)})
+		default:
+			_ = enc.Encode(rpcMessage{JSONRPC: jsonRPCVersion, ID: msg.ID, Error: &RPCError{Code: RPCErrMethodNotFound, Message: "nope"}})
+		}
+	})
+
+	var out map[string]string
+	if err := conn.Call(context.Background(), "echo", map[string]string{"a": "b"}, &out); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if out["a"] != "b" {
+		t.Errorf("unexpected result: %v", out)
+	}
+
+	select {
+	case got := <-notified:
+		if got != `log:"hi"` {
+			t.Errorf("unexpected notification: %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("notification not delivered")
+	}
+
+	err := conn.Call(context.Background(), "missing", nil, nil)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != RPCErrMethodNotFound {
+		t.Errorf("expected method-not-found, got %v", err)
+	}
+}
+
+func TestRPCConnCancelSendsCancelRequest(t *testing.T) {
+	host, peer := net.Pipe()
+	defer peer.Close()
+
+	conn := newRPCConn(host, nil)
+	defer conn.Close()
+
+	cancelled := make(chan struct{}, 1)
+	fakePeer(t, peer, func(msg rpcMessage, _ *json.Encoder) {
+		if msg.Method == "$/cancelRequest" {
+			cancelled <- struct{}{}
+		}
+		// slow 는 응답하지 않는다
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := conn.Call(ctx, "slow", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("$/cancelRequest not sent")
+	}
+}
+
+func TestRPCConnClosedFailsPendingCalls(t *testing.T) {
+	host, peer := net.Pipe()
+	conn := newRPCConn(host, nil)
+
+	fakePeer(t, peer, func(_ rpcMessage, _ *json.Encoder) {
+		peer.Close() // 응답 전에 끊김 (프로세스 크래시)
+	})
+
+	if err := conn.Call(context.Background(), "any", nil, nil); !errors.Is(err, ErrRPCClosed) {
+		t.Fatalf("expected ErrRPCClosed, got %v", err)
+	}
+	if err := conn.Call(context.Background(), "again", nil, nil); !errors.Is(err, ErrRPCClosed) {
+		t.Errorf("calls after close should fail fast, got %v", err)
+	}
+}
+
+func TestRPCConnDuplicateResponseDoesNotStallReadLoop(t *testing.T) {
+	host, peer := net.Pipe()
+	defer peer.Close()
+
+	conn := newRPCConn(host, nil)
+	defer conn.Close()
+
+	fakePeer(t, peer, func(msg rpcMessage, enc *json.Encoder) {
+		// 같은 id 로 여러 번 응답하는 플러그인
+		for i := 0; i < 3; i++ {
+			_ = enc.Encode(rpcMessage{JSONRPC: jsonRPCVersion, ID: msg.ID, Result: msg.Params})
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, method := range []string{"first", "second"} {
+		var out string
+		if err := conn.Call(ctx, method, method, &out); err != nil || out != method {
+			t.Fatalf("%s: got %q, %v", method, out, err)
+		}
+	}
+}
@@ -1,62 +1,275 @@
 package plugin
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
 	"sync"
 	"time"
 )
 
+const (
+	schedulerTickInterval = time.Second
+	schedulerStopGrace    = 5 * time.Second // Stop 시 실행 중 작업 대기 상한
+	schedulerLockPrefix   = "angple:scheduler:"
+	schedulerRunLockTTL   = 30 * time.Minute // Timeout 없는 작업의 실행 락 만료
+)
+
+// 실행 계기
+const (
+	TriggerSchedule = "schedule" // 스케줄러 tick
+	TriggerManual   = "manual"   // 관리자 API
+	TriggerHTTP     = "http"     // 외부 crontab 호환 엔드포인트
+)
+
+var (
+	// ErrTaskNotFound 등록되지 않은 작업
+	ErrTaskNotFound = errors.New("scheduled task not found")
+	// ErrTaskBusy 이 인스턴스나 다른 인스턴스에서 이미 실행 중
+	ErrTaskBusy = errors.New("scheduled task is already running")
+	// ErrDryRunUnsupported dry-run 을 지원하지 않는 작업
+	ErrDryRunUnsupported = errors.New("scheduled task does not support dry-run")
+)
+
+// TaskFunc 실행 기록을 받는 작업 핸들러 - run.DryRun 을 존중하고 결과는 run.SetResult 로 남긴다
+type TaskFunc func(ctx context.Context, run *ScheduleRun) error
+
+// TaskOptions 작업 등록 옵션 - Manual 이 아니면 Cron 과 Interval 중 하나는 필수
+type TaskOptions struct {
+	Cron        string        // 5필드 cron 표현식 또는 @daily 등 (Interval 보다 우선)
+	Interval    time.Duration // 고정 간격
+	Timezone    string        // Cron 기준 시간대 (예: Asia/Seoul, 기본 UTC)
+	Jitter      time.Duration // 예정 시각에 0~Jitter 임의 지연을 더한다
+	Timeout     time.Duration // 실행 제한 시간 (0 이면 제한 없음)
+	Manual      bool          // 자동 실행 없이 RunNow 로만 실행 (Cron 은 표시·검증용으로만 남는다)
+	DryRun      bool          // 핸들러가 dry-run 을 지원하는지
+	Description string
+}
+
+// schedule 옵션으로 다음 실행 시각 계산기 생성
+func (o TaskOptions) schedule() (taskSchedule, error) {
+	if o.Manual {
+		if o.Cron != "" || o.Interval > 0 {
+			if _, err := (TaskOptions{Cron: o.Cron, Interval: o.Interval, Timezone: o.Timezone}).schedule(); err != nil {
+				return nil, err
+			}
+		}
+		return manualSchedule{}, nil
+	}
+	if o.Cron != "" {
+		loc, err := loadTaskLocation(o.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		return ParseCron(o.Cron, loc)
+	}
+	if o.Interval <= 0 {
+		return nil, errors.New("cron or positive interval is required")
+	}
+	return intervalSchedule(o.Interval), nil
+}
+
+// TaskOptions 매니페스트 schedules 항목을 스케줄러 등록 옵션으로 변환
+func (sc ScheduleConfig) TaskOptions() (TaskOptions, error) {
+	opts := TaskOptions{Cron: sc.Cron, Timezone: sc.Timezone}
+	durations := []struct {
+		field string
+		value string
+		dst   *time.Duration
+	}{
+		{"interval", sc.Interval, &opts.Interval},
+		{"jitter", sc.Jitter, &opts.Jitter},
+		{"timeout", sc.Timeout, &opts.Timeout},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil || v < 0 {
+			return opts, fmt.Errorf("invalid schedule %s %q for %s", d.field, d.value, sc.Name)
+		}
+		*d.dst = v
+	}
+	if _, err := opts.schedule(); err != nil {
+		return opts, fmt.Errorf("invalid schedule %s: %w", sc.Name, err)
+	}
+	return opts, nil
+}
+
+// loadTaskLocation 시간대 로드. 컨테이너에 tzdata 가 없어도 KST 는 동작해야 한다.
+func loadTaskLocation(name string) (*time.Location, error) {
+	if name == "" || name == "UTC" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err == nil {
+		return loc, nil
+	}
+	if name == "Asia/Seoul" || name == "KST" {
+		return time.FixedZone("KST", 9*60*60), nil
+	}
+	return nil, fmt.Errorf("unknown timezone %q: %w", name, err)
+}
+
 // ScheduledTask 등록된 주기적 작업
 type ScheduledTask struct {
-	Name       string
-	PluginName string
-	Interval   time.Duration
-	Handler    func() error
-	LastRun    time.Time
-	NextRun    time.Time
-	RunCount   int64
-	LastError  error
+	Name         string
+	PluginName   string
+	Interval     time.Duration
+	Cron         string
+	Timezone     string
+	Jitter       time.Duration
+	Timeout      time.Duration
+	Manual       bool
+	DryRun       bool
+	Description  string
+	Handler      TaskFunc
+	LastRun      time.Time
+	NextRun      time.Time
+	RunCount     int64
+	SkipCount    int64 // 이전 실행이 끝나지 않아 건너뛴 횟수
+	LastError    error
+	LastDuration time.Duration
+
+	schedule taskSchedule
+	slot     time.Time // 지터 적용 전 예정 시각 - 인스턴스 간 분산 락 키
+	running  bool
+}
+
+// TaskLocker 여러 인스턴스 중 한 곳에서만 작업을 실행하기 위한 분산 락
+type TaskLocker interface {
+	// TryLock key 를 ttl 동안 선점한다. 다른 인스턴스가 이미 잡았으면 false.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Unlock 이 인스턴스가 잡은 key 를 푼다 (남이 잡은 락은 건드리지 않는다)
+	Unlock(ctx context.Context, key string) error
+}
+
+// RunRequest 즉시 실행 요청
+type RunRequest struct {
+	Trigger     string // 비면 TriggerManual
+	TriggeredBy string // 실행한 관리자 등
+	DryRun      bool
 }
 
 // Scheduler 플러그인 스케줄러 (in-process)
+//
+// 작업마다 별도 고루틴에서 실행되고, 같은 작업은 이전 실행이 끝나기 전까지 다시 시작하지 않는다.
+// locker 가 설정되면 예정 시각(슬롯)마다 한 인스턴스만 실행하고, 실행 중에는 다른 인스턴스의
+// 스케줄·즉시 실행도 건너뛴다.
 type Scheduler struct {
-	tasks  []*ScheduledTask
-	mu     sync.RWMutex
-	logger Logger
-	stop   chan struct{}
-	wg     sync.WaitGroup
+	tasks    []*ScheduledTask
+	mu       sync.RWMutex
+	logger   Logger
+	locker   TaskLocker
+	runs     ScheduleRunStore
+	hooks    []func(run *ScheduleRun)
+	instance string
+	stop     chan struct{}
+	wg       sync.WaitGroup // tick 루프
+	runWG    sync.WaitGroup // 실행 중 작업
+	ctx      context.Context
+	cancel   context.CancelFunc
 }
 
 // NewScheduler 스케줄러 생성
 func NewScheduler(logger Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Scheduler{
-		tasks:  make([]*ScheduledTask, 0),
-		logger: logger,
-		stop:   make(chan struct{}),
+		tasks:    make([]*ScheduledTask, 0),
+		logger:   logger,
+		runs:     NewMemoryScheduleRunStore(),
+		instance: instanceID(),
+		stop:     make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 }
 
-// Register 주기적 작업 등록
-func (s *Scheduler) Register(pluginName, taskName string, interval time.Duration, handler func() error) {
+// SetLocker 분산 락 설정 (nil 이면 인스턴스마다 실행)
+func (s *Scheduler) SetLocker(locker TaskLocker) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.locker = locker
+}
 
-	s.tasks = append(s.tasks, &ScheduledTask{
-		Name:       taskName,
-		PluginName: pluginName,
-		Interval:   interval,
-		Handler:    handler,
-		NextRun:    time.Now().Add(interval),
+// SetRunStore 실행 이력 저장소 설정
+func (s *Scheduler) SetRunStore(store ScheduleRunStore) {
+	if store == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs = store
+}
+
+// AddRunHook 실행이 끝나거나 건너뛸 때마다 호출할 함수 등록 (메트릭 등)
+// 건너뛴 실행은 Status 가 ScheduleRunSkipped 이고 Error 에 사유가 담기며, 이력에는 남지 않는다.
+func (s *Scheduler) AddRunHook(hook func(run *ScheduleRun)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+// Register 고정 간격 작업 등록
+func (s *Scheduler) Register(pluginName, taskName string, interval time.Duration, handler func() error) {
+	if err := s.RegisterTask(pluginName, taskName, TaskOptions{Interval: interval}, func(context.Context) error {
+		return handler()
+	}); err != nil {
+		s.logger.Error("Scheduled task %s/%s not registered: %v", pluginName, taskName, err)
+	}
+}
+
+// RegisterTask cron·시간대·지터·타임아웃을 지정해 작업 등록
+// 타임아웃이 지나면 ctx 가 취소된다. 핸들러는 ctx 를 존중해야 한다.
+func (s *Scheduler) RegisterTask(pluginName, taskName string, opts TaskOptions, handler func(ctx context.Context) error) error {
+	return s.RegisterTaskFunc(pluginName, taskName, opts, func(ctx context.Context, _ *ScheduleRun) error {
+		return handler(ctx)
 	})
+}
+
+// RegisterTaskFunc 실행 기록(dry-run 여부, 결과)을 다루는 작업 등록
+func (s *Scheduler) RegisterTaskFunc(pluginName, taskName string, opts TaskOptions, handler TaskFunc) error {
+	sched, err := opts.schedule()
+	if err != nil {
+		return err
+	}
+
+	task := &ScheduledTask{
+		Name:        taskName,
+		PluginName:  pluginName,
+		Interval:    opts.Interval,
+		Cron:        opts.Cron,
+		Timezone:    opts.Timezone,
+		Jitter:      opts.Jitter,
+		Timeout:     opts.Timeout,
+		Manual:      opts.Manual,
+		DryRun:      opts.DryRun,
+		Description: opts.Description,
+		Handler:     handler,
+		schedule:    sched,
+	}
+	if task.Cron != "" {
+		task.Interval = 0
+	}
 
-	s.logger.Info("Scheduled task registered: %s/%s (every %s)", pluginName, taskName, interval)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance(task, time.Now())
+	s.tasks = append(s.tasks, task)
+
+	s.logger.Info("Scheduled task registered: %s/%s (%s, next %s)",
+		pluginName, taskName, task.describe(), task.NextRun.Format(time.RFC3339))
+	return nil
 }
 
-// Unregister 플러그인의 모든 작업 해제
+// Unregister 플러그인의 모든 작업 해제 (실행 중인 작업은 끝까지 돈다)
 func (s *Scheduler) Unregister(pluginName string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	filtered := s.tasks[:0]
+	filtered := make([]*ScheduledTask, 0, len(s.tasks))
 	for _, t := range s.tasks {
 		if t.PluginName != pluginName {
 			filtered = append(filtered, t)
@@ -70,7 +283,7 @@ func (s *Scheduler) Start() {
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		ticker := time.NewTicker(30 * time.Second)
+		ticker := time.NewTicker(schedulerTickInterval)
 		defer ticker.Stop()
 
 		for {
@@ -78,45 +291,294 @@ func (s *Scheduler) Start() {
 			case <-s.stop:
 				return
 			case now := <-ticker.C:
-				s.tick(now)
+				s.dispatch(now)
 			}
 		}
 	}()
 	s.logger.Info("Plugin scheduler started")
 }
 
-// Stop 스케줄러 중지
+// Stop 스케줄러 중지 - 실행 중 작업의 ctx 를 취소하고 잠시 기다린다
 func (s *Scheduler) Stop() {
 	close(s.stop)
 	s.wg.Wait()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.runWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(schedulerStopGrace):
+		s.logger.Warn("Plugin scheduler stopped with tasks still running")
+	}
 	s.logger.Info("Plugin scheduler stopped")
 }
 
-// tick 실행 대상 작업 체크 및 실행
+// tick 실행 대상 작업을 실행하고 모두 끝날 때까지 대기
 func (s *Scheduler) tick(now time.Time) {
-	s.mu.RLock()
-	tasks := make([]*ScheduledTask, len(s.tasks))
-	copy(tasks, s.tasks)
-	s.mu.RUnlock()
+	s.dispatch(now).Wait()
+}
+
+// dispatch 예정 시각이 지난 작업을 각자 고루틴으로 시작한다
+func (s *Scheduler) dispatch(now time.Time) *sync.WaitGroup {
+	var batch sync.WaitGroup
 
-	for _, task := range tasks {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, task := range s.tasks {
 		if now.Before(task.NextRun) {
 			continue
 		}
+		slot := task.slot
+		s.advance(task, now)
+
+		if task.running {
+			task.SkipCount++
+			s.logger.Warn("Scheduled task still running, skipped: %s/%s", task.PluginName, task.Name)
+			continue
+		}
+		task.running = true
 
+		batch.Add(1)
+		s.runWG.Add(1)
+		go func(t *ScheduledTask, slot time.Time) {
+			defer s.runWG.Done()
+			defer batch.Done()
+			s.run(t, slot)
+		}(task, slot)
+	}
+	return &batch
+}
+
+// advance 다음 슬롯과 (지터 적용) 실행 시각 계산 - s.mu 보유 상태에서 호출
+func (s *Scheduler) advance(task *ScheduledTask, now time.Time) {
+	next := task.schedule.Next(now)
+	if next.IsZero() {
+		// 다시 오지 않는 cron (예: 2월 30일) - 사실상 비활성
+		task.slot = time.Time{}
+		task.NextRun = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+		return
+	}
+	task.slot = next
+	task.NextRun = next
+	if task.Jitter > 0 {
+		task.NextRun = next.Add(time.Duration(rand.Int64N(int64(task.Jitter)))) //nolint:gosec // jitter, not security
+	}
+}
+
+// run 예정된 작업 1회 실행 (dispatch 가 running 을 세운 상태)
+func (s *Scheduler) run(task *ScheduledTask, slot time.Time) {
+	_, _ = s.execute(s.ctx, task, slot, RunRequest{Trigger: TriggerSchedule}) //nolint:errcheck // 결과는 이력·로그에 남는다
+}
+
+// RunNow 작업을 즉시 실행하고 끝날 때까지 기다린다 (스케줄과 같은 락·이력·훅을 거친다)
+// 작업이 실패해도 실행 기록과 함께 에러를 돌려준다. 기록이 nil 이면 실행하지 않은 것이다.
+func (s *Scheduler) RunNow(ctx context.Context, pluginName, taskName string, req RunRequest) (*ScheduleRun, error) {
+	if req.Trigger == "" {
+		req.Trigger = TriggerManual
+	}
+
+	s.mu.Lock()
+	var task *ScheduledTask
+	for _, t := range s.tasks {
+		if t.PluginName == pluginName && t.Name == taskName {
+			task = t
+			break
+		}
+	}
+	if task == nil {
+		s.mu.Unlock()
+		return nil, ErrTaskNotFound
+	}
+	if req.DryRun && !task.DryRun {
+		s.mu.Unlock()
+		return nil, ErrDryRunUnsupported
+	}
+	if task.running {
+		s.mu.Unlock()
+		s.skipped(task, req, ScheduleSkipRunning)
+		return nil, ErrTaskBusy
+	}
+	task.running = true
+	s.mu.Unlock()
+
+	return s.execute(ctx, task, time.Time{}, req)
+}
+
+// execute 락 → 타임아웃 → 기록 → 훅. slot 이 zero 면 즉시 실행.
+// 호출 전에 task.running 이 세워져 있어야 하고, 여기서 내린다.
+func (s *Scheduler) execute(ctx context.Context, task *ScheduledTask, slot time.Time, req RunRequest) (*ScheduleRun, error) {
+	defer func() {
+		s.mu.Lock()
+		task.running = false
+		s.mu.Unlock()
+	}()
+
+	s.mu.RLock()
+	locker, store := s.locker, s.runs
+	s.mu.RUnlock()
+
+	// dry-run 은 롤백되므로 락 없이 돌린다
+	if locker != nil && !req.DryRun {
+		acquired, release := s.lock(ctx, locker, task, slot)
+		if !acquired {
+			s.skipped(task, req, ScheduleSkipLocked)
+			return nil, ErrTaskBusy
+		}
+		defer release()
+	}
+
+	ctx, cancel := ctx, context.CancelFunc(func() {})
+	if task.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, task.Timeout)
+	}
+	defer cancel()
+
+	record := &ScheduleRun{
+		PluginName:  task.PluginName,
+		TaskName:    task.Name,
+		Trigger:     req.Trigger,
+		TriggeredBy: req.TriggeredBy,
+		DryRun:      req.DryRun,
+		Instance:    s.instance,
+	}
+	if !slot.IsZero() {
+		record.ScheduledAt = &slot
+	}
+
+	if req.Trigger == TriggerSchedule {
 		s.logger.Info("Running scheduled task: %s/%s", task.PluginName, task.Name)
+	} else {
+		s.logger.Info("Running task now: %s/%s (%s, dry-run=%v)", task.PluginName, task.Name, req.Trigger, req.DryRun)
+	}
+	record.StartedAt = time.Now()
+	err := s.invoke(ctx, task, record)
+	record.FinishedAt = time.Now()
+	record.DurationMs = record.FinishedAt.Sub(record.StartedAt).Milliseconds()
 
-		if err := task.Handler(); err != nil {
-			s.logger.Error("Scheduled task error [%s/%s]: %v", task.PluginName, task.Name, err)
-			task.LastError = err
-		} else {
-			task.LastError = nil
+	record.Status = ScheduleRunSuccess
+	if err != nil {
+		record.Status = ScheduleRunFailed
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			record.Status = ScheduleRunTimeout
 		}
+		record.Error = err.Error()
+		s.logger.Error("Scheduled task error [%s/%s]: %v", task.PluginName, task.Name, err)
+	}
 
-		task.LastRun = now
-		task.NextRun = now.Add(task.Interval)
+	// dry-run 은 이력에만 남기고 작업 상태(마지막 실행 등)는 건드리지 않는다
+	if !req.DryRun {
+		s.mu.Lock()
+		task.LastRun = record.StartedAt
+		task.LastDuration = record.FinishedAt.Sub(record.StartedAt)
+		task.LastError = err
 		task.RunCount++
+		s.mu.Unlock()
 	}
+
+	if store != nil {
+		if saveErr := store.SaveRun(record); saveErr != nil {
+			s.logger.Warn("Failed to record scheduled run %s/%s: %v", task.PluginName, task.Name, saveErr)
+		}
+	}
+	s.notify(record)
+	return record, err
+}
+
+// lock 슬롯 락(스케줄 실행만)과 실행 락을 잡는다. 락 저장소 장애 시에는 로컬 실행을 택한다.
+func (s *Scheduler) lock(ctx context.Context, locker TaskLocker, task *ScheduledTask, slot time.Time) (bool, func()) {
+	prefix := fmt.Sprintf("%s%s:%s:", schedulerLockPrefix, task.PluginName, task.Name)
+
+	if !slot.IsZero() {
+		// 슬롯 락은 풀지 않는다 - 지터 때문에 늦게 도착한 다른 인스턴스가 같은 슬롯을 다시 돌리지 않도록 만료까지 둔다
+		ok, err := locker.TryLock(ctx, fmt.Sprintf("%s%d", prefix, slot.Unix()), task.Jitter+task.Timeout+time.Minute)
+		switch {
+		case err != nil:
+			s.logger.Warn("Scheduler lock unavailable for %s/%s, running locally: %v", task.PluginName, task.Name, err)
+			return true, func() {}
+		case !ok:
+			s.logger.Debug("Scheduled task %s/%s taken by another instance", task.PluginName, task.Name)
+			return false, nil
+		}
+	}
+
+	// 실행 락 - 다른 인스턴스의 이전 슬롯이나 즉시 실행과 겹치지 않도록 실행하는 동안만 잡는다
+	key := prefix + "running"
+	ttl := schedulerRunLockTTL
+	if task.Timeout > 0 {
+		ttl = task.Timeout + time.Minute
+	}
+	ok, err := locker.TryLock(ctx, key, ttl)
+	switch {
+	case err != nil:
+		s.logger.Warn("Scheduler lock unavailable for %s/%s, running locally: %v", task.PluginName, task.Name, err)
+		return true, func() {}
+	case !ok:
+		s.logger.Debug("Scheduled task %s/%s running on another instance", task.PluginName, task.Name)
+		return false, nil
+	}
+	return true, func() {
+		// 작업 ctx 가 취소됐어도 락은 풀어야 한다
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := locker.Unlock(unlockCtx, key); err != nil {
+			s.logger.Warn("Scheduler unlock failed for %s/%s: %v", task.PluginName, task.Name, err)
+		}
+	}
+}
+
+// skipped 실행하지 않은 회차를 훅에만 알린다
+func (s *Scheduler) skipped(task *ScheduledTask, req RunRequest, reason string) {
+	s.notify(&ScheduleRun{
+		PluginName:  task.PluginName,
+		TaskName:    task.Name,
+		Trigger:     req.Trigger,
+		TriggeredBy: req.TriggeredBy,
+		DryRun:      req.DryRun,
+		Status:      ScheduleRunSkipped,
+		Error:       reason,
+		Instance:    s.instance,
+	})
+}
+
+// notify 실행 훅 호출
+func (s *Scheduler) notify(run *ScheduleRun) {
+	s.mu.RLock()
+	hooks := s.hooks
+	s.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(run)
+	}
+}
+
+// invoke 핸들러 호출 (panic 은 에러로 변환)
+func (s *Scheduler) invoke(ctx context.Context, task *ScheduledTask, run *ScheduleRun) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Scheduled task panicked [%s/%s]: %v", task.PluginName, task.Name, r)
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+	return task.Handler(ctx, run)
+}
+
+// describe 로그용 일정 표현
+func (t *ScheduledTask) describe() string {
+	if t.Manual {
+		return "manual"
+	}
+	if t.Cron != "" {
+		tz := t.Timezone
+		if tz == "" {
+			tz = "UTC"
+		}
+		return fmt.Sprintf("cron %q %s", t.Cron, tz)
+	}
+	return "every " + t.Interval.String()
 }
 
 // GetTasks 등록된 작업 목록 조회 (모니터링용)
@@ -127,12 +589,28 @@ func (s *Scheduler) GetTasks() []ScheduledTaskInfo {
 	result := make([]ScheduledTaskInfo, 0, len(s.tasks))
 	for _, t := range s.tasks {
 		info := ScheduledTaskInfo{
-			Name:       t.Name,
-			PluginName: t.PluginName,
-			Interval:   t.Interval.String(),
-			LastRun:    t.LastRun,
-			NextRun:    t.NextRun,
-			RunCount:   t.RunCount,
+			Name:         t.Name,
+			PluginName:   t.PluginName,
+			Cron:         t.Cron,
+			Timezone:     t.Timezone,
+			Description:  t.Description,
+			Manual:       t.Manual,
+			DryRun:       t.DryRun,
+			LastRun:      t.LastRun,
+			NextRun:      t.NextRun,
+			RunCount:     t.RunCount,
+			SkipCount:    t.SkipCount,
+			Running:      t.running,
+			LastDuration: t.LastDuration.String(),
+		}
+		if t.Interval > 0 {
+			info.Interval = t.Interval.String()
+		}
+		if t.Jitter > 0 {
+			info.Jitter = t.Jitter.String()
+		}
+		if t.Timeout > 0 {
+			info.Timeout = t.Timeout.String()
 		}
 		if t.LastError != nil {
 			errMsg := t.LastError.Error()
@@ -143,13 +621,32 @@ func (s *Scheduler) GetTasks() []ScheduledTaskInfo {
 	return result
 }
 
+// GetRuns 작업 실행 이력 (최신순)
+func (s *Scheduler) GetRuns(pluginName, taskName string, limit int) ([]ScheduleRun, error) {
+	s.mu.RLock()
+	store := s.runs
+	s.mu.RUnlock()
+	return store.RecentRuns(pluginName, taskName, limit)
+}
+
 // ScheduledTaskInfo 작업 정보 (JSON 응답용)
 type ScheduledTaskInfo struct {
-	Name       string    `json:"name"`
-	PluginName string    `json:"plugin_name"`
-	Interval   string    `json:"interval"`
-	LastRun    time.Time `json:"last_run"`
-	NextRun    time.Time `json:"next_run"`
-	RunCount   int64     `json:"run_count"`
-	LastError  *string   `json:"last_error,omitempty"`
+	Name         string        `json:"name"`
+	PluginName   string        `json:"plugin_name"`
+	Interval     string        `json:"interval,omitempty"`
+	Cron         string        `json:"cron,omitempty"`
+	Timezone     string        `json:"timezone,omitempty"`
+	Jitter       string        `json:"jitter,omitempty"`
+	Timeout      string        `json:"timeout,omitempty"`
+	Description  string        `json:"description,omitempty"`
+	Manual       bool          `json:"manual"`
+	DryRun       bool          `json:"dry_run_supported"`
+	LastRun      time.Time     `json:"last_run"`
+	NextRun      time.Time     `json:"next_run"`
+	RunCount     int64         `json:"run_count"`
+	SkipCount    int64         `json:"skip_count"`
+	Running      bool          `json:"running"`
+	LastDuration string        `json:"last_duration"`
+	LastError    *string       `json:"last_error,omitempty"`
+	RecentRuns   []ScheduleRun `json:"recent_runs,omitempty"`
 }
@@ -0,0 +1,148 @@
+package plugin
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 스케줄 실행 결과
+const (
+	ScheduleRunSuccess = "success"
+	ScheduleRunFailed  = "failed"
+	ScheduleRunTimeout = "timeout"
+	ScheduleRunSkipped = "skipped" // 훅 전용 - 이력에는 남지 않는다
+)
+
+// 건너뛴 사유 (skipped 실행의 Error)
+const (
+	ScheduleSkipRunning = "running" // 이 인스턴스에서 이전 실행이 안 끝남
+	ScheduleSkipLocked  = "locked"  // 다른 인스턴스가 슬롯 또는 실행 락을 잡음
+)
+
+// ScheduleRun 스케줄 작업 실행 이력 (GORM 모델)
+type ScheduleRun struct {
+	ID          int64      `gorm:"primaryKey" json:"id"`
+	PluginName  string     `gorm:"size:100;index:idx_plugin_schedule_runs_task,priority:1" json:"plugin_name"`
+	TaskName    string     `gorm:"size:100;index:idx_plugin_schedule_runs_task,priority:2" json:"task_name"`
+	Trigger     string     `gorm:"column:trigger_type;size:20" json:"trigger,omitempty"` // schedule | manual | http
+	TriggeredBy string     `gorm:"size:100" json:"triggered_by,omitempty"`
+	DryRun      bool       `json:"dry_run"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"` // 즉시 실행이면 nil
+	StartedAt   time.Time  `gorm:"index:idx_plugin_schedule_runs_task,priority:3" json:"started_at"`
+	FinishedAt  time.Time  `json:"finished_at"`
+	DurationMs  int64      `json:"duration_ms"`
+	Status      string     `gorm:"size:20" json:"status"` // success | failed | timeout
+	Result      string     `gorm:"type:text" json:"-"`
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	Instance    string     `gorm:"size:100" json:"instance"` // 실행한 인스턴스 (hostname-pid)
+
+	ResultJSON json.RawMessage `gorm:"-" json:"result,omitempty"`
+}
+
+// TableName 테이블명
+func (ScheduleRun) TableName() string {
+	return "plugin_schedule_runs"
+}
+
+// SetResult 작업 결과를 JSON 으로 남긴다 (직렬화 실패 시 무시)
+func (r *ScheduleRun) SetResult(v interface{}) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	r.Result = string(raw)
+	r.ResultJSON = raw
+}
+
+// AfterFind 저장된 결과를 raw JSON 으로 노출
+func (r *ScheduleRun) AfterFind(*gorm.DB) error {
+	if r.Result != "" {
+		r.ResultJSON = json.RawMessage(r.Result)
+	}
+	return nil
+}
+
+// ScheduleRunStore 실행 이력 저장소
+type ScheduleRunStore interface {
+	SaveRun(run *ScheduleRun) error
+	// RecentRuns 최신순 조회. pluginName·taskName 이 비면 전체.
+	RecentRuns(pluginName, taskName string, limit int) ([]ScheduleRun, error)
+}
+
+const memoryScheduleRunLimit = 200
+
+// MemoryScheduleRunStore 재시작하면 사라지는 기본 저장소
+type MemoryScheduleRunStore struct {
+	mu   sync.RWMutex
+	runs []ScheduleRun // 최신이 앞
+	seq  int64
+}
+
+// NewMemoryScheduleRunStore 생성자
+func NewMemoryScheduleRunStore() *MemoryScheduleRunStore {
+	return &MemoryScheduleRunStore{}
+}
+
+// SaveRun 이력 추가
+func (m *MemoryScheduleRunStore) SaveRun(run *ScheduleRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	run.ID = m.seq
+	m.runs = append([]ScheduleRun{*run}, m.runs...)
+	if len(m.runs) > memoryScheduleRunLimit {
+		m.runs = m.runs[:memoryScheduleRunLimit]
+	}
+	return nil
+}
+
+// RecentRuns 최신순 조회
+func (m *MemoryScheduleRunStore) RecentRuns(pluginName, taskName string, limit int) ([]ScheduleRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]ScheduleRun, 0)
+	for _, r := range m.runs {
+		if (pluginName == "" || r.PluginName == pluginName) && (taskName == "" || r.TaskName == taskName) {
+			result = append(result, r)
+			if limit > 0 && len(result) >= limit {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// GormScheduleRunStore plugin_schedule_runs 테이블 저장소
+type GormScheduleRunStore struct {
+	db *gorm.DB
+}
+
+// NewGormScheduleRunStore 생성자
+func NewGormScheduleRunStore(db *gorm.DB) *GormScheduleRunStore {
+	return &GormScheduleRunStore{db: db}
+}
+
+// SaveRun 이력 추가
+func (g *GormScheduleRunStore) SaveRun(run *ScheduleRun) error {
+	return g.db.Create(run).Error
+}
+
+// RecentRuns 최신순 조회
+func (g *GormScheduleRunStore) RecentRuns(pluginName, taskName string, limit int) ([]ScheduleRun, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	q := g.db.Model(&ScheduleRun{})
+	if pluginName != "" {
+		q = q.Where("plugin_name = ?", pluginName)
+	}
+	if taskName != "" {
+		q = q.Where("task_name = ?", taskName)
+	}
+	var runs []ScheduleRun
+	err := q.Order("started_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
@@ -0,0 +1,93 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// unlockScript 아직 이 인스턴스가 잡고 있을 때만 지운다
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisTaskLocker SET NX 기반 스케줄 슬롯 락
+type RedisTaskLocker struct {
+	client *redis.Client
+	owner  string
+}
+
+// NewRedisTaskLocker 생성자
+func NewRedisTaskLocker(client *redis.Client) *RedisTaskLocker {
+	return &RedisTaskLocker{client: client, owner: instanceID()}
+}
+
+// TryLock key 를 ttl 동안 선점
+func (l *RedisTaskLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return l.client.SetNX(ctx, key, l.owner, ttl).Result()
+}
+
+// Unlock 이 인스턴스가 잡은 key 해제
+func (l *RedisTaskLocker) Unlock(ctx context.Context, key string) error {
+	return unlockScript.Run(ctx, l.client, []string{key}, l.owner).Err()
+}
+
+// ScheduleLock plugin_schedule_locks 행 (Redis 가 없을 때의 스케줄 락)
+type ScheduleLock struct {
+	LockKey   string    `gorm:"primaryKey;size:191" json:"lock_key"`
+	Holder    string    `gorm:"size:100" json:"holder"`
+	ExpiresAt time.Time `gorm:"index:idx_plugin_schedule_locks_expires" json:"expires_at"`
+}
+
+// TableName 테이블명
+func (ScheduleLock) TableName() string {
+	return "plugin_schedule_locks"
+}
+
+// GormTaskLocker 키당 1행을 INSERT 로 선점하는 DB 락 (만료된 행은 선점 전에 지운다)
+type GormTaskLocker struct {
+	db    *gorm.DB
+	owner string
+}
+
+// NewGormTaskLocker 생성자
+func NewGormTaskLocker(db *gorm.DB) *GormTaskLocker {
+	return &GormTaskLocker{db: db, owner: instanceID()}
+}
+
+// TryLock key 를 ttl 동안 선점
+func (l *GormTaskLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	db := l.db.WithContext(ctx)
+	if err := db.Where("expires_at < ?", now).Delete(&ScheduleLock{}).Error; err != nil {
+		return false, err
+	}
+	res := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&ScheduleLock{LockKey: key, Holder: l.owner, ExpiresAt: now.Add(ttl)})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// Unlock 이 인스턴스가 잡은 key 해제
+func (l *GormTaskLocker) Unlock(ctx context.Context, key string) error {
+	return l.db.WithContext(ctx).Where("lock_key = ? AND holder = ?", key, l.owner).Delete(&ScheduleLock{}).Error
+}
+
+// instanceID 이 프로세스를 구분하는 이름 (hostname-pid) - 컨슈머 그룹·락 소유자 표시용
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "angple"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
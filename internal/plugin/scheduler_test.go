@@ -1,9 +1,15 @@
 package plugin
 
 import (
+	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestScheduler_RegisterAndGetTasks(t *testing.T) {
@@ -100,3 +106,274 @@ func TestScheduler_SkipNotReady(t *testing.T) {
 		t.Error("expected handler NOT called for future task")
 	}
 }
+
+func TestScheduler_RegisterTaskCron(t *testing.T) {
+	s := NewScheduler(NewDefaultLogger("test"))
+
+	err := s.RegisterTask("test-plugin", "daily", TaskOptions{Cron: "0 4 * * *", Timezone: "Asia/Seoul"}, func(context.Context) error { return nil })
+	if err != nil {
+		t.Fatalf("RegisterTask: %v", err)
+	}
+	next := s.tasks[0].NextRun.In(time.FixedZone("KST", 9*60*60))
+	if next.Hour() != 4 || next.Minute() != 0 {
+		t.Errorf("expected 04:00 KST, got %s", next)
+	}
+
+	if err := s.RegisterTask("test-plugin", "bad", TaskOptions{Cron: "bogus"}, func(context.Context) error { return nil }); err == nil {
+		t.Error("expected error for invalid cron")
+	}
+	if err := s.RegisterTask("test-plugin", "none", TaskOptions{}, func(context.Context) error { return nil }); err == nil {
+		t.Error("expected error without cron or interval")
+	}
+}
+
+func TestScheduler_OverlapSkipped(t *testing.T) {
+	s := NewScheduler(NewDefaultLogger("test"))
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	s.Register("test-plugin", "slow", time.Millisecond, func() error {
+		calls.Add(1)
+		<-release
+		return nil
+	})
+
+	s.tasks[0].NextRun = time.Now().Add(-time.Second)
+	first := s.dispatch(time.Now())
+
+	s.mu.Lock()
+	s.tasks[0].NextRun = time.Now().Add(-time.Second)
+	s.mu.Unlock()
+	s.dispatch(time.Now()).Wait()
+
+	close(release)
+	first.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected 1 call, got %d", n)
+	}
+	if s.tasks[0].SkipCount != 1 {
+		t.Errorf("expected SkipCount 1, got %d", s.tasks[0].SkipCount)
+	}
+}
+
+func TestScheduler_TimeoutRecorded(t *testing.T) {
+	s := NewScheduler(NewDefaultLogger("test"))
+	store := NewMemoryScheduleRunStore()
+	s.SetRunStore(store)
+
+	if err := s.RegisterTask("test-plugin", "stuck", TaskOptions{Interval: time.Hour, Timeout: 20 * time.Millisecond}, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}); err != nil {
+		t.Fatal(err)
+	}
+	s.tasks[0].NextRun = time.Now().Add(-time.Second)
+	s.tick(time.Now())
+
+	runs, _ := store.RecentRuns("test-plugin", "stuck", 10) //nolint:errcheck // memory store
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(runs))
+	}
+	if runs[0].Status != ScheduleRunTimeout {
+		t.Errorf("expected status timeout, got %s", runs[0].Status)
+	}
+	if runs[0].Instance == "" {
+		t.Error("expected instance to be recorded")
+	}
+}
+
+type fakeLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *fakeLocker) TryLock(_ context.Context, key string, _ time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[key] {
+		return false, nil
+	}
+	l.held[key] = true
+	return true, nil
+}
+
+func (l *fakeLocker) Unlock(_ context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.held, key)
+	return nil
+}
+
+func TestScheduler_LockRunsSlotOnce(t *testing.T) {
+	locker := &fakeLocker{held: make(map[string]bool)}
+	var calls atomic.Int32
+
+	// 같은 락을 공유하는 두 레플리카
+	replicas := []*Scheduler{NewScheduler(NewDefaultLogger("a")), NewScheduler(NewDefaultLogger("b"))}
+	for _, s := range replicas {
+		s.SetLocker(locker)
+		s.Register("test-plugin", "once", time.Hour, func() error {
+			calls.Add(1)
+			return nil
+		})
+	}
+
+	now := time.Now()
+	for _, s := range replicas {
+		s.tasks[0].NextRun = now.Add(-time.Second)
+		s.tick(now)
+	}
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected slot to run once across replicas, got %d", n)
+	}
+	if replicas[0].tasks[0].RunCount+replicas[1].tasks[0].RunCount != 1 {
+		t.Error("expected exactly one replica to record the run")
+	}
+}
+
+func TestScheduler_PanicRecovered(t *testing.T) {
+	s := NewScheduler(NewDefaultLogger("test"))
+	s.Register("test-plugin", "panics", time.Hour, func() error { panic("boom") })
+	s.tasks[0].NextRun = time.Now().Add(-time.Second)
+
+	s.tick(time.Now())
+
+	if s.tasks[0].LastError == nil {
+		t.Error("expected panic to be recorded as error")
+	}
+}
+
+func TestScheduler_RunNowRecordsRun(t *testing.T) {
+	s := NewScheduler(NewDefaultLogger("test"))
+	store := NewMemoryScheduleRunStore()
+	s.SetRunStore(store)
+	s.SetLocker(&fakeLocker{held: make(map[string]bool)})
+	var hooked []string
+	s.AddRunHook(func(run *ScheduleRun) { hooked = append(hooked, run.Status) })
+
+	if err := s.RegisterTaskFunc("test-plugin", "report", TaskOptions{Manual: true, Cron: "0 4 * * *"}, func(_ context.Context, run *ScheduleRun) error {
+		run.SetResult(map[string]int{"sent": 3})
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if s.tasks[0].NextRun.Year() != 9999 {
+		t.Fatalf("manual task must not be scheduled, next %s", s.tasks[0].NextRun)
+	}
+
+	run, err := s.RunNow(context.Background(), "test-plugin", "report", RunRequest{TriggeredBy: "admin"})
+	if err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	if run.Status != ScheduleRunSuccess || run.Trigger != TriggerManual || run.ScheduledAt != nil {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if string(run.ResultJSON) != `{"sent":3}` {
+		t.Errorf("unexpected result: %s", run.ResultJSON)
+	}
+	runs, _ := store.RecentRuns("test-plugin", "report", 10) //nolint:errcheck // memory store
+	if len(runs) != 1 || runs[0].TriggeredBy != "admin" {
+		t.Fatalf("unexpected runs: %+v", runs)
+	}
+	if len(hooked) != 1 || hooked[0] != ScheduleRunSuccess {
+		t.Errorf("unexpected hook calls: %v", hooked)
+	}
+
+	// 실행 락이 풀려야 다음 즉시 실행이 가능하다
+	if _, err := s.RunNow(context.Background(), "test-plugin", "report", RunRequest{}); err != nil {
+		t.Fatalf("second RunNow: %v", err)
+	}
+}
+
+func TestScheduler_RunNowErrors(t *testing.T) {
+	s := NewScheduler(NewDefaultLogger("test"))
+	s.Register("test-plugin", "plain", time.Hour, func() error { return nil })
+
+	if _, err := s.RunNow(context.Background(), "test-plugin", "missing", RunRequest{}); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+	if _, err := s.RunNow(context.Background(), "test-plugin", "plain", RunRequest{DryRun: true}); !errors.Is(err, ErrDryRunUnsupported) {
+		t.Errorf("expected ErrDryRunUnsupported, got %v", err)
+	}
+
+	s.tasks[0].running = true
+	var skipped *ScheduleRun
+	s.AddRunHook(func(run *ScheduleRun) { skipped = run })
+	if _, err := s.RunNow(context.Background(), "test-plugin", "plain", RunRequest{}); !errors.Is(err, ErrTaskBusy) {
+		t.Errorf("expected ErrTaskBusy, got %v", err)
+	}
+	if skipped == nil || skipped.Status != ScheduleRunSkipped || skipped.Error != ScheduleSkipRunning {
+		t.Errorf("expected skipped hook, got %+v", skipped)
+	}
+}
+
+func TestScheduler_RunNowBusyOnOtherInstance(t *testing.T) {
+	locker := &fakeLocker{held: make(map[string]bool)}
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	replicas := []*Scheduler{NewScheduler(NewDefaultLogger("a")), NewScheduler(NewDefaultLogger("b"))}
+	for _, s := range replicas {
+		s.SetLocker(locker)
+		if err := s.RegisterTask("test-plugin", "slow", TaskOptions{Manual: true}, func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := replicas[0].RunNow(context.Background(), "test-plugin", "slow", RunRequest{})
+		done <- err
+	}()
+	<-started
+	if _, err := replicas[1].RunNow(context.Background(), "test-plugin", "slow", RunRequest{}); !errors.Is(err, ErrTaskBusy) {
+		t.Errorf("expected ErrTaskBusy while another instance runs, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("first RunNow: %v", err)
+	}
+}
+
+func TestGormTaskLocker(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&ScheduleLock{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	a := &GormTaskLocker{db: db, owner: "a"}
+	b := &GormTaskLocker{db: db, owner: "b"}
+	ctx := context.Background()
+
+	if ok, err := a.TryLock(ctx, "k", time.Minute); err != nil || !ok {
+		t.Fatalf("a should lock: ok=%v err=%v", ok, err)
+	}
+	if ok, _ := b.TryLock(ctx, "k", time.Minute); ok { //nolint:errcheck // ok 만 본다
+		t.Fatal("b must not take a held lock")
+	}
+	// 남의 락은 풀 수 없다
+	if err := b.Unlock(ctx, "k"); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if ok, _ := b.TryLock(ctx, "k", time.Minute); ok { //nolint:errcheck // ok 만 본다
+		t.Fatal("b must not take a lock after unlocking someone else's")
+	}
+	if err := a.Unlock(ctx, "k"); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if ok, err := b.TryLock(ctx, "k", -time.Second); err != nil || !ok {
+		t.Fatalf("b should lock after release: ok=%v err=%v", ok, err)
+	}
+	// 만료된 락은 다른 인스턴스가 가져간다
+	if ok, err := a.TryLock(ctx, "k", time.Minute); err != nil || !ok {
+		t.Fatalf("a should take an expired lock: ok=%v err=%v", ok, err)
+	}
+}
@@ -40,6 +40,59 @@ type PluginManifest struct {
 
 	// 메뉴 정의 (선택) - 플러그인이 Admin UI에 메뉴 등록
 	Menus []MenuConfig `yaml:"menus"`
+
+	// 이벤트 버스 구독 토픽 (선택) - 외부 프로세스 플러그인에 전달
+	Events []string `yaml:"events"`
+
+	// 주기 작업 (선택) - 외부 프로세스 플러그인에 tick 전달
+	Schedules []ScheduleConfig `yaml:"schedules"`
+
+	// 실행 런타임 (선택) - 없으면 RegisterFactory 로 컴파일된 내장 플러그인
+	Runtime *RuntimeConfig `yaml:"runtime"`
+}
+
+// 플러그인 런타임 종류
+const (
+	RuntimeBuiltIn = "builtin" // 바이너리에 컴파일된 플러그인 (기본)
+	RuntimeProcess = "process" // JSON-RPC 로 통신하는 외부 실행 파일
+)
+
+// 외부 프로세스 플러그인 전송 방식
+const (
+	TransportStdio = "stdio"
+	TransportUnix  = "unix"
+)
+
+// RuntimeConfig 플러그인 실행 방식 설정
+type RuntimeConfig struct {
+	Type      string            `yaml:"type"`      // builtin | process
+	Command   string            `yaml:"command"`   // 실행 파일 (플러그인 디렉토리 기준 상대 경로 허용)
+	Args      []string          `yaml:"args"`      // 실행 인자
+	Env       map[string]string `yaml:"env"`       // 추가 환경 변수
+	Transport string            `yaml:"transport"` // stdio(기본) | unix
+	Timeout   string            `yaml:"timeout"`   // RPC 호출 타임아웃 (기본 10s)
+	Restart   RestartPolicy     `yaml:"restart"`
+}
+
+// RestartPolicy 프로세스 비정상 종료 시 재시작 정책
+type RestartPolicy struct {
+	MaxRestarts int    `yaml:"max_restarts"` // 연속 재시작 허용 횟수 (기본 5, 음수면 재시작 안 함)
+	Backoff     string `yaml:"backoff"`      // 첫 재시작 대기 (기본 1s, 실패마다 2배, 최대 1m)
+}
+
+// IsProcess 외부 프로세스 런타임 여부
+func (r *RuntimeConfig) IsProcess() bool {
+	return r != nil && r.Type == RuntimeProcess
+}
+
+// ScheduleConfig 매니페스트 주기 작업 정의 - cron 과 interval 중 하나는 필수
+type ScheduleConfig struct {
+	Name     string `yaml:"name"`
+	Interval string `yaml:"interval"` // time.ParseDuration 형식 (예: 5m, 1h)
+	Cron     string `yaml:"cron"`     // 예: "0 4 * * *", "@daily"
+	Timezone string `yaml:"timezone"` // 예: Asia/Seoul (기본 UTC)
+	Jitter   string `yaml:"jitter"`   // 예: 30s
+	Timeout  string `yaml:"timeout"`  // 예: 5m
 }
 
 // MenuConfig 플러그인 메뉴 설정
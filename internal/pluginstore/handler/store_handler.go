@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/damoang/angple-backend/internal/middleware"
 	"github.com/damoang/angple-backend/internal/plugin"
 	"github.com/damoang/angple-backend/internal/pluginstore/service"
 	"github.com/gin-gonic/gin"
@@ -183,9 +185,55 @@ func (h *StoreHandler) Dashboard(c *gin.Context) {
 // GET /api/v2/admin/plugins/schedules
 func (h *StoreHandler) ScheduledTasks(c *gin.Context) {
 	tasks := h.manager.GetScheduledTasks()
+	for i := range tasks {
+		runs, err := h.manager.GetScheduleRuns(tasks[i].PluginName, tasks[i].Name, 5)
+		if err == nil {
+			tasks[i].RecentRuns = runs
+		}
+	}
 	c.JSON(http.StatusOK, gin.H{"data": tasks})
 }
 
+// ScheduleRuns 스케줄 작업 실행 이력 조회 (최신순)
+// GET /api/v2/admin/plugins/schedules/runs?plugin=&task=&limit=
+func (h *StoreHandler) ScheduleRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50")) //nolint:errcheck // 파싱 실패 시 0 → 기본값
+	runs, err := h.manager.GetScheduleRuns(c.Query("plugin"), c.Query("task"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{"code": "SCHEDULE_ERROR", "message": "실행 이력 조회 실패", "details": err.Error()},
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": runs})
+}
+
+// RunSchedule 스케줄 작업 즉시 실행 - 끝날 때까지 기다린 뒤 실행 기록을 돌려준다
+// POST /api/v2/admin/plugins/schedules/:plugin/:task/run?dry_run=true
+func (h *StoreHandler) RunSchedule(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false")) //nolint:errcheck // 파싱 실패 시 false
+	run, err := h.manager.RunScheduledTask(c.Request.Context(), c.Param("plugin"), c.Param("task"), plugin.RunRequest{
+		Trigger:     plugin.TriggerManual,
+		TriggeredBy: middleware.GetUserID(c),
+		DryRun:      dryRun,
+	})
+	if run != nil {
+		// 작업 자체가 실패해도 기록은 남았으므로 200 으로 돌려주고 status 로 구분한다
+		c.JSON(http.StatusOK, gin.H{"data": run})
+		return
+	}
+	switch {
+	case errors.Is(err, plugin.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": gin.H{"code": "TASK_NOT_FOUND", "message": "스케줄 작업을 찾을 수 없습니다"}})
+	case errors.Is(err, plugin.ErrTaskBusy):
+		c.JSON(http.StatusConflict, gin.H{"error": gin.H{"code": "TASK_BUSY", "message": "이미 실행 중입니다"}})
+	case errors.Is(err, plugin.ErrDryRunUnsupported):
+		c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"code": "DRY_RUN_UNSUPPORTED", "message": "dry-run 을 지원하지 않는 작업입니다"}})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": gin.H{"code": "SCHEDULE_ERROR", "message": "작업 실행 실패", "details": err.Error()}})
+	}
+}
+
 // RateLimitConfigs 레이트 리밋 설정 목록 조회
 // GET /api/v2/admin/plugins/rate-limits
 func (h *StoreHandler) RateLimitConfigs(c *gin.Context) {
@@ -208,11 +256,85 @@ func (h *StoreHandler) PluginMetricsSingle(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": metrics})
 }
 
-// EventSubscriptions 이벤트 구독 현황 조회
-// GET /api/v2/admin/plugins/event-subscriptions
+// EventSubscriptions 이벤트 구독 현황 조회 (구독자별 전달 통계 + dead-letter 포함)
+// GET /api/v2/admin/plugins/event-subscriptions?dead_letter_limit=50
 func (h *StoreHandler) EventSubscriptions(c *gin.Context) {
-	subs := h.manager.GetEventSubscriptions()
-	c.JSON(http.StatusOK, gin.H{"data": subs})
+	bus := h.manager.GetEventBus()
+	limit, _ := strconv.Atoi(c.DefaultQuery("dead_letter_limit", "50")) //nolint:errcheck // 파싱 실패 시 0 → 기본값
+
+	deadLetters, err := bus.DeadLetters(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": gin.H{"code": "EVENT_BUS_ERROR", "message": "dead-letter 조회 실패", "details": err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         h.manager.GetEventSubscriptions(),
+		"backend":      bus.BackendName(),
+		"stats":        bus.GetSubscriptionStatus(),
+		"dead_letters": deadLetters,
+	})
+}
+
+// ReplayEventsRequest 이벤트 재전달 요청
+type ReplayEventsRequest struct {
+	Plugin string `json:"plugin" binding:"required"`
+	Topic  string `json:"topic" binding:"required"`
+	From   string `json:"from"` // 이 오프셋 이후부터 (비우면 보관분 처음부터)
+}
+
+// ReplayEvents 플러그인 구독을 오프셋 이후로 되감아 재전달
+// POST /api/v2/admin/plugins/event-subscriptions/replay
+func (h *StoreHandler) ReplayEvents(c *gin.Context) {
+	var req ReplayEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{"code": "INVALID_REQUEST", "message": "plugin, topic 은 필수입니다"},
+		})
+		return
+	}
+
+	count, err := h.manager.GetEventBus().Replay(c.Request.Context(), req.Plugin, req.Topic, req.From)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, plugin.ErrNoSubscription) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": gin.H{"code": "REPLAY_ERROR", "message": err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"replayed": count}})
+}
+
+// RedriveDeadLetter dead-letter 1건 재전달
+// POST /api/v2/admin/plugins/event-subscriptions/dead-letters/:id/redrive
+func (h *StoreHandler) RedriveDeadLetter(c *gin.Context) {
+	if err := h.manager.GetEventBus().Redrive(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": gin.H{"code": "REDRIVE_FAILED", "message": err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "이벤트가 재전달되었습니다"}})
+}
+
+// DiscardDeadLetter dead-letter 1건 삭제
+// DELETE /api/v2/admin/plugins/event-subscriptions/dead-letters/:id
+func (h *StoreHandler) DiscardDeadLetter(c *gin.Context) {
+	if err := h.manager.GetEventBus().DiscardDeadLetter(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": gin.H{"code": "DEAD_LETTER_NOT_FOUND", "message": err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"message": "dead-letter 가 삭제되었습니다"}})
 }
 
 // PluginOverview 플러그인 전체 현황 조회
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository handles API key persistence
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// AutoMigrate creates or extends the api_keys table.
+// ⛔ prod 는 수동 DDL 선행 원칙 — migrations/008_api_keys_hashed.sql 참고.
+func (r *APIKeyRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.APIKey{})
+}
+
+// ListByUser returns the member's keys, newest first (폐기한 키 포함)
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// CountActiveByUser counts the member's usable keys
+func (r *APIKeyRepository) CountActiveByUser(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.APIKey{}).
+		Where("user_id = ? AND active = ?", userID, true).Count(&count).Error
+	return count, err
+}
+
+// FindByUser returns one key owned by the member (nil if none)
+func (r *APIKeyRepository) FindByUser(ctx context.Context, userID string, id int64) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindActiveByHash returns the active key with the given hash (nil if none)
+func (r *APIKeyRepository) FindActiveByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ? AND active = ?", keyHash, true).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListLegacy returns keys still stored in plaintext (해시 도입 전 발급분)
+func (r *APIKeyRepository) ListLegacy(ctx context.Context) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	err := r.db.WithContext(ctx).
+		Where("(key_hash IS NULL OR key_hash = '') AND api_key IS NOT NULL AND api_key <> ''").
+		Find(&keys).Error
+	return keys, err
+}
+
+// Create inserts a key
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// Update saves every column of a key
+func (r *APIKeyRepository) Update(ctx context.Context, key *domain.APIKey) error {
+	return r.db.WithContext(ctx).Save(key).Error
+}
+
+// RecordUse bumps the usage counter and last-use fields
+func (r *APIKeyRepository) RecordUse(ctx context.Context, id int64, ip string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.APIKey{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"usage_count":  gorm.Expr("usage_count + 1"),
+		"last_used_at": at,
+		"last_used_ip": ip,
+	}).Error
+}
@@ -0,0 +1,197 @@
+package gnuboard
+
+import (
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain/gnuboard"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// memoUnreadDatetime 는 미열람 쪽지의 me_read_datetime 이다 (MemoRepository.CountUnread 와 같은 판정)
+const memoUnreadDatetime = "0000-00-00 00:00:00"
+
+// MemoThreadRepository groups g5_memo into conversations and keeps per-thread state and attachments
+type MemoThreadRepository interface {
+	// Threads returns the member's conversations, newest first. before 는 이전 페이지 마지막 last_message_id (0 = 처음).
+	// archived 면 보관한 대화방만, 아니면 보관하지 않았거나 보관 뒤 새 쪽지가 온 대화방만.
+	Threads(mbID string, archived bool, before, limit int) ([]*gnuboard.MemoThread, error)
+	// Messages returns the member's rows with peer, newest first (before 는 me_id 커서, 0 = 처음)
+	Messages(mbID, peer string, before, limit int) ([]*gnuboard.G5Memo, error)
+	// ReadTimes returns me_read_datetime of the given recv rows that the peer has read (보낸 쪽지의 읽음 확인)
+	ReadTimes(recvIDs []int) (map[int]string, error)
+	// MarkThreadRead marks every unread message from peer as read and returns how many changed
+	MarkThreadRead(mbID, peer string) (int64, error)
+
+	State(mbID, peer string) (*gnuboard.MemoThreadState, error)
+	SetArchived(mbID, peer string, archivedMeID int) error
+	SetMuted(mbID, peer string, muted bool) error
+
+	CreateAttachment(a *gnuboard.MemoAttachment) error
+	// CountPending counts the member's unsent uploads among ids
+	CountPending(mbID string, ids []int64) (int64, error)
+	// AttachPending links the member's unsent uploads to the message meID
+	AttachPending(mbID string, ids []int64, meID int) error
+	AttachmentsFor(meIDs []int) (map[int][]gnuboard.MemoAttachment, error)
+}
+
+type memoThreadRepository struct {
+	db *gorm.DB
+}
+
+// NewMemoThreadRepository creates a new MemoThreadRepository
+func NewMemoThreadRepository(db *gorm.DB) MemoThreadRepository {
+	return &memoThreadRepository{db: db}
+}
+
+// Threads 는 받은 쪽지(recv)·보낸 쪽지(send) 행을 상대별로 묶는다. 한 쪽지는 두 행(recv·send)이지만
+// 회원마다 자기 쪽 행만 보므로 겹치지 않는다.
+func (r *memoThreadRepository) Threads(mbID string, archived bool, before, limit int) ([]*gnuboard.MemoThread, error) {
+	grouped := `SELECT peer_mb_id, MAX(me_id) AS last_me_id, SUM(unread) AS unread FROM (
+			SELECT me_send_mb_id AS peer_mb_id, me_id, CASE WHEN me_read_datetime = ? THEN 1 ELSE 0 END AS unread
+			FROM g5_memo WHERE me_recv_mb_id = ? AND me_type = 'recv'
+			UNION ALL
+			SELECT me_recv_mb_id AS peer_mb_id, me_id, 0 AS unread
+			FROM g5_memo WHERE me_send_mb_id = ? AND me_type = 'send'
+		) m GROUP BY peer_mb_id`
+	where := "COALESCE(s.archived_me_id, 0) < t.last_me_id"
+	if archived {
+		where = "s.archived_me_id >= t.last_me_id"
+	}
+	args := []interface{}{memoUnreadDatetime, mbID, mbID, mbID}
+	if before > 0 {
+		where += " AND t.last_me_id < ?"
+		args = append(args, before)
+	}
+	args = append(args, limit)
+
+	var threads []*gnuboard.MemoThread
+	err := r.db.Raw(`SELECT t.peer_mb_id, t.last_me_id, t.unread, COALESCE(s.muted, 0) AS muted,
+			CASE WHEN COALESCE(s.archived_me_id, 0) >= t.last_me_id THEN 1 ELSE 0 END AS archived
+		FROM (`+grouped+`) t
+		LEFT JOIN g5_memo_thread s ON s.mb_id = ? AND s.peer_mb_id = t.peer_mb_id
+		WHERE `+where+`
+		ORDER BY t.last_me_id DESC LIMIT ?`, args...).Scan(&threads).Error
+	if err != nil || len(threads) == 0 {
+		return threads, err
+	}
+
+	lastIDs := make([]int, 0, len(threads))
+	for _, t := range threads {
+		lastIDs = append(lastIDs, t.LastMeID)
+	}
+	var lasts []gnuboard.G5Memo
+	if err := r.db.Where("me_id IN ?", lastIDs).Find(&lasts).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*gnuboard.G5Memo, len(lasts))
+	for i := range lasts {
+		byID[lasts[i].MeID] = &lasts[i]
+	}
+	for _, t := range threads {
+		if m, ok := byID[t.LastMeID]; ok {
+			t.LastMemo = m.MeMemo
+			t.LastAt = m.MeSendDatetime.Format("2006-01-02 15:04:05")
+			t.LastMine = m.MeType == "send"
+		}
+	}
+	return threads, nil
+}
+
+func (r *memoThreadRepository) Messages(mbID, peer string, before, limit int) ([]*gnuboard.G5Memo, error) {
+	q := r.db.Where("((me_recv_mb_id = ? AND me_send_mb_id = ? AND me_type = 'recv') OR (me_send_mb_id = ? AND me_recv_mb_id = ? AND me_type = 'send'))",
+		mbID, peer, mbID, peer)
+	if before > 0 {
+		q = q.Where("me_id < ?", before)
+	}
+	var memos []*gnuboard.G5Memo
+	err := q.Order("me_id DESC").Limit(limit).Find(&memos).Error
+	return memos, err
+}
+
+func (r *memoThreadRepository) ReadTimes(recvIDs []int) (map[int]string, error) {
+	out := make(map[int]string)
+	if len(recvIDs) == 0 {
+		return out, nil
+	}
+	var rows []gnuboard.G5Memo
+	if err := r.db.Select("me_id, me_read_datetime").
+		Where("me_id IN ? AND me_type = 'recv'", recvIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		if rows[i].IsRead() {
+			out[rows[i].MeID] = rows[i].MeReadDatetime
+		}
+	}
+	return out, nil
+}
+
+func (r *memoThreadRepository) MarkThreadRead(mbID, peer string) (int64, error) {
+	result := r.db.Model(&gnuboard.G5Memo{}).
+		Where("me_recv_mb_id = ? AND me_send_mb_id = ? AND me_type = 'recv' AND me_read_datetime = ?", mbID, peer, memoUnreadDatetime).
+		Update("me_read_datetime", time.Now().Format("2006-01-02 15:04:05"))
+	return result.RowsAffected, result.Error
+}
+
+func (r *memoThreadRepository) State(mbID, peer string) (*gnuboard.MemoThreadState, error) {
+	state := &gnuboard.MemoThreadState{MbID: mbID, PeerMbID: peer}
+	err := r.db.Where("mb_id = ? AND peer_mb_id = ?", mbID, peer).Limit(1).Find(state).Error
+	return state, err
+}
+
+func (r *memoThreadRepository) SetArchived(mbID, peer string, archivedMeID int) error {
+	return r.upsertState(&gnuboard.MemoThreadState{MbID: mbID, PeerMbID: peer, ArchivedMeID: archivedMeID}, "archived_me_id")
+}
+
+func (r *memoThreadRepository) SetMuted(mbID, peer string, muted bool) error {
+	return r.upsertState(&gnuboard.MemoThreadState{MbID: mbID, PeerMbID: peer, Muted: muted}, "muted")
+}
+
+// upsertState 는 column 하나만 바꾼다 — 보관과 알림 끄기는 서로 건드리지 않는다
+func (r *memoThreadRepository) upsertState(state *gnuboard.MemoThreadState, column string) error {
+	state.UpdatedAt = time.Now()
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "mb_id"}, {Name: "peer_mb_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{column, "updated_at"}),
+	}).Create(state).Error
+}
+
+func (r *memoThreadRepository) CreateAttachment(a *gnuboard.MemoAttachment) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	return r.db.Create(a).Error
+}
+
+func (r *memoThreadRepository) CountPending(mbID string, ids []int64) (int64, error) {
+	var count int64
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	err := r.db.Model(&gnuboard.MemoAttachment{}).Where("id IN ? AND mb_id = ? AND me_id = 0", ids, mbID).Count(&count).Error
+	return count, err
+}
+
+func (r *memoThreadRepository) AttachPending(mbID string, ids []int64, meID int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&gnuboard.MemoAttachment{}).Where("id IN ? AND mb_id = ? AND me_id = 0", ids, mbID).
+		Update("me_id", meID).Error
+}
+
+func (r *memoThreadRepository) AttachmentsFor(meIDs []int) (map[int][]gnuboard.MemoAttachment, error) {
+	out := make(map[int][]gnuboard.MemoAttachment)
+	if len(meIDs) == 0 {
+		return out, nil
+	}
+	var rows []gnuboard.MemoAttachment
+	if err := r.db.Where("me_id IN ?", meIDs).Order("id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, a := range rows {
+		out[a.MeID] = append(out[a.MeID], a)
+	}
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package gnuboard
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain/gnuboard"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newMemoThreadTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&gnuboard.G5Memo{}, &gnuboard.MemoThreadState{}, &gnuboard.MemoAttachment{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// sendMemo writes a recv·send pair the way Gnuboard does — 받은 쪽은 미열람(zero date)
+func sendMemo(t *testing.T, db *gorm.DB, from, to, text string) int {
+	t.Helper()
+	now := time.Now()
+	recv := gnuboard.G5Memo{MeSendMbID: from, MeRecvMbID: to, MeMemo: text, MeReadDatetime: memoUnreadDatetime, MeSendDatetime: now, MeType: "recv"}
+	if err := db.Create(&recv).Error; err != nil {
+		t.Fatalf("create recv: %v", err)
+	}
+	send := gnuboard.G5Memo{MeSendMbID: from, MeRecvMbID: to, MeMemo: text, MeReadDatetime: now.Format("2006-01-02 15:04:05"), MeSendDatetime: now, MeType: "send", MeSendID: recv.MeID}
+	if err := db.Create(&send).Error; err != nil {
+		t.Fatalf("create send: %v", err)
+	}
+	db.Model(&recv).Update("me_send_id", send.MeID)
+	return recv.MeID
+}
+
+func TestMemoThreads(t *testing.T) {
+	db := newMemoThreadTestDB(t)
+	repo := NewMemoThreadRepository(db)
+
+	sendMemo(t, db, "kim", "zoe", "팝니다?")
+	sendMemo(t, db, "zoe", "kim", "얼마인가요")
+	first := sendMemo(t, db, "kim", "zoe", "3만원")
+	sendMemo(t, db, "lee", "zoe", "안녕하세요")
+	sendMemo(t, db, "zoe", "park", "답장 주세요")
+
+	threads, err := repo.Threads("zoe", false, 0, 10)
+	if err != nil {
+		t.Fatalf("Threads: %v", err)
+	}
+	var got []string
+	for _, th := range threads {
+		got = append(got, fmt.Sprintf("%s:%d:%s:%v", th.PeerMbID, th.Unread, th.LastMemo, th.LastMine))
+	}
+	if fmt.Sprint(got) != "[park:0:답장 주세요:true lee:1:안녕하세요:false kim:2:3만원:false]" {
+		t.Fatalf("unexpected threads %v", got)
+	}
+	// 커서는 이전 페이지 마지막 대화방의 last_message_id
+	if page, _ := repo.Threads("zoe", false, threads[1].LastMeID, 10); len(page) != 1 || page[0].PeerMbID != "kim" {
+		t.Fatalf("cursor page should hold kim only, got %+v", page)
+	}
+
+	msgs, err := repo.Messages("zoe", "kim", 0, 10)
+	if err != nil || len(msgs) != 3 || msgs[0].MeMemo != "3만원" || msgs[1].MeType != "send" {
+		t.Fatalf("Messages: %+v %v", msgs, err)
+	}
+	// 내가 보낸 쪽지의 읽음 확인은 상대가 읽은 뒤에 생긴다
+	if receipts, _ := repo.ReadTimes([]int{msgs[1].PairKey()}); len(receipts) != 0 {
+		t.Fatalf("kim has not read yet, got %v", receipts)
+	}
+	if n, _ := repo.MarkThreadRead("kim", "zoe"); n != 1 {
+		t.Fatalf("kim should read one message, got %d", n)
+	}
+	if receipts, _ := repo.ReadTimes([]int{msgs[1].PairKey()}); len(receipts) != 1 {
+		t.Fatalf("expected a read receipt, got %v", receipts)
+	}
+	if n, _ := repo.MarkThreadRead("zoe", "kim"); n != 2 {
+		t.Fatalf("zoe should read two messages, got %d", n)
+	}
+
+	// 보관 → 목록에서 빠지고, 새 쪽지가 오면 돌아온다. 알림 끄기는 보관을 건드리지 않는다.
+	_ = repo.SetArchived("zoe", "kim", first)
+	_ = repo.SetMuted("zoe", "kim", true)
+	if threads, _ := repo.Threads("zoe", false, 0, 10); len(threads) != 2 {
+		t.Fatalf("archived thread should be hidden, got %d threads", len(threads))
+	}
+	if archived, _ := repo.Threads("zoe", true, 0, 10); len(archived) != 1 || !archived[0].Archived || !archived[0].Muted {
+		t.Fatalf("unexpected archived list %+v", archived)
+	}
+	sendMemo(t, db, "kim", "zoe", "아직 있나요")
+	if threads, _ := repo.Threads("zoe", false, 0, 10); len(threads) != 3 || threads[0].PeerMbID != "kim" || threads[0].Archived {
+		t.Fatalf("new message should unarchive, got %+v", threads[0])
+	}
+
+	// 첨부 — 내 미전송 업로드만 붙는다
+	mine := &gnuboard.MemoAttachment{MbID: "zoe", FileKey: "images/a.jpg", URL: "https://cdn/a.jpg"}
+	other := &gnuboard.MemoAttachment{MbID: "kim", FileKey: "images/b.jpg", URL: "https://cdn/b.jpg"}
+	_ = repo.CreateAttachment(mine)
+	_ = repo.CreateAttachment(other)
+	if n, _ := repo.CountPending("zoe", []int64{mine.ID, other.ID}); n != 1 {
+		t.Fatalf("only zoe's upload is pending for zoe, got %d", n)
+	}
+	pair := sendMemo(t, db, "zoe", "kim", "")
+	_ = repo.AttachPending("zoe", []int64{mine.ID}, pair)
+	if byMsg, _ := repo.AttachmentsFor([]int{pair}); len(byMsg[pair]) != 1 || byMsg[pair][0].URL != mine.URL {
+		t.Fatalf("unexpected attachments %+v", byMsg)
+	}
+	if n, _ := repo.CountPending("zoe", []int64{mine.ID}); n != 0 {
+		t.Fatal("sent upload must not be attachable again")
+	}
+}
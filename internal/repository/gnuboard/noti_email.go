@@ -0,0 +1,272 @@
+package gnuboard
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 이메일 알림 분류 — 회원이 분류마다 받기(즉시·일간·주간)/끄기를 고른다
+const (
+	EmailCategoryAccount = "account" // 계정: 탈퇴 숙려 만료 예고, 이용 제한(제재) 안내
+	EmailCategoryPoint   = "point"   // 포인트 만료 예정
+	EmailCategoryDigest  = "digest"  // 게시판 요약 구독
+)
+
+// 이메일 받는 주기 — 일간·주간은 쌓아 두었다가 요약 한 통으로 보낸다
+const (
+	EmailFrequencyOff     = "off"
+	EmailFrequencyInstant = "instant"
+	EmailFrequencyDaily   = "daily"
+	EmailFrequencyWeekly  = "weekly"
+)
+
+// g5_noti_email_queue.status
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+	EmailStatusSkipped = "skipped" // 주소 없음·수신 거부 목록·발송 전에 알림을 끔
+)
+
+// g5_noti_email_suppression.reason
+const (
+	EmailSuppressionBounce    = "bounce"    // 영구 실패(SMTP 5xx) — 발송기가 올린다
+	EmailSuppressionComplaint = "complaint" // 스팸 신고
+	EmailSuppressionManual    = "manual"
+)
+
+// EmailCategories lists the categories in display order
+var EmailCategories = []string{EmailCategoryAccount, EmailCategoryPoint, EmailCategoryDigest}
+
+// emailCategoryDefaults 는 설정 행이 없을 때의 기본값이다. 계정 안내만 기본으로 받고 나머지는 옵트인.
+var emailCategoryDefaults = map[string]string{
+	EmailCategoryAccount: EmailFrequencyInstant,
+	EmailCategoryPoint:   EmailFrequencyOff,
+	EmailCategoryDigest:  EmailFrequencyOff,
+}
+
+// ValidEmailFrequency reports whether f is one of the frequencies
+func ValidEmailFrequency(f string) bool {
+	switch f {
+	case EmailFrequencyOff, EmailFrequencyInstant, EmailFrequencyDaily, EmailFrequencyWeekly:
+		return true
+	}
+	return false
+}
+
+// ValidEmailCategory reports whether c is one of EmailCategories
+func ValidEmailCategory(c string) bool {
+	_, ok := emailCategoryDefaults[c]
+	return ok
+}
+
+// EmailPreference represents a row in g5_noti_email_preference (회원 × 분류)
+type EmailPreference struct {
+	MbID      string    `gorm:"column:mb_id;primaryKey;size:20"`
+	Category  string    `gorm:"column:category;primaryKey;size:20"`
+	Frequency string    `gorm:"column:frequency;size:10;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// TableName returns the g5_noti_email_preference table name
+func (EmailPreference) TableName() string { return "g5_noti_email_preference" }
+
+// EmailOutbox represents a row in g5_noti_email_queue.
+// 알림을 만드는 곳(cron 등)이 쌓고 service.EmailNotificationService 가 꺼내 보낸다 — 즉시 분류는 매 몇 분,
+// 일간·주간은 요약 한 통으로 묶어서. Frequency 는 쌓을 때의 설정이다.
+type EmailOutbox struct {
+	ID       int64  `gorm:"column:id;primaryKey;autoIncrement"`
+	MbID     string `gorm:"column:mb_id;size:20;not null;index:idx_noti_email_queue_mb"`
+	Category string `gorm:"column:category;size:20;not null"`
+	Template string `gorm:"column:template;size:40;not null"`
+	// Args 는 템플릿 문구(i18n email.<template>.*)의 %s 자리에 들어갈 값들이다 (JSON 배열)
+	Args        string     `gorm:"column:args;type:text"`
+	Link        string     `gorm:"column:link;size:255"` // 사이트 경로 (/point 등)
+	Frequency   string     `gorm:"column:frequency;size:10;not null;index:idx_noti_email_queue_due,priority:2"`
+	Status      string     `gorm:"column:status;size:10;not null;index:idx_noti_email_queue_due,priority:1"`
+	Attempts    int        `gorm:"column:attempts;not null;default:0"`
+	LastError   string     `gorm:"column:last_error;size:500"`
+	AvailableAt time.Time  `gorm:"column:available_at;index:idx_noti_email_queue_due,priority:3"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	SentAt      *time.Time `gorm:"column:sent_at"`
+}
+
+// TableName returns the g5_noti_email_queue table name
+func (EmailOutbox) TableName() string { return "g5_noti_email_queue" }
+
+// ArgList decodes Args
+func (o *EmailOutbox) ArgList() []string {
+	var args []string
+	_ = json.Unmarshal([]byte(o.Args), &args)
+	return args
+}
+
+// EmailSuppression represents a row in g5_noti_email_suppression — 이 주소로는 보내지 않는다.
+// 영구 실패(SMTP 5xx)면 발송기가 자동으로, 스팸 신고·요청은 관리자가 올린다.
+type EmailSuppression struct {
+	Email     string    `gorm:"column:email;primaryKey;size:255"`
+	Reason    string    `gorm:"column:reason;size:20;not null"`
+	Detail    string    `gorm:"column:detail;size:500"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+// TableName returns the g5_noti_email_suppression table name
+func (EmailSuppression) TableName() string { return "g5_noti_email_suppression" }
+
+// EnqueueEmail queues an email notification for the member's current setting of category.
+// 꺼 둔 분류면 아무것도 하지 않는다. tx 로 부르면 알림을 만든 변경과 함께 커밋·롤백된다 (EnqueueSearchSync 와 같은 outbox).
+func EnqueueEmail(tx *gorm.DB, mbID, category, template, link string, args ...string) error {
+	freq, err := emailFrequency(tx, mbID, category)
+	if err != nil || freq == EmailFrequencyOff {
+		return err
+	}
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return tx.Create(&EmailOutbox{
+		MbID:        mbID,
+		Category:    category,
+		Template:    template,
+		Args:        string(encoded),
+		Link:        link,
+		Frequency:   freq,
+		Status:      EmailStatusPending,
+		AvailableAt: now,
+		CreatedAt:   now,
+	}).Error
+}
+
+func emailFrequency(db *gorm.DB, mbID, category string) (string, error) {
+	var pref EmailPreference
+	err := db.Where("mb_id = ? AND category = ?", mbID, category).Take(&pref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return emailCategoryDefaults[category], nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return pref.Frequency, nil
+}
+
+// NotiEmailRepository handles email preferences, the outbox and the suppression list
+type NotiEmailRepository interface {
+	// Preferences returns every category's frequency (설정 행이 없으면 기본값)
+	Preferences(mbID string) (map[string]string, error)
+	SetPreference(mbID, category, frequency string) error
+
+	// Due returns pending rows of frequency that are available by now, oldest first
+	Due(frequency string, now time.Time, limit int) ([]EmailOutbox, error)
+	MarkSent(ids []int64, now time.Time) error
+	MarkSkipped(ids []int64, reason string) error
+	// MarkRetry records a failed attempt. final 이면 failed 로 끝내고, 아니면 retryAt 에 다시 꺼낸다.
+	MarkRetry(ids []int64, errMsg string, retryAt time.Time, final bool) error
+
+	IsSuppressed(email string) (bool, error)
+	Suppress(email, reason, detail string) error
+	Unsuppress(email string) error
+	ListSuppressions(offset, limit int) ([]EmailSuppression, int64, error)
+}
+
+type notiEmailRepository struct {
+	db *gorm.DB
+}
+
+// NewNotiEmailRepository creates a new NotiEmailRepository
+func NewNotiEmailRepository(db *gorm.DB) NotiEmailRepository {
+	return &notiEmailRepository{db: db}
+}
+
+func (r *notiEmailRepository) Preferences(mbID string) (map[string]string, error) {
+	var rows []EmailPreference
+	if err := r.db.Where("mb_id = ?", mbID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	prefs := make(map[string]string, len(emailCategoryDefaults))
+	for c, f := range emailCategoryDefaults {
+		prefs[c] = f
+	}
+	for _, row := range rows {
+		if ValidEmailCategory(row.Category) {
+			prefs[row.Category] = row.Frequency
+		}
+	}
+	return prefs, nil
+}
+
+func (r *notiEmailRepository) SetPreference(mbID, category, frequency string) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "mb_id"}, {Name: "category"}},
+		DoUpdates: clause.AssignmentColumns([]string{"frequency", "updated_at"}),
+	}).Create(&EmailPreference{MbID: mbID, Category: category, Frequency: frequency, UpdatedAt: time.Now()}).Error
+}
+
+func (r *notiEmailRepository) Due(frequency string, now time.Time, limit int) ([]EmailOutbox, error) {
+	var rows []EmailOutbox
+	err := r.db.Where("status = ? AND frequency = ? AND available_at <= ?", EmailStatusPending, frequency, now).
+		Order("id").Limit(limit).Find(&rows).Error
+	return rows, err
+}
+
+func (r *notiEmailRepository) MarkSent(ids []int64, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&EmailOutbox{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"status": EmailStatusSent, "sent_at": now, "last_error": ""}).Error
+}
+
+func (r *notiEmailRepository) MarkSkipped(ids []int64, reason string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&EmailOutbox{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"status": EmailStatusSkipped, "last_error": TrimWriteAfterEventError(errors.New(reason))}).Error
+}
+
+func (r *notiEmailRepository) MarkRetry(ids []int64, errMsg string, retryAt time.Time, final bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	updates := map[string]interface{}{
+		"attempts":     gorm.Expr("attempts + 1"),
+		"last_error":   TrimWriteAfterEventError(errors.New(errMsg)),
+		"available_at": retryAt,
+	}
+	if final {
+		updates["status"] = EmailStatusFailed
+	}
+	return r.db.Model(&EmailOutbox{}).Where("id IN ?", ids).Updates(updates).Error
+}
+
+func (r *notiEmailRepository) IsSuppressed(email string) (bool, error) {
+	var count int64
+	err := r.db.Model(&EmailSuppression{}).Where("email = ?", email).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *notiEmailRepository) Suppress(email, reason, detail string) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "email"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "detail"}),
+	}).Create(&EmailSuppression{Email: email, Reason: reason, Detail: detail, CreatedAt: time.Now()}).Error
+}
+
+func (r *notiEmailRepository) Unsuppress(email string) error {
+	return r.db.Where("email = ?", email).Delete(&EmailSuppression{}).Error
+}
+
+func (r *notiEmailRepository) ListSuppressions(offset, limit int) ([]EmailSuppression, int64, error) {
+	var total int64
+	if err := r.db.Model(&EmailSuppression{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []EmailSuppression
+	err := r.db.Order("created_at DESC").Offset(offset).Limit(limit).Find(&rows).Error
+	return rows, total, err
+}
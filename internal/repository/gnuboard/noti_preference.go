@@ -1,6 +1,7 @@
 package gnuboard
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -15,9 +16,87 @@ type NotiPreference struct {
 	NotiLike    bool   `gorm:"column:noti_like;default:1"`
 	NotiFollow  bool   `gorm:"column:noti_follow;default:1"`
 	// 게시판 구독('write' subscribe) 알림 — 회원 팔로우(NotiFollow)와 분리 (#12607)
-	NotiBoardSubscribe bool      `gorm:"column:noti_board_subscribe;default:1"`
-	LikeThreshold      int       `gorm:"column:like_threshold;default:1"`
-	UpdatedAt          time.Time `gorm:"column:updated_at"`
+	NotiBoardSubscribe bool `gorm:"column:noti_board_subscribe;default:1"`
+	LikeThreshold      int  `gorm:"column:like_threshold;default:1"`
+	// 멘션은 내가 팔로우하는 회원의 것만 울린다 — 나머지는 목록에 읽음으로 남는다
+	MentionFollowingOnly bool `gorm:"column:mention_following_only;default:0"`
+	// 방해 금지 시간 "HH:MM" (TimeZone 기준, 자정을 넘겨도 된다). 둘 다 비면 끔. 이 동안은 푸시만 보내지 않는다.
+	QuietStart string    `gorm:"column:quiet_start;size:5;default:''"`
+	QuietEnd   string    `gorm:"column:quiet_end;size:5;default:''"`
+	TimeZone   string    `gorm:"column:time_zone;size:64;default:'Asia/Seoul'"`
+	UpdatedAt  time.Time `gorm:"column:updated_at"`
+}
+
+// DefaultNotiTimeZone 는 방해 금지 시간의 기본 시간대다
+const DefaultNotiTimeZone = "Asia/Seoul"
+
+// ParseQuietClock parses "HH:MM" into minutes after midnight
+func ParseQuietClock(s string) (int, error) {
+	if len(s) != 5 || s[2] != ':' {
+		return 0, fmt.Errorf("invalid time %q (HH:MM)", s)
+	}
+	for _, i := range []int{0, 1, 3, 4} {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, fmt.Errorf("invalid time %q (HH:MM)", s)
+		}
+	}
+	h := int(s[0]-'0')*10 + int(s[1]-'0')
+	m := int(s[3]-'0')*10 + int(s[4]-'0')
+	if h > 23 || m > 59 {
+		return 0, fmt.Errorf("invalid time %q (HH:MM)", s)
+	}
+	return h*60 + m, nil
+}
+
+// InQuietHours reports whether t falls in the member's quiet hours.
+// 시작 == 끝이거나 값이 깨져 있으면 끈 것으로 본다.
+func (p *NotiPreference) InQuietHours(t time.Time) bool {
+	if p.QuietStart == "" || p.QuietEnd == "" {
+		return false
+	}
+	start, err1 := ParseQuietClock(p.QuietStart)
+	end, err2 := ParseQuietClock(p.QuietEnd)
+	if err1 != nil || err2 != nil || start == end {
+		return false
+	}
+	tz := p.TimeZone
+	if tz == "" {
+		tz = DefaultNotiTimeZone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end // 23:00–07:00 처럼 자정을 넘는 구간
+}
+
+// Allows reports whether the per-type switches let n through.
+// ph_from_case 갈래는 handler.mapNotificationType 과 같다 — 구독형(write subscribe·digest·popular)은 NotiBoardSubscribe.
+func (p *NotiPreference) Allows(n *Notification) bool {
+	switch n.PhFromCase {
+	case "comment", "reply", "board":
+		if n.PhToCase == "comment_reply" {
+			return p.NotiReply
+		}
+		return p.NotiComment
+	case "mention":
+		return p.NotiMention
+	case "good":
+		return p.NotiLike
+	case "write":
+		if n.PhToCase == "follow" {
+			return p.NotiFollow
+		}
+		return p.NotiBoardSubscribe
+	case "digest":
+		return p.NotiBoardSubscribe
+	}
+	return true
 }
 
 // TableName returns the g5_noti_preference table name
@@ -53,6 +132,7 @@ func (r *notiPreferenceRepository) Get(mbID string) (*NotiPreference, error) {
 				NotiFollow:         true,
 				NotiBoardSubscribe: true,
 				LikeThreshold:      1,
+				TimeZone:           DefaultNotiTimeZone,
 			}, nil
 		}
 		return nil, err
@@ -67,12 +147,16 @@ func (r *notiPreferenceRepository) Upsert(pref *NotiPreference) error {
 		return r.db.Create(pref).Error
 	}
 	return r.db.Model(&NotiPreference{}).Where("mb_id = ?", pref.MbID).Updates(map[string]interface{}{
-		"noti_comment":         pref.NotiComment,
-		"noti_reply":           pref.NotiReply,
-		"noti_mention":         pref.NotiMention,
-		"noti_like":            pref.NotiLike,
-		"noti_follow":          pref.NotiFollow,
-		"noti_board_subscribe": pref.NotiBoardSubscribe,
-		"like_threshold":       pref.LikeThreshold,
+		"noti_comment":           pref.NotiComment,
+		"noti_reply":             pref.NotiReply,
+		"noti_mention":           pref.NotiMention,
+		"noti_like":              pref.NotiLike,
+		"noti_follow":            pref.NotiFollow,
+		"noti_board_subscribe":   pref.NotiBoardSubscribe,
+		"like_threshold":         pref.LikeThreshold,
+		"mention_following_only": pref.MentionFollowingOnly,
+		"quiet_start":            pref.QuietStart,
+		"quiet_end":              pref.QuietEnd,
+		"time_zone":              pref.TimeZone,
 	}).Error
 }
@@ -34,6 +34,27 @@ type Notification struct {
 // TableName returns the g5_na_noti table name
 func (Notification) TableName() string { return "g5_na_noti" }
 
+// notificationCreated 는 g5_na_noti 행이 생길 때마다 ph_id 로 불린다 (SetNotificationCreatedHook)
+var notificationCreated func(phID int)
+
+// SetNotificationCreatedHook sets the callback for every inserted notification (실시간 전달 — worker.NotiRealtimeWorker).
+// 기동 시 한 번만 설정한다. nil 이면 아무것도 하지 않는다(배치 도구 등).
+func SetNotificationCreatedHook(fn func(phID int)) {
+	notificationCreated = fn
+}
+
+// AfterCreate runs for every insert through the Notification model — NotiRepository.Create 든
+// 잡·핸들러의 db.Create 든 같은 경로를 지난다.
+//
+// ⛔ 트랜잭션 안에서 불릴 수 있다(dry-run 잡은 롤백된다). 그래서 ph_id 만 넘기고, 받는 쪽이
+// 커밋된 행을 다시 읽어 전달한다 — 롤백된 알림이 소켓으로 나가지 않도록.
+func (n *Notification) AfterCreate(_ *gorm.DB) error {
+	if notificationCreated != nil && n.PhID > 0 {
+		notificationCreated(n.PhID)
+	}
+	return nil
+}
+
 // GroupedNotification represents a group of notifications for the same post+type
 type GroupedNotification struct {
 	BoTable    string `gorm:"column:bo_table"`
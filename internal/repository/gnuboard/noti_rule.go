@@ -0,0 +1,154 @@
+package gnuboard
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// g5_noti_rule.mode — 글 단위(wr_id > 0)는 mute·follow, 게시판 단위(wr_id = 0)는 mute·on
+const (
+	NotiRuleMute   = "mute"   // 알림은 목록에 읽음으로만 남긴다 (푸시·실시간 없음)
+	NotiRuleFollow = "follow" // 글: 내 글·댓글이 아니어도 새 댓글을 받는다, 종류별 스위치보다 우선
+	NotiRuleOn     = "on"     // 게시판: 종류별 스위치를 꺼 뒀어도 이 게시판 알림은 받는다
+)
+
+// ValidNotiRuleMode reports whether mode fits the rule's scope (wrID 0 = 게시판)
+func ValidNotiRuleMode(wrID int, mode string) bool {
+	if wrID > 0 {
+		return mode == NotiRuleMute || mode == NotiRuleFollow
+	}
+	return mode == NotiRuleMute || mode == NotiRuleOn
+}
+
+// NotiRule represents a row in g5_noti_rule — 회원이 글·게시판 하나에 건 알림 규칙
+type NotiRule struct {
+	MbID    string `gorm:"column:mb_id;primaryKey;size:20" json:"-"`
+	BoTable string `gorm:"column:bo_table;primaryKey;size:20;index:idx_noti_rule_thread,priority:1" json:"bo_table"`
+	// WrID 는 원글 id(알림의 wr_parent)다. 0 이면 게시판 전체 규칙.
+	WrID      int       `gorm:"column:wr_id;primaryKey;index:idx_noti_rule_thread,priority:2" json:"wr_id"`
+	Mode      string    `gorm:"column:mode;size:10;not null" json:"mode"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// TableName returns the g5_noti_rule table name
+func (NotiRule) TableName() string { return "g5_noti_rule" }
+
+// NotiDecision is what happens to one notification for its recipient
+type NotiDecision int
+
+const (
+	NotiDeliver NotiDecision = iota // 목록 + 실시간 + 푸시
+	NotiSilent                      // 목록에 읽음으로만 남긴다
+	NotiDrop                        // 만들지 않는다 (종류별 스위치를 끔)
+)
+
+// DecideNotification applies the recipient's rules to n. 더 좁은 규칙이 이긴다:
+// 글 규칙 → 게시판 규칙 → 종류별 스위치 → 멘션 팔로우 한정.
+// thread·board 는 n 의 글·게시판에 걸린 규칙(없으면 nil), followsSender 는 수신자가 보낸 사람을 팔로우하는지다.
+func DecideNotification(pref *NotiPreference, thread, board *NotiRule, n *Notification, followsSender bool) NotiDecision {
+	if thread != nil {
+		switch thread.Mode {
+		case NotiRuleMute:
+			return NotiSilent
+		case NotiRuleFollow:
+			return NotiDeliver
+		}
+	}
+	boardOn := false
+	if board != nil {
+		switch board.Mode {
+		case NotiRuleMute:
+			return NotiSilent
+		case NotiRuleOn:
+			boardOn = true
+		}
+	}
+	if !boardOn && !pref.Allows(n) {
+		return NotiDrop
+	}
+	if n.PhFromCase == "mention" && pref.MentionFollowingOnly && !followsSender {
+		return NotiSilent
+	}
+	return NotiDeliver
+}
+
+// NotiRuleRepository handles per-thread and per-board notification rules
+type NotiRuleRepository interface {
+	List(mbID string) ([]NotiRule, error)
+	Set(rule *NotiRule) error
+	Delete(mbID, boTable string, wrID int) error
+	// ThreadFollowers returns members following the thread (boTable, wrID)
+	ThreadFollowers(boTable string, wrID int) ([]string, error)
+	// Decide loads the recipient's rules for n and runs DecideNotification
+	Decide(pref *NotiPreference, n *Notification) (NotiDecision, error)
+}
+
+type notiRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewNotiRuleRepository creates a new NotiRuleRepository
+func NewNotiRuleRepository(db *gorm.DB) NotiRuleRepository {
+	return &notiRuleRepository{db: db}
+}
+
+func (r *notiRuleRepository) List(mbID string) ([]NotiRule, error) {
+	var rules []NotiRule
+	err := r.db.Where("mb_id = ?", mbID).Order("bo_table, wr_id").Find(&rules).Error
+	return rules, err
+}
+
+func (r *notiRuleRepository) Set(rule *NotiRule) error {
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = time.Now()
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "mb_id"}, {Name: "bo_table"}, {Name: "wr_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mode"}),
+	}).Create(rule).Error
+}
+
+func (r *notiRuleRepository) Delete(mbID, boTable string, wrID int) error {
+	return r.db.Where("mb_id = ? AND bo_table = ? AND wr_id = ?", mbID, boTable, wrID).Delete(&NotiRule{}).Error
+}
+
+func (r *notiRuleRepository) ThreadFollowers(boTable string, wrID int) ([]string, error) {
+	var ids []string
+	err := r.db.Model(&NotiRule{}).Where("bo_table = ? AND wr_id = ? AND mode = ?", boTable, wrID, NotiRuleFollow).
+		Pluck("mb_id", &ids).Error
+	return ids, err
+}
+
+func (r *notiRuleRepository) Decide(pref *NotiPreference, n *Notification) (NotiDecision, error) {
+	var thread, board *NotiRule
+	if n.BoTable != "" {
+		postID := n.WrParent
+		if postID == 0 {
+			postID = n.WrID
+		}
+		var rules []NotiRule
+		if err := r.db.Where("mb_id = ? AND bo_table = ? AND wr_id IN ?", pref.MbID, n.BoTable, []int{0, postID}).
+			Find(&rules).Error; err != nil {
+			return NotiDeliver, err
+		}
+		for i := range rules {
+			if rules[i].WrID == 0 {
+				board = &rules[i]
+			} else {
+				thread = &rules[i]
+			}
+		}
+	}
+	follows := false
+	if n.PhFromCase == "mention" && pref.MentionFollowingOnly && n.RelMbID != "" {
+		var count int64
+		if err := r.db.Table("g5_member_follow").Where("mb_id = ? AND target_id = ?", pref.MbID, n.RelMbID).
+			Count(&count).Error; err != nil {
+			return NotiDeliver, err
+		}
+		follows = count > 0
+	}
+	return DecideNotification(pref, thread, board, n, follows), nil
+}
@@ -0,0 +1,63 @@
+package gnuboard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecideNotification(t *testing.T) {
+	pref := &NotiPreference{MbID: "zoe", NotiComment: true, NotiReply: true, NotiMention: true, MentionFollowingOnly: true}
+	comment := &Notification{MbID: "zoe", PhFromCase: "comment", PhToCase: "comment", BoTable: "free", WrID: 11, WrParent: 10}
+	subscribe := &Notification{MbID: "zoe", PhFromCase: "write", PhToCase: "subscribe", BoTable: "free", WrID: 12}
+	mention := &Notification{MbID: "zoe", PhFromCase: "mention", RelMbID: "bob", BoTable: "free", WrID: 13}
+
+	cases := []struct {
+		name          string
+		thread, board *NotiRule
+		n             *Notification
+		follows       bool
+		want          NotiDecision
+	}{
+		{"switch on", nil, nil, comment, false, NotiDeliver},
+		{"switch off", nil, nil, subscribe, false, NotiDrop},
+		{"board on beats switch", nil, &NotiRule{Mode: NotiRuleOn}, subscribe, false, NotiDeliver},
+		{"board mute", nil, &NotiRule{Mode: NotiRuleMute}, comment, false, NotiSilent},
+		{"thread mute", &NotiRule{Mode: NotiRuleMute}, &NotiRule{Mode: NotiRuleOn}, comment, false, NotiSilent},
+		{"thread follow beats board mute", &NotiRule{Mode: NotiRuleFollow}, &NotiRule{Mode: NotiRuleMute}, comment, false, NotiDeliver},
+		{"mention from stranger", nil, nil, mention, false, NotiSilent},
+		{"mention from followed", nil, nil, mention, true, NotiDeliver},
+	}
+	for _, tc := range cases {
+		if got := DecideNotification(pref, tc.thread, tc.board, tc.n, tc.follows); got != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	kst := time.FixedZone("KST", 9*3600)
+	at := func(h, m int) time.Time { return time.Date(2026, 10, 17, h, m, 0, 0, kst) }
+
+	overnight := &NotiPreference{QuietStart: "23:00", QuietEnd: "07:00", TimeZone: "Asia/Seoul"}
+	for _, tc := range []struct {
+		t    time.Time
+		want bool
+	}{
+		{at(23, 0), true}, {at(2, 30), true}, {at(6, 59), true}, {at(7, 0), false}, {at(12, 0), false},
+		{at(23, 30).UTC(), true}, // 서버 시간대와 무관하게 회원 시간대로 본다
+	} {
+		if got := overnight.InQuietHours(tc.t); got != tc.want {
+			t.Errorf("23:00–07:00 at %s: got %v", tc.t.In(kst).Format("15:04"), got)
+		}
+	}
+
+	daytime := &NotiPreference{QuietStart: "09:00", QuietEnd: "18:00", TimeZone: "UTC"}
+	if daytime.InQuietHours(at(12, 0)) || !daytime.InQuietHours(at(20, 0)) { // 03:00, 11:00 UTC
+		t.Error("09:00–18:00 UTC should cover 20:00 KST but not 12:00 KST")
+	}
+	for _, p := range []*NotiPreference{{}, {QuietStart: "07:00", QuietEnd: "07:00"}, {QuietStart: "7:00", QuietEnd: "08:00"}} {
+		if p.InQuietHours(at(7, 30)) {
+			t.Errorf("%+v should be off", p)
+		}
+	}
+}
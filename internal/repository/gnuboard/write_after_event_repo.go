@@ -206,6 +206,21 @@ func (r *writeAfterEventRepository) ReclaimStaleProcessing(staleBefore time.Time
 	return res.RowsAffected, res.Error
 }
 
+// EnqueueSearchSync adds a search sync event in the caller's transaction.
+// 워커를 거치지 않고 바뀌는 데이터(회원 익명화·광고주 글 일괄 비공개 등)가 검색 색인에 따라가게 한다.
+func EnqueueSearchSync(tx *gorm.DB, eventType, boardSlug string, writeID int, memberID string) error {
+	now := time.Now()
+	return tx.Create(&domain.WriteAfterEvent{
+		EventType:   eventType,
+		BoardSlug:   boardSlug,
+		WriteID:     writeID,
+		MemberID:    memberID,
+		Status:      domain.WriteAfterEventStatusPending,
+		OccurredAt:  now,
+		AvailableAt: now,
+	}).Error
+}
+
 func TrimWriteAfterEventError(err error) string {
 	if err == nil {
 		return ""
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// SavedSearchRepository handles saved search persistence
+type SavedSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSavedSearchRepository creates a new SavedSearchRepository
+func NewSavedSearchRepository(db *gorm.DB) *SavedSearchRepository {
+	return &SavedSearchRepository{db: db}
+}
+
+// AutoMigrate creates the saved_searches table.
+// ⛔ prod 는 수동 DDL 선행 원칙 — migrations/007_saved_searches.sql 참고.
+func (r *SavedSearchRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.SavedSearch{})
+}
+
+// ListByMember returns the member's saved searches, newest first
+func (r *SavedSearchRepository) ListByMember(ctx context.Context, mbID string) ([]domain.SavedSearch, error) {
+	var searches []domain.SavedSearch
+	err := r.db.WithContext(ctx).Where("mb_id = ?", mbID).Order("id DESC").Find(&searches).Error
+	return searches, err
+}
+
+// CountByMember counts the member's saved searches
+func (r *SavedSearchRepository) CountByMember(ctx context.Context, mbID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.SavedSearch{}).Where("mb_id = ?", mbID).Count(&count).Error
+	return count, err
+}
+
+// FindByMember returns one saved search owned by the member (nil if none)
+func (r *SavedSearchRepository) FindByMember(ctx context.Context, mbID string, id int64) (*domain.SavedSearch, error) {
+	var search domain.SavedSearch
+	err := r.db.WithContext(ctx).Where("id = ? AND mb_id = ?", id, mbID).First(&search).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &search, nil
+}
+
+// Create inserts a saved search
+func (r *SavedSearchRepository) Create(ctx context.Context, search *domain.SavedSearch) error {
+	return r.db.WithContext(ctx).Create(search).Error
+}
+
+// Update saves every column of a saved search
+func (r *SavedSearchRepository) Update(ctx context.Context, search *domain.SavedSearch) error {
+	return r.db.WithContext(ctx).Save(search).Error
+}
+
+// Delete removes a saved search owned by the member; it reports whether a row was removed
+func (r *SavedSearchRepository) Delete(ctx context.Context, mbID string, id int64) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ? AND mb_id = ?", id, mbID).Delete(&domain.SavedSearch{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListNotifying returns notify-enabled searches after afterID (id 순 배치 조회)
+func (r *SavedSearchRepository) ListNotifying(ctx context.Context, afterID int64, limit int) ([]domain.SavedSearch, error) {
+	var searches []domain.SavedSearch
+	err := r.db.WithContext(ctx).Where("notify = ? AND id > ?", true, afterID).
+		Order("id ASC").Limit(limit).Find(&searches).Error
+	return searches, err
+}
+
+// MarkChecked records an alert check; matchAt 이 nil 이 아니면 마지막 알림 글 시각도 옮긴다
+func (r *SavedSearchRepository) MarkChecked(ctx context.Context, id int64, checkedAt time.Time, matchAt *time.Time) error {
+	updates := map[string]interface{}{"last_checked_at": checkedAt}
+	if matchAt != nil {
+		updates["last_match_at"] = *matchAt
+	}
+	return r.db.WithContext(ctx).Model(&domain.SavedSearch{}).Where("id = ?", id).Updates(updates).Error
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// SiteIdentityProviderRepository handles per-tenant OIDC IdP settings
+type SiteIdentityProviderRepository struct {
+	db *gorm.DB
+}
+
+// NewSiteIdentityProviderRepository creates a new SiteIdentityProviderRepository
+func NewSiteIdentityProviderRepository(db *gorm.DB) *SiteIdentityProviderRepository {
+	return &SiteIdentityProviderRepository{db: db}
+}
+
+// AutoMigrate creates the site_identity_providers table
+// ⛔ 운영 DB 는 migrations/012_site_identity_providers.sql 과 함께 바꾼다
+func (r *SiteIdentityProviderRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.SiteIdentityProvider{})
+}
+
+// FindBySiteID retrieves the IdP of a site (없으면 nil)
+func (r *SiteIdentityProviderRepository) FindBySiteID(ctx context.Context, siteID string) (*domain.SiteIdentityProvider, error) {
+	var idp domain.SiteIdentityProvider
+	err := r.db.WithContext(ctx).Where("site_id = ?", siteID).First(&idp).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &idp, nil
+}
+
+// Save creates or updates the IdP of a site (site_id 당 하나)
+func (r *SiteIdentityProviderRepository) Save(ctx context.Context, idp *domain.SiteIdentityProvider) error {
+	return r.db.WithContext(ctx).Save(idp).Error
+}
+
+// Delete removes the IdP of a site
+func (r *SiteIdentityProviderRepository) Delete(ctx context.Context, siteID string) (bool, error) {
+	res := r.db.WithContext(ctx).Where("site_id = ?", siteID).Delete(&domain.SiteIdentityProvider{})
+	return res.RowsAffected > 0, res.Error
+}
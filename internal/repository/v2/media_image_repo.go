@@ -0,0 +1,42 @@
+package v2
+
+import (
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// MediaImageRepository v2 uploaded image / variant data access
+type MediaImageRepository interface {
+	// Create stores the image with its variants
+	Create(img *v2.V2MediaImage) error
+	// FindByURLs returns images (with variants) keyed by their original URL — 없는 주소는 빠진다
+	FindByURLs(urls []string) (map[string]*v2.V2MediaImage, error)
+}
+
+type mediaImageRepository struct {
+	db *gorm.DB
+}
+
+// NewMediaImageRepository creates a new v2 MediaImageRepository
+func NewMediaImageRepository(db *gorm.DB) MediaImageRepository {
+	return &mediaImageRepository{db: db}
+}
+
+func (r *mediaImageRepository) Create(img *v2.V2MediaImage) error {
+	return r.db.Create(img).Error
+}
+
+func (r *mediaImageRepository) FindByURLs(urls []string) (map[string]*v2.V2MediaImage, error) {
+	out := make(map[string]*v2.V2MediaImage)
+	if len(urls) == 0 {
+		return out, nil
+	}
+	var images []*v2.V2MediaImage
+	if err := r.db.Preload("Variants").Where("url IN ?", urls).Find(&images).Error; err != nil {
+		return nil, err
+	}
+	for _, img := range images {
+		out[img.URL] = img
+	}
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package v2
+
+import (
+	"errors"
+	"time"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// MediaObjectRepository v2 content-addressed media object data access
+type MediaObjectRepository interface {
+	// Claim returns the object stored for sum and marks it as just uploaded (유예기간을 다시 센다) — 없으면 nil
+	Claim(sum string, now time.Time) (*v2.V2MediaObject, error)
+	// Register records a newly stored object. 동시에 같은 내용이 먼저 기록됐으면 그 행을 돌려준다.
+	Register(obj *v2.V2MediaObject) (*v2.V2MediaObject, error)
+}
+
+type mediaObjectRepository struct {
+	db *gorm.DB
+}
+
+// NewMediaObjectRepository creates a new v2 MediaObjectRepository
+func NewMediaObjectRepository(db *gorm.DB) MediaObjectRepository {
+	return &mediaObjectRepository{db: db}
+}
+
+func (r *mediaObjectRepository) Claim(sum string, now time.Time) (*v2.V2MediaObject, error) {
+	// 먼저 갱신한다 — media-gc 는 last_uploaded_at 이 유예기간 안이면 지우지 않으므로,
+	// 갱신이 먹은 행은 이번 업로드가 끝날 때까지 살아 있다
+	res := r.db.Model(&v2.V2MediaObject{}).Where("sha256 = ?", sum).Update("last_uploaded_at", now)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	var obj v2.V2MediaObject
+	err := r.db.Where("sha256 = ?", sum).First(&obj).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+func (r *mediaObjectRepository) Register(obj *v2.V2MediaObject) (*v2.V2MediaObject, error) {
+	if err := r.db.Create(obj).Error; err != nil {
+		// uk(sha256) 충돌 — 같은 파일이 동시에 올라왔다. 같은 key 에 같은 내용이라 먼저 기록된 행을 쓴다
+		var existing v2.V2MediaObject
+		if findErr := r.db.Where("sha256 = ?", obj.SHA256).First(&existing).Error; findErr == nil {
+			return &existing, nil
+		}
+		return nil, err
+	}
+	return obj, nil
+}
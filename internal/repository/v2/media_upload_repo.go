@@ -0,0 +1,119 @@
+package v2
+
+import (
+	"errors"
+	"time"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// ErrUploadOffsetConflict is returned when another request advanced the upload first
+var ErrUploadOffsetConflict = errors.New("upload offset changed")
+
+// MediaUploadRepository v2 resumable upload data access
+type MediaUploadRepository interface {
+	Create(upload *v2.V2MediaUpload) error
+	// Find returns the upload — 없으면 nil
+	Find(id string) (*v2.V2MediaUpload, error)
+	// AppendPart records a stored chunk and moves the offset from part.Offset to part.Offset+part.Size.
+	// 그 사이 다른 요청이 offset 을 옮겼으면 ErrUploadOffsetConflict.
+	AppendPart(part *v2.V2MediaUploadPart, expiresAt time.Time) error
+	// Parts lists the chunks in offset order
+	Parts(uploadID string) ([]v2.V2MediaUploadPart, error)
+	// PendingUsage returns the number and declared bytes of the member's unfinished uploads
+	PendingUsage(mbID string, now time.Time) (count int64, bytes int64, err error)
+	// Complete records the stored file (조각 기록은 지운다)
+	Complete(id, fileKey, url, contentType string) error
+	// Delete removes the upload and its part rows
+	Delete(id string) error
+	// Expired lists uploads past expires_at (완료된 업로드 포함)
+	Expired(now time.Time, limit int) ([]v2.V2MediaUpload, error)
+}
+
+type mediaUploadRepository struct {
+	db *gorm.DB
+}
+
+// NewMediaUploadRepository creates a new v2 MediaUploadRepository
+func NewMediaUploadRepository(db *gorm.DB) MediaUploadRepository {
+	return &mediaUploadRepository{db: db}
+}
+
+func (r *mediaUploadRepository) Create(upload *v2.V2MediaUpload) error {
+	return r.db.Create(upload).Error
+}
+
+func (r *mediaUploadRepository) Find(id string) (*v2.V2MediaUpload, error) {
+	var upload v2.V2MediaUpload
+	err := r.db.Where("id = ?", id).First(&upload).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (r *mediaUploadRepository) AppendPart(part *v2.V2MediaUploadPart, expiresAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&v2.V2MediaUpload{}).
+			Where("id = ? AND status = ? AND upload_offset = ?", part.UploadID, v2.MediaUploadPending, part.Offset).
+			Updates(map[string]interface{}{"upload_offset": part.Offset + part.Size, "expires_at": expiresAt})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrUploadOffsetConflict
+		}
+		return tx.Create(part).Error
+	})
+}
+
+func (r *mediaUploadRepository) Parts(uploadID string) ([]v2.V2MediaUploadPart, error) {
+	var parts []v2.V2MediaUploadPart
+	err := r.db.Where("upload_id = ?", uploadID).Order("part_offset").Find(&parts).Error
+	return parts, err
+}
+
+func (r *mediaUploadRepository) PendingUsage(mbID string, now time.Time) (int64, int64, error) {
+	var usage struct {
+		Count int64
+		Bytes int64
+	}
+	err := r.db.Model(&v2.V2MediaUpload{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(upload_length), 0) AS bytes").
+		Where("mb_id = ? AND status = ? AND expires_at > ?", mbID, v2.MediaUploadPending, now).
+		Scan(&usage).Error
+	return usage.Count, usage.Bytes, err
+}
+
+func (r *mediaUploadRepository) Complete(id, fileKey, url, contentType string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&v2.V2MediaUpload{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":       v2.MediaUploadCompleted,
+			"file_key":     fileKey,
+			"url":          url,
+			"content_type": contentType,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("upload_id = ?", id).Delete(&v2.V2MediaUploadPart{}).Error
+	})
+}
+
+func (r *mediaUploadRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("upload_id = ?", id).Delete(&v2.V2MediaUploadPart{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&v2.V2MediaUpload{}).Error
+	})
+}
+
+func (r *mediaUploadRepository) Expired(now time.Time, limit int) ([]v2.V2MediaUpload, error) {
+	var uploads []v2.V2MediaUpload
+	err := r.db.Where("expires_at <= ?", now).Order("expires_at").Limit(limit).Find(&uploads).Error
+	return uploads, err
+}
@@ -0,0 +1,98 @@
+package v2
+
+import (
+	"errors"
+	"time"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// MFARepository v2 TOTP second factor and recovery code data access
+type MFARepository interface {
+	AutoMigrate() error
+	Find(userID uint64) (*v2.V2UserMFA, error)
+	Save(mfa *v2.V2UserMFA) error
+	// Delete removes the factor and its recovery codes
+	Delete(userID uint64) error
+	// ClaimStep records step as used; false 면 이미 같은(또는 더 뒤) step 을 받았다
+	ClaimStep(userID uint64, step int64) (bool, error)
+	ReplaceRecoveryCodes(userID uint64, hashes []string) error
+	// UseRecoveryCode consumes an unused code; false 면 없는 코드거나 이미 썼다
+	UseRecoveryCode(userID uint64, hash string, at time.Time) (bool, error)
+	CountRecoveryCodes(userID uint64) (int64, error)
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+// NewMFARepository creates a new v2 MFARepository
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+// AutoMigrate creates v2_user_mfa and v2_mfa_recovery_codes.
+// ⛔ prod 는 수동 DDL 선행 원칙 — migrations/010_v2_user_mfa.sql 참고.
+func (r *mfaRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&v2.V2UserMFA{}, &v2.V2MFARecoveryCode{})
+}
+
+func (r *mfaRepository) Find(userID uint64) (*v2.V2UserMFA, error) {
+	var mfa v2.V2UserMFA
+	err := r.db.Where("user_id = ?", userID).First(&mfa).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+func (r *mfaRepository) Save(mfa *v2.V2UserMFA) error {
+	return r.db.Save(mfa).Error
+}
+
+func (r *mfaRepository) Delete(userID uint64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&v2.V2MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&v2.V2UserMFA{}).Error
+	})
+}
+
+func (r *mfaRepository) ClaimStep(userID uint64, step int64) (bool, error) {
+	result := r.db.Model(&v2.V2UserMFA{}).
+		Where("user_id = ? AND last_step < ?", userID, step).
+		Update("last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(userID uint64, hashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&v2.V2MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]v2.V2MFARecoveryCode, 0, len(hashes))
+		for _, h := range hashes {
+			codes = append(codes, v2.V2MFARecoveryCode{UserID: userID, CodeHash: h})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *mfaRepository) UseRecoveryCode(userID uint64, hash string, at time.Time) (bool, error) {
+	result := r.db.Model(&v2.V2MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *mfaRepository) CountRecoveryCodes(userID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&v2.V2MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,101 @@
+package v2
+
+import (
+	"errors"
+	"time"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// SessionRepository v2 auth session (refresh token family) data access
+type SessionRepository interface {
+	AutoMigrate() error
+	Create(session *v2.V2AuthSession) error
+	FindByFamily(familyID string) (*v2.V2AuthSession, error)
+	// Rotate swaps the current token id only if it is still fromTokenID (동시 갱신·재사용은 false)
+	Rotate(familyID, fromTokenID, toTokenID, ip string, seenAt, expiresAt time.Time) (bool, error)
+	ListActiveByUser(userID uint64, now time.Time) ([]v2.V2AuthSession, error)
+	// Revoke marks sessions revoked and returns the family ids it changed
+	Revoke(where *gorm.DB, reason string, at time.Time) ([]string, error)
+	Scope() *gorm.DB
+	MarkMFA(familyID string, at time.Time) (bool, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new v2 SessionRepository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// AutoMigrate creates the v2_auth_sessions table.
+// ⛔ prod 는 수동 DDL 선행 원칙 — migrations/009_v2_auth_sessions.sql 참고.
+func (r *sessionRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&v2.V2AuthSession{})
+}
+
+func (r *sessionRepository) Create(session *v2.V2AuthSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *sessionRepository) FindByFamily(familyID string) (*v2.V2AuthSession, error) {
+	var session v2.V2AuthSession
+	err := r.db.Where("family_id = ?", familyID).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) Rotate(familyID, fromTokenID, toTokenID, ip string, seenAt, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&v2.V2AuthSession{}).
+		Where("family_id = ? AND token_id = ? AND revoked_at IS NULL", familyID, fromTokenID).
+		Updates(map[string]interface{}{
+			"token_id":     toTokenID,
+			"generation":   gorm.Expr("generation + 1"),
+			"ip":           ip,
+			"last_seen_at": seenAt,
+			"expires_at":   expiresAt,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *sessionRepository) ListActiveByUser(userID uint64, now time.Time) ([]v2.V2AuthSession, error) {
+	var sessions []v2.V2AuthSession
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// Scope returns a query on v2_auth_sessions to narrow before Revoke
+func (r *sessionRepository) Scope() *gorm.DB {
+	return r.db.Model(&v2.V2AuthSession{})
+}
+
+func (r *sessionRepository) Revoke(where *gorm.DB, reason string, at time.Time) ([]string, error) {
+	var families []string
+	if err := where.Session(&gorm.Session{}).Where("revoked_at IS NULL").Pluck("family_id", &families).Error; err != nil {
+		return nil, err
+	}
+	if len(families) == 0 {
+		return nil, nil
+	}
+	err := r.db.Model(&v2.V2AuthSession{}).
+		Where("family_id IN ? AND revoked_at IS NULL", families).
+		Updates(map[string]interface{}{"revoked_at": at, "revoke_reason": reason}).Error
+	return families, err
+}
+
+// MarkMFA records a completed second factor on an active session (step-up)
+func (r *sessionRepository) MarkMFA(familyID string, at time.Time) (bool, error) {
+	result := r.db.Model(&v2.V2AuthSession{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("mfa_verified_at", at)
+	return result.RowsAffected == 1, result.Error
+}
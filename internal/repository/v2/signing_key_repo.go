@@ -0,0 +1,58 @@
+package v2
+
+import (
+	"time"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// SigningKeyRepository v2 JWT signing key data access
+type SigningKeyRepository interface {
+	AutoMigrate() error
+	// List returns keys that are not retired at now, oldest activation first
+	List(now time.Time) ([]v2.V2JWTSigningKey, error)
+	Create(key *v2.V2JWTSigningKey) error
+	// Retire sets retires_at once; false 면 없거나 이미 은퇴 시각이 정해진 키다
+	Retire(kid string, at time.Time) (bool, error)
+	// DeleteRetired removes keys whose retires_at has passed
+	DeleteRetired(now time.Time) (int64, error)
+}
+
+type signingKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewSigningKeyRepository creates a new v2 SigningKeyRepository
+func NewSigningKeyRepository(db *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepository{db: db}
+}
+
+// AutoMigrate creates v2_jwt_signing_keys.
+// ⛔ prod 는 수동 DDL 선행 원칙 — migrations/013_v2_jwt_signing_keys.sql 참고.
+func (r *signingKeyRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&v2.V2JWTSigningKey{})
+}
+
+func (r *signingKeyRepository) List(now time.Time) ([]v2.V2JWTSigningKey, error) {
+	var keys []v2.V2JWTSigningKey
+	err := r.db.Where("retires_at IS NULL OR retires_at > ?", now).
+		Order("activates_at ASC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *signingKeyRepository) Create(key *v2.V2JWTSigningKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *signingKeyRepository) Retire(kid string, at time.Time) (bool, error) {
+	result := r.db.Model(&v2.V2JWTSigningKey{}).
+		Where("kid = ? AND retires_at IS NULL", kid).
+		Update("retires_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *signingKeyRepository) DeleteRetired(now time.Time) (int64, error) {
+	result := r.db.Where("retires_at IS NOT NULL AND retires_at <= ?", now).Delete(&v2.V2JWTSigningKey{})
+	return result.RowsAffected, result.Error
+}
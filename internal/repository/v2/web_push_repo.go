@@ -0,0 +1,73 @@
+package v2
+
+import (
+	"errors"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// WebPushRepository v2 browser Web Push subscription data access
+type WebPushRepository interface {
+	Upsert(sub *v2.V2WebPushSubscription) error
+	DeleteByUserAndEndpoint(userID uint64, endpoint string) error
+	// DeleteByEndpoint 는 엔드포인트 단독으로 삭제한다 — 로그아웃된 브라우저의 구독 해제와
+	// 푸시 서비스가 404/410 을 준 구독 정리용. 엔드포인트는 비추측성 URL 이라 보유가 소유 증명이다.
+	DeleteByEndpoint(endpoint string) error
+	ListByUser(userID uint64) ([]v2.V2WebPushSubscription, error)
+	ListByUsers(userIDs []uint64) ([]v2.V2WebPushSubscription, error)
+}
+
+type webPushRepository struct {
+	db *gorm.DB
+}
+
+// NewWebPushRepository creates a new v2 WebPushRepository
+func NewWebPushRepository(db *gorm.DB) WebPushRepository {
+	return &webPushRepository{db: db}
+}
+
+// Upsert inserts a subscription or updates the row that owns the endpoint.
+// 같은 브라우저에서 다른 회원으로 로그인하면 구독이 새 회원에게 넘어간다 (deviceRepository.Upsert 와 같다).
+func (r *webPushRepository) Upsert(sub *v2.V2WebPushSubscription) error {
+	var existing v2.V2WebPushSubscription
+	result := r.db.Where("endpoint = ?", sub.Endpoint).First(&existing)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return r.db.Create(sub).Error
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	existing.UserID = sub.UserID
+	existing.P256dh = sub.P256dh
+	existing.Auth = sub.Auth
+	existing.UserAgent = sub.UserAgent
+	if err := r.db.Save(&existing).Error; err != nil {
+		return err
+	}
+	*sub = existing
+	return nil
+}
+
+func (r *webPushRepository) DeleteByUserAndEndpoint(userID uint64, endpoint string) error {
+	return r.db.Where("user_id = ? AND endpoint = ?", userID, endpoint).Delete(&v2.V2WebPushSubscription{}).Error
+}
+
+func (r *webPushRepository) DeleteByEndpoint(endpoint string) error {
+	return r.db.Where("endpoint = ?", endpoint).Delete(&v2.V2WebPushSubscription{}).Error
+}
+
+func (r *webPushRepository) ListByUser(userID uint64) ([]v2.V2WebPushSubscription, error) {
+	var subs []v2.V2WebPushSubscription
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&subs).Error
+	return subs, err
+}
+
+func (r *webPushRepository) ListByUsers(userIDs []uint64) ([]v2.V2WebPushSubscription, error) {
+	var subs []v2.V2WebPushSubscription
+	if len(userIDs) == 0 {
+		return subs, nil
+	}
+	err := r.db.Where("user_id IN ?", userIDs).Find(&subs).Error
+	return subs, err
+}
@@ -0,0 +1,98 @@
+package v2
+
+import (
+	"errors"
+	"time"
+
+	v2 "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+// WebAuthnRepository v2 passkey (WebAuthn credential) data access
+type WebAuthnRepository interface {
+	AutoMigrate() error
+	ListByUser(userID uint64) ([]v2.V2WebAuthnCredential, error)
+	FindByCredentialID(credentialID string) (*v2.V2WebAuthnCredential, error)
+	CountByUser(userID uint64) (int64, error)
+	Create(cred *v2.V2WebAuthnCredential) error
+	// UpdateUse records an assertion only if the stored counter is still fromCount (동시 로그인 경합은 false)
+	UpdateUse(id uint64, fromCount, toCount uint32, backupState bool, at time.Time) (bool, error)
+	// Rename / Delete 는 회원 본인 자격증명만 건드린다; false 면 없는 id 다
+	Rename(userID, id uint64, name string) (bool, error)
+	Delete(userID, id uint64) (bool, error)
+}
+
+type webAuthnRepository struct {
+	db *gorm.DB
+}
+
+// NewWebAuthnRepository creates a new v2 WebAuthnRepository
+func NewWebAuthnRepository(db *gorm.DB) WebAuthnRepository {
+	return &webAuthnRepository{db: db}
+}
+
+// AutoMigrate creates the v2_webauthn_credentials table.
+// ⛔ prod 는 수동 DDL 선행 원칙 — migrations/011_v2_webauthn_credentials.sql 참고.
+func (r *webAuthnRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&v2.V2WebAuthnCredential{})
+}
+
+func (r *webAuthnRepository) ListByUser(userID uint64) ([]v2.V2WebAuthnCredential, error) {
+	var creds []v2.V2WebAuthnCredential
+	err := r.db.Where("user_id = ?", userID).Order("id").Find(&creds).Error
+	return creds, err
+}
+
+func (r *webAuthnRepository) FindByCredentialID(credentialID string) (*v2.V2WebAuthnCredential, error) {
+	var cred v2.V2WebAuthnCredential
+	err := r.db.Where("credential_id = ?", credentialID).First(&cred).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+func (r *webAuthnRepository) CountByUser(userID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&v2.V2WebAuthnCredential{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *webAuthnRepository) Create(cred *v2.V2WebAuthnCredential) error {
+	return r.db.Create(cred).Error
+}
+
+func (r *webAuthnRepository) UpdateUse(id uint64, fromCount, toCount uint32, backupState bool, at time.Time) (bool, error) {
+	result := r.db.Model(&v2.V2WebAuthnCredential{}).
+		Where("id = ? AND sign_count = ?", id, fromCount).
+		Updates(map[string]interface{}{
+			"sign_count":   toCount,
+			"backup_state": backupState,
+			"last_used_at": at,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *webAuthnRepository) Rename(userID, id uint64, name string) (bool, error) {
+	result := r.db.Model(&v2.V2WebAuthnCredential{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("name", name)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		// 같은 이름으로 바꾸면 MySQL 은 0 행을 돌려준다 — 존재 여부로 다시 본다
+		var count int64
+		err := r.db.Model(&v2.V2WebAuthnCredential{}).Where("id = ? AND user_id = ?", id, userID).Count(&count).Error
+		return count > 0, err
+	}
+	return true, nil
+}
+
+func (r *webAuthnRepository) Delete(userID, id uint64) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&v2.V2WebAuthnCredential{})
+	return result.RowsAffected == 1, result.Error
+}
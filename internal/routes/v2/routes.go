@@ -185,6 +185,55 @@ func SetupScrap(router *gin.Engine, h *v2handler.ScrapHandler, jwtManager *jwt.M
 	me.GET("/scraps", h.ListScraps)
 }
 
+// SetupMFA configures TOTP two-factor routes; 코드를 받는 라우트에는 codeRateLimit 을 건다(무차별 대입 방지).
+func SetupMFA(router *gin.Engine, h *v2handler.MFAHandler, authHandler *v2handler.V2AuthHandler, jwtManager *jwt.Manager, codeRateLimit ...gin.HandlerFunc) {
+	withLimit := func(fn gin.HandlerFunc) []gin.HandlerFunc {
+		return append(append([]gin.HandlerFunc{}, codeRateLimit...), fn)
+	}
+	mfa := router.Group("/api/v2/auth/mfa")
+	// 로그인 2단계 — 비밀번호 뒤 받은 mfa_ticket + 코드 (아직 토큰이 없다)
+	mfa.POST("/verify", withLimit(authHandler.VerifyMFA)...)
+
+	mfa.Use(middleware.JWTAuth(jwtManager))
+	mfa.GET("", h.Status)
+	mfa.POST("/enroll", h.Enroll)
+	mfa.POST("/enroll/confirm", withLimit(h.ConfirmEnroll)...)
+	mfa.POST("/disable", withLimit(h.Disable)...)
+	mfa.POST("/recovery-codes", withLimit(h.RegenerateRecoveryCodes)...)
+	mfa.POST("/challenge", withLimit(h.Challenge)...)
+}
+
+// SetupWebAuthn configures passkey routes; 로그인 라우트에는 loginRateLimit 을 건다.
+func SetupWebAuthn(router *gin.Engine, h *v2handler.WebAuthnHandler, jwtManager *jwt.Manager, loginRateLimit ...gin.HandlerFunc) {
+	withLimit := func(fn gin.HandlerFunc) []gin.HandlerFunc {
+		return append(append([]gin.HandlerFunc{}, loginRateLimit...), fn)
+	}
+	wa := router.Group("/api/v2/auth/webauthn")
+	// 패스키 로그인 — 아직 토큰이 없다
+	wa.POST("/login/begin", withLimit(h.BeginLogin)...)
+	wa.POST("/login/finish", withLimit(h.FinishLogin)...)
+
+	wa.Use(middleware.JWTAuth(jwtManager))
+	wa.POST("/register/begin", h.BeginRegistration)
+	wa.POST("/register/finish", h.FinishRegistration)
+	wa.GET("/credentials", h.List)
+	wa.PATCH("/credentials/:id", h.Rename)
+	wa.DELETE("/credentials/:id", h.Delete)
+}
+
+// SetupSessions configures the member's login session (device) routes
+func SetupSessions(router *gin.Engine, h *v2handler.SessionHandler, jwtManager *jwt.Manager) {
+	me := router.Group("/api/v2/me", middleware.JWTAuth(jwtManager))
+	me.GET("/sessions", h.List)
+	me.DELETE("/sessions", h.RevokeAll)
+	me.DELETE("/sessions/:id", h.Revoke)
+}
+
+// SetupJWKS configures the public JWT key set route
+func SetupJWKS(router *gin.Engine, h *v2handler.JWKSHandler) {
+	router.GET("/.well-known/jwks.json", h.Get)
+}
+
 // SetupMemo configures v2 memo routes
 func SetupMemo(router *gin.Engine, h *v2handler.MemoHandler, jwtManager *jwt.Manager, gnuDB *gorm.DB) {
 	auth := middleware.JWTAuth(jwtManager)
@@ -230,6 +279,18 @@ func SetupDevices(router *gin.Engine, h *v2handler.DeviceHandler, jwtManager *jw
 	devices.DELETE("/:token", h.UnregisterDevice)
 }
 
+// SetupWebPush configures v2 browser Web Push subscription routes
+func SetupWebPush(router *gin.Engine, h *v2handler.WebPushHandler, jwtManager *jwt.Manager) {
+	auth := middleware.JWTAuth(jwtManager)
+
+	web := router.Group("/api/v2/push/web")
+	web.GET("/public-key", h.GetPublicKey)
+	// 로그아웃된 브라우저의 구독 해제 — 엔드포인트 단독 인증(기기 by-token 해제와 같다)
+	web.POST("/unsubscribe", h.Unsubscribe)
+	web.POST("/subscriptions", auth, h.Subscribe)
+	web.GET("/subscriptions", auth, h.ListSubscriptions)
+}
+
 // SetupMessage configures v2 message routes
 func SetupMessage(router *gin.Engine, h *v2handler.MessageHandler, jwtManager *jwt.Manager, gnuDB ...*gorm.DB) {
 	auth := middleware.JWTAuth(jwtManager)
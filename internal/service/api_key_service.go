@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/repository"
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// API 키 제한
+const (
+	apiKeyMaxPerMember     = 10
+	apiKeyDefaultRateLimit = 60
+	apiKeyMaxRateLimit     = 600 // 전역 IP 한도(DefaultRateLimitConfig)와 같다
+	apiKeyPrefixLen        = 11  // "ak_" + 8 hex — 목록에서 키를 알아보는 용도
+)
+
+// API key errors
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrAPIKeyLimit    = errors.New("API key limit reached")
+	ErrInvalidAPIKey  = errors.New("invalid API key request")
+)
+
+// APIKeyInput is the body of POST /api/v2/auth/api-keys
+type APIKeyInput struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes"`
+	RateLimit     int      `json:"rate_limit"`      // 분당 요청 수, 0 이면 기본값
+	ExpiresInDays int      `json:"expires_in_days"` // 0 이면 만료 없음
+}
+
+// APIKeyService issues, rotates, revokes and authenticates API keys
+type APIKeyService struct {
+	repo *repository.APIKeyRepository
+	db   *gorm.DB
+}
+
+// NewAPIKeyService creates a new APIKeyService
+func NewAPIKeyService(repo *repository.APIKeyRepository, db *gorm.DB) *APIKeyService {
+	return &APIKeyService{repo: repo, db: db}
+}
+
+// hashAPIKey returns the stored form of a raw key
+func hashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// newAPIKeySecret returns a raw key, its hash and its visible prefix
+func newAPIKeySecret() (raw, hash, prefix string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	raw = middleware.APIKeyPrefix + hex.EncodeToString(b)
+	return raw, hashAPIKey(raw), raw[:apiKeyPrefixLen], nil
+}
+
+// List returns the member's keys (평문 키는 없다)
+func (s *APIKeyService) List(ctx context.Context, mbID string) ([]domain.APIKey, error) {
+	return s.repo.ListByUser(ctx, mbID)
+}
+
+// Create issues a key for the member; the returned Key field is the only copy of the secret.
+// admin:* 는 관리자(레벨 10 이상)만 받을 수 있다. mfa 는 요청 세션이 2단계를 거쳤는지다(middleware.HasMFA).
+func (s *APIKeyService) Create(ctx context.Context, mbID string, level int, in APIKeyInput, mfa bool) (*domain.APIKey, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len([]rune(name)) > 100 {
+		return nil, fmt.Errorf("%w: name must be 1-100 characters", ErrInvalidAPIKey)
+	}
+	scopes := in.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{middleware.ScopePostsRead}
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(middleware.APIKeyScopes, scope) {
+			return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidAPIKey, scope)
+		}
+		if scope == middleware.ScopeAdmin && level < 10 {
+			return nil, fmt.Errorf("%w: %s requires an admin account", ErrInvalidAPIKey, scope)
+		}
+	}
+	rateLimit := in.RateLimit
+	if rateLimit == 0 {
+		rateLimit = apiKeyDefaultRateLimit
+	}
+	if rateLimit < 1 || rateLimit > apiKeyMaxRateLimit {
+		return nil, fmt.Errorf("%w: rate_limit must be 1-%d", ErrInvalidAPIKey, apiKeyMaxRateLimit)
+	}
+	if in.ExpiresInDays < 0 {
+		return nil, fmt.Errorf("%w: expires_in_days must not be negative", ErrInvalidAPIKey)
+	}
+
+	count, err := s.repo.CountActiveByUser(ctx, mbID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= apiKeyMaxPerMember {
+		return nil, fmt.Errorf("%w: at most %d active keys", ErrAPIKeyLimit, apiKeyMaxPerMember)
+	}
+
+	raw, hash, prefix, err := newAPIKeySecret()
+	if err != nil {
+		return nil, err
+	}
+	key := &domain.APIKey{
+		KeyHash:   hash,
+		Prefix:    prefix,
+		Name:      name,
+		UserID:    mbID,
+		Scopes:    strings.Join(scopes, ","),
+		RateLimit: rateLimit,
+		Active:    true,
+	}
+	if in.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, in.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	if mfa {
+		now := time.Now()
+		key.MFAVerifiedAt = &now
+	}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	key.Key = raw
+
+	pkglogger.GetLogger().Info().
+		Str("user_id", mbID).
+		Str("key_prefix", prefix).
+		Str("scopes", key.Scopes).
+		Msg("API key generated")
+	return key, nil
+}
+
+// Rotate replaces the secret of an active key; 이전 키는 즉시 쓸 수 없게 된다.
+// 새 비밀은 요청 세션의 2단계 여부(mfa)를 이어받는다 — 2단계 없이 교체하면 MFA 표시가 지워진다.
+func (s *APIKeyService) Rotate(ctx context.Context, mbID string, id int64, mfa bool) (*domain.APIKey, error) {
+	key, err := s.repo.FindByUser(ctx, mbID, id)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || !key.Active {
+		return nil, ErrAPIKeyNotFound
+	}
+	raw, hash, prefix, err := newAPIKeySecret()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	key.KeyHash, key.Prefix, key.RotatedAt = hash, prefix, &now
+	key.MFAVerifiedAt = nil
+	if mfa {
+		key.MFAVerifiedAt = &now
+	}
+	if err := s.repo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+	key.Key = raw
+
+	pkglogger.GetLogger().Info().
+		Str("user_id", mbID).
+		Int64("key_id", id).
+		Str("key_prefix", prefix).
+		Msg("API key rotated")
+	return key, nil
+}
+
+// Revoke disables a key for good (기록은 남긴다)
+func (s *APIKeyService) Revoke(ctx context.Context, mbID string, id int64) error {
+	key, err := s.repo.FindByUser(ctx, mbID, id)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return ErrAPIKeyNotFound
+	}
+	if !key.Active {
+		return nil
+	}
+	now := time.Now()
+	key.Active, key.RevokedAt = false, &now
+	if err := s.repo.Update(ctx, key); err != nil {
+		return err
+	}
+	pkglogger.GetLogger().Info().
+		Str("user_id", mbID).
+		Int64("key_id", id).
+		Msg("API key revoked")
+	return nil
+}
+
+// Authenticate resolves a raw key to its member (middleware.SetAPIKeyAuth 로 주입).
+// 회원 레벨·닉네임은 매번 g5_member 에서 읽는다 — 강등·탈퇴가 키에 바로 반영되도록.
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey, clientIP string) (*middleware.APIKeyPrincipal, error) {
+	if !strings.HasPrefix(rawKey, middleware.APIKeyPrefix) {
+		return nil, middleware.ErrAPIKeyInvalid
+	}
+	key, err := s.repo.FindActiveByHash(ctx, hashAPIKey(rawKey))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, middleware.ErrAPIKeyInvalid
+	}
+	now := time.Now()
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(now) {
+		return nil, middleware.ErrAPIKeyInvalid
+	}
+
+	var member struct {
+		MbID   string `gorm:"column:mb_id"`
+		MbNick string `gorm:"column:mb_nick"`
+		Level  int    `gorm:"column:mb_level"`
+	}
+	if err := s.db.WithContext(ctx).Table("g5_member").Select("mb_id, mb_nick, mb_level").
+		Where("mb_id = ?", key.UserID).Limit(1).Scan(&member).Error; err != nil {
+		return nil, err
+	}
+	if member.MbID == "" {
+		return nil, middleware.ErrAPIKeyInvalid // 회원이 없어진 키
+	}
+	userID := member.MbID
+	var v2ID uint64
+	if err := s.db.WithContext(ctx).Table("v2_users").Select("id").
+		Where("username = ?", member.MbID).Limit(1).Scan(&v2ID).Error; err == nil && v2ID > 0 {
+		userID = strconv.FormatUint(v2ID, 10)
+	}
+
+	if err := s.repo.RecordUse(ctx, key.ID, clientIP, now); err != nil {
+		// 사용량 기록 실패로 요청을 막지는 않는다
+		pkglogger.GetLogger().Warn().Err(err).Int64("key_id", key.ID).Msg("failed to record API key use")
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(key.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return &middleware.APIKeyPrincipal{
+		KeyID:     key.ID,
+		UserID:    userID,
+		Username:  member.MbID,
+		Nickname:  member.MbNick,
+		Level:     member.Level,
+		Scopes:    scopes,
+		RateLimit: key.RateLimit,
+		MFA:       key.MFAVerifiedAt != nil,
+	}, nil
+}
+
+// legacyAPIKeyScopes maps the pre-scope grants (read,write,admin) to resource scopes
+var legacyAPIKeyScopes = map[string][]string{
+	"read":  {middleware.ScopePostsRead, middleware.ScopeNotificationsRead},
+	"write": {middleware.ScopePostsWrite, middleware.ScopeNotificationsWrite},
+	"admin": {middleware.ScopeAdmin},
+}
+
+// MigrateLegacyKeys hashes keys issued before hashing and converts their scopes and owner.
+// migrations/008_api_keys_hashed.sql 과 같은 일을 한다 — 이미 옮긴 행은 건드리지 않는다.
+// 옮긴 키에는 MFA 표시가 없다: admin:* 키도 관리자 MFA 정책 아래서는 2단계 세션에서 교체해야 쓸 수 있다.
+func (s *APIKeyService) MigrateLegacyKeys(ctx context.Context) (int, error) {
+	keys, err := s.repo.ListLegacy(ctx)
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	for i := range keys {
+		key := &keys[i]
+		raw := *key.LegacyKey
+		key.KeyHash = hashAPIKey(raw)
+		key.Prefix = raw[:min(apiKeyPrefixLen, len(raw))]
+		key.LegacyKey = nil
+		key.MFAVerifiedAt = nil
+
+		var scopes []string
+		for _, scope := range strings.Split(key.Scopes, ",") {
+			scope = strings.TrimSpace(scope)
+			if mapped, ok := legacyAPIKeyScopes[scope]; ok {
+				scopes = append(scopes, mapped...)
+			} else if scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+		key.Scopes = strings.Join(scopes, ",")
+		if slices.Contains(scopes, middleware.ScopeAdmin) {
+			pkglogger.GetLogger().Warn().
+				Int64("key_id", key.ID).
+				Str("user_id", key.UserID).
+				Msg("legacy admin API key migrated without a second factor; rotate it from a 2FA session before enabling mfa.require_for_admins")
+		}
+
+		// 예전 발급 API 는 Bearer 경로에서 v2_users.id(숫자)를 소유자로 넣었다 — mb_id 로 통일
+		var count int64
+		s.db.WithContext(ctx).Table("g5_member").Where("mb_id = ?", key.UserID).Count(&count)
+		if count == 0 {
+			var username string
+			s.db.WithContext(ctx).Table("v2_users").Select("username").Where("id = ?", key.UserID).Limit(1).Scan(&username)
+			if username != "" {
+				key.UserID = username
+			}
+		}
+
+		if err := s.repo.Update(ctx, key); err != nil {
+			return migrated, fmt.Errorf("migrate API key %d: %w", key.ID, err)
+		}
+		migrated++
+	}
+	return migrated, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/middleware"
+	"github.com/damoang/angple-backend/internal/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAPIKeyTest(t *testing.T) (*APIKeyService, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	for _, sql := range []string{
+		`CREATE TABLE g5_member (mb_id TEXT PRIMARY KEY, mb_nick TEXT, mb_level INTEGER)`,
+		`CREATE TABLE v2_users (id INTEGER PRIMARY KEY, username TEXT)`,
+		`INSERT INTO g5_member VALUES ('dealbot', '딜봇', 2), ('admin', '관리자', 10)`,
+		`INSERT INTO v2_users VALUES (42, 'dealbot')`,
+	} {
+		if err := db.Exec(sql).Error; err != nil {
+			t.Fatalf("exec %q: %v", sql, err)
+		}
+	}
+	repo := repository.NewAPIKeyRepository(db)
+	if err := repo.AutoMigrate(); err != nil {
+		t.Fatalf("migrate api_keys: %v", err)
+	}
+	return NewAPIKeyService(repo, db), db
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	svc, db := setupAPIKeyTest(t)
+	ctx := context.Background()
+
+	key, err := svc.Create(ctx, "dealbot", 2, APIKeyInput{Name: "딜 알림", Scopes: []string{middleware.ScopePostsRead}}, false)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.HasPrefix(key.Key, "ak_") || !strings.HasPrefix(key.Key, key.Prefix) || key.RateLimit != apiKeyDefaultRateLimit {
+		t.Fatalf("unexpected key: %+v", key)
+	}
+	var stored domain.APIKey
+	db.First(&stored, key.ID)
+	if stored.KeyHash == "" || strings.Contains(stored.KeyHash, key.Key[3:]) || stored.LegacyKey != nil {
+		t.Fatalf("key must be stored hashed: %+v", stored)
+	}
+
+	principal, err := svc.Authenticate(ctx, key.Key, "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if principal.UserID != "42" || principal.Username != "dealbot" || principal.Level != 2 || principal.Scopes[0] != middleware.ScopePostsRead {
+		t.Errorf("unexpected principal: %+v", principal)
+	}
+	db.First(&stored, key.ID)
+	if stored.UsageCount != 1 || stored.LastUsedIP != "10.0.0.1" || stored.LastUsed == nil {
+		t.Errorf("usage not recorded: %+v", stored)
+	}
+
+	rotated, err := svc.Rotate(ctx, "dealbot", key.ID, false)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, key.Key, ""); !errors.Is(err, middleware.ErrAPIKeyInvalid) {
+		t.Errorf("old secret after rotate: err = %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, rotated.Key, ""); err != nil {
+		t.Errorf("new secret after rotate: %v", err)
+	}
+
+	if err := svc.Revoke(ctx, "admin", key.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("revoke by another member: err = %v", err)
+	}
+	if err := svc.Revoke(ctx, "dealbot", key.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, rotated.Key, ""); !errors.Is(err, middleware.ErrAPIKeyInvalid) {
+		t.Errorf("revoked key: err = %v", err)
+	}
+}
+
+func TestAPIKeyCreateValidation(t *testing.T) {
+	svc, _ := setupAPIKeyTest(t)
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, "dealbot", 2, APIKeyInput{Name: "x", Scopes: []string{middleware.ScopeAdmin}}, false); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("admin scope for member: err = %v", err)
+	}
+	if _, err := svc.Create(ctx, "admin", 10, APIKeyInput{Name: "x", Scopes: []string{middleware.ScopeAdmin}}, false); err != nil {
+		t.Errorf("admin scope for admin: %v", err)
+	}
+	if _, err := svc.Create(ctx, "dealbot", 2, APIKeyInput{Name: "x", Scopes: []string{"read"}}, false); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("unknown scope: err = %v", err)
+	}
+	if _, err := svc.Create(ctx, "dealbot", 2, APIKeyInput{Name: "x", RateLimit: apiKeyMaxRateLimit + 1}, false); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("rate limit: err = %v", err)
+	}
+}
+
+func TestAPIKeyMFAMark(t *testing.T) {
+	svc, _ := setupAPIKeyTest(t)
+	ctx := context.Background()
+
+	key, err := svc.Create(ctx, "admin", 10, APIKeyInput{Name: "운영 봇", Scopes: []string{middleware.ScopeAdmin}}, true)
+	if err != nil || key.MFAVerifiedAt == nil {
+		t.Fatalf("Create after second factor: %+v, %v", key, err)
+	}
+	if principal, err := svc.Authenticate(ctx, key.Key, ""); err != nil || !principal.MFA {
+		t.Fatalf("key issued after second factor should carry mfa: %+v, %v", principal, err)
+	}
+
+	// 2단계 없이 교체하면 표시가 지워진다
+	rotated, err := svc.Rotate(ctx, "admin", key.ID, false)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if principal, err := svc.Authenticate(ctx, rotated.Key, ""); err != nil || principal.MFA {
+		t.Fatalf("key rotated without second factor should not carry mfa: %+v, %v", principal, err)
+	}
+	rotated, err = svc.Rotate(ctx, "admin", key.ID, true)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if principal, err := svc.Authenticate(ctx, rotated.Key, ""); err != nil || !principal.MFA {
+		t.Fatalf("key rotated after second factor should carry mfa: %+v, %v", principal, err)
+	}
+}
+
+func TestMigrateLegacyAPIKeys(t *testing.T) {
+	svc, db := setupAPIKeyTest(t)
+	ctx := context.Background()
+
+	legacy := "ak_0123456789abcdef"
+	if err := db.Exec(`INSERT INTO api_keys (api_key, name, user_id, scopes, active, rate_limit, usage_count) VALUES (?, 'old', '42', 'read,write', 1, 60, 0)`, legacy).Error; err != nil {
+		t.Fatalf("insert legacy key: %v", err)
+	}
+	n, err := svc.MigrateLegacyKeys(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("MigrateLegacyKeys = %d, %v", n, err)
+	}
+	principal, err := svc.Authenticate(ctx, legacy, "")
+	if err != nil {
+		t.Fatalf("Authenticate legacy key: %v", err)
+	}
+	if principal.Username != "dealbot" || strings.Join(principal.Scopes, ",") != "posts:read,notifications:read,posts:write,notifications:write" {
+		t.Errorf("unexpected migrated principal: %+v", principal)
+	}
+	if principal.MFA {
+		t.Error("migrated key must not carry mfa")
+	}
+
+	// 예전 admin 키는 admin:* 가 되지만 2단계 표시 없이 옮겨진다 — 정책 아래서는 교체 전까지 막힌다
+	legacyAdmin := "ak_fedcba9876543210"
+	if err := db.Exec(`INSERT INTO api_keys (api_key, name, user_id, scopes, active, rate_limit, usage_count) VALUES (?, 'ops', 'admin', 'admin', 1, 60, 0)`, legacyAdmin).Error; err != nil {
+		t.Fatalf("insert legacy admin key: %v", err)
+	}
+	if n, err := svc.MigrateLegacyKeys(ctx); err != nil || n != 1 {
+		t.Fatalf("MigrateLegacyKeys(admin) = %d, %v", n, err)
+	}
+	principal, err = svc.Authenticate(ctx, legacyAdmin, "")
+	if err != nil || strings.Join(principal.Scopes, ",") != middleware.ScopeAdmin || principal.MFA {
+		t.Fatalf("migrated admin key should be admin:* without mfa: %+v, %v", principal, err)
+	}
+	if n, _ := svc.MigrateLegacyKeys(ctx); n != 0 {
+		t.Errorf("second migration touched %d keys", n)
+	}
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"github.com/damoang/angple-backend/pkg/i18n"
+	"github.com/damoang/angple-backend/pkg/mail"
+	"gorm.io/gorm"
+)
+
+// 이메일 발송 제한
+const (
+	emailInstantBatch = 200
+	emailDigestBatch  = 5000 // 요약 한 번에 읽는 대기 행
+	emailDigestItems  = 30   // 요약 메일 한 통에 싣는 알림 (나머지는 "사이트에서 보기")
+	emailMaxAttempts  = 5
+	emailRetryBase    = 5 * time.Minute
+)
+
+// ErrInvalidUnsubscribeToken is returned for tampered or malformed unsubscribe tokens
+var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+//go:embed email_templates/notification.html email_templates/notification.txt
+var emailTemplateFS embed.FS
+
+var (
+	emailHTMLTemplate = htmltemplate.Must(htmltemplate.ParseFS(emailTemplateFS, "email_templates/notification.html"))
+	emailTextTemplate = texttemplate.Must(texttemplate.ParseFS(emailTemplateFS, "email_templates/notification.txt"))
+)
+
+// EmailNotificationConfig configures EmailNotificationService
+type EmailNotificationConfig struct {
+	From    string // MAIL_FROM (예: 다모앙 <noreply@damoang.net>)
+	BaseURL string // 메일 속 링크의 사이트 주소 (WEB_BASE_URL)
+	// UnsubscribeURL 은 RFC 8058 one-click POST 를 받는 API 주소다 (기본 BaseURL + /api/v2/email/unsubscribe).
+	// 본문의 수신 거부 링크는 사이트의 확인 페이지(BaseURL + /email/unsubscribe)로 간다 — 메일 보안 스캐너가
+	// GET 을 미리 열어 보므로 GET 으로는 끄지 않는다.
+	UnsubscribeURL string
+	Secret         []byte // 수신 거부 토큰 서명 키
+}
+
+// EmailRunResult summarizes one email-outbox / email-digest run
+type EmailRunResult struct {
+	Queued     int `json:"queued"`     // 꺼낸 대기 행
+	Sent       int `json:"sent"`       // 보낸 메일 수
+	Skipped    int `json:"skipped"`    // 주소 없음·수신 거부·설정 꺼짐
+	Suppressed int `json:"suppressed"` // 이번에 수신 거부 목록에 오른 주소
+	Failed     int `json:"failed"`     // 일시 실패 (다시 시도한다)
+}
+
+// EmailNotificationService renders queued email notifications (gnurepo.EnqueueEmail) and sends them.
+// 즉시 분류는 email-outbox cron 이 한 건씩, 일간·주간 분류는 email-digest-* cron 이 회원별 한 통으로 묶어 보낸다.
+type EmailNotificationService struct {
+	db        *gorm.DB
+	repo      gnurepo.NotiEmailRepository
+	transport mail.Transport
+	bundle    *i18n.Bundle
+	cfg       EmailNotificationConfig
+}
+
+// NewEmailNotificationService creates a new EmailNotificationService
+func NewEmailNotificationService(db *gorm.DB, repo gnurepo.NotiEmailRepository, transport mail.Transport, bundle *i18n.Bundle, cfg EmailNotificationConfig) *EmailNotificationService {
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.UnsubscribeURL == "" {
+		cfg.UnsubscribeURL = cfg.BaseURL + "/api/v2/email/unsubscribe"
+	}
+	return &EmailNotificationService{db: db, repo: repo, transport: transport, bundle: bundle, cfg: cfg}
+}
+
+// RunInstant sends pending instant notifications, one email each
+func (s *EmailNotificationService) RunInstant(ctx context.Context) (*EmailRunResult, error) {
+	now := time.Now()
+	rows, err := s.repo.Due(gnurepo.EmailFrequencyInstant, now, emailInstantBatch)
+	if err != nil {
+		return nil, err
+	}
+	res := &EmailRunResult{Queued: len(rows)}
+	for i := range rows {
+		if ctx.Err() != nil {
+			break
+		}
+		s.deliver(ctx, rows[i].MbID, gnurepo.EmailFrequencyInstant, rows[i:i+1], now, res)
+	}
+	return res, ctx.Err()
+}
+
+// RunDigest sends one summary email per member for the pending rows of frequency (daily·weekly)
+func (s *EmailNotificationService) RunDigest(ctx context.Context, frequency string) (*EmailRunResult, error) {
+	if frequency != gnurepo.EmailFrequencyDaily && frequency != gnurepo.EmailFrequencyWeekly {
+		return nil, fmt.Errorf("unknown digest frequency %q", frequency)
+	}
+	now := time.Now()
+	rows, err := s.repo.Due(frequency, now, emailDigestBatch)
+	if err != nil {
+		return nil, err
+	}
+	res := &EmailRunResult{Queued: len(rows)}
+	byMember := make(map[string][]gnurepo.EmailOutbox)
+	var order []string
+	for _, row := range rows {
+		if _, ok := byMember[row.MbID]; !ok {
+			order = append(order, row.MbID)
+		}
+		byMember[row.MbID] = append(byMember[row.MbID], row)
+	}
+	for _, mbID := range order {
+		if ctx.Err() != nil {
+			break
+		}
+		s.deliver(ctx, mbID, frequency, byMember[mbID], now, res)
+	}
+	return res, ctx.Err()
+}
+
+// deliver sends rows (한 회원의 것) as one email and records the outcome on every row
+func (s *EmailNotificationService) deliver(ctx context.Context, mbID, frequency string, rows []gnurepo.EmailOutbox, now time.Time, res *EmailRunResult) {
+	ids := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	skip := func(reason string) {
+		if err := s.repo.MarkSkipped(ids, reason); err != nil {
+			log.Printf("[EmailNoti] mark skipped failed for %s: %v", mbID, err)
+		}
+		res.Skipped += len(ids)
+	}
+
+	var member struct {
+		MbEmail string `gorm:"column:mb_email"`
+	}
+	if err := s.db.Table("g5_member").Select("mb_email").Where("mb_id = ?", mbID).Take(&member).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("[EmailNoti] member lookup failed for %s: %v", mbID, err)
+		res.Failed += len(rows)
+		return
+	}
+	email := strings.TrimSpace(member.MbEmail)
+	if email == "" {
+		skip("no email address")
+		return
+	}
+	if suppressed, err := s.repo.IsSuppressed(email); err != nil || suppressed {
+		if err != nil {
+			log.Printf("[EmailNoti] suppression lookup failed for %s: %v", mbID, err)
+			res.Failed += len(rows)
+			return
+		}
+		skip("suppressed")
+		return
+	}
+
+	// 쌓인 뒤에 분류를 껐으면 보내지 않는다
+	prefs, err := s.repo.Preferences(mbID)
+	if err != nil {
+		log.Printf("[EmailNoti] preference lookup failed for %s: %v", mbID, err)
+		res.Failed += len(rows)
+		return
+	}
+	var send []gnurepo.EmailOutbox
+	var off []int64
+	for _, row := range rows {
+		if prefs[row.Category] == gnurepo.EmailFrequencyOff {
+			off = append(off, row.ID)
+			continue
+		}
+		send = append(send, row)
+	}
+	if len(off) > 0 {
+		if err := s.repo.MarkSkipped(off, "preference off"); err != nil {
+			log.Printf("[EmailNoti] mark skipped failed for %s: %v", mbID, err)
+		}
+		res.Skipped += len(off)
+	}
+	if len(send) == 0 {
+		return
+	}
+	ids = ids[:0]
+	for _, row := range send {
+		ids = append(ids, row.ID)
+	}
+
+	msg, err := s.render(mbID, email, frequency, send)
+	if err != nil {
+		skip(err.Error())
+		return
+	}
+	err = s.transport.Send(ctx, msg)
+	switch {
+	case err == nil:
+		if err := s.repo.MarkSent(ids, now); err != nil {
+			log.Printf("[EmailNoti] mark sent failed for %s: %v", mbID, err)
+		}
+		res.Sent++
+	case mail.IsPermanent(err):
+		// 없는 주소·수신 거부 — 다시 보내면 평판만 깎인다
+		if serr := s.repo.Suppress(email, gnurepo.EmailSuppressionBounce, err.Error()); serr != nil {
+			log.Printf("[EmailNoti] suppress failed for %s: %v", mbID, serr)
+		}
+		if merr := s.repo.MarkRetry(ids, err.Error(), now, true); merr != nil {
+			log.Printf("[EmailNoti] mark failed for %s: %v", mbID, merr)
+		}
+		res.Suppressed++
+	default:
+		attempts := send[0].Attempts + 1
+		retryAt := now.Add(emailRetryBase << min(attempts-1, 6))
+		if merr := s.repo.MarkRetry(ids, err.Error(), retryAt, attempts >= emailMaxAttempts); merr != nil {
+			log.Printf("[EmailNoti] mark retry failed for %s: %v", mbID, merr)
+		}
+		log.Printf("[EmailNoti] send failed for %s (attempt %d): %v", mbID, attempts, err)
+		res.Failed += len(send)
+	}
+}
+
+type emailItem struct {
+	Subject string
+	Body    string
+	URL     string
+}
+
+type emailView struct {
+	Title            string
+	Intro            string
+	Digest           bool
+	Items            []emailItem
+	OpenLabel        string
+	Reason           string
+	SettingsLabel    string
+	SettingsURL      string
+	UnsubscribeLabel string
+	UnsubscribeURL   string
+}
+
+// render builds the email for rows. 회원별 언어 설정이 없어 기본 언어(ko)로 쓴다.
+func (s *EmailNotificationService) render(mbID, email, frequency string, rows []gnurepo.EmailOutbox) (*mail.Message, error) {
+	locale := i18n.LocaleKo
+	t := func(key string, args ...interface{}) string { return s.bundle.T(locale, key, args...) }
+
+	var categories []string
+	items := make([]emailItem, 0, min(len(rows), emailDigestItems))
+	for i, row := range rows {
+		if !slices.Contains(categories, row.Category) {
+			categories = append(categories, row.Category)
+		}
+		if i >= emailDigestItems {
+			continue
+		}
+		args := row.ArgList()
+		item := emailItem{
+			Subject: fillArgs(t("email."+row.Template+".subject"), args),
+			Body:    fillArgs(t("email."+row.Template+".body"), args),
+		}
+		if row.Link != "" {
+			item.URL = s.cfg.BaseURL + row.Link
+		}
+		items = append(items, item)
+	}
+
+	view := emailView{
+		Items:            items,
+		OpenLabel:        t("email.open"),
+		SettingsLabel:    t("email.footer.settings"),
+		SettingsURL:      s.cfg.BaseURL + "/settings/notifications",
+		UnsubscribeLabel: t("email.footer.unsubscribe"),
+	}
+	labels := make([]string, 0, len(categories))
+	for _, c := range categories {
+		labels = append(labels, t("email.category."+c))
+	}
+	view.Reason = t("email.footer.reason", strings.Join(labels, ", "))
+	if frequency == gnurepo.EmailFrequencyInstant {
+		view.Title = items[0].Subject
+	} else {
+		view.Digest = true
+		view.Title = t("email.digest."+frequency+"_subject", len(rows))
+		view.Intro = t("email.digest.intro")
+	}
+
+	token := s.UnsubscribeToken(mbID, categories)
+	view.UnsubscribeURL = s.cfg.BaseURL + "/email/unsubscribe?token=" + url.QueryEscape(token)
+
+	var html, text bytes.Buffer
+	if err := emailHTMLTemplate.Execute(&html, view); err != nil {
+		return nil, err
+	}
+	if err := emailTextTemplate.Execute(&text, view); err != nil {
+		return nil, err
+	}
+	return &mail.Message{
+		From:    s.cfg.From,
+		To:      email,
+		Subject: view.Title,
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: map[string]string{
+			// RFC 8058 — 메일 앱의 "구독 취소" 버튼이 이 주소로 POST 한다 (DKIM 서명이 두 헤더를 덮어야 한다)
+			"List-Unsubscribe":      "<" + s.cfg.UnsubscribeURL + "?token=" + url.QueryEscape(token) + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+// fillArgs fills the %s verbs of a template message in order. 제목은 본문보다 값을 적게 쓰므로
+// 남는 값은 버린다 (fmt 의 %!(EXTRA …) 가 메일에 찍히지 않게).
+func fillArgs(format string, args []string) string {
+	n := min(strings.Count(format, "%s"), len(args))
+	values := make([]interface{}, 0, n)
+	for _, a := range args[:n] {
+		values = append(values, a)
+	}
+	if n == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, values...)
+}
+
+// UnsubscribeToken signs (mbID, categories) for the one-click unsubscribe link.
+// 만료가 없다 — 오래된 메일의 링크도 동작해야 한다. 토큰은 해당 분류를 끄는 것 말고는 할 수 없다.
+func (s *EmailNotificationService) UnsubscribeToken(mbID string, categories []string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(mbID + "\n" + strings.Join(categories, ",")))
+	return payload + "." + s.unsubscribeSignature(payload)
+}
+
+// ParseUnsubscribeToken verifies a token and returns what it unsubscribes
+func (s *EmailNotificationService) ParseUnsubscribeToken(token string) (mbID string, categories []string, err error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.unsubscribeSignature(payload))) {
+		return "", nil, ErrInvalidUnsubscribeToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", nil, ErrInvalidUnsubscribeToken
+	}
+	mbID, list, ok := strings.Cut(string(raw), "\n")
+	if !ok || mbID == "" {
+		return "", nil, ErrInvalidUnsubscribeToken
+	}
+	for _, c := range strings.Split(list, ",") {
+		if gnurepo.ValidEmailCategory(c) {
+			categories = append(categories, c)
+		}
+	}
+	if len(categories) == 0 {
+		return "", nil, ErrInvalidUnsubscribeToken
+	}
+	return mbID, categories, nil
+}
+
+// Unsubscribe turns off the categories named by a token
+func (s *EmailNotificationService) Unsubscribe(token string) ([]string, error) {
+	mbID, categories, err := s.ParseUnsubscribeToken(token)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range categories {
+		if err := s.repo.SetPreference(mbID, c, gnurepo.EmailFrequencyOff); err != nil {
+			return nil, err
+		}
+	}
+	return categories, nil
+}
+
+func (s *EmailNotificationService) unsubscribeSignature(payload string) string {
+	mac := hmac.New(sha256.New, s.cfg.Secret)
+	mac.Write([]byte("email-unsubscribe\n" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:18])
+}
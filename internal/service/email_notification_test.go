@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"github.com/damoang/angple-backend/pkg/i18n"
+	"github.com/damoang/angple-backend/pkg/mail"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeMailTransport records messages; bounce 주소는 SMTP 550 처럼 영구 실패한다
+type fakeMailTransport struct {
+	sent   []*mail.Message
+	bounce string
+}
+
+func (f *fakeMailTransport) Send(_ context.Context, msg *mail.Message) error {
+	if msg.To == f.bounce {
+		return &mail.PermanentError{Code: 550, Err: errors.New("no such user")}
+	}
+	if _, err := msg.Bytes(); err != nil {
+		return err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func setupEmailNotiTest(t *testing.T) (*EmailNotificationService, *fakeMailTransport, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&gnurepo.EmailPreference{}, &gnurepo.EmailOutbox{}, &gnurepo.EmailSuppression{}); err != nil {
+		t.Fatalf("migrate email tables: %v", err)
+	}
+	db.Exec(`CREATE TABLE g5_member (mb_id TEXT PRIMARY KEY, mb_email TEXT)`)
+	db.Exec(`INSERT INTO g5_member VALUES ('zoe', 'zoe@example.com'), ('kim', 'gone@example.com'), ('lee', '')`)
+
+	bundle := i18n.NewBundle(i18n.LocaleKo)
+	for locale, msgs := range i18n.DefaultMessages() {
+		bundle.LoadMessages(locale, msgs)
+	}
+	transport := &fakeMailTransport{bounce: "gone@example.com"}
+	svc := NewEmailNotificationService(db, gnurepo.NewNotiEmailRepository(db), transport, bundle, EmailNotificationConfig{
+		From:    "다모앙 <noreply@damoang.net>",
+		BaseURL: "https://damoang.net/",
+		Secret:  []byte("test-secret"),
+	})
+	return svc, transport, db
+}
+
+func queued(t *testing.T, db *gorm.DB, mbID string) int64 {
+	t.Helper()
+	var n int64
+	db.Model(&gnurepo.EmailOutbox{}).Where("mb_id = ?", mbID).Count(&n)
+	return n
+}
+
+func TestEmailNotificationInstant(t *testing.T) {
+	svc, transport, db := setupEmailNotiTest(t)
+	ctx := context.Background()
+
+	for _, mbID := range []string{"zoe", "kim", "lee"} {
+		if err := gnurepo.EnqueueEmail(db, mbID, gnurepo.EmailCategoryAccount, "discipline", "/messages", "3일"); err != nil {
+			t.Fatalf("EnqueueEmail: %v", err)
+		}
+	}
+	// 포인트는 옵트인 — 설정이 없으면 쌓지 않는다
+	if err := gnurepo.EnqueueEmail(db, "zoe", gnurepo.EmailCategoryPoint, "point_expiry", "/point", "100"); err != nil {
+		t.Fatalf("EnqueueEmail: %v", err)
+	}
+	if n := queued(t, db, "zoe"); n != 1 {
+		t.Fatalf("expected 1 queued row for zoe, got %d", n)
+	}
+
+	res, err := svc.RunInstant(ctx)
+	if err != nil {
+		t.Fatalf("RunInstant: %v", err)
+	}
+	if res.Sent != 1 || res.Skipped != 1 || res.Suppressed != 1 || res.Failed != 0 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	msg := transport.sent[0]
+	if msg.To != "zoe@example.com" || msg.Subject != "이용 제한 안내" || !strings.Contains(msg.Text, "기간: 3일") ||
+		!strings.Contains(msg.Text, "https://damoang.net/messages") || msg.Headers["List-Unsubscribe-Post"] != "List-Unsubscribe=One-Click" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	repo := gnurepo.NewNotiEmailRepository(db)
+	if ok, _ := repo.IsSuppressed("gone@example.com"); !ok {
+		t.Fatal("permanent failure should suppress the address")
+	}
+	if res, _ := svc.RunInstant(ctx); res.Queued != 0 {
+		t.Fatalf("finished rows must not be picked again, got %+v", res)
+	}
+
+	// 헤더의 one-click 주소로 수신 거부 → 같은 분류는 더 쌓이지 않는다
+	link := strings.Trim(msg.Headers["List-Unsubscribe"], "<>")
+	u, _ := url.Parse(link)
+	if u.Path != "/api/v2/email/unsubscribe" {
+		t.Fatalf("unexpected unsubscribe URL %q", link)
+	}
+	categories, err := svc.Unsubscribe(u.Query().Get("token"))
+	if err != nil || len(categories) != 1 || categories[0] != gnurepo.EmailCategoryAccount {
+		t.Fatalf("Unsubscribe: %v %v", categories, err)
+	}
+	_ = gnurepo.EnqueueEmail(db, "zoe", gnurepo.EmailCategoryAccount, "discipline", "/messages", "7일")
+	if n := queued(t, db, "zoe"); n != 1 {
+		t.Fatalf("unsubscribed category must not queue, got %d rows", n)
+	}
+	if _, err := svc.Unsubscribe(u.Query().Get("token") + "x"); !errors.Is(err, ErrInvalidUnsubscribeToken) {
+		t.Fatalf("tampered token should fail, got %v", err)
+	}
+}
+
+func TestEmailNotificationDigest(t *testing.T) {
+	svc, transport, db := setupEmailNotiTest(t)
+	ctx := context.Background()
+	repo := gnurepo.NewNotiEmailRepository(db)
+	_ = repo.SetPreference("zoe", gnurepo.EmailCategoryPoint, gnurepo.EmailFrequencyDaily)
+	_ = repo.SetPreference("zoe", gnurepo.EmailCategoryDigest, gnurepo.EmailFrequencyDaily)
+
+	_ = gnurepo.EnqueueEmail(db, "zoe", gnurepo.EmailCategoryPoint, "point_expiry", "/point", "100")
+	_ = gnurepo.EnqueueEmail(db, "zoe", gnurepo.EmailCategoryDigest, "board_digest", "/free", "자유게시판", "3", "가, 나, 다")
+	if res, _ := svc.RunInstant(ctx); res.Queued != 0 {
+		t.Fatalf("daily rows must wait for the digest, got %+v", res)
+	}
+
+	res, err := svc.RunDigest(ctx, gnurepo.EmailFrequencyDaily)
+	if err != nil || res.Sent != 1 || res.Queued != 2 {
+		t.Fatalf("RunDigest: %+v %v", res, err)
+	}
+	msg := transport.sent[0]
+	if msg.Subject != "오늘의 알림 2건" || !strings.Contains(msg.Text, "7일 안에 100P가 만료됩니다.") ||
+		!strings.Contains(msg.Text, "■ 자유게시판 새 글 3건") || !strings.Contains(msg.HTML, "자유게시판 게시판에 새 글 3건: 가, 나, 다") {
+		t.Fatalf("unexpected digest %s\n%s", msg.Subject, msg.Text)
+	}
+	_, categories, err := svc.ParseUnsubscribeToken(strings.TrimPrefix(strings.Trim(msg.Headers["List-Unsubscribe"], "<>"), "https://damoang.net/api/v2/email/unsubscribe?token="))
+	if err != nil || strings.Join(categories, ",") != "point,digest" {
+		t.Fatalf("digest token should cover every category in the mail: %v %v", categories, err)
+	}
+	if _, err := svc.RunDigest(ctx, "hourly"); err == nil {
+		t.Fatal("unknown frequency should fail")
+	}
+}
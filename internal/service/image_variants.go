@@ -0,0 +1,260 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/buckket/go-blurhash"
+	"golang.org/x/image/draw"
+)
+
+// ImageVariant is one configured derivative width of an uploaded image
+type ImageVariant struct {
+	Name  string
+	Width int
+}
+
+// DefaultImageVariants — thumb 는 작은 카드, list 는 게시판 목록 썸네일, full 은 본문 보기용이다
+var DefaultImageVariants = []ImageVariant{
+	{Name: "thumb", Width: 320},
+	{Name: "list", Width: 640},
+	{Name: "full", Width: 1920},
+}
+
+// ParseImageVariants parses "thumb:320,list:640,full:1920" (MEDIA_IMAGE_VARIANTS). 빈 값은 기본값이다.
+func ParseImageVariants(spec string) ([]ImageVariant, error) {
+	if strings.TrimSpace(spec) == "" {
+		return DefaultImageVariants, nil
+	}
+	var variants []ImageVariant
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		name, width, ok := strings.Cut(strings.TrimSpace(part), ":")
+		w, err := strconv.Atoi(width)
+		if !ok || err != nil || w < 16 || w > 8192 || name == "" || len(name) > 16 || seen[name] {
+			return nil, fmt.Errorf("invalid image variant %q (name:width)", part)
+		}
+		seen[name] = true
+		variants = append(variants, ImageVariant{Name: name, Width: w})
+	}
+	return variants, nil
+}
+
+// webpEncoder 는 WebP 인코더다 — libwebp(cgo) 빌드에서만 채워진다 (image_webp_cgo.go).
+// nil 이면 파생본을 원본 형식으로만 만든다.
+var webpEncoder func(w io.Writer, img image.Image, quality int) error
+
+const (
+	imageJPEGQuality = 85
+	imageWebPQuality = 80
+	// blurhashWidth 는 자리표시 계산 전에 줄이는 폭이다 — 4x3 성분이면 이 정도로 충분하고 빠르다
+	blurhashWidth = 64
+)
+
+// encodedImage is an image encoded for upload
+type encodedImage struct {
+	data        []byte
+	contentType string
+	ext         string
+	format      string
+}
+
+// encodeImage encodes img as format (jpeg·png·webp). 어느 인코더도 EXIF 등 메타데이터를 쓰지 않는다.
+func encodeImage(img image.Image, format string) (*encodedImage, error) {
+	var buf bytes.Buffer
+	out := &encodedImage{format: format}
+	switch format {
+	case "png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		out.contentType, out.ext = "image/png", ".png"
+	case "webp":
+		if webpEncoder == nil {
+			return nil, fmt.Errorf("webp encoder not available")
+		}
+		if err := webpEncoder(&buf, img, imageWebPQuality); err != nil {
+			return nil, err
+		}
+		out.contentType, out.ext = "image/webp", ".webp"
+	default:
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: imageJPEGQuality}); err != nil {
+			return nil, err
+		}
+		out.contentType, out.ext, out.format = "image/jpeg", ".jpg", "jpeg"
+	}
+	out.data = buf.Bytes()
+	return out, nil
+}
+
+// scaleToWidth resizes img to width keeping the aspect ratio (Catmull-Rom — 목록 썸네일의 계단 현상 방지)
+func scaleToWidth(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	if width <= 0 || b.Dx() <= width {
+		return img
+	}
+	height := max(b.Dy()*width/b.Dx(), 1)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// imagePlaceholder returns the blurhash of img (실패하면 빈 값 — 자리표시는 없어도 된다)
+func imagePlaceholder(img image.Image) string {
+	small := img
+	if img.Bounds().Dx() > blurhashWidth {
+		small = scaleToWidth(img, blurhashWidth)
+	}
+	hash, err := blurhash.Encode(4, 3, small)
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
+// exifOrientation reads the EXIF Orientation tag (1–8) of a JPEG — 없거나 읽지 못하면 1(그대로).
+// 폰 사진은 센서 방향 그대로 저장하고 이 태그로 돌려 보이게 한다. 재인코딩하면 태그가 사라지므로 픽셀을 직접 돌린다.
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xD8 || (marker >= 0xD0 && marker <= 0xD7) || marker == 0x01 {
+			i += 2
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 { // 스캔 시작 — 메타데이터 구간이 끝났다
+			return 1
+		}
+		segLen := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if segLen < 2 || i+2+segLen > len(data) {
+			return 1
+		}
+		seg := data[i+4 : i+2+segLen]
+		if marker == 0xE1 && len(seg) > 6 && string(seg[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(seg[6:])
+		}
+		i += 2 + segLen
+	}
+	return 1
+}
+
+// tiffOrientation finds tag 0x0112 in IFD0 of a TIFF header
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd : ifd+2]))
+	for n := 0; n < count; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			if v := int(order.Uint16(tiff[entry+8 : entry+10])); v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// stripWebPMetadata drops the EXIF and XMP chunks of a WebP file and clears their VP8X flags.
+// 재인코딩하지 못한 WebP(인코더 없는 빌드, 애니메이션 등)도 GPS 같은 메타데이터 없이 올리기 위해 쓴다 — 픽셀 데이터는 그대로다.
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("not a webp file")
+	}
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	vp8x := -1
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return nil, fmt.Errorf("truncated webp chunk header")
+		}
+		fourcc := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		if size < 0 || size > len(data)-i-8 {
+			return nil, fmt.Errorf("truncated webp chunk %q", fourcc)
+		}
+		end := i + 8 + size
+		if fourcc != "EXIF" && fourcc != "XMP " {
+			if fourcc == "VP8X" {
+				vp8x = len(out)
+			}
+			out = append(out, data[i:end]...)
+			if size%2 == 1 { // 청크는 짝수 길이로 채운다
+				out = append(out, 0)
+			}
+		}
+		i = end + size%2
+	}
+	if vp8x >= 0 && vp8x+8 < len(out) {
+		out[vp8x+8] &^= 0x08 | 0x04 // VP8X flags: EXIF, XMP
+	}
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out, nil
+}
+
+// applyOrientation turns img upright for an EXIF orientation (2–8 은 뒤집기·회전 조합)
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	// 5–8 은 90° 계열이라 가로·세로가 바뀐다
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // 좌우 반전
+				dx, dy = w-1-x, y
+			case 3: // 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // 상하 반전
+				dx, dy = x, h-1-y
+			case 5: // 좌우 반전 + 270° (transpose)
+				dx, dy = y, x
+			case 6: // 시계 방향 90°
+				dx, dy = h-1-y, x
+			case 7: // transverse
+				dx, dy = h-1-y, w-1-x
+			case 8: // 반시계 방향 90°
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
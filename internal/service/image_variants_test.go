@@ -0,0 +1,284 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/storage"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// jpegWithOrientation encodes a w×h JPEG (왼쪽 절반 빨강) and inserts an EXIF APP1 segment with the given orientation
+func jpegWithOrientation(t *testing.T, w, h, orientation int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{B: 255, A: 255}
+			if x < w/2 {
+				c = color.RGBA{R: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	// TIFF: II, 42, IFD0 at 8 → 1 entry (0x0112 SHORT 1 = orientation), next IFD 0
+	tiff := []byte("II*\x00\x08\x00\x00\x00")
+	entry := make([]byte, 2+12+4)
+	binary.LittleEndian.PutUint16(entry[0:], 1)
+	binary.LittleEndian.PutUint16(entry[2:], 0x0112)
+	binary.LittleEndian.PutUint16(entry[4:], 3)
+	binary.LittleEndian.PutUint32(entry[6:], 1)
+	binary.LittleEndian.PutUint16(entry[10:], uint16(orientation))
+	payload := append([]byte("Exif\x00\x00"), append(tiff, entry...)...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	data := buf.Bytes()
+	return append(append([]byte{0xFF, 0xD8}, segment...), data[2:]...)
+}
+
+func fileHeader(t *testing.T, name string, data []byte) *multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("file", name)
+	_, _ = part.Write(data)
+	_ = mw.Close()
+	form, err := multipart.NewReader(&body, mw.Boundary()).ReadForm(10 << 20)
+	if err != nil {
+		t.Fatalf("read form: %v", err)
+	}
+	return form.File["file"][0]
+}
+
+func TestParseImageVariants(t *testing.T) {
+	if v, err := ParseImageVariants(""); err != nil || len(v) != len(DefaultImageVariants) {
+		t.Fatalf("empty spec should use defaults: %v %v", v, err)
+	}
+	v, err := ParseImageVariants("thumb:200, list:480")
+	if err != nil || len(v) != 2 || v[1] != (ImageVariant{Name: "list", Width: 480}) {
+		t.Fatalf("unexpected %v %v", v, err)
+	}
+	for _, bad := range []string{"thumb", "thumb:abc", "thumb:0", "a:100,a:200", ":100"} {
+		if _, err := ParseImageVariants(bad); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestExifOrientation(t *testing.T) {
+	for o := 1; o <= 8; o++ {
+		if got := exifOrientation(jpegWithOrientation(t, 8, 4, o)); got != o {
+			t.Errorf("orientation %d: got %d", o, got)
+		}
+	}
+	if got := exifOrientation([]byte("not a jpeg")); got != 1 {
+		t.Errorf("garbage should read as 1, got %d", got)
+	}
+
+	// 6 = 시계 방향 90°: 왼쪽(빨강)이 위로 간다
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	src.Set(0, 0, color.RGBA{R: 255, A: 255})
+	rotated := applyOrientation(src, 6)
+	if b := rotated.Bounds(); b.Dx() != 2 || b.Dy() != 4 {
+		t.Fatalf("orientation 6 should swap sides, got %v", b)
+	}
+	if r, _, _, _ := rotated.At(1, 0).RGBA(); r == 0 {
+		t.Fatal("top-left pixel should land at the top-right after a clockwise turn")
+	}
+}
+
+func TestUploadImageVariants(t *testing.T) {
+	root := t.TempDir()
+	store, err := storage.NewLocalBackend(storage.LocalConfig{Root: root, PublicURL: "https://api.test/files", SigningKey: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&v2domain.V2MediaImage{}, &v2domain.V2MediaVariant{}); err != nil {
+		t.Fatal(err)
+	}
+	repo := v2repo.NewMediaImageRepository(db)
+
+	svc := NewMediaService(store)
+	svc.SetImageRepository(repo)
+	svc.SetImageVariants([]ImageVariant{{Name: "thumb", Width: 10}, {Name: "list", Width: 16}, {Name: "full", Width: 100}})
+
+	// 40x20 사진을 세로로 찍은 것(orientation 6) — 올린 뒤엔 20x40 이어야 한다
+	res, err := svc.UploadImage(context.Background(), fileHeader(t, "phone.jpg", jpegWithOrientation(t, 40, 20, 6)), 1920)
+	if err != nil {
+		t.Fatalf("UploadImage: %v", err)
+	}
+	if res.Width != 20 || res.Height != 40 || res.ContentType != "image/jpeg" || res.Blurhash == "" {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	stored, _ := os.ReadFile(filepath.Join(root, filepath.FromSlash(res.Key)))
+	if bytes.Contains(stored, []byte("Exif")) {
+		t.Fatal("EXIF must be stripped from the stored original")
+	}
+
+	formats := 1
+	if webpEncoder != nil {
+		formats = 2
+	}
+	if len(res.Variants) != 3*formats {
+		t.Fatalf("want %d variants, got %+v", 3*formats, res.Variants)
+	}
+	for _, v := range res.Variants {
+		if v.Name == "full" && v.Format == "jpeg" && v.Key != res.Key {
+			t.Errorf("full jpeg at original width should reuse the original, got %s", v.Key)
+		}
+		if ok, _ := store.Exists(context.Background(), v.Key); !ok {
+			t.Errorf("variant %s/%s not stored at %s", v.Name, v.Format, v.Key)
+		}
+	}
+
+	images, err := repo.FindByURLs([]string{res.URL, "https://elsewhere/x.jpg"})
+	if err != nil || len(images) != 1 {
+		t.Fatalf("FindByURLs: %v %v", images, err)
+	}
+	list := images[res.URL].Variant("list")
+	if list == nil || list.Width != 16 || list.Height != 32 {
+		t.Fatalf("unexpected list variant %+v", list)
+	}
+	if webpEncoder != nil && list.Format != "webp" {
+		t.Fatalf("list variant should prefer webp, got %s", list.Format)
+	}
+}
+
+func TestUploadDeduplicatesContent(t *testing.T) {
+	store, err := storage.NewLocalBackend(storage.LocalConfig{Root: t.TempDir(), PublicURL: "https://api.test/files", SigningKey: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&v2domain.V2MediaImage{}, &v2domain.V2MediaVariant{}, &v2domain.V2MediaObject{}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewMediaService(store)
+	svc.SetImageRepository(v2repo.NewMediaImageRepository(db))
+	svc.SetObjectRepository(v2repo.NewMediaObjectRepository(db))
+	svc.SetImageVariants([]ImageVariant{{Name: "list", Width: 16}})
+
+	photo := jpegWithOrientation(t, 40, 20, 1)
+	first, err := svc.UploadImage(context.Background(), fileHeader(t, "a.jpg", photo), 1920)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.UploadImage(context.Background(), fileHeader(t, "meme (1).jpg", photo), 1920)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Key != first.Key || second.URL != first.URL || len(second.Variants) != len(first.Variants) || second.Blurhash != first.Blurhash {
+		t.Fatalf("same content should reuse the stored image:\n%+v\n%+v", first, second)
+	}
+	if _, ok := storage.ContentHash(first.Key); !ok {
+		t.Fatalf("key should be content-addressed: %s", first.Key)
+	}
+
+	doc := []byte("hello attachment")
+	a, err := svc.UploadAttachment(context.Background(), fileHeader(t, "a.txt", doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := svc.UploadAttachment(context.Background(), fileHeader(t, "b.txt", doc))
+	if err != nil || a.Key != b.Key {
+		t.Fatalf("attachments should dedup: %v %s %s", err, a.Key, b.Key)
+	}
+
+	var objects int64
+	db.Model(&v2domain.V2MediaObject{}).Count(&objects)
+	if objects != 2 {
+		t.Fatalf("want 2 object rows, got %d", objects)
+	}
+	// 공유 파일은 API 로 지우지 않는다 — media-gc 가 참조를 보고 치운다
+	if err := svc.DeleteFile(context.Background(), a.Key); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.Exists(context.Background(), a.Key); !ok {
+		t.Fatal("content-addressed file must survive DeleteFile")
+	}
+}
+
+// webpChunk builds one RIFF chunk (홀수 길이면 0 으로 채운다)
+func webpChunk(fourcc string, payload []byte) []byte {
+	chunk := append([]byte(fourcc), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(payload)))
+	chunk = append(chunk, payload...)
+	if len(payload)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func TestStripWebPMetadata(t *testing.T) {
+	vp8x := make([]byte, 10)
+	vp8x[0] = 0x10 | 0x08 | 0x04 // alpha, EXIF, XMP
+	var body []byte
+	body = append(body, webpChunk("VP8X", vp8x)...)
+	body = append(body, webpChunk("VP8L", []byte("pixel"))...)
+	body = append(body, webpChunk("EXIF", []byte("GPS 37.56,126.97"))...)
+	body = append(body, webpChunk("XMP ", []byte("<x:xmpmeta/>"))...)
+	data := append([]byte("RIFF\x00\x00\x00\x00WEBP"), body...)
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)-8))
+
+	stripped, err := stripWebPMetadata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte("RIFF\x00\x00\x00\x00WEBP"), webpChunk("VP8X", append([]byte{0x10}, vp8x[1:]...))...)
+	want = append(want, webpChunk("VP8L", []byte("pixel"))...)
+	binary.LittleEndian.PutUint32(want[4:], uint32(len(want)-8))
+	if !bytes.Equal(stripped, want) {
+		t.Fatalf("unexpected output\n got %q\nwant %q", stripped, want)
+	}
+	if _, err := stripWebPMetadata(data[:len(data)-3]); err == nil {
+		t.Fatal("truncated chunk should be rejected")
+	}
+
+	// 인코더가 없어 재인코딩하지 못해도 저장본에는 EXIF 가 없다
+	defer func(enc func(io.Writer, image.Image, int) error) { webpEncoder = enc }(webpEncoder)
+	webpEncoder = nil
+	root := t.TempDir()
+	store, err := storage.NewLocalBackend(storage.LocalConfig{Root: root, PublicURL: "https://api.test/files", SigningKey: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := NewMediaService(store).UploadImage(context.Background(), fileHeader(t, "photo.webp", data), 1920)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(res.Key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ContentType != "image/webp" || !bytes.Equal(saved, want) {
+		t.Fatalf("stored webp should be stripped: %s %q", res.ContentType, saved)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build cgo
+
+package service
+
+import (
+	"image"
+	"io"
+
+	"github.com/chai2010/webp"
+)
+
+// libwebp 는 cgo 로만 쓸 수 있다 — 배포 이미지(deployments/docker/api.Dockerfile)는 cgo 로 빌드한다.
+// CGO_ENABLED=0 빌드는 WebP 파생본 없이 원본 형식만 만들고, WebP 원본은 메타데이터만 걷어 그대로 올린다.
+func init() {
+	webpEncoder = func(w io.Writer, img image.Image, quality int) error {
+		return webp.Encode(w, img, &webp.Options{Quality: float32(quality)})
+	}
+}
@@ -3,33 +3,46 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"image"
-	"image/jpeg"
-	"image/png"
+	_ "image/gif" // GIF 크기·첫 프레임(자리표시) 디코딩
 	"io"
 	"mime/multipart"
 	"net/http"
 	"path"
 	"strings"
+	"time"
 
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
 	pkglogger "github.com/damoang/angple-backend/pkg/logger"
 	"github.com/damoang/angple-backend/pkg/storage"
+	_ "golang.org/x/image/webp" // WebP 업로드 디코딩
 )
 
-// MediaService handles file uploads with image processing and S3 storage
+// maxImagePixels 는 디코딩할 이미지의 최대 픽셀 수다 (8000x5000)
+const maxImagePixels = 40_000_000
+
+// MediaService handles file uploads with image processing and object storage (S3 또는 로컬 디스크)
 type MediaService struct {
-	s3        *storage.S3Client
+	store     storage.Backend
 	maxSize   int64    // max file size in bytes
 	allowExts []string // allowed file extensions
+	variants  []ImageVariant
+	images    v2repo.MediaImageRepository  // nil 이면 파생본을 만들기만 하고 기록하지 않는다
+	objects   v2repo.MediaObjectRepository // nil 이면 중복 확인·참조 집계 없이 내용 주소로 올리기만 한다
+	uploads   v2repo.MediaUploadRepository // nil 이면 이어 올리기(/api/v2/media/uploads)를 쓰지 않는다
 }
 
 // NewMediaService creates a new MediaService
-func NewMediaService(s3Client *storage.S3Client) *MediaService {
+func NewMediaService(store storage.Backend) *MediaService {
 	return &MediaService{
-		s3:      s3Client,
-		maxSize: 50 * 1024 * 1024, // 50MB
+		store:    store,
+		maxSize:  50 * 1024 * 1024, // 50MB
+		variants: DefaultImageVariants,
 		allowExts: []string{
 			".jpg", ".jpeg", ".png", ".gif", ".webp",
 			".mp4", ".webm", ".mov",
@@ -40,6 +53,21 @@ func NewMediaService(s3Client *storage.S3Client) *MediaService {
 	}
 }
 
+// SetImageVariants sets the derivative widths made for each uploaded image
+func (s *MediaService) SetImageVariants(variants []ImageVariant) {
+	s.variants = variants
+}
+
+// SetImageRepository records uploaded images and their variants (목록 썸네일 조회용)
+func (s *MediaService) SetImageRepository(images v2repo.MediaImageRepository) {
+	s.images = images
+}
+
+// SetObjectRepository records stored objects by content hash — 같은 파일은 한 번만 저장하고 media-gc 가 참조를 센다
+func (s *MediaService) SetObjectRepository(objects v2repo.MediaObjectRepository) {
+	s.objects = objects
+}
+
 // UploadResult represents the result of an upload operation
 type MediaUploadResult struct {
 	Key         string `json:"key"`
@@ -51,6 +79,9 @@ type MediaUploadResult struct {
 	Size        int64  `json:"size"`
 	Width       int    `json:"width,omitempty"`
 	Height      int    `json:"height,omitempty"`
+	// Blurhash·Variants 는 이미지 업로드에만 있다 (파생본: 크기별 WebP + 원본 형식)
+	Blurhash string                    `json:"blurhash,omitempty"`
+	Variants []v2domain.V2MediaVariant `json:"variants,omitempty"`
 }
 
 // UploadImage uploads an image, optionally converting to JPEG and resizing
@@ -81,51 +112,53 @@ func (s *MediaService) UploadImage(ctx context.Context, file *multipart.FileHead
 	}
 
 	contentType := http.DetectContentType(data)
-	var reader io.Reader = bytes.NewReader(data)
-	size := int64(len(data))
+	body := data
 	var width, height int
 
-	// Try to decode and resize if it's a raster image (not SVG/GIF)
-	if ext != ".gif" {
-		img, format, decErr := image.Decode(bytes.NewReader(data))
-		if decErr == nil {
-			bounds := img.Bounds()
-			width = bounds.Dx()
-			height = bounds.Dy()
-
-			// Resize if wider than maxWidth
-			if maxWidth > 0 && width > maxWidth {
-				img = resizeImage(img, maxWidth)
-				bounds = img.Bounds()
-				width = bounds.Dx()
-				height = bounds.Dy()
-			}
+	// 픽셀 수가 지나친 이미지는 디코딩 전에 막는다 (작은 파일이 수 GB 로 풀리는 경우)
+	if cfg, _, cfgErr := image.DecodeConfig(bytes.NewReader(data)); cfgErr == nil {
+		if int64(cfg.Width)*int64(cfg.Height) > maxImagePixels {
+			return nil, fmt.Errorf("image dimensions too large (%dx%d)", cfg.Width, cfg.Height)
+		}
+		width, height = cfg.Width, cfg.Height
+	}
 
-			// Re-encode
-			var buf bytes.Buffer
-			switch format {
-			case "png":
-				if err := png.Encode(&buf, img); err == nil {
-					reader = &buf
-					size = int64(buf.Len())
-					contentType = "image/png"
-					ext = ".png"
-				}
-			default:
-				// Encode as JPEG for everything else
-				if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err == nil {
-					reader = &buf
-					size = int64(buf.Len())
-					contentType = "image/jpeg"
-					ext = ".jpg"
+	// Decode → EXIF 방향 적용 → maxWidth 로 줄여 재인코딩 (GPS 등 메타데이터는 재인코딩으로 빠진다).
+	// GIF 는 애니메이션을 지키려 그대로 올리고 파생본을 만들지 않는다.
+	var img image.Image
+	format := ""
+	reencoded := false
+	if decoded, decFormat, decErr := image.Decode(bytes.NewReader(data)); decErr == nil {
+		img, format = decoded, decFormat
+		if ext != ".gif" {
+			img = applyOrientation(img, exifOrientation(data))
+			img = scaleToWidth(img, maxWidth)
+			width, height = img.Bounds().Dx(), img.Bounds().Dy()
+
+			// WebP 원본은 WebP 로 — 인코더가 없는 빌드에서는 아래에서 메타데이터만 걷어 낸다
+			if format != "webp" || webpEncoder != nil {
+				if encoded, err := encodeImage(img, format); err == nil {
+					body = encoded.data
+					contentType = encoded.contentType
+					ext = encoded.ext
+					format = encoded.format
+					reencoded = true
 				}
 			}
 		}
 	}
+	// 재인코딩하지 못한 WebP(인코더 없음·애니메이션 등)도 EXIF·XMP 는 남기지 않는다
+	if !reencoded && contentType == "image/webp" {
+		stripped, err := stripWebPMetadata(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webp image: %w", err)
+		}
+		body = stripped
+	}
 
-	key := storage.GenerateKey("images", sanitizeFilename(file.Filename, ext))
-
-	result, err := s.s3.Upload(ctx, key, reader, contentType, size)
+	size := int64(len(body))
+	sum := sha256.Sum256(body)
+	result, reused, err := storeContent(ctx, s.store, s.objects, "images", hex.EncodeToString(sum[:]), ext, bytes.NewReader(body), contentType, size)
 	if err != nil {
 		return nil, err
 	}
@@ -133,9 +166,10 @@ func (s *MediaService) UploadImage(ctx context.Context, file *multipart.FileHead
 	pkglogger.GetLogger().Info().
 		Str("key", result.Key).
 		Int64("size", size).
+		Bool("dedup", reused).
 		Msg("image uploaded")
 
-	return &MediaUploadResult{
+	out := &MediaUploadResult{
 		Key:         result.Key,
 		URL:         result.URL,
 		CDNURL:      result.CDNURL,
@@ -145,7 +179,170 @@ func (s *MediaService) UploadImage(ctx context.Context, file *multipart.FileHead
 		Size:        size,
 		Width:       width,
 		Height:      height,
-	}, nil
+	}
+	if img == nil {
+		return out, nil
+	}
+	if reused && s.reuseImageRecord(out) {
+		return out, nil
+	}
+
+	out.Blurhash = imagePlaceholder(img)
+	if ext != ".gif" {
+		out.Variants = s.uploadVariants(ctx, img, format, out)
+	}
+	s.recordImage(out)
+	return out, nil
+}
+
+// uploadVariants uploads each configured width as WebP and in the original format.
+// 원본보다 넓은 파생본은 만들지 않고 원본 폭으로 둔다. 실패한 파생본은 빠질 뿐 업로드는 성공이다.
+func (s *MediaService) uploadVariants(ctx context.Context, img image.Image, format string, original *MediaUploadResult) []v2domain.V2MediaVariant {
+	formats := []string{format}
+	if format != "webp" && webpEncoder != nil {
+		formats = []string{"webp", format}
+	} else if format == "webp" && webpEncoder == nil {
+		return nil
+	}
+	base := strings.TrimSuffix(original.Key, path.Ext(original.Key))
+
+	// 같은 폭·형식은 한 번만 올린다 (작은 이미지는 thumb·list·full 이 같은 파일이 된다)
+	type uploaded struct {
+		key, url string
+		size     int64
+	}
+	done := make(map[string]uploaded)
+	scaled := make(map[int]image.Image)
+
+	var variants []v2domain.V2MediaVariant
+	for _, v := range s.variants {
+		width := min(v.Width, original.Width)
+		small, ok := scaled[width]
+		if !ok {
+			small = scaleToWidth(img, width)
+			scaled[width] = small
+		}
+		for _, f := range formats {
+			variant := v2domain.V2MediaVariant{Name: v.Name, Format: f, Width: small.Bounds().Dx(), Height: small.Bounds().Dy()}
+			doneKey := fmt.Sprintf("%d/%s", width, f)
+			if prev, ok := done[doneKey]; ok {
+				variant.Key, variant.URL, variant.Size = prev.key, prev.url, prev.size
+				variants = append(variants, variant)
+				continue
+			}
+			if width == original.Width && f == format {
+				// 원본과 같은 파일이다
+				variant.Key, variant.URL, variant.Size = original.Key, original.URL, original.Size
+			} else {
+				encoded, err := encodeImage(small, f)
+				if err != nil {
+					continue
+				}
+				res, err := s.store.Upload(ctx, base+"_"+v.Name+encoded.ext, bytes.NewReader(encoded.data), encoded.contentType, int64(len(encoded.data)))
+				if err != nil {
+					pkglogger.GetLogger().Warn().Err(err).Str("key", original.Key).Str("variant", v.Name).Msg("image variant upload failed")
+					continue
+				}
+				variant.Key, variant.URL, variant.Size = res.Key, res.URL, int64(len(encoded.data))
+			}
+			done[doneKey] = uploaded{variant.Key, variant.URL, variant.Size}
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
+
+// recordImage stores the image and its variants so list thumbnails can find them by URL
+func (s *MediaService) recordImage(out *MediaUploadResult) {
+	if s.images == nil {
+		return
+	}
+	record := &v2domain.V2MediaImage{
+		Key:         out.Key,
+		URL:         out.URL,
+		ContentType: out.ContentType,
+		Width:       out.Width,
+		Height:      out.Height,
+		Blurhash:    out.Blurhash,
+		Variants:    out.Variants,
+	}
+	if err := s.images.Create(record); err != nil {
+		pkglogger.GetLogger().Warn().Err(err).Str("key", out.Key).Msg("failed to record image variants")
+	}
+}
+
+// storeContent uploads body under its content-addressed key (storage.ContentKey), or returns the object
+// already stored for the same content without uploading again (reused=true).
+// objects 가 nil 이면 기록 없이 올리기만 한다. MemberService(프로필 이미지)도 같이 쓴다.
+func storeContent(ctx context.Context, store storage.Backend, objects v2repo.MediaObjectRepository,
+	prefix, sum, ext string, body io.Reader, contentType string, size int64) (*storage.UploadResult, bool, error) {
+	now := time.Now()
+	if objects != nil {
+		existing, err := objects.Claim(sum, now)
+		if err != nil {
+			// 기록을 못 읽어도 올리기는 한다 — 같은 key 에 같은 내용이라 덮어써도 무해하다
+			pkglogger.GetLogger().Warn().Err(err).Str("sha256", sum).Msg("media object lookup failed")
+		} else if existing != nil {
+			return &storage.UploadResult{
+				Key:         existing.Key,
+				URL:         existing.URL,
+				CDNURL:      existing.URL,
+				ContentType: existing.ContentType,
+				Size:        existing.Size,
+			}, true, nil
+		}
+	}
+
+	result, err := store.Upload(ctx, storage.ContentKey(prefix, sum, ext), body, contentType, size)
+	if err != nil {
+		return nil, false, err
+	}
+	if objects == nil {
+		return result, false, nil
+	}
+	// 올린 직후에는 아무 글도 쓰지 않으므로 고아 상태로 시작한다 — 다음 media-gc 스캔이 참조를 센다
+	if _, err := objects.Register(&v2domain.V2MediaObject{
+		SHA256:         sum,
+		Key:            result.Key,
+		URL:            result.URL,
+		ContentType:    contentType,
+		Size:           size,
+		OrphanedAt:     &now,
+		LastUploadedAt: now,
+	}); err != nil {
+		// 기록이 없는 파일은 GC 대상이 아닐 뿐이다
+		pkglogger.GetLogger().Warn().Err(err).Str("key", result.Key).Msg("failed to record media object")
+	}
+	return result, false, nil
+}
+
+// reuseImageRecord fills blurhash·variants from the record of an image stored earlier with the same content.
+// 기록이 없으면 false — 파생본을 다시 만든다 (같은 key 라 덮어쓸 뿐이다).
+func (s *MediaService) reuseImageRecord(out *MediaUploadResult) bool {
+	if s.images == nil {
+		return false
+	}
+	records, err := s.images.FindByURLs([]string{out.URL})
+	if err != nil || records[out.URL] == nil {
+		return false
+	}
+	record := records[out.URL]
+	out.Width, out.Height = record.Width, record.Height
+	out.Blurhash = record.Blurhash
+	out.Variants = record.Variants
+	return true
+}
+
+// hashFile returns the SHA-256 hex digest of src and rewinds it
+func hashFile(src multipart.File) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, src); err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to reset file reader: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
 // UploadAttachment uploads a general file attachment
@@ -182,10 +379,12 @@ func (s *MediaService) UploadAttachment(ctx context.Context, file *multipart.Fil
 	if _, err := src.Seek(0, io.SeekStart); err != nil {
 		return nil, fmt.Errorf("failed to reset file reader: %w", err)
 	}
+	sum, err := hashFile(src)
+	if err != nil {
+		return nil, err
+	}
 
-	key := storage.GenerateKey("attachments", sanitizeFilename(file.Filename, ext))
-
-	result, err := s.s3.Upload(ctx, key, src, contentType, file.Size)
+	result, _, err := storeContent(ctx, s.store, s.objects, "attachments", sum, ext, src, contentType, file.Size)
 	if err != nil {
 		return nil, err
 	}
@@ -230,9 +429,12 @@ func (s *MediaService) UploadVideo(ctx context.Context, file *multipart.FileHead
 		contentType = "video/quicktime"
 	}
 
-	key := storage.GenerateKey("videos", sanitizeFilename(file.Filename, ext))
+	sum, err := hashFile(src)
+	if err != nil {
+		return nil, err
+	}
 
-	result, err := s.s3.Upload(ctx, key, src, contentType, file.Size)
+	result, _, err := storeContent(ctx, s.store, s.objects, "videos", sum, ext, src, contentType, file.Size)
 	if err != nil {
 		return nil, err
 	}
@@ -253,7 +455,7 @@ func (s *MediaService) UploadVideo(ctx context.Context, file *multipart.FileHead
 	}, nil
 }
 
-// allowedKeyPrefixes restricts which S3 paths can be deleted via API
+// allowedKeyPrefixes restricts which storage keys can be deleted via API
 var allowedKeyPrefixes = []string{"images/", "attachments/", "videos/", "editor/"}
 
 // DeleteFile removes a file from storage after validating the key prefix
@@ -268,12 +470,16 @@ func (s *MediaService) DeleteFile(ctx context.Context, key string) error {
 	if !allowed {
 		return fmt.Errorf("삭제할 수 없는 파일입니다")
 	}
-	return s.s3.Delete(ctx, key)
+	// 내용 주소 파일은 다른 글도 같이 쓸 수 있다 — 지우지 않고 참조가 없어지면 media-gc 가 치운다
+	if _, ok := storage.ContentHash(key); ok && s.objects != nil {
+		return nil
+	}
+	return s.store.Delete(ctx, key)
 }
 
 // GetCDNURL returns the CDN URL for a storage key
 func (s *MediaService) GetCDNURL(key string) string {
-	return s.s3.GetCDNURL(key)
+	return s.store.GetCDNURL(key)
 }
 
 func (s *MediaService) isAllowedExt(ext string) bool {
@@ -320,46 +526,3 @@ func isDangerousContentType(ct string) bool {
 	}
 	return false
 }
-
-func sanitizeFilename(original, ext string) string {
-	base := strings.TrimSuffix(path.Base(original), path.Ext(original))
-	// Keep only alphanumeric, Korean, dash, underscore
-	var result strings.Builder
-	for _, r := range base {
-		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
-			r == '-' || r == '_' || (r >= 0xAC00 && r <= 0xD7A3) { // Korean
-			result.WriteRune(r)
-		}
-	}
-	s := result.String()
-	if s == "" {
-		s = "file"
-	}
-	return s + ext
-}
-
-// resizeImage resizes an image to the given max width, preserving aspect ratio
-func resizeImage(img image.Image, maxWidth int) image.Image {
-	bounds := img.Bounds()
-	origWidth := bounds.Dx()
-	origHeight := bounds.Dy()
-
-	if origWidth <= maxWidth {
-		return img
-	}
-
-	newWidth := maxWidth
-	newHeight := origHeight * newWidth / origWidth
-
-	// Simple nearest-neighbor resize (good enough for thumbnails)
-	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
-	for y := 0; y < newHeight; y++ {
-		for x := 0; x < newWidth; x++ {
-			srcX := x * origWidth / newWidth
-			srcY := y * origHeight / newHeight
-			dst.Set(x, y, img.At(bounds.Min.X+srcX, bounds.Min.Y+srcY))
-		}
-	}
-
-	return dst
-}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+)
+
+// Resumable upload errors (핸들러가 HTTP 상태로 바꾼다)
+var (
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrUploadExpired        = errors.New("upload expired")
+	ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+	ErrUploadTooLarge       = errors.New("upload too large")
+	ErrUploadChunkTooLarge  = errors.New("upload chunk too large")
+	ErrUploadQuotaExceeded  = errors.New("upload quota exceeded")
+	ErrUploadIncomplete     = errors.New("upload incomplete")
+	ErrUploadRejected       = errors.New("upload rejected")
+)
+
+// Resumable upload kinds
+const (
+	UploadKindVideo      = "video"
+	UploadKindAttachment = "attachment"
+)
+
+const (
+	// MaxUploadChunkSize 는 PATCH 한 번에 받는 최대 바이트다 — 조각은 메모리에 모아 저장소에 올린다
+	MaxUploadChunkSize = 16 * 1024 * 1024
+	// UploadExpiry 는 마지막 조각 뒤 업로드를 버리기까지의 시간이다 (tus Upload-Expires)
+	UploadExpiry = 24 * time.Hour
+	// maxPendingUploads·uploadQuotaFactor — 회원당 진행 중 업로드는 5개, 선언 크기 합은 파일 한도의 4배까지
+	maxPendingUploads  = 5
+	uploadQuotaFactor  = 4
+	uploadCleanupBatch = 200
+)
+
+// SetUploadRepository enables resumable uploads (/api/v2/media/uploads)
+func (s *MediaService) SetUploadRepository(uploads v2repo.MediaUploadRepository) {
+	s.uploads = uploads
+}
+
+// ResumableUploads reports whether resumable uploads are wired
+func (s *MediaService) ResumableUploads() bool {
+	return s.uploads != nil
+}
+
+// CreateUpload starts a resumable upload of length bytes. kind 가 비면 확장자로 video·attachment 를 정한다.
+// maxFileSize 는 요금제 한도(PlanLimits.MaxFileSize)다 — 회원 할당량도 이 값에서 나온다.
+func (s *MediaService) CreateUpload(mbID, kind, filename string, length, maxFileSize int64) (*v2domain.V2MediaUpload, error) {
+	ext := strings.ToLower(path.Ext(filename))
+	if kind == "" {
+		kind = UploadKindAttachment
+		if isVideoExt(ext) {
+			kind = UploadKindVideo
+		}
+	}
+	switch kind {
+	case UploadKindVideo:
+		if !isVideoExt(ext) {
+			return nil, fmt.Errorf("%w: unsupported video format: %s", ErrUploadRejected, ext)
+		}
+	case UploadKindAttachment:
+		if !s.isAllowedExt(ext) {
+			return nil, fmt.Errorf("%w: file type not allowed: %s", ErrUploadRejected, ext)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown upload kind %q", ErrUploadRejected, kind)
+	}
+	if length <= 0 {
+		return nil, fmt.Errorf("%w: Upload-Length is required", ErrUploadRejected)
+	}
+	if length > maxFileSize {
+		return nil, fmt.Errorf("%w (max %dMB)", ErrUploadTooLarge, maxFileSize/(1024*1024))
+	}
+
+	now := time.Now()
+	count, pending, err := s.uploads.PendingUsage(mbID, now)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxPendingUploads || pending+length > uploadQuotaFactor*maxFileSize {
+		return nil, fmt.Errorf("%w (진행 중 업로드 %d개, %dMB)", ErrUploadQuotaExceeded, count, pending/(1024*1024))
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	upload := &v2domain.V2MediaUpload{
+		ID:        hex.EncodeToString(id),
+		MbID:      mbID,
+		Kind:      kind,
+		Filename:  path.Base(filename),
+		Length:    length,
+		Status:    v2domain.MediaUploadPending,
+		ExpiresAt: now.Add(UploadExpiry),
+	}
+	if err := s.uploads.Create(upload); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// UploadStatus returns the member's upload (HEAD — 이어 올릴 offset)
+func (s *MediaService) UploadStatus(mbID, id string) (*v2domain.V2MediaUpload, error) {
+	upload, err := s.uploads.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if upload == nil || upload.MbID != mbID {
+		return nil, ErrUploadNotFound
+	}
+	if upload.Status == v2domain.MediaUploadPending && !upload.ExpiresAt.After(time.Now()) {
+		return nil, ErrUploadExpired
+	}
+	return upload, nil
+}
+
+// WriteUploadChunk stores the bytes of body at offset (PATCH). 연결이 도중에 끊기면 받은 만큼은 남긴다.
+func (s *MediaService) WriteUploadChunk(ctx context.Context, mbID, id string, offset int64, body io.Reader) (*v2domain.V2MediaUpload, error) {
+	upload, err := s.UploadStatus(mbID, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status != v2domain.MediaUploadPending || offset != upload.Offset {
+		return upload, ErrUploadOffsetMismatch
+	}
+	remaining := upload.Length - upload.Offset
+	if remaining == 0 {
+		return upload, nil
+	}
+
+	// 한도보다 1 바이트 더 읽어 넘치는지 본다 — 잘라서 받지 않고 조각 전체를 거절한다
+	limit := min(remaining, MaxUploadChunkSize)
+	data, readErr := io.ReadAll(io.LimitReader(body, limit+1))
+	if int64(len(data)) > limit {
+		if limit == remaining {
+			return upload, fmt.Errorf("%w: chunk runs past Upload-Length", ErrUploadChunkTooLarge)
+		}
+		return upload, fmt.Errorf("%w (max %dMB per PATCH)", ErrUploadChunkTooLarge, MaxUploadChunkSize/(1024*1024))
+	}
+	if len(data) == 0 {
+		if readErr != nil {
+			return nil, readErr
+		}
+		return upload, nil
+	}
+
+	// 같은 offset 에 동시에 온 PATCH 가 서로의 조각을 덮지 않도록 key 에 임의 접미사를 붙인다
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("uploads/%s/%012d-%s", id, offset, hex.EncodeToString(suffix))
+	stored, err := s.store.Upload(ctx, key, bytes.NewReader(data), "application/octet-stream", int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	part := &v2domain.V2MediaUploadPart{UploadID: id, Offset: offset, Size: int64(len(data)), FileKey: stored.Key}
+	if err := s.uploads.AppendPart(part, time.Now().Add(UploadExpiry)); err != nil {
+		_ = s.store.Delete(ctx, stored.Key)
+		if errors.Is(err, v2repo.ErrUploadOffsetConflict) {
+			return upload, ErrUploadOffsetMismatch
+		}
+		return nil, err
+	}
+	if readErr != nil {
+		pkglogger.GetLogger().Info().Err(readErr).Str("upload_id", id).Int("kept", len(data)).Msg("upload chunk interrupted")
+	}
+	upload.Offset += part.Size
+	upload.ExpiresAt = time.Now().Add(UploadExpiry)
+	return upload, nil
+}
+
+// CompleteUpload joins the chunks, sniffs the content type and stores the file like UploadVideo/UploadAttachment.
+// 이미 완료된 업로드는 같은 결과를 다시 돌려준다.
+func (s *MediaService) CompleteUpload(ctx context.Context, mbID, id string) (*MediaUploadResult, error) {
+	upload, err := s.UploadStatus(mbID, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status == v2domain.MediaUploadCompleted {
+		return &MediaUploadResult{
+			Key:         upload.FileKey,
+			URL:         upload.URL,
+			CDNURL:      upload.URL,
+			Filename:    upload.Filename,
+			ContentType: upload.ContentType,
+			Size:        upload.Length,
+		}, nil
+	}
+	if upload.Offset < upload.Length {
+		return nil, fmt.Errorf("%w (%d/%d bytes)", ErrUploadIncomplete, upload.Offset, upload.Length)
+	}
+
+	parts, err := s.uploads.Parts(id)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp("", "media-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+	sum, err := s.joinUploadParts(ctx, upload, parts, tmp)
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, _ := tmp.ReadAt(head, 0)
+	sniffed := http.DetectContentType(head[:n])
+	ext := strings.ToLower(path.Ext(upload.Filename))
+	prefix, contentType := "attachments", sniffed
+	switch upload.Kind {
+	case UploadKindVideo:
+		// mov 등은 octet-stream 으로 잡힌다 — 다른 형식으로 확인된 것만 거른다
+		if !strings.HasPrefix(sniffed, "video/") && sniffed != "application/octet-stream" {
+			_ = s.discardUpload(ctx, id, parts)
+			return nil, fmt.Errorf("%w: not a video (%s)", ErrUploadRejected, sniffed)
+		}
+		prefix, contentType = "videos", "video/"+strings.TrimPrefix(ext, ".")
+		if ext == ".mov" {
+			contentType = "video/quicktime"
+		}
+	default:
+		if isDangerousContentType(sniffed) {
+			_ = s.discardUpload(ctx, id, parts)
+			return nil, fmt.Errorf("%w: potentially dangerous file type detected", ErrUploadRejected)
+		}
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	result, reused, err := storeContent(ctx, s.store, s.objects, prefix, sum, ext, tmp, contentType, upload.Length)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.uploads.Complete(id, result.Key, result.URL, contentType); err != nil {
+		return nil, err
+	}
+	for _, p := range parts {
+		_ = s.store.Delete(ctx, p.FileKey)
+	}
+
+	pkglogger.GetLogger().Info().
+		Str("key", result.Key).
+		Int64("size", upload.Length).
+		Int("parts", len(parts)).
+		Bool("dedup", reused).
+		Msg("resumable upload completed")
+
+	return &MediaUploadResult{
+		Key:         result.Key,
+		URL:         result.URL,
+		CDNURL:      result.CDNURL,
+		OriginURL:   result.OriginURL,
+		Filename:    upload.Filename,
+		ContentType: contentType,
+		Size:        upload.Length,
+	}, nil
+}
+
+// joinUploadParts copies the chunks in order into dst and returns the SHA-256 of the whole file
+func (s *MediaService) joinUploadParts(ctx context.Context, upload *v2domain.V2MediaUpload, parts []v2domain.V2MediaUploadPart, dst io.Writer) (string, error) {
+	h := sha256.New()
+	w := io.MultiWriter(dst, h)
+	var next int64
+	for _, p := range parts {
+		if p.Offset != next {
+			return "", fmt.Errorf("upload %s: missing bytes at %d", upload.ID, next)
+		}
+		obj, err := s.store.Open(ctx, p.FileKey)
+		if err != nil {
+			return "", fmt.Errorf("upload %s: open part %d: %w", upload.ID, p.Offset, err)
+		}
+		written, err := io.Copy(w, obj.Body)
+		_ = obj.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		if written != p.Size {
+			return "", fmt.Errorf("upload %s: part %d has %d bytes, want %d", upload.ID, p.Offset, written, p.Size)
+		}
+		next += p.Size
+	}
+	if next != upload.Length {
+		return "", fmt.Errorf("%w (%d/%d bytes)", ErrUploadIncomplete, next, upload.Length)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CancelUpload discards the member's upload (tus termination)
+func (s *MediaService) CancelUpload(ctx context.Context, mbID, id string) error {
+	upload, err := s.uploads.Find(id)
+	if err != nil {
+		return err
+	}
+	if upload == nil || upload.MbID != mbID {
+		return ErrUploadNotFound
+	}
+	parts, err := s.uploads.Parts(id)
+	if err != nil {
+		return err
+	}
+	return s.discardUpload(ctx, id, parts)
+}
+
+// discardUpload deletes the stored chunks and the upload rows
+func (s *MediaService) discardUpload(ctx context.Context, id string, parts []v2domain.V2MediaUploadPart) error {
+	for _, p := range parts {
+		if err := s.store.Delete(ctx, p.FileKey); err != nil {
+			pkglogger.GetLogger().Warn().Err(err).Str("key", p.FileKey).Msg("failed to delete upload part")
+		}
+	}
+	return s.uploads.Delete(id)
+}
+
+// UploadCleanupResult is the media-upload-cleanup report
+type UploadCleanupResult struct {
+	Expired int `json:"expired"`
+	Parts   int `json:"parts"`
+}
+
+// CleanupUploads removes uploads past their expiry with their chunks (media-upload-cleanup 잡).
+// 완료된 업로드는 결과 행만 남아 있다가 같이 지워진다.
+func (s *MediaService) CleanupUploads(ctx context.Context) (*UploadCleanupResult, error) {
+	result := &UploadCleanupResult{}
+	for {
+		expired, err := s.uploads.Expired(time.Now(), uploadCleanupBatch)
+		if err != nil {
+			return result, err
+		}
+		if len(expired) == 0 {
+			return result, nil
+		}
+		for _, upload := range expired {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			parts, err := s.uploads.Parts(upload.ID)
+			if err != nil {
+				return result, err
+			}
+			if err := s.discardUpload(ctx, upload.ID, parts); err != nil {
+				return result, err
+			}
+			result.Expired++
+			result.Parts += len(parts)
+		}
+	}
+}
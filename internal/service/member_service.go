@@ -3,6 +3,8 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"image"
 	"image/jpeg"
@@ -18,6 +20,7 @@ import (
 	"github.com/damoang/angple-backend/pkg/storage"
 
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
 )
 
 const (
@@ -27,18 +30,24 @@ const (
 
 // MemberService handles member profile image operations
 type MemberService struct {
-	s3         *storage.S3Client
+	store      storage.Backend
 	memberRepo gnurepo.MemberRepository
+	objects    v2repo.MediaObjectRepository // 있으면 내용 주소로 올리고 예전 이미지는 media-gc 에 맡긴다
 }
 
 // NewMemberService creates a new MemberService
-func NewMemberService(s3Client *storage.S3Client, memberRepo gnurepo.MemberRepository) *MemberService {
+func NewMemberService(store storage.Backend, memberRepo gnurepo.MemberRepository) *MemberService {
 	return &MemberService{
-		s3:         s3Client,
+		store:      store,
 		memberRepo: memberRepo,
 	}
 }
 
+// SetObjectRepository stores profile images content-addressed (MediaService 와 같은 v2_media_objects)
+func (s *MemberService) SetObjectRepository(objects v2repo.MediaObjectRepository) {
+	s.objects = objects
+}
+
 // UpdateMemberImage processes and uploads a member profile image
 func (s *MemberService) UpdateMemberImage(ctx context.Context, mbID string, file *multipart.FileHeader) (string, error) {
 	if file.Size > maxProfileImageSize {
@@ -68,13 +77,11 @@ func (s *MemberService) UpdateMemberImage(ctx context.Context, mbID string, file
 	}
 
 	// 이미지 디코딩 및 리사이즈
-	var reader io.Reader
-	var size int64
+	var body []byte
 
 	if ext == ".gif" {
 		// GIF는 리사이즈 없이 그대로 업로드
-		reader = bytes.NewReader(data)
-		size = int64(len(data))
+		body = data
 	} else {
 		img, _, decErr := image.Decode(bytes.NewReader(data))
 		if decErr != nil {
@@ -100,8 +107,7 @@ func (s *MemberService) UpdateMemberImage(ctx context.Context, mbID string, file
 			contentType = "image/jpeg"
 			ext = ".jpg"
 		}
-		reader = &buf
-		size = int64(buf.Len())
+		body = buf.Bytes()
 	}
 
 	// 기존 이미지 삭제
@@ -109,21 +115,27 @@ func (s *MemberService) UpdateMemberImage(ctx context.Context, mbID string, file
 	if err != nil {
 		return "", fmt.Errorf("회원 조회 실패: %w", err)
 	}
-	if member.MbImageUrl != "" {
-		_ = s.s3.Delete(ctx, member.MbImageUrl)
+	if member.MbImageUrl != "" && s.ownsImage(member.MbImageUrl) {
+		_ = s.store.Delete(ctx, member.MbImageUrl)
 	}
 
-	// S3 업로드
-	prefix := mbID[:2]
-	if len(mbID) < 2 {
-		prefix = mbID
+	// 저장소 업로드
+	size := int64(len(body))
+	var result *storage.UploadResult
+	if s.objects != nil {
+		sum := sha256.Sum256(body)
+		result, _, err = storeContent(ctx, s.store, s.objects, "data/member_image", hex.EncodeToString(sum[:]), ext, bytes.NewReader(body), contentType, size)
+	} else {
+		prefix := mbID[:2]
+		if len(mbID) < 2 {
+			prefix = mbID
+		}
+		key := fmt.Sprintf("data/member_image/%s/%s_%d%s",
+			strings.ToLower(prefix), mbID, time.Now().Unix(), ext)
+		result, err = s.store.Upload(ctx, key, bytes.NewReader(body), contentType, size)
 	}
-	key := fmt.Sprintf("data/member_image/%s/%s_%d%s",
-		strings.ToLower(prefix), mbID, time.Now().Unix(), ext)
-
-	result, err := s.s3.Upload(ctx, key, reader, contentType, size)
 	if err != nil {
-		return "", fmt.Errorf("S3 업로드 실패: %w", err)
+		return "", fmt.Errorf("파일 업로드 실패: %w", err)
 	}
 
 	// DB 업데이트
@@ -152,13 +164,13 @@ func (s *MemberService) DeleteMemberImage(ctx context.Context, mbID string) erro
 		return fmt.Errorf("회원 조회 실패: %w", err)
 	}
 
-	if member.MbImageUrl != "" {
-		if delErr := s.s3.Delete(ctx, member.MbImageUrl); delErr != nil {
+	if member.MbImageUrl != "" && s.ownsImage(member.MbImageUrl) {
+		if delErr := s.store.Delete(ctx, member.MbImageUrl); delErr != nil {
 			pkglogger.GetLogger().Warn().
 				Str("mb_id", mbID).
 				Str("key", member.MbImageUrl).
 				Err(delErr).
-				Msg("failed to delete profile image from S3")
+				Msg("failed to delete profile image from storage")
 		}
 	}
 
@@ -173,6 +185,13 @@ func (s *MemberService) DeleteMemberImage(ctx context.Context, mbID string) erro
 	return nil
 }
 
+// ownsImage reports whether the stored image belongs to this member alone and may be deleted right away.
+// 내용 주소 이미지는 다른 회원·글과 같은 파일일 수 있어 지우지 않는다 — 참조가 없어지면 media-gc 가 치운다.
+func (s *MemberService) ownsImage(key string) bool {
+	_, shared := storage.ContentHash(key)
+	return !shared || s.objects == nil
+}
+
 func isProfileImageExt(ext string) bool {
 	switch ext {
 	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
@@ -3,21 +3,49 @@ package service
 import (
 	"context"
 	"crypto/rand"
-	"encoding/hex"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/damoang/angple-backend/internal/common"
 	"github.com/damoang/angple-backend/internal/domain"
 	"github.com/damoang/angple-backend/pkg/jwt"
 	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+	"github.com/damoang/angple-backend/pkg/oidc"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OAuth flow and linking limits
+const (
+	// OAuthFlowTTL 은 로그인 시작부터 콜백까지 허용하는 시간이다(흐름 쿠키 수명)
+	OAuthFlowTTL      = 10 * time.Minute
+	oauthFlowAudience = "oauth-flow"
+	// TenantProviderPrefix 로 시작하는 제공자 이름은 테넌트 IdP(sso-<subdomain>)로 예약돼 있다
+	TenantProviderPrefix = "sso-"
+	syntheticUserPrefix  = "oauth_" // 연동 없이 로그인한 소셜 계정의 합성 user_id (oauth_<provider>_<uid>)
+)
+
+// OAuth errors
+var (
+	ErrUnsupportedOAuthProvider = errors.New("unsupported provider")
+	ErrOAuthState               = errors.New("invalid OAuth state")
+	ErrOAuthAccountLinked       = errors.New("이미 다른 회원에 연동된 계정입니다")
+	ErrOAuthProviderLinked      = errors.New("이 제공자에는 이미 다른 계정이 연동되어 있습니다")
+	ErrOAuthAccountNotFound     = errors.New("연동된 계정이 없습니다")
+	ErrLastLoginMethod          = errors.New("마지막 로그인 수단은 해제할 수 없습니다. 비밀번호나 패스키를 먼저 등록해주세요")
 )
 
 // OAuthService handles OAuth2 social login flows
@@ -25,6 +53,28 @@ type OAuthService struct {
 	db         *gorm.DB
 	jwtManager *jwt.Manager
 	providers  map[domain.OAuthProvider]*domain.OAuthConfig
+	// OpenID Connect 제공자 (설정 파일로 추가 — Google·Apple·GitHub·사내 IdP)
+	oidcProviders map[domain.OAuthProvider]*oidc.Provider
+
+	// 테넌트 IdP 조회 (ProvisioningService.FindIdentityProviderBySubdomain) — 디스커버리·JWKS 캐시를 살리려고
+	// 설정이 바뀌지(updated_at) 않은 동안은 같은 Provider 를 재사용한다
+	tenantIdP   func(ctx context.Context, subdomain string) (*domain.SiteIdentityProvider, error)
+	tenantMu    sync.Mutex
+	tenantCache map[string]tenantProvider
+}
+
+type tenantProvider struct {
+	provider  *oidc.Provider
+	updatedAt time.Time
+}
+
+// OAuthFlowStart is what the handler needs to send the browser to the provider
+type OAuthFlowStart struct {
+	AuthURL string
+	// Cookie 는 콜백에서 state·nonce·PKCE 를 되살리는 서명된 흐름 토큰이다(httpOnly 쿠키로 보관)
+	Cookie string
+	// FormPost 면 제공자가 콜백을 교차 사이트 POST 로 보낸다 — 쿠키를 SameSite=None 으로 둬야 실린다
+	FormPost bool
 }
 
 // NewOAuthService creates a new OAuthService
@@ -35,9 +85,11 @@ func NewOAuthService(db *gorm.DB, jwtManager *jwt.Manager) *OAuthService {
 		}
 	}
 	return &OAuthService{
-		db:         db,
-		jwtManager: jwtManager,
-		providers:  make(map[domain.OAuthProvider]*domain.OAuthConfig),
+		db:            db,
+		jwtManager:    jwtManager,
+		providers:     make(map[domain.OAuthProvider]*domain.OAuthConfig),
+		oidcProviders: make(map[domain.OAuthProvider]*oidc.Provider),
+		tenantCache:   make(map[string]tenantProvider),
 	}
 }
 
@@ -46,11 +98,117 @@ func (s *OAuthService) RegisterProvider(provider domain.OAuthProvider, cfg *doma
 	s.providers[provider] = cfg
 }
 
+// RegisterOIDCProvider registers a generic OpenID Connect (or plain OAuth2 + userinfo) provider
+func (s *OAuthService) RegisterOIDCProvider(provider domain.OAuthProvider, cfg oidc.Config) error {
+	if provider == "" || strings.ContainsAny(string(provider), "/ ") {
+		return fmt.Errorf("invalid provider name %q", provider)
+	}
+	if strings.HasPrefix(string(provider), TenantProviderPrefix) {
+		return fmt.Errorf("provider name %q uses the reserved %q prefix", provider, TenantProviderPrefix)
+	}
+	if _, ok := s.providers[provider]; ok {
+		return fmt.Errorf("provider %q is already registered", provider)
+	}
+	p, err := oidc.New(cfg)
+	if err != nil {
+		return err
+	}
+	s.oidcProviders[provider] = p
+	return nil
+}
+
+// SetTenantIdentityProviders enables sso-<subdomain> providers backed by per-tenant IdP settings
+func (s *OAuthService) SetTenantIdentityProviders(lookup func(ctx context.Context, subdomain string) (*domain.SiteIdentityProvider, error)) {
+	s.tenantIdP = lookup
+}
+
+// ProviderNames returns the globally configured providers (테넌트 IdP 는 빠진다)
+func (s *OAuthService) ProviderNames() []string {
+	names := make([]string, 0, len(s.providers)+len(s.oidcProviders))
+	for p := range s.providers {
+		names = append(names, string(p))
+	}
+	for p := range s.oidcProviders {
+		names = append(names, string(p))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// oidcProvider returns the OIDC client for provider, or nil for a legacy (naver·kakao) provider
+func (s *OAuthService) oidcProvider(ctx context.Context, provider domain.OAuthProvider) (*oidc.Provider, error) {
+	if p, ok := s.oidcProviders[provider]; ok {
+		return p, nil
+	}
+	if _, ok := s.providers[provider]; ok {
+		return nil, nil
+	}
+	subdomain, ok := strings.CutPrefix(string(provider), TenantProviderPrefix)
+	if !ok || subdomain == "" || s.tenantIdP == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedOAuthProvider, provider)
+	}
+	idp, err := s.tenantIdP(ctx, subdomain)
+	if err != nil {
+		return nil, err
+	}
+	if idp == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedOAuthProvider, provider)
+	}
+
+	s.tenantMu.Lock()
+	defer s.tenantMu.Unlock()
+	if cached, ok := s.tenantCache[subdomain]; ok && cached.updatedAt.Equal(idp.UpdatedAt) {
+		return cached.provider, nil
+	}
+	p, err := oidc.New(tenantOIDCConfig(idp))
+	if err != nil {
+		return nil, err
+	}
+	s.tenantCache[subdomain] = tenantProvider{provider: p, updatedAt: idp.UpdatedAt}
+	return p, nil
+}
+
+// flowSecret derives state, nonce and PKCE verifier from the flow id (쿠키 밖으로 나가는 건 state·nonce 뿐이다)
+func flowSecret(kind string, provider domain.OAuthProvider, flowID string) string {
+	sum := sha256.Sum256([]byte(kind + "|" + string(provider) + "|" + flowID))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// StartFlow begins an authorization code flow. linkUserID 가 있으면 로그인 대신 그 회원(mb_id)에게 연동한다.
+func (s *OAuthService) StartFlow(ctx context.Context, provider domain.OAuthProvider, linkUserID string) (*OAuthFlowStart, error) {
+	p, err := s.oidcProvider(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	flowID := base64.RawURLEncoding.EncodeToString(raw)
+	cookie, err := s.jwtManager.GenerateTicket(linkUserID, oauthFlowAudience, flowID, OAuthFlowTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	start := &OAuthFlowStart{Cookie: cookie}
+	state := flowSecret("state", provider, flowID)
+	if p == nil {
+		start.AuthURL, err = s.GetAuthURL(provider, state)
+	} else {
+		start.AuthURL, err = p.AuthCodeURL(ctx, state, flowSecret("nonce", provider, flowID), flowSecret("pkce", provider, flowID))
+		start.FormPost = p.Config().ResponseMode == "form_post"
+	}
+	if err != nil {
+		return nil, err
+	}
+	return start, nil
+}
+
 // GetAuthURL returns the OAuth authorization URL for the given provider
 func (s *OAuthService) GetAuthURL(provider domain.OAuthProvider, state string) (string, error) {
 	cfg, ok := s.providers[provider]
 	if !ok {
-		return "", fmt.Errorf("unsupported provider: %s", provider)
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedOAuthProvider, provider)
 	}
 
 	switch provider {
@@ -72,44 +230,77 @@ func (s *OAuthService) GetAuthURL(provider domain.OAuthProvider, state string) (
 		}
 		return "https://kauth.kakao.com/oauth/authorize?" + params.Encode(), nil
 
-	case domain.OAuthProviderGoogle:
-		params := url.Values{
-			"response_type": {"code"},
-			"client_id":     {cfg.ClientID},
-			"redirect_uri":  {cfg.RedirectURL},
-			"scope":         {strings.Join(cfg.Scopes, " ")},
-			"state":         {state},
-			"access_type":   {"offline"},
-		}
-		return "https://accounts.google.com/o/oauth2/v2/auth?" + params.Encode(), nil
-
 	default:
-		return "", fmt.Errorf("unsupported provider: %s", provider)
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedOAuthProvider, provider)
 	}
 }
 
-// HandleCallback exchanges the authorization code for tokens and user info
-func (s *OAuthService) HandleCallback(ctx context.Context, provider domain.OAuthProvider, code string) (*domain.OAuthLoginResponse, error) {
-	cfg, ok := s.providers[provider]
-	if !ok {
-		return nil, fmt.Errorf("unsupported provider: %s", provider)
+// HandleCallback verifies the flow cookie and state, exchanges the code and logs in or links the account.
+// 연동된 실제 회원이면 토큰 없이 Member=true 로 돌려준다 — 토큰은 회원 로그인 경로가 발급한다.
+func (s *OAuthService) HandleCallback(ctx context.Context, provider domain.OAuthProvider, flowCookie, state, code string) (*domain.OAuthLoginResponse, error) {
+	claims, err := s.jwtManager.VerifyToken(flowCookie)
+	if err != nil || !slices.Contains(claims.Audience, oauthFlowAudience) || claims.ID == "" {
+		return nil, ErrOAuthState
+	}
+	if subtle.ConstantTimeCompare([]byte(state), []byte(flowSecret("state", provider, claims.ID))) != 1 {
+		return nil, ErrOAuthState
 	}
 
-	// Exchange code for access token
-	tokenResp, err := s.exchangeCode(provider, cfg, code)
+	p, err := s.oidcProvider(ctx, provider)
 	if err != nil {
-		return nil, fmt.Errorf("token exchange failed: %w", err)
+		return nil, err
 	}
-
-	// Get user info from provider
-	accessTokenVal, ok := tokenResp["access_token"].(string)
-	if !ok {
-		return nil, fmt.Errorf("access_token not found or not a string in token response")
+	var (
+		userInfo     *domain.OAuthUserInfo
+		accessToken  string
+		refreshToken string
+	)
+	if p != nil {
+		tok, err := p.Exchange(ctx, code, flowSecret("pkce", provider, claims.ID))
+		if err != nil {
+			return nil, fmt.Errorf("token exchange failed: %w", err)
+		}
+		id, err := p.Identify(ctx, tok, flowSecret("nonce", provider, claims.ID))
+		if err != nil {
+			return nil, fmt.Errorf("get user info failed: %w", err)
+		}
+		userInfo = &domain.OAuthUserInfo{
+			Provider:     provider,
+			ProviderUID:  id.Subject,
+			Email:        id.Email,
+			Name:         id.Name,
+			ProfileImage: id.Picture,
+		}
+		accessToken, refreshToken = tok.AccessToken, tok.RefreshToken
+	} else {
+		cfg := s.providers[provider]
+		tokenResp, err := s.exchangeCode(provider, cfg, code)
+		if err != nil {
+			return nil, fmt.Errorf("token exchange failed: %w", err)
+		}
+		var ok bool
+		if accessToken, ok = tokenResp["access_token"].(string); !ok {
+			return nil, fmt.Errorf("access_token not found or not a string in token response")
+		}
+		refreshToken, _ = tokenResp["refresh_token"].(string) //nolint:errcheck // type assertion, not error
+		if userInfo, err = s.getUserInfo(provider, accessToken); err != nil {
+			return nil, fmt.Errorf("get user info failed: %w", err)
+		}
 	}
-	userInfo, err := s.getUserInfo(provider, accessTokenVal)
-	if err != nil {
-		return nil, fmt.Errorf("get user info failed: %w", err)
+
+	if claims.UserID != "" {
+		account, err := s.linkAccount(ctx, claims.UserID, userInfo, accessToken, refreshToken)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.OAuthLoginResponse{UserID: claims.UserID, Linked: account}, nil
 	}
+	return s.login(ctx, userInfo, accessToken, refreshToken)
+}
+
+// login finds or creates the account for userInfo and issues tokens for unlinked (synthetic) accounts
+func (s *OAuthService) login(ctx context.Context, userInfo *domain.OAuthUserInfo, accessTokenVal, refreshTokenVal string) (*domain.OAuthLoginResponse, error) {
+	provider := userInfo.Provider
 
 	// Find or create OAuth account
 	var oauthAccount domain.OAuthAccount
@@ -120,35 +311,27 @@ func (s *OAuthService) HandleCallback(ctx context.Context, provider domain.OAuth
 		// New OAuth user — create account
 		isNewUser = true
 		oauthAccount = domain.OAuthAccount{
-			UserID:       fmt.Sprintf("oauth_%s_%s", provider, userInfo.ProviderUID),
+			UserID:       syntheticUserID(provider, userInfo.ProviderUID),
 			Provider:     provider,
 			ProviderUID:  userInfo.ProviderUID,
 			Email:        userInfo.Email,
 			Name:         userInfo.Name,
 			ProfileImage: userInfo.ProfileImage,
 			AccessToken:  accessTokenVal,
-		}
-		if rt, ok := tokenResp["refresh_token"].(string); ok {
-			oauthAccount.RefreshToken = rt
+			RefreshToken: refreshTokenVal,
 		}
 		if err := s.db.WithContext(ctx).Create(&oauthAccount).Error; err != nil {
 			return nil, fmt.Errorf("create oauth account failed: %w", err)
 		}
 	} else if result.Error != nil {
 		return nil, result.Error
-	} else {
-		// Update tokens
-		updates := map[string]interface{}{
-			"access_token": tokenResp["access_token"],
-			"name":         userInfo.Name,
-			"email":        userInfo.Email,
-		}
-		if rt, ok := tokenResp["refresh_token"].(string); ok && rt != "" {
-			updates["refresh_token"] = rt
-		}
-		if err := s.db.WithContext(ctx).Model(&oauthAccount).Updates(updates).Error; err != nil {
-			return nil, fmt.Errorf("update oauth account failed: %w", err)
-		}
+	} else if err := s.refreshAccount(ctx, &oauthAccount, userInfo, accessTokenVal, refreshTokenVal); err != nil {
+		return nil, err
+	}
+
+	// 연동된 실제 회원 — 2단계 인증·탈퇴 숙려·세션은 회원 로그인 경로가 처리한다
+	if !strings.HasPrefix(oauthAccount.UserID, syntheticUserPrefix) {
+		return &domain.OAuthLoginResponse{UserID: oauthAccount.UserID, Member: true}, nil
 	}
 
 	// 탈퇴 게이트(방어적): 연동 계정이 확정 탈퇴(익명화)된 g5_member 라면 로그인을 차단한다.
@@ -181,6 +364,163 @@ func (s *OAuthService) HandleCallback(ctx context.Context, provider domain.OAuth
 	}, nil
 }
 
+func syntheticUserID(provider domain.OAuthProvider, providerUID string) string {
+	return fmt.Sprintf("%s%s_%s", syntheticUserPrefix, provider, providerUID)
+}
+
+// refreshAccount stores the latest tokens and profile of an existing account
+func (s *OAuthService) refreshAccount(ctx context.Context, account *domain.OAuthAccount, userInfo *domain.OAuthUserInfo, accessToken, refreshToken string) error {
+	updates := map[string]interface{}{
+		"access_token": accessToken,
+		"name":         userInfo.Name,
+		"email":        userInfo.Email,
+	}
+	if refreshToken != "" {
+		updates["refresh_token"] = refreshToken
+	}
+	if err := s.db.WithContext(ctx).Model(account).Updates(updates).Error; err != nil {
+		return fmt.Errorf("update oauth account failed: %w", err)
+	}
+	return nil
+}
+
+// linkAccount attaches the provider identity to member mbID.
+// 다른 회원에 연동된 신원은 넘겨받지 않는다 — 연동 없이 로그인해 생긴 합성 계정(oauth_<provider>_<uid>)만 옮겨 온다.
+func (s *OAuthService) linkAccount(ctx context.Context, mbID string, userInfo *domain.OAuthUserInfo, accessToken, refreshToken string) (*domain.OAuthAccount, error) {
+	var account domain.OAuthAccount
+	err := s.db.WithContext(ctx).Where("provider = ? AND provider_uid = ?", userInfo.Provider, userInfo.ProviderUID).First(&account).Error
+	switch {
+	case err == nil:
+		if account.UserID != mbID && account.UserID != syntheticUserID(userInfo.Provider, userInfo.ProviderUID) {
+			return nil, ErrOAuthAccountLinked
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+	default:
+		return nil, err
+	}
+
+	var other int64
+	if err := s.db.WithContext(ctx).Model(&domain.OAuthAccount{}).
+		Where("user_id = ? AND provider = ? AND provider_uid <> ?", mbID, userInfo.Provider, userInfo.ProviderUID).
+		Count(&other).Error; err != nil {
+		return nil, err
+	}
+	if other > 0 {
+		return nil, ErrOAuthProviderLinked
+	}
+
+	if account.ID == 0 {
+		account = domain.OAuthAccount{
+			UserID:       mbID,
+			Provider:     userInfo.Provider,
+			ProviderUID:  userInfo.ProviderUID,
+			Email:        userInfo.Email,
+			Name:         userInfo.Name,
+			ProfileImage: userInfo.ProfileImage,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+		}
+		if err := s.db.WithContext(ctx).Create(&account).Error; err != nil {
+			return nil, fmt.Errorf("create oauth account failed: %w", err)
+		}
+		return &account, nil
+	}
+	if account.UserID != mbID {
+		// 합성 계정을 회원에게 옮긴다 — 조건부 갱신이라 동시에 다른 회원이 가져가면 0행이다
+		res := s.db.WithContext(ctx).Model(&domain.OAuthAccount{}).
+			Where("id = ? AND user_id = ?", account.ID, account.UserID).
+			Update("user_id", mbID)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil, ErrOAuthAccountLinked
+		}
+		account.UserID = mbID
+	}
+	if err := s.refreshAccount(ctx, &account, userInfo, accessToken, refreshToken); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// ListAccounts returns the providers linked to member mbID
+func (s *OAuthService) ListAccounts(ctx context.Context, mbID string) ([]domain.OAuthAccount, error) {
+	var accounts []domain.OAuthAccount
+	err := s.db.WithContext(ctx).Where("user_id = ?", mbID).Order("id").Find(&accounts).Error
+	return accounts, err
+}
+
+// Unlink removes the member's account for provider, unless it is their last way to log in
+func (s *OAuthService) Unlink(ctx context.Context, mbID string, provider domain.OAuthProvider) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// ⛔ 회원의 연동 행을 모두 잠근 뒤 세고 지운다 — 두 제공자를 동시에 해제하면
+		//    서로를 "남은 수단"으로 세어 둘 다 지워지고 로그인 수단이 사라진다
+		var accounts []domain.OAuthAccount
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", mbID).Order("id").Find(&accounts).Error; err != nil {
+			return err
+		}
+		idx := slices.IndexFunc(accounts, func(a domain.OAuthAccount) bool { return a.Provider == provider })
+		if idx < 0 {
+			return ErrOAuthAccountNotFound
+		}
+		if len(accounts) == 1 {
+			remaining, err := otherLoginMethods(tx, mbID)
+			if err != nil {
+				return err
+			}
+			if remaining == 0 {
+				return ErrLastLoginMethod
+			}
+		}
+		return tx.Delete(&accounts[idx]).Error
+	})
+}
+
+// otherLoginMethods counts the ways mbID can log in without an oauth account
+// (비밀번호 + 패스키). 0 이 아니기만 하면 되므로 찾는 대로 돌려준다.
+func otherLoginMethods(db *gorm.DB, mbID string) (int64, error) {
+	migrator := db.Migrator()
+	var user struct {
+		ID       uint64
+		Password string
+	}
+	userErr := gorm.ErrRecordNotFound
+	if migrator.HasTable("v2_users") {
+		userErr = db.Table("v2_users").Select("id, password").Where("username = ?", mbID).Take(&user).Error
+		if userErr != nil && !errors.Is(userErr, gorm.ErrRecordNotFound) {
+			return 0, userErr
+		}
+	}
+	if userErr == nil {
+		if user.Password != "" {
+			return 1, nil
+		}
+		var passkeys int64
+		if migrator.HasTable("v2_webauthn_credentials") {
+			if err := db.Table("v2_webauthn_credentials").Where("user_id = ?", user.ID).Count(&passkeys).Error; err != nil {
+				return 0, err
+			}
+		}
+		return passkeys, nil
+	}
+
+	// v2_users 로 옮겨지기 전 회원 — 그누보드 비밀번호가 남아 있는지 본다
+	if !migrator.HasTable("g5_member") {
+		return 0, nil
+	}
+	var password string
+	err := db.Table("g5_member").Select("mb_password").Where("mb_id = ?", mbID).Row().Scan(&password)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	if password != "" {
+		return 1, nil
+	}
+	return 0, nil
+}
+
 // exchangeCode exchanges authorization code for access token
 func (s *OAuthService) exchangeCode(provider domain.OAuthProvider, cfg *domain.OAuthConfig, code string) (map[string]interface{}, error) {
 	var tokenURL string
@@ -201,11 +541,6 @@ func (s *OAuthService) exchangeCode(provider domain.OAuthProvider, cfg *domain.O
 		params.Set("client_id", cfg.ClientID)
 		params.Set("client_secret", cfg.ClientSecret) //nolint:gosec // credential variable name, not actual credentials
 
-	case domain.OAuthProviderGoogle:
-		tokenURL = "https://oauth2.googleapis.com/token" //nolint:gosec // credential variable name, not actual credentials
-		params.Set("client_id", cfg.ClientID)
-		params.Set("client_secret", cfg.ClientSecret) //nolint:gosec // credential variable name, not actual credentials
-
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
@@ -248,8 +583,6 @@ func (s *OAuthService) getUserInfo(provider domain.OAuthProvider, accessToken st
 		apiURL = "https://openapi.naver.com/v1/nid/me"
 	case domain.OAuthProviderKakao:
 		apiURL = "https://kapi.kakao.com/v2/user/me"
-	case domain.OAuthProviderGoogle:
-		apiURL = "https://www.googleapis.com/oauth2/v2/userinfo"
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
@@ -305,12 +638,6 @@ func (s *OAuthService) parseUserInfo(provider domain.OAuthProvider, raw map[stri
 			}
 		}
 		info.Name = info.Nickname
-
-	case domain.OAuthProviderGoogle:
-		info.ProviderUID = fmt.Sprintf("%v", raw["id"])
-		info.Email, _ = raw["email"].(string)          //nolint:errcheck // type assertion, not error
-		info.Name, _ = raw["name"].(string)            //nolint:errcheck // type assertion, not error
-		info.ProfileImage, _ = raw["picture"].(string) //nolint:errcheck // type assertion, not error
 	}
 
 	if info.ProviderUID == "" {
@@ -319,65 +646,3 @@ func (s *OAuthService) parseUserInfo(provider domain.OAuthProvider, raw map[stri
 
 	return info, nil
 }
-
-// --- API Key Management ---
-
-// GenerateAPIKey creates a new API key for a user
-func (s *OAuthService) GenerateAPIKey(ctx context.Context, userID, name, scopes string) (*domain.APIKey, error) {
-	if s.db == nil {
-		return nil, fmt.Errorf("database not available")
-	}
-	if err := s.db.AutoMigrate(&domain.APIKey{}); err != nil {
-		pkglogger.GetLogger().Warn().Err(err).Msg("failed to auto-migrate APIKey")
-	}
-
-	keyBytes := make([]byte, 32)
-	if _, err := rand.Read(keyBytes); err != nil {
-		return nil, err
-	}
-	key := "ak_" + hex.EncodeToString(keyBytes)
-
-	apiKey := &domain.APIKey{
-		Key:    key,
-		Name:   name,
-		UserID: userID,
-		Scopes: scopes,
-		Active: true,
-	}
-
-	if err := s.db.WithContext(ctx).Create(apiKey).Error; err != nil {
-		return nil, err
-	}
-
-	pkglogger.GetLogger().Info().
-		Str("user_id", userID).
-		Str("key_name", name).
-		Msg("API key generated")
-
-	return apiKey, nil
-}
-
-// ValidateAPIKey checks if a key is valid and returns the associated record
-func (s *OAuthService) ValidateAPIKey(ctx context.Context, key string) (*domain.APIKey, error) {
-	if s.db == nil {
-		return nil, fmt.Errorf("database not available")
-	}
-
-	var apiKey domain.APIKey
-	err := s.db.WithContext(ctx).Where("api_key = ? AND active = ?", key, true).First(&apiKey).Error
-	if err != nil {
-		return nil, fmt.Errorf("invalid API key")
-	}
-
-	if apiKey.ExpiresAt != nil && apiKey.ExpiresAt.Before(time.Now()) {
-		return nil, fmt.Errorf("API key expired")
-	}
-
-	// Update last used
-	now := time.Now()
-	if err := s.db.WithContext(ctx).Model(&apiKey).Update("last_used_at", now).Error; err != nil {
-		return nil, fmt.Errorf("update last used: %w", err)
-	}
-
-	return &apiKey, nil
-}
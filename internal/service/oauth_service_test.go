@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/damoang/angple-backend/pkg/oidc"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupOAuthTest(t *testing.T) (*OAuthService, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	for _, sql := range []string{
+		`CREATE TABLE g5_member (mb_id TEXT PRIMARY KEY, mb_password TEXT, mb_leave_date TEXT DEFAULT '')`,
+		`CREATE TABLE v2_users (id INTEGER PRIMARY KEY, username TEXT, password TEXT)`,
+		`CREATE TABLE v2_webauthn_credentials (id INTEGER PRIMARY KEY, user_id INTEGER)`,
+		`INSERT INTO g5_member (mb_id, mb_password) VALUES ('zoe', ''), ('legacy', '*hash'), ('bob', '')`,
+		`INSERT INTO v2_users VALUES (1, 'zoe', ''), (2, 'bob', 'bcrypt')`,
+	} {
+		if err := db.Exec(sql).Error; err != nil {
+			t.Fatalf("exec %q: %v", sql, err)
+		}
+	}
+	return NewOAuthService(db, jwt.NewManager("test-secret", 900, 3600)), db
+}
+
+func githubIdentity(uid string) *domain.OAuthUserInfo {
+	return &domain.OAuthUserInfo{Provider: "github", ProviderUID: uid, Name: "Zoe"}
+}
+
+func TestOAuthLinkAccount(t *testing.T) {
+	svc, db := setupOAuthTest(t)
+	ctx := context.Background()
+
+	// 연동 없이 로그인해 생긴 합성 계정은 회원에게 옮겨 온다
+	resp, err := svc.login(ctx, githubIdentity("77"), "at", "")
+	if err != nil || resp.Member || resp.UserID != "oauth_github_77" || resp.AccessToken == "" {
+		t.Fatalf("unlinked login: %+v %v", resp, err)
+	}
+	account, err := svc.linkAccount(ctx, "zoe", githubIdentity("77"), "at2", "")
+	if err != nil || account.UserID != "zoe" {
+		t.Fatalf("link: %+v %v", account, err)
+	}
+	// 이제 같은 신원으로 로그인하면 회원 로그인 경로로 넘긴다 (여기서는 토큰을 만들지 않는다)
+	resp, err = svc.login(ctx, githubIdentity("77"), "at3", "")
+	if err != nil || !resp.Member || resp.UserID != "zoe" || resp.AccessToken != "" {
+		t.Fatalf("linked login: %+v %v", resp, err)
+	}
+
+	// 다른 회원에 연동된 신원은 가져갈 수 없다
+	if _, err := svc.linkAccount(ctx, "bob", githubIdentity("77"), "at", ""); !errors.Is(err, ErrOAuthAccountLinked) {
+		t.Fatalf("expected ErrOAuthAccountLinked, got %v", err)
+	}
+	// 제공자당 한 계정
+	if _, err := svc.linkAccount(ctx, "zoe", githubIdentity("88"), "at", ""); !errors.Is(err, ErrOAuthProviderLinked) {
+		t.Fatalf("expected ErrOAuthProviderLinked, got %v", err)
+	}
+	// 다시 연동해도 같은 행이다
+	if again, err := svc.linkAccount(ctx, "zoe", githubIdentity("77"), "at", ""); err != nil || again.ID != account.ID {
+		t.Fatalf("relink: %+v %v", again, err)
+	}
+	var count int64
+	db.Model(&domain.OAuthAccount{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected one oauth account, got %d", count)
+	}
+}
+
+func TestOAuthUnlinkKeepsALoginMethod(t *testing.T) {
+	svc, db := setupOAuthTest(t)
+	ctx := context.Background()
+	for _, link := range []struct{ mbID, uid string }{{"zoe", "1"}, {"bob", "2"}, {"legacy", "3"}} {
+		if _, err := svc.linkAccount(ctx, link.mbID, githubIdentity(link.uid), "at", ""); err != nil {
+			t.Fatalf("link %s: %v", link.mbID, err)
+		}
+	}
+
+	// zoe 는 비밀번호도 패스키도 없다 — 유일한 로그인 수단
+	if err := svc.Unlink(ctx, "zoe", "github"); !errors.Is(err, ErrLastLoginMethod) {
+		t.Fatalf("expected ErrLastLoginMethod, got %v", err)
+	}
+	// 패스키를 등록하면 해제할 수 있다
+	db.Exec(`INSERT INTO v2_webauthn_credentials (user_id) VALUES (1)`)
+	if err := svc.Unlink(ctx, "zoe", "github"); err != nil {
+		t.Fatalf("unlink with a passkey: %v", err)
+	}
+	if err := svc.Unlink(ctx, "zoe", "github"); !errors.Is(err, ErrOAuthAccountNotFound) {
+		t.Fatalf("expected ErrOAuthAccountNotFound, got %v", err)
+	}
+
+	// v2 비밀번호가 있는 회원, v2_users 로 옮겨지기 전 그누보드 비밀번호가 있는 회원
+	if err := svc.Unlink(ctx, "bob", "github"); err != nil {
+		t.Fatalf("unlink with a password: %v", err)
+	}
+	if err := svc.Unlink(ctx, "legacy", "github"); err != nil {
+		t.Fatalf("unlink with a legacy password: %v", err)
+	}
+}
+
+func TestOAuthUnlinkLastOfSeveralLinks(t *testing.T) {
+	svc, _ := setupOAuthTest(t)
+	ctx := context.Background()
+	if _, err := svc.linkAccount(ctx, "zoe", githubIdentity("1"), "at", ""); err != nil {
+		t.Fatalf("link github: %v", err)
+	}
+	kakao := &domain.OAuthUserInfo{Provider: domain.OAuthProviderKakao, ProviderUID: "9", Name: "Zoe"}
+	if _, err := svc.linkAccount(ctx, "zoe", kakao, "at", ""); err != nil {
+		t.Fatalf("link kakao: %v", err)
+	}
+
+	// 다른 연동이 남아 있으면 해제할 수 있고, 잠근 행 기준으로 다시 세므로 마지막 하나는 지킨다
+	if err := svc.Unlink(ctx, "zoe", "github"); err != nil {
+		t.Fatalf("unlink github: %v", err)
+	}
+	if err := svc.Unlink(ctx, "zoe", domain.OAuthProviderKakao); !errors.Is(err, ErrLastLoginMethod) {
+		t.Fatalf("expected ErrLastLoginMethod, got %v", err)
+	}
+	accounts, err := svc.ListAccounts(ctx, "zoe")
+	if err != nil || len(accounts) != 1 || accounts[0].Provider != domain.OAuthProviderKakao {
+		t.Fatalf("expected kakao to remain, got %+v %v", accounts, err)
+	}
+}
+
+func TestOAuthFlowState(t *testing.T) {
+	svc, _ := setupOAuthTest(t)
+	ctx := context.Background()
+	svc.RegisterProvider(domain.OAuthProviderNaver, &domain.OAuthConfig{ClientID: "naver", RedirectURL: "https://damoang.test/cb"})
+
+	start, err := svc.StartFlow(ctx, domain.OAuthProviderNaver, "")
+	if err != nil {
+		t.Fatalf("StartFlow: %v", err)
+	}
+	u, _ := url.Parse(start.AuthURL)
+	state := u.Query().Get("state")
+	if state == "" || start.Cookie == "" || start.FormPost {
+		t.Fatalf("unexpected flow %+v", start)
+	}
+
+	// 다른 흐름의 쿠키나 다른 제공자의 콜백으로는 state 가 맞지 않는다
+	other, _ := svc.StartFlow(ctx, domain.OAuthProviderNaver, "")
+	if _, err := svc.HandleCallback(ctx, domain.OAuthProviderNaver, other.Cookie, state, "code"); !errors.Is(err, ErrOAuthState) {
+		t.Fatalf("expected ErrOAuthState for a foreign cookie, got %v", err)
+	}
+	if _, err := svc.HandleCallback(ctx, domain.OAuthProviderKakao, start.Cookie, state, "code"); !errors.Is(err, ErrOAuthState) {
+		t.Fatalf("expected ErrOAuthState for another provider, got %v", err)
+	}
+	// access token 은 흐름 쿠키로 쓸 수 없다
+	access, _ := svc.jwtManager.GenerateAccessToken("zoe", "zoe", "조", 1)
+	if _, err := svc.HandleCallback(ctx, domain.OAuthProviderNaver, access, state, "code"); !errors.Is(err, ErrOAuthState) {
+		t.Fatalf("expected ErrOAuthState for an access token, got %v", err)
+	}
+
+	if _, err := svc.StartFlow(ctx, "sso-acme", ""); !errors.Is(err, ErrUnsupportedOAuthProvider) {
+		t.Fatalf("tenant providers need a lookup, got %v", err)
+	}
+	if err := svc.RegisterOIDCProvider("sso-acme", oidc.Config{Issuer: "https://idp.test", ClientID: "c"}); err == nil {
+		t.Fatal("the sso- prefix is reserved for tenant IdPs")
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/damoang/angple-backend/internal/domain"
 	"github.com/damoang/angple-backend/internal/middleware"
 	"github.com/damoang/angple-backend/internal/repository"
+	"github.com/damoang/angple-backend/pkg/oidc"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -34,6 +36,7 @@ type ProvisioningService struct {
 	dbResolver *middleware.TenantDBResolver
 	db         *gorm.DB
 	baseDomain string // e.g. "angple.com"
+	idpRepo    *repository.SiteIdentityProviderRepository
 }
 
 // NewProvisioningService creates a new ProvisioningService
@@ -53,6 +56,11 @@ func NewProvisioningService(
 	}
 }
 
+// SetIdentityProviderRepository enables per-tenant OIDC IdP settings (nil 이면 IdP API 가 오류를 낸다)
+func (s *ProvisioningService) SetIdentityProviderRepository(repo *repository.SiteIdentityProviderRepository) {
+	s.idpRepo = repo
+}
+
 // planPricing holds pricing configuration
 var planPricing = map[string]domain.PlanPricing{
 	planFree:       {Plan: planFree, MonthlyKRW: 0, YearlyKRW: 0, TrialDays: 0},
@@ -318,6 +326,105 @@ func (s *ProvisioningService) DeleteCommunity(ctx context.Context, siteID string
 	return s.siteRepo.Delete(ctx, siteID)
 }
 
+// GetIdentityProvider returns the tenant IdP of a site
+func (s *ProvisioningService) GetIdentityProvider(ctx context.Context, siteID string) (*domain.SiteIdentityProvider, error) {
+	if s.idpRepo == nil {
+		return nil, errors.New("IdP 설정을 사용할 수 없습니다")
+	}
+	idp, err := s.idpRepo.FindBySiteID(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("IdP 조회 실패: %w", err)
+	}
+	if idp == nil {
+		return nil, errors.New("IdP 설정이 없습니다")
+	}
+	return idp, nil
+}
+
+// SetIdentityProvider creates or replaces the tenant IdP of a site.
+// 연결 확인(디스커버리)은 첫 로그인 때 한다 — 여기서는 설정 형식만 검증한다.
+func (s *ProvisioningService) SetIdentityProvider(ctx context.Context, siteID string, req *domain.SiteIdentityProviderRequest) (*domain.SiteIdentityProvider, error) {
+	if s.idpRepo == nil {
+		return nil, errors.New("IdP 설정을 사용할 수 없습니다")
+	}
+	site, err := s.siteRepo.FindByID(ctx, siteID)
+	if err != nil || site == nil {
+		return nil, errors.New("사이트를 찾을 수 없습니다")
+	}
+	idp, err := s.idpRepo.FindBySiteID(ctx, siteID)
+	if err != nil {
+		return nil, fmt.Errorf("IdP 조회 실패: %w", err)
+	}
+	if idp == nil {
+		idp = &domain.SiteIdentityProvider{SiteID: siteID, Enabled: true}
+	}
+	idp.DisplayName = strings.TrimSpace(req.DisplayName)
+	idp.Issuer = strings.TrimSpace(req.Issuer)
+	idp.ClientID = strings.TrimSpace(req.ClientID)
+	idp.RedirectURL = strings.TrimSpace(req.RedirectURL)
+	idp.Scopes = strings.Join(strings.Fields(req.Scopes), " ")
+	if req.ClientSecret != "" {
+		idp.ClientSecret = req.ClientSecret
+	}
+	if req.Enabled != nil {
+		idp.Enabled = *req.Enabled
+	}
+	if idp.DisplayName == "" {
+		idp.DisplayName = site.SiteName
+	}
+	if _, err := oidc.New(tenantOIDCConfig(idp)); err != nil {
+		return nil, fmt.Errorf("IdP 설정이 올바르지 않습니다: %w", err)
+	}
+	if err := s.idpRepo.Save(ctx, idp); err != nil {
+		return nil, fmt.Errorf("IdP 저장 실패: %w", err)
+	}
+	return idp, nil
+}
+
+// DeleteIdentityProvider removes the tenant IdP of a site.
+// 이미 연동된 계정(oauth_accounts)은 남는다 — 같은 issuer 로 다시 설정하면 그대로 이어진다.
+func (s *ProvisioningService) DeleteIdentityProvider(ctx context.Context, siteID string) error {
+	if s.idpRepo == nil {
+		return errors.New("IdP 설정을 사용할 수 없습니다")
+	}
+	deleted, err := s.idpRepo.Delete(ctx, siteID)
+	if err != nil {
+		return fmt.Errorf("IdP 삭제 실패: %w", err)
+	}
+	if !deleted {
+		return errors.New("IdP 설정이 없습니다")
+	}
+	return nil
+}
+
+// FindIdentityProviderBySubdomain returns the enabled IdP of an active site (없으면 nil).
+// OAuthService 가 sso-<subdomain> 제공자를 찾을 때 쓴다.
+func (s *ProvisioningService) FindIdentityProviderBySubdomain(ctx context.Context, subdomain string) (*domain.SiteIdentityProvider, error) {
+	if s.idpRepo == nil {
+		return nil, nil
+	}
+	site, err := s.siteRepo.FindBySubdomain(ctx, subdomain)
+	if err != nil || site == nil || !site.Active || site.Suspended {
+		return nil, err
+	}
+	idp, err := s.idpRepo.FindBySiteID(ctx, site.ID)
+	if err != nil || idp == nil || !idp.Enabled {
+		return nil, err
+	}
+	return idp, nil
+}
+
+// tenantOIDCConfig maps a stored tenant IdP to the OIDC client configuration
+func tenantOIDCConfig(idp *domain.SiteIdentityProvider) oidc.Config {
+	return oidc.Config{
+		Issuer:       idp.Issuer,
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  idp.RedirectURL,
+		Scopes:       strings.Fields(idp.Scopes),
+	}
+}
+
 func getDBStrategyByPlan(plan string) string {
 	switch plan {
 	case planFree:
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/repository"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"gorm.io/gorm"
+)
+
+// 저장한 검색 제한
+const (
+	savedSearchMaxPerMember = 20
+	savedSearchMaxName      = 100
+	savedSearchAlertBatch   = 200
+	savedSearchAlertTop     = 5
+)
+
+// Saved search errors
+var (
+	ErrSavedSearchNotFound = errors.New("saved search not found")
+	ErrSavedSearchLimit    = errors.New("saved search limit reached")
+)
+
+// SavedSearchView is the API form of a saved search
+type SavedSearchView struct {
+	*domain.SavedSearch
+	Params SearchParams `json:"params"`
+}
+
+// SavedSearchInput is the create / update body (update 는 nil 필드를 건너뛴다)
+type SavedSearchInput struct {
+	Name   *string       `json:"name"`
+	Params *SearchParams `json:"params"`
+	Notify *bool         `json:"notify"`
+}
+
+// SavedSearchAlertResult summarizes one saved-search-alerts run
+type SavedSearchAlertResult struct {
+	Checked      int `json:"checked"`
+	Matched      int `json:"matched"`
+	NotisCreated int `json:"notis_created"`
+	Errors       int `json:"errors"`
+}
+
+// SavedSearchService manages saved searches and their new-match alerts
+type SavedSearchService struct {
+	repo   *repository.SavedSearchRepository
+	search *SearchService
+	db     *gorm.DB
+}
+
+// NewSavedSearchService creates a new SavedSearchService
+func NewSavedSearchService(repo *repository.SavedSearchRepository, search *SearchService, db *gorm.DB) *SavedSearchService {
+	return &SavedSearchService{repo: repo, search: search, db: db}
+}
+
+// List returns the member's saved searches
+func (s *SavedSearchService) List(ctx context.Context, mbID string) ([]SavedSearchView, error) {
+	searches, err := s.repo.ListByMember(ctx, mbID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]SavedSearchView, 0, len(searches))
+	for i := range searches {
+		views = append(views, savedSearchView(&searches[i]))
+	}
+	return views, nil
+}
+
+// Create saves a named search for the member
+func (s *SavedSearchService) Create(ctx context.Context, mbID string, in SavedSearchInput) (*SavedSearchView, error) {
+	if in.Name == nil || in.Params == nil {
+		return nil, fmt.Errorf("%w: name and params are required", ErrInvalidSearch)
+	}
+	count, err := s.repo.CountByMember(ctx, mbID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= savedSearchMaxPerMember {
+		return nil, fmt.Errorf("%w: at most %d", ErrSavedSearchLimit, savedSearchMaxPerMember)
+	}
+
+	// 만든 시점 이후의 글만 알린다 — 저장하자마자 과거 글이 한꺼번에 알림으로 오지 않게
+	now := time.Now()
+	search := &domain.SavedSearch{MbID: mbID, LastMatchAt: &now}
+	if in.Notify != nil {
+		search.Notify = *in.Notify
+	}
+	if err := applySavedSearchInput(search, in); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, search); err != nil {
+		return nil, err
+	}
+	view := savedSearchView(search)
+	return &view, nil
+}
+
+// Update changes the name, params or notify flag of the member's saved search
+func (s *SavedSearchService) Update(ctx context.Context, mbID string, id int64, in SavedSearchInput) (*SavedSearchView, error) {
+	search, err := s.repo.FindByMember(ctx, mbID, id)
+	if err != nil {
+		return nil, err
+	}
+	if search == nil {
+		return nil, ErrSavedSearchNotFound
+	}
+	if in.Notify != nil {
+		if *in.Notify && !search.Notify {
+			// 알림을 다시 켜면 꺼져 있던 동안의 글은 건너뛴다
+			now := time.Now()
+			search.LastMatchAt = &now
+		}
+		search.Notify = *in.Notify
+	}
+	if err := applySavedSearchInput(search, in); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, search); err != nil {
+		return nil, err
+	}
+	view := savedSearchView(search)
+	return &view, nil
+}
+
+// Delete removes the member's saved search
+func (s *SavedSearchService) Delete(ctx context.Context, mbID string, id int64) error {
+	removed, err := s.repo.Delete(ctx, mbID, id)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+func applySavedSearchInput(search *domain.SavedSearch, in SavedSearchInput) error {
+	if in.Name != nil {
+		name := strings.TrimSpace(*in.Name)
+		if name == "" || len([]rune(name)) > savedSearchMaxName {
+			return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidSearch, savedSearchMaxName)
+		}
+		search.Name = name
+	}
+	if in.Params != nil {
+		if err := in.Params.Validate(); err != nil {
+			return err
+		}
+		raw, err := json.Marshal(in.Params)
+		if err != nil {
+			return err
+		}
+		search.Params = string(raw)
+	}
+	return nil
+}
+
+func savedSearchView(search *domain.SavedSearch) SavedSearchView {
+	view := SavedSearchView{SavedSearch: search}
+	_ = json.Unmarshal([]byte(search.Params), &view.Params) //nolint:errcheck // 저장 시 검증한 JSON
+	return view
+}
+
+// RunAlerts checks every notify-enabled saved search for posts written since its last alert
+// and leaves one g5_na_noti row per search (cron: saved-search-alerts).
+//
+// 알림은 글 단위가 아니라 검색 단위로 하나만 남긴다 — 인기 키워드는 15분에 수십 건이 걸려
+// 글마다 보내면 알림함이 덮인다. 댓글은 대상이 아니다(새 글 알림).
+func (s *SavedSearchService) RunAlerts(ctx context.Context) (*SavedSearchAlertResult, error) {
+	res := &SavedSearchAlertResult{}
+	var afterID int64
+	for {
+		searches, err := s.repo.ListNotifying(ctx, afterID, savedSearchAlertBatch)
+		if err != nil {
+			return res, err
+		}
+		if len(searches) == 0 {
+			return res, nil
+		}
+		for i := range searches {
+			if err := ctx.Err(); err != nil {
+				return res, err
+			}
+			res.Checked++
+			if err := s.alert(ctx, &searches[i], res); err != nil {
+				res.Errors++
+				log.Printf("[SavedSearch] alert %d (%s): %v", searches[i].ID, searches[i].MbID, err)
+			}
+		}
+		afterID = searches[len(searches)-1].ID
+	}
+}
+
+func (s *SavedSearchService) alert(ctx context.Context, search *domain.SavedSearch, res *SavedSearchAlertResult) error {
+	var params SearchParams
+	if err := json.Unmarshal([]byte(search.Params), &params); err != nil {
+		return fmt.Errorf("decode params: %w", err)
+	}
+	params.Sort = SearchSortRecent
+	q, err := s.search.BuildQuery(params, search.MbID, 1, savedSearchAlertTop)
+	if err != nil {
+		return err
+	}
+	if search.LastMatchAt != nil {
+		// created_at 은 초 단위라 마지막으로 알린 글 자체는 1초 뒤부터 잘라 뺀다
+		from := search.LastMatchAt.Truncate(time.Second).Add(time.Second)
+		if q.From == nil || from.After(*q.From) {
+			q.From = &from
+		}
+	}
+	if q.To != nil && q.From != nil && !q.To.After(*q.From) {
+		return s.repo.MarkChecked(ctx, search.ID, time.Now(), nil) // 기간이 이미 끝난 검색
+	}
+
+	found, err := s.search.SearchPosts(ctx, q)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	// 차단·탈퇴 등으로 결과가 없으면 total 이 남아도 알릴 글이 없다
+	if found.Total == 0 || len(found.Results) == 0 {
+		return s.repo.MarkChecked(ctx, search.ID, now, nil)
+	}
+	res.Matched++
+
+	top := found.Results[0].Source
+	boardID, _ := top["board_id"].(string)
+	postID := searchSourceInt(top["post_id"])
+	title, _ := top["title"].(string)
+	matchAt := now
+	if createdAt, ok := top["created_at"].(string); ok {
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", createdAt, time.Local); err == nil {
+			matchAt = t
+		}
+	}
+
+	msg := fmt.Sprintf("저장한 검색 '%s'에 새 글 %d건: %s", search.Name, found.Total, title)
+	if found.Total == 1 {
+		msg = fmt.Sprintf("저장한 검색 '%s'에 새 글: %s", search.Name, title)
+	}
+	noti := &gnurepo.Notification{
+		PhToCase: "search", PhFromCase: "saved_search", BoTable: boardID,
+		WrID: postID, MbID: search.MbID,
+		RelMsg:        msg,
+		RelURL:        "/search?" + savedSearchQueryString(&params),
+		PhReaded:      "N",
+		PhDatetime:    now,
+		ParentSubject: search.Name,
+		WrParent:      postID,
+	}
+	if err := s.db.WithContext(ctx).Create(noti).Error; err != nil {
+		return fmt.Errorf("create notification: %w", err)
+	}
+	res.NotisCreated++
+	return s.repo.MarkChecked(ctx, search.ID, now, &matchAt)
+}
+
+// savedSearchQueryString renders params back into the /search query string
+func savedSearchQueryString(p *SearchParams) string {
+	v := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("q", p.Keyword)
+	set("type", p.Type)
+	set("board_id", p.BoardID)
+	set("author", p.Author)
+	set("category", strings.Join(p.Categories, ","))
+	set("tags", strings.Join(p.Tags, ","))
+	set("from", p.From)
+	set("to", p.To)
+	if p.MinGood > 0 {
+		v.Set("min_good", strconv.Itoa(p.MinGood))
+	}
+	if p.HasImage {
+		v.Set("has_image", "true")
+	}
+	if p.HasFile {
+		v.Set("has_file", "true")
+	}
+	if p.ExcludeBlocked {
+		v.Set("exclude_blocked", "true")
+	}
+	v.Set("sort", SearchSortRecent)
+	return v.Encode()
+}
+
+// searchSourceInt reads a numeric source field (ES 는 float64, 내장 색인은 int 로 돌려준다)
+func searchSourceInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case json.Number:
+		i, _ := n.Int64() //nolint:errcheck // 0 이면 글 링크 없이 알린다
+		return int(i)
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/repository"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+)
+
+func setupSavedSearchTest(t *testing.T) (*SavedSearchService, *repository.SavedSearchRepository) {
+	t.Helper()
+	search, db := setupEmbeddedSearchTest(t)
+	if err := db.AutoMigrate(&gnurepo.Notification{}); err != nil {
+		t.Fatalf("migrate g5_na_noti: %v", err)
+	}
+	repo := repository.NewSavedSearchRepository(db)
+	if err := repo.AutoMigrate(); err != nil {
+		t.Fatalf("migrate saved_searches: %v", err)
+	}
+	return NewSavedSearchService(repo, search, db), repo
+}
+
+func TestSavedSearchCRUD(t *testing.T) {
+	svc, _ := setupSavedSearchTest(t)
+	ctx := context.Background()
+
+	name, notify := "산불 소식", true
+	created, err := svc.Create(ctx, "alice", SavedSearchInput{Name: &name, Params: &SearchParams{Keyword: "산불", Tags: []string{"재난"}}, Notify: &notify})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.LastMatchAt == nil || created.Params.Tags[0] != "재난" {
+		t.Fatalf("unexpected saved search: %+v", created)
+	}
+
+	if _, err := svc.Create(ctx, "alice", SavedSearchInput{Name: &name, Params: &SearchParams{}}); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("empty q: err = %v", err)
+	}
+	if _, err := svc.Update(ctx, "bob", created.ID, SavedSearchInput{Name: &name}); !errors.Is(err, ErrSavedSearchNotFound) {
+		t.Errorf("other member update: err = %v", err)
+	}
+	if err := svc.Delete(ctx, "bob", created.ID); !errors.Is(err, ErrSavedSearchNotFound) {
+		t.Errorf("other member delete: err = %v", err)
+	}
+
+	list, err := svc.List(ctx, "alice")
+	if err != nil || len(list) != 1 || list[0].Params.Keyword != "산불" {
+		t.Fatalf("List: %+v %v", list, err)
+	}
+	if err := svc.Delete(ctx, "alice", created.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
+
+func TestSavedSearchRunAlerts(t *testing.T) {
+	svc, repo := setupSavedSearchTest(t)
+	ctx := context.Background()
+
+	name, notify := "산불", true
+	created, err := svc.Create(ctx, "dave", SavedSearchInput{Name: &name, Params: &SearchParams{Keyword: "산불"}, Notify: &notify})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// 픽스처 글(2026-03)이 저장 이후에 올라온 것으로 되돌린다
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	if err := repo.MarkChecked(ctx, created.ID, since, &since); err != nil {
+		t.Fatalf("MarkChecked: %v", err)
+	}
+
+	res, err := svc.RunAlerts(ctx)
+	if err != nil {
+		t.Fatalf("RunAlerts: %v", err)
+	}
+	if res.Checked != 1 || res.NotisCreated != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	var noti gnurepo.Notification
+	if err := svc.db.Where("mb_id = ?", "dave").First(&noti).Error; err != nil {
+		t.Fatalf("notification: %v", err)
+	}
+	if noti.PhFromCase != "saved_search" || noti.BoTable != "free" || noti.WrID != 3 {
+		t.Errorf("unexpected notification: %+v", noti)
+	}
+
+	// 이미 알린 글은 다시 알리지 않는다
+	res, err = svc.RunAlerts(ctx)
+	if err != nil || res.NotisCreated != 0 {
+		t.Fatalf("second run: %+v %v", res, err)
+	}
+}
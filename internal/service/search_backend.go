@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	es "github.com/damoang/angple-backend/pkg/elasticsearch"
+)
+
+// Search sort orders
+const (
+	SearchSortRelevance = "relevance"
+	SearchSortRecent    = "recent"
+)
+
+// SearchQuery is a backend-neutral search request
+type SearchQuery struct {
+	Keyword  string
+	BoardIDs []string // 비어 있으면 검색 허용 게시판 전체
+	AuthorID string
+	From     *time.Time // created_at >= From
+	To       *time.Time // created_at < To
+	Sort     string     // relevance(기본) | recent
+	Page     int
+	PerPage  int
+
+	// 아래는 게시글에만 있는 조건이다 (postOnly) — 댓글 검색에는 걸 수 없다
+	Categories []string // ca_name 중 하나
+	Tags       []string // 전부 달린 글
+	MinGood    int
+	HasImage   bool
+	HasFile    bool
+
+	ExcludeAuthorIDs []string // 차단한 회원 제외
+}
+
+// postOnly reports whether the query uses filters only posts have
+func (q *SearchQuery) postOnly() bool {
+	return len(q.Categories) > 0 || len(q.Tags) > 0 || q.MinGood > 0 || q.HasImage || q.HasFile
+}
+
+func (q *SearchQuery) offset() int {
+	if q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.PerPage
+}
+
+// SearchResponse is the hit list every backend returns.
+// 프론트가 ES 응답 형태(results[].source/highlight)에 맞춰져 있어 그 형태를 그대로 쓴다.
+type SearchResponse = es.SearchResponse
+
+// SearchBackend stores and queries the post/comment documents behind SearchService.
+//
+// index 인자는 alias(PostsIndex/CommentsIndex) 또는 Reindex 가 만드는 버전 인덱스 이름이다.
+// docs 의 값은 PostDocument / CommentDocument 다.
+type SearchBackend interface {
+	Name() string
+
+	// EnsureIndex creates alias (and a versioned index behind it) when missing
+	EnsureIndex(ctx context.Context, alias string) error
+	// CreateIndex creates an empty index for documents of alias
+	CreateIndex(ctx context.Context, name, alias string) error
+	// SwapAliases points every alias at its new index in one step and returns the retired indices
+	SwapAliases(ctx context.Context, next map[string]string) ([]string, error)
+	DeleteIndex(ctx context.Context, name string) error
+	Refresh(ctx context.Context, name string) error
+
+	BulkIndex(ctx context.Context, index string, docs map[string]interface{}) error
+	DeleteDocument(ctx context.Context, index, docID string) error
+	// DeleteStale removes documents in scope whose indexed_at is older than indexedAt
+	DeleteStale(ctx context.Context, index, boardID string, scope searchScope, indexedAt int64) (int64, error)
+	// Digest returns the document count and checksum sum of one board (Reconcile)
+	Digest(ctx context.Context, index, boardID string) (boardDigest, error)
+
+	Search(ctx context.Context, alias string, q *SearchQuery) (*SearchResponse, error)
+	Suggest(ctx context.Context, prefix string, size int) ([]string, error)
+}
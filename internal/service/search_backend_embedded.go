@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	es "github.com/damoang/angple-backend/pkg/elasticsearch"
+	"github.com/damoang/angple-backend/pkg/fulltext"
+)
+
+// embeddedSearchBackend keeps the documents in an in-process n-gram index (pkg/fulltext).
+//
+// ⛔ 색인은 프로세스 메모리에만 있다. 실시간 색인은 write-after 워커가 이벤트를 집은 파드에서만
+// 일어나므로 레플리카가 여럿이면 파드마다 결과가 달라진다 — 단일 인스턴스(소규모 자체 호스팅)
+// 전용이다. 기동 시 Reindex 로 DB 에서 다시 만든다.
+type embeddedSearchBackend struct {
+	mu      sync.RWMutex
+	indices map[string]*fulltext.Index
+	aliases map[string]string
+}
+
+// NewEmbeddedSearchBackend creates an in-process SearchBackend
+func NewEmbeddedSearchBackend() SearchBackend {
+	return &embeddedSearchBackend{
+		indices: make(map[string]*fulltext.Index),
+		aliases: make(map[string]string),
+	}
+}
+
+func (b *embeddedSearchBackend) Name() string { return "embedded" }
+
+// index resolves an alias or index name
+func (b *embeddedSearchBackend) index(name string) (*fulltext.Index, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if target, ok := b.aliases[name]; ok {
+		name = target
+	}
+	ix, ok := b.indices[name]
+	if !ok {
+		return nil, fmt.Errorf("search index %s not found", name)
+	}
+	return ix, nil
+}
+
+func (b *embeddedSearchBackend) EnsureIndex(_ context.Context, alias string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.aliases[alias]; ok {
+		return nil
+	}
+	name := versionedIndexName(alias, time.Now())
+	b.indices[name] = fulltext.NewIndex()
+	b.aliases[alias] = name
+	return nil
+}
+
+func (b *embeddedSearchBackend) CreateIndex(_ context.Context, name, _ string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.indices[name]; ok {
+		return fmt.Errorf("search index %s already exists", name)
+	}
+	b.indices[name] = fulltext.NewIndex()
+	return nil
+}
+
+func (b *embeddedSearchBackend) SwapAliases(_ context.Context, next map[string]string) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, index := range next {
+		if _, ok := b.indices[index]; !ok {
+			return nil, fmt.Errorf("search index %s not found", index)
+		}
+	}
+	var retired []string
+	for alias, index := range next {
+		if old, ok := b.aliases[alias]; ok && old != index {
+			retired = append(retired, old)
+		}
+		b.aliases[alias] = index
+	}
+	return retired, nil
+}
+
+func (b *embeddedSearchBackend) DeleteIndex(_ context.Context, name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.indices, name)
+	return nil
+}
+
+func (b *embeddedSearchBackend) Refresh(context.Context, string) error { return nil }
+
+func (b *embeddedSearchBackend) BulkIndex(_ context.Context, index string, docs map[string]interface{}) error {
+	ix, err := b.index(index)
+	if err != nil {
+		return err
+	}
+	for id, doc := range docs {
+		d, err := embeddedDocument(id, doc)
+		if err != nil {
+			return err
+		}
+		ix.Put(d)
+	}
+	return nil
+}
+
+func (b *embeddedSearchBackend) DeleteDocument(_ context.Context, index, docID string) error {
+	ix, err := b.index(index)
+	if err != nil {
+		return err
+	}
+	ix.Delete(docID)
+	return nil
+}
+
+func (b *embeddedSearchBackend) DeleteStale(_ context.Context, index, boardID string, scope searchScope, indexedAt int64) (int64, error) {
+	ix, err := b.index(index)
+	if err != nil {
+		return 0, err
+	}
+	removed := ix.DeleteWhere(func(d *fulltext.Document) bool {
+		return inScope(d, boardID, scope) && d.Numbers["indexed_at"] < indexedAt
+	})
+	return int64(removed), nil
+}
+
+func (b *embeddedSearchBackend) Digest(_ context.Context, index, boardID string) (boardDigest, error) {
+	ix, err := b.index(index)
+	if err != nil {
+		return boardDigest{}, err
+	}
+	var digest boardDigest
+	ix.Each(func(d *fulltext.Document) bool {
+		if d.Keywords["board_id"] == boardID {
+			digest.Count++
+			digest.Sum += d.Numbers["checksum"]
+		}
+		return true
+	})
+	return digest, nil
+}
+
+func inScope(d *fulltext.Document, boardID string, scope searchScope) bool {
+	if d.Keywords["board_id"] != boardID {
+		return false
+	}
+	if scope.PostID > 0 && d.Numbers["post_id"] != int64(scope.PostID) {
+		return false
+	}
+	if scope.CommentID > 0 && d.Numbers["comment_id"] != int64(scope.CommentID) {
+		return false
+	}
+	if scope.AuthorID != "" && d.Keywords["author_id"] != scope.AuthorID {
+		return false
+	}
+	return true
+}
+
+func boolNumber(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// embeddedDocument converts a PostDocument / CommentDocument into an index document
+func embeddedDocument(id string, doc interface{}) (fulltext.Document, error) {
+	d := fulltext.Document{ID: id, Text: map[string]string{}, Keywords: map[string]string{}, Numbers: map[string]int64{}}
+	var createdAt string
+	switch v := doc.(type) {
+	case PostDocument:
+		v.TitleSuggest = nil
+		d.Text["title"], d.Text["content"], d.Text["author"] = v.Title, v.Content, v.Author
+		d.Keywords["board_id"], d.Keywords["author_id"], d.Keywords["category"] = v.BoardID, v.AuthorID, v.Category
+		d.Numbers["post_id"], d.Numbers["checksum"], d.Numbers["indexed_at"] = int64(v.PostID), int64(v.Checksum), v.IndexedAt
+		d.Numbers["good"], d.Numbers["has_image"], d.Numbers["has_file"] = int64(v.Good), boolNumber(v.HasImage), boolNumber(v.HasFile)
+		d.Lists = map[string][]string{"tags": v.Tags}
+		createdAt, doc = v.CreatedAt, v
+	case *PostDocument:
+		return embeddedDocument(id, *v)
+	case CommentDocument:
+		d.Text["content"], d.Text["author"] = v.Content, v.Author
+		d.Keywords["board_id"], d.Keywords["author_id"] = v.BoardID, v.AuthorID
+		d.Numbers["post_id"], d.Numbers["comment_id"] = int64(v.PostID), int64(v.CommentID)
+		d.Numbers["checksum"], d.Numbers["indexed_at"] = int64(v.Checksum), v.IndexedAt
+		createdAt = v.CreatedAt
+	case *CommentDocument:
+		return embeddedDocument(id, *v)
+	default:
+		return d, fmt.Errorf("unsupported search document %T", doc)
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", createdAt, time.Local); err == nil {
+		d.Numbers["created_at"] = t.Unix()
+	}
+
+	// 검색 결과의 source 는 ES _source 와 같은 JSON 모양으로 돌려준다
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return d, err
+	}
+	if err := json.Unmarshal(raw, &d.Source); err != nil {
+		return d, err
+	}
+	return d, nil
+}
+
+func (b *embeddedSearchBackend) Search(_ context.Context, alias string, q *SearchQuery) (*SearchResponse, error) {
+	ix, err := b.index(alias)
+	if err != nil {
+		return nil, err
+	}
+
+	boards := make(map[string]bool, len(q.BoardIDs))
+	for _, id := range q.BoardIDs {
+		boards[id] = true
+	}
+	fq := fulltext.Query{
+		Text:     q.Keyword,
+		Filter:   embeddedFilter(q, boards),
+		TieBreak: "created_at",
+		Offset:   q.offset(),
+		Limit:    q.PerPage,
+		PreTag:   "<mark>",
+		PostTag:  "</mark>",
+	}
+	if q.Sort == SearchSortRecent {
+		fq.SortBy = "created_at"
+	}
+	if alias == CommentsIndex {
+		fq.Fields = map[string]float64{"content": 1}
+		fq.Highlight = map[string]fulltext.HighlightOptions{"content": {FragmentSize: 200, Fragments: 3}}
+	} else {
+		fq.Fields = map[string]float64{"title": 3, "content": 1, "author": 1}
+		fq.Highlight = map[string]fulltext.HighlightOptions{
+			"title":   {},
+			"content": {FragmentSize: 150, Fragments: 3},
+		}
+		fq.Facets = map[string]func(*fulltext.Document) []string{
+			"board":    func(d *fulltext.Document) []string { return []string{d.Keywords["board_id"]} },
+			"category": func(d *fulltext.Document) []string { return nonEmpty(d.Keywords["category"]) },
+			"month": func(d *fulltext.Document) []string {
+				if created := d.Numbers["created_at"]; created > 0 {
+					return []string{time.Unix(created, 0).Format("2006-01")}
+				}
+				return nil
+			},
+		}
+	}
+
+	found := ix.Search(fq)
+	resp := &SearchResponse{Total: int64(found.Total), Facets: embeddedFacets(found.Facets)}
+	for _, hit := range found.Hits {
+		resp.Results = append(resp.Results, es.SearchResult{
+			ID:        hit.Doc.ID,
+			Score:     hit.Score,
+			Source:    hit.Doc.Source,
+			Highlight: hit.Highlight,
+		})
+	}
+	return resp, nil
+}
+
+func (b *embeddedSearchBackend) Suggest(_ context.Context, prefix string, size int) ([]string, error) {
+	ix, err := b.index(PostsIndex)
+	if err != nil {
+		return nil, err
+	}
+	return ix.Suggest("title", prefix, size), nil
+}
+
+// embeddedFilter applies the structured conditions of q
+func embeddedFilter(q *SearchQuery, boards map[string]bool) func(*fulltext.Document) bool {
+	var from, to int64
+	if q.From != nil {
+		from = q.From.Unix()
+	}
+	if q.To != nil {
+		to = q.To.Unix()
+	}
+	categories := make(map[string]bool, len(q.Categories))
+	for _, c := range q.Categories {
+		categories[c] = true
+	}
+	excluded := make(map[string]bool, len(q.ExcludeAuthorIDs))
+	for _, id := range q.ExcludeAuthorIDs {
+		excluded[id] = true
+	}
+	return func(d *fulltext.Document) bool {
+		if len(boards) > 0 && !boards[d.Keywords["board_id"]] {
+			return false
+		}
+		if q.AuthorID != "" && d.Keywords["author_id"] != q.AuthorID {
+			return false
+		}
+		if excluded[d.Keywords["author_id"]] {
+			return false
+		}
+		created := d.Numbers["created_at"]
+		if (from != 0 && created < from) || (to != 0 && created >= to) {
+			return false
+		}
+		if len(categories) > 0 && !categories[d.Keywords["category"]] {
+			return false
+		}
+		for _, tag := range q.Tags {
+			if !slices.Contains(d.Lists["tags"], tag) {
+				return false
+			}
+		}
+		if q.MinGood > 0 && d.Numbers["good"] < int64(q.MinGood) {
+			return false
+		}
+		if q.HasImage && d.Numbers["has_image"] == 0 {
+			return false
+		}
+		return !q.HasFile || d.Numbers["has_file"] == 1
+	}
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+// embeddedFacets converts counts into buckets ordered like Elasticsearch
+// (terms 는 건수 내림차순 상위 searchFacetSize 개, month 는 시간순)
+func embeddedFacets(counts map[string]map[string]int) map[string][]es.FacetBucket {
+	if counts == nil {
+		return nil
+	}
+	facets := make(map[string][]es.FacetBucket, len(counts))
+	for name, byKey := range counts {
+		buckets := make([]es.FacetBucket, 0, len(byKey))
+		for key, n := range byKey {
+			buckets = append(buckets, es.FacetBucket{Key: key, Count: int64(n)})
+		}
+		sort.Slice(buckets, func(i, j int) bool {
+			if name == "month" {
+				return buckets[i].Key < buckets[j].Key
+			}
+			if buckets[i].Count != buckets[j].Count {
+				return buckets[i].Count > buckets[j].Count
+			}
+			return buckets[i].Key < buckets[j].Key
+		})
+		if name != "month" && len(buckets) > searchFacetSize {
+			buckets = buckets[:searchFacetSize]
+		}
+		facets[name] = buckets
+	}
+	return facets
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupEmbeddedSearchTest(t *testing.T) (*SearchService, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	for _, sql := range []string{
+		`CREATE TABLE g5_board (bo_table TEXT PRIMARY KEY, bo_use_search INTEGER)`,
+		`CREATE TABLE g5_write_free (
+			wr_id INTEGER PRIMARY KEY, wr_parent INTEGER, wr_is_comment INTEGER,
+			wr_subject TEXT, wr_content TEXT, wr_name TEXT, mb_id TEXT, ca_name TEXT,
+			wr_datetime TEXT, wr_hit INTEGER, wr_good INTEGER, wr_file INTEGER DEFAULT 0, wr_option TEXT, wr_deleted_at TEXT)`,
+		`CREATE TABLE g5_na_tag_log (id INTEGER PRIMARY KEY, bo_table TEXT, wr_id INTEGER, tag TEXT)`,
+		`INSERT INTO g5_board VALUES ('free', 1)`,
+		`INSERT INTO g5_write_free VALUES (1, 1, 0, '강원 산불 소식', '<p>산불이 번지고 있습니다</p><img src="a.jpg">', '앙플', 'alice', '뉴스', '2026-03-01 10:00:00', 0, 12, 0, '', NULL)`,
+		`INSERT INTO g5_write_free VALUES (2, 2, 0, '생산 불가 공지', '부품 생산 불가', '운영자', 'bob', '공지', '2026-03-05 10:00:00', 0, 0, 0, '', NULL)`,
+		`INSERT INTO g5_write_free VALUES (3, 3, 0, '산불 대피 요령', '산불 대피는 바람 방향을 보고', '운영자', 'bob', '정보', '2026-04-10 10:00:00', 0, 3, 1, '', NULL)`,
+		`INSERT INTO g5_write_free VALUES (4, 1, 1, '', '산불 진화 응원합니다', '댓글러', 'carol', '', '2026-03-02 10:00:00', 0, 0, 0, '', NULL)`,
+		`INSERT INTO g5_na_tag_log (bo_table, wr_id, tag) VALUES ('free', 1, '강원'), ('free', 1, '재난'), ('free', 3, '재난')`,
+	} {
+		if err := db.Exec(sql).Error; err != nil {
+			t.Fatalf("exec %q: %v", sql, err)
+		}
+	}
+
+	svc := NewSearchService(NewEmbeddedSearchBackend(), db)
+	for _, postID := range []int{1, 2, 3} {
+		if err := svc.SyncThread(context.Background(), "free", postID); err != nil {
+			t.Fatalf("SyncThread(%d): %v", postID, err)
+		}
+	}
+	return svc, db
+}
+
+func TestEmbeddedSearchPostsAndComments(t *testing.T) {
+	svc, _ := setupEmbeddedSearchTest(t)
+	ctx := context.Background()
+
+	posts, err := svc.SearchPosts(ctx, SearchQuery{Keyword: "산불", Sort: SearchSortRecent, Page: 1, PerPage: 10})
+	if err != nil {
+		t.Fatalf("SearchPosts: %v", err)
+	}
+	if posts.Total != 2 || posts.Results[0].ID != "free_3" || posts.Results[1].ID != "free_1" {
+		t.Fatalf("unexpected posts: %+v", posts)
+	}
+	if got := posts.Results[1].Highlight["title"]; len(got) != 1 || got[0] != "강원 <mark>산불</mark> 소식" {
+		t.Errorf("title highlight = %q", got)
+	}
+	if posts.Results[1].Source["author_id"] != "alice" {
+		t.Errorf("source = %+v", posts.Results[1].Source)
+	}
+
+	comments, err := svc.SearchComments(ctx, SearchQuery{Keyword: "진화", Page: 1, PerPage: 10})
+	if err != nil {
+		t.Fatalf("SearchComments: %v", err)
+	}
+	if comments.Total != 1 || comments.Results[0].ID != "free_1_4" {
+		t.Fatalf("unexpected comments: %+v", comments)
+	}
+}
+
+func TestEmbeddedSearchFilters(t *testing.T) {
+	svc, _ := setupEmbeddedSearchTest(t)
+	ctx := context.Background()
+
+	res, err := svc.SearchPosts(ctx, SearchQuery{Keyword: "산불", AuthorID: "bob", Page: 1, PerPage: 10})
+	if err != nil || res.Total != 1 || res.Results[0].ID != "free_3" {
+		t.Fatalf("author filter: %+v %v", res, err)
+	}
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2026, 3, 5, 0, 0, 0, 0, time.Local)
+	res, err = svc.SearchPosts(ctx, SearchQuery{Keyword: "산불", From: &from, To: &to, Page: 1, PerPage: 10})
+	if err != nil || res.Total != 1 || res.Results[0].ID != "free_1" {
+		t.Fatalf("date filter: %+v %v", res, err)
+	}
+
+	res, err = svc.SearchPosts(ctx, SearchQuery{Keyword: "산불", BoardIDs: []string{"qa"}, Page: 1, PerPage: 10})
+	if err != nil || res.Total != 0 {
+		t.Fatalf("board filter: %+v %v", res, err)
+	}
+}
+
+func TestEmbeddedSearchAdvancedFilters(t *testing.T) {
+	svc, _ := setupEmbeddedSearchTest(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		q    SearchQuery
+		want []string
+	}{
+		{"category", SearchQuery{Categories: []string{"정보", "공지"}}, []string{"free_3"}},
+		{"tags all", SearchQuery{Tags: []string{"재난", "강원"}}, []string{"free_1"}},
+		{"tag", SearchQuery{Tags: []string{"재난"}}, []string{"free_3", "free_1"}},
+		{"min good", SearchQuery{MinGood: 10}, []string{"free_1"}},
+		{"has image", SearchQuery{HasImage: true}, []string{"free_1"}},
+		{"has file", SearchQuery{HasFile: true}, []string{"free_3"}},
+		{"exclude authors", SearchQuery{ExcludeAuthorIDs: []string{"bob"}}, []string{"free_1"}},
+	}
+	for _, tc := range cases {
+		tc.q.Keyword, tc.q.Sort, tc.q.Page, tc.q.PerPage = "산불", SearchSortRecent, 1, 10
+		res, err := svc.SearchPosts(ctx, tc.q)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var got []string
+		for _, r := range res.Results {
+			got = append(got, r.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestEmbeddedSearchFacets(t *testing.T) {
+	svc, _ := setupEmbeddedSearchTest(t)
+
+	res, err := svc.SearchPosts(context.Background(), SearchQuery{Keyword: "산불", Page: 1, PerPage: 1})
+	if err != nil {
+		t.Fatalf("SearchPosts: %v", err)
+	}
+	if len(res.Results) != 1 || res.Total != 2 {
+		t.Fatalf("unexpected page: %+v", res)
+	}
+	// 패싯은 페이지가 아니라 일치하는 글 전체를 센다
+	if got := res.Facets["board"]; len(got) != 1 || got[0].Key != "free" || got[0].Count != 2 {
+		t.Errorf("board facet = %+v", got)
+	}
+	if got := res.Facets["month"]; len(got) != 2 || got[0].Key != "2026-03" || got[1].Key != "2026-04" {
+		t.Errorf("month facet = %+v", got)
+	}
+	if got := res.Facets["category"]; len(got) != 2 {
+		t.Errorf("category facet = %+v", got)
+	}
+}
+
+func TestUnifiedSearchPostOnlyFilters(t *testing.T) {
+	svc, _ := setupEmbeddedSearchTest(t)
+	ctx := context.Background()
+
+	if _, err := svc.UnifiedSearch(ctx, SearchQuery{Keyword: "산불", MinGood: 1, Page: 1, PerPage: 10}, "comments"); !errors.Is(err, ErrInvalidSearch) {
+		t.Fatalf("comments with post-only filter: err = %v", err)
+	}
+	res, err := svc.UnifiedSearch(ctx, SearchQuery{Keyword: "산불", MinGood: 1, Page: 1, PerPage: 10}, "all")
+	if err != nil {
+		t.Fatalf("UnifiedSearch: %v", err)
+	}
+	posts, comments := res["posts"].(*SearchResponse), res["comments"].(*SearchResponse)
+	if posts.Total != 2 || comments.Total != 0 {
+		t.Errorf("unexpected unified result: posts=%d comments=%d", posts.Total, comments.Total)
+	}
+}
+
+func TestEmbeddedSearchSyncRemovesDeletedThread(t *testing.T) {
+	svc, db := setupEmbeddedSearchTest(t)
+	ctx := context.Background()
+
+	if err := db.Exec(`UPDATE g5_write_free SET wr_deleted_at = '2026-03-11 00:00:00' WHERE wr_id = 1`).Error; err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	if err := svc.SyncThread(ctx, "free", 1); err != nil {
+		t.Fatalf("SyncThread: %v", err)
+	}
+
+	posts, _ := svc.SearchPosts(ctx, SearchQuery{Keyword: "산불", Page: 1, PerPage: 10})
+	comments, _ := svc.SearchComments(ctx, SearchQuery{Keyword: "진화", Page: 1, PerPage: 10})
+	if posts.Total != 1 || comments.Total != 0 {
+		t.Fatalf("deleted thread still searchable: posts=%d comments=%d", posts.Total, comments.Total)
+	}
+}
+
+func TestEmbeddedSearchReindexSwapsAlias(t *testing.T) {
+	svc, _ := setupEmbeddedSearchTest(t)
+	backend := svc.backend.(*embeddedSearchBackend)
+	before := backend.aliases[PostsIndex]
+
+	status := &SearchReindexResult{
+		PostsIndex:    versionedIndexName(PostsIndex, time.Now().Add(time.Second)),
+		CommentsIndex: versionedIndexName(CommentsIndex, time.Now().Add(time.Second)),
+	}
+	// 재색인 후 정합성 검사(CRC32)는 MySQL 전용이라 sqlite 에서는 catch-up 단계만 실패한다
+	_ = svc.reindex(context.Background(), status) //nolint:errcheck // see above
+
+	if backend.aliases[PostsIndex] == before || backend.aliases[PostsIndex] != status.PostsIndex {
+		t.Fatalf("alias not swapped: %s", backend.aliases[PostsIndex])
+	}
+	if _, ok := backend.indices[before]; ok {
+		t.Errorf("retired index %s should be dropped", before)
+	}
+	if status.Posts != 3 || status.Comments != 1 {
+		t.Errorf("unexpected reindex counts: %+v", status)
+	}
+	res, err := svc.SearchPosts(context.Background(), SearchQuery{Keyword: "산불", Page: 1, PerPage: 10})
+	if err != nil || res.Total != 2 {
+		t.Fatalf("search after reindex: %+v %v", res, err)
+	}
+}
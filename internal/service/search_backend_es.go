@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	es "github.com/damoang/angple-backend/pkg/elasticsearch"
+)
+
+// esSearchBackend keeps the documents in Elasticsearch (nori 분석기)
+type esSearchBackend struct {
+	client *es.Client
+}
+
+// NewElasticsearchBackend creates a SearchBackend on an Elasticsearch cluster
+func NewElasticsearchBackend(client *es.Client) SearchBackend {
+	return &esSearchBackend{client: client}
+}
+
+func (b *esSearchBackend) Name() string { return "elasticsearch" }
+
+// koreanAnalysis is the shared nori analyzer settings
+func koreanAnalysis() map[string]interface{} {
+	return map[string]interface{}{
+		"analysis": map[string]interface{}{
+			"analyzer": map[string]interface{}{
+				"korean": map[string]interface{}{
+					"type":      "custom",
+					"tokenizer": "nori_tokenizer",
+					"filter":    []string{"nori_readingform", "lowercase"},
+				},
+			},
+		},
+	}
+}
+
+// createdAtMapping accepts wr_datetime as stored ("2006-01-02 15:04:05") as well as ISO dates
+func createdAtMapping() map[string]interface{} {
+	return map[string]interface{}{"type": "date", "format": "yyyy-MM-dd HH:mm:ss||strict_date_optional_time||epoch_millis"}
+}
+
+// postIndexMapping returns the posts index settings and mappings.
+// 필드를 추가하면 기존 인덱스에는 동적 매핑으로 잘못 잡히므로 /admin/search/reindex 로 새로 만든다.
+func postIndexMapping() map[string]interface{} {
+	return map[string]interface{}{
+		"settings": koreanAnalysis(),
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"board_id":   map[string]interface{}{"type": "keyword"},
+				"post_id":    map[string]interface{}{"type": "integer"},
+				"title":      map[string]interface{}{"type": "text", "analyzer": "korean", "search_analyzer": "korean"},
+				"content":    map[string]interface{}{"type": "text", "analyzer": "korean", "search_analyzer": "korean"},
+				"author":     map[string]interface{}{"type": "text", "fields": map[string]interface{}{"keyword": map[string]interface{}{"type": "keyword"}}},
+				"author_id":  map[string]interface{}{"type": "keyword"},
+				"category":   map[string]interface{}{"type": "keyword"},
+				"created_at": createdAtMapping(),
+				"views":      map[string]interface{}{"type": "integer"},
+				"good":       map[string]interface{}{"type": "integer"},
+				"tags":       map[string]interface{}{"type": "keyword"},
+				"has_image":  map[string]interface{}{"type": "boolean"},
+				"has_file":   map[string]interface{}{"type": "boolean"},
+				"checksum":   map[string]interface{}{"type": "long"},
+				"indexed_at": map[string]interface{}{"type": "long"},
+				"title_suggest": map[string]interface{}{
+					"type":            "completion",
+					"analyzer":        "korean",
+					"search_analyzer": "korean",
+				},
+			},
+		},
+	}
+}
+
+// commentIndexMapping returns the comments index settings and mappings
+func commentIndexMapping() map[string]interface{} {
+	return map[string]interface{}{
+		"settings": koreanAnalysis(),
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"board_id":   map[string]interface{}{"type": "keyword"},
+				"post_id":    map[string]interface{}{"type": "integer"},
+				"comment_id": map[string]interface{}{"type": "integer"},
+				"content":    map[string]interface{}{"type": "text", "analyzer": "korean", "search_analyzer": "korean"},
+				"author":     map[string]interface{}{"type": "text", "fields": map[string]interface{}{"keyword": map[string]interface{}{"type": "keyword"}}},
+				"author_id":  map[string]interface{}{"type": "keyword"},
+				"created_at": createdAtMapping(),
+				"checksum":   map[string]interface{}{"type": "long"},
+				"indexed_at": map[string]interface{}{"type": "long"},
+			},
+		},
+	}
+}
+
+func indexMappingFor(alias string) map[string]interface{} {
+	if alias == CommentsIndex {
+		return commentIndexMapping()
+	}
+	return postIndexMapping()
+}
+
+// EnsureIndex creates the index with Korean nori analyzer mappings.
+// 새로 만들 때는 버전 인덱스(angple_posts_v...) + alias 로 만들어 이후 재색인을 alias 교체로 끝낼 수 있게 한다.
+// 예전 방식의 실제 인덱스가 이미 있으면 그대로 두고, 첫 Reindex 가 alias 로 바꿔 준다.
+func (b *esSearchBackend) EnsureIndex(ctx context.Context, alias string) error {
+	exists, err := b.client.IndexExists(ctx, alias)
+	if err != nil {
+		return fmt.Errorf("check %s: %w", alias, err)
+	}
+	if exists {
+		return nil
+	}
+	versioned := versionedIndexName(alias, time.Now())
+	if err := b.client.CreateIndex(ctx, versioned, indexMappingFor(alias)); err != nil {
+		return fmt.Errorf("create %s index: %w", alias, err)
+	}
+	if err := b.client.UpdateAliases(ctx, []map[string]interface{}{
+		{"add": map[string]interface{}{"index": versioned, "alias": alias}},
+	}); err != nil {
+		return fmt.Errorf("alias %s: %w", alias, err)
+	}
+	return nil
+}
+
+func (b *esSearchBackend) CreateIndex(ctx context.Context, name, alias string) error {
+	return b.client.CreateIndex(ctx, name, indexMappingFor(alias))
+}
+
+// SwapAliases moves every alias in one _aliases call so search never sees a half-swapped state
+func (b *esSearchBackend) SwapAliases(ctx context.Context, next map[string]string) ([]string, error) {
+	var actions []map[string]interface{}
+	var retired []string
+	for alias, index := range next {
+		old, err := b.client.AliasIndices(ctx, alias)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, map[string]interface{}{"add": map[string]interface{}{"index": index, "alias": alias}})
+		if old == nil {
+			// alias 가 아니라 예전 방식의 실제 인덱스 — 같은 요청 안에서 지워야 이름을 alias 로 쓸 수 있다
+			if exists, err := b.client.IndexExists(ctx, alias); err != nil {
+				return nil, err
+			} else if exists {
+				actions = append(actions, map[string]interface{}{"remove_index": map[string]interface{}{"index": alias}})
+			}
+			continue
+		}
+		for _, o := range old {
+			actions = append(actions, map[string]interface{}{"remove": map[string]interface{}{"index": o, "alias": alias}})
+			retired = append(retired, o)
+		}
+	}
+	if err := b.client.UpdateAliases(ctx, actions); err != nil {
+		return nil, err
+	}
+	return retired, nil
+}
+
+func (b *esSearchBackend) DeleteIndex(ctx context.Context, name string) error {
+	return b.client.DeleteIndex(ctx, name)
+}
+
+func (b *esSearchBackend) Refresh(ctx context.Context, name string) error {
+	return b.client.Refresh(ctx, name)
+}
+
+func (b *esSearchBackend) BulkIndex(ctx context.Context, index string, docs map[string]interface{}) error {
+	return b.client.BulkIndex(ctx, index, docs)
+}
+
+func (b *esSearchBackend) DeleteDocument(ctx context.Context, index, docID string) error {
+	return b.client.DeleteDocument(ctx, index, docID)
+}
+
+func (b *esSearchBackend) DeleteStale(ctx context.Context, index, boardID string, scope searchScope, indexedAt int64) (int64, error) {
+	return b.client.DeleteByQuery(ctx, index, staleQuery(boardID, scope, indexedAt))
+}
+
+func (b *esSearchBackend) Digest(ctx context.Context, index, boardID string) (boardDigest, error) {
+	count, sum, err := b.client.CountAndSum(ctx, index, map[string]interface{}{
+		"bool": map[string]interface{}{"filter": scopeFilter(boardID, searchScope{})},
+	}, "checksum")
+	return boardDigest{Count: count, Sum: int64(math.Round(sum))}, err
+}
+
+// scopeFilter builds the ES filter matching the scope
+func scopeFilter(boardID string, scope searchScope) []map[string]interface{} {
+	filter := []map[string]interface{}{
+		{"term": map[string]interface{}{"board_id": boardID}},
+	}
+	if scope.PostID > 0 {
+		filter = append(filter, map[string]interface{}{"term": map[string]interface{}{"post_id": scope.PostID}})
+	}
+	if scope.CommentID > 0 {
+		filter = append(filter, map[string]interface{}{"term": map[string]interface{}{"comment_id": scope.CommentID}})
+	}
+	if scope.AuthorID != "" {
+		filter = append(filter, map[string]interface{}{"term": map[string]interface{}{"author_id": scope.AuthorID}})
+	}
+	return filter
+}
+
+// staleQuery matches documents in scope that the re-sync started at indexedAt did not rewrite.
+// indexed_at 이 없는 예전 문서도 잡히도록 must_not range 로 쓴다.
+func staleQuery(boardID string, scope searchScope, indexedAt int64) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": scopeFilter(boardID, scope),
+			"must_not": []map[string]interface{}{
+				{"range": map[string]interface{}{"indexed_at": map[string]interface{}{"gte": indexedAt}}},
+			},
+		},
+	}
+}
+
+// searchFilter builds the board/author/date filters of a search
+func searchFilter(q *SearchQuery) []map[string]interface{} {
+	var filter []map[string]interface{}
+	if len(q.BoardIDs) > 0 {
+		filter = append(filter, map[string]interface{}{
+			"terms": map[string]interface{}{"board_id": q.BoardIDs},
+		})
+	}
+	if q.AuthorID != "" {
+		filter = append(filter, map[string]interface{}{
+			"term": map[string]interface{}{"author_id": q.AuthorID},
+		})
+	}
+	if q.From != nil || q.To != nil {
+		rng := map[string]interface{}{"format": "yyyy-MM-dd HH:mm:ss"}
+		if q.From != nil {
+			rng["gte"] = q.From.Format("2006-01-02 15:04:05")
+		}
+		if q.To != nil {
+			rng["lt"] = q.To.Format("2006-01-02 15:04:05")
+		}
+		filter = append(filter, map[string]interface{}{"range": map[string]interface{}{"created_at": rng}})
+	}
+	if len(q.Categories) > 0 {
+		filter = append(filter, map[string]interface{}{
+			"terms": map[string]interface{}{"category": q.Categories},
+		})
+	}
+	for _, tag := range q.Tags {
+		filter = append(filter, map[string]interface{}{
+			"term": map[string]interface{}{"tags": tag},
+		})
+	}
+	if q.MinGood > 0 {
+		filter = append(filter, map[string]interface{}{
+			"range": map[string]interface{}{"good": map[string]interface{}{"gte": q.MinGood}},
+		})
+	}
+	if q.HasImage {
+		filter = append(filter, map[string]interface{}{"term": map[string]interface{}{"has_image": true}})
+	}
+	if q.HasFile {
+		filter = append(filter, map[string]interface{}{"term": map[string]interface{}{"has_file": true}})
+	}
+	return filter
+}
+
+// searchFacetSize caps the buckets of the board / category facets
+const searchFacetSize = 30
+
+// searchAggs counts matches by board, category and month (게시글 검색 패싯)
+func searchAggs() map[string]interface{} {
+	return map[string]interface{}{
+		"board":    map[string]interface{}{"terms": map[string]interface{}{"field": "board_id", "size": searchFacetSize}},
+		"category": map[string]interface{}{"terms": map[string]interface{}{"field": "category", "size": searchFacetSize}},
+		"month": map[string]interface{}{"date_histogram": map[string]interface{}{
+			"field":             "created_at",
+			"calendar_interval": "month",
+			"format":            "yyyy-MM",
+			"min_doc_count":     1,
+		}},
+	}
+}
+
+// searchSort orders by relevance (최신순 보조) or by recency
+func searchSort(q *SearchQuery) []interface{} {
+	recent := map[string]interface{}{"created_at": map[string]interface{}{"order": "desc"}}
+	if q.Sort == SearchSortRecent {
+		return []interface{}{recent, "_score"}
+	}
+	return []interface{}{"_score", recent}
+}
+
+func (b *esSearchBackend) Search(ctx context.Context, alias string, q *SearchQuery) (*SearchResponse, error) {
+	var must []map[string]interface{}
+	var highlight map[string]interface{}
+	if alias == CommentsIndex {
+		must = []map[string]interface{}{
+			{
+				"match_phrase": map[string]interface{}{
+					"content": map[string]interface{}{
+						"query":    q.Keyword,
+						"analyzer": "korean",
+					},
+				},
+			},
+		}
+		highlight = map[string]interface{}{
+			"content": map[string]interface{}{"fragment_size": 200, "number_of_fragments": 3},
+		}
+	} else {
+		must = []map[string]interface{}{
+			{
+				"multi_match": map[string]interface{}{
+					"query":  q.Keyword,
+					"fields": []string{"title^3", "content", "author"},
+					"type":   "phrase",
+				},
+			},
+		}
+		highlight = map[string]interface{}{
+			"title":   map[string]interface{}{"number_of_fragments": 0},
+			"content": map[string]interface{}{"fragment_size": 150, "number_of_fragments": 3},
+		}
+	}
+
+	boolQuery := map[string]interface{}{
+		"must":   must,
+		"filter": searchFilter(q),
+	}
+	if len(q.ExcludeAuthorIDs) > 0 {
+		boolQuery["must_not"] = []map[string]interface{}{
+			{"terms": map[string]interface{}{"author_id": q.ExcludeAuthorIDs}},
+		}
+	}
+	query := map[string]interface{}{
+		"query": map[string]interface{}{"bool": boolQuery},
+		"highlight": map[string]interface{}{
+			"fields":    highlight,
+			"pre_tags":  []string{"<mark>"},
+			"post_tags": []string{"</mark>"},
+		},
+		"sort": searchSort(q),
+	}
+	if alias == PostsIndex {
+		query["aggs"] = searchAggs()
+	}
+	return b.client.Search(ctx, alias, query, q.offset(), q.PerPage)
+}
+
+func (b *esSearchBackend) Suggest(ctx context.Context, prefix string, size int) ([]string, error) {
+	return b.client.Suggest(ctx, PostsIndex, "title_suggest", prefix, size)
+}
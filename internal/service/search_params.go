@@ -0,0 +1,175 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+)
+
+// ErrInvalidSearch is returned for malformed search parameters
+var ErrInvalidSearch = errors.New("invalid search parameters")
+
+// searchMaxTerms caps the category / tag lists of one search
+const searchMaxTerms = 10
+
+// SearchParams is the user-facing form of a search (쿼리스트링과 저장된 검색이 같은 모양을 쓴다)
+type SearchParams struct {
+	Keyword        string   `json:"q"`
+	Type           string   `json:"type,omitempty"` // all | posts | comments
+	BoardID        string   `json:"board_id,omitempty"`
+	Author         string   `json:"author,omitempty"` // mb_id 또는 닉네임
+	Categories     []string `json:"category,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	From           string   `json:"from,omitempty"` // YYYY-MM-DD
+	To             string   `json:"to,omitempty"`   // YYYY-MM-DD (그 날 포함)
+	MinGood        int      `json:"min_good,omitempty"`
+	HasImage       bool     `json:"has_image,omitempty"`
+	HasFile        bool     `json:"has_file,omitempty"`
+	ExcludeBlocked bool     `json:"exclude_blocked,omitempty"`
+	Sort           string   `json:"sort,omitempty"`
+}
+
+// ParseSearchParams reads SearchParams from a query string.
+// category·tags 는 반복(category=a&category=b)과 쉼표 구분(category=a,b) 둘 다 받는다.
+func ParseSearchParams(values url.Values) SearchParams {
+	p := SearchParams{
+		Keyword:        strings.TrimSpace(values.Get("q")),
+		Type:           values.Get("type"),
+		BoardID:        values.Get("board_id"),
+		Author:         strings.TrimSpace(values.Get("author")),
+		Categories:     splitSearchList(values["category"]),
+		Tags:           splitSearchList(values["tags"]),
+		From:           values.Get("from"),
+		To:             values.Get("to"),
+		ExcludeBlocked: values.Get("exclude_blocked") == "true" || values.Get("exclude_blocked") == "1",
+		Sort:           values.Get("sort"),
+	}
+	if p.Author == "" {
+		p.Author = values.Get("author_id") // 이전 파라미터 호환
+	}
+	if n, err := strconv.Atoi(values.Get("min_good")); err == nil {
+		p.MinGood = n
+	}
+	p.HasImage = values.Get("has_image") == "true" || values.Get("has_image") == "1"
+	p.HasFile = values.Get("has_file") == "true" || values.Get("has_file") == "1"
+	return p
+}
+
+func splitSearchList(raw []string) []string {
+	var out []string
+	for _, v := range raw {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
+
+// Validate checks the parameters without touching the database
+func (p *SearchParams) Validate() error {
+	if p.Keyword == "" {
+		return fmt.Errorf("%w: q is required", ErrInvalidSearch)
+	}
+	switch p.Type {
+	case "", "all", "posts", "comments":
+	default:
+		return fmt.Errorf("%w: type must be all, posts or comments", ErrInvalidSearch)
+	}
+	switch p.Sort {
+	case "", SearchSortRelevance, SearchSortRecent:
+	default:
+		return fmt.Errorf("%w: sort must be relevance or recent", ErrInvalidSearch)
+	}
+	if len(p.Categories) > searchMaxTerms || len(p.Tags) > searchMaxTerms {
+		return fmt.Errorf("%w: at most %d categories and tags", ErrInvalidSearch, searchMaxTerms)
+	}
+	if p.MinGood < 0 {
+		return fmt.Errorf("%w: min_good must not be negative", ErrInvalidSearch)
+	}
+	if p.BoardID != "" && !searchBoardTable.MatchString(p.BoardID) {
+		return fmt.Errorf("%w: invalid board_id", ErrInvalidSearch)
+	}
+	for _, d := range []string{p.From, p.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.ParseInLocation("2006-01-02", d, time.Local); err != nil {
+			return fmt.Errorf("%w: dates must be YYYY-MM-DD", ErrInvalidSearch)
+		}
+	}
+	return nil
+}
+
+// SetBlockRepository enables exclude_blocked (차단한 회원의 글·댓글 제외)
+func (s *SearchService) SetBlockRepository(repo v2repo.BlockRepository) {
+	s.blockRepo = repo
+}
+
+// BuildQuery turns SearchParams into a SearchQuery for viewer (비로그인은 빈 문자열).
+func (s *SearchService) BuildQuery(p SearchParams, viewer string, page, perPage int) (SearchQuery, error) {
+	if err := p.Validate(); err != nil {
+		return SearchQuery{}, err
+	}
+	q := SearchQuery{
+		Keyword:    p.Keyword,
+		Sort:       p.Sort,
+		Page:       page,
+		PerPage:    perPage,
+		Categories: p.Categories,
+		Tags:       p.Tags,
+		MinGood:    p.MinGood,
+		HasImage:   p.HasImage,
+		HasFile:    p.HasFile,
+	}
+	if q.Sort == "" {
+		q.Sort = SearchSortRelevance
+	}
+	if p.BoardID != "" {
+		q.BoardIDs = []string{p.BoardID}
+	}
+	if p.From != "" {
+		t, _ := time.ParseInLocation("2006-01-02", p.From, time.Local) //nolint:errcheck // Validate 에서 확인
+		q.From = &t
+	}
+	if p.To != "" {
+		t, _ := time.ParseInLocation("2006-01-02", p.To, time.Local) //nolint:errcheck // Validate 에서 확인
+		t = t.AddDate(0, 0, 1)
+		q.To = &t
+	}
+	if p.Author != "" {
+		q.AuthorID = s.resolveAuthor(p.Author)
+	}
+	if p.ExcludeBlocked && viewer != "" && s.blockRepo != nil {
+		blocked, err := s.blockRepo.GetContentBlockedUserIDs(viewer)
+		if err != nil {
+			return SearchQuery{}, fmt.Errorf("load blocked members: %w", err)
+		}
+		q.ExcludeAuthorIDs = blocked
+	}
+	return q, nil
+}
+
+// resolveAuthor maps a nickname to its mb_id; mb_id 가 그대로 있으면 그쪽이 우선이다.
+// 둘 다 없으면 입력을 mb_id 로 보고 넘긴다(결과 0건).
+func (s *SearchService) resolveAuthor(author string) string {
+	if s.db == nil {
+		return author
+	}
+	var count int64
+	if s.db.Table("g5_member").Where("mb_id = ?", author).Count(&count); count > 0 {
+		return author
+	}
+	var mbID string
+	s.db.Table("g5_member").Select("mb_id").Where("mb_nick = ?", author).Limit(1).Scan(&mbID)
+	if mbID == "" {
+		return author
+	}
+	return mbID
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchParams(t *testing.T) {
+	values, _ := url.ParseQuery("q=+SSD+&author_id=bob&category=특가,할인&category=중고&tags=ssd&min_good=5&has_image=1&exclude_blocked=true&sort=recent")
+	p := ParseSearchParams(values)
+
+	want := SearchParams{
+		Keyword:        "SSD",
+		Author:         "bob",
+		Categories:     []string{"특가", "할인", "중고"},
+		Tags:           []string{"ssd"},
+		MinGood:        5,
+		HasImage:       true,
+		ExcludeBlocked: true,
+		Sort:           "recent",
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Fatalf("ParseSearchParams = %+v, want %+v", p, want)
+	}
+}
+
+func TestSearchParamsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		p    SearchParams
+		ok   bool
+	}{
+		{"minimal", SearchParams{Keyword: "산불"}, true},
+		{"missing q", SearchParams{}, false},
+		{"bad type", SearchParams{Keyword: "a", Type: "users"}, false},
+		{"bad sort", SearchParams{Keyword: "a", Sort: "hot"}, false},
+		{"bad date", SearchParams{Keyword: "a", From: "2026/01/01"}, false},
+		{"negative good", SearchParams{Keyword: "a", MinGood: -1}, false},
+		{"bad board", SearchParams{Keyword: "a", BoardID: "free; DROP"}, false},
+		{"too many tags", SearchParams{Keyword: "a", Tags: make([]string, searchMaxTerms+1)}, false},
+	} {
+		err := tc.p.Validate()
+		if tc.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if !tc.ok && !errors.Is(err, ErrInvalidSearch) {
+			t.Errorf("%s: err = %v, want ErrInvalidSearch", tc.name, err)
+		}
+	}
+}
+
+func TestBuildQueryDateRangeIncludesEndDay(t *testing.T) {
+	svc := NewSearchService(NewEmbeddedSearchBackend(), nil)
+	q, err := svc.BuildQuery(SearchParams{Keyword: "a", From: "2026-03-01", To: "2026-03-01"}, "", 1, 20)
+	if err != nil {
+		t.Fatalf("BuildQuery: %v", err)
+	}
+	if q.To.Sub(*q.From).Hours() != 24 || q.Sort != SearchSortRelevance {
+		t.Errorf("unexpected query: from=%v to=%v sort=%s", q.From, q.To, q.Sort)
+	}
+}
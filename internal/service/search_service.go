@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
 	es "github.com/damoang/angple-backend/pkg/elasticsearch"
 	pkglogger "github.com/damoang/angple-backend/pkg/logger"
 	"gorm.io/gorm"
@@ -27,6 +30,13 @@ type PostDocument struct {
 	CreatedAt string `json:"created_at"`
 	Views     int    `json:"views"`
 	Good      int    `json:"good"`
+	// 고급 검색 필터 (g5_na_tag_log 태그, 본문 이미지, 첨부파일)
+	Tags     []string `json:"tags"`
+	HasImage bool     `json:"has_image"`
+	HasFile  bool     `json:"has_file"`
+	// Checksum 은 CRC32(제목+작성자+본문) — 정합성 검사(Reconcile)가 DB 와 합계를 비교한다
+	Checksum  uint32 `json:"checksum"`
+	IndexedAt int64  `json:"indexed_at"` // unix ms, 범위 재동기화 후 남은 옛 문서를 지우는 기준
 	// For autocomplete
 	TitleSuggest map[string]interface{} `json:"title_suggest,omitempty"`
 }
@@ -40,25 +50,41 @@ type CommentDocument struct {
 	Author    string `json:"author"`
 	AuthorID  string `json:"author_id"`
 	CreatedAt string `json:"created_at"`
+	Checksum  uint32 `json:"checksum"`
+	IndexedAt int64  `json:"indexed_at"`
 }
 
-// SearchService provides Elasticsearch-based search
+// SearchService provides full-text search over posts and comments.
+// 실제 색인은 SearchBackend(Elasticsearch 또는 내장 n-gram 색인)가 들고, 이 서비스는 DB 를 읽어 동기화한다.
 type SearchService struct {
-	esClient *es.Client
-	db       *gorm.DB
+	backend   SearchBackend
+	db        *gorm.DB
+	blockRepo v2repo.BlockRepository
+
+	mu          sync.RWMutex
+	building    map[string]string // alias → Reindex 가 채우는 중인 새 인덱스
+	lastReindex *SearchReindexResult
+	reindexMu   sync.Mutex
 }
 
 // NewSearchService creates a new SearchService
-func NewSearchService(esClient *es.Client, db *gorm.DB) *SearchService {
-	svc := &SearchService{esClient: esClient, db: db}
+func NewSearchService(backend SearchBackend, db *gorm.DB) *SearchService {
+	svc := &SearchService{backend: backend, db: db, building: make(map[string]string)}
 	// Ensure indices exist
 	ctx := context.Background()
-	if err := svc.ensureIndices(ctx); err != nil {
-		pkglogger.GetLogger().Error().Err(err).Msg("failed to create ES indices")
+	for _, alias := range []string{PostsIndex, CommentsIndex} {
+		if err := backend.EnsureIndex(ctx, alias); err != nil {
+			pkglogger.GetLogger().Error().Err(err).Str("backend", backend.Name()).Msg("failed to create search indices")
+		}
 	}
 	return svc
 }
 
+// BackendName returns the name of the search backend in use
+func (s *SearchService) BackendName() string {
+	return s.backend.Name()
+}
+
 // getSearchableBoardIDs returns board table names where bo_use_search = 1
 func (s *SearchService) getSearchableBoardIDs() ([]string, error) {
 	var boards []struct {
@@ -75,73 +101,15 @@ func (s *SearchService) getSearchableBoardIDs() ([]string, error) {
 	return ids, nil
 }
 
-// ensureIndices creates ES indices with Korean nori analyzer mappings
-func (s *SearchService) ensureIndices(ctx context.Context) error {
-	postMapping := map[string]interface{}{
-		"settings": map[string]interface{}{
-			"analysis": map[string]interface{}{
-				"analyzer": map[string]interface{}{
-					"korean": map[string]interface{}{
-						"type":      "custom",
-						"tokenizer": "nori_tokenizer",
-						"filter":    []string{"nori_readingform", "lowercase"},
-					},
-				},
-			},
-		},
-		"mappings": map[string]interface{}{
-			"properties": map[string]interface{}{
-				"board_id":   map[string]interface{}{"type": "keyword"},
-				"post_id":    map[string]interface{}{"type": "integer"},
-				"title":      map[string]interface{}{"type": "text", "analyzer": "korean", "search_analyzer": "korean"},
-				"content":    map[string]interface{}{"type": "text", "analyzer": "korean", "search_analyzer": "korean"},
-				"author":     map[string]interface{}{"type": "text", "fields": map[string]interface{}{"keyword": map[string]interface{}{"type": "keyword"}}},
-				"author_id":  map[string]interface{}{"type": "keyword"},
-				"category":   map[string]interface{}{"type": "keyword"},
-				"created_at": map[string]interface{}{"type": "date"},
-				"views":      map[string]interface{}{"type": "integer"},
-				"good":       map[string]interface{}{"type": "integer"},
-				"title_suggest": map[string]interface{}{
-					"type":            "completion",
-					"analyzer":        "korean",
-					"search_analyzer": "korean",
-				},
-			},
-		},
-	}
-
-	commentMapping := map[string]interface{}{
-		"settings": map[string]interface{}{
-			"analysis": map[string]interface{}{
-				"analyzer": map[string]interface{}{
-					"korean": map[string]interface{}{
-						"type":      "custom",
-						"tokenizer": "nori_tokenizer",
-						"filter":    []string{"nori_readingform", "lowercase"},
-					},
-				},
-			},
-		},
-		"mappings": map[string]interface{}{
-			"properties": map[string]interface{}{
-				"board_id":   map[string]interface{}{"type": "keyword"},
-				"post_id":    map[string]interface{}{"type": "integer"},
-				"comment_id": map[string]interface{}{"type": "integer"},
-				"content":    map[string]interface{}{"type": "text", "analyzer": "korean", "search_analyzer": "korean"},
-				"author":     map[string]interface{}{"type": "text", "fields": map[string]interface{}{"keyword": map[string]interface{}{"type": "keyword"}}},
-				"author_id":  map[string]interface{}{"type": "keyword"},
-				"created_at": map[string]interface{}{"type": "date"},
-			},
-		},
-	}
-
-	if err := s.esClient.CreateIndex(ctx, PostsIndex, postMapping); err != nil {
-		return fmt.Errorf("create posts index: %w", err)
+// writeTargets returns the indices a live write must reach: the alias, plus the index
+// being built by a running Reindex so it does not miss writes made during the rebuild.
+func (s *SearchService) writeTargets(alias string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if building, ok := s.building[alias]; ok {
+		return []string{alias, building}
 	}
-	if err := s.esClient.CreateIndex(ctx, CommentsIndex, commentMapping); err != nil {
-		return fmt.Errorf("create comments index: %w", err)
-	}
-	return nil
+	return []string{alias}
 }
 
 // IndexPost indexes a single post
@@ -150,157 +118,105 @@ func (s *SearchService) IndexPost(ctx context.Context, doc *PostDocument) error
 	doc.TitleSuggest = map[string]interface{}{
 		"input": strings.Fields(doc.Title),
 	}
-	return s.esClient.IndexDocument(ctx, PostsIndex, docID, doc)
+	for _, index := range s.writeTargets(PostsIndex) {
+		if err := s.backend.BulkIndex(ctx, index, map[string]interface{}{docID: *doc}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // DeletePost removes a post from the index
 func (s *SearchService) DeletePost(ctx context.Context, boardID string, postID int) error {
 	docID := fmt.Sprintf("%s_%d", boardID, postID)
-	return s.esClient.DeleteDocument(ctx, PostsIndex, docID)
+	for _, index := range s.writeTargets(PostsIndex) {
+		if err := s.backend.DeleteDocument(ctx, index, docID); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // IndexComment indexes a single comment
 func (s *SearchService) IndexComment(ctx context.Context, doc *CommentDocument) error {
 	docID := fmt.Sprintf("%s_%d_%d", doc.BoardID, doc.PostID, doc.CommentID)
-	return s.esClient.IndexDocument(ctx, CommentsIndex, docID, doc)
+	for _, index := range s.writeTargets(CommentsIndex) {
+		if err := s.backend.BulkIndex(ctx, index, map[string]interface{}{docID: *doc}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // DeleteComment removes a comment from the index
 func (s *SearchService) DeleteComment(ctx context.Context, boardID string, postID, commentID int) error {
 	docID := fmt.Sprintf("%s_%d_%d", boardID, postID, commentID)
-	return s.esClient.DeleteDocument(ctx, CommentsIndex, docID)
-}
-
-// SearchPosts searches posts with highlighting
-func (s *SearchService) SearchPosts(ctx context.Context, keyword, boardID string, page, perPage int) (*es.SearchResponse, error) {
-	must := []map[string]interface{}{
-		{
-			"multi_match": map[string]interface{}{
-				"query":  keyword,
-				"fields": []string{"title^3", "content", "author"},
-				"type":   "phrase",
-			},
-		},
-	}
-
-	var filter []map[string]interface{}
-	if boardID == "" {
-		searchableIDs, err := s.getSearchableBoardIDs()
-		if err == nil && len(searchableIDs) > 0 {
-			filter = append(filter, map[string]interface{}{
-				"terms": map[string]interface{}{"board_id": searchableIDs},
-			})
+	for _, index := range s.writeTargets(CommentsIndex) {
+		if err := s.backend.DeleteDocument(ctx, index, docID); err != nil {
+			return err
 		}
-	} else {
-		filter = append(filter, map[string]interface{}{
-			"term": map[string]interface{}{"board_id": boardID},
-		})
-	}
-
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"must":   must,
-				"filter": filter,
-			},
-		},
-		"highlight": map[string]interface{}{
-			"fields": map[string]interface{}{
-				"title":   map[string]interface{}{"number_of_fragments": 0},
-				"content": map[string]interface{}{"fragment_size": 150, "number_of_fragments": 3},
-			},
-			"pre_tags":  []string{"<mark>"},
-			"post_tags": []string{"</mark>"},
-		},
-		"sort": []interface{}{
-			"_score",
-			map[string]interface{}{"created_at": map[string]interface{}{"order": "desc"}},
-		},
 	}
-
-	from := (page - 1) * perPage
-	return s.esClient.Search(ctx, PostsIndex, query, from, perPage)
+	return nil
 }
 
-// SearchComments searches comments with highlighting
-func (s *SearchService) SearchComments(ctx context.Context, keyword, boardID string, page, perPage int) (*es.SearchResponse, error) {
-	must := []map[string]interface{}{
-		{
-			"match_phrase": map[string]interface{}{
-				"content": map[string]interface{}{
-					"query":    keyword,
-					"analyzer": "korean",
-				},
-			},
-		},
-	}
-
-	var filter []map[string]interface{}
-	if boardID == "" {
-		searchableIDs, err := s.getSearchableBoardIDs()
-		if err == nil && len(searchableIDs) > 0 {
-			filter = append(filter, map[string]interface{}{
-				"terms": map[string]interface{}{"board_id": searchableIDs},
-			})
+// scoped fills in the board restriction: 게시판을 지정하지 않으면 검색 허용(bo_use_search) 게시판만
+func (s *SearchService) scoped(q SearchQuery) *SearchQuery {
+	if len(q.BoardIDs) == 0 {
+		if searchableIDs, err := s.getSearchableBoardIDs(); err == nil && len(searchableIDs) > 0 {
+			q.BoardIDs = searchableIDs
 		}
-	} else {
-		filter = append(filter, map[string]interface{}{
-			"term": map[string]interface{}{"board_id": boardID},
-		})
 	}
+	return &q
+}
 
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"must":   must,
-				"filter": filter,
-			},
-		},
-		"highlight": map[string]interface{}{
-			"fields": map[string]interface{}{
-				"content": map[string]interface{}{"fragment_size": 200, "number_of_fragments": 3},
-			},
-			"pre_tags":  []string{"<mark>"},
-			"post_tags": []string{"</mark>"},
-		},
-		"sort": []interface{}{
-			"_score",
-			map[string]interface{}{"created_at": map[string]interface{}{"order": "desc"}},
-		},
-	}
+// SearchPosts searches posts with highlighting
+func (s *SearchService) SearchPosts(ctx context.Context, q SearchQuery) (*SearchResponse, error) {
+	return s.backend.Search(ctx, PostsIndex, s.scoped(q))
+}
 
-	from := (page - 1) * perPage
-	return s.esClient.Search(ctx, CommentsIndex, query, from, perPage)
+// SearchComments searches comments with highlighting
+func (s *SearchService) SearchComments(ctx context.Context, q SearchQuery) (*SearchResponse, error) {
+	return s.backend.Search(ctx, CommentsIndex, s.scoped(q))
 }
 
 // UnifiedSearch searches across both posts and comments
-func (s *SearchService) UnifiedSearch(ctx context.Context, keyword, boardID, searchType string, page, perPage int) (map[string]interface{}, error) {
+func (s *SearchService) UnifiedSearch(ctx context.Context, q SearchQuery, searchType string) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
 	switch searchType {
 	case "posts":
-		posts, err := s.SearchPosts(ctx, keyword, boardID, page, perPage)
+		posts, err := s.SearchPosts(ctx, q)
 		if err != nil {
 			return nil, err
 		}
 		result["posts"] = posts
 	case "comments":
-		comments, err := s.SearchComments(ctx, keyword, boardID, page, perPage)
+		if q.postOnly() {
+			return nil, fmt.Errorf("%w: category, tags, min_good, has_image and has_file apply to posts only", ErrInvalidSearch)
+		}
+		comments, err := s.SearchComments(ctx, q)
 		if err != nil {
 			return nil, err
 		}
 		result["comments"] = comments
 	default:
 		// Search both
-		posts, err := s.SearchPosts(ctx, keyword, boardID, page, perPage)
+		posts, err := s.SearchPosts(ctx, q)
 		if err != nil {
 			return nil, err
 		}
-		comments, err := s.SearchComments(ctx, keyword, boardID, 1, 5) // 댓글은 상위 5개만
+		result["posts"] = posts
+		// 카테고리·태그처럼 게시글에만 있는 조건이 걸리면 댓글은 찾지 않는다
+		if q.postOnly() {
+			result["comments"] = &SearchResponse{Results: []es.SearchResult{}}
+			break
+		}
+		top := q
+		top.Page, top.PerPage = 1, 5 // 댓글은 상위 5개만
+		comments, err := s.SearchComments(ctx, top)
 		if err != nil {
 			return nil, err
 		}
-		result["posts"] = posts
 		result["comments"] = comments
 	}
 
@@ -312,7 +228,7 @@ func (s *SearchService) Autocomplete(ctx context.Context, prefix string, size in
 	if size <= 0 {
 		size = 10
 	}
-	return s.esClient.Suggest(ctx, PostsIndex, "title_suggest", prefix, size)
+	return s.backend.Suggest(ctx, prefix, size)
 }
 
 // BulkIndexPosts indexes multiple posts from the database (for initial sync)
@@ -320,59 +236,34 @@ func (s *SearchService) BulkIndexPosts(ctx context.Context, boardID string, limi
 	if s.db == nil {
 		return 0, fmt.Errorf("database not available")
 	}
-
-	// Skip boards where bo_use_search is disabled
-	var searchCount int64
-	s.db.Table("g5_board").Where("bo_table = ? AND bo_use_search = ?", boardID, 1).Count(&searchCount)
-	if searchCount == 0 {
-		return 0, nil
+	if !searchBoardTable.MatchString(boardID) {
+		return 0, fmt.Errorf("invalid board id %q", boardID)
 	}
 
-	tableName := fmt.Sprintf("g5_write_%s", boardID)
-
-	var rows []struct {
-		WrID       int    `gorm:"column:wr_id"`
-		WrSubject  string `gorm:"column:wr_subject"`
-		WrContent  string `gorm:"column:wr_content"`
-		WrName     string `gorm:"column:wr_name"`
-		MbID       string `gorm:"column:mb_id"`
-		CaName     string `gorm:"column:ca_name"`
-		WrDatetime string `gorm:"column:wr_datetime"`
-		WrHit      int    `gorm:"column:wr_hit"`
-		WrGood     int    `gorm:"column:wr_good"`
+	// Skip boards where bo_use_search is disabled
+	if searchable, err := s.isSearchableBoard(boardID); err != nil || !searchable {
+		return 0, err
 	}
 
-	err := s.db.Table(tableName).
-		Where("wr_is_comment = 0 AND (wr_deleted_at IS NULL OR wr_deleted_at = '0000-00-00 00:00:00')").
-		Order("wr_id DESC").
+	var rows []searchWriteRow
+	err := s.db.WithContext(ctx).Table(fmt.Sprintf("g5_write_%s", boardID) + " AS p").
+		Where("p.wr_is_comment = 0 AND " + searchableCond("p")).
+		Order("p.wr_id DESC").
 		Limit(limit).
 		Find(&rows).Error
 	if err != nil {
 		return 0, err
 	}
 
-	docs := make(map[string]interface{})
-	for _, row := range rows {
-		docID := fmt.Sprintf("%s_%d", boardID, row.WrID)
-		docs[docID] = PostDocument{
-			BoardID:   boardID,
-			PostID:    row.WrID,
-			Title:     row.WrSubject,
-			Content:   stripHTML(row.WrContent),
-			Author:    row.WrName,
-			AuthorID:  row.MbID,
-			Category:  row.CaName,
-			CreatedAt: row.WrDatetime,
-			Views:     row.WrHit,
-			Good:      row.WrGood,
-			TitleSuggest: map[string]interface{}{
-				"input": strings.Fields(row.WrSubject),
-			},
-		}
+	docs, err := s.postDocuments(ctx, boardID, rows, time.Now().UnixMilli())
+	if err != nil {
+		return 0, err
 	}
 
-	if err := s.esClient.BulkIndex(ctx, PostsIndex, docs); err != nil {
-		return 0, err
+	for _, index := range s.writeTargets(PostsIndex) {
+		if err := s.backend.BulkIndex(ctx, index, docs); err != nil {
+			return 0, err
+		}
 	}
 
 	pkglogger.GetLogger().Info().
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"hash/crc32"
+	"testing"
+	"time"
+)
+
+func TestSearchChecksumsMatchConcat(t *testing.T) {
+	row := &searchWriteRow{WrSubject: "제목", WrName: "앙플", WrContent: "<p>본문</p>"}
+
+	if got, want := postChecksum(row), crc32.ChecksumIEEE([]byte("제목앙플<p>본문</p>")); got != want {
+		t.Errorf("postChecksum = %d, want %d", got, want)
+	}
+	if got, want := commentChecksum(row), crc32.ChecksumIEEE([]byte("앙플<p>본문</p>")); got != want {
+		t.Errorf("commentChecksum = %d, want %d", got, want)
+	}
+
+	doc := newPostDocument("free", row, 42)
+	if doc.Content != "본문" || doc.Checksum != postChecksum(row) || doc.IndexedAt != 42 {
+		t.Errorf("unexpected post document: %+v", doc)
+	}
+}
+
+func TestSearchStaleQueryScope(t *testing.T) {
+	if got := len(scopeFilter("free", searchScope{})); got != 1 {
+		t.Fatalf("board scope should filter by board only, got %d terms", got)
+	}
+	if got := len(scopeFilter("free", searchScope{PostID: 3, AuthorID: "user"})); got != 3 {
+		t.Fatalf("expected board+post+author terms, got %d", got)
+	}
+
+	q := staleQuery("free", searchScope{PostID: 3}, 1000)
+	boolQ := q["bool"].(map[string]interface{})
+	mustNot := boolQ["must_not"].([]map[string]interface{})
+	rng := mustNot[0]["range"].(map[string]interface{})["indexed_at"].(map[string]interface{})
+	if rng["gte"] != int64(1000) {
+		t.Errorf("stale query must exclude docs rewritten by this sync: %+v", rng)
+	}
+}
+
+func TestVersionedIndexName(t *testing.T) {
+	at := time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC)
+	if got := versionedIndexName("angple_posts", at); got != "angple_posts_v20261016153000" {
+		t.Errorf("versionedIndexName = %q", got)
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"strings"
 	"time"
 
+	gnudomain "github.com/damoang/angple-backend/internal/domain/gnuboard"
 	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	"gorm.io/gorm"
 )
 
@@ -209,6 +211,22 @@ func (s *AdminService) anonymizeMemberUser(user *v2domain.V2User, req AnonymizeM
 				Reason: "no matching text found",
 			})
 		}
+
+		// 검색 색인: 회원이 쓴 글(작성자명)과 본문을 고친 대상 글타래를 다시 색인한다
+		if err := gnurepo.EnqueueSearchSync(tx, gnudomain.WriteAfterEventTypeSearchMemberSync, "", 0, user.Username); err != nil {
+			return err
+		}
+		threads := make(map[string]bool, len(targets))
+		for _, target := range targets {
+			key := fmt.Sprintf("%s:%d", target.BoardID, target.PostID)
+			if threads[key] {
+				continue
+			}
+			threads[key] = true
+			if err := gnurepo.EnqueueSearchSync(tx, gnudomain.WriteAfterEventTypeSearchThreadSync, target.BoardID, target.PostID, ""); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	gnudomain "github.com/damoang/angple-backend/internal/domain/gnuboard"
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
 	"gorm.io/driver/sqlite"
@@ -27,6 +28,9 @@ func TestAnonymizeMemberBacksUpAndReplacesTargetRows(t *testing.T) {
 		}
 	}
 
+	if err := db.AutoMigrate(&gnudomain.WriteAfterEvent{}); err != nil {
+		t.Fatalf("migrate write-after events: %v", err)
+	}
 	mustExec(`CREATE TABLE v2_users (
 		id INTEGER PRIMARY KEY,
 		username TEXT,
@@ -109,6 +113,16 @@ func TestAnonymizeMemberBacksUpAndReplacesTargetRows(t *testing.T) {
 		t.Fatalf("legacy nickname = %q, want 탈퇴_1", nickname)
 	}
 
+	var searchEvents []gnudomain.WriteAfterEvent
+	if err := db.Order("id").Find(&searchEvents).Error; err != nil {
+		t.Fatalf("query search events: %v", err)
+	}
+	if len(searchEvents) != 3 ||
+		searchEvents[0].EventType != gnudomain.WriteAfterEventTypeSearchMemberSync || searchEvents[0].MemberID != "user7" ||
+		searchEvents[1].BoardSlug != "disciplinelog" || searchEvents[2].WriteID != 5936618 {
+		t.Fatalf("unexpected search sync events: %+v", searchEvents)
+	}
+
 	assertRowContains := func(table string, rowID int, want string) {
 		t.Helper()
 		var row struct {
@@ -181,6 +195,9 @@ func TestAnonymizeMemberDefaultsToGenericNickname(t *testing.T) {
 		}
 	}
 
+	if err := db.AutoMigrate(&gnudomain.WriteAfterEvent{}); err != nil {
+		t.Fatalf("migrate write-after events: %v", err)
+	}
 	mustExec(`CREATE TABLE v2_users (
 		id INTEGER PRIMARY KEY,
 		username TEXT,
@@ -33,6 +33,8 @@ type V2AuthService struct {
 	notiRepo   gnurepo.NotiRepository
 	db         *gorm.DB
 	redis      *redis.Client
+	sessions   *SessionService
+	mfa        *MFAService
 }
 
 // NewV2AuthService creates a new V2AuthService
@@ -55,6 +57,49 @@ func (s *V2AuthService) SetRedis(rc *redis.Client) {
 	s.redis = rc
 }
 
+// SetSessionService enables server-side sessions: 로그인마다 세션을 만들고 리프레시 때 회전한다.
+// 주입하지 않으면 이전처럼 무상태 토큰을 발급한다(테스트·로컬).
+func (s *V2AuthService) SetSessionService(sessions *SessionService) {
+	s.sessions = sessions
+}
+
+// SetMFAService enables TOTP second factor on login (켠 회원은 Login 이 토큰 대신 mfa_ticket 을 받는다)
+func (s *V2AuthService) SetMFAService(mfa *MFAService) {
+	s.mfa = mfa
+}
+
+// issueTokens issues a token pair, as a new server session when sessions are enabled
+func (s *V2AuthService) issueTokens(user *v2domain.V2User, level int, meta []SessionMeta, mfa bool) (accessToken, refreshToken string, err error) {
+	if s.sessions != nil {
+		accessToken, refreshToken, err = s.sessions.Issue(context.Background(), user, level, firstSessionMeta(meta), mfa)
+		if err != nil {
+			return "", "", fmt.Errorf("create session: %w", err)
+		}
+		return accessToken, refreshToken, nil
+	}
+	return s.statelessTokens(user, level)
+}
+
+func (s *V2AuthService) statelessTokens(user *v2domain.V2User, level int) (accessToken, refreshToken string, err error) {
+	userIDStr := strconv.FormatUint(user.ID, 10)
+	accessToken, err = s.jwtManager.GenerateAccessToken(userIDStr, user.Username, user.Nickname, level)
+	if err != nil {
+		return "", "", fmt.Errorf("generate access token: %w", err)
+	}
+	refreshToken, err = s.jwtManager.GenerateRefreshToken(userIDStr)
+	if err != nil {
+		return "", "", fmt.Errorf("generate refresh token: %w", err)
+	}
+	return accessToken, refreshToken, nil
+}
+
+func firstSessionMeta(meta []SessionMeta) SessionMeta {
+	if len(meta) > 0 {
+		return meta[0]
+	}
+	return SessionMeta{}
+}
+
 // V2LoginResponse represents v2 login response
 //
 //nolint:revive
@@ -62,6 +107,9 @@ type V2LoginResponse struct {
 	User         *v2domain.V2User `json:"user"`
 	AccessToken  string           `json:"access_token"`
 	RefreshToken string           `json:"refresh_token"`
+	// MFATicket 이 있으면 비밀번호만 맞은 상태다 — 토큰은 없고, 2단계 코드와 함께
+	// POST /auth/mfa/verify 로 보내야 로그인이 끝난다.
+	MFATicket string `json:"mfa_ticket,omitempty"`
 	// WithdrawalGrace 가 non-nil 이면 대상이 탈퇴 숙려중(취소 가능)이다. 정상 로그인 성공이 아니며
 	// 프론트는 취소 UI 를 노출해야 한다. 취소(DELETE /members/me/leave) 호출을 위해 토큰은 함께 발급된다.
 	WithdrawalGrace *WithdrawalGraceInfo `json:"withdrawal_grace,omitempty"`
@@ -121,7 +169,8 @@ func (s *V2AuthService) checkWithdrawal(username string, now time.Time) (common.
 
 // Login authenticates a user against v2_users table.
 // Supports both bcrypt (new accounts), then legacy gnuboard hashing (migrated users).
-func (s *V2AuthService) Login(username, password string) (*V2LoginResponse, error) {
+// meta 는 세션 목록에 보일 기기 정보다(선택).
+func (s *V2AuthService) Login(username, password string, meta ...SessionMeta) (*V2LoginResponse, error) {
 	user, err := s.userRepo.FindByUsername(username)
 	if err != nil {
 		return nil, common.ErrInvalidCredentials
@@ -135,6 +184,28 @@ func (s *V2AuthService) Login(username, password string) (*V2LoginResponse, erro
 		return nil, common.ErrInvalidCredentials
 	}
 
+	if !isBcryptHash(user.Password) {
+		if upgraded, hashErr := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost); hashErr == nil {
+			user.Password = string(upgraded)
+			if updateErr := s.userRepo.Update(user); updateErr != nil {
+				log.Printf("[v2-auth] password upgrade failed for user %s: %v", username, updateErr)
+			}
+		}
+	}
+
+	// 2단계 인증을 켠 회원은 여기서 멈춘다 — 숙려 분기·XP·세션 생성은 코드 확인 뒤(completeLogin)
+	if s.mfa != nil && s.mfa.IsEnabled(user.ID) {
+		ticket, err := s.mfaTicket(user)
+		if err != nil {
+			return nil, err
+		}
+		return &V2LoginResponse{User: user, MFATicket: ticket}, nil
+	}
+	return s.completeLogin(user, meta, false)
+}
+
+// completeLogin runs everything after the credentials are proven (mfa: 2단계까지 거쳤다)
+func (s *V2AuthService) completeLogin(user *v2domain.V2User, meta []SessionMeta, mfa bool) (*V2LoginResponse, error) {
 	// 탈퇴 숙려기간 분기: mb_leave_date 세팅됨 → 정상 로그인 대신 상태 반환.
 	//   - 숙려중(30일 미경과): 취소 가능. 토큰은 발급하되 WithdrawalGrace 표시(리프레시 쿠키는 핸들러에서 미설정).
 	//   - 확정(30일 경과): 이미 익명화 → 로그인 불가.
@@ -142,14 +213,10 @@ func (s *V2AuthService) Login(username, password string) (*V2LoginResponse, erro
 	case common.WithdrawalConfirmed:
 		return nil, common.ErrAccountWithdrawn
 	case common.WithdrawalGrace:
-		userIDStr := strconv.FormatUint(user.ID, 10)
-		accessToken, err := s.jwtManager.GenerateAccessToken(userIDStr, user.Username, user.Nickname, int(user.Level))
-		if err != nil {
-			return nil, fmt.Errorf("generate access token: %w", err)
-		}
-		refreshToken, err := s.jwtManager.GenerateRefreshToken(userIDStr)
+		// 숙려 로그인은 세션을 만들지 않는다 — 리프레시 쿠키를 주지 않는 취소 전용 토큰이다
+		accessToken, refreshToken, err := s.statelessTokens(user, int(user.Level))
 		if err != nil {
-			return nil, fmt.Errorf("generate refresh token: %w", err)
+			return nil, err
 		}
 		days := int(time.Until(deadline).Hours() / 24)
 		if days < 0 {
@@ -175,17 +242,8 @@ func (s *V2AuthService) Login(username, password string) (*V2LoginResponse, erro
 		}, nil
 	}
 
-	if !isBcryptHash(user.Password) {
-		if upgraded, hashErr := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost); hashErr == nil {
-			user.Password = string(upgraded)
-			if updateErr := s.userRepo.Update(user); updateErr != nil {
-				log.Printf("[v2-auth] password upgrade failed for user %s: %v", username, updateErr)
-			}
-		}
-	}
-
 	if s.expRepo != nil {
-		s.grantLoginXP(username)
+		s.grantLoginXP(user.Username)
 	}
 
 	level := int(user.Level)
@@ -195,14 +253,9 @@ func (s *V2AuthService) Login(username, password string) (*V2LoginResponse, erro
 		_ = s.userRepo.Update(user)
 	}
 
-	userIDStr := strconv.FormatUint(user.ID, 10)
-	accessToken, err := s.jwtManager.GenerateAccessToken(userIDStr, user.Username, user.Nickname, level)
-	if err != nil {
-		return nil, fmt.Errorf("generate access token: %w", err)
-	}
-	refreshToken, err := s.jwtManager.GenerateRefreshToken(userIDStr)
+	accessToken, refreshToken, err := s.issueTokens(user, level, meta, mfa)
 	if err != nil {
-		return nil, fmt.Errorf("generate refresh token: %w", err)
+		return nil, err
 	}
 
 	return &V2LoginResponse{
@@ -212,6 +265,126 @@ func (s *V2AuthService) Login(username, password string) (*V2LoginResponse, erro
 	}, nil
 }
 
+// MFATicketAudience is the audience claim of the login tickets waiting for a second factor
+const MFATicketAudience = "mfa-login"
+
+// MFATicketTTL is how long an mfa_ticket waits for its code (코드는 티켓당 mfaTicketMaxTries 번까지)
+const MFATicketTTL = 5 * time.Minute
+
+const mfaTicketMaxTries = 5
+
+func (s *V2AuthService) mfaTicket(user *v2domain.V2User) (string, error) {
+	jti, err := newSessionToken()
+	if err != nil {
+		return "", err
+	}
+	return s.jwtManager.GenerateTicket(strconv.FormatUint(user.ID, 10), MFATicketAudience, jti, MFATicketTTL)
+}
+
+// CompleteMFALogin finishes a login that returned an mfa_ticket, given a TOTP or recovery code.
+// 티켓은 한 번만 쓰이고, 틀린 코드가 mfaTicketMaxTries 번이면 티켓이 버려진다(Redis 가 있을 때).
+func (s *V2AuthService) CompleteMFALogin(ticket, code string, meta ...SessionMeta) (*V2LoginResponse, error) {
+	if s.mfa == nil {
+		return nil, common.ErrUnauthorized
+	}
+	claims, err := s.jwtManager.VerifyToken(ticket)
+	if err != nil || !slices.Contains(claims.Audience, MFATicketAudience) || claims.ID == "" {
+		return nil, common.ErrUnauthorized
+	}
+	userID, err := strconv.ParseUint(claims.UserID, 10, 64)
+	if err != nil {
+		return nil, common.ErrUnauthorized
+	}
+
+	ctx := context.Background()
+	key := "mfa:ticket:" + claims.ID
+	if s.redis != nil {
+		tries, err := s.redis.Incr(ctx, key+":tries").Result()
+		if err == nil {
+			s.redis.Expire(ctx, key+":tries", MFATicketTTL)
+			if tries > mfaTicketMaxTries {
+				return nil, common.ErrUnauthorized
+			}
+		}
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.Status == "inactive" {
+		return nil, common.ErrUnauthorized
+	}
+	if err := s.mfa.Verify(user.ID, code); err != nil {
+		return nil, err
+	}
+	// 단일 사용: 같은 티켓으로 두 번째 세션을 만들지 못하게 한다(Redis 실패는 fail-open)
+	if s.redis != nil {
+		if _, err := s.redis.SetArgs(ctx, key+":used", "1",
+			redis.SetArgs{Mode: "NX", TTL: MFATicketTTL}).Result(); errors.Is(err, redis.Nil) {
+			return nil, common.ErrUnauthorized
+		}
+	}
+	return s.completeLogin(user, meta, true)
+}
+
+// PasskeyLogin finishes a login proven by a passkey assertion (WebAuthnService.FinishLogin).
+// 사용자 확인(UV — 생체·PIN)을 거친 패스키는 그 자체로 2단계라 TOTP 를 다시 묻지 않는다.
+// UV 없이 들어온 경우 TOTP 를 켠 회원은 비밀번호 로그인처럼 mfa_ticket 을 받는다.
+func (s *V2AuthService) PasskeyLogin(user *v2domain.V2User, userVerified bool, meta ...SessionMeta) (*V2LoginResponse, error) {
+	if user.Status == "inactive" {
+		return nil, errors.New("account is inactive")
+	}
+	if !userVerified && s.mfa != nil && s.mfa.IsEnabled(user.ID) {
+		ticket, err := s.mfaTicket(user)
+		if err != nil {
+			return nil, err
+		}
+		return &V2LoginResponse{User: user, MFATicket: ticket}, nil
+	}
+	return s.completeLogin(user, meta, userVerified)
+}
+
+// OAuthMemberLogin logs in member mbID after a linked social/OIDC account proved the identity.
+// 제공자는 2단계 인증을 대신하지 않는다 — TOTP 를 켠 회원은 비밀번호 로그인처럼 티켓을 받는다.
+func (s *V2AuthService) OAuthMemberLogin(mbID string, meta ...SessionMeta) (*V2LoginResponse, error) {
+	user, err := s.userRepo.FindByUsername(mbID)
+	if err != nil {
+		// v2_users 로 옮겨지기 전 회원 — g5_member 에서 만든다(탈퇴·정지는 거절)
+		if user, err = s.provisionV2UserFromMember(mbID); err != nil {
+			return nil, common.ErrUnauthorized
+		}
+	}
+	if user.Status == "inactive" {
+		return nil, errors.New("account is inactive")
+	}
+	if s.mfa != nil && s.mfa.IsEnabled(user.ID) {
+		ticket, err := s.mfaTicket(user)
+		if err != nil {
+			return nil, err
+		}
+		return &V2LoginResponse{User: user, MFATicket: ticket}, nil
+	}
+	return s.completeLogin(user, meta, false)
+}
+
+// StepUp re-proves the second factor for a logged-in session and returns an access token with mfa=true.
+// 관리자 MFA 정책(middleware.SetAdminMFARequired)이 켜져 있을 때 관리 화면에 들어가기 전에 쓴다.
+func (s *V2AuthService) StepUp(userID uint64, sessionID, code string) (string, error) {
+	if s.mfa == nil {
+		return "", ErrMFANotEnabled
+	}
+	if err := s.mfa.Verify(userID, code); err != nil {
+		return "", err
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", common.ErrUnauthorized
+	}
+	if s.sessions != nil && sessionID != "" {
+		return s.sessions.StepUp(user, int(user.Level), sessionID)
+	}
+	// 세션 없는(도입 전) 토큰 — 이번 access token 에만 mfa 가 실린다
+	return s.jwtManager.GenerateSessionAccessToken(strconv.FormatUint(user.ID, 10), user.Username, user.Nickname, int(user.Level), "", true)
+}
+
 func (s *V2AuthService) grantLoginXP(username string) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -253,7 +426,7 @@ const AppLoginAudience = "app-login"
 // The code is an HS256 JWT minted by the web (same JWT_SECRET) with
 // aud="app-login", sub=<g5 mb_id> and nickname/email/level claims.
 // If the member has no v2_users row yet, one is auto-provisioned from claims.
-func (s *V2AuthService) AppExchangeLogin(code string) (*V2LoginResponse, error) {
+func (s *V2AuthService) AppExchangeLogin(code string, meta ...SessionMeta) (*V2LoginResponse, error) {
 	claims, err := s.jwtManager.VerifyToken(code)
 	if err != nil {
 		return nil, common.ErrUnauthorized
@@ -312,18 +485,22 @@ func (s *V2AuthService) AppExchangeLogin(code string) (*V2LoginResponse, error)
 		return nil, common.ErrUnauthorized
 	}
 
+	// 웹 소셜 로그인은 2단계를 거치지 않는다 — 켠 회원은 앱에서 코드를 한 번 더 받는다
+	if s.mfa != nil && s.mfa.IsEnabled(user.ID) {
+		ticket, err := s.mfaTicket(user)
+		if err != nil {
+			return nil, err
+		}
+		return &V2LoginResponse{User: user, MFATicket: ticket}, nil
+	}
+
 	if s.expRepo != nil {
 		s.grantLoginXP(user.Username)
 	}
 
-	userIDStr := strconv.FormatUint(user.ID, 10)
-	accessToken, err := s.jwtManager.GenerateAccessToken(userIDStr, user.Username, user.Nickname, int(user.Level))
+	accessToken, refreshToken, err := s.issueTokens(user, int(user.Level), meta, false)
 	if err != nil {
-		return nil, fmt.Errorf("generate access token: %w", err)
-	}
-	refreshToken, err := s.jwtManager.GenerateRefreshToken(userIDStr)
-	if err != nil {
-		return nil, fmt.Errorf("generate refresh token: %w", err)
+		return nil, err
 	}
 
 	return &V2LoginResponse{
@@ -472,10 +649,14 @@ func (s *V2AuthService) provisionV2UserFromMember(mbID string) (*v2domain.V2User
 	return user, nil
 }
 
-// RefreshToken validates a refresh token and issues new token pair
-func (s *V2AuthService) RefreshToken(refreshToken string) (*V2LoginResponse, error) {
+// RefreshToken validates a refresh token and issues new token pair.
+//
+// 세션 모드에서는 sid 가 있는 토큰을 회전하고(재사용이면 패밀리 폐기), sid 가 없는
+// 세션 도입 전 토큰은 한 번만 받아 새 세션으로 옮긴다.
+func (s *V2AuthService) RefreshToken(refreshToken string, meta ...SessionMeta) (*V2LoginResponse, error) {
 	claims, err := s.jwtManager.VerifyToken(refreshToken)
-	if err != nil {
+	// ⛔ 2단계 인증 대기 티켓은 sid 없는 옛 refresh 토큰처럼 보인다 — 받으면 2단계를 건너뛴다
+	if err != nil || claims.Ticket {
 		return nil, common.ErrUnauthorized
 	}
 
@@ -501,34 +682,82 @@ func (s *V2AuthService) RefreshToken(refreshToken string) (*V2LoginResponse, err
 		return nil, common.ErrAccountWithdrawn
 	}
 
-	userIDStr := strconv.FormatUint(user.ID, 10)
-	newAccess, err := s.jwtManager.GenerateAccessToken(userIDStr, user.Username, user.Nickname, int(user.Level))
-	if err != nil {
-		return nil, fmt.Errorf("generate access token: %w", err)
+	// 숙려중이면 정상 세션 갱신이 아니라 취소 가능 상태를 표시한다(핸들러가 쿠키 미설정 + 상태 반환).
+	// ⛔ 세션을 회전하지 않는다 — 새 리프레시 토큰이 클라이언트에 가지 않으므로 회전하면
+	//    취소 뒤 첫 갱신이 재사용으로 판정되어 세션이 끊긴다.
+	if state == common.WithdrawalGrace {
+		newAccess, _, err := s.statelessTokens(user, int(user.Level))
+		if err != nil {
+			return nil, err
+		}
+		days := int(time.Until(deadline).Hours() / 24)
+		if days < 0 {
+			days = 0
+		}
+		return &V2LoginResponse{
+			User:        user,
+			AccessToken: newAccess,
+			WithdrawalGrace: &WithdrawalGraceInfo{
+				LeaveDate:     deadline.AddDate(0, 0, -common.WithdrawalGraceDays).Format("20060102"),
+				Deadline:      deadline.Format("2006-01-02"),
+				DaysRemaining: days,
+			},
+		}, nil
+	}
+
+	var newAccess, newRefresh string
+	switch {
+	case s.sessions != nil && claims.SessionID != "":
+		newAccess, newRefresh, err = s.sessions.Rotate(context.Background(), claims, user, int(user.Level), firstSessionMeta(meta))
+	case s.sessions != nil:
+		// 세션 도입 전 무상태 토큰(웹 발급 토큰은 위에서 이미 1회용으로 소비했다)
+		if claims.UserID != "" && !s.claimLegacyRefresh(refreshToken, claims) {
+			return nil, common.ErrUnauthorized
+		}
+		newAccess, newRefresh, err = s.issueTokens(user, int(user.Level), meta, false)
+	default:
+		newAccess, newRefresh, err = s.statelessTokens(user, int(user.Level))
 	}
-	newRefresh, err := s.jwtManager.GenerateRefreshToken(userIDStr)
 	if err != nil {
-		return nil, fmt.Errorf("generate refresh token: %w", err)
+		return nil, err
 	}
-
-	resp := &V2LoginResponse{
+	return &V2LoginResponse{
 		User:         user,
 		AccessToken:  newAccess,
 		RefreshToken: newRefresh,
+	}, nil
+}
+
+// claimLegacyRefresh lets a pre-session refresh token through exactly once (다음부터는 세션 토큰을 쓴다).
+// Redis 가 없거나 실패하면 통과시킨다 — 옮겨 가는 한 번뿐인 경로라 가용성을 택한다.
+func (s *V2AuthService) claimLegacyRefresh(refreshToken string, claims *jwt.Claims) bool {
+	if s.redis == nil {
+		return true
 	}
-	// 숙려중이면 정상 세션 갱신이 아니라 취소 가능 상태를 표시한다(핸들러가 쿠키 미설정 + 상태 반환).
-	if state == common.WithdrawalGrace {
-		days := int(time.Until(deadline).Hours() / 24)
-		if days < 0 {
-			days = 0
-		}
-		resp.WithdrawalGrace = &WithdrawalGraceInfo{
-			LeaveDate:     deadline.AddDate(0, 0, -common.WithdrawalGraceDays).Format("20060102"),
-			Deadline:      deadline.Format("2006-01-02"),
-			DaysRemaining: days,
+	ttl := time.Minute
+	if claims.ExpiresAt != nil {
+		ttl = max(time.Until(claims.ExpiresAt.Time), time.Minute)
+	}
+	sum := sha256.Sum256([]byte(refreshToken))
+	_, err := s.redis.SetArgs(context.Background(), "authsess:legacy:"+hex.EncodeToString(sum[:]), "1",
+		redis.SetArgs{Mode: "NX", TTL: ttl}).Result()
+	return !errors.Is(err, redis.Nil)
+}
+
+// Logout revokes the server session behind the refresh token or access token sid.
+// 서명이 틀리거나 만료된 토큰은 조용히 넘어간다 — 로그아웃은 쿠키 삭제만으로도 끝나야 한다.
+func (s *V2AuthService) Logout(refreshToken, sessionID string) {
+	if s.sessions == nil {
+		return
+	}
+	if sessionID == "" && refreshToken != "" {
+		if claims, err := s.jwtManager.VerifyToken(refreshToken); err == nil {
+			sessionID = claims.SessionID
 		}
 	}
-	return resp, nil
+	if err := s.sessions.RevokeFamily(context.Background(), sessionID, SessionRevokeLogout); err != nil {
+		log.Printf("[v2-auth] logout session revoke failed: %v", err)
+	}
 }
 
 func (s *V2AuthService) GetCurrentUser(userID uint64) (*v2domain.V2User, error) {
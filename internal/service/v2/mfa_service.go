@@ -0,0 +1,285 @@
+package v2
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/totp"
+)
+
+// 2단계 인증 설정
+const (
+	mfaRecoveryCodeCount = 10
+	mfaTOTPSkew          = 1 // 앞뒤 30초씩 — 기기 시계 오차
+	mfaSealPrefix        = "v1:"
+)
+
+// MFA errors
+var (
+	ErrMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFAInvalidCode    = errors.New("invalid two-factor code")
+)
+
+// MFAStatus is the body of GET /api/v2/auth/mfa
+type MFAStatus struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int64      `json:"recovery_codes_left"`
+}
+
+// MFAEnrollment is what the client renders as a QR code (secret 은 수동 입력용)
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAService manages TOTP enrollment, recovery codes and second-factor checks
+type MFAService struct {
+	repo    v2repo.MFARepository
+	issuer  string
+	sealKey []byte // nil 이면 시크릿을 평문으로 저장한다
+}
+
+// NewMFAService creates a new MFAService. secretKey 가 비어 있지 않으면 TOTP 시크릿을
+// AES-GCM 으로 봉인해 저장한다 — DB 덤프만으로는 코드를 만들 수 없게.
+//
+// ⛔ secretKey 를 바꾸면 기존 등록은 풀 수 없다(재등록 필요). JWT_SECRET 과 분리한 이유다 —
+// JWT 키는 롤오버(SetNextKey)가 있지만 이 키는 롤오버가 없다.
+func NewMFAService(repo v2repo.MFARepository, issuer, secretKey string) *MFAService {
+	s := &MFAService{repo: repo, issuer: issuer}
+	if s.issuer == "" {
+		s.issuer = "Angple"
+	}
+	if secretKey != "" {
+		sum := sha256.Sum256([]byte(secretKey))
+		s.sealKey = sum[:]
+	}
+	return s
+}
+
+// Status returns whether the member has 2FA on and how many recovery codes remain
+func (s *MFAService) Status(userID uint64) (*MFAStatus, error) {
+	mfa, err := s.repo.Find(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return &MFAStatus{}, nil
+	}
+	left, err := s.repo.CountRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &MFAStatus{Enabled: true, EnabledAt: mfa.EnabledAt, RecoveryCodesLeft: left}, nil
+}
+
+// IsEnabled reports whether login needs a second factor for the member.
+// 조회 실패는 true 로 본다 — 2FA 를 켠 계정이 DB 장애로 한 요소 로그인이 되면 안 된다.
+func (s *MFAService) IsEnabled(userID uint64) bool {
+	mfa, err := s.repo.Find(userID)
+	if err != nil {
+		log.Printf("[v2-auth] MFA lookup failed for user %d: %v", userID, err)
+		return true
+	}
+	return mfa != nil && mfa.Enabled
+}
+
+// BeginEnroll creates (or replaces) a pending secret; ConfirmEnroll 로 첫 코드를 확인해야 켜진다.
+func (s *MFAService) BeginEnroll(user *v2domain.V2User) (*MFAEnrollment, error) {
+	existing, err := s.repo.Find(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(&v2domain.V2UserMFA{UserID: user.ID, Username: user.Username, Secret: sealed}); err != nil {
+		return nil, err
+	}
+	return &MFAEnrollment{Secret: secret, ProvisioningURI: totp.ProvisioningURI(s.issuer, user.Username, secret)}, nil
+}
+
+// ConfirmEnroll turns 2FA on after the first valid code and returns fresh recovery codes.
+// 복구 코드 평문은 이 응답에만 있다.
+func (s *MFAService) ConfirmEnroll(userID uint64, code string) ([]string, error) {
+	mfa, err := s.repo.Find(userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, ErrMFANotEnabled
+	}
+	if mfa.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err := s.verifyTOTP(mfa, code); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	mfa.Enabled, mfa.EnabledAt = true, &now
+	if err := s.repo.Save(mfa); err != nil {
+		return nil, err
+	}
+	log.Printf("[v2-auth] MFA enabled for %s", mfa.Username)
+	return s.newRecoveryCodes(userID)
+}
+
+// Disable turns 2FA off; 지금 코드(TOTP 또는 복구 코드)로 본인임을 다시 확인한다.
+func (s *MFAService) Disable(userID uint64, code string) error {
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(userID); err != nil {
+		return err
+	}
+	log.Printf("[v2-auth] MFA disabled for user %d", userID)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code (남은 코드는 모두 무효가 된다)
+func (s *MFAService) RegenerateRecoveryCodes(userID uint64, code string) ([]string, error) {
+	if err := s.Verify(userID, code); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(userID)
+}
+
+// Verify checks a TOTP code or, failing that, consumes a recovery code
+func (s *MFAService) Verify(userID uint64, code string) error {
+	mfa, err := s.repo.Find(userID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return ErrMFANotEnabled
+	}
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(mfa, code)
+	}
+	used, err := s.repo.UseRecoveryCode(userID, hashRecoveryCode(code), time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrMFAInvalidCode
+	}
+	log.Printf("[v2-auth] MFA recovery code used by %s", mfa.Username)
+	return nil
+}
+
+func (s *MFAService) verifyTOTP(mfa *v2domain.V2UserMFA, code string) error {
+	secret, err := s.open(mfa.Secret)
+	if err != nil {
+		return err
+	}
+	step, ok := totp.Validate(secret, code, time.Now(), mfaTOTPSkew)
+	if !ok {
+		return ErrMFAInvalidCode
+	}
+	// 같은 코드를 유효 시간 안에 다시 쓰는 재전송을 막는다
+	claimed, err := s.repo.ClaimStep(mfa.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrMFAInvalidCode
+	}
+	mfa.LastStep = step // 호출자가 mfa 를 Save 해도 기록한 step 을 되돌리지 않게
+	return nil
+}
+
+func (s *MFAService) newRecoveryCodes(userID uint64) ([]string, error) {
+	codes := make([]string, 0, mfaRecoveryCodeCount)
+	hashes := make([]string, 0, mfaRecoveryCodeCount)
+	for range mfaRecoveryCodeCount {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := hex.EncodeToString(b)
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	if err := s.repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode normalizes (대소문자·하이픈·공백 무시) and hashes a recovery code
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *MFAService) seal(secret string) (string, error) {
+	if s.sealKey == nil {
+		return secret, nil
+	}
+	gcm, err := s.gcm()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return mfaSealPrefix + base64.RawStdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+func (s *MFAService) open(stored string) (string, error) {
+	sealed, ok := strings.CutPrefix(stored, mfaSealPrefix)
+	if !ok {
+		return stored, nil // 봉인 키 없이 등록한 시크릿
+	}
+	if s.sealKey == nil {
+		return "", errors.New("MFA secret is sealed but MFA_SECRET_KEY is not set")
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", fmt.Errorf("decode MFA secret: %w", err)
+	}
+	gcm, err := s.gcm()
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < gcm.NonceSize() {
+		return "", errors.New("MFA secret is truncated")
+	}
+	plain, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("open MFA secret: %w", err)
+	}
+	return string(plain), nil
+}
+
+func (s *MFAService) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.sealKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
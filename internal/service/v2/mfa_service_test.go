@@ -0,0 +1,156 @@
+package v2
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/common"
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/totp"
+)
+
+// currentCode returns the TOTP code for the step after the last one used (재전송 방지를 피해 다음 step 을 쓴다)
+func currentCode(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	code, err := totp.CodeAt(secret, totp.Step(time.Now())+offset)
+	if err != nil {
+		t.Fatalf("CodeAt: %v", err)
+	}
+	return code
+}
+
+func TestMFAEnrollLoginAndRecovery(t *testing.T) {
+	svc, _, jwtManager, db := newSessionTestService(t)
+	mfaRepo := v2repo.NewMFARepository(db)
+	if err := mfaRepo.AutoMigrate(); err != nil {
+		t.Fatalf("migrate mfa: %v", err)
+	}
+	mfa := NewMFAService(mfaRepo, "Damoang", "seal-key")
+	svc.SetMFAService(mfa)
+
+	enrollment, err := mfa.BeginEnroll(&v2domain.V2User{ID: 1, Username: "zoe"})
+	if err != nil {
+		t.Fatalf("BeginEnroll: %v", err)
+	}
+	if !strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/Damoang:zoe?") {
+		t.Fatalf("unexpected URI %s", enrollment.ProvisioningURI)
+	}
+	var stored v2domain.V2UserMFA
+	db.First(&stored)
+	if stored.Secret == enrollment.Secret || !strings.HasPrefix(stored.Secret, mfaSealPrefix) {
+		t.Fatal("secret should be stored sealed")
+	}
+
+	// 등록 확인 전에는 로그인이 한 요소 그대로다
+	if resp, err := svc.Login("zoe", "pw"); err != nil || resp.MFATicket != "" {
+		t.Fatalf("pending enrollment should not require MFA: %+v %v", resp, err)
+	}
+	if _, err := mfa.ConfirmEnroll(1, "000000"); !errors.Is(err, ErrMFAInvalidCode) {
+		t.Fatalf("wrong code should fail, got %v", err)
+	}
+	recovery, err := mfa.ConfirmEnroll(1, currentCode(t, enrollment.Secret, -1))
+	if err != nil || len(recovery) != mfaRecoveryCodeCount {
+		t.Fatalf("ConfirmEnroll: %d codes, %v", len(recovery), err)
+	}
+
+	// 비밀번호만으로는 티켓뿐이다
+	resp, err := svc.Login("zoe", "pw")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if resp.MFATicket == "" || resp.AccessToken != "" || resp.RefreshToken != "" {
+		t.Fatalf("login with MFA should return only a ticket: %+v", resp)
+	}
+	// 티켓을 refresh 토큰으로 내밀어 2단계를 건너뛸 수 없다
+	if _, err := svc.RefreshToken(resp.MFATicket); err == nil {
+		t.Fatal("an mfa ticket must not be accepted as a refresh token")
+	}
+	// 이미 쓴 step 의 코드는 다시 받지 않는다
+	if _, err := svc.CompleteMFALogin(resp.MFATicket, currentCode(t, enrollment.Secret, -1)); !errors.Is(err, ErrMFAInvalidCode) {
+		t.Fatalf("replayed TOTP should be rejected, got %v", err)
+	}
+	done, err := svc.CompleteMFALogin(resp.MFATicket, currentCode(t, enrollment.Secret, 0))
+	if err != nil {
+		t.Fatalf("CompleteMFALogin: %v", err)
+	}
+	claims, err := jwtManager.VerifyToken(done.AccessToken)
+	if err != nil || !claims.MFA || claims.SessionID == "" {
+		t.Fatalf("access token should carry mfa and sid: %+v %v", claims, err)
+	}
+	// 회전한 토큰에도 mfa 가 남는다
+	rotated, err := svc.RefreshToken(done.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if claims, _ := jwtManager.VerifyToken(rotated.AccessToken); claims == nil || !claims.MFA { //nolint:errcheck // 방금 발급
+		t.Fatal("rotated access token should keep mfa")
+	}
+
+	// 복구 코드는 한 번만 쓰인다 (대소문자·하이픈 무시)
+	resp, _ = svc.Login("zoe", "pw") //nolint:errcheck // 위에서 확인
+	if _, err := svc.CompleteMFALogin(resp.MFATicket, strings.ToUpper(strings.ReplaceAll(recovery[0], "-", ""))); err != nil {
+		t.Fatalf("recovery code login: %v", err)
+	}
+	if err := mfa.Verify(1, recovery[0]); !errors.Is(err, ErrMFAInvalidCode) {
+		t.Fatalf("used recovery code should be rejected, got %v", err)
+	}
+	status, _ := mfa.Status(1) //nolint:errcheck // 위에서 확인
+	if !status.Enabled || status.RecoveryCodesLeft != mfaRecoveryCodeCount-1 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	// 위조 티켓·access token 은 티켓으로 받지 않는다
+	if _, err := svc.CompleteMFALogin(done.AccessToken, recovery[1]); !errors.Is(err, common.ErrUnauthorized) {
+		t.Fatalf("access token as ticket should be rejected, got %v", err)
+	}
+
+	if err := mfa.Disable(1, recovery[1]); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	if resp, _ := svc.Login("zoe", "pw"); resp == nil || resp.MFATicket != "" { //nolint:errcheck // 아래에서 확인
+		t.Fatal("login after disabling MFA should issue tokens")
+	}
+}
+
+func TestMFAStepUpMarksSession(t *testing.T) {
+	svc, _, jwtManager, db := newSessionTestService(t)
+	mfaRepo := v2repo.NewMFARepository(db)
+	if err := mfaRepo.AutoMigrate(); err != nil {
+		t.Fatalf("migrate mfa: %v", err)
+	}
+	mfa := NewMFAService(mfaRepo, "", "")
+	svc.SetMFAService(mfa)
+
+	// 2FA 를 켜기 전에 로그인한 세션
+	login, err := svc.Login("zoe", "pw")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	claims, _ := jwtManager.VerifyToken(login.AccessToken) //nolint:errcheck // 방금 발급
+	if claims.MFA {
+		t.Fatal("password login should not carry mfa")
+	}
+
+	enrollment, err := mfa.BeginEnroll(&v2domain.V2User{ID: 1, Username: "zoe"})
+	if err != nil {
+		t.Fatalf("BeginEnroll: %v", err)
+	}
+	if _, err := mfa.ConfirmEnroll(1, currentCode(t, enrollment.Secret, -1)); err != nil {
+		t.Fatalf("ConfirmEnroll: %v", err)
+	}
+	stepped, err := svc.StepUp(1, claims.SessionID, currentCode(t, enrollment.Secret, 0))
+	if err != nil {
+		t.Fatalf("StepUp: %v", err)
+	}
+	if c, _ := jwtManager.VerifyToken(stepped); c == nil || !c.MFA || c.SessionID != claims.SessionID { //nolint:errcheck // 아래에서 확인
+		t.Fatalf("step-up token should carry mfa on the same session: %+v", c)
+	}
+	var session v2domain.V2AuthSession
+	db.First(&session)
+	if session.MFAVerifiedAt == nil {
+		t.Fatal("session should record the step-up")
+	}
+}
@@ -0,0 +1,279 @@
+package v2
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/common"
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// 세션 폐기 사유 (v2_auth_sessions.revoke_reason)
+const (
+	SessionRevokeLogout   = "logout"
+	SessionRevokeUser     = "user"
+	SessionRevokeUserAll  = "user_all"
+	SessionRevokeReuse    = "reuse_detected"
+	SessionRevokeAdminBan = "admin_ban"
+)
+
+// sessionStateOKTTL 은 "살아 있음" 캐시 수명이다. 폐기는 캐시를 바로 "revoked" 로 덮으므로
+// 이 값은 Redis 쓰기가 실패했을 때 폐기가 늦게 닿는 최대 시간이 된다.
+const sessionStateOKTTL = 60 * time.Second
+
+// ErrSessionNotFound is returned when the member has no such active session
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionMeta describes the device a login or refresh came from
+type SessionMeta struct {
+	UserAgent  string
+	IP         string
+	Platform   string // web | mobile
+	DeviceName string
+}
+
+// SessionView is one row of GET /api/v2/me/sessions
+type SessionView struct {
+	v2domain.V2AuthSession
+	Current bool `json:"current"`
+}
+
+// SessionService stores refresh token families (v2_auth_sessions) and rotates them.
+//
+// 리프레시 토큰마다 sid(패밀리)·jti(회전 번호)를 싣고, DB 에는 지금 유효한 jti 하나만 둔다.
+// 갱신은 jti 를 조건부로 바꾸는 UPDATE 한 번이라 같은 토큰은 한 번만 통과한다 —
+// 두 번째로 온 토큰은 탈취된 사본으로 보고 패밀리 전체를 폐기한다.
+// 폐기 여부는 Redis(authsess:state:<sid>)에 캐시해 JWTAuth 가 요청마다 DB 를 치지 않게 한다.
+type SessionService struct {
+	repo       v2repo.SessionRepository
+	jwtManager *jwt.Manager
+	redis      *redis.Client
+}
+
+// NewSessionService creates a new SessionService
+func NewSessionService(repo v2repo.SessionRepository, jwtManager *jwt.Manager) *SessionService {
+	return &SessionService{repo: repo, jwtManager: jwtManager}
+}
+
+// SetRedis sets the Redis client used as the session state cache (없으면 매번 DB 를 본다)
+func (s *SessionService) SetRedis(rc *redis.Client) {
+	s.redis = rc
+}
+
+func sessionStateKey(sid string) string { return "authsess:state:" + sid }
+
+func newSessionToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Issue starts a new session for user and returns its token pair (mfa: 2단계 인증을 거친 로그인)
+func (s *SessionService) Issue(ctx context.Context, user *v2domain.V2User, level int, meta SessionMeta, mfa bool) (accessToken, refreshToken string, err error) {
+	sid, err := newSessionToken()
+	if err != nil {
+		return "", "", err
+	}
+	jti, err := newSessionToken()
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now()
+	session := &v2domain.V2AuthSession{
+		FamilyID:   sid,
+		UserID:     user.ID,
+		Username:   user.Username,
+		TokenID:    jti,
+		DeviceName: truncateRunes(meta.DeviceName, 100),
+		Platform:   truncateRunes(meta.Platform, 16),
+		UserAgent:  truncateRunes(meta.UserAgent, 255),
+		IP:         meta.IP,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.jwtManager.RefreshExpiry()),
+	}
+	if session.Platform == "" {
+		session.Platform = "web"
+	}
+	if mfa {
+		session.MFAVerifiedAt = &now
+	}
+	if err := s.repo.Create(session); err != nil {
+		return "", "", err
+	}
+	return s.tokens(user, level, sid, jti, mfa)
+}
+
+// Rotate exchanges the refresh token described by claims for the next one in its family.
+// 이미 회전된 jti 가 다시 오면 패밀리를 폐기하고 ErrUnauthorized 를 돌려준다.
+func (s *SessionService) Rotate(ctx context.Context, claims *jwt.Claims, user *v2domain.V2User, level int, meta SessionMeta) (accessToken, refreshToken string, err error) {
+	// ⛔ 세션 access token 도 sid 를 싣는다 — jti 가 없거나 access 클레임이 있으면 리프레시 토큰이 아니다.
+	//    재사용 판정까지 가면 access token 만 가진 쪽(WS 서버·플러그인 포함)이 세션을 끊을 수 있다
+	if claims.ID == "" || claims.Username != "" || claims.Nickname != "" {
+		return "", "", common.ErrUnauthorized
+	}
+	session, err := s.repo.FindByFamily(claims.SessionID)
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now()
+	if session == nil || session.RevokedAt != nil || !session.ExpiresAt.After(now) || session.UserID != user.ID {
+		return "", "", common.ErrUnauthorized
+	}
+	jti, err := newSessionToken()
+	if err != nil {
+		return "", "", err
+	}
+	ok, err := s.repo.Rotate(session.FamilyID, claims.ID, jti, meta.IP, now, now.Add(s.jwtManager.RefreshExpiry()))
+	if err != nil {
+		return "", "", err
+	}
+	if !ok {
+		// 이미 쓴 리프레시 토큰의 재사용 — 공격자와 정상 기기 중 누가 먼저 썼는지 알 수 없으므로
+		// 둘 다 끊는다. ⛔ 클라이언트가 응답을 잃고 같은 토큰으로 재시도해도 여기로 온다(재로그인).
+		log.Printf("[v2-auth] refresh token reuse detected: user=%s session=%d ip=%s", user.Username, session.ID, meta.IP)
+		if _, err := s.revoke(ctx, s.repo.Scope().Where("family_id = ?", session.FamilyID), SessionRevokeReuse); err != nil {
+			log.Printf("[v2-auth] revoke reused session %d failed: %v", session.ID, err)
+		}
+		return "", "", common.ErrUnauthorized
+	}
+	return s.tokens(user, level, session.FamilyID, jti, session.MFAVerifiedAt != nil)
+}
+
+// StepUp marks the session as second-factor verified and returns an access token that says so.
+// 리프레시 토큰은 그대로다 — 다음 회전부터 새 access token 에도 mfa 가 실린다.
+func (s *SessionService) StepUp(user *v2domain.V2User, level int, sid string) (string, error) {
+	ok, err := s.repo.MarkMFA(sid, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", common.ErrUnauthorized
+	}
+	return s.jwtManager.GenerateSessionAccessToken(strconv.FormatUint(user.ID, 10), user.Username, user.Nickname, level, sid, true)
+}
+
+func (s *SessionService) tokens(user *v2domain.V2User, level int, sid, jti string, mfa bool) (string, string, error) {
+	userIDStr := strconv.FormatUint(user.ID, 10)
+	accessToken, err := s.jwtManager.GenerateSessionAccessToken(userIDStr, user.Username, user.Nickname, level, sid, mfa)
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err := s.jwtManager.GenerateSessionRefreshToken(userIDStr, sid, jti)
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+// List returns the member's active sessions, marking currentSID
+func (s *SessionService) List(userID uint64, currentSID string) ([]SessionView, error) {
+	sessions, err := s.repo.ListActiveByUser(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	views := make([]SessionView, 0, len(sessions))
+	for i := range sessions {
+		views = append(views, SessionView{V2AuthSession: sessions[i], Current: sessions[i].FamilyID == currentSID})
+	}
+	return views, nil
+}
+
+// Revoke logs out one of the member's sessions by its id
+func (s *SessionService) Revoke(ctx context.Context, userID, id uint64) error {
+	n, err := s.revoke(ctx, s.repo.Scope().Where("id = ? AND user_id = ?", id, userID), SessionRevokeUser)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeFamily logs out the session a refresh token or access token belongs to (로그아웃)
+func (s *SessionService) RevokeFamily(ctx context.Context, sid, reason string) error {
+	if sid == "" {
+		return nil
+	}
+	_, err := s.revoke(ctx, s.repo.Scope().Where("family_id = ?", sid), reason)
+	return err
+}
+
+// RevokeAll logs out every session of the member except exceptSID ("" 이면 전부)
+func (s *SessionService) RevokeAll(ctx context.Context, userID uint64, exceptSID string) (int, error) {
+	q := s.repo.Scope().Where("user_id = ?", userID)
+	if exceptSID != "" {
+		q = q.Where("family_id <> ?", exceptSID)
+	}
+	return s.revoke(ctx, q, SessionRevokeUserAll)
+}
+
+// RevokeAllForMember logs out every session of the member by mb_id (관리자 강제 로그아웃)
+func (s *SessionService) RevokeAllForMember(ctx context.Context, mbID, reason string) (int64, error) {
+	n, err := s.revoke(ctx, s.repo.Scope().Where("username = ?", mbID), reason)
+	return int64(n), err
+}
+
+// revoke marks the sessions matched by where and flips their cached state right away
+func (s *SessionService) revoke(ctx context.Context, where *gorm.DB, reason string) (int, error) {
+	families, err := s.repo.Revoke(where, reason, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	for _, sid := range families {
+		s.cacheState(ctx, sid, true)
+	}
+	return len(families), nil
+}
+
+// IsRevoked reports whether the session is revoked or gone (middleware.SetSessionCheck 로 주입).
+// 조회 실패는 false 로 본다 — 로그인 가용성이 우선이고, 폐기된 토큰도 access TTL 안에 끝난다.
+func (s *SessionService) IsRevoked(ctx context.Context, sid string) bool {
+	if s.redis != nil {
+		if state, err := s.redis.Get(ctx, sessionStateKey(sid)).Result(); err == nil {
+			return state == "revoked"
+		}
+	}
+	session, err := s.repo.FindByFamily(sid)
+	if err != nil {
+		log.Printf("[v2-auth] session state lookup failed for %s: %v", sid, err)
+		return false
+	}
+	revoked := session == nil || session.RevokedAt != nil || !session.ExpiresAt.After(time.Now())
+	s.cacheState(ctx, sid, revoked)
+	return revoked
+}
+
+func (s *SessionService) cacheState(ctx context.Context, sid string, revoked bool) {
+	if s.redis == nil {
+		return
+	}
+	state, ttl := "ok", sessionStateOKTTL
+	if revoked {
+		// access token 이 살아 있는 동안만 기억하면 된다
+		state, ttl = "revoked", s.jwtManager.AccessExpiry()
+	}
+	if err := s.redis.Set(ctx, sessionStateKey(sid), state, ttl).Err(); err != nil {
+		log.Printf("[v2-auth] session state cache write failed for %s: %v", sid, err)
+	}
+}
+
+func truncateRunes(v string, n int) string {
+	v = strings.TrimSpace(v)
+	if r := []rune(v); len(r) > n {
+		return string(r[:n])
+	}
+	return v
+}
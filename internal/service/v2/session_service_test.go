@@ -0,0 +1,195 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/damoang/angple-backend/internal/common"
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newSessionTestService(t *testing.T) (*V2AuthService, *SessionService, *jwt.Manager, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=private"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE v2_users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		username TEXT, email TEXT, password TEXT, nickname TEXT, level INTEGER, status TEXT,
+		point INTEGER DEFAULT 0, exp INTEGER DEFAULT 0,
+		nariya_level INTEGER DEFAULT 1, nariya_max INTEGER DEFAULT 1000,
+		avatar_url TEXT, bio TEXT,
+		created_at DATETIME, updated_at DATETIME
+	)`).Error; err != nil {
+		t.Fatalf("create v2_users: %v", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte("pw"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	db.Exec(`INSERT INTO v2_users (id, username, nickname, password, level, status) VALUES (1, 'zoe', '조', ?, 2, 'active')`, string(hash))
+
+	sessionRepo := v2repo.NewSessionRepository(db)
+	if err := sessionRepo.AutoMigrate(); err != nil {
+		t.Fatalf("migrate sessions: %v", err)
+	}
+	jwtManager := jwt.NewManager("test-secret", 900, 604800)
+	sessions := NewSessionService(sessionRepo, jwtManager)
+	svc := NewV2AuthService(v2repo.NewUserRepository(db), jwtManager, nil)
+	svc.SetSessionService(sessions)
+	return svc, sessions, jwtManager, db
+}
+
+func TestSessionRefreshRotatesAndDetectsReuse(t *testing.T) {
+	svc, sessions, jwtManager, db := newSessionTestService(t)
+	ctx := context.Background()
+
+	login, err := svc.Login("zoe", "pw", SessionMeta{UserAgent: "test-agent", IP: "10.0.0.1", Platform: "mobile"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	claims, err := jwtManager.VerifyToken(login.AccessToken)
+	if err != nil || claims.SessionID == "" {
+		t.Fatalf("access token should carry sid, got %+v (%v)", claims, err)
+	}
+
+	rotated, err := svc.RefreshToken(login.RefreshToken, SessionMeta{IP: "10.0.0.2"})
+	if err != nil {
+		t.Fatalf("first refresh: %v", err)
+	}
+	if rotated.RefreshToken == login.RefreshToken {
+		t.Fatal("refresh should rotate the refresh token")
+	}
+	var session v2domain.V2AuthSession
+	db.First(&session)
+	if session.Generation != 1 || session.IP != "10.0.0.2" || session.Platform != "mobile" {
+		t.Fatalf("unexpected session after rotation: %+v", session)
+	}
+
+	// 옛 토큰 재사용 → 거부 + 패밀리 폐기(새 토큰까지 무효)
+	if _, err := svc.RefreshToken(login.RefreshToken); !errors.Is(err, common.ErrUnauthorized) {
+		t.Fatalf("replayed refresh should be rejected, got %v", err)
+	}
+	if _, err := svc.RefreshToken(rotated.RefreshToken); !errors.Is(err, common.ErrUnauthorized) {
+		t.Fatalf("family should be revoked after reuse, got %v", err)
+	}
+	db.First(&session)
+	if session.RevokedAt == nil || session.RevokeReason != SessionRevokeReuse {
+		t.Fatalf("session should be revoked for reuse, got %+v", session)
+	}
+	if !sessions.IsRevoked(ctx, claims.SessionID) {
+		t.Error("access tokens of a reused family should be rejected")
+	}
+}
+
+func TestSessionRefreshRejectsAccessTokenWithoutRevoking(t *testing.T) {
+	svc, sessions, jwtManager, db := newSessionTestService(t)
+
+	login, err := svc.Login("zoe", "pw", SessionMeta{IP: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	claims, err := jwtManager.VerifyToken(login.AccessToken)
+	if err != nil || claims.SessionID == "" {
+		t.Fatalf("access token should carry sid, got %+v (%v)", claims, err)
+	}
+
+	// access token 을 refresh 에 넣어도 재사용으로 보지 않는다 — 거부만 하고 세션은 그대로
+	if _, err := svc.RefreshToken(login.AccessToken); !errors.Is(err, common.ErrUnauthorized) {
+		t.Fatalf("access token should not refresh, got %v", err)
+	}
+	var session v2domain.V2AuthSession
+	db.First(&session)
+	if session.RevokedAt != nil || session.Generation != 0 {
+		t.Fatalf("session should be untouched, got %+v", session)
+	}
+	if sessions.IsRevoked(context.Background(), claims.SessionID) {
+		t.Fatal("access token should stay valid")
+	}
+	if _, err := svc.RefreshToken(login.RefreshToken); err != nil {
+		t.Fatalf("real refresh token should still rotate: %v", err)
+	}
+}
+
+func TestSessionRevokeAllKeepsCurrent(t *testing.T) {
+	svc, sessions, jwtManager, _ := newSessionTestService(t)
+	ctx := context.Background()
+
+	var sids []string
+	for range 3 {
+		login, err := svc.Login("zoe", "pw")
+		if err != nil {
+			t.Fatalf("login: %v", err)
+		}
+		claims, _ := jwtManager.VerifyToken(login.AccessToken) //nolint:errcheck // 방금 발급
+		sids = append(sids, claims.SessionID)
+	}
+
+	list, err := sessions.List(1, sids[0])
+	if err != nil || len(list) != 3 {
+		t.Fatalf("expected 3 sessions, got %d (%v)", len(list), err)
+	}
+
+	n, err := sessions.RevokeAll(ctx, 1, sids[0])
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 revoked, got %d (%v)", n, err)
+	}
+	if sessions.IsRevoked(ctx, sids[0]) {
+		t.Error("current session should survive revoke-all")
+	}
+	if !sessions.IsRevoked(ctx, sids[1]) || !sessions.IsRevoked(ctx, sids[2]) {
+		t.Error("other sessions should be revoked")
+	}
+	list, _ = sessions.List(1, sids[0]) //nolint:errcheck // 위에서 확인
+	if len(list) != 1 || !list[0].Current {
+		t.Fatalf("only the current session should remain, got %+v", list)
+	}
+
+	if err := sessions.Revoke(ctx, 1, list[0].ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if err := sessions.Revoke(ctx, 1, list[0].ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("second revoke should be not found, got %v", err)
+	}
+
+	// 관리자 강제 로그아웃은 mb_id 로 남은 세션을 전부 끊는다
+	if _, err := svc.Login("zoe", "pw"); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if n, err := sessions.RevokeAllForMember(ctx, "zoe", SessionRevokeAdminBan); err != nil || n != 1 {
+		t.Fatalf("expected 1 session revoked by ban, got %d (%v)", n, err)
+	}
+}
+
+func TestSessionLegacyRefreshMigratesAndLogout(t *testing.T) {
+	svc, sessions, jwtManager, _ := newSessionTestService(t)
+	ctx := context.Background()
+
+	legacy, err := jwtManager.GenerateRefreshToken("1")
+	if err != nil {
+		t.Fatalf("gen refresh: %v", err)
+	}
+	resp, err := svc.RefreshToken(legacy)
+	if err != nil {
+		t.Fatalf("legacy refresh: %v", err)
+	}
+	claims, err := jwtManager.VerifyToken(resp.RefreshToken)
+	if err != nil || claims.SessionID == "" {
+		t.Fatalf("legacy refresh should move to a session token, got %+v (%v)", claims, err)
+	}
+
+	svc.Logout(resp.RefreshToken, "")
+	if !sessions.IsRevoked(ctx, claims.SessionID) {
+		t.Error("logout should revoke the session")
+	}
+	if _, err := svc.RefreshToken(resp.RefreshToken); !errors.Is(err, common.ErrUnauthorized) {
+		t.Fatalf("refresh after logout should be rejected, got %v", err)
+	}
+}
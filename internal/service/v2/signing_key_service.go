@@ -0,0 +1,317 @@
+package v2
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/jwt"
+)
+
+// JWT 서명키 로테이션 설정
+const (
+	// signingKeyPrepublish 는 다음 키를 활성 전에 JWKS 에 미리 싣는 기간이다 — 게임 서버 등 검증 측 캐시가 먼저 받아 두도록
+	signingKeyPrepublish = 24 * time.Hour
+	// signingKeyReloadEvery 는 레플리카가 DB 키링을 다시 읽는 간격이다 (다른 레플리카가 만든 키·은퇴 반영)
+	signingKeyReloadEvery = 5 * time.Minute
+	signingKeySealPrefix  = "v1:"
+)
+
+// KeyRotationResult is the summary of one jwt-key-rotation run
+type KeyRotationResult struct {
+	Created     string     `json:"created,omitempty"`
+	ActivatesAt *time.Time `json:"activates_at,omitempty"`
+	Retired     []string   `json:"retired,omitempty"`
+	Deleted     int64      `json:"deleted"`
+}
+
+// SigningKeyService keeps the asymmetric JWT signing keys in the DB and installs them into jwt.Manager.
+//
+// 키 하나의 수명: 생성(활성 24시간 전, JWKS 에 공개) → 활성(서명) → 다음 키 활성 후 리프레시 토큰
+// 수명만큼 검증 전용 → 은퇴(삭제). 로테이션은 cron 잡(jwt-key-rotation)이 레플리카 하나에서만 한다.
+type SigningKeyService struct {
+	repo        v2repo.SigningKeyRepository
+	manager     *jwt.Manager
+	algorithm   string
+	rotateEvery time.Duration
+	sealKey     []byte // nil 이면 개인키를 봉인하지 않는다
+}
+
+// NewSigningKeyService creates a new SigningKeyService.
+// algorithm 이 HS256(또는 빈 값)이면 서명키를 만들지 않고, 남아 있는 키는 검증에만 쓴다.
+//
+// ⛔ sealSecret 을 바꾸면 저장된 개인키를 열 수 없다 — 기동이 실패하므로 키 행을 지우고 새로 만들 것
+// (그동안 발급된 토큰은 무효가 된다).
+func NewSigningKeyService(repo v2repo.SigningKeyRepository, manager *jwt.Manager, algorithm string, rotateEvery time.Duration, sealSecret string) *SigningKeyService {
+	s := &SigningKeyService{repo: repo, manager: manager, algorithm: algorithm, rotateEvery: rotateEvery}
+	if s.rotateEvery < 2*signingKeyPrepublish {
+		s.rotateEvery = 2 * signingKeyPrepublish
+	}
+	if sealSecret != "" {
+		sum := sha256.Sum256([]byte(sealSecret))
+		s.sealKey = sum[:]
+	}
+	return s
+}
+
+// Signing reports whether tokens are signed with asymmetric keys (ES256·EdDSA)
+func (s *SigningKeyService) Signing() bool {
+	return s.algorithm == jwt.AlgES256 || s.algorithm == jwt.AlgEdDSA
+}
+
+// Start installs the DB keyring into the manager and reloads it periodically until ctx is done.
+// 서명 설정인데 활성 키가 하나도 없으면(첫 배포) 바로 활성되는 키를 만든다 — 레플리카가 동시에
+// 만들면 키가 둘이 되지만, 다음 로테이션이 먼저 만든 쪽을 은퇴시킨다.
+func (s *SigningKeyService) Start(ctx context.Context) error {
+	if s.Signing() {
+		if err := s.bootstrap(time.Now()); err != nil {
+			return err
+		}
+	}
+	s.manager.SetKeySource(s.Keys)
+	if err := s.manager.ReloadKeys(ctx); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(signingKeyReloadEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.manager.ReloadKeys(ctx); err != nil {
+					log.Printf("[JWT] signing key reload failed: %v", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Keys loads the keyring from the DB (jwt.KeySource).
+// HS256 설정이면 개인키는 싣지 않는다 — ES256·EdDSA 에서 되돌려도 이미 발급한 토큰은 만료까지 통한다.
+func (s *SigningKeyService) Keys(_ context.Context) ([]*jwt.SigningKey, error) {
+	rows, err := s.repo.List(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*jwt.SigningKey, 0, len(rows))
+	for i := range rows {
+		key, err := s.decode(&rows[i])
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", rows[i].KID, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Rotate creates the next key ahead of time, retires keys replaced by the active one and
+// deletes retired keys. 한 번에 한 레플리카에서만 돌린다 (cron 리스).
+func (s *SigningKeyService) Rotate(ctx context.Context) (*KeyRotationResult, error) {
+	now := time.Now()
+	rows, err := s.repo.List(now)
+	if err != nil {
+		return nil, err
+	}
+	result := &KeyRotationResult{}
+
+	var active *v2domain.V2JWTSigningKey
+	pending := false
+	for i := range rows { // activates_at 오름차순 — 마지막으로 활성된 키가 서명 키다
+		if rows[i].ActivatesAt.After(now) {
+			pending = true
+		} else {
+			active = &rows[i]
+		}
+	}
+
+	switch {
+	case active == nil && !pending:
+		created, err := s.create(now)
+		if err != nil {
+			return nil, err
+		}
+		active = created
+		result.Created, result.ActivatesAt = created.KID, &created.ActivatesAt
+	case active != nil && !pending && s.due(active, now):
+		activatesAt := active.ActivatesAt.Add(s.rotateEvery)
+		if earliest := now.Add(signingKeyPrepublish); activatesAt.Before(earliest) {
+			activatesAt = earliest
+		}
+		created, err := s.create(activatesAt)
+		if err != nil {
+			return nil, err
+		}
+		result.Created, result.ActivatesAt = created.KID, &created.ActivatesAt
+	}
+
+	// 활성 키보다 먼저 활성된 키는 그 키로 서명한 리프레시 토큰이 만료될 때까지만 검증에 쓴다.
+	// 다른 레플리카가 새 키를 읽어 들이기 전까지 옛 키로 서명할 수 있으니 재적재 간격만큼 더 둔다.
+	if active != nil {
+		retiresAt := active.ActivatesAt.Add(s.manager.RefreshExpiry() + signingKeyReloadEvery)
+		for i := range rows {
+			row := &rows[i]
+			if row.KID == active.KID || row.RetiresAt != nil || row.ActivatesAt.After(active.ActivatesAt) {
+				continue
+			}
+			ok, err := s.repo.Retire(row.KID, retiresAt)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				result.Retired = append(result.Retired, row.KID)
+			}
+		}
+	}
+
+	if result.Deleted, err = s.repo.DeleteRetired(now); err != nil {
+		return nil, err
+	}
+	keys, err := s.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.manager.SetSigningKeys(keys)
+	return result, nil
+}
+
+// due reports whether the next key should be published now: 활성 키가 수명 끝에서 하루 안쪽이거나 알고리즘 설정이 바뀌었다
+func (s *SigningKeyService) due(active *v2domain.V2JWTSigningKey, now time.Time) bool {
+	return active.Algorithm != s.algorithm || now.Sub(active.ActivatesAt) >= s.rotateEvery-signingKeyPrepublish
+}
+
+func (s *SigningKeyService) bootstrap(now time.Time) error {
+	rows, err := s.repo.List(now)
+	if err != nil {
+		return err
+	}
+	for i := range rows {
+		if !rows[i].ActivatesAt.After(now) {
+			return nil
+		}
+	}
+	_, err = s.create(now)
+	return err
+}
+
+func (s *SigningKeyService) create(activatesAt time.Time) (*v2domain.V2JWTSigningKey, error) {
+	key, err := jwt.GenerateSigningKey(s.algorithm)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := x509.MarshalPKCS8PrivateKey(key.Private)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := x509.MarshalPKIXPublicKey(key.Public)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.seal(priv)
+	if err != nil {
+		return nil, err
+	}
+	row := &v2domain.V2JWTSigningKey{
+		KID:         key.ID,
+		Algorithm:   key.Algorithm,
+		PrivateKey:  sealed,
+		PublicKey:   base64.StdEncoding.EncodeToString(pub),
+		ActivatesAt: activatesAt,
+	}
+	if err := s.repo.Create(row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func (s *SigningKeyService) decode(row *v2domain.V2JWTSigningKey) (*jwt.SigningKey, error) {
+	der, err := base64.StdEncoding.DecodeString(row.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode public key: %w", err)
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	key := &jwt.SigningKey{ID: row.KID, Algorithm: row.Algorithm, Public: pub, ActivatesAt: row.ActivatesAt, RetiresAt: row.RetiresAt}
+	if !s.Signing() {
+		return key, nil
+	}
+	der, err = s.open(row.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, jwt.ErrUnsupportedAlgorithm
+	}
+	key.Private = signer
+	return key, nil
+}
+
+func (s *SigningKeyService) seal(der []byte) (string, error) {
+	if s.sealKey == nil {
+		return base64.RawStdEncoding.EncodeToString(der), nil
+	}
+	gcm, err := s.gcm()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return signingKeySealPrefix + base64.RawStdEncoding.EncodeToString(gcm.Seal(nonce, nonce, der, nil)), nil
+}
+
+func (s *SigningKeyService) open(stored string) ([]byte, error) {
+	sealed, ok := strings.CutPrefix(stored, signingKeySealPrefix)
+	if !ok {
+		return base64.RawStdEncoding.DecodeString(stored) // 봉인 키 없이 만든 키
+	}
+	if s.sealKey == nil {
+		return nil, errors.New("signing key is sealed but JWT_KEY_SECRET is not set")
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("decode signing key: %w", err)
+	}
+	gcm, err := s.gcm()
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < gcm.NonceSize() {
+		return nil, errors.New("signing key is truncated")
+	}
+	der, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("open signing key: %w", err)
+	}
+	return der, nil
+}
+
+func (s *SigningKeyService) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.sealKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
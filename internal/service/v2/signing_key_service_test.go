@@ -0,0 +1,137 @@
+package v2
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/jwt"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newSigningKeyTestService(t *testing.T, algorithm string) (*SigningKeyService, *jwt.Manager, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=private"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	repo := v2repo.NewSigningKeyRepository(db)
+	if err := repo.AutoMigrate(); err != nil {
+		t.Fatalf("migrate signing keys: %v", err)
+	}
+	manager := jwt.NewManager("test-secret", 900, 604800)
+	return NewSigningKeyService(repo, manager, algorithm, 30*24*time.Hour, "seal-secret"), manager, db
+}
+
+func listSigningKeys(t *testing.T, db *gorm.DB) []v2domain.V2JWTSigningKey {
+	t.Helper()
+	var rows []v2domain.V2JWTSigningKey
+	if err := db.Order("activates_at ASC").Find(&rows).Error; err != nil {
+		t.Fatalf("list keys: %v", err)
+	}
+	return rows
+}
+
+func TestSigningKeyBootstrapAndSeal(t *testing.T) {
+	svc, manager, db := newSigningKeyTestService(t, jwt.AlgES256)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := svc.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	rows := listSigningKeys(t, db)
+	if len(rows) != 1 || !strings.HasPrefix(rows[0].PrivateKey, signingKeySealPrefix) {
+		t.Fatalf("expected one sealed key, got %+v", rows)
+	}
+
+	token, err := manager.GenerateAccessToken("zoe", "zoe", "조", 2)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := manager.VerifyToken(token); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if set := manager.JWKS(); len(set.Keys) != 1 || set.Keys[0].Kid != rows[0].KID {
+		t.Fatalf("unexpected JWKS %+v", set)
+	}
+
+	// 다른 봉인 키로는 열 수 없다 — 조용히 HS256 으로 돌아가지 않고 실패한다
+	other := NewSigningKeyService(v2repo.NewSigningKeyRepository(db), jwt.NewManager("test-secret", 900, 604800), jwt.AlgES256, 0, "other")
+	if _, err := other.Keys(ctx); err == nil {
+		t.Fatal("a wrong seal secret must fail to load keys")
+	}
+}
+
+func TestSigningKeyRotation(t *testing.T) {
+	svc, manager, db := newSigningKeyTestService(t, jwt.AlgEdDSA)
+	ctx := context.Background()
+	now := time.Now()
+
+	// 활성된 지 29일 된 키 — 수명(30일) 끝 하루 안쪽이라 다음 키를 미리 공개한다
+	old, err := svc.create(now.Add(-29 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	result, err := svc.Rotate(ctx)
+	if err != nil || result.Created == "" || len(result.Retired) != 0 {
+		t.Fatalf("first rotation: %+v %v", result, err)
+	}
+	if result.ActivatesAt.Before(now.Add(signingKeyPrepublish)) {
+		t.Fatalf("next key activates too early: %v", result.ActivatesAt)
+	}
+	if set := manager.JWKS(); len(set.Keys) != 2 {
+		t.Fatalf("next key must be pre-published, got %+v", set)
+	}
+	// 이미 다음 키가 있으면 또 만들지 않는다
+	if again, err := svc.Rotate(ctx); err != nil || again.Created != "" {
+		t.Fatalf("second rotation: %+v %v", again, err)
+	}
+
+	// 다음 키가 활성되면 이전 키는 리프레시 토큰 수명만큼만 남는다
+	oldToken, _ := manager.GenerateAccessToken("zoe", "zoe", "조", 2)
+	db.Model(&v2domain.V2JWTSigningKey{}).Where("kid = ?", result.Created).Update("activates_at", now.Add(-time.Minute))
+	result, err = svc.Rotate(ctx)
+	if err != nil || len(result.Retired) != 1 || result.Retired[0] != old.KID {
+		t.Fatalf("retire rotation: %+v %v", result, err)
+	}
+	if _, err := manager.VerifyToken(oldToken); err != nil {
+		t.Fatalf("old key must verify inside the overlap: %v", err)
+	}
+
+	// 은퇴 시각이 지나면 지운다
+	db.Model(&v2domain.V2JWTSigningKey{}).Where("kid = ?", old.KID).Update("retires_at", now.Add(-time.Second))
+	if result, err = svc.Rotate(ctx); err != nil || result.Deleted != 1 {
+		t.Fatalf("delete rotation: %+v %v", result, err)
+	}
+	if _, err := manager.VerifyToken(oldToken); err == nil {
+		t.Fatal("a deleted key must not verify")
+	}
+}
+
+func TestSigningKeyHS256KeepsVerifying(t *testing.T) {
+	svc, manager, db := newSigningKeyTestService(t, jwt.AlgES256)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := svc.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	issued, _ := manager.GenerateAccessToken("zoe", "zoe", "조", 2)
+
+	// ES256 → HS256 으로 되돌린 레플리카: HS256 으로 서명하지만 이미 발급한 ES256 토큰도 받는다
+	back := jwt.NewManager("test-secret", 900, 604800)
+	hs := NewSigningKeyService(v2repo.NewSigningKeyRepository(db), back, "HS256", 0, "seal-secret")
+	if err := hs.Start(ctx); err != nil {
+		t.Fatalf("Start HS256: %v", err)
+	}
+	if _, err := back.VerifyToken(issued); err != nil {
+		t.Fatalf("ES256 token after switching back: %v", err)
+	}
+	token, _ := back.GenerateAccessToken("zoe", "zoe", "조", 2)
+	if !strings.HasPrefix(token, "eyJhbGciOiJIUzI1NiIs") {
+		t.Fatalf("expected an HS256 token, got %s", token)
+	}
+}
@@ -0,0 +1,396 @@
+package v2
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/redis/go-redis/v9"
+)
+
+// 패스키 설정
+const (
+	webAuthnMaxPerUser   = 10
+	webAuthnMaxName      = 100
+	webAuthnCeremonyTTL  = 5 * time.Minute // 브라우저 프롬프트 대기(기본 타임아웃)보다 길게
+	webAuthnDefaultName  = "패스키"
+	webAuthnRedisPrefix  = "webauthn:"
+	webAuthnUserHandleSz = 8 // v2_users.id big-endian
+)
+
+// Passkey errors
+var (
+	ErrPasskeyNotFound = errors.New("passkey not found")
+	ErrPasskeyLimit    = errors.New("passkey limit reached")
+	ErrPasskeyExists   = errors.New("passkey is already registered")
+	ErrPasskeyName     = errors.New("invalid passkey name")
+	// ErrPasskeyInvalid: 챌린지 만료·서명 불일치·카운터 역행 등 — 자세한 이유는 로그에만 남긴다
+	ErrPasskeyInvalid = errors.New("passkey verification failed")
+	// ErrPasskeyStepUpRequired: 2단계 인증을 켠 회원은 mfa 가 실린 토큰으로만 패스키를 추가할 수 있다
+	ErrPasskeyStepUpRequired = errors.New("two-factor step-up required to add a passkey")
+)
+
+// webAuthnUser adapts a member and their credentials to webauthn.User.
+// user handle 은 v2_users.id 8바이트다 — 아이디(mb_id)는 바뀔 수 있고 인증기에 남기기엔 개인정보다.
+type webAuthnUser struct {
+	user  *v2domain.V2User
+	creds []v2domain.V2WebAuthnCredential
+}
+
+func webAuthnUserHandle(userID uint64) []byte {
+	b := make([]byte, webAuthnUserHandleSz)
+	binary.BigEndian.PutUint64(b, userID)
+	return b
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte          { return webAuthnUserHandle(u.user.ID) }
+func (u *webAuthnUser) WebAuthnName() string        { return u.user.Username }
+func (u *webAuthnUser) WebAuthnDisplayName() string { return u.user.Nickname }
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	out := make([]webauthn.Credential, 0, len(u.creds))
+	for i := range u.creds {
+		c := &u.creds[i]
+		id, err := base64.RawURLEncoding.DecodeString(c.CredentialID)
+		if err != nil {
+			continue
+		}
+		aaguid, _ := hex.DecodeString(c.AAGUID) //nolint:errcheck // 비어 있으면 nil
+		var transports []protocol.AuthenticatorTransport
+		for _, t := range strings.Split(c.Transports, ",") {
+			if t != "" {
+				transports = append(transports, protocol.AuthenticatorTransport(t))
+			}
+		}
+		out = append(out, webauthn.Credential{
+			ID:              id,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Transport:       transports,
+			Flags:           webauthn.CredentialFlags{BackupEligible: c.BackupEligible, BackupState: c.BackupState},
+			Authenticator:   webauthn.Authenticator{AAGUID: aaguid, SignCount: c.SignCount},
+		})
+	}
+	return out
+}
+
+// WebAuthnService registers passkeys and verifies passkey assertions.
+//
+// 챌린지(SessionData)는 Redis 에 한 번만 꺼낼 수 있게 두고, Redis 가 없으면 프로세스 메모리에 둔다
+// (단일 인스턴스·테스트 전용 — 여러 파드에서는 begin/finish 가 다른 파드로 가면 실패한다).
+type WebAuthnService struct {
+	wa       *webauthn.WebAuthn
+	repo     v2repo.WebAuthnRepository
+	userRepo v2repo.UserRepository
+	mfa      *MFAService
+	redis    *redis.Client
+
+	mu      sync.Mutex
+	pending map[string]pendingCeremony
+}
+
+type pendingCeremony struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewWebAuthnService creates a new WebAuthnService for the relying party rpID
+// (쿠키 도메인과 같은 등록 가능 도메인, 예: "damoang.net"). origins 는 허용할 웹 출처 목록이다.
+func NewWebAuthnService(repo v2repo.WebAuthnRepository, userRepo v2repo.UserRepository, rpID, rpDisplayName string, origins []string) (*WebAuthnService, error) {
+	if rpDisplayName == "" {
+		rpDisplayName = "Angple"
+	}
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: rpDisplayName,
+		RPOrigins:     origins,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("webauthn config: %w", err)
+	}
+	return &WebAuthnService{wa: wa, repo: repo, userRepo: userRepo, pending: map[string]pendingCeremony{}}, nil
+}
+
+// SetRedis stores ceremony challenges in Redis (여러 파드 배포에서는 필수)
+func (s *WebAuthnService) SetRedis(rc *redis.Client) {
+	s.redis = rc
+}
+
+// SetMFAService makes adding a passkey require a step-up token for members with TOTP on
+func (s *WebAuthnService) SetMFAService(mfa *MFAService) {
+	s.mfa = mfa
+}
+
+// putCeremony stores session data under key until webAuthnCeremonyTTL
+func (s *WebAuthnService) putCeremony(key string, session *webauthn.SessionData) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	if s.redis != nil {
+		return s.redis.Set(context.Background(), webAuthnRedisPrefix+key, data, webAuthnCeremonyTTL).Err()
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, p := range s.pending {
+		if now.After(p.expiresAt) {
+			delete(s.pending, k)
+		}
+	}
+	s.pending[key] = pendingCeremony{data: data, expiresAt: now.Add(webAuthnCeremonyTTL)}
+	return nil
+}
+
+// takeCeremony returns and removes the session data under key — 같은 챌린지로 두 번 끝낼 수 없다
+func (s *WebAuthnService) takeCeremony(key string) (*webauthn.SessionData, error) {
+	var data []byte
+	if s.redis != nil {
+		raw, err := s.redis.GetDel(context.Background(), webAuthnRedisPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrPasskeyInvalid
+		}
+		if err != nil {
+			return nil, err
+		}
+		data = raw
+	} else {
+		s.mu.Lock()
+		p, ok := s.pending[key]
+		delete(s.pending, key)
+		s.mu.Unlock()
+		if !ok || time.Now().After(p.expiresAt) {
+			return nil, ErrPasskeyInvalid
+		}
+		data = p.data
+	}
+	var session webauthn.SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *WebAuthnService) loadUser(user *v2domain.V2User) (*webAuthnUser, error) {
+	creds, err := s.repo.ListByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &webAuthnUser{user: user, creds: creds}, nil
+}
+
+// BeginRegistration returns creation options for navigator.credentials.create().
+// mfaVerified 는 요청 토큰에 mfa 가 실렸는지다(middleware.HasMFA).
+func (s *WebAuthnService) BeginRegistration(user *v2domain.V2User, mfaVerified bool) (*protocol.CredentialCreation, error) {
+	if s.mfa != nil && !mfaVerified && s.mfa.IsEnabled(user.ID) {
+		return nil, ErrPasskeyStepUpRequired
+	}
+	wu, err := s.loadUser(user)
+	if err != nil {
+		return nil, err
+	}
+	if len(wu.creds) >= webAuthnMaxPerUser {
+		return nil, fmt.Errorf("%w: at most %d", ErrPasskeyLimit, webAuthnMaxPerUser)
+	}
+	// 아이디 없이 로그인(discoverable)하려면 인증기에 계정이 남아야 한다 — resident key 필수
+	creation, session, err := s.wa.BeginRegistration(wu,
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithExclusions(webauthn.Credentials(wu.WebAuthnCredentials()).CredentialDescriptors()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.putCeremony(fmt.Sprintf("reg:%d", user.ID), session); err != nil {
+		return nil, err
+	}
+	return creation, nil
+}
+
+// FinishRegistration verifies the attestation in body (navigator.credentials.create() 결과 JSON) and stores the passkey
+func (s *WebAuthnService) FinishRegistration(user *v2domain.V2User, body []byte, name string) (*v2domain.V2WebAuthnCredential, error) {
+	name, err := passkeyName(name)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.takeCeremony(fmt.Sprintf("reg:%d", user.ID))
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(body)
+	if err != nil {
+		log.Printf("[v2-webauthn] parse attestation for user %d: %v", user.ID, err)
+		return nil, ErrPasskeyInvalid
+	}
+	wu, err := s.loadUser(user)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := s.wa.CreateCredential(wu, *session, parsed)
+	if err != nil {
+		log.Printf("[v2-webauthn] registration for user %d rejected: %v", user.ID, err)
+		return nil, ErrPasskeyInvalid
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+	if existing, err := s.repo.FindByCredentialID(credentialID); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, ErrPasskeyExists
+	}
+	transports := make([]string, 0, len(credential.Transport))
+	for _, t := range credential.Transport {
+		transports = append(transports, string(t))
+	}
+	cred := &v2domain.V2WebAuthnCredential{
+		UserID:          user.ID,
+		Username:        user.Username,
+		CredentialID:    credentialID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          hex.EncodeToString(credential.Authenticator.AAGUID),
+		SignCount:       credential.Authenticator.SignCount,
+		Transports:      truncateRunes(strings.Join(transports, ","), 100),
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		Name:            name,
+	}
+	if err := s.repo.Create(cred); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// BeginLogin returns request options for a username-less passkey login and the id that finishes it
+func (s *WebAuthnService) BeginLogin() (*protocol.CredentialAssertion, string, error) {
+	assertion, session, err := s.wa.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationPreferred),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	sessionID, err := newSessionToken()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.putCeremony("login:"+sessionID, session); err != nil {
+		return nil, "", err
+	}
+	return assertion, sessionID, nil
+}
+
+// FinishLogin verifies an assertion (navigator.credentials.get() 결과 JSON) and returns its member.
+// userVerified 는 인증기가 생체·PIN 으로 사용자를 확인했는지다 — 그렇다면 2단계 인증을 거친 것으로 본다.
+func (s *WebAuthnService) FinishLogin(sessionID string, body []byte) (user *v2domain.V2User, userVerified bool, err error) {
+	if sessionID == "" {
+		return nil, false, ErrPasskeyInvalid
+	}
+	session, err := s.takeCeremony("login:" + sessionID)
+	if err != nil {
+		return nil, false, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(body)
+	if err != nil {
+		log.Printf("[v2-webauthn] parse assertion: %v", err)
+		return nil, false, ErrPasskeyInvalid
+	}
+
+	var stored *v2domain.V2WebAuthnCredential
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		if len(userHandle) != webAuthnUserHandleSz {
+			return nil, errors.New("unknown user handle")
+		}
+		found, err := s.repo.FindByCredentialID(base64.RawURLEncoding.EncodeToString(rawID))
+		if err != nil {
+			return nil, err
+		}
+		if found == nil || found.UserID != binary.BigEndian.Uint64(userHandle) {
+			return nil, errors.New("unknown credential")
+		}
+		stored = found
+		owner, err := s.userRepo.FindByID(found.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return s.loadUser(owner)
+	}
+	wu, credential, err := s.wa.ValidatePasskeyLogin(handler, *session, parsed)
+	if err != nil {
+		log.Printf("[v2-webauthn] assertion rejected: %v", err)
+		return nil, false, ErrPasskeyInvalid
+	}
+	adapted, ok := wu.(*webAuthnUser)
+	if !ok || stored == nil {
+		return nil, false, ErrPasskeyInvalid
+	}
+	owner := adapted.user
+	if credential.Authenticator.CloneWarning {
+		// 카운터가 뒤로 갔다 — 같은 키가 두 곳에 있다는 신호라 로그인시키지 않는다
+		log.Printf("[v2-webauthn] sign counter regressed for credential %d (user %d): stored %d, got %d",
+			stored.ID, owner.ID, stored.SignCount, parsed.Response.AuthenticatorData.Counter)
+		return nil, false, ErrPasskeyInvalid
+	}
+	ok, err = s.repo.UpdateUse(stored.ID, stored.SignCount, credential.Authenticator.SignCount, credential.Flags.BackupState, time.Now())
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		return nil, false, ErrPasskeyInvalid // 같은 카운터로 동시에 들어온 다른 로그인이 먼저 끝났다
+	}
+	return owner, credential.Flags.UserVerified, nil
+}
+
+// List returns the member's passkeys
+func (s *WebAuthnService) List(userID uint64) ([]v2domain.V2WebAuthnCredential, error) {
+	return s.repo.ListByUser(userID)
+}
+
+// Rename changes the label of the member's passkey and returns the stored label
+func (s *WebAuthnService) Rename(userID, id uint64, name string) (string, error) {
+	name, err := passkeyName(name)
+	if err != nil {
+		return "", err
+	}
+	ok, err := s.repo.Rename(userID, id, name)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", ErrPasskeyNotFound
+	}
+	return name, nil
+}
+
+// Delete removes the member's passkey (인증기에 남은 키는 더 이상 로그인되지 않는다)
+func (s *WebAuthnService) Delete(userID, id uint64) error {
+	ok, err := s.repo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPasskeyNotFound
+	}
+	return nil
+}
+
+func passkeyName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return webAuthnDefaultName, nil
+	}
+	if len([]rune(name)) > webAuthnMaxName {
+		return "", fmt.Errorf("%w: at most %d characters", ErrPasskeyName, webAuthnMaxName)
+	}
+	return name, nil
+}
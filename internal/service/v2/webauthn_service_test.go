@@ -0,0 +1,275 @@
+package v2
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/jwt"
+)
+
+const testRPOrigin = "https://damoang.test"
+
+// softAuthenticator is a minimal ES256 "none"-attestation authenticator for tests
+type softAuthenticator struct {
+	t      *testing.T
+	key    *ecdsa.PrivateKey
+	credID []byte
+	uv     bool
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	credID := make([]byte, 16)
+	_, _ = rand.Read(credID)
+	return &softAuthenticator{t: t, key: key, credID: credID, uv: true}
+}
+
+// cborHead encodes a CBOR major type and argument
+func cborHead(major byte, n int) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n < 256:
+		return []byte{major<<5 | 24, byte(n)}
+	default:
+		return []byte{major<<5 | 25, byte(n >> 8), byte(n)}
+	}
+}
+
+func cborBytes(b []byte) []byte { return append(cborHead(2, len(b)), b...) }
+func cborText(s string) []byte  { return append(cborHead(3, len(s)), s...) }
+
+// cborInt encodes small signed integers (COSE 키 라벨·값)
+func cborInt(n int) []byte {
+	if n < 0 {
+		return cborHead(1, -1-n)
+	}
+	return cborHead(0, n)
+}
+
+func (a *softAuthenticator) coseKey() []byte {
+	x := a.key.X.FillBytes(make([]byte, 32))
+	y := a.key.Y.FillBytes(make([]byte, 32))
+	out := cborHead(5, 5)
+	out = append(out, cborInt(1)...)
+	out = append(out, cborInt(2)...) // kty: EC2
+	out = append(out, cborInt(3)...)
+	out = append(out, cborInt(-7)...) // alg: ES256
+	out = append(out, cborInt(-1)...)
+	out = append(out, cborInt(1)...) // crv: P-256
+	out = append(out, cborInt(-2)...)
+	out = append(out, cborBytes(x)...)
+	out = append(out, cborInt(-3)...)
+	out = append(out, cborBytes(y)...)
+	return out
+}
+
+func (a *softAuthenticator) authData(rpID string, counter uint32, attested bool) []byte {
+	rpHash := sha256.Sum256([]byte(rpID))
+	flags := byte(0x01) // UP
+	if a.uv {
+		flags |= 0x04
+	}
+	if attested {
+		flags |= 0x40
+	}
+	out := append([]byte{}, rpHash[:]...)
+	out = append(out, flags)
+	out = binary.BigEndian.AppendUint32(out, counter)
+	if attested {
+		out = append(out, make([]byte, 16)...) // AAGUID
+		out = binary.BigEndian.AppendUint16(out, uint16(len(a.credID)))
+		out = append(out, a.credID...)
+		out = append(out, a.coseKey()...)
+	}
+	return out
+}
+
+func clientDataJSON(typ string, challenge []byte) []byte {
+	b, _ := json.Marshal(map[string]interface{}{
+		"type":        typ,
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":      testRPOrigin,
+		"crossOrigin": false,
+	})
+	return b
+}
+
+func (a *softAuthenticator) create(rpID string, challenge []byte) []byte {
+	att := cborHead(5, 3)
+	att = append(att, cborText("fmt")...)
+	att = append(att, cborText("none")...)
+	att = append(att, cborText("attStmt")...)
+	att = append(att, cborHead(5, 0)...)
+	att = append(att, cborText("authData")...)
+	att = append(att, cborBytes(a.authData(rpID, 0, true))...)
+
+	enc := base64.RawURLEncoding.EncodeToString
+	body, _ := json.Marshal(map[string]interface{}{
+		"id":    enc(a.credID),
+		"rawId": enc(a.credID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    enc(clientDataJSON("webauthn.create", challenge)),
+			"attestationObject": enc(att),
+			"transports":        []string{"internal", "hybrid"},
+		},
+	})
+	return body
+}
+
+func (a *softAuthenticator) get(rpID string, challenge []byte, counter uint32, userHandle []byte) []byte {
+	authData := a.authData(rpID, counter, false)
+	cdj := clientDataJSON("webauthn.get", challenge)
+	cdHash := sha256.Sum256(cdj)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), cdHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		a.t.Fatalf("sign: %v", err)
+	}
+	enc := base64.RawURLEncoding.EncodeToString
+	body, _ := json.Marshal(map[string]interface{}{
+		"id":    enc(a.credID),
+		"rawId": enc(a.credID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    enc(cdj),
+			"authenticatorData": enc(authData),
+			"signature":         enc(sig),
+			"userHandle":        enc(userHandle),
+		},
+	})
+	return body
+}
+
+func newWebAuthnTestService(t *testing.T) (*V2AuthService, *WebAuthnService, *jwt.Manager) {
+	t.Helper()
+	auth, _, jwtManager, db := newSessionTestService(t)
+	repo := v2repo.NewWebAuthnRepository(db)
+	if err := repo.AutoMigrate(); err != nil {
+		t.Fatalf("migrate webauthn: %v", err)
+	}
+	wa, err := NewWebAuthnService(repo, v2repo.NewUserRepository(db), "damoang.test", "Damoang", []string{testRPOrigin})
+	if err != nil {
+		t.Fatalf("NewWebAuthnService: %v", err)
+	}
+	return auth, wa, jwtManager
+}
+
+func registerPasskey(t *testing.T, wa *WebAuthnService, authr *softAuthenticator, user *v2domain.V2User, name string) *v2domain.V2WebAuthnCredential {
+	t.Helper()
+	creation, err := wa.BeginRegistration(user, false)
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+	if creation.Response.AuthenticatorSelection.ResidentKey != "required" {
+		t.Fatalf("passkeys must be discoverable, got %q", creation.Response.AuthenticatorSelection.ResidentKey)
+	}
+	cred, err := wa.FinishRegistration(user, authr.create("damoang.test", creation.Response.Challenge), name)
+	if err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	return cred
+}
+
+func TestWebAuthnRegisterAndLogin(t *testing.T) {
+	auth, wa, jwtManager := newWebAuthnTestService(t)
+	user := &v2domain.V2User{ID: 1, Username: "zoe", Nickname: "조"}
+	authr := newSoftAuthenticator(t)
+
+	cred := registerPasskey(t, wa, authr, user, "")
+	if cred.Name != webAuthnDefaultName || cred.Transports != "internal,hybrid" || cred.UserID != 1 {
+		t.Fatalf("unexpected credential %+v", cred)
+	}
+	// 같은 챌린지로 두 번 끝낼 수 없다
+	if _, err := wa.FinishRegistration(user, authr.create("damoang.test", []byte("x")), ""); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("finish without a pending ceremony should fail, got %v", err)
+	}
+
+	login := func(counter uint32) (*V2LoginResponse, error) {
+		assertion, sessionID, err := wa.BeginLogin()
+		if err != nil {
+			t.Fatalf("BeginLogin: %v", err)
+		}
+		if len(assertion.Response.AllowedCredentials) != 0 {
+			t.Fatal("discoverable login should not list credentials")
+		}
+		user, uv, err := wa.FinishLogin(sessionID, authr.get("damoang.test", assertion.Response.Challenge, counter, webAuthnUserHandle(1)))
+		if err != nil {
+			return nil, err
+		}
+		return auth.PasskeyLogin(user, uv, SessionMeta{Platform: "web"})
+	}
+
+	resp, err := login(5)
+	if err != nil {
+		t.Fatalf("passkey login: %v", err)
+	}
+	if resp.AccessToken == "" || resp.RefreshToken == "" || resp.User.Username != "zoe" {
+		t.Fatalf("expected a full login response, got %+v", resp)
+	}
+	claims, err := jwtManager.VerifyToken(resp.AccessToken)
+	if err != nil || !claims.MFA || claims.SessionID == "" {
+		t.Fatalf("user-verified passkey login should be an mfa session: %+v %v", claims, err)
+	}
+
+	// 카운터가 늘지 않으면 복제된 키로 본다
+	if _, err := login(5); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("replayed counter should be rejected, got %v", err)
+	}
+	if _, err := login(6); err != nil {
+		t.Fatalf("next counter should log in: %v", err)
+	}
+
+	// 다른 회원이 등록한 user handle 로는 로그인할 수 없다
+	assertion, sessionID, _ := wa.BeginLogin()
+	if _, _, err := wa.FinishLogin(sessionID, authr.get("damoang.test", assertion.Response.Challenge, 7, webAuthnUserHandle(2))); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("mismatched user handle should fail, got %v", err)
+	}
+}
+
+func TestWebAuthnManageCredentials(t *testing.T) {
+	_, wa, _ := newWebAuthnTestService(t)
+	user := &v2domain.V2User{ID: 1, Username: "zoe", Nickname: "조"}
+	first := registerPasskey(t, wa, newSoftAuthenticator(t), user, "휴대폰")
+	registerPasskey(t, wa, newSoftAuthenticator(t), user, "보안 키")
+
+	creds, err := wa.List(1)
+	if err != nil || len(creds) != 2 {
+		t.Fatalf("List: %d %v", len(creds), err)
+	}
+	// 기존 자격증명은 다시 만들지 않도록 제외 목록에 들어간다
+	creation, err := wa.BeginRegistration(user, false)
+	if err != nil || len(creation.Response.CredentialExcludeList) != 2 {
+		t.Fatalf("exclude list: %+v %v", creation, err)
+	}
+
+	if name, err := wa.Rename(1, first.ID, "  새 휴대폰 "); err != nil || name != "새 휴대폰" {
+		t.Fatalf("Rename: %q %v", name, err)
+	}
+	if _, err := wa.Rename(2, first.ID, "남의 키"); !errors.Is(err, ErrPasskeyNotFound) {
+		t.Fatalf("renaming another member's passkey should fail, got %v", err)
+	}
+	if err := wa.Delete(2, first.ID); !errors.Is(err, ErrPasskeyNotFound) {
+		t.Fatalf("deleting another member's passkey should fail, got %v", err)
+	}
+	if err := wa.Delete(1, first.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if creds, _ := wa.List(1); len(creds) != 1 {
+		t.Fatalf("expected one passkey left, got %d", len(creds))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	gnudomain "github.com/damoang/angple-backend/internal/domain/gnuboard"
+	"github.com/damoang/angple-backend/internal/plugin"
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	"gorm.io/gorm"
 )
@@ -15,6 +16,7 @@ type DeleteWorker struct {
 	writeRepo gnurepo.WriteRepository
 	sdRepo    gnurepo.ScheduledDeleteRepository
 	eventRepo gnurepo.WriteAfterEventRepository
+	hooks     *plugin.HookManager
 	db        *gorm.DB
 	stop      chan struct{}
 	wg        sync.WaitGroup
@@ -31,6 +33,12 @@ func NewDeleteWorker(db *gorm.DB, writeRepo gnurepo.WriteRepository, sdRepo gnur
 	}
 }
 
+// SetHooks wires plugin after_delete hooks for deletes executed by the worker.
+// Before hooks already ran when the delete was scheduled, so only after hooks fire here.
+func (w *DeleteWorker) SetHooks(hm *plugin.HookManager) {
+	w.hooks = hm
+}
+
 // Start begins the background worker with a 30-second tick interval
 func (w *DeleteWorker) Start() {
 	w.wg.Add(1)
@@ -125,6 +133,14 @@ func (w *DeleteWorker) processPending() {
 				log.Printf("[DeleteWorker] Error soft deleting comment %s/%d: %v", sd.BoTable, sd.WrID, err)
 				continue
 			}
+			w.doHook(plugin.HookCommentAfterDelete, map[string]interface{}{
+				"board_id":   sd.BoTable,
+				"post_id":    comment.WrParent,
+				"comment_id": sd.WrID,
+				"member_id":  sd.RequestedBy,
+				"author_id":  comment.MbID,
+				"scheduled":  true,
+			})
 		} else {
 			post, findErr := w.writeRepo.FindPostByIDIncludeDeleted(sd.BoTable, sd.WrID)
 			if findErr != nil {
@@ -162,6 +178,14 @@ func (w *DeleteWorker) processPending() {
 				log.Printf("[DeleteWorker] Error soft deleting post %s/%d: %v", sd.BoTable, sd.WrID, err)
 				continue
 			}
+			w.doHook(plugin.HookPostAfterDelete, map[string]interface{}{
+				"board_id":  sd.BoTable,
+				"post_id":   sd.WrID,
+				"member_id": sd.RequestedBy,
+				"author_id": post.MbID,
+				"title":     post.WrSubject,
+				"scheduled": true,
+			})
 		}
 
 		// Mark as executed
@@ -173,3 +197,11 @@ func (w *DeleteWorker) processPending() {
 			sd.BoTable, sd.WrID, sd.WrIsComment, sd.DelayMinutes)
 	}
 }
+
+// doHook dispatches a plugin action hook when hooks are wired
+func (w *DeleteWorker) doHook(event string, payload map[string]interface{}) {
+	if w.hooks == nil {
+		return
+	}
+	w.hooks.Do(event, payload)
+}
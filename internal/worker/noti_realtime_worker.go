@@ -0,0 +1,343 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	"github.com/damoang/angple-backend/internal/handler"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"github.com/damoang/angple-backend/internal/ws"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// NotiRealtimeWorker pushes newly inserted notifications (g5_na_noti) to the
+// recipient's WebSocket connections as "notification" + "unread_count" events.
+//
+// 흐름: Notification.AfterCreate 훅 → Enqueue(ph_id) → 잠깐 모아 커밋된 행을 다시 읽는다
+// (롤백된 dry-run 알림은 끝내 안 보이므로 나가지 않는다) → Redis 로 모든 인스턴스에 행을 뿌린다 →
+// 각 인스턴스는 "자기에게 붙은" 수신자만 설정·차단을 확인해 로컬 연결로 보낸다.
+//
+// 수신자 연결이 있는 인스턴스만 DB 를 더 만지므로(설정·차단·미읽음) 다이제스트처럼 수천 건이
+// 한 번에 생겨도 비용은 접속 중인 수신자 수에 비례한다. 한 연결은 한 인스턴스에만 있으니
+// 이벤트가 두 번 가지 않는다.
+type NotiRealtimeWorker struct {
+	db       *gorm.DB
+	hub      *ws.Hub
+	notiRepo gnurepo.NotiRepository
+	prefRepo gnurepo.NotiPreferenceRepository
+	rules    gnurepo.NotiRuleRepository
+	redis    *redis.Client
+
+	queue chan int
+	// pending 은 아직 커밋이 보이지 않은 ph_id 와 처음 받은 시각이다 (run 고루틴 전용)
+	pending map[int]time.Time
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+const (
+	notiRealtimeChannel = "notifications:created"
+	notiRealtimeQueue   = 4096
+	notiRealtimeFlush   = 300 * time.Millisecond
+	// notiRealtimeCommitWait 는 삽입 트랜잭션의 커밋을 기다리는 한도다 — 넘으면 롤백된 것으로 보고 버린다
+	notiRealtimeCommitWait = 10 * time.Second
+	notiRealtimeChunk      = 200
+	// notiResumeLimit 을 넘게 놓쳤으면 다시 보내지 않고 resync 로 목록을 새로 받게 한다
+	notiResumeLimit = 50
+)
+
+// NewNotiRealtimeWorker creates the worker. redisClient 가 nil 이면 단일 인스턴스로 보고 로컬에만 보낸다.
+func NewNotiRealtimeWorker(db *gorm.DB, hub *ws.Hub, notiRepo gnurepo.NotiRepository, prefRepo gnurepo.NotiPreferenceRepository, redisClient *redis.Client) *NotiRealtimeWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &NotiRealtimeWorker{
+		db:       db,
+		hub:      hub,
+		notiRepo: notiRepo,
+		prefRepo: prefRepo,
+		redis:    redisClient,
+		queue:    make(chan int, notiRealtimeQueue),
+		pending:  make(map[int]time.Time),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// SetNotiRules applies thread·board rules and the mention filter (nil 이면 종류별 스위치만 본다)
+func (w *NotiRealtimeWorker) SetNotiRules(rules gnurepo.NotiRuleRepository) {
+	w.rules = rules
+}
+
+// Enqueue records a new ph_id (gnurepo.SetNotificationCreatedHook). 요청 경로에서 불리므로 막히지 않는다 —
+// 큐가 차면 버린다(알림함·미읽음 폴링이 원본이다).
+func (w *NotiRealtimeWorker) Enqueue(phID int) {
+	select {
+	case w.queue <- phID:
+	default:
+	}
+}
+
+// Start launches the batching loop and, with Redis, the cross-instance subscriber.
+func (w *NotiRealtimeWorker) Start() {
+	w.wg.Add(1)
+	go w.run()
+	if w.redis != nil {
+		w.wg.Add(1)
+		go w.subscribe()
+	}
+	log.Printf("[NotiRealtimeWorker] Started (redis=%t)", w.redis != nil)
+}
+
+// Stop stops both goroutines. 큐에 남은 알림은 버린다 — 재접속한 클라이언트가 resume 으로 받는다.
+func (w *NotiRealtimeWorker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+	log.Printf("[NotiRealtimeWorker] Stopped")
+}
+
+func (w *NotiRealtimeWorker) run() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(notiRealtimeFlush)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case phID := <-w.queue:
+			if _, ok := w.pending[phID]; !ok {
+				w.pending[phID] = time.Now()
+			}
+		case <-ticker.C:
+			w.flush(time.Now())
+		}
+	}
+}
+
+// flush dispatches the pending notifications that are committed by now.
+func (w *NotiRealtimeWorker) flush(now time.Time) {
+	rows := w.committed(now)
+	for start := 0; start < len(rows); start += notiRealtimeChunk {
+		w.dispatch(rows[start:min(start+notiRealtimeChunk, len(rows))])
+	}
+}
+
+// committed loads the visible rows among pending ids and forgets them, dropping ids that never
+// showed up within notiRealtimeCommitWait (롤백).
+func (w *NotiRealtimeWorker) committed(now time.Time) []gnurepo.Notification {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	ids := make([]int, 0, len(w.pending))
+	for id := range w.pending {
+		ids = append(ids, id)
+	}
+	var rows []gnurepo.Notification
+	for start := 0; start < len(ids); start += notiRealtimeChunk {
+		end := min(start+notiRealtimeChunk, len(ids))
+		var chunk []gnurepo.Notification
+		if err := w.db.Where("ph_id IN ?", ids[start:end]).Order("ph_id ASC").Find(&chunk).Error; err != nil {
+			log.Printf("[NotiRealtimeWorker] load notifications failed: %v", err)
+			return nil // 다음 틱에 다시
+		}
+		rows = append(rows, chunk...)
+	}
+	for i := range rows {
+		delete(w.pending, rows[i].PhID)
+	}
+	for id, since := range w.pending {
+		if now.Sub(since) > notiRealtimeCommitWait {
+			delete(w.pending, id)
+		}
+	}
+	return rows
+}
+
+// dispatch hands rows to every instance (자기 자신은 구독으로 받는다). 발행이 안 되면 로컬에만 전달한다.
+func (w *NotiRealtimeWorker) dispatch(rows []gnurepo.Notification) {
+	if w.redis != nil {
+		if data, err := json.Marshal(rows); err == nil {
+			if w.redis.Publish(w.ctx, notiRealtimeChannel, data).Err() == nil {
+				return
+			}
+		}
+	}
+	w.deliverLocal(rows)
+}
+
+func (w *NotiRealtimeWorker) subscribe() {
+	defer w.wg.Done()
+	pubsub := w.redis.Subscribe(w.ctx, notiRealtimeChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var rows []gnurepo.Notification
+			if err := json.Unmarshal([]byte(msg.Payload), &rows); err == nil {
+				w.deliverLocal(rows)
+			}
+		}
+	}
+}
+
+// deliverLocal sends rows to recipients connected to this instance.
+// 걸러진 알림도 미읽음 뱃지에는 잡히므로 unread_count 는 수신자마다 항상 보낸다.
+func (w *NotiRealtimeWorker) deliverLocal(rows []gnurepo.Notification) {
+	byMember := make(map[string][]gnurepo.Notification)
+	var order []string
+	for i := range rows {
+		mbID := rows[i].MbID
+		if mbID == "" || !w.hub.Online(mbID) {
+			continue
+		}
+		if _, ok := byMember[mbID]; !ok {
+			order = append(order, mbID)
+		}
+		byMember[mbID] = append(byMember[mbID], rows[i])
+	}
+	for _, mbID := range order {
+		events := w.filter(mbID, byMember[mbID])
+		events = append(events, w.UnreadCount(mbID))
+		w.hub.SendLocal(mbID, events...)
+	}
+}
+
+// filter turns one member's rows into notification events, dropping muted and blocked ones.
+func (w *NotiRealtimeWorker) filter(mbID string, rows []gnurepo.Notification) []*ws.Event {
+	pref, err := w.prefRepo.Get(mbID)
+	if err != nil {
+		log.Printf("[NotiRealtimeWorker] preference load failed for %s: %v", mbID, err)
+		return nil
+	}
+	blocked := w.blockedSenders(mbID, rows)
+
+	events := make([]*ws.Event, 0, len(rows))
+	for i := range rows {
+		n := &rows[i]
+		// reaction 은 알림 목록·뱃지에서 상시 제외되는 종류다 (NotiRepository.CountUnread).
+		// 읽음으로 만들어진 행은 조용히 기록된 것(글·게시판 뮤트)이거나 이미 확인한 것이다.
+		if n.PhFromCase == "reaction" || n.PhReaded == "Y" || w.muted(pref, n) {
+			continue
+		}
+		if blockedBy(blocked[n.RelMbID], n.PhFromCase == "memo") {
+			continue
+		}
+		events = append(events, &ws.Event{
+			ID:      strconv.Itoa(n.PhID),
+			Type:    "notification",
+			Payload: handler.NotificationPayload(*n),
+		})
+	}
+	return events
+}
+
+// muted reports whether the recipient's switches or rules keep n off the live stream
+func (w *NotiRealtimeWorker) muted(pref *gnurepo.NotiPreference, n *gnurepo.Notification) bool {
+	if w.rules == nil {
+		return !pref.Allows(n)
+	}
+	decision, err := w.rules.Decide(pref, n)
+	if err != nil {
+		log.Printf("[NotiRealtimeWorker] rule load failed for %s: %v", pref.MbID, err)
+		return !pref.Allows(n)
+	}
+	return decision != gnurepo.NotiDeliver
+}
+
+// blockedBy reports whether one of the recipient's block scopes on the sender covers this notification.
+// ⛔ 'message'(쪽지 한정 차단)는 쪽지만, 'content' 는 글·댓글 알림만 거른다 (domain.MemberBlock).
+func blockedBy(scopes []string, memo bool) bool {
+	for _, scope := range scopes {
+		switch scope {
+		case domain.BlockScopeMessage:
+			if memo {
+				return true
+			}
+		case domain.BlockScopeContent:
+			if !memo {
+				return true
+			}
+		default: // all, 구버전 빈 값
+			return true
+		}
+	}
+	return false
+}
+
+// blockedSenders returns sender → block scopes for the senders the recipient blocked
+func (w *NotiRealtimeWorker) blockedSenders(mbID string, rows []gnurepo.Notification) map[string][]string {
+	senders := make([]string, 0, len(rows))
+	for i := range rows {
+		if rows[i].RelMbID != "" {
+			senders = append(senders, rows[i].RelMbID)
+		}
+	}
+	blocked := make(map[string][]string)
+	if len(senders) == 0 {
+		return blocked
+	}
+	var blocks []domain.MemberBlock
+	if err := w.db.Select("blocked_mb_id, block_scope").
+		Where("mb_id = ? AND blocked_mb_id IN ?", mbID, senders).
+		Find(&blocks).Error; err != nil {
+		log.Printf("[NotiRealtimeWorker] block load failed for %s: %v", mbID, err)
+	}
+	for _, b := range blocks {
+		blocked[b.BlockedMbID] = append(blocked[b.BlockedMbID], b.Scope)
+	}
+	return blocked
+}
+
+// UnreadCount builds the unread_count event — GET /api/v1/notifications/unread-count 와 같은 합계다.
+func (w *NotiRealtimeWorker) UnreadCount(mbID string) *ws.Event {
+	total, _ := w.notiRepo.CountUnread(mbID)
+	if bc, err := w.notiRepo.CountUnreadBroadcasts(mbID); err == nil {
+		total += bc
+	}
+	return &ws.Event{Type: "unread_count", Payload: map[string]int64{"total_unread": total}}
+}
+
+// Missed returns the notifications a reconnecting client has not seen (resume 토큰 이후).
+// notiResumeLimit 을 넘게 놓쳤으면 resync 이벤트 하나만 돌려준다 — 클라이언트는 목록을 다시 받는다.
+func (w *NotiRealtimeWorker) Missed(mbID string, afterPhID int) []*ws.Event {
+	if afterPhID <= 0 {
+		return nil
+	}
+	var rows []gnurepo.Notification
+	if err := w.db.Where("mb_id = ? AND ph_id > ?", mbID, afterPhID).
+		Order("ph_id ASC").Limit(notiResumeLimit + 1).Find(&rows).Error; err != nil {
+		log.Printf("[NotiRealtimeWorker] resume load failed for %s: %v", mbID, err)
+		return []*ws.Event{{Type: "resync"}}
+	}
+	if len(rows) > notiResumeLimit {
+		return []*ws.Event{{Type: "resync"}}
+	}
+	return w.filter(mbID, rows)
+}
+
+// Broadcast announces a new all-member broadcast (na_broadcast). 방송은 회원별 행이 없어
+// resume 토큰이 없다 — 놓친 클라이언트는 unread_count·목록으로 받는다.
+func (w *NotiRealtimeWorker) Broadcast(title, body, url string) {
+	w.hub.SendToAll(&ws.Event{
+		Type: "notification",
+		Payload: map[string]interface{}{
+			"type":      "broadcast",
+			"title":     title,
+			"content":   body,
+			"url":       url,
+			"broadcast": true,
+		},
+	})
+}
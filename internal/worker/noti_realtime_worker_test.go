@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/damoang/angple-backend/internal/domain"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"github.com/damoang/angple-backend/internal/ws"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newNotiRealtimeTestWorker(t *testing.T) (*NotiRealtimeWorker, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&gnurepo.Notification{}, &gnurepo.NotiPreference{}, &domain.MemberBlock{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	w := NewNotiRealtimeWorker(db, ws.NewHub(nil), gnurepo.NewNotiRepository(db), gnurepo.NewNotiPreferenceRepository(db), nil)
+	return w, db
+}
+
+func eventIDs(events []*ws.Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.Type+":"+e.ID)
+	}
+	return ids
+}
+
+func TestNotiRealtimeSkipsRolledBackInserts(t *testing.T) {
+	w, db := newNotiRealtimeTestWorker(t)
+	gnurepo.SetNotificationCreatedHook(w.Enqueue)
+	t.Cleanup(func() { gnurepo.SetNotificationCreatedHook(nil) })
+
+	if err := db.Create(&gnurepo.Notification{MbID: "zoe", PhFromCase: "memo", PhToCase: "memo"}).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// dry-run 잡처럼 트랜잭션 안에서 만들고 롤백한다
+	_ = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&gnurepo.Notification{MbID: "zoe", PhFromCase: "digest"}).Error; err != nil {
+			t.Fatalf("create in tx: %v", err)
+		}
+		return errors.New("dry-run")
+	})
+
+	now := time.Now()
+	for len(w.queue) > 0 {
+		w.pending[<-w.queue] = now
+	}
+	if len(w.pending) != 2 {
+		t.Fatalf("expected both inserts queued, got %v", w.pending)
+	}
+	rows := w.committed(now)
+	if len(rows) != 1 || rows[0].PhFromCase != "memo" {
+		t.Fatalf("only the committed row should be delivered, got %+v", rows)
+	}
+	if rows := w.committed(now.Add(notiRealtimeCommitWait + time.Second)); len(rows) != 0 || len(w.pending) != 0 {
+		t.Fatalf("rolled-back id must be dropped, got %+v pending=%v", rows, w.pending)
+	}
+}
+
+func TestNotiRealtimeHonorsPreferencesAndBlocks(t *testing.T) {
+	w, db := newNotiRealtimeTestWorker(t)
+	db.Create(&gnurepo.NotiPreference{MbID: "zoe", NotiComment: true, NotiReply: true, NotiMention: true, NotiFollow: true, NotiBoardSubscribe: true})
+	db.Model(&gnurepo.NotiPreference{}).Where("mb_id = ?", "zoe").Update("noti_like", false)
+	db.Create(&domain.MemberBlock{MbID: "zoe", BlockedMbID: "spam", Scope: domain.BlockScopeAll})
+	db.Create(&domain.MemberBlock{MbID: "zoe", BlockedMbID: "pen", Scope: domain.BlockScopeMessage})
+
+	rows := []gnurepo.Notification{
+		{PhID: 1, MbID: "zoe", RelMbID: "bob", PhFromCase: "good", PhToCase: "good"},         // 공감 알림 끔
+		{PhID: 2, MbID: "zoe", RelMbID: "spam", PhFromCase: "comment", PhToCase: "comment"},  // 전체 차단
+		{PhID: 3, MbID: "zoe", RelMbID: "pen", PhFromCase: "memo", PhToCase: "memo"},         // 쪽지 차단
+		{PhID: 4, MbID: "zoe", RelMbID: "pen", PhFromCase: "comment", PhToCase: "comment"},   // 쪽지 차단은 댓글과 무관
+		{PhID: 5, MbID: "zoe", RelMbID: "bob", PhFromCase: "reaction", PhToCase: "reaction"}, // 목록에서도 제외되는 종류
+		{PhID: 6, MbID: "zoe", RelMbID: "bob", PhFromCase: "memo", PhToCase: "memo"},
+	}
+	got := eventIDs(w.filter("zoe", rows))
+	if fmt.Sprint(got) != "[notification:4 notification:6]" {
+		t.Fatalf("unexpected events %v", got)
+	}
+}
+
+func TestNotiRealtimeMissed(t *testing.T) {
+	w, db := newNotiRealtimeTestWorker(t)
+	for i := 0; i < 3; i++ {
+		db.Create(&gnurepo.Notification{MbID: "zoe", RelMbID: "bob", PhFromCase: "memo"})
+	}
+	db.Create(&gnurepo.Notification{MbID: "kim", PhFromCase: "memo"})
+
+	if got := eventIDs(w.Missed("zoe", 1)); fmt.Sprint(got) != "[notification:2 notification:3]" {
+		t.Fatalf("unexpected missed events %v", got)
+	}
+	if got := w.Missed("zoe", 0); len(got) != 0 {
+		t.Fatalf("no resume token must not replay, got %v", eventIDs(got))
+	}
+
+	for i := 0; i < notiResumeLimit; i++ {
+		db.Create(&gnurepo.Notification{MbID: "zoe", PhFromCase: "memo"})
+	}
+	if got := eventIDs(w.Missed("zoe", 1)); fmt.Sprint(got) != "[resync:]" {
+		t.Fatalf("too many missed should resync, got %v", got)
+	}
+}
@@ -0,0 +1,194 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	"gorm.io/gorm"
+)
+
+const expoPushURL = "https://exp.host/--/api/v2/push/send"
+
+// expoProvider sends to app devices (v2_devices) through the Expo Push API
+type expoProvider struct {
+	db          *gorm.DB
+	httpClient  *http.Client
+	accessToken string
+}
+
+func newExpoProvider(db *gorm.DB, accessToken string) *expoProvider {
+	return &expoProvider{
+		db:          db,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		accessToken: accessToken,
+	}
+}
+
+func (p *expoProvider) name() string { return "expo" }
+
+type expoPushMessage struct {
+	To        string            `json:"to"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body,omitempty"`
+	Data      map[string]string `json:"data,omitempty"`
+	Sound     string            `json:"sound,omitempty"`
+	ChannelID string            `json:"channelId,omitempty"`
+	Priority  string            `json:"priority,omitempty"`
+}
+
+type expoPushTicket struct {
+	Status  string `json:"status"` // "ok" | "error"
+	Message string `json:"message,omitempty"`
+	Details struct {
+		Error string `json:"error,omitempty"`
+	} `json:"details,omitempty"`
+}
+
+// targets maps users to their Expo push tokens.
+func (p *expoProvider) targets(userIDs []uint64) (map[uint64][]pushTarget, error) {
+	out := make(map[uint64][]pushTarget)
+	if len(userIDs) == 0 {
+		return out, nil
+	}
+	var devices []v2domain.V2Device
+	if err := p.db.Where("user_id IN ?", userIDs).Find(&devices).Error; err != nil {
+		return out, err
+	}
+	for _, d := range devices {
+		// Only Expo tokens are routable via exp.host; skip anything else.
+		if !strings.HasPrefix(d.Token, "ExponentPushToken") && !strings.HasPrefix(d.Token, "ExpoPushToken") {
+			continue
+		}
+		out[d.UserID] = append(out[d.UserID], pushTarget{Address: d.Token})
+	}
+	return out, nil
+}
+
+func buildExpoMessage(m pushMessage) expoPushMessage {
+	return expoPushMessage{
+		To:        m.Target.Address,
+		Title:     m.Note.Title,
+		Body:      m.Note.Body,
+		Data:      map[string]string{"url": m.Note.AppURL, "id": strconv.Itoa(m.Note.PhID)},
+		Sound:     "default",
+		ChannelID: "default",
+		Priority:  "high",
+	}
+}
+
+// send pushes messages to Expo in chunks of 100 and cleans up dead tokens.
+// Ticket order matches message order within a chunk (Expo semantics), so the
+// dead token is read straight from msgs — no parallel slice to misalign.
+func (p *expoProvider) send(pending []pushMessage) pushResult {
+	const chunkSize = 100
+	msgs := make([]expoPushMessage, len(pending))
+	for i, m := range pending {
+		msgs[i] = buildExpoMessage(m)
+	}
+	var res pushResult
+	for start := 0; start < len(msgs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(msgs) {
+			end = len(msgs)
+		}
+		tickets, err := p.sendChunk(msgs[start:end])
+		if err != nil {
+			res.failed += end - start
+			log.Printf("[PushNotifyWorker] expo chunk send failed (%d msgs): %v", end-start, err)
+			continue
+		}
+		for i, t := range tickets {
+			if t.Status == "ok" {
+				res.sent++
+				continue
+			}
+			if t.Details.Error == "DeviceNotRegistered" && start+i < len(msgs) {
+				if p.deleteDeadToken(msgs[start+i].To) {
+					res.pruned++
+					continue
+				}
+			}
+			res.failed++
+		}
+	}
+	return res
+}
+
+func (p *expoProvider) sendChunk(msgs []expoPushMessage) ([]expoPushTicket, error) {
+	payload, err := json.Marshal(msgs)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		if attempt > 0 {
+			time.Sleep(2 * time.Second)
+		}
+		// context.Background() 는 http.NewRequest 의 내부 동작과 동일하다 —
+		// 취소 신호를 새로 도입하지 않으려 일부러 그대로 둔다. 요청 시한은
+		// 종전처럼 p.httpClient 의 Timeout 이 담당한다.
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, expoPushURL, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		if p.accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+p.accessToken)
+		}
+
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			// 전송 계층 오류는 재시도하지 않는다 — Expo 가 이미 배치를 수신했을 수
+			// 있어(응답 유실) 재전송하면 중복 푸시가 된다. 미발송(인앱엔 남음)을 택함.
+			return nil, err
+		}
+		body, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if readErr != nil {
+			return nil, readErr
+		}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("expo push http %d: %s", resp.StatusCode, truncateForLog(body))
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("expo push http %d: %s", resp.StatusCode, truncateForLog(body))
+		}
+		var parsed struct {
+			Data []expoPushTicket `json:"data"`
+		}
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			return nil, fmt.Errorf("expo push response parse: %w", err)
+		}
+		return parsed.Data, nil
+	}
+	return nil, lastErr
+}
+
+func (p *expoProvider) deleteDeadToken(token string) bool {
+	if err := p.db.Where("token = ?", token).Delete(&v2domain.V2Device{}).Error; err != nil {
+		log.Printf("[PushNotifyWorker] dead token delete failed: %v", err)
+		return false
+	}
+	log.Printf("[PushNotifyWorker] removed DeviceNotRegistered token")
+	return true
+}
+
+func truncateForLog(b []byte) string {
+	s := string(b)
+	if len(s) > 200 {
+		return s[:200]
+	}
+	return s
+}
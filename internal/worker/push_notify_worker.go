@@ -1,14 +1,9 @@
 package worker
 
 import (
-	"bytes"
-	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
 	"strings"
 	"sync"
@@ -17,15 +12,23 @@ import (
 	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
 	"github.com/damoang/angple-backend/internal/handler"
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/webpush"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"gorm.io/gorm"
 )
 
-// PushNotifyWorker turns in-app notifications (g5_na_noti) into device push
-// notifications via the Expo Push API. The notification table acts as an
-// outbox: this worker polls rows past a persisted cursor (v2_push_cursors),
-// resolves recipients' registered devices (v2_devices) and sends batched
-// pushes. It never touches the request path — failures are logged and the
-// in-app notification remains the source of truth.
+// PushNotifyWorker turns in-app notifications (g5_na_noti) into push
+// notifications. The notification table acts as an outbox: this worker polls
+// rows past a persisted cursor (v2_push_cursors), resolves recipients'
+// registrations on every provider and sends them. It never touches the
+// request path — failures are logged and the in-app notification remains the
+// source of truth.
+//
+// Providers (pushProvider):
+//   - expo: 앱 기기 토큰(v2_devices) → Expo Push API (push_expo.go)
+//   - webpush: 브라우저 구독(v2_web_push_subscriptions) → RFC 8030 Web Push (push_web.go, EnableWebPush)
 //
 // Enabled only when PUSH_ENABLED=true (or 1). Optional EXPO_ACCESS_TOKEN is
 // sent as a bearer token (Expo "enhanced push security").
@@ -33,16 +36,17 @@ type PushNotifyWorker struct {
 	db           *gorm.DB
 	pollInterval time.Duration
 	batchSize    int
-	// maxPerCycle caps how many push messages one poll cycle may send.
-	// Guards against cron-generated notification floods and Expo rate limits.
+	// maxPerCycle caps how many push messages one poll cycle may send (전 provider 합).
+	// Guards against cron-generated notification floods and provider rate limits.
 	maxPerCycle int
-	httpClient  *http.Client
-	accessToken string
+	providers   []pushProvider
 	stop        chan struct{}
 	wg          sync.WaitGroup
-}
 
-const expoPushURL = "https://exp.host/--/api/v2/push/send"
+	// prefRepo·rules 가 있으면 수신자의 종류별 스위치·글/게시판 규칙·방해 금지 시간을 지킨다 (SetNotiRules)
+	prefRepo gnurepo.NotiPreferenceRepository
+	rules    gnurepo.NotiRuleRepository
+}
 
 // pushFromCases is the MVP whitelist: person-to-person events only.
 // System/cron notifications (digest, point expiry, promote 등) are excluded
@@ -56,26 +60,91 @@ var pushFromCases = map[string]bool{
 	"memo":    true, // 쪽지
 }
 
+var (
+	pushMessagesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "push_messages_total",
+			Help: "Total number of push messages by provider and result (sent, failed, pruned)",
+		},
+		[]string{"provider", "result"},
+	)
+	pushSendDurationSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "push_send_duration_seconds",
+			Help:    "Time one provider takes to send a poll cycle's messages",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"provider"},
+	)
+)
+
+// pushProvider is one push delivery channel
+type pushProvider interface {
+	// name is the metrics·log label
+	name() string
+	// targets returns the registered addresses of the given v2 users
+	targets(userIDs []uint64) (map[uint64][]pushTarget, error)
+	// send delivers messages. 서비스가 "없는 주소"라고 답한 등록은 지운다(pruned).
+	send(msgs []pushMessage) pushResult
+}
+
+// pushTarget is one registration on a provider
+type pushTarget struct {
+	Address string // Expo 토큰 | Web Push 엔드포인트
+	// Web Push 구독 키 (expo 는 비어 있다)
+	P256dh string
+	Auth   string
+}
+
+// pushNote is a notification rendered for push — provider 와 무관한 내용
+type pushNote struct {
+	PhID  int
+	Title string
+	Body  string
+	// AppURL 은 앱 딥링크 경로(pushAppURL), WebURL 은 웹 주소(알림 목록의 url 과 같다)
+	AppURL string
+	WebURL string
+}
+
+type pushMessage struct {
+	Target pushTarget
+	Note   pushNote
+}
+
+type pushResult struct {
+	sent, failed, pruned int
+}
+
 func NewPushNotifyWorker(db *gorm.DB) *PushNotifyWorker {
 	return &PushNotifyWorker{
 		db:           db,
 		pollInterval: 3 * time.Second,
 		batchSize:    500,
 		maxPerCycle:  1000,
-		httpClient:   &http.Client{Timeout: 10 * time.Second},
-		accessToken:  os.Getenv("EXPO_ACCESS_TOKEN"),
+		providers:    []pushProvider{newExpoProvider(db, os.Getenv("EXPO_ACCESS_TOKEN"))},
 		stop:         make(chan struct{}),
 	}
 }
 
+// EnableWebPush adds the browser Web Push provider (VAPID 키가 설정된 경우에만 부른다)
+func (w *PushNotifyWorker) EnableWebPush(vapid *webpush.VAPID) {
+	w.providers = append(w.providers, newWebPushProvider(v2repo.NewWebPushRepository(w.db), vapid))
+}
+
+// SetNotiRules makes pushes follow the recipient's preferences, rules and quiet hours
+func (w *PushNotifyWorker) SetNotiRules(prefRepo gnurepo.NotiPreferenceRepository, rules gnurepo.NotiRuleRepository) {
+	w.prefRepo = prefRepo
+	w.rules = rules
+}
+
 // Start launches the single poller goroutine. A single worker is intentional:
 // the cursor is a global watermark and one poller avoids double-send races.
 func (w *PushNotifyWorker) Start() {
 	// 커서 테이블은 워커가 직접 보장한다 — AUTO_MIGRATE_ON_BOOT=false 인
 	// 환경(canary/prod)에서 전체 스키마 마이그레이션 없이도 동작하도록.
 	// AutoMigrate 는 멱등이고 이 한 테이블만 만진다.
-	if err := w.db.AutoMigrate(&v2domain.V2PushCursor{}); err != nil {
-		log.Printf("[PushNotifyWorker] cursor table migrate failed: %v", err)
+	if err := w.db.AutoMigrate(&v2domain.V2PushCursor{}, &v2domain.V2WebPushSubscription{}); err != nil {
+		log.Printf("[PushNotifyWorker] table migrate failed: %v", err)
 	}
 	w.wg.Add(1)
 	go func() {
@@ -91,7 +160,11 @@ func (w *PushNotifyWorker) Start() {
 			}
 		}
 	}()
-	log.Printf("[PushNotifyWorker] Started (interval=%s batch=%d cap=%d)", w.pollInterval, w.batchSize, w.maxPerCycle)
+	names := make([]string, 0, len(w.providers))
+	for _, p := range w.providers {
+		names = append(names, p.name())
+	}
+	log.Printf("[PushNotifyWorker] Started (interval=%s batch=%d cap=%d providers=%s)", w.pollInterval, w.batchSize, w.maxPerCycle, strings.Join(names, ","))
 }
 
 func (w *PushNotifyWorker) Stop() {
@@ -145,24 +218,6 @@ func (w *PushNotifyWorker) claimCursor(oldPhID, newPhID int) bool {
 	return true
 }
 
-type expoPushMessage struct {
-	To        string            `json:"to"`
-	Title     string            `json:"title"`
-	Body      string            `json:"body,omitempty"`
-	Data      map[string]string `json:"data,omitempty"`
-	Sound     string            `json:"sound,omitempty"`
-	ChannelID string            `json:"channelId,omitempty"`
-	Priority  string            `json:"priority,omitempty"`
-}
-
-type expoPushTicket struct {
-	Status  string `json:"status"` // "ok" | "error"
-	Message string `json:"message,omitempty"`
-	Details struct {
-		Error string `json:"error,omitempty"`
-	} `json:"details,omitempty"`
-}
-
 func (w *PushNotifyWorker) processBatch() {
 	cursor, ok := w.loadCursor()
 	if !ok {
@@ -189,32 +244,59 @@ func (w *PushNotifyWorker) processBatch() {
 		return
 	}
 
-	// Resolve recipient mb_id → v2 user id → device tokens (2 queries, no N+1).
+	// Resolve recipient mb_id → v2 user id → each provider's registrations
+	// (1 + provider 수 만큼의 쿼리, no N+1).
 	mbSet := make(map[string]struct{})
 	for _, n := range notis {
 		if pushFromCases[n.PhFromCase] && n.MbID != "" {
 			mbSet[n.MbID] = struct{}{}
 		}
 	}
-	tokensByMb := w.resolveDeviceTokens(mbSet)
+	userByMb := w.resolveUsers(mbSet)
+	userIDs := make([]uint64, 0, len(userByMb))
+	for _, id := range userByMb {
+		userIDs = append(userIDs, id)
+	}
+	targets := make([]map[uint64][]pushTarget, len(w.providers))
+	for i, p := range w.providers {
+		t, err := p.targets(userIDs)
+		if err != nil {
+			log.Printf("[PushNotifyWorker] %s target lookup failed: %v", p.name(), err)
+		}
+		targets[i] = t
+	}
 
 	// Build messages in ph_id order, respecting the per-cycle cap. If the cap
 	// would be crossed mid-notification, stop there and leave the rest for the
 	// next cycle (cursor only advances past fully processed rows).
-	var msgs []expoPushMessage
+	msgs := make([][]pushMessage, len(w.providers))
+	total := 0
 	lastProcessed := cursor
+	prefs := make(map[string]*gnurepo.NotiPreference)
+	now := time.Now()
 	for _, n := range notis {
-		var candidate []expoPushMessage
+		count := 0
+		userID, ok := userByMb[n.MbID]
 		// 화이트리스트 + 미읽음만 발송. 읽힌 행도 커서는 전진(스킵만).
-		if pushFromCases[n.PhFromCase] && n.PhReaded != "Y" {
-			for _, tok := range tokensByMb[n.MbID] {
-				candidate = append(candidate, w.buildMessage(n, tok))
+		// 글·게시판 뮤트로 조용히 기록된 행은 읽음으로 만들어지므로 여기서 함께 빠진다.
+		deliver := ok && pushFromCases[n.PhFromCase] && n.PhReaded != "Y" && w.allowed(&n, prefs, now)
+		if deliver {
+			for i := range w.providers {
+				count += len(targets[i][userID])
 			}
 		}
-		if len(msgs)+len(candidate) > w.maxPerCycle && lastProcessed > cursor {
+		if total+count > w.maxPerCycle && lastProcessed > cursor {
 			break
 		}
-		msgs = append(msgs, candidate...)
+		if deliver && count > 0 {
+			note := buildPushNote(n)
+			for i := range w.providers {
+				for _, t := range targets[i][userID] {
+					msgs[i] = append(msgs[i], pushMessage{Target: t, Note: note})
+				}
+			}
+		}
+		total += count
 		lastProcessed = n.PhID
 	}
 
@@ -226,14 +308,55 @@ func (w *PushNotifyWorker) processBatch() {
 	if !w.claimCursor(cursor, lastProcessed) {
 		return
 	}
-	if len(msgs) > 0 {
-		w.sendAll(msgs)
+	for i, p := range w.providers {
+		if len(msgs[i]) == 0 {
+			continue
+		}
+		started := time.Now()
+		res := p.send(msgs[i])
+		pushSendDurationSeconds.WithLabelValues(p.name()).Observe(time.Since(started).Seconds())
+		pushMessagesTotal.WithLabelValues(p.name(), "sent").Add(float64(res.sent))
+		pushMessagesTotal.WithLabelValues(p.name(), "failed").Add(float64(res.failed))
+		pushMessagesTotal.WithLabelValues(p.name(), "pruned").Add(float64(res.pruned))
+		log.Printf("[PushNotifyWorker] %s cycle done: sent=%d failed=%d pruned=%d", p.name(), res.sent, res.failed, res.pruned)
+	}
+}
+
+// allowed applies the recipient's switches, rules and quiet hours. 방해 금지 시간에 걸린 알림은 미루지 않고
+// 푸시만 건너뛴다 — 목록·뱃지에는 그대로 있다. 설정을 못 읽으면 예전처럼 보낸다.
+func (w *PushNotifyWorker) allowed(n *gnurepo.Notification, prefs map[string]*gnurepo.NotiPreference, now time.Time) bool {
+	if w.prefRepo == nil {
+		return true
+	}
+	pref, ok := prefs[n.MbID]
+	if !ok {
+		var err error
+		if pref, err = w.prefRepo.Get(n.MbID); err != nil {
+			log.Printf("[PushNotifyWorker] preference load failed for %s: %v", n.MbID, err)
+			pref = nil
+		}
+		prefs[n.MbID] = pref
+	}
+	if pref == nil {
+		return true
 	}
+	if pref.InQuietHours(now) {
+		return false
+	}
+	if w.rules == nil {
+		return pref.Allows(n)
+	}
+	decision, err := w.rules.Decide(pref, n)
+	if err != nil {
+		log.Printf("[PushNotifyWorker] rule load failed for %s: %v", n.MbID, err)
+		return pref.Allows(n)
+	}
+	return decision == gnurepo.NotiDeliver
 }
 
-// resolveDeviceTokens maps recipient mb_ids to their Expo push tokens.
-func (w *PushNotifyWorker) resolveDeviceTokens(mbSet map[string]struct{}) map[string][]string {
-	out := make(map[string][]string)
+// resolveUsers maps recipient mb_ids to v2 user ids (등록은 v2 user id 기준이다)
+func (w *PushNotifyWorker) resolveUsers(mbSet map[string]struct{}) map[string]uint64 {
+	out := make(map[string]uint64)
 	if len(mbSet) == 0 {
 		return out
 	}
@@ -241,39 +364,18 @@ func (w *PushNotifyWorker) resolveDeviceTokens(mbSet map[string]struct{}) map[st
 	for mb := range mbSet {
 		mbIDs = append(mbIDs, mb)
 	}
-
 	var users []v2domain.V2User
 	if err := w.db.Select("id", "username").Where("username IN ?", mbIDs).Find(&users).Error; err != nil {
 		log.Printf("[PushNotifyWorker] user lookup failed: %v", err)
 		return out
 	}
-	if len(users) == 0 {
-		return out
-	}
-	idToMb := make(map[uint64]string, len(users))
-	userIDs := make([]uint64, 0, len(users))
 	for _, u := range users {
-		idToMb[u.ID] = u.Username
-		userIDs = append(userIDs, u.ID)
-	}
-
-	var devices []v2domain.V2Device
-	if err := w.db.Where("user_id IN ?", userIDs).Find(&devices).Error; err != nil {
-		log.Printf("[PushNotifyWorker] device lookup failed: %v", err)
-		return out
-	}
-	for _, d := range devices {
-		// Only Expo tokens are routable via exp.host; skip anything else.
-		if !strings.HasPrefix(d.Token, "ExponentPushToken") && !strings.HasPrefix(d.Token, "ExpoPushToken") {
-			continue
-		}
-		mb := idToMb[d.UserID]
-		out[mb] = append(out[mb], d.Token)
+		out[u.Username] = u.ID
 	}
 	return out
 }
 
-func (w *PushNotifyWorker) buildMessage(n gnurepo.Notification, token string) expoPushMessage {
+func buildPushNote(n gnurepo.Notification) pushNote {
 	body := strings.TrimSpace(n.RelMsg)
 	if body == "" {
 		body = strings.TrimSpace(n.ParentSubject)
@@ -281,16 +383,14 @@ func (w *PushNotifyWorker) buildMessage(n gnurepo.Notification, token string) ex
 	if r := []rune(body); len(r) > 120 {
 		body = string(r[:120]) + "…"
 	}
-	return expoPushMessage{
-		To: token,
+	return pushNote{
+		PhID: n.PhID,
 		// 제목은 handler.NotificationTitle 단일 소스 사용 — 복제 금지(bug#13242).
 		// comment 계열의 80%는 "내 글에 댓글"이라 toCase/wrParent 구분이 필수다.
-		Title:     handler.NotificationTitle(n.PhFromCase, n.PhToCase, n.RelMbNick, n.WrID, n.WrParent),
-		Body:      body,
-		Data:      map[string]string{"url": pushAppURL(n)},
-		Sound:     "default",
-		ChannelID: "default",
-		Priority:  "high",
+		Title:  handler.NotificationTitle(n.PhFromCase, n.PhToCase, n.RelMbNick, n.WrID, n.WrParent),
+		Body:   body,
+		AppURL: pushAppURL(n),
+		WebURL: handler.NotificationURL(n),
 	}
 }
 
@@ -309,103 +409,3 @@ func pushAppURL(n gnurepo.Notification) string {
 	}
 	return ""
 }
-
-// sendAll pushes messages to Expo in chunks of 100 and cleans up dead tokens.
-// Ticket order matches message order within a chunk (Expo semantics), so the
-// dead token is read straight from msgs — no parallel slice to misalign.
-func (w *PushNotifyWorker) sendAll(msgs []expoPushMessage) {
-	const chunkSize = 100
-	sent, failed := 0, 0
-	for start := 0; start < len(msgs); start += chunkSize {
-		end := start + chunkSize
-		if end > len(msgs) {
-			end = len(msgs)
-		}
-		tickets, err := w.sendChunk(msgs[start:end])
-		if err != nil {
-			failed += end - start
-			log.Printf("[PushNotifyWorker] chunk send failed (%d msgs): %v", end-start, err)
-			continue
-		}
-		for i, t := range tickets {
-			if t.Status == "ok" {
-				sent++
-				continue
-			}
-			failed++
-			if t.Details.Error == "DeviceNotRegistered" && start+i < len(msgs) {
-				w.deleteDeadToken(msgs[start+i].To)
-			}
-		}
-	}
-	log.Printf("[PushNotifyWorker] cycle done: sent=%d failed=%d", sent, failed)
-}
-
-func (w *PushNotifyWorker) sendChunk(msgs []expoPushMessage) ([]expoPushTicket, error) {
-	payload, err := json.Marshal(msgs)
-	if err != nil {
-		return nil, err
-	}
-
-	var lastErr error
-	for attempt := 0; attempt < 2; attempt++ {
-		if attempt > 0 {
-			time.Sleep(2 * time.Second)
-		}
-		// context.Background() 는 http.NewRequest 의 내부 동작과 동일하다 —
-		// 취소 신호를 새로 도입하지 않으려 일부러 그대로 둔다. 요청 시한은
-		// 종전처럼 w.client 의 Timeout 이 담당한다.
-		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, expoPushURL, bytes.NewReader(payload))
-		if err != nil {
-			return nil, err
-		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Accept", "application/json")
-		if w.accessToken != "" {
-			req.Header.Set("Authorization", "Bearer "+w.accessToken)
-		}
-
-		resp, err := w.httpClient.Do(req)
-		if err != nil {
-			// 전송 계층 오류는 재시도하지 않는다 — Expo 가 이미 배치를 수신했을 수
-			// 있어(응답 유실) 재전송하면 중복 푸시가 된다. 미발송(인앱엔 남음)을 택함.
-			return nil, err
-		}
-		body, readErr := io.ReadAll(resp.Body)
-		_ = resp.Body.Close()
-		if readErr != nil {
-			return nil, readErr
-		}
-		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
-			lastErr = fmt.Errorf("expo push http %d: %s", resp.StatusCode, truncateForLog(body))
-			continue
-		}
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("expo push http %d: %s", resp.StatusCode, truncateForLog(body))
-		}
-		var parsed struct {
-			Data []expoPushTicket `json:"data"`
-		}
-		if err := json.Unmarshal(body, &parsed); err != nil {
-			return nil, fmt.Errorf("expo push response parse: %w", err)
-		}
-		return parsed.Data, nil
-	}
-	return nil, lastErr
-}
-
-func (w *PushNotifyWorker) deleteDeadToken(token string) {
-	if err := w.db.Where("token = ?", token).Delete(&v2domain.V2Device{}).Error; err != nil {
-		log.Printf("[PushNotifyWorker] dead token delete failed: %v", err)
-		return
-	}
-	log.Printf("[PushNotifyWorker] removed DeviceNotRegistered token")
-}
-
-func truncateForLog(b []byte) string {
-	s := string(b)
-	if len(s) > 200 {
-		return s[:200]
-	}
-	return s
-}
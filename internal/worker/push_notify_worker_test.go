@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	v2domain "github.com/damoang/angple-backend/internal/domain/v2"
+	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type fakePushProvider struct {
+	label string
+	reg   map[uint64][]pushTarget
+	got   []pushMessage
+}
+
+func (p *fakePushProvider) name() string { return p.label }
+
+func (p *fakePushProvider) targets(userIDs []uint64) (map[uint64][]pushTarget, error) {
+	return p.reg, nil
+}
+
+func (p *fakePushProvider) send(msgs []pushMessage) pushResult {
+	p.got = append(p.got, msgs...)
+	return pushResult{sent: len(msgs)}
+}
+
+func newPushTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&gnurepo.Notification{}, &v2domain.V2PushCursor{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	// v2_users 의 enum 컬럼은 sqlite 가 못 읽는다 — 워커가 읽는 열만 만든다
+	db.Exec("CREATE TABLE v2_users (id integer PRIMARY KEY, username varchar(50))")
+	db.Exec("INSERT INTO v2_users (id, username) VALUES (7, 'zoe'), (8, 'kim')")
+	db.Create(&v2domain.V2PushCursor{ID: 1, LastPhID: 0})
+	return db
+}
+
+func TestPushNotifyFansOutToProviders(t *testing.T) {
+	db := newPushTestDB(t)
+	old := time.Now().Add(-time.Minute)
+	rows := []gnurepo.Notification{
+		{MbID: "zoe", PhFromCase: "memo", RelMsg: "안녕", RelURL: "/bbs/board.php?bo_table=free&wr_id=3", BoTable: "free", WrID: 3, PhDatetime: old},
+		{MbID: "zoe", PhFromCase: "digest", PhDatetime: old},                     // 화이트리스트 밖
+		{MbID: "zoe", PhFromCase: "memo", PhReaded: "Y", PhDatetime: old},        // 이미 읽음
+		{MbID: "ghost", PhFromCase: "memo", PhDatetime: old},                     // v2 회원 없음
+		{MbID: "zoe", PhFromCase: "memo", PhDatetime: time.Now().Add(time.Hour)}, // 아직 정착 전
+	}
+	for i := range rows {
+		if err := db.Create(&rows[i]).Error; err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	app := &fakePushProvider{label: "app", reg: map[uint64][]pushTarget{7: {{Address: "ExponentPushToken[a]"}}}}
+	web := &fakePushProvider{label: "web", reg: map[uint64][]pushTarget{7: {{Address: "https://fcm.googleapis.com/fcm/send/1"}, {Address: "https://fcm.googleapis.com/fcm/send/2"}}}}
+	w := NewPushNotifyWorker(db)
+	w.providers = []pushProvider{app, web}
+	w.processBatch()
+
+	if len(app.got) != 1 || len(web.got) != 2 {
+		t.Fatalf("expected 1 app + 2 web messages, got %d + %d", len(app.got), len(web.got))
+	}
+	note := web.got[0].Note
+	if note.PhID != rows[0].PhID || note.Body != "안녕" || note.WebURL != "/free/3" || note.AppURL != "/messages" {
+		t.Fatalf("unexpected note %+v", note)
+	}
+	var cur v2domain.V2PushCursor
+	db.First(&cur, "id = 1")
+	if cur.LastPhID != rows[3].PhID {
+		t.Fatalf("cursor should stop before the unsettled row, got %d", cur.LastPhID)
+	}
+}
+
+func TestPushNotifyHonorsRulesAndQuietHours(t *testing.T) {
+	db := newPushTestDB(t)
+	if err := db.AutoMigrate(&gnurepo.NotiPreference{}, &gnurepo.NotiRule{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	// zoe 는 지금 방해 금지 시간이다
+	now := time.Now().UTC()
+	db.Create(&gnurepo.NotiPreference{MbID: "zoe", NotiComment: true, NotiReply: true, NotiMention: true, NotiLike: true,
+		QuietStart: now.Add(-time.Hour).Format("15:04"), QuietEnd: now.Add(time.Hour).Format("15:04"), TimeZone: "UTC"})
+	rules := gnurepo.NewNotiRuleRepository(db)
+	_ = rules.Set(&gnurepo.NotiRule{MbID: "kim", BoTable: "free", WrID: 10, Mode: gnurepo.NotiRuleMute})
+
+	old := time.Now().Add(-time.Minute)
+	rows := []gnurepo.Notification{
+		{MbID: "zoe", PhFromCase: "memo", PhDatetime: old},
+		{MbID: "kim", PhFromCase: "comment", PhToCase: "comment", BoTable: "free", WrID: 11, WrParent: 10, PhDatetime: old}, // 글 뮤트
+		{MbID: "kim", PhFromCase: "comment", PhToCase: "comment", BoTable: "free", WrID: 21, WrParent: 20, PhDatetime: old},
+	}
+	for i := range rows {
+		if err := db.Create(&rows[i]).Error; err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	app := &fakePushProvider{label: "app", reg: map[uint64][]pushTarget{7: {{Address: "ExponentPushToken[z]"}}, 8: {{Address: "ExponentPushToken[k]"}}}}
+	w := NewPushNotifyWorker(db)
+	w.providers = []pushProvider{app}
+	w.SetNotiRules(gnurepo.NewNotiPreferenceRepository(db), rules)
+	w.processBatch()
+
+	if len(app.got) != 1 || app.got[0].Note.PhID != rows[2].PhID {
+		t.Fatalf("only the unmuted comment should be pushed, got %+v", app.got)
+	}
+}
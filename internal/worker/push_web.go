@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	v2repo "github.com/damoang/angple-backend/internal/repository/v2"
+	"github.com/damoang/angple-backend/pkg/webpush"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// webPushConcurrency 는 동시에 보내는 요청 수다 — Web Push 는 구독마다 요청 하나라 Expo 처럼 묶을 수 없다
+	webPushConcurrency = 16
+	// webPushTTL 동안 푸시 서비스가 꺼진 브라우저를 위해 메시지를 들고 있다
+	webPushTTL = 12 * time.Hour
+)
+
+// webPushProvider sends to browser subscriptions (v2_web_push_subscriptions) with RFC 8030 Web Push
+type webPushProvider struct {
+	repo   v2repo.WebPushRepository
+	sender *webpush.Sender
+}
+
+func newWebPushProvider(repo v2repo.WebPushRepository, vapid *webpush.VAPID) *webPushProvider {
+	return &webPushProvider{repo: repo, sender: webpush.NewSender(vapid, nil)}
+}
+
+func (p *webPushProvider) name() string { return "webpush" }
+
+func (p *webPushProvider) targets(userIDs []uint64) (map[uint64][]pushTarget, error) {
+	out := make(map[uint64][]pushTarget)
+	subs, err := p.repo.ListByUsers(userIDs)
+	if err != nil {
+		return out, err
+	}
+	for _, s := range subs {
+		out[s.UserID] = append(out[s.UserID], pushTarget{Address: s.Endpoint, P256dh: s.P256dh, Auth: s.Auth})
+	}
+	return out, nil
+}
+
+// webPushPayload is what the service worker receives (push 이벤트의 event.data.json())
+type webPushPayload struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+// send posts each message; 404·410 이면 구독이 사라진 것이라 지운다
+func (p *webPushProvider) send(msgs []pushMessage) pushResult {
+	var (
+		mu  sync.Mutex
+		res pushResult
+	)
+	count := func(sent, failed, pruned int) {
+		mu.Lock()
+		res.sent += sent
+		res.failed += failed
+		res.pruned += pruned
+		mu.Unlock()
+	}
+
+	var g errgroup.Group
+	g.SetLimit(webPushConcurrency)
+	for _, m := range msgs {
+		g.Go(func() error {
+			payload, err := json.Marshal(webPushPayload{ID: m.Note.PhID, Title: m.Note.Title, Body: m.Note.Body, URL: m.Note.WebURL})
+			if err != nil {
+				count(0, 1, 0)
+				return nil
+			}
+			sub := webpush.Subscription{Endpoint: m.Target.Address, P256dh: m.Target.P256dh, Auth: m.Target.Auth}
+			// 재시도하지 않는다 — Expo 와 같이 중복보다 미발송을 택한다(인앱 알림이 원본이다)
+			status, err := p.sender.Send(context.Background(), sub, payload, webpush.Options{TTL: webPushTTL, Urgency: "normal"})
+			switch {
+			case errors.Is(err, webpush.ErrInvalidSubscription):
+				// 더는 받을 수 없는 구독(허용 목록 밖 엔드포인트·깨진 키) — 매 주기 실패하지 않도록 지운다
+				if p.repo.DeleteByEndpoint(sub.Endpoint) == nil {
+					count(0, 0, 1)
+					return nil
+				}
+				count(0, 1, 0)
+			case err != nil:
+				count(0, 1, 0)
+				log.Printf("[PushNotifyWorker] webpush send failed: %v", err)
+			case webpush.Expired(status):
+				if err := p.repo.DeleteByEndpoint(sub.Endpoint); err != nil {
+					log.Printf("[PushNotifyWorker] expired subscription delete failed: %v", err)
+					count(0, 1, 0)
+					return nil
+				}
+				count(0, 0, 1)
+			case status == http.StatusCreated || status == http.StatusOK || status == http.StatusAccepted:
+				count(1, 0, 0)
+			default:
+				count(0, 1, 0)
+				log.Printf("[PushNotifyWorker] webpush http %d", status)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return res
+}
@@ -19,6 +19,7 @@ import (
 
 	gnurepo "github.com/damoang/angple-backend/internal/repository/gnuboard"
 	"github.com/damoang/angple-backend/internal/service"
+	"github.com/damoang/angple-backend/internal/ws"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"gorm.io/gorm"
@@ -27,6 +28,21 @@ import (
 type BlockedIDsProvider func(ctx context.Context, userID string) []string
 type ClearPostMemCacheFunc func(boardSlug string)
 
+// SearchIndexer keeps the search index in step with g5_write_* (service.SearchService).
+// 모든 메서드는 DB 의 현재 상태를 다시 읽어 색인하거나 지운다 — 순서가 뒤바뀌거나 중복돼도 수렴한다.
+type SearchIndexer interface {
+	SyncPost(ctx context.Context, boardSlug string, postID int) error
+	SyncThread(ctx context.Context, boardSlug string, postID int) error
+	SyncComment(ctx context.Context, boardSlug string, commentID int) error
+	SyncMember(ctx context.Context, memberID string) error
+}
+
+// searchSyncTimeout 은 검색 동기화 이벤트 하나의 상한이다. 회원 단위는 전 게시판을 돈다.
+const (
+	searchSyncTimeout       = 30 * time.Second
+	searchMemberSyncTimeout = 5 * time.Minute
+)
+
 var (
 	writeAfterEventsProcessedTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -90,6 +106,9 @@ type WriteAfterWorker struct {
 	activitySync   *service.MemberActivitySyncService
 	getBlockedIDs  BlockedIDsProvider
 	clearPostCache ClearPostMemCacheFunc
+	search         SearchIndexer
+	topics         ws.TopicPublisher
+	notiRules      gnurepo.NotiRuleRepository
 
 	repo           gnurepo.WriteAfterEventRepository
 	pollInterval   time.Duration
@@ -150,6 +169,29 @@ func NewWriteAfterWorker(
 	}
 }
 
+// SetSearchIndexer enables search indexing from the outbox (nil 이면 검색 이벤트를 만들지 않는다)
+func (w *WriteAfterWorker) SetSearchIndexer(indexer SearchIndexer) {
+	w.search = indexer
+}
+
+// SetTopicPublisher streams new posts·comments to realtime topic subscribers (nil 이면 보내지 않는다)
+func (w *WriteAfterWorker) SetTopicPublisher(topics ws.TopicPublisher) {
+	w.topics = topics
+}
+
+// SetNotiRules applies thread·board rules to created notifications (nil 이면 종류별 스위치만 본다)
+func (w *WriteAfterWorker) SetNotiRules(rules gnurepo.NotiRuleRepository) {
+	w.notiRules = rules
+}
+
+// publishTopic sends an id-only hint — 구독자는 REST 로 다시 읽으므로 비밀 댓글 등 항목 권한은 REST 가 적용한다.
+func (w *WriteAfterWorker) publishTopic(topic, eventType string, payload map[string]any, actor string) {
+	if w.topics == nil {
+		return
+	}
+	w.topics.PublishTopic(topic, &ws.Event{Type: eventType, Payload: payload}, actor)
+}
+
 func (w *WriteAfterWorker) Start(concurrency int) {
 	if concurrency < 1 {
 		concurrency = 1
@@ -283,7 +325,8 @@ func (w *WriteAfterWorker) handleEvent(event gnudomain.WriteAfterEvent) error {
 			Subject:   event.Subject,
 			CreatedAt: event.OccurredAt,
 		})
-		return nil
+		w.publishTopic(ws.BoardTopic(event.BoardSlug), "post_created", map[string]any{"wr_id": event.WriteID}, event.MemberID)
+		return w.enqueueSearchSync(event)
 	case gnudomain.WriteAfterEventTypeCommentCreated:
 		postID := 0
 		if event.PostID != nil {
@@ -298,17 +341,101 @@ func (w *WriteAfterWorker) handleEvent(event gnudomain.WriteAfterEvent) error {
 			Author:    event.Author,
 			CreatedAt: event.OccurredAt,
 		})
-		return nil
-	case gnudomain.WriteAfterEventTypePostUpdated, gnudomain.WriteAfterEventTypePostDeleted, gnudomain.WriteAfterEventTypePostRestored:
+		w.publishTopic(ws.PostTopic(event.BoardSlug, postID), "comment_created", map[string]any{
+			"comment_id": event.WriteID,
+			"parent_id":  event.ParentID,
+		}, event.MemberID)
+		return w.enqueueSearchSync(event)
+	case gnudomain.WriteAfterEventTypePostUpdated, gnudomain.WriteAfterEventTypePostDeleted,
+		gnudomain.WriteAfterEventTypePostRestored, gnudomain.WriteAfterEventTypePostMoved:
 		w.handlePostChanged(event)
-		return nil
+		return w.enqueueSearchSync(event)
 	case gnudomain.WriteAfterEventTypeCommentUpdated, gnudomain.WriteAfterEventTypeCommentDeleted, gnudomain.WriteAfterEventTypeCommentRestored:
-		return w.handleCommentChanged(event)
+		if err := w.handleCommentChanged(event); err != nil {
+			return err
+		}
+		return w.enqueueSearchSync(event)
 	case gnudomain.WriteAfterEventTypeAffiliatePostSync,
 		gnudomain.WriteAfterEventTypeAffiliateCommentSync,
 		gnudomain.WriteAfterEventTypeAffiliatePostDelete,
 		gnudomain.WriteAfterEventTypeAffiliateCommentDelete:
 		return w.handleAffiliateEvent(event)
+	case gnudomain.WriteAfterEventTypeSearchPostSync,
+		gnudomain.WriteAfterEventTypeSearchThreadSync,
+		gnudomain.WriteAfterEventTypeSearchCommentSync,
+		gnudomain.WriteAfterEventTypeSearchMemberSync:
+		return w.handleSearchEvent(event)
+	default:
+		return gnurepo.FormatUnknownWriteAfterEvent(event.EventType)
+	}
+}
+
+// searchSyncEventType maps a content event to the search event derived from it
+func searchSyncEventType(eventType string) string {
+	switch eventType {
+	case gnudomain.WriteAfterEventTypePostCreated:
+		return gnudomain.WriteAfterEventTypeSearchPostSync
+	case gnudomain.WriteAfterEventTypePostUpdated, // 비밀글 전환은 수정으로 들어온다 — 댓글 노출도 같이 바뀐다
+		gnudomain.WriteAfterEventTypePostDeleted,
+		gnudomain.WriteAfterEventTypePostRestored,
+		gnudomain.WriteAfterEventTypePostMoved:
+		return gnudomain.WriteAfterEventTypeSearchThreadSync
+	case gnudomain.WriteAfterEventTypeCommentCreated,
+		gnudomain.WriteAfterEventTypeCommentUpdated,
+		gnudomain.WriteAfterEventTypeCommentDeleted,
+		gnudomain.WriteAfterEventTypeCommentRestored:
+		return gnudomain.WriteAfterEventTypeSearchCommentSync
+	default:
+		return ""
+	}
+}
+
+// enqueueSearchSync derives the search event from a processed content event.
+//
+// 색인을 여기서 바로 하지 않고 이벤트를 하나 더 넣는 이유: 실패하면 재시도되는 단위가 이벤트라,
+// 같이 하면 ES 장애 동안 알림·캐시 처리까지 통째로 재시도된다. 넣기에 실패하면 원 이벤트를
+// 실패로 돌려 재시도한다(알림은 createNotification 의 중복 가드가 막는다).
+func (w *WriteAfterWorker) enqueueSearchSync(event gnudomain.WriteAfterEvent) error {
+	if w.search == nil || w.repo == nil {
+		return nil
+	}
+	eventType := searchSyncEventType(event.EventType)
+	if eventType == "" {
+		return nil
+	}
+	return w.repo.Create(nil, &gnudomain.WriteAfterEvent{
+		EventType:  eventType,
+		BoardSlug:  event.BoardSlug,
+		WriteID:    event.WriteID,
+		PostID:     event.PostID,
+		MemberID:   event.MemberID,
+		Status:     gnudomain.WriteAfterEventStatusPending,
+		OccurredAt: time.Now(),
+	})
+}
+
+// handleSearchEvent applies a search sync event.
+// 색인기가 없는 인스턴스(ES 미가용)는 건너뛴다 — 다음 정합성 검사(search-reconcile)가 메운다.
+func (w *WriteAfterWorker) handleSearchEvent(event gnudomain.WriteAfterEvent) error {
+	if w.search == nil {
+		return nil
+	}
+	timeout := searchSyncTimeout
+	if event.EventType == gnudomain.WriteAfterEventTypeSearchMemberSync {
+		timeout = searchMemberSyncTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	switch event.EventType {
+	case gnudomain.WriteAfterEventTypeSearchPostSync:
+		return w.search.SyncPost(ctx, event.BoardSlug, event.WriteID)
+	case gnudomain.WriteAfterEventTypeSearchThreadSync:
+		return w.search.SyncThread(ctx, event.BoardSlug, event.WriteID)
+	case gnudomain.WriteAfterEventTypeSearchCommentSync:
+		return w.search.SyncComment(ctx, event.BoardSlug, event.WriteID)
+	case gnudomain.WriteAfterEventTypeSearchMemberSync:
+		return w.search.SyncMember(ctx, event.MemberID)
 	default:
 		return gnurepo.FormatUnknownWriteAfterEvent(event.EventType)
 	}
@@ -375,7 +502,8 @@ func (w *WriteAfterWorker) markFailed(event gnudomain.WriteAfterEvent, err error
 	if w.repo == nil {
 		return nil
 	}
-	if isAffiliateEventType(event.EventType) {
+	// 검색 동기화도 외부 시스템(ES) 호출이라 같은 상한·백오프를 쓴다. 놓친 건 search-reconcile 이 메운다.
+	if isAffiliateEventType(event.EventType) || isSearchEventType(event.EventType) {
 		// 재시도가 무의미한 실패(원글 삭제 등)는 상한을 기다리지 않고 바로 접는다.
 		var permErr *affiliatePermanentError
 		if errors.As(err, &permErr) {
@@ -428,6 +556,18 @@ const (
 	stopTimeout = 5 * time.Second
 )
 
+func isSearchEventType(eventType string) bool {
+	switch eventType {
+	case gnudomain.WriteAfterEventTypeSearchPostSync,
+		gnudomain.WriteAfterEventTypeSearchThreadSync,
+		gnudomain.WriteAfterEventTypeSearchCommentSync,
+		gnudomain.WriteAfterEventTypeSearchMemberSync:
+		return true
+	default:
+		return false
+	}
+}
+
 func affiliateRetryDelay(retryCount int) time.Duration {
 	switch retryCount {
 	case 0:
@@ -590,10 +730,10 @@ func (w *WriteAfterWorker) handlePostCreated(job PostCreatedJob) {
 			continue
 		}
 		pref, ok := w.mustGetNotiPreference(fid)
-		if !ok || !pref.NotiFollow {
+		if !ok {
 			continue
 		}
-		w.createNotification(&gnurepo.Notification{
+		w.createNotification(pref, &gnurepo.Notification{
 			PhToCase: "follow", PhFromCase: "write", BoTable: job.BoardSlug,
 			WrID: job.WriteID, MbID: fid, RelMbID: job.MemberID,
 			RelMbNick:  authorName,
@@ -621,11 +761,12 @@ func (w *WriteAfterWorker) handlePostCreated(job PostCreatedJob) {
 		if blockedBy[sid] {
 			continue
 		}
+		// 게시판 규칙(on·mute)이 NotiBoardSubscribe 보다 우선한다 — createNotification 의 판정
 		pref, ok := w.mustGetNotiPreference(sid)
-		if !ok || !pref.NotiBoardSubscribe {
+		if !ok {
 			continue
 		}
-		w.createNotification(&gnurepo.Notification{
+		w.createNotification(pref, &gnurepo.Notification{
 			PhToCase: "subscribe", PhFromCase: "write", BoTable: job.BoardSlug,
 			WrID: job.WriteID, MbID: sid, RelMbID: job.MemberID,
 			RelMbNick:  authorName,
@@ -673,16 +814,16 @@ func (w *WriteAfterWorker) handleCommentCreated(job CommentCreatedJob) {
 	//    답글 알림 + 댓글 알림이 **같은 사람에게 두 개** 간다(bug/13206, 하루 200~300건).
 	//    레거시 PHP 에는 이 가드가 있었는데(hook.lib.php:1186) Go 이식 때 빠졌다.
 	repliedToPostAuthor := false
+	// notified 는 이 댓글로 이미 알림 대상이 된 회원이다 — 글 팔로워 알림에서 뺀다
+	notified := map[string]bool{job.MemberID: true, postAuthor.MbID: true}
 
 	if job.ParentID != nil && *job.ParentID > 0 {
 		var parentAuthorMbID string
 		if err := w.db.Table(tableName).Select("mb_id").Where("wr_id = ?", *job.ParentID).Scan(&parentAuthorMbID).Error; err == nil && parentAuthorMbID != "" && parentAuthorMbID != job.MemberID {
+			notified[parentAuthorMbID] = true
 			if !w.isBlocked(parentAuthorMbID, job.MemberID) {
-				if pref, ok := w.mustGetNotiPreference(parentAuthorMbID); ok && pref.NotiReply {
-					if parentAuthorMbID == postAuthor.MbID {
-						repliedToPostAuthor = true
-					}
-					w.createNotification(&gnurepo.Notification{
+				if pref, ok := w.mustGetNotiPreference(parentAuthorMbID); ok {
+					recorded := w.createNotification(pref, &gnurepo.Notification{
 						PhToCase:      "comment_reply",
 						PhFromCase:    "comment",
 						BoTable:       job.BoardSlug,
@@ -697,32 +838,70 @@ func (w *WriteAfterWorker) handleCommentCreated(job CommentCreatedJob) {
 						ParentSubject: postAuthor.WrSubject,
 						WrParent:      job.PostID,
 					})
+					if recorded && parentAuthorMbID == postAuthor.MbID {
+						repliedToPostAuthor = true
+					}
 				}
 			}
 		}
 	}
 
 	// 같은 사건으로 답글 알림을 이미 보냈으면 글 알림은 생략한다 — 더 구체적인 쪽(답글)이 남는다.
-	if repliedToPostAuthor {
+	if !repliedToPostAuthor && postAuthor.MbID != job.MemberID && !w.isBlocked(postAuthor.MbID, job.MemberID) {
+		if pref, ok := w.mustGetNotiPreference(postAuthor.MbID); ok {
+			w.createNotification(pref, &gnurepo.Notification{
+				PhToCase:      "comment",
+				PhFromCase:    "comment",
+				BoTable:       job.BoardSlug,
+				WrID:          job.WriteID,
+				MbID:          postAuthor.MbID,
+				RelMbID:       job.MemberID,
+				RelMbNick:     job.Author,
+				RelMsg:        fmt.Sprintf("%s님이 회원님의 글에 댓글을 남겼습니다.", job.Author),
+				RelURL:        fmt.Sprintf("/%s/%d#c_%d", job.BoardSlug, job.PostID, job.WriteID),
+				PhReaded:      "N",
+				PhDatetime:    job.CreatedAt,
+				ParentSubject: postAuthor.WrSubject,
+				WrParent:      job.PostID,
+			})
+		}
+	}
+
+	w.notifyThreadFollowers(job, postAuthor.WrSubject, notified)
+}
+
+// notifyThreadFollowers tells members who follow the thread (g5_noti_rule follow) about a new comment.
+// 글·댓글 작성자가 아니어도 받는다. 작성자·답글/댓글 알림을 이미 받은 사람·보낸 사람을 차단한 회원은 뺀다.
+func (w *WriteAfterWorker) notifyThreadFollowers(job CommentCreatedJob, subject string, notified map[string]bool) {
+	if w.notiRules == nil {
 		return
 	}
-	if postAuthor.MbID == job.MemberID || w.isBlocked(postAuthor.MbID, job.MemberID) {
+	followers, err := w.notiRules.ThreadFollowers(job.BoardSlug, job.PostID)
+	if err != nil {
+		log.Printf("[WriteAfterWorker] thread followers lookup failed for %s/%d: %v", job.BoardSlug, job.PostID, err)
 		return
 	}
-	if pref, ok := w.mustGetNotiPreference(postAuthor.MbID); ok && pref.NotiComment {
-		w.createNotification(&gnurepo.Notification{
-			PhToCase:      "comment",
+	for _, mbID := range followers {
+		if notified[mbID] || w.isBlocked(mbID, job.MemberID) {
+			continue
+		}
+		pref, ok := w.mustGetNotiPreference(mbID)
+		if !ok {
+			continue
+		}
+		w.createNotification(pref, &gnurepo.Notification{
+			PhToCase:      "thread",
 			PhFromCase:    "comment",
 			BoTable:       job.BoardSlug,
 			WrID:          job.WriteID,
-			MbID:          postAuthor.MbID,
+			MbID:          mbID,
 			RelMbID:       job.MemberID,
 			RelMbNick:     job.Author,
-			RelMsg:        fmt.Sprintf("%s님이 회원님의 글에 댓글을 남겼습니다.", job.Author),
+			RelMsg:        fmt.Sprintf("%s님이 팔로우한 글에 댓글을 남겼습니다.", job.Author),
 			RelURL:        fmt.Sprintf("/%s/%d#c_%d", job.BoardSlug, job.PostID, job.WriteID),
 			PhReaded:      "N",
 			PhDatetime:    job.CreatedAt,
-			ParentSubject: postAuthor.WrSubject,
+			ParentSubject: subject,
 			WrParent:      job.PostID,
 		})
 	}
@@ -805,14 +984,36 @@ func (w *WriteAfterWorker) mustGetNotiPreference(mbID string) (*gnurepo.NotiPref
 	return pref, true
 }
 
-func (w *WriteAfterWorker) createNotification(noti *gnurepo.Notification) {
+// createNotification records noti if the recipient's switches and rules allow it and reports whether it did.
+// 글·게시판 뮤트(NotiSilent)는 읽음으로 남긴다 — 목록엔 있고 실시간·푸시는 울리지 않는다.
+func (w *WriteAfterWorker) createNotification(pref *gnurepo.NotiPreference, noti *gnurepo.Notification) bool {
+	decision := gnurepo.NotiDeliver
+	if !pref.Allows(noti) {
+		decision = gnurepo.NotiDrop
+	}
+	if w.notiRules != nil {
+		d, err := w.notiRules.Decide(pref, noti)
+		if err != nil {
+			log.Printf("[WriteAfterWorker] notification rule lookup failed for %s: %v", noti.MbID, err)
+		} else {
+			decision = d
+		}
+	}
+	switch decision {
+	case gnurepo.NotiDrop:
+		return false
+	case gnurepo.NotiSilent:
+		noti.PhReaded = "Y"
+	}
+
 	// 중복 가드: 알림 INSERT 후 MarkProcessed 전에 프로세스가 죽으면 이벤트가
 	// 재클레임되어 팔로워·구독자 전원에게 같은 알림이 재발행된다.
 	// idx_noti_dedup (bo_table, wr_id, rel_mb_id, ph_from_case) 로 싸게 걸러진다.
 	if exists, err := w.notiRepo.Exists(noti.MbID, noti.BoTable, noti.WrID, noti.PhFromCase, noti.RelMbID); err == nil && exists {
-		return
+		return true
 	}
 	if err := w.notiRepo.Create(noti); err != nil {
 		log.Printf("[WriteAfterWorker] notification create failed for %s/%d: %v", noti.BoTable, noti.WrID, err)
 	}
+	return true
 }
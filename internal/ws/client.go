@@ -1,6 +1,8 @@
 package ws
 
 import (
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -19,19 +21,42 @@ type Client struct {
 	conn     *websocket.Conn
 	send     chan []byte
 	memberID string
+	viewer   Viewer
+
+	// topics·blocked 는 Hub 가 h.mu 를 쥐고 만진다
+	topics  map[string]bool
+	blocked map[string]bool
+
+	// 아래는 ReadPump 전용
+	limit      bucket
+	lastTyping map[string]time.Time
+}
+
+// clientMessage is what a client may send: 토픽 구독·해지와 입력 중 알림
+//
+//	{"action":"subscribe","topic":"post:free:123"}
+//	{"action":"unsubscribe","topic":"post:free:123"}
+//	{"action":"typing","topic":"post:free:123"}
+type clientMessage struct {
+	Action string `json:"action"`
+	Topic  string `json:"topic"`
 }
 
 // NewClient creates a new WebSocket client
-func NewClient(hub *Hub, conn *websocket.Conn, memberID string) *Client {
+func NewClient(hub *Hub, conn *websocket.Conn, viewer Viewer) *Client {
 	return &Client{
-		hub:      hub,
-		conn:     conn,
-		send:     make(chan []byte, 256),
-		memberID: memberID,
+		hub:        hub,
+		conn:       conn,
+		send:       make(chan []byte, 256),
+		memberID:   viewer.MemberID,
+		viewer:     viewer,
+		topics:     make(map[string]bool),
+		limit:      bucket{rate: 2, burst: 20},
+		lastTyping: make(map[string]time.Time),
 	}
 }
 
-// ReadPump reads messages from the WebSocket (handles pong/close)
+// ReadPump reads messages from the WebSocket (handles pong/close and topic actions)
 func (c *Client) ReadPump() {
 	defer func() {
 		c.hub.unregister <- c
@@ -46,12 +71,87 @@ func (c *Client) ReadPump() {
 	})
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, data, err := c.conn.ReadMessage()
 		if err != nil {
 			break
 		}
-		// Client messages are ignored (server-push only)
+		var msg clientMessage
+		if json.Unmarshal(data, &msg) != nil {
+			continue
+		}
+		c.handle(&msg, time.Now())
+	}
+}
+
+// handle runs one client action. 권한 확인(DB)은 여기서 한다 — Hub.Run 을 막지 않도록.
+func (c *Client) handle(msg *clientMessage, now time.Time) {
+	if !c.limit.allow(now) {
+		return // 연결당 요청 한도 — 조용히 버린다
+	}
+	topic, err := ParseTopic(msg.Topic)
+	if err != nil {
+		c.reject(msg.Topic, err)
+		return
+	}
+	switch msg.Action {
+	case "subscribe":
+		if err := c.subscribe(msg.Topic, topic); err != nil {
+			c.reject(msg.Topic, err)
+		}
+	case "unsubscribe":
+		c.hub.subscribe <- &subscription{client: c, topic: msg.Topic, remove: true}
+	case "typing":
+		// 댓글 입력 중 — 글 토픽을 구독 중일 때만, 토픽마다 typingEvery 에 한 번
+		if topic.Kind != TopicPost || !c.hub.subscribed(c, msg.Topic) || now.Sub(c.lastTyping[msg.Topic]) < typingEvery {
+			return
+		}
+		c.lastTyping[msg.Topic] = now
+		c.hub.PublishTopic(msg.Topic, &Event{
+			Type:    "typing",
+			Payload: map[string]string{"member_id": c.memberID, "nick": c.viewer.Nick},
+		}, c.memberID)
+	}
+}
+
+// subscribe checks the viewer's access and asks the hub to add the topic
+func (c *Client) subscribe(name string, topic Topic) error {
+	if c.hub.access == nil {
+		return ErrTopicForbidden
+	}
+	if err := c.hub.access.Authorize(c.viewer, topic); err != nil {
+		if !errors.Is(err, ErrTopicForbidden) {
+			err = ErrTopicForbidden // 조회 실패도 거절 — 내부 오류 문구는 내보내지 않는다
+		}
+		return err
 	}
+	c.hub.subscribe <- &subscription{client: c, topic: name, blocked: c.hub.access.ContentBlocked(c.memberID)}
+	return nil
+}
+
+// SubscribeAll subscribes to topics at once (SSE 는 단방향이라 접속 URL 로만 구독한다).
+// 권한·한도는 {"action":"subscribe"} 와 같고 거절은 error 이벤트로 간다. 받아들인 토픽을 돌려준다.
+func (c *Client) SubscribeAll(names []string) []string {
+	accepted := make([]string, 0, len(names))
+	for i, name := range names {
+		if i >= maxTopicsPerClient {
+			c.reject(name, ErrTopicLimit)
+			continue
+		}
+		topic, err := ParseTopic(name)
+		if err == nil {
+			err = c.subscribe(name, topic)
+		}
+		if err != nil {
+			c.reject(name, err)
+			continue
+		}
+		accepted = append(accepted, name)
+	}
+	return accepted
+}
+
+func (c *Client) reject(topic string, err error) {
+	c.hub.SendToClient(c, &Event{Topic: topic, Type: "error", Payload: map[string]string{"message": err.Error()}})
 }
 
 // WritePump sends messages to the WebSocket
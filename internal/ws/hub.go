@@ -4,15 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
 const redisPubSubChannel = "notifications"
 
+// allMembers 는 접속한 모든 회원에게 보내는 이벤트의 대상이다 (전 회원 방송)
+const allMembers = "*"
+
+// topicTick 은 한도에 걸려 미뤄 둔 토픽 이벤트를 내보내는 간격이다
+const topicTick = 250 * time.Millisecond
+
 // Event represents a real-time notification event sent via WebSocket
 type Event struct {
-	Type    string      `json:"type"`    // "notification", "unread_count"
+	// ID 는 재접속 토큰이다(notification 이벤트의 ph_id). 클라이언트는 마지막으로 받은 ID 를
+	// /ws/notifications?resume=<id> 로 넘기면 놓친 알림을 받는다 — 같은 ID 가 두 번 오면 무시할 것.
+	ID string `json:"id,omitempty"`
+	// Topic 은 토픽 이벤트가 속한 토픽이다 (topic.go)
+	Topic string `json:"topic,omitempty"`
+	// Seq 는 재생 스트림 id 다 (replay.go) — 발행한 이벤트에만 있다. 같은 Seq 가 두 번 오면 무시할 것.
+	Seq     string      `json:"seq,omitempty"`
+	Type    string      `json:"type"`    // "notification", "unread_count", "resync", 토픽 이벤트 타입
 	Payload interface{} `json:"payload"` // event-specific data
 }
 
@@ -20,14 +34,24 @@ type Event struct {
 type Hub struct {
 	// Registered clients grouped by member ID
 	clients map[string]map[*Client]bool
+	// 토픽 구독 — 토픽 → 연결 (client.topics 와 함께 h.mu 로 지킨다)
+	topics map[string]map[*Client]bool
 
 	// Register/unregister channels
 	register   chan *Client
 	unregister chan *Client
+	subscribe  chan *subscription
 
 	// Broadcast to a specific member
 	broadcast chan *targetedEvent
 
+	// limiters 는 토픽별 전송 한도다 (Run 고루틴 전용)
+	limiters map[string]*topicLimiter
+	access   TopicAccess
+	presence *presence
+	// replay 면 발행하는 이벤트를 짧은 Redis 스트림에도 남긴다 (replay.go)
+	replay bool
+
 	mu          sync.RWMutex
 	redisClient *redis.Client
 	ctx         context.Context
@@ -37,20 +61,43 @@ type Hub struct {
 type targetedEvent struct {
 	MemberID string
 	Event    *Event
+	Client   *Client // 설정되면 그 연결에만 보낸다 (재접속 시 놓친 알림)
+	Topic    string  // 설정되면 그 토픽 구독자에게 보낸다
+	Actor    string  // 토픽 이벤트를 만든 회원 — 그를 차단한 구독자는 받지 않는다
+	// Limited 가 false 면 토픽 한도를 지나지 않는다 (구독 응답 등)
+	Limited bool
+}
+
+// subscription is a (un)subscribe request from a client's read loop
+type subscription struct {
+	client  *Client
+	topic   string
+	remove  bool
+	blocked []string
 }
 
 // NewHub creates a new Hub
 func NewHub(redisClient *redis.Client) *Hub {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &Hub{
+	h := &Hub{
 		clients:     make(map[string]map[*Client]bool),
+		topics:      make(map[string]map[*Client]bool),
 		register:    make(chan *Client),
 		unregister:  make(chan *Client),
+		subscribe:   make(chan *subscription, 64),
 		broadcast:   make(chan *targetedEvent, 256),
+		limiters:    make(map[string]*topicLimiter),
 		redisClient: redisClient,
 		ctx:         ctx,
 		cancel:      cancel,
 	}
+	h.presence = newPresence(h, redisClient)
+	return h
+}
+
+// SetTopicAccess enables topic subscriptions. 설정하지 않으면 구독 요청은 모두 거절된다.
+func (h *Hub) SetTopicAccess(access TopicAccess) {
+	h.access = access
 }
 
 // Register adds a client to the hub
@@ -64,7 +111,10 @@ func (h *Hub) Run() {
 	if h.redisClient != nil {
 		go h.subscribeRedis()
 	}
+	go h.presence.run()
 
+	ticker := time.NewTicker(topicTick)
+	defer ticker.Stop()
 	for {
 		select {
 		case client := <-h.register:
@@ -77,33 +127,29 @@ func (h *Hub) Run() {
 
 		case client := <-h.unregister:
 			h.mu.Lock()
-			if clients, ok := h.clients[client.memberID]; ok {
-				if _, ok := clients[client]; ok {
-					delete(clients, client)
-					close(client.send)
-					if len(clients) == 0 {
-						delete(h.clients, client.memberID)
-					}
-				}
-			}
+			h.remove(client)
+			h.mu.Unlock()
+
+		case sub := <-h.subscribe:
+			h.mu.Lock()
+			h.applySubscription(sub)
 			h.mu.Unlock()
 
 		case msg := <-h.broadcast:
-			h.mu.RLock()
-			if clients, ok := h.clients[msg.MemberID]; ok {
-				data, err := json.Marshal(msg.Event)
-				if err == nil {
-					for client := range clients {
-						select {
-						case client.send <- data:
-						default:
-							close(client.send)
-							delete(clients, client)
-						}
-					}
+			if msg.Topic != "" && msg.Limited && !h.admit(msg, time.Now()) {
+				continue
+			}
+			h.send(msg)
+
+		case now := <-ticker.C:
+			for topic, l := range h.limiters {
+				for _, msg := range l.release(now) {
+					h.send(msg)
+				}
+				if l.idle(now) {
+					delete(h.limiters, topic)
 				}
 			}
-			h.mu.RUnlock()
 
 		case <-h.ctx.Done():
 			return
@@ -111,27 +157,229 @@ func (h *Hub) Run() {
 	}
 }
 
-// SendToMember sends an event to a specific member (local + Redis publish)
+// admit applies the topic's rate limit: false 면 미뤄 뒀거나(종류별 최신 것만) 버렸다 (Run 고루틴 전용)
+func (h *Hub) admit(msg *targetedEvent, now time.Time) bool {
+	l := h.limiters[msg.Topic]
+	if l == nil {
+		l = newTopicLimiter()
+		h.limiters[msg.Topic] = l
+	}
+	if !l.holding(msg.Event.Type) && l.allow(now) {
+		return true
+	}
+	// 입력 중 표시는 지나가는 신호라 미루지 않고 버린다 — 합치면 보낸 사람(actor)을 잃는다
+	if msg.Event.Type != "typing" {
+		l.hold(msg)
+	}
+	return false
+}
+
+func (h *Hub) send(msg *targetedEvent) {
+	data, err := json.Marshal(msg.Event)
+	if err != nil {
+		return
+	}
+	// 느린 연결은 끊는다(remove) — 맵을 고치므로 쓰기 잠금이다
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case msg.Client != nil:
+		if h.clients[msg.Client.memberID][msg.Client] {
+			h.deliver(msg.Client, data)
+		}
+	case msg.Topic != "":
+		for client := range h.topics[msg.Topic] {
+			if msg.Actor != "" && (client.blocked[msg.Actor] || (msg.Event.Type == "typing" && client.memberID == msg.Actor)) {
+				continue
+			}
+			h.deliver(client, data)
+		}
+	case msg.MemberID == allMembers:
+		for _, clients := range h.clients {
+			for client := range clients {
+				h.deliver(client, data)
+			}
+		}
+	default:
+		for client := range h.clients[msg.MemberID] {
+			h.deliver(client, data)
+		}
+	}
+}
+
+// applySubscription adds or removes one topic of a client (h.mu 를 쥔 채로 부른다)
+func (h *Hub) applySubscription(sub *subscription) {
+	client := sub.client
+	if !h.clients[client.memberID][client] {
+		return // 그새 끊겼다
+	}
+	if sub.remove {
+		if client.topics[sub.topic] {
+			h.leave(client, sub.topic)
+			h.presence.touch(sub.topic)
+		}
+		return
+	}
+	if !client.topics[sub.topic] && len(client.topics) >= maxTopicsPerClient {
+		h.deliver(client, mustMarshal(&Event{Topic: sub.topic, Type: "error", Payload: map[string]string{"message": ErrTopicLimit.Error()}}))
+		return
+	}
+	client.blocked = make(map[string]bool, len(sub.blocked))
+	for _, id := range sub.blocked {
+		client.blocked[id] = true
+	}
+	if h.topics[sub.topic] == nil {
+		h.topics[sub.topic] = make(map[*Client]bool)
+	}
+	h.topics[sub.topic][client] = true
+	client.topics[sub.topic] = true
+	h.deliver(client, mustMarshal(&Event{Topic: sub.topic, Type: "subscribed", Payload: map[string]string{}}))
+	h.presence.touch(sub.topic)
+}
+
+// leave drops a client from a topic (h.mu 를 쥔 채로 부른다)
+func (h *Hub) leave(client *Client, topic string) {
+	delete(client.topics, topic)
+	if subs := h.topics[topic]; subs != nil {
+		delete(subs, client)
+		if len(subs) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+}
+
+// deliver queues data for a client, dropping the client when its buffer is full (h.mu 를 쥔 채로 부른다)
+func (h *Hub) deliver(client *Client, data []byte) {
+	select {
+	case client.send <- data:
+	default:
+		h.remove(client)
+	}
+}
+
+// remove unregisters a client and closes its send channel once (h.mu 를 쥔 채로 부른다)
+func (h *Hub) remove(client *Client) {
+	clients, ok := h.clients[client.memberID]
+	if !ok || !clients[client] {
+		return
+	}
+	for topic := range client.topics {
+		h.leave(client, topic)
+		h.presence.touch(topic)
+	}
+	delete(clients, client)
+	close(client.send)
+	if len(clients) == 0 {
+		delete(h.clients, client.memberID)
+	}
+}
+
+// SendToMember sends an event to a specific member on every instance.
+//
+// Redis 가 있으면 발행만 한다 — 이 인스턴스도 자기 구독으로 받아 로컬 연결에 전달한다.
+// 예전처럼 로컬 전달 + 발행을 같이 하면 같은 인스턴스의 연결이 두 번 받는다.
+// 발행이 실패하거나 Redis 가 없으면 로컬 연결에만 전달한다.
 func (h *Hub) SendToMember(memberID string, event *Event) {
-	// Local broadcast
+	if h.publish(&redisMessage{MemberID: memberID, Event: event}) {
+		return
+	}
 	h.broadcast <- &targetedEvent{MemberID: memberID, Event: event}
+}
 
-	// Publish to Redis for multi-instance support
-	if h.redisClient != nil {
-		msg := &redisMessage{MemberID: memberID, Event: event}
-		data, err := json.Marshal(msg)
-		if err == nil {
-			h.redisClient.Publish(h.ctx, redisPubSubChannel, data) //nolint:errcheck
+// SendToAll sends an event to every connected member on every instance (전 회원 방송)
+func (h *Hub) SendToAll(event *Event) {
+	h.SendToMember(allMembers, event)
+}
+
+// SendLocal sends events to the member's connections on this instance only.
+// 다른 인스턴스가 같은 이벤트를 따로 만들어 보내는 경우(알림 워커가 인스턴스마다 로컬 연결만 맡는다)에 쓴다.
+func (h *Hub) SendLocal(memberID string, events ...*Event) {
+	for _, event := range events {
+		h.broadcast <- &targetedEvent{MemberID: memberID, Event: event}
+	}
+}
+
+// SendToClient sends events to one connection only — 다른 인스턴스·같은 회원의 다른 연결에는 가지 않는다
+func (h *Hub) SendToClient(client *Client, events ...*Event) {
+	for _, event := range events {
+		h.broadcast <- &targetedEvent{MemberID: client.memberID, Event: event, Client: client}
+	}
+}
+
+// PublishTopic sends an event to the topic's subscribers on every instance (TopicPublisher).
+// 요청 경로에서 불린다 — 구독자가 없어도 발행 한 번이 전부다.
+func (h *Hub) PublishTopic(topic string, event *Event, actor string) {
+	event.Topic = topic
+	if h.publish(&redisMessage{Topic: topic, Actor: actor, Event: event}) {
+		return
+	}
+	h.broadcast <- &targetedEvent{Topic: topic, Actor: actor, Event: event, Limited: true}
+}
+
+// Online reports whether the member has a connection on this instance
+func (h *Hub) Online(memberID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients[memberID]) > 0
+}
+
+// subscribed reports whether the client currently follows the topic
+func (h *Hub) subscribed(client *Client, topic string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return client.topics[topic]
+}
+
+// topicMembers returns the distinct members subscribed to each given topic on this instance
+func (h *Hub) topicMembers(topics []string) map[string][]string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	out := make(map[string][]string, len(topics))
+	for _, topic := range topics {
+		seen := make(map[string]bool)
+		for client := range h.topics[topic] {
+			if !seen[client.memberID] {
+				seen[client.memberID] = true
+				out[topic] = append(out[topic], client.memberID)
+			}
 		}
 	}
+	return out
+}
+
+// localTopics returns every topic with a subscriber on this instance
+func (h *Hub) localTopics() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	topics := make([]string, 0, len(h.topics))
+	for topic := range h.topics {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+func (h *Hub) publish(msg *redisMessage) bool {
+	if h.redisClient == nil {
+		return false
+	}
+	if h.replay {
+		h.record(msg)
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return false
+	}
+	return h.redisClient.Publish(h.ctx, redisPubSubChannel, data).Err() == nil
 }
 
 type redisMessage struct {
-	MemberID string `json:"member_id"`
+	MemberID string `json:"member_id,omitempty"`
+	Topic    string `json:"topic,omitempty"`
+	Actor    string `json:"actor,omitempty"`
 	Event    *Event `json:"event"`
 }
 
-// subscribeRedis listens for notifications from other instances
+// subscribeRedis listens for notifications from every instance (자기 발행분 포함)
 func (h *Hub) subscribeRedis() {
 	pubsub := h.redisClient.Subscribe(h.ctx, redisPubSubChannel)
 	defer pubsub.Close()
@@ -146,7 +394,7 @@ func (h *Hub) subscribeRedis() {
 			var rm redisMessage
 			if err := json.Unmarshal([]byte(msg.Payload), &rm); err == nil {
 				// Only local broadcast (don't re-publish to Redis)
-				h.broadcast <- &targetedEvent{MemberID: rm.MemberID, Event: rm.Event}
+				h.broadcast <- &targetedEvent{MemberID: rm.MemberID, Topic: rm.Topic, Actor: rm.Actor, Event: rm.Event, Limited: rm.Topic != ""}
 			}
 		case <-h.ctx.Done():
 			return
@@ -158,3 +406,8 @@ func (h *Hub) subscribeRedis() {
 func (h *Hub) Stop() {
 	h.cancel()
 }
+
+func mustMarshal(event *Event) []byte {
+	data, _ := json.Marshal(event)
+	return data
+}
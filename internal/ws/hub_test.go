@@ -0,0 +1,224 @@
+package ws
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func recv(t *testing.T, c *Client) *Event {
+	t.Helper()
+	select {
+	case data := <-c.send:
+		var e Event
+		if err := json.Unmarshal(data, &e); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return &e
+	case <-time.After(2 * presenceTick):
+		t.Fatal("no event delivered")
+		return nil
+	}
+}
+
+func assertQuiet(t *testing.T, c *Client) {
+	t.Helper()
+	select {
+	case data := <-c.send:
+		t.Fatalf("unexpected extra event %s", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHubDeliversOnce(t *testing.T) {
+	hub := NewHub(nil)
+	go hub.Run()
+	defer hub.Stop()
+
+	zoe, zoe2, kim := NewClient(hub, nil, Viewer{MemberID: "zoe"}), NewClient(hub, nil, Viewer{MemberID: "zoe"}), NewClient(hub, nil, Viewer{MemberID: "kim"})
+	hub.Register(zoe)
+	hub.Register(zoe2)
+	hub.Register(kim)
+	if !hub.Online("zoe") || hub.Online("lee") {
+		t.Fatal("unexpected presence")
+	}
+
+	// Redis 없이: 회원의 모든 연결에 한 번씩
+	hub.SendToMember("zoe", &Event{ID: "7", Type: "notification"})
+	for _, c := range []*Client{zoe, zoe2} {
+		if e := recv(t, c); e.ID != "7" {
+			t.Fatalf("unexpected event %+v", e)
+		}
+		assertQuiet(t, c)
+	}
+	assertQuiet(t, kim)
+
+	// 재접속 재전송은 그 연결에만
+	hub.SendToClient(zoe2, &Event{Type: "unread_count"})
+	if e := recv(t, zoe2); e.Type != "unread_count" {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	assertQuiet(t, zoe)
+
+	hub.SendToAll(&Event{Type: "notification"})
+	for _, c := range []*Client{zoe, zoe2, kim} {
+		recv(t, c)
+	}
+}
+
+func TestParseTopic(t *testing.T) {
+	valid := map[string]Topic{
+		"post:free:12":     {Kind: TopicPost, Board: "free", WrID: 12},
+		"board:free":       {Kind: TopicBoard, Board: "free"},
+		"giving:7":         {Kind: TopicGiving, Board: "giving", WrID: 7},
+		PostTopic("qa", 3): {Kind: TopicPost, Board: "qa", WrID: 3},
+	}
+	for name, want := range valid {
+		if got, err := ParseTopic(name); err != nil || got != want {
+			t.Fatalf("ParseTopic(%q) = %+v, %v", name, got, err)
+		}
+	}
+	for _, name := range []string{"", "post:free", "post:free:0", "post:free;drop:1", "board:", "giving:x", "room:1", "board:free:1"} {
+		if _, err := ParseTopic(name); err == nil {
+			t.Fatalf("ParseTopic(%q) should fail", name)
+		}
+	}
+}
+
+func TestTopicLimiterCoalesces(t *testing.T) {
+	l := newTopicLimiter()
+	now := time.Now()
+	for i := 0; i < int(topicBurst); i++ {
+		if !l.allow(now) {
+			t.Fatalf("burst exhausted early at %d", i)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		l.hold(&targetedEvent{Topic: "t", Actor: "bob", Event: &Event{Type: "reaction_count", Payload: i}})
+	}
+	l.hold(&targetedEvent{Topic: "t", Actor: "bob", Event: &Event{Type: "comment_created"}})
+	if !l.holding("reaction_count") || l.holding("typing") {
+		t.Fatal("unexpected holding state")
+	}
+	if out := l.release(now); len(out) != 0 {
+		t.Fatalf("empty bucket must not release, got %d", len(out))
+	}
+
+	out := l.release(now.Add(time.Second))
+	if len(out) != 2 || out[0].Event.Type != "reaction_count" || out[0].Event.Payload != 4 || out[1].Event.Type != "comment_created" {
+		t.Fatalf("expected latest of each type in order, got %+v", out)
+	}
+	if out[0].Actor != "" {
+		t.Fatal("coalesced events must drop the actor")
+	}
+	if l.idle(now.Add(time.Second)) || !l.idle(now.Add(time.Minute)) {
+		t.Fatal("limiter should become idle once refilled")
+	}
+}
+
+func TestHubTopicSkipsBlockedActors(t *testing.T) {
+	hub := NewHub(nil)
+	go hub.Run()
+	defer hub.Stop()
+
+	topic := PostTopic("free", 1)
+	zoe, kim, bob := NewClient(hub, nil, Viewer{MemberID: "zoe"}), NewClient(hub, nil, Viewer{MemberID: "kim"}), NewClient(hub, nil, Viewer{MemberID: "bob"})
+	for _, c := range []*Client{zoe, kim, bob} {
+		hub.Register(c)
+	}
+	hub.subscribe <- &subscription{client: zoe, topic: topic, blocked: []string{"bob"}}
+	hub.subscribe <- &subscription{client: kim, topic: topic}
+	hub.subscribe <- &subscription{client: bob, topic: topic}
+	for _, c := range []*Client{zoe, kim, bob} {
+		if e := recv(t, c); e.Type != "subscribed" || e.Topic != topic {
+			t.Fatalf("unexpected event %+v", e)
+		}
+		// 구독이 틱을 나눠 들어가면 1·2 를 거쳐 3 이 된다
+		for {
+			e := recv(t, c)
+			if e.Type != "presence" {
+				t.Fatalf("expected presence, got %+v", e)
+			}
+			if e.Payload.(map[string]interface{})["count"] == float64(3) {
+				break
+			}
+		}
+	}
+
+	hub.PublishTopic(topic, &Event{Type: "comment_created"}, "bob")
+	if e := recv(t, kim); e.Type != "comment_created" || e.Topic != topic {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	recv(t, bob)
+	assertQuiet(t, zoe)
+
+	// 입력 중 표시는 본인에게 돌아가지 않는다
+	hub.PublishTopic(topic, &Event{Type: "typing"}, "kim")
+	recv(t, bob)
+	assertQuiet(t, kim)
+	recv(t, zoe)
+
+	hub.subscribe <- &subscription{client: kim, topic: topic, remove: true}
+	hub.PublishTopic(topic, &Event{Type: "reaction_count"}, "")
+	recv(t, zoe)
+	assertQuiet(t, kim)
+}
+
+// pipeWriter is an http.ResponseWriter whose body is read line by line by the test
+type pipeWriter struct {
+	header http.Header
+	*io.PipeWriter
+}
+
+func (w pipeWriter) Header() http.Header { return w.header }
+func (w pipeWriter) WriteHeader(int)     {}
+func (w pipeWriter) Flush()              {}
+
+func TestServeSSE(t *testing.T) {
+	hub := NewHub(nil)
+	go hub.Run()
+	defer hub.Stop()
+
+	zoe := NewClient(hub, nil, Viewer{MemberID: "zoe"})
+	hub.Register(zoe)
+	pr, pw := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		zoe.ServeSSE(ctx, pipeWriter{header: http.Header{}, PipeWriter: pw}, ParseSSECursor("1700000000000-4_7"))
+		pw.Close()
+		close(done)
+	}()
+
+	hub.SendToClient(zoe,
+		&Event{ID: "9", Type: "notification"},
+		&Event{Topic: "board:free", Type: "post_created", Seq: "1700000000000-12"},
+		&Event{Topic: "board:free", Type: "post_created", Seq: "1700000000000-5"}, // 재생·실시간이 겹쳐 늦게 온 것
+	)
+	lines := bufio.NewScanner(pr)
+	var got []string
+	for len(got) < 9 && lines.Scan() {
+		got = append(got, lines.Text())
+	}
+	cancel()
+	go io.Copy(io.Discard, pr) //nolint:errcheck
+	<-done
+
+	want := []string{
+		"retry: 3000", "",
+		"id: 1700000000000-4_9", `data: {"id":"9","type":"notification","payload":null}`, "",
+		"id: 1700000000000-12_9", `data: {"topic":"board:free","seq":"1700000000000-12","type":"post_created","payload":null}`, "",
+		"id: 1700000000000-12_9", // 커서는 뒤로 가지 않는다
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected stream:\n%s", strings.Join(got, "\n"))
+	}
+	if c := ParseSSECursor("garbage_x"); c != (SSECursor{}) {
+		t.Fatalf("invalid Last-Event-ID should start fresh, got %+v", c)
+	}
+}
@@ -0,0 +1,157 @@
+package ws
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 읽는 사람 수(presence) — 토픽을 구독 중인 회원 수. 같은 회원의 여러 탭은 한 명이다.
+//
+// Redis 가 있으면 토픽마다 ZSET(ws:presence:{topic}, 회원 → 만료 시각)에 인스턴스들이 자기 구독자를
+// 주기적으로 써 넣고, 만료된 회원을 지운 뒤 센다. 각 인스턴스는 그 수를 자기 구독자에게만 보낸다
+// (발행하지 않는다 — 인스턴스마다 같은 수를 보내면 중복이다). Redis 가 없으면 로컬 구독자 수다.
+const (
+	presenceKeyPrefix = "ws:presence:"
+	presenceTick      = time.Second
+	// presenceHeartbeat 마다 로컬 토픽 전부를 다시 쓴다. 다른 인스턴스에서 생긴 변화도 이때 반영된다.
+	presenceHeartbeat = 20 * time.Second
+	presenceTTL       = 3 * presenceHeartbeat
+)
+
+type presence struct {
+	hub   *Hub
+	redis *redis.Client
+	dirty chan string
+
+	// 아래는 run 고루틴 전용
+	known  map[string]map[string]bool // 토픽 → 마지막으로 써 넣은 로컬 회원
+	counts map[string]int64
+}
+
+func newPresence(hub *Hub, redisClient *redis.Client) *presence {
+	return &presence{
+		hub:    hub,
+		redis:  redisClient,
+		dirty:  make(chan string, 1024),
+		known:  make(map[string]map[string]bool),
+		counts: make(map[string]int64),
+	}
+}
+
+// touch marks a topic whose local subscribers changed (h.mu 를 쥔 채로 불려도 막히지 않는다 — 넘치면 하트비트가 맞춘다)
+func (p *presence) touch(topic string) {
+	select {
+	case p.dirty <- topic:
+	default:
+	}
+}
+
+func (p *presence) run() {
+	tick := time.NewTicker(presenceTick)
+	defer tick.Stop()
+	heartbeat := time.NewTicker(presenceHeartbeat)
+	defer heartbeat.Stop()
+
+	pending := make(map[string]bool)
+	for {
+		select {
+		case <-p.hub.ctx.Done():
+			return
+		case topic := <-p.dirty:
+			pending[topic] = true
+		case <-tick.C:
+			if len(pending) == 0 {
+				continue
+			}
+			topics := make([]string, 0, len(pending))
+			for topic := range pending {
+				topics = append(topics, topic)
+			}
+			pending = make(map[string]bool)
+			p.refresh(topics, true)
+		case <-heartbeat.C:
+			topics := p.hub.localTopics()
+			for topic := range p.counts {
+				if !slices.Contains(topics, topic) {
+					topics = append(topics, topic) // 마지막 구독자가 떠난 토픽도 정리한다
+				}
+			}
+			p.refresh(topics, false)
+		}
+	}
+}
+
+// refresh writes the local members of topics and delivers counts — force 면 바뀌지 않았어도 보낸다(새 구독자용)
+func (p *presence) refresh(topics []string, force bool) {
+	members := p.hub.topicMembers(topics)
+	counts := p.count(topics, members)
+	for _, topic := range topics {
+		local := members[topic]
+		count, ok := counts[topic]
+		if len(local) == 0 {
+			delete(p.known, topic)
+			delete(p.counts, topic)
+			continue
+		}
+		if !ok || (!force && p.counts[topic] == count) {
+			continue
+		}
+		p.counts[topic] = count
+		p.hub.broadcast <- &targetedEvent{
+			Topic:   topic,
+			Event:   &Event{Topic: topic, Type: "presence", Payload: map[string]int64{"count": count}},
+			Limited: true,
+		}
+	}
+}
+
+// count returns the reader count of each topic, writing this instance's members first
+func (p *presence) count(topics []string, members map[string][]string) map[string]int64 {
+	counts := make(map[string]int64, len(topics))
+	if p.redis == nil {
+		for _, topic := range topics {
+			counts[topic] = int64(len(members[topic]))
+		}
+		return counts
+	}
+
+	ctx, cancel := context.WithTimeout(p.hub.ctx, 2*time.Second)
+	defer cancel()
+	now := time.Now()
+	expires := float64(now.Add(presenceTTL).Unix())
+	cards := make(map[string]*redis.IntCmd, len(topics))
+	_, err := p.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, topic := range topics {
+			key := presenceKeyPrefix + topic
+			current := make(map[string]bool, len(members[topic]))
+			for _, id := range members[topic] {
+				current[id] = true
+				pipe.ZAdd(ctx, key, redis.Z{Score: expires, Member: id})
+			}
+			// 이 인스턴스에서 떠난 회원 — 다른 인스턴스에 남아 있으면 그쪽 하트비트가 되살린다
+			for id := range p.known[topic] {
+				if !current[id] {
+					pipe.ZRem(ctx, key, id)
+				}
+			}
+			p.known[topic] = current
+			pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(now.Unix(), 10))
+			pipe.Expire(ctx, key, presenceTTL)
+			cards[topic] = pipe.ZCard(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ws] presence update failed: %v", err)
+		return counts
+	}
+	for topic, cmd := range cards {
+		counts[topic] = cmd.Val()
+	}
+	return counts
+}
@@ -0,0 +1,150 @@
+package ws
+
+import (
+	"cmp"
+	"encoding/json"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 재생(replay) — 끊겼다 다시 붙은 연결(SSE Last-Event-ID)이 놓친 이벤트를 받는 짧은 Redis 스트림.
+//
+// 발행하는 이벤트(회원·전체 방송·토픽)를 받는 쪽 키마다 스트림에 XADD 하고, 그 스트림 id 를 Event.Seq 로
+// 싣는다. 스트림 id 는 Redis 시계의 밀리초라 키가 달라도 앞뒤를 비교할 수 있다 — 재접속한 연결은 자기
+// 키들을 Seq 이후로 읽어 합친다. 인스턴스마다 따로 만드는 이벤트(알림 워커의 SendLocal)는 여기 없다 —
+// 알림은 ph_id 로 DB 에서 다시 읽는다(handler.WSResumer).
+const (
+	replayKeyPrefix = "ws:replay:"
+	// replayMaxLen 을 넘게 놓쳤거나 replayWindow 보다 오래 끊겨 있었으면 resync 다
+	replayMaxLen = 100
+	replayWindow = 5 * time.Minute
+)
+
+// EnableReplay records published events for Client.Replay (Redis 가 없으면 아무 일도 하지 않는다).
+// 요청을 받기 전에 부른다.
+func (h *Hub) EnableReplay() {
+	h.replay = h.redisClient != nil
+}
+
+func replayKey(memberID, topic string) string {
+	switch {
+	case topic != "":
+		return replayKeyPrefix + "t:" + topic
+	case memberID == allMembers:
+		return replayKeyPrefix + "all"
+	default:
+		return replayKeyPrefix + "m:" + memberID
+	}
+}
+
+// record appends msg to its replay stream and stamps the stream id on the event (실패하면 Seq 없이 보낸다)
+func (h *Hub) record(msg *redisMessage) {
+	if msg.Event.Type == "typing" {
+		return // 지나가는 신호 — 다시 보낼 일이 없다
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	key := replayKey(msg.MemberID, msg.Topic)
+	var add *redis.StringCmd
+	_, err = h.redisClient.Pipelined(h.ctx, func(pipe redis.Pipeliner) error {
+		add = pipe.XAdd(h.ctx, &redis.XAddArgs{
+			Stream: key,
+			MaxLen: replayMaxLen,
+			Approx: true,
+			Values: map[string]interface{}{"m": data},
+		})
+		pipe.Expire(h.ctx, key, replayWindow)
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ws] replay record failed: %v", err)
+		return
+	}
+	msg.Event.Seq = add.Val()
+}
+
+// Replay returns what the client missed after seq: 회원·전체 방송 이벤트와 topics 의 이벤트를 Seq 순으로.
+// ok 가 false 면 재생 범위를 넘게 놓쳤다 — 호출자는 resync 를 보내 화면을 다시 읽게 한다.
+// 구독자가 차단한 회원이 만든 토픽 이벤트는 뺀다(Hub.send 와 같은 규칙).
+func (c *Client) Replay(seq string, topics []string) (events []*Event, ok bool) {
+	h := c.hub
+	if seq == "" {
+		return nil, true
+	}
+	if !h.replay {
+		return nil, false
+	}
+	ms, _, valid := parseSeq(seq)
+	if !valid || time.Since(time.UnixMilli(int64(ms))) > replayWindow {
+		return nil, false
+	}
+
+	keys := []string{replayKey(c.memberID, ""), replayKey(allMembers, "")}
+	for _, topic := range topics {
+		keys = append(keys, replayKey("", topic))
+	}
+	cmds := make([]*redis.XMessageSliceCmd, len(keys))
+	_, err := h.redisClient.Pipelined(h.ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.XRangeN(h.ctx, key, "("+seq, "+", replayMaxLen+1)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ws] replay read failed for %s: %v", c.memberID, err)
+		return nil, false
+	}
+
+	var blocked []string
+	if h.access != nil && len(topics) > 0 {
+		blocked = h.access.ContentBlocked(c.memberID)
+	}
+	for _, cmd := range cmds {
+		entries := cmd.Val()
+		if len(entries) > replayMaxLen {
+			return nil, false
+		}
+		for _, entry := range entries {
+			raw, _ := entry.Values["m"].(string)
+			var rm redisMessage
+			if json.Unmarshal([]byte(raw), &rm) != nil || rm.Event == nil {
+				continue
+			}
+			if rm.Actor != "" && slices.Contains(blocked, rm.Actor) {
+				continue
+			}
+			rm.Event.Seq = entry.ID
+			events = append(events, rm.Event)
+		}
+	}
+	slices.SortFunc(events, func(a, b *Event) int { return compareSeq(a.Seq, b.Seq) })
+	return events, true
+}
+
+// parseSeq splits a stream id "ms-n"
+func parseSeq(seq string) (ms, n uint64, ok bool) {
+	left, right, found := strings.Cut(seq, "-")
+	if !found {
+		return 0, 0, false
+	}
+	ms, err1 := strconv.ParseUint(left, 10, 64)
+	n, err2 := strconv.ParseUint(right, 10, 64)
+	return ms, n, err1 == nil && err2 == nil
+}
+
+// compareSeq orders stream ids (문자열 비교는 자릿수가 다르면 틀린다)
+func compareSeq(a, b string) int {
+	am, an, _ := parseSeq(a)
+	bm, bn, _ := parseSeq(b)
+	if c := cmp.Compare(am, bm); c != 0 {
+		return c
+	}
+	return cmp.Compare(an, bn)
+}
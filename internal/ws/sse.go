@@ -0,0 +1,104 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSE(text/event-stream) — WebSocket 업그레이드가 막힌 망(사내 프록시·일부 인앱 브라우저)용 대체 통로.
+//
+// 같은 Hub 의 Client 다(conn 없음): WebSocket 과 같은 이벤트가 같은 봉투(Event JSON)로 data: 줄에 실린다.
+// event: 필드는 쓰지 않는다 — 클라이언트는 onmessage 하나로 WebSocket 과 같은 분기를 탄다.
+// 단방향이라 구독은 접속 URL 로만 하고(SubscribeAll) typing 은 보낼 수 없다.
+const (
+	sseHeartbeat = 20 * time.Second
+	sseRetry     = 3 * time.Second
+)
+
+// SSECursor is the reconnect cursor carried in the SSE id: field — "<seq>_<ph_id>".
+// Seq 는 재생 스트림(replay.go) 위치, NotiID 는 마지막 notification 이벤트의 ph_id 다(알림은 DB 에서 다시 읽는다).
+type SSECursor struct {
+	Seq    string
+	NotiID int
+}
+
+// ParseSSECursor reads a Last-Event-ID (잘못된 값은 빈 커서 — 재생 없이 새로 시작한다)
+func ParseSSECursor(s string) SSECursor {
+	seq, noti, _ := strings.Cut(s, "_")
+	cursor := SSECursor{}
+	if _, _, ok := parseSeq(seq); ok {
+		cursor.Seq = seq
+	}
+	if id, err := strconv.Atoi(noti); err == nil && id > 0 {
+		cursor.NotiID = id
+	}
+	return cursor
+}
+
+func (c SSECursor) String() string {
+	return c.Seq + "_" + strconv.Itoa(c.NotiID)
+}
+
+// advance moves the cursor past one delivered event
+func (c *SSECursor) advance(data []byte) {
+	var e struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Seq  string `json:"seq"`
+	}
+	if json.Unmarshal(data, &e) != nil {
+		return
+	}
+	// 재생·실시간이 겹치면 순서가 섞여 올 수 있다 — 뒤로 가지 않는다
+	if e.Seq != "" && (c.Seq == "" || compareSeq(e.Seq, c.Seq) > 0) {
+		c.Seq = e.Seq
+	}
+	if e.Type == "notification" {
+		if id, err := strconv.Atoi(e.ID); err == nil && id > c.NotiID {
+			c.NotiID = id
+		}
+	}
+}
+
+// ServeSSE writes the client's events as text/event-stream until ctx ends or the hub drops the client.
+// 호출자가 응답 헤더를 쓰고 Register 한 뒤 부른다. 돌아올 때 Hub 에서 뺀다.
+func (c *Client) ServeSSE(ctx context.Context, w http.ResponseWriter, cursor SSECursor) {
+	defer func() {
+		c.hub.unregister <- c
+	}()
+
+	rc := http.NewResponseController(w)
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	if _, err := io.WriteString(w, "retry: "+strconv.FormatInt(sseRetry.Milliseconds(), 10)+"\n\n"); err != nil {
+		return
+	}
+	for {
+		if rc.Flush() != nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-c.send:
+			if !ok {
+				return // 느린 연결이라 Hub 가 끊었다 — EventSource 가 Last-Event-ID 로 다시 붙는다
+			}
+			cursor.advance(message)
+			if _, err := io.WriteString(w, "id: "+cursor.String()+"\ndata: "+string(message)+"\n\n"); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			// 주석 줄 — 프록시의 유휴 연결 종료를 막는다. EventSource 는 무시한다
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package ws
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 토픽 이름 — 페이지 단위 실시간 스트림
+//
+//	post:{board}:{wr_id}  글 상세 (새 댓글·리액션 수·입력 중·읽는 사람 수)
+//	board:{slug}          게시판 목록 (새 글)
+//	giving:{wr_id}        나눔 상세 (응모 수·개표 결과)
+//
+// 이벤트는 "무엇이 바뀌었나" 힌트만 싣는다(댓글 본문 등은 없다). 클라이언트는 받은 뒤 REST 로
+// 다시 읽으므로 비밀 댓글·차단 숨김 같은 항목별 권한은 REST 가 그대로 적용한다.
+const (
+	TopicPost   = "post"
+	TopicBoard  = "board"
+	TopicGiving = "giving"
+)
+
+// 토픽 한도
+const (
+	maxTopicsPerClient = 20
+	// topicRate·topicBurst 는 한 토픽에 한 인스턴스가 내보내는 초당 이벤트 수다. 넘치면 종류별 최신 것만
+	// 남겨 다음 틱에 보낸다 — 인기 글의 리액션 폭주가 구독자 수만큼 곱해지지 않도록.
+	topicRate  = 5.0
+	topicBurst = 10.0
+	// typingEvery 는 한 연결이 한 토픽에 typing 을 보낼 수 있는 최소 간격이다
+	typingEvery = 3 * time.Second
+)
+
+var (
+	// ErrTopicInvalid is returned for malformed topic names
+	ErrTopicInvalid = errors.New("잘못된 토픽입니다")
+	// ErrTopicForbidden is returned when the member may not read the topic
+	ErrTopicForbidden = errors.New("읽을 수 없는 토픽입니다")
+	// ErrTopicLimit is returned past maxTopicsPerClient
+	ErrTopicLimit = errors.New("구독할 수 있는 토픽 수를 넘었습니다")
+
+	topicSlugRe = regexp.MustCompile(`^[a-zA-Z0-9_]{1,20}$`)
+)
+
+// Topic is a parsed topic name
+type Topic struct {
+	Kind  string
+	Board string // post·board: 게시판 slug, giving: "giving"
+	WrID  int    // post·giving
+}
+
+// PostTopic returns the topic of a post page
+func PostTopic(board string, wrID int) string {
+	return TopicPost + ":" + board + ":" + strconv.Itoa(wrID)
+}
+
+// BoardTopic returns the topic of a board list
+func BoardTopic(board string) string {
+	return TopicBoard + ":" + board
+}
+
+// GivingTopic returns the topic of a giving (나눔) page
+func GivingTopic(wrID int) string {
+	return TopicGiving + ":" + strconv.Itoa(wrID)
+}
+
+// ParseTopic validates a topic name
+func ParseTopic(name string) (Topic, error) {
+	parts := strings.Split(name, ":")
+	switch {
+	case len(parts) == 3 && parts[0] == TopicPost:
+		id, err := strconv.Atoi(parts[2])
+		if err != nil || id <= 0 || !topicSlugRe.MatchString(parts[1]) {
+			return Topic{}, ErrTopicInvalid
+		}
+		return Topic{Kind: TopicPost, Board: parts[1], WrID: id}, nil
+	case len(parts) == 2 && parts[0] == TopicBoard:
+		if !topicSlugRe.MatchString(parts[1]) {
+			return Topic{}, ErrTopicInvalid
+		}
+		return Topic{Kind: TopicBoard, Board: parts[1]}, nil
+	case len(parts) == 2 && parts[0] == TopicGiving:
+		id, err := strconv.Atoi(parts[1])
+		if err != nil || id <= 0 {
+			return Topic{}, ErrTopicInvalid
+		}
+		return Topic{Kind: TopicGiving, Board: "giving", WrID: id}, nil
+	}
+	return Topic{}, ErrTopicInvalid
+}
+
+// Viewer is the member behind a connection
+type Viewer struct {
+	MemberID string
+	Level    int
+	Nick     string
+}
+
+// TopicAccess decides who may subscribe to what (REST 조회 권한과 같은 규칙 — handler.WSTopicAccess).
+type TopicAccess interface {
+	// Authorize returns nil when the viewer may read the topic: ErrTopicForbidden 또는 조회 실패
+	Authorize(viewer Viewer, topic Topic) error
+	// ContentBlocked returns the members whose content the viewer hides (글/댓글 차단) —
+	// 그 회원이 만든 토픽 이벤트(댓글·입력 중)는 이 연결에 보내지 않는다.
+	ContentBlocked(memberID string) []string
+}
+
+// TopicPublisher publishes topic events (Hub). actor 는 이벤트를 만든 회원이다 — 그를 차단한 구독자는 받지 않는다.
+type TopicPublisher interface {
+	PublishTopic(topic string, event *Event, actor string)
+}
+
+// bucket is a token bucket
+type bucket struct {
+	rate, burst float64
+	tokens      float64
+	last        time.Time
+}
+
+func (b *bucket) allow(now time.Time) bool {
+	if b.last.IsZero() {
+		b.tokens = b.burst
+	} else {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// topicLimiter limits one topic's events with latest-per-type coalescing (Hub.Run 전용)
+type topicLimiter struct {
+	bucket
+	deferred map[string]*targetedEvent // 이벤트 타입 → 가장 최근 것
+	order    []string
+}
+
+func newTopicLimiter() *topicLimiter {
+	return &topicLimiter{bucket: bucket{rate: topicRate, burst: topicBurst}}
+}
+
+// holding reports whether an event of this type is already waiting (새 것도 뒤에 서야 순서가 안 뒤집힌다)
+func (l *topicLimiter) holding(eventType string) bool {
+	_, ok := l.deferred[eventType]
+	return ok
+}
+
+// hold keeps only the latest event of its type. 합친 이벤트는 actor 를 지운다 —
+// 힌트일 뿐이고, 차단한 회원의 것이 마지막이어도 다른 회원의 변경 힌트를 잃지 않도록.
+func (l *topicLimiter) hold(msg *targetedEvent) {
+	if l.deferred == nil {
+		l.deferred = make(map[string]*targetedEvent)
+	}
+	if _, ok := l.deferred[msg.Event.Type]; !ok {
+		l.order = append(l.order, msg.Event.Type)
+	}
+	held := *msg
+	held.Actor = ""
+	l.deferred[msg.Event.Type] = &held
+}
+
+// release returns held events the bucket now allows
+func (l *topicLimiter) release(now time.Time) []*targetedEvent {
+	var out []*targetedEvent
+	for len(l.order) > 0 && l.allow(now) {
+		t := l.order[0]
+		l.order = l.order[1:]
+		out = append(out, l.deferred[t])
+		delete(l.deferred, t)
+	}
+	return out
+}
+
+// idle reports whether the limiter holds nothing and its bucket is full again (지워도 된다)
+func (l *topicLimiter) idle(now time.Time) bool {
+	return len(l.order) == 0 && now.Sub(l.last).Seconds()*l.rate >= l.burst
+}
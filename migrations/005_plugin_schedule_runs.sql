@@ -0,0 +1,16 @@
+-- plugin_schedule_runs: 플러그인 스케줄 작업 실행 이력
+-- 서버 기동 시 HasTable 이 false 이면 AutoMigrate 로도 생성된다 (cmd/api/main.go)
+
+CREATE TABLE IF NOT EXISTS plugin_schedule_runs (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    plugin_name VARCHAR(100) NOT NULL DEFAULT '',
+    task_name VARCHAR(100) NOT NULL DEFAULT '',
+    scheduled_at DATETIME(3) NULL COMMENT '지터 적용 전 예정 시각',
+    started_at DATETIME(3) NULL,
+    finished_at DATETIME(3) NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT '' COMMENT 'success | failed | timeout',
+    error TEXT,
+    instance VARCHAR(100) NOT NULL DEFAULT '' COMMENT '실행한 인스턴스 (hostname-pid)',
+    INDEX idx_plugin_schedule_runs_task (plugin_name, task_name, started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,16 @@
+-- 내장 cron 잡 (internal/cron) 은 플러그인 스케줄러에서 plugin_name='cron' 으로 돈다
+-- 실행 이력은 plugin_schedule_runs 를 함께 쓰고, Redis 가 없을 때의 단일 실행 락은 plugin_schedule_locks 에 둔다
+-- 서버 기동 시 컬럼·테이블이 없으면 AutoMigrate 로도 생성된다 (cmd/api/main.go)
+
+ALTER TABLE plugin_schedule_runs
+    ADD COLUMN trigger_type VARCHAR(20) NOT NULL DEFAULT '' COMMENT 'schedule | manual | http' AFTER task_name,
+    ADD COLUMN triggered_by VARCHAR(100) NOT NULL DEFAULT '' COMMENT '수동 실행한 관리자 mb_id' AFTER trigger_type,
+    ADD COLUMN dry_run TINYINT(1) NOT NULL DEFAULT 0 AFTER triggered_by,
+    ADD COLUMN result TEXT COMMENT '작업 결과 JSON' AFTER status;
+
+CREATE TABLE IF NOT EXISTS plugin_schedule_locks (
+    lock_key VARCHAR(191) NOT NULL PRIMARY KEY COMMENT 'angple:scheduler:{plugin}:{task}:{slot|running}',
+    holder VARCHAR(100) NOT NULL DEFAULT '' COMMENT '잡은 인스턴스 (hostname-pid)',
+    expires_at DATETIME(3) NOT NULL,
+    INDEX idx_plugin_schedule_locks_expires (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
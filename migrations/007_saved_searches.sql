@@ -0,0 +1,16 @@
+-- saved_searches: 회원이 저장한 고급 검색 조건과 새 글 알림 (internal/service.SavedSearchService)
+-- 서버 기동 시 AutoMigrate 로도 생성된다 (cmd/api/main.go)
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    mb_id VARCHAR(20) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    params TEXT COMMENT 'SearchParams JSON (q, board_id, author, category, tags, from, to, min_good, ...)',
+    notify TINYINT(1) NOT NULL DEFAULT 0 COMMENT '새 글 알림 (g5_na_noti)',
+    last_match_at DATETIME(3) NULL COMMENT '마지막으로 알린 글의 작성 시각',
+    last_checked_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    INDEX idx_saved_searches_mb (mb_id),
+    INDEX idx_saved_searches_notify (notify)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
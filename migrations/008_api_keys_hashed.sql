@@ -0,0 +1,32 @@
+-- api_keys: 평문 저장 → SHA-256 해시 + 식별용 prefix, 스코프·키별 한도·사용량 (internal/service.APIKeyService)
+-- 서버 기동 시 AutoMigrate + MigrateLegacyKeys 로도 적용된다 (cmd/api/main.go)
+
+ALTER TABLE api_keys
+    ADD COLUMN key_hash VARCHAR(64) NULL AFTER id,
+    ADD COLUMN key_prefix VARCHAR(16) NOT NULL DEFAULT '' AFTER key_hash,
+    ADD COLUMN rate_limit INT NOT NULL DEFAULT 60 COMMENT '분당 요청 수',
+    ADD COLUMN last_used_ip VARCHAR(45) NOT NULL DEFAULT '',
+    ADD COLUMN usage_count BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN rotated_at DATETIME(3) NULL,
+    ADD COLUMN revoked_at DATETIME(3) NULL,
+    MODIFY COLUMN api_key VARCHAR(64) NULL COMMENT '해시 도입 전 평문 (이관 후 NULL)',
+    ADD UNIQUE INDEX idx_api_keys_key_hash (key_hash);
+
+-- 기존 평문 키 이관: 해시·prefix 를 채우고 평문을 지운다. 스코프는 read/write/admin → 리소스:동작
+-- ⛔ admin → admin:* 로 옮긴 키는 2단계 인증 세션에서 발급된 적이 없다 — mfa_verified_at(010) 이 NULL 이라
+--    관리자 MFA 정책(mfa.require_for_admins)이 켜지면 2단계 세션에서 교체하기 전까지 관리자 라우트에서 막힌다
+UPDATE api_keys
+SET key_hash = SHA2(api_key, 256),
+    key_prefix = LEFT(api_key, 11),
+    scopes = TRIM(BOTH ',' FROM CONCAT_WS(',',
+        IF(FIND_IN_SET('read', scopes) > 0, 'posts:read,notifications:read', NULL),
+        IF(FIND_IN_SET('write', scopes) > 0, 'posts:write,notifications:write', NULL),
+        IF(FIND_IN_SET('admin', scopes) > 0, 'admin:*', NULL))),
+    api_key = NULL
+WHERE key_hash IS NULL AND api_key IS NOT NULL;
+
+-- 소유자를 mb_id 로 통일: 예전 발급 API 는 Bearer 경로에서 v2_users.id(숫자)를 넣었다
+UPDATE api_keys k
+JOIN v2_users u ON u.id = k.user_id
+SET k.user_id = u.username
+WHERE NOT EXISTS (SELECT 1 FROM g5_member m WHERE m.mb_id = k.user_id);
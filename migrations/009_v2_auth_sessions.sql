@@ -0,0 +1,26 @@
+-- v2_auth_sessions: 서버 세션 = 리프레시 토큰 패밀리 (internal/service/v2.SessionService)
+-- 리프레시 토큰의 sid 가 family_id, jti 가 token_id 다. 갱신마다 token_id 를 바꾸고,
+-- 옛 토큰이 다시 오면 패밀리를 폐기한다(revoke_reason = reuse_detected).
+-- 서버 기동 시 AutoMigrate 로도 생성된다 (cmd/api/main.go)
+
+CREATE TABLE IF NOT EXISTS v2_auth_sessions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    family_id VARCHAR(32) NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL COMMENT 'v2_users.id',
+    username VARCHAR(50) NOT NULL COMMENT 'g5_member.mb_id (관리자 강제 로그아웃)',
+    token_id VARCHAR(32) NOT NULL COMMENT '지금 유효한 리프레시 토큰의 jti',
+    generation INT NOT NULL DEFAULT 0,
+    device_name VARCHAR(100) NULL,
+    platform VARCHAR(16) NULL COMMENT 'web | mobile',
+    user_agent VARCHAR(255) NULL,
+    ip VARCHAR(45) NULL COMMENT '마지막으로 본 IP',
+    created_at DATETIME(3) NULL,
+    last_seen_at DATETIME(3) NULL,
+    expires_at DATETIME(3) NULL,
+    revoked_at DATETIME(3) NULL,
+    revoke_reason VARCHAR(30) NULL COMMENT 'logout | user | user_all | reuse_detected | admin_ban',
+    UNIQUE INDEX idx_v2_auth_sessions_family_id (family_id),
+    INDEX idx_v2_auth_sessions_user_id (user_id),
+    INDEX idx_v2_auth_sessions_username (username),
+    INDEX idx_v2_auth_sessions_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
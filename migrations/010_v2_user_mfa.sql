@@ -0,0 +1,29 @@
+-- v2_user_mfa / v2_mfa_recovery_codes: TOTP 2단계 인증 (internal/service/v2.MFAService)
+-- 서버 기동 시 AutoMigrate 로도 생성된다 (cmd/api/main.go)
+
+CREATE TABLE IF NOT EXISTS v2_user_mfa (
+    user_id BIGINT UNSIGNED NOT NULL PRIMARY KEY COMMENT 'v2_users.id',
+    username VARCHAR(50) NOT NULL COMMENT 'g5_member.mb_id',
+    secret VARCHAR(255) NOT NULL COMMENT 'base32 TOTP 시크릿 (MFA_SECRET_KEY 가 있으면 v1: AES-GCM 봉인본)',
+    enabled TINYINT(1) NOT NULL DEFAULT 0 COMMENT '첫 코드 확인 전에는 0',
+    enabled_at DATETIME(3) NULL,
+    last_step BIGINT NOT NULL DEFAULT 0 COMMENT '마지막으로 받은 time step — 코드 재전송 방지',
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    INDEX idx_v2_user_mfa_username (username)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS v2_mfa_recovery_codes (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    code_hash CHAR(64) NOT NULL COMMENT 'sha256(소문자, 하이픈 제거)',
+    used_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    INDEX idx_v2_mfa_recovery_codes_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- v2_auth_sessions: 2단계 인증을 거친 세션 (관리자 MFA 정책)
+ALTER TABLE v2_auth_sessions ADD COLUMN mfa_verified_at DATETIME(3) NULL AFTER revoke_reason;
+
+-- api_keys: 2단계 인증을 거친 세션에서 발급·교체된 키 (NULL 이면 정책이 켜졌을 때 관리자 라우트에서 막힌다)
+ALTER TABLE api_keys ADD COLUMN mfa_verified_at DATETIME(3) NULL AFTER rotated_at;
@@ -0,0 +1,21 @@
+-- v2_webauthn_credentials: 패스키(WebAuthn) 로그인 (internal/service/v2.WebAuthnService)
+-- 서버 기동 시 AutoMigrate 로도 생성된다 (cmd/api/main.go)
+
+CREATE TABLE IF NOT EXISTS v2_webauthn_credentials (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL COMMENT 'v2_users.id',
+    username VARCHAR(50) NOT NULL COMMENT 'g5_member.mb_id',
+    credential_id VARCHAR(255) NOT NULL COMMENT '인증기 credential id (base64url)',
+    public_key BLOB NOT NULL COMMENT 'COSE 공개키',
+    attestation_type VARCHAR(32) NULL,
+    aaguid CHAR(32) NULL COMMENT '인증기 모델 (hex)',
+    sign_count INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '서명 카운터 — 줄어들면 복제 의심으로 거절',
+    transports VARCHAR(100) NULL COMMENT '쉼표 구분 (usb,nfc,ble,internal,hybrid)',
+    backup_eligible TINYINT(1) NOT NULL DEFAULT 0,
+    backup_state TINYINT(1) NOT NULL DEFAULT 0 COMMENT '동기화 패스키',
+    name VARCHAR(100) NOT NULL COMMENT '회원이 붙인 이름',
+    created_at DATETIME(3) NULL,
+    last_used_at DATETIME(3) NULL,
+    UNIQUE INDEX idx_v2_webauthn_credentials_credential_id (credential_id),
+    INDEX idx_v2_webauthn_credentials_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,17 @@
+-- site_identity_providers: 테넌트별 OpenID Connect IdP (internal/service.ProvisioningService, OAuthService)
+-- 서버 기동 시 AutoMigrate 로도 생성된다 (cmd/api/main.go)
+
+CREATE TABLE IF NOT EXISTS site_identity_providers (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    site_id VARCHAR(64) NOT NULL COMMENT 'sites.id',
+    display_name VARCHAR(100) NOT NULL DEFAULT '' COMMENT '로그인 버튼에 보이는 이름',
+    issuer VARCHAR(255) NOT NULL COMMENT 'OIDC issuer (디스커버리 기준 URL)',
+    client_id VARCHAR(255) NOT NULL,
+    client_secret VARCHAR(512) NOT NULL DEFAULT '',
+    redirect_url VARCHAR(512) NOT NULL COMMENT '.../api/v2/auth/oauth/sso-<subdomain>/callback',
+    scopes VARCHAR(255) NOT NULL DEFAULT '' COMMENT '공백 구분, 비우면 openid email profile',
+    enabled TINYINT(1) NOT NULL DEFAULT 1,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    UNIQUE KEY idx_site_identity_providers_site_id (site_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
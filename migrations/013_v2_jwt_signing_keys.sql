@@ -0,0 +1,15 @@
+-- v2_jwt_signing_keys: JWT 비대칭 서명키 (internal/service/v2.SigningKeyService)
+-- 다음 키는 activates_at 전에 만들어 /.well-known/jwks.json 에 미리 싣고,
+-- 이전 키는 retires_at(= 다음 키 활성 + 리프레시 토큰 수명)까지 검증에만 쓴다.
+-- 서버 기동 시 AutoMigrate 로도 생성된다 (cmd/api/main.go)
+
+CREATE TABLE IF NOT EXISTS v2_jwt_signing_keys (
+    kid VARCHAR(64) NOT NULL PRIMARY KEY COMMENT 'RFC 7638 JWK thumbprint',
+    algorithm VARCHAR(10) NOT NULL COMMENT 'ES256 | EdDSA',
+    private_key TEXT NOT NULL COMMENT 'PKCS#8 — jwt.key_secret 로 AES-GCM 봉인',
+    public_key TEXT NOT NULL COMMENT 'PKIX',
+    activates_at DATETIME(3) NOT NULL,
+    retires_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    INDEX idx_v2_jwt_signing_keys_activates_at (activates_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,16 @@
+-- v2_web_push_subscriptions: 브라우저 Web Push 구독 (worker.PushNotifyWorker 의 webpush 채널)
+-- 앱 기기 토큰(v2_devices, Expo)과 별개다. 푸시 서비스가 404/410 을 주면 워커가 지운다.
+-- 서버 기동 시 AutoMigrate 로도 생성된다 (worker.PushNotifyWorker.Start)
+
+CREATE TABLE IF NOT EXISTS v2_web_push_subscriptions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    endpoint VARCHAR(512) NOT NULL COMMENT '푸시 서비스 URL — 비추측성, 보유가 소유 증명',
+    p256dh VARCHAR(128) NOT NULL COMMENT '구독 공개키 (base64url)',
+    auth VARCHAR(64) NOT NULL COMMENT '구독 인증 시크릿 (base64url)',
+    user_agent VARCHAR(255) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    UNIQUE INDEX idx_v2_web_push_subscriptions_endpoint (endpoint),
+    INDEX idx_v2_web_push_subscriptions_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,36 @@
+-- 이메일 알림 채널: 분류별 수신 설정(g5_noti_preference 옆), 발송 대기열(outbox), 수신 거부 목록
+-- 알림을 만드는 곳은 gnurepo.EnqueueEmail 로 대기열에 쌓고, cron email-outbox·email-digest-* 가 보낸다.
+-- 서버 기동 시 AutoMigrate 로도 생성된다 (cmd/api 이메일 알림 배선)
+
+CREATE TABLE IF NOT EXISTS g5_noti_email_preference (
+    mb_id VARCHAR(20) NOT NULL,
+    category VARCHAR(20) NOT NULL COMMENT 'account | point | digest',
+    frequency VARCHAR(10) NOT NULL COMMENT 'off | instant | daily | weekly — 행이 없으면 분류 기본값',
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (mb_id, category)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS g5_noti_email_queue (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    mb_id VARCHAR(20) NOT NULL,
+    category VARCHAR(20) NOT NULL,
+    template VARCHAR(40) NOT NULL COMMENT 'i18n email.<template>.subject·body',
+    args TEXT NULL COMMENT '문구의 %s 자리 값 (JSON 배열)',
+    link VARCHAR(255) NULL COMMENT '사이트 경로',
+    frequency VARCHAR(10) NOT NULL COMMENT '쌓을 때의 설정 — instant 는 한 건씩, daily·weekly 는 요약으로',
+    status VARCHAR(10) NOT NULL COMMENT 'pending | sent | failed | skipped',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error VARCHAR(500) NULL,
+    available_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    sent_at DATETIME(3) NULL,
+    INDEX idx_noti_email_queue_due (status, frequency, available_at),
+    INDEX idx_noti_email_queue_mb (mb_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS g5_noti_email_suppression (
+    email VARCHAR(255) NOT NULL PRIMARY KEY,
+    reason VARCHAR(20) NOT NULL COMMENT 'bounce(SMTP 5xx, 자동) | complaint | manual',
+    detail VARCHAR(500) NULL,
+    created_at DATETIME(3) NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
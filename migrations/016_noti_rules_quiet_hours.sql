@@ -0,0 +1,19 @@
+-- 알림 세부 설정: 글·게시판 단위 규칙(g5_noti_rule)과 방해 금지 시간·멘션 팔로우 한정 (g5_noti_preference)
+-- 글 규칙(wr_id > 0)은 mute·follow, 게시판 규칙(wr_id = 0)은 mute·on. 뮤트된 알림은 읽음으로 기록된다.
+-- 서버 기동 시에도 g5_noti_rule 을 만들고 g5_noti_preference 의 빠진 컬럼을 더한다 (cmd/api/main.go)
+
+ALTER TABLE g5_noti_preference
+    ADD COLUMN mention_following_only TINYINT(1) NOT NULL DEFAULT 0 COMMENT '팔로우한 회원의 멘션만 알림',
+    ADD COLUMN quiet_start VARCHAR(5) NOT NULL DEFAULT '' COMMENT '방해 금지 시작 HH:MM (빈 값 = 끔)',
+    ADD COLUMN quiet_end VARCHAR(5) NOT NULL DEFAULT '' COMMENT '방해 금지 끝 HH:MM',
+    ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT 'Asia/Seoul' COMMENT '방해 금지 시간의 시간대 (IANA)';
+
+CREATE TABLE IF NOT EXISTS g5_noti_rule (
+    mb_id VARCHAR(20) NOT NULL,
+    bo_table VARCHAR(20) NOT NULL,
+    wr_id INT NOT NULL DEFAULT 0 COMMENT '원글 id, 0 = 게시판 전체',
+    mode VARCHAR(10) NOT NULL COMMENT 'mute | follow (글) / mute | on (게시판)',
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (mb_id, bo_table, wr_id),
+    INDEX idx_noti_rule_thread (bo_table, wr_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
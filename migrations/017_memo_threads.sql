@@ -0,0 +1,28 @@
+-- 쪽지 대화방: 회원 × 상대 대화방 상태(g5_memo_thread)와 쪽지 이미지 첨부(g5_memo_attachment)
+-- 대화방 목록·안 읽은 수는 g5_memo 에서 매번 계산한다 — 여기엔 보관·알림 끄기만 둔다.
+-- 서버 기동 시 AutoMigrate 로도 생성된다 (cmd/api/main.go)
+
+CREATE TABLE IF NOT EXISTS g5_memo_thread (
+    mb_id VARCHAR(20) NOT NULL,
+    peer_mb_id VARCHAR(20) NOT NULL,
+    archived_me_id INT NOT NULL DEFAULT 0 COMMENT '보관 시점의 마지막 쪽지 id, 0 = 보관 안 함',
+    muted TINYINT(1) NOT NULL DEFAULT 0 COMMENT '쪽지 알림을 읽음으로만 남김',
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (mb_id, peer_mb_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS g5_memo_attachment (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    me_id INT NOT NULL DEFAULT 0 COMMENT '받은 쪽(recv) 행의 me_id, 0 = 아직 보내지 않은 업로드',
+    mb_id VARCHAR(20) NOT NULL COMMENT '올린 회원',
+    file_key VARCHAR(255) NOT NULL COMMENT '스토리지 키',
+    url VARCHAR(500) NOT NULL,
+    filename VARCHAR(255) NULL,
+    content_type VARCHAR(100) NULL,
+    size BIGINT NULL,
+    width INT NULL,
+    height INT NULL,
+    created_at DATETIME(3) NULL,
+    INDEX idx_memo_attachment_me (me_id),
+    INDEX idx_memo_attachment_mb (mb_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
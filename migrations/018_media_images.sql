@@ -0,0 +1,30 @@
+-- v2_media_images / v2_media_image_variants: 업로드 이미지와 크기별 파생본 (service.MediaService.UploadImage)
+-- 파생본은 폭(thumb·list·full, MEDIA_IMAGE_VARIANTS) × 형식(webp, 원본 형식)이다. 원본보다 넓게 만들지 않는다.
+-- 게시판 목록·나눔 목록 썸네일이 원본 주소(url)로 찾아 list 파생본을 내보낸다.
+-- 서버 기동 시 AutoMigrate 로도 생성된다
+
+CREATE TABLE IF NOT EXISTS v2_media_images (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    file_key VARCHAR(255) NOT NULL COMMENT '저장소 key (storage.Backend)',
+    url VARCHAR(500) NOT NULL COMMENT '업로드 응답의 원본 주소 — 본문·wr_10 에 박히는 값',
+    content_type VARCHAR(100) NULL,
+    width INT NULL,
+    height INT NULL,
+    blurhash VARCHAR(64) NULL COMMENT '로딩 자리표시 (blurha.sh)',
+    created_at DATETIME(3) NULL,
+    UNIQUE INDEX idx_v2_media_images_file_key (file_key),
+    INDEX idx_v2_media_images_url (url)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS v2_media_image_variants (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    image_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(16) NOT NULL COMMENT 'thumb | list | full',
+    format VARCHAR(8) NOT NULL COMMENT 'webp | jpeg | png',
+    file_key VARCHAR(255) NOT NULL COMMENT '작은 이미지는 여러 파생본이 같은 key(원본 포함)를 쓴다',
+    url VARCHAR(500) NOT NULL,
+    width INT NULL,
+    height INT NULL,
+    size BIGINT NULL,
+    INDEX idx_v2_media_image_variants_image_id (image_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
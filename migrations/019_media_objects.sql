@@ -0,0 +1,32 @@
+-- v2_media_objects / v2_media_refs: 내용 주소(SHA-256) 미디어 파일과 참조 (service.MediaService, cron media-gc)
+-- 같은 내용은 같은 key(images/ab/ab12…ef.jpg)라 한 번만 저장된다. 참조는 media-gc 가 매번 전부 다시 스캔한다:
+--   post·comment(g5_write_* 본문·wr_10, g5_board_file) / profile(g5_member.mb_image_url) / site_logo / message(g5_memo_attachment)
+-- ref_count 가 0 인 채로 유예기간(MEDIA_GC_GRACE_DAYS, 기본 30일)이 지나면 파일과 파생본을 지운다.
+-- 이 테이블 이전에 올라온 파일(무작위 key)은 기록이 없어 GC 대상이 아니다.
+-- 서버 기동 시 AutoMigrate 로도 생성된다
+
+CREATE TABLE IF NOT EXISTS v2_media_objects (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    sha256 CHAR(64) NOT NULL,
+    file_key VARCHAR(255) NOT NULL COMMENT '저장소 key (storage.ContentKey)',
+    url VARCHAR(500) NOT NULL COMMENT '업로드 응답 주소',
+    content_type VARCHAR(100) NULL,
+    size BIGINT NULL,
+    ref_count INT NOT NULL DEFAULT 0 COMMENT 'v2_media_refs 행 수 (media-gc 가 다시 센다)',
+    orphaned_at DATETIME(3) NULL COMMENT '참조가 없어진 시각 — 참조가 있으면 NULL',
+    last_uploaded_at DATETIME(3) NOT NULL COMMENT '같은 내용이 마지막으로 올라온 시각',
+    created_at DATETIME(3) NULL,
+    UNIQUE INDEX idx_v2_media_objects_sha256 (sha256),
+    UNIQUE INDEX idx_v2_media_objects_file_key (file_key),
+    INDEX idx_v2_media_objects_orphaned_at (orphaned_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS v2_media_refs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    object_id BIGINT UNSIGNED NOT NULL,
+    ref_type VARCHAR(16) NOT NULL COMMENT 'post | comment | profile | site_logo | message',
+    ref_key VARCHAR(100) NOT NULL COMMENT 'bo_table:wr_id | mb_id | site_logos.id | g5_memo_attachment.id',
+    scanned_at DATETIME(3) NOT NULL COMMENT '마지막으로 스캔에서 본 시각 — 이번 스캔보다 이르면 지워진 참조',
+    UNIQUE INDEX uk_media_ref (object_id, ref_type, ref_key),
+    INDEX idx_v2_media_refs_scanned_at (scanned_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
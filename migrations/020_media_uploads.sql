@@ -0,0 +1,32 @@
+-- v2_media_uploads / v2_media_upload_parts: 이어 올리기(tus 1.0.0) 진행 상태 (/api/v2/media/uploads, service.MediaService)
+-- PATCH 한 번마다 받은 조각을 저장소 uploads/<id>/ 에 따로 올리고 offset 을 옮긴다 — 어느 API 인스턴스가 받아도 이어진다.
+-- POST .../complete 에서 조각을 합쳐 MIME 을 확인한 뒤 내용 주소 key 로 저장하고 조각은 지운다.
+-- expires_at(마지막 조각 + 24시간)이 지난 업로드는 cron media-upload-cleanup 이 조각과 함께 지운다.
+-- 서버 기동 시 AutoMigrate 로도 생성된다
+
+CREATE TABLE IF NOT EXISTS v2_media_uploads (
+    id VARCHAR(32) NOT NULL PRIMARY KEY,
+    mb_id VARCHAR(20) NOT NULL,
+    kind VARCHAR(16) NOT NULL COMMENT 'video | attachment',
+    filename VARCHAR(255) NOT NULL,
+    upload_length BIGINT NOT NULL COMMENT '선언한 전체 크기 (Upload-Length)',
+    upload_offset BIGINT NOT NULL DEFAULT 0 COMMENT '지금까지 받은 바이트',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' COMMENT 'pending | completed',
+    file_key VARCHAR(255) NULL COMMENT '완료 후 저장된 key',
+    url VARCHAR(500) NULL,
+    content_type VARCHAR(100) NULL,
+    expires_at DATETIME(3) NOT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    INDEX idx_v2_media_uploads_mb_id (mb_id),
+    INDEX idx_v2_media_uploads_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS v2_media_upload_parts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    upload_id VARCHAR(32) NOT NULL,
+    part_offset BIGINT NOT NULL COMMENT '이 조각의 시작 위치',
+    size BIGINT NOT NULL,
+    file_key VARCHAR(255) NOT NULL COMMENT '저장소 key (uploads/<id>/...)',
+    UNIQUE INDEX uk_media_upload_part (upload_id, part_offset)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
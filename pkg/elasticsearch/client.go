@@ -154,11 +154,18 @@ type SearchResult struct {
 	Highlight map[string][]string    `json:"highlight,omitempty"`
 }
 
+// FacetBucket is one bucket of a terms / date_histogram aggregation
+type FacetBucket struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
 // SearchResponse holds search results
 type SearchResponse struct {
-	Total   int64          `json:"total"`
-	Results []SearchResult `json:"results"`
-	Suggest []string       `json:"suggest,omitempty"`
+	Total   int64                    `json:"total"`
+	Results []SearchResult           `json:"results"`
+	Suggest []string                 `json:"suggest,omitempty"`
+	Facets  map[string][]FacetBucket `json:"facets,omitempty"`
 }
 
 // Search performs a search query and returns results with highlights
@@ -331,5 +338,223 @@ func parseSearchResponse(raw map[string]interface{}) *SearchResponse {
 		}
 	}
 
+	if aggs, ok := raw["aggregations"].(map[string]interface{}); ok {
+		resp.Facets = make(map[string][]FacetBucket, len(aggs))
+		for name, agg := range aggs {
+			body, ok := agg.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			buckets, _ := body["buckets"].([]interface{})
+			facet := make([]FacetBucket, 0, len(buckets))
+			for _, b := range buckets {
+				bucket, ok := b.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				// date_histogram 은 key 가 epoch ms 라 key_as_string 을 쓴다
+				key, ok := bucket["key_as_string"].(string)
+				if !ok {
+					key = fmt.Sprintf("%v", bucket["key"])
+				}
+				count, _ := bucket["doc_count"].(float64)
+				facet = append(facet, FacetBucket{Key: key, Count: int64(count)})
+			}
+			resp.Facets[name] = facet
+		}
+	}
+
 	return resp
 }
+
+// IndexExists reports whether an index or alias exists
+func (c *Client) IndexExists(ctx context.Context, name string) (bool, error) {
+	res, err := c.es.Indices.Exists([]string{name}, c.es.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	res.Body.Close()
+	switch res.StatusCode {
+	case 200:
+		return true, nil
+	case 404:
+		return false, nil
+	default:
+		return false, fmt.Errorf("index exists error [%s]", res.Status())
+	}
+}
+
+// AliasIndices returns the indices an alias points to (nil if the alias does not exist)
+func (c *Client) AliasIndices(ctx context.Context, alias string) ([]string, error) {
+	res, err := c.es.Indices.GetAlias(c.es.Indices.GetAlias.WithName(alias), c.es.Indices.GetAlias.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return nil, nil
+	}
+	if res.IsError() {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("get alias error [%s]: failed to read response body: %w", res.Status(), err)
+		}
+		return nil, fmt.Errorf("get alias error [%s]: %s", res.Status(), string(body))
+	}
+
+	var raw map[string]interface{}
+	if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode alias response: %w", err)
+	}
+	indices := make([]string, 0, len(raw))
+	for index := range raw {
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+// UpdateAliases applies alias actions atomically (add / remove / remove_index)
+func (c *Client) UpdateAliases(ctx context.Context, actions []map[string]interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]interface{}{"actions": actions}); err != nil {
+		return fmt.Errorf("failed to encode alias actions: %w", err)
+	}
+
+	res, err := c.es.Indices.UpdateAliases(&buf, c.es.Indices.UpdateAliases.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("update aliases error [%s]: failed to read response body: %w", res.Status(), err)
+		}
+		return fmt.Errorf("update aliases error [%s]: %s", res.Status(), string(body))
+	}
+	return nil
+}
+
+// DeleteIndex deletes an index (404 is ok)
+func (c *Client) DeleteIndex(ctx context.Context, index string) error {
+	res, err := c.es.Indices.Delete([]string{index}, c.es.Indices.Delete.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() && res.StatusCode != 404 {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return fmt.Errorf("delete index error [%s]: failed to read response body: %w", res.Status(), err)
+		}
+		return fmt.Errorf("delete index error [%s]: %s", res.Status(), string(body))
+	}
+	return nil
+}
+
+// Refresh makes recent writes visible to search
+func (c *Client) Refresh(ctx context.Context, index string) error {
+	res, err := c.es.Indices.Refresh(c.es.Indices.Refresh.WithIndex(index), c.es.Indices.Refresh.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("refresh error [%s]", res.Status())
+	}
+	return nil
+}
+
+// DeleteByQuery removes matching documents and returns the number deleted.
+// 동시에 갱신된 문서(버전 충돌)는 건너뛴다 — 방금 다시 색인된 문서를 지우지 않기 위해서다.
+func (c *Client) DeleteByQuery(ctx context.Context, index string, query map[string]interface{}) (int64, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]interface{}{"query": query}); err != nil {
+		return 0, fmt.Errorf("failed to encode delete query: %w", err)
+	}
+
+	res, err := c.es.DeleteByQuery(
+		[]string{index},
+		&buf,
+		c.es.DeleteByQuery.WithContext(ctx),
+		c.es.DeleteByQuery.WithConflicts("proceed"),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return 0, nil // index not created yet
+	}
+	if res.IsError() {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return 0, fmt.Errorf("delete by query error [%s]: failed to read response body: %w", res.Status(), err)
+		}
+		return 0, fmt.Errorf("delete by query error [%s]: %s", res.Status(), string(body))
+	}
+
+	var raw struct {
+		Deleted int64 `json:"deleted"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+		return 0, fmt.Errorf("failed to decode delete by query response: %w", err)
+	}
+	return raw.Deleted, nil
+}
+
+// CountAndSum returns the hit count and the sum of a numeric field for a query
+func (c *Client) CountAndSum(ctx context.Context, index string, query map[string]interface{}, field string) (int64, float64, error) {
+	body := map[string]interface{}{
+		"query": query,
+		"aggs": map[string]interface{}{
+			"field_sum": map[string]interface{}{"sum": map[string]interface{}{"field": field}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return 0, 0, fmt.Errorf("failed to encode count query: %w", err)
+	}
+
+	res, err := c.es.Search(
+		c.es.Search.WithContext(ctx),
+		c.es.Search.WithIndex(index),
+		c.es.Search.WithBody(&buf),
+		c.es.Search.WithSize(0),
+		c.es.Search.WithTrackTotalHits(true),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return 0, 0, fmt.Errorf("count error [%s]: failed to read response body: %w", res.Status(), err)
+		}
+		return 0, 0, fmt.Errorf("count error [%s]: %s", res.Status(), string(body))
+	}
+
+	var raw struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+		} `json:"hits"`
+		Aggregations struct {
+			FieldSum struct {
+				Value float64 `json:"value"`
+			} `json:"field_sum"`
+		} `json:"aggregations"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+		return 0, 0, fmt.Errorf("failed to decode count response: %w", err)
+	}
+	return raw.Hits.Total.Value, raw.Aggregations.FieldSum.Value, nil
+}
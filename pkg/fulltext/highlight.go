@@ -0,0 +1,112 @@
+package fulltext
+
+import (
+	"sort"
+	"strings"
+)
+
+type span struct{ start, end int }
+
+// matchSpans finds every occurrence of the query words in norm, merged and sorted
+func matchSpans(norm []rune, words [][]rune) []span {
+	var spans []span
+	for _, word := range words {
+		for from := 0; from < len(norm); {
+			i := containsRunes(norm[from:], word)
+			if i < 0 {
+				break
+			}
+			spans = append(spans, span{from + i, from + i + len(word)})
+			from += i + len(word)
+		}
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start <= last.end {
+			if s.end > last.end {
+				last.end = s.end
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// highlight builds the snippets of one hit, Elasticsearch highlight 형태로 (field → fragments)
+func highlight(e *entry, words [][]rune, q Query) map[string][]string {
+	pre, post := q.PreTag, q.PostTag
+	if pre == "" && post == "" {
+		pre, post = "<em>", "</em>"
+	}
+	out := make(map[string][]string)
+	for field, opts := range q.Highlight {
+		spans := matchSpans(e.norm[field], words)
+		if len(spans) == 0 {
+			continue
+		}
+		orig := []rune(e.doc.Text[field])
+		if opts.Fragments <= 0 || opts.FragmentSize <= 0 {
+			out[field] = []string{mark(orig, 0, len(orig), spans, pre, post)}
+			continue
+		}
+		var fragments []string
+		covered := -1
+		for _, s := range spans {
+			if len(fragments) >= opts.Fragments {
+				break
+			}
+			if s.start < covered {
+				continue // 앞 조각에 이미 들어간 일치
+			}
+			start := s.start - (opts.FragmentSize-(s.end-s.start))/2
+			if start < covered {
+				start = covered
+			}
+			if start < 0 {
+				start = 0
+			}
+			end := start + opts.FragmentSize
+			if end < s.end {
+				end = s.end
+			}
+			if end > len(orig) {
+				end = len(orig)
+			}
+			fragments = append(fragments, strings.TrimSpace(mark(orig, start, end, spans, pre, post)))
+			covered = end
+		}
+		out[field] = fragments
+	}
+	return out
+}
+
+// mark returns orig[start:end] with the spans inside wrapped in pre/post tags
+func mark(orig []rune, start, end int, spans []span, pre, post string) string {
+	var b strings.Builder
+	pos := start
+	for _, s := range spans {
+		if s.end <= start || s.start >= end {
+			continue
+		}
+		from, to := s.start, s.end
+		if from < start {
+			from = start
+		}
+		if to > end {
+			to = end
+		}
+		b.WriteString(string(orig[pos:from]))
+		b.WriteString(pre)
+		b.WriteString(string(orig[from:to]))
+		b.WriteString(post)
+		pos = to
+	}
+	b.WriteString(string(orig[pos:end]))
+	return b.String()
+}
@@ -0,0 +1,393 @@
+// Package fulltext is a small in-process inverted index for deployments without a search server.
+//
+// 한글은 형태소 분석 대신 2-gram 으로 색인하고, 후보를 찾은 뒤 원문에서 검색어가 실제로
+// 붙어 나오는지 다시 확인한다(Sphinx 1-gram infix 의 "산불" ↔ "생산 불가" 오탐 방지).
+// 색인은 메모리에만 있고 프로세스마다 따로다 — 원본(DB)에서 다시 만들 수 있는 캐시로 취급한다.
+package fulltext
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BM25 parameters (Elasticsearch 기본값과 같다)
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Document is one indexed record
+type Document struct {
+	ID       string
+	Text     map[string]string      // full-text fields (검색·하이라이트 대상)
+	Keywords map[string]string      // exact-match fields (board_id, author_id …)
+	Lists    map[string][]string    // multi-valued exact-match fields (tags …)
+	Numbers  map[string]int64       // numeric fields (post_id, created_at unix …)
+	Source   map[string]interface{} // returned as-is with hits
+}
+
+// HighlightOptions controls snippets for one field
+type HighlightOptions struct {
+	FragmentSize int // runes per fragment
+	Fragments    int // 0 이면 필드 전체를 하이라이트해서 돌려준다
+}
+
+// Query is a search request
+type Query struct {
+	Text   string
+	Fields map[string]float64 // field → boost; 비어 있으면 모든 텍스트 필드를 1 로
+	Filter func(*Document) bool
+	// SortBy 가 비어 있으면 관련도순, 아니면 그 숫자 필드 내림차순(최신순)
+	SortBy   string
+	TieBreak string // 관련도가 같을 때 내림차순으로 비교할 숫자 필드
+	Offset   int
+	Limit    int
+
+	Highlight map[string]HighlightOptions
+	PreTag    string
+	PostTag   string
+
+	// Facets 는 이름 → 문서의 버킷 키(들). 페이지가 아니라 일치 문서 전체를 센다
+	Facets map[string]func(*Document) []string
+}
+
+// Hit is one search result
+type Hit struct {
+	Doc       *Document
+	Score     float64
+	Highlight map[string][]string
+}
+
+// Result is a page of hits plus the total match count
+type Result struct {
+	Total  int
+	Hits   []Hit
+	Facets map[string]map[string]int // facet → bucket key → count
+}
+
+type entry struct {
+	doc   *Document
+	norm  map[string][]rune         // field → 소문자화한 원문 (구문 확인·하이라이트)
+	terms map[string]map[string]int // field → term → tf
+	size  map[string]int            // field → term 수
+}
+
+// Index is a concurrency-safe inverted index
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]*entry
+	postings map[string]map[string]map[*entry]int // field → term → entry → tf
+	totalLen map[string]int                       // field → 전체 term 수 (평균 길이 계산용)
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]*entry),
+		postings: make(map[string]map[string]map[*entry]int),
+		totalLen: make(map[string]int),
+	}
+}
+
+// Len returns the number of documents
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Put adds or replaces a document
+func (ix *Index) Put(doc Document) {
+	e := &entry{
+		doc:   &doc,
+		norm:  make(map[string][]rune, len(doc.Text)),
+		terms: make(map[string]map[string]int, len(doc.Text)),
+		size:  make(map[string]int, len(doc.Text)),
+	}
+	for field, text := range doc.Text {
+		e.norm[field] = normalize(text)
+		tf := make(map[string]int)
+		for _, term := range Tokenize(text) {
+			tf[term]++
+			e.size[field]++
+		}
+		e.terms[field] = tf
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if old, ok := ix.docs[doc.ID]; ok {
+		ix.unlink(old)
+	}
+	ix.docs[doc.ID] = e
+	for field, tf := range e.terms {
+		byTerm := ix.postings[field]
+		if byTerm == nil {
+			byTerm = make(map[string]map[*entry]int)
+			ix.postings[field] = byTerm
+		}
+		for term, n := range tf {
+			if byTerm[term] == nil {
+				byTerm[term] = make(map[*entry]int)
+			}
+			byTerm[term][e] = n
+		}
+		ix.totalLen[field] += e.size[field]
+	}
+}
+
+// Delete removes a document; it reports whether the document existed
+func (ix *Index) Delete(id string) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	e, ok := ix.docs[id]
+	if !ok {
+		return false
+	}
+	ix.unlink(e)
+	delete(ix.docs, id)
+	return true
+}
+
+// DeleteWhere removes every document matching pred and returns how many were removed
+func (ix *Index) DeleteWhere(pred func(*Document) bool) int {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	removed := 0
+	for id, e := range ix.docs {
+		if pred(e.doc) {
+			ix.unlink(e)
+			delete(ix.docs, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Each calls fn for every document until fn returns false (순서는 정해져 있지 않다)
+func (ix *Index) Each(fn func(*Document) bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	for _, e := range ix.docs {
+		if !fn(e.doc) {
+			return
+		}
+	}
+}
+
+// unlink removes e from the postings; caller holds the write lock
+func (ix *Index) unlink(e *entry) {
+	for field, tf := range e.terms {
+		byTerm := ix.postings[field]
+		for term := range tf {
+			delete(byTerm[term], e)
+			if len(byTerm[term]) == 0 {
+				delete(byTerm, term)
+			}
+		}
+		ix.totalLen[field] -= e.size[field]
+	}
+}
+
+// queryTerms returns the posting terms that a query word needs
+func queryTerms(word []rune) []string {
+	if isCJK(word[0]) && len(word) == 1 {
+		return []string{string(word)}
+	}
+	return runTerms(word)
+}
+
+// Search runs q and returns one page of hits
+func (ix *Index) Search(q Query) Result {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	fields := q.Fields
+	if len(fields) == 0 {
+		fields = make(map[string]float64, len(ix.postings))
+		for field := range ix.postings {
+			fields[field] = 1
+		}
+	}
+	words := queryWords(q.Text)
+	if strings.TrimSpace(q.Text) != "" && len(words) == 0 {
+		return Result{} // 문장부호만 있는 검색어
+	}
+
+	var candidates map[*entry]struct{}
+	if len(words) == 0 {
+		candidates = make(map[*entry]struct{}, len(ix.docs))
+		for _, e := range ix.docs {
+			candidates[e] = struct{}{}
+		}
+	} else {
+		for _, word := range words {
+			for _, term := range queryTerms(word) {
+				candidates = ix.intersect(candidates, term, fields)
+				if len(candidates) == 0 {
+					return Result{}
+				}
+			}
+		}
+	}
+
+	type scored struct {
+		e     *entry
+		score float64
+	}
+	matches := make([]scored, 0, len(candidates))
+	for e := range candidates {
+		if !matchesAllWords(e, words, fields) {
+			continue
+		}
+		if q.Filter != nil && !q.Filter(e.doc) {
+			continue
+		}
+		matches = append(matches, scored{e: e, score: ix.score(e, words, fields)})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if q.SortBy != "" {
+			if x, y := a.e.doc.Numbers[q.SortBy], b.e.doc.Numbers[q.SortBy]; x != y {
+				return x > y
+			}
+		}
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if q.TieBreak != "" {
+			if x, y := a.e.doc.Numbers[q.TieBreak], b.e.doc.Numbers[q.TieBreak]; x != y {
+				return x > y
+			}
+		}
+		return a.e.doc.ID < b.e.doc.ID
+	})
+
+	result := Result{Total: len(matches)}
+	if len(q.Facets) > 0 {
+		result.Facets = make(map[string]map[string]int, len(q.Facets))
+		for name, keys := range q.Facets {
+			counts := make(map[string]int)
+			for _, m := range matches {
+				for _, key := range keys(m.e.doc) {
+					counts[key]++
+				}
+			}
+			result.Facets[name] = counts
+		}
+	}
+	start := q.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start >= len(matches) {
+		return result
+	}
+	end := len(matches)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+	for _, m := range matches[start:end] {
+		hit := Hit{Doc: m.e.doc, Score: m.score}
+		if len(q.Highlight) > 0 && len(words) > 0 {
+			hit.Highlight = highlight(m.e, words, q)
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	return result
+}
+
+// intersect narrows candidates to entries having term in any of the fields (nil = 처음)
+func (ix *Index) intersect(candidates map[*entry]struct{}, term string, fields map[string]float64) map[*entry]struct{} {
+	next := make(map[*entry]struct{})
+	for field := range fields {
+		for e := range ix.postings[field][term] {
+			if candidates == nil {
+				next[e] = struct{}{}
+			} else if _, ok := candidates[e]; ok {
+				next[e] = struct{}{}
+			}
+		}
+	}
+	return next
+}
+
+// matchesAllWords checks every query word occurs contiguously in some searched field
+func matchesAllWords(e *entry, words [][]rune, fields map[string]float64) bool {
+	for _, word := range words {
+		found := false
+		for field := range fields {
+			if containsRunes(e.norm[field], word) >= 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// score is field-boosted BM25 over the query terms
+func (ix *Index) score(e *entry, words [][]rune, fields map[string]float64) float64 {
+	n := float64(len(ix.docs))
+	total := 0.0
+	for field, boost := range fields {
+		tf := e.terms[field]
+		if len(tf) == 0 {
+			continue
+		}
+		avg := float64(ix.totalLen[field]) / n
+		if avg == 0 {
+			continue
+		}
+		dl := float64(e.size[field])
+		for _, word := range words {
+			for _, term := range queryTerms(word) {
+				f := float64(tf[term])
+				if f == 0 {
+					continue
+				}
+				df := float64(len(ix.postings[field][term]))
+				idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+				total += boost * idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*dl/avg))
+			}
+		}
+	}
+	return total
+}
+
+// Suggest returns up to size distinct words of field starting with prefix, most frequent first
+func (ix *Index) Suggest(field, prefix string, size int) []string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || size <= 0 {
+		return nil
+	}
+	ix.mu.RLock()
+	counts := make(map[string]int)
+	for _, e := range ix.docs {
+		for _, word := range prefixWords(e.doc.Text[field], prefix) {
+			counts[word]++
+		}
+	}
+	ix.mu.RUnlock()
+
+	words := make([]string, 0, len(counts))
+	for word := range counts {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	if len(words) > size {
+		words = words[:size]
+	}
+	return words
+}
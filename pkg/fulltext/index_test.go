@@ -0,0 +1,101 @@
+package fulltext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("산불이 Go1")
+	want := []string{"산불", "불이", "산", "불", "이", "go1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %q, want %q", got, want)
+	}
+}
+
+func newTestIndex() *Index {
+	ix := NewIndex()
+	ix.Put(Document{ID: "1", Text: map[string]string{"title": "강원 산불 진화 완료", "content": "밤사이 산불이 모두 꺼졌습니다"}, Keywords: map[string]string{"board": "free"}, Numbers: map[string]int64{"at": 1}})
+	ix.Put(Document{ID: "2", Text: map[string]string{"title": "생산 불가 공지", "content": "부품 생산 불가로 출시가 늦어집니다"}, Keywords: map[string]string{"board": "free"}, Numbers: map[string]int64{"at": 2}})
+	ix.Put(Document{ID: "3", Text: map[string]string{"title": "산불 조심", "content": "Golang 으로 만든 산불 알림"}, Keywords: map[string]string{"board": "dev"}, Numbers: map[string]int64{"at": 3}})
+	return ix
+}
+
+func ids(r Result) []string {
+	var out []string
+	for _, h := range r.Hits {
+		out = append(out, h.Doc.ID)
+	}
+	return out
+}
+
+func TestSearchHangulPhrase(t *testing.T) {
+	ix := newTestIndex()
+
+	r := ix.Search(Query{Text: "산불", Fields: map[string]float64{"title": 3, "content": 1}, TieBreak: "at"})
+	if r.Total != 2 {
+		t.Fatalf("산불 should not match 생산 불가, got %v", ids(r))
+	}
+
+	r = ix.Search(Query{Text: "산불", SortBy: "at"})
+	if got := ids(r); !reflect.DeepEqual(got, []string{"3", "1"}) {
+		t.Errorf("recency order = %v", got)
+	}
+
+	r = ix.Search(Query{Text: "산불", Filter: func(d *Document) bool { return d.Keywords["board"] == "dev" }})
+	if got := ids(r); !reflect.DeepEqual(got, []string{"3"}) {
+		t.Errorf("filtered = %v", got)
+	}
+
+	if r := ix.Search(Query{Text: "golang 알림"}); r.Total != 1 {
+		t.Errorf("mixed-script AND query total = %d", r.Total)
+	}
+	r = ix.Search(Query{Text: "산불", Facets: map[string]func(*Document) []string{
+		"board": func(d *Document) []string { return []string{d.Keywords["board"]} },
+	}})
+	if got := r.Facets["board"]; got["free"] != 1 || got["dev"] != 1 {
+		t.Errorf("board facet = %v", got)
+	}
+	if r := ix.Search(Query{Text: "산불 공지"}); r.Total != 0 {
+		t.Errorf("every word must match, got %v", ids(r))
+	}
+}
+
+func TestSearchHighlightAndDelete(t *testing.T) {
+	ix := newTestIndex()
+
+	r := ix.Search(Query{
+		Text:      "산불",
+		Filter:    func(d *Document) bool { return d.ID == "1" },
+		Highlight: map[string]HighlightOptions{"title": {}, "content": {FragmentSize: 6, Fragments: 1}},
+		PreTag:    "<mark>",
+		PostTag:   "</mark>",
+	})
+	if len(r.Hits) != 1 {
+		t.Fatalf("expected one hit, got %v", ids(r))
+	}
+	hl := r.Hits[0].Highlight
+	if hl["title"][0] != "강원 <mark>산불</mark> 진화 완료" {
+		t.Errorf("title highlight = %q", hl["title"])
+	}
+	if hl["content"][0] != "이 <mark>산불</mark>이" {
+		t.Errorf("content highlight = %q", hl["content"])
+	}
+
+	if !ix.Delete("1") || ix.Delete("1") {
+		t.Fatal("delete should report existence once")
+	}
+	if n := ix.DeleteWhere(func(d *Document) bool { return d.Keywords["board"] == "dev" }); n != 1 {
+		t.Fatalf("DeleteWhere removed %d", n)
+	}
+	if r := ix.Search(Query{Text: "산불"}); r.Total != 0 || ix.Len() != 1 {
+		t.Errorf("deleted docs still searchable: %v (len %d)", ids(r), ix.Len())
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	ix := newTestIndex()
+	if got := ix.Suggest("title", "산", 5); !reflect.DeepEqual(got, []string{"산불"}) {
+		t.Errorf("Suggest = %q", got)
+	}
+}
@@ -0,0 +1,121 @@
+package fulltext
+
+import (
+	"strings"
+	"unicode"
+)
+
+// isCJK reports whether r is Hangul, Han or Kana.
+// 띄어쓰기·조사가 붙어 형태소 경계를 알 수 없는 글자들이라 n-gram 으로 자른다.
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Hangul, r) || unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// normalize lower-cases rune by rune so positions in the result match the input runes.
+func normalize(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// Tokenize splits text into index terms.
+//
+//   - 한글·한자·가나 구간은 글자 하나씩(1-gram)과 2-gram ("산불이" → "산", "불", "이", "산불", "불이")
+//   - 영문·숫자 구간은 소문자 단어 하나
+//
+// 검색어는 2-gram 으로만 찾으므로(한 글자 검색어만 1-gram) "생산 불가" 는 "산불" 에 걸리지 않는다.
+func Tokenize(text string) []string {
+	var terms []string
+	for _, run := range wordRuns(normalize(text)) {
+		terms = append(terms, runTerms(run)...)
+		if isCJK(run[0]) && len(run) > 1 {
+			for _, r := range run {
+				terms = append(terms, string(r))
+			}
+		}
+	}
+	return terms
+}
+
+// wordRuns splits runes into maximal runs of the same script class (CJK vs. others).
+func wordRuns(runes []rune) [][]rune {
+	var runs [][]rune
+	start := -1
+	cjk := false
+	for i, r := range runes {
+		if !isWordRune(r) {
+			if start >= 0 {
+				runs = append(runs, runes[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && isCJK(r) != cjk {
+			runs = append(runs, runes[start:i])
+			start = -1
+		}
+		if start < 0 {
+			start = i
+			cjk = isCJK(r)
+		}
+	}
+	if start >= 0 {
+		runs = append(runs, runes[start:])
+	}
+	return runs
+}
+
+func runTerms(run []rune) []string {
+	if !isCJK(run[0]) || len(run) == 1 {
+		return []string{string(run)}
+	}
+	terms := make([]string, 0, len(run)-1)
+	for i := 0; i+1 < len(run); i++ {
+		terms = append(terms, string(run[i:i+2]))
+	}
+	return terms
+}
+
+// queryWords splits a query into the normalized words every match must contain.
+func queryWords(query string) [][]rune {
+	return wordRuns(normalize(query))
+}
+
+// containsRunes reports whether needle occurs in haystack and returns its first offset.
+func containsRunes(haystack, needle []rune) int {
+	if len(needle) == 0 {
+		return -1
+	}
+	first := needle[0]
+outer:
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if haystack[i] != first {
+			continue
+		}
+		for j := 1; j < len(needle); j++ {
+			if haystack[i+j] != needle[j] {
+				continue outer
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// prefixWords returns the words of text starting with prefix (자동완성)
+func prefixWords(text, prefix string) []string {
+	var out []string
+	for _, word := range strings.Fields(text) {
+		if strings.HasPrefix(strings.ToLower(word), prefix) {
+			out = append(out, word)
+		}
+	}
+	return out
+}
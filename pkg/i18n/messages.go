@@ -65,6 +65,26 @@ var koMessages = map[string]string{
 
 	// Search
 	"search.query_required": "검색어를 입력해주세요",
+
+	// Email notifications (service.EmailNotificationService)
+	"email.category.account":         "계정 안내",
+	"email.category.point":           "포인트",
+	"email.category.digest":          "게시판 요약",
+	"email.open":                     "사이트에서 보기",
+	"email.footer.reason":            "알림 설정(%s)에 따라 보내 드리는 메일입니다.",
+	"email.footer.settings":          "알림 설정 바꾸기",
+	"email.footer.unsubscribe":       "이 메일 받지 않기",
+	"email.digest.daily_subject":     "오늘의 알림 %d건",
+	"email.digest.weekly_subject":    "이번 주 알림 %d건",
+	"email.digest.intro":             "그동안 쌓인 알림을 모아 보내 드립니다.",
+	"email.point_expiry.subject":     "포인트 만료 예정 안내",
+	"email.point_expiry.body":        "7일 안에 %sP가 만료됩니다.",
+	"email.discipline.subject":       "이용 제한 안내",
+	"email.discipline.body":          "운영 정책 위반으로 이용이 제한되었습니다 (기간: %s). 자세한 사유는 쪽지함에서 확인해 주세요.",
+	"email.withdrawal_grace.subject": "탈퇴 확정 예정 안내",
+	"email.withdrawal_grace.body":    "%s에 탈퇴가 확정되어 닉네임과 작성한 글의 작성자명이 익명으로 바뀝니다. 탈퇴를 취소하려면 그 전에 로그인해 주세요.",
+	"email.board_digest.subject":     "%s 새 글 %s건",
+	"email.board_digest.body":        "%s 게시판에 새 글 %s건: %s",
 }
 
 //nolint:dupl // i18n message maps for different languages intentionally share the same keys
@@ -122,6 +142,26 @@ var enMessages = map[string]string{
 
 	// Search
 	"search.query_required": "Search query is required",
+
+	// Email notifications (service.EmailNotificationService)
+	"email.category.account":         "Account notices",
+	"email.category.point":           "Points",
+	"email.category.digest":          "Board digests",
+	"email.open":                     "View on site",
+	"email.footer.reason":            "You are receiving this because of your notification settings (%s).",
+	"email.footer.settings":          "Change notification settings",
+	"email.footer.unsubscribe":       "Unsubscribe from these emails",
+	"email.digest.daily_subject":     "%d notifications today",
+	"email.digest.weekly_subject":    "%d notifications this week",
+	"email.digest.intro":             "Here is a summary of the notifications you received.",
+	"email.point_expiry.subject":     "Your points are about to expire",
+	"email.point_expiry.body":        "%sP will expire within 7 days.",
+	"email.discipline.subject":       "Account restriction notice",
+	"email.discipline.body":          "Your account has been restricted for violating the community policy (period: %s). See your messages for details.",
+	"email.withdrawal_grace.subject": "Your account withdrawal will be finalized soon",
+	"email.withdrawal_grace.body":    "Your withdrawal will be finalized on %s and your nickname and author names will be anonymized. Log in before then to cancel it.",
+	"email.board_digest.subject":     "%s: %s new posts",
+	"email.board_digest.body":        "%s has %s new posts: %s",
 }
 
 //nolint:dupl // i18n message maps for different languages intentionally share the same keys
@@ -179,4 +219,24 @@ var jaMessages = map[string]string{
 
 	// Search
 	"search.query_required": "検索語を入力してください",
+
+	// Email notifications (service.EmailNotificationService)
+	"email.category.account":         "アカウントのお知らせ",
+	"email.category.point":           "ポイント",
+	"email.category.digest":          "掲示板まとめ",
+	"email.open":                     "サイトで見る",
+	"email.footer.reason":            "通知設定（%s）に基づいてお送りしています。",
+	"email.footer.settings":          "通知設定を変更",
+	"email.footer.unsubscribe":       "このメールの配信を停止",
+	"email.digest.daily_subject":     "今日の通知 %d件",
+	"email.digest.weekly_subject":    "今週の通知 %d件",
+	"email.digest.intro":             "届いた通知をまとめてお送りします。",
+	"email.point_expiry.subject":     "ポイント失効予定のお知らせ",
+	"email.point_expiry.body":        "7日以内に%sPが失効します。",
+	"email.discipline.subject":       "利用制限のお知らせ",
+	"email.discipline.body":          "運営ポリシー違反により利用が制限されました（期間: %s）。詳しい理由はメッセージをご確認ください。",
+	"email.withdrawal_grace.subject": "退会確定予定のお知らせ",
+	"email.withdrawal_grace.body":    "%sに退会が確定し、ニックネームと投稿の作成者名が匿名化されます。退会を取り消すにはそれまでにログインしてください。",
+	"email.board_digest.subject":     "%s 新着 %s件",
+	"email.board_digest.body":        "%s の新着 %s件: %s",
 }
@@ -20,14 +20,26 @@ type Claims struct {
 	Nickname string `json:"nickname"`
 	Level    int    `json:"level"`
 	Email    string `json:"email,omitempty"`
+	// SessionID 는 서버 세션(리프레시 토큰 패밀리) ID 다. 세션 없이 발급된 토큰은 비어 있다
+	SessionID string `json:"sid,omitempty"`
+	// MFA 는 이 세션이 2단계 인증(TOTP·복구 코드)을 거쳤는지다 — RequireAdmin 의 MFA 정책이 본다
+	MFA bool `json:"mfa,omitempty"`
+	// Ticket 은 GenerateTicket 으로 만든 단일 용도 토큰 표시다 — 로그인·갱신 토큰 자리에 받으면 안 된다
+	Ticket bool `json:"tkt,omitempty"`
 }
 
 // Manager JWT token manager
+//
+// 비대칭 서명키(SetSigningKeys·SetKeySource)가 있으면 그 키로 서명하고 헤더에 kid 를 싣는다.
+// 없으면 예전처럼 HS256 공유 시크릿으로 서명한다. HS256 토큰은 SetAcceptHS256(false) 전까지 계속 받는다.
 type Manager struct {
 	secretKey     []byte
 	secretKeyNext []byte // 키 롤오버용 보조 검증키(설정 시 주키 실패하면 재시도). 서명엔 미사용.
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
+
+	rejectHS256 bool
+	keys        keyring
 }
 
 // NewManager creates a new JWT manager
@@ -40,7 +52,7 @@ func NewManager(secret string, accessExpiry, refreshExpiry int) *Manager {
 }
 
 // SetNextKey registers an additional key accepted ONLY for verification (무중단 키 롤오버).
-// 서명은 항상 주키(secretKey)를 쓴다. 빈 값이면 비활성 = 기존 동작과 완전히 동일.
+// HS256 서명은 항상 주키(secretKey)를 쓴다. 빈 값이면 비활성 = 기존 동작과 완전히 동일.
 func (m *Manager) SetNextKey(next string) {
 	if next != "" {
 		m.secretKeyNext = []byte(next)
@@ -61,8 +73,7 @@ func (m *Manager) GenerateAccessToken(userID, username, nickname string, level i
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.secretKey)
+	return m.sign(claims)
 }
 
 // GenerateRefreshToken generates a refresh token
@@ -76,13 +87,79 @@ func (m *Manager) GenerateRefreshToken(userID string) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.secretKey)
+	return m.sign(claims)
+}
+
+// GenerateSessionAccessToken generates an access token bound to a server session (sid)
+func (m *Manager) GenerateSessionAccessToken(userID, username, nickname string, level int, sessionID string, mfa bool) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:    userID,
+		Username:  username,
+		Nickname:  nickname,
+		Level:     level,
+		SessionID: sessionID,
+		MFA:       mfa,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.accessExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	return m.sign(claims)
+}
+
+// GenerateSessionRefreshToken generates a refresh token for one rotation (jti) of a session family (sid)
+func (m *Manager) GenerateSessionRefreshToken(userID, sessionID, tokenID string) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:    userID,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.refreshExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	return m.sign(claims)
+}
+
+// GenerateTicket generates a short-lived single-purpose token (예: 2단계 인증 대기 티켓).
+// audience 로 용도를 구분한다 — access·refresh 토큰 자리에 쓰일 수 없도록 호출자가 aud 를 확인할 것.
+func (m *Manager) GenerateTicket(userID, audience, tokenID string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID: userID,
+		Ticket: true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	return m.sign(claims)
+}
+
+// RefreshExpiry returns the refresh token lifetime
+func (m *Manager) RefreshExpiry() time.Duration {
+	return m.refreshExpiry
+}
+
+// AccessExpiry returns the access token lifetime
+func (m *Manager) AccessExpiry() time.Duration {
+	return m.accessExpiry
 }
 
 // VerifyToken verifies and parses a token.
-// 주키로 먼저 검증하고, 서명 불일치 등으로 실패하면 보조키(secretKeyNext)가 설정된 경우 재시도한다.
+// HS256 은 주키로 먼저 검증하고, 서명 불일치 등으로 실패하면 보조키(secretKeyNext)가 설정된 경우 재시도한다.
 // 만료(ErrExpiredToken)는 재시도하지 않는다. → 무중단 키 롤오버(신·구 키 동시 수용).
+// ES256·EdDSA 는 헤더의 kid 로 공개키를 찾는다(모르는 kid 면 키 저장소를 한 번 다시 읽는다).
 func (m *Manager) VerifyToken(tokenString string) (*Claims, error) {
 	claims, err := m.verifyWithKey(tokenString, m.secretKey)
 	if err != nil && errors.Is(err, ErrInvalidToken) && m.secretKeyNext != nil {
@@ -97,11 +174,18 @@ func (m *Manager) VerifyToken(tokenString string) (*Claims, error) {
 func (m *Manager) verifyWithKey(tokenString string, key []byte) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if m.rejectHS256 || len(key) == 0 {
+				return nil, ErrInvalidToken
+			}
+			return key, nil
+		case *jwt.SigningMethodECDSA, *jwt.SigningMethodEd25519:
+			kid, _ := token.Header["kid"].(string) //nolint:errcheck // 없으면 아래에서 거절된다
+			return m.keys.verificationKey(kid, token.Method.Alg())
 		}
-		return key, nil
-	})
+		return nil, ErrInvalidToken
+	}, jwt.WithValidMethods([]string{"HS256", AlgES256, AlgEdDSA}))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -0,0 +1,358 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 비대칭 서명 알고리즘 (JWS alg)
+const (
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+// keyReloadEvery 는 모르는 kid 로 키 저장소를 다시 읽는 최소 간격이다 (위조 토큰으로 저장소를 두드리지 않게)
+const keyReloadEvery = 10 * time.Second
+
+var (
+	// ErrUnsupportedAlgorithm is returned for an algorithm other than ES256 and EdDSA
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	// ErrNoSigningKey is returned when neither an active private key nor an HS256 secret is set (검증 전용 Manager)
+	ErrNoSigningKey = errors.New("no signing key")
+)
+
+// SigningKey is one asymmetric key of the keyring.
+// Private 이 nil 이면 검증 전용(JWKS 로 받은 공개키)이다.
+// ActivatesAt 전에는 서명에 쓰지 않지만 JWKS 에는 미리 실린다 — 검증 측 캐시가 새 키를 먼저 받아 두도록.
+// RetiresAt 이 지나면 서명·검증·JWKS 모두에서 빠진다 (nil 이면 은퇴 예정 없음).
+type SigningKey struct {
+	ID          string
+	Algorithm   string
+	Private     crypto.Signer
+	Public      crypto.PublicKey
+	ActivatesAt time.Time
+	RetiresAt   *time.Time
+}
+
+func (k *SigningKey) retired(now time.Time) bool {
+	return k.RetiresAt != nil && !now.Before(*k.RetiresAt)
+}
+
+// KeySource loads the current keyring (DB 의 서명키, 원격 JWKS 등)
+type KeySource func(ctx context.Context) ([]*SigningKey, error)
+
+// keyring holds the asymmetric keys of a Manager
+type keyring struct {
+	mu       sync.RWMutex
+	keys     []*SigningKey
+	source   KeySource
+	loadedAt time.Time
+
+	reloadMu sync.Mutex
+}
+
+// SetSigningKeys replaces the keyring.
+// 서명은 활성(ActivatesAt 경과, 미은퇴) 키 중 가장 늦게 활성된 개인키로 한다. 그런 키가 없으면 HS256.
+func (m *Manager) SetSigningKeys(keys []*SigningKey) {
+	m.keys.mu.Lock()
+	m.keys.keys = keys
+	m.keys.loadedAt = time.Now()
+	m.keys.mu.Unlock()
+}
+
+// SetKeySource sets where ReloadKeys (and a token with an unknown kid) loads the keyring from
+func (m *Manager) SetKeySource(src KeySource) {
+	m.keys.mu.Lock()
+	m.keys.source = src
+	m.keys.mu.Unlock()
+}
+
+// ReloadKeys loads the keyring from the key source
+func (m *Manager) ReloadKeys(ctx context.Context) error {
+	m.keys.mu.RLock()
+	src := m.keys.source
+	m.keys.mu.RUnlock()
+	if src == nil {
+		return nil
+	}
+	keys, err := src(ctx)
+	if err != nil {
+		return err
+	}
+	m.SetSigningKeys(keys)
+	return nil
+}
+
+// SetAcceptHS256 controls whether HS256 tokens are still accepted.
+// ⛔ 모든 발급처가 비대칭 키로 서명하고 기존 HS256 토큰이 만료(refresh_in)된 뒤에만 끌 것.
+func (m *Manager) SetAcceptHS256(accept bool) {
+	m.rejectHS256 = !accept
+}
+
+// sign signs claims with the active asymmetric key (kid 헤더 포함), 없으면 HS256 공유 시크릿
+func (m *Manager) sign(claims *Claims) (string, error) {
+	if key := m.keys.active(time.Now()); key != nil {
+		method := jwt.GetSigningMethod(key.Algorithm)
+		if method == nil {
+			return "", ErrUnsupportedAlgorithm
+		}
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = key.ID
+		return token.SignedString(key.Private)
+	}
+	if len(m.secretKey) == 0 {
+		return "", ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(m.secretKey)
+}
+
+func (r *keyring) active(now time.Time) *SigningKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var best *SigningKey
+	for _, k := range r.keys {
+		if k.Private == nil || now.Before(k.ActivatesAt) || k.retired(now) {
+			continue
+		}
+		if best == nil || k.ActivatesAt.After(best.ActivatesAt) {
+			best = k
+		}
+	}
+	return best
+}
+
+func (r *keyring) lookup(kid string, now time.Time) *SigningKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, k := range r.keys {
+		if k.ID == kid && !k.retired(now) {
+			return k
+		}
+	}
+	return nil
+}
+
+// verificationKey finds the public key for kid, reloading the keyring once if it is unknown
+func (r *keyring) verificationKey(kid, alg string) (interface{}, error) {
+	if kid == "" {
+		return nil, ErrInvalidToken
+	}
+	now := time.Now()
+	key := r.lookup(kid, now)
+	if key == nil {
+		r.reload(now)
+		key = r.lookup(kid, now)
+	}
+	if key == nil || key.Algorithm != alg {
+		return nil, ErrInvalidToken
+	}
+	return key.Public, nil
+}
+
+func (r *keyring) reload(now time.Time) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+	r.mu.RLock()
+	src, loadedAt := r.source, r.loadedAt
+	r.mu.RUnlock()
+	if src == nil || now.Sub(loadedAt) < keyReloadEvery {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	keys, err := src(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loadedAt = now // 실패해도 간격은 지킨다
+	if err == nil {
+		r.keys = keys
+	}
+}
+
+// GenerateSigningKey creates a new key pair. kid 는 공개키의 RFC 7638 thumbprint 다.
+func GenerateSigningKey(alg string) (*SigningKey, error) {
+	var key *SigningKey
+	switch alg {
+	case AlgES256:
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key = &SigningKey{Algorithm: alg, Private: priv, Public: &priv.PublicKey}
+	case AlgEdDSA:
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key = &SigningKey{Algorithm: alg, Private: priv, Public: pub}
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	kid, err := Thumbprint(key.Public)
+	if err != nil {
+		return nil, err
+	}
+	key.ID = kid
+	return key, nil
+}
+
+// JWK is a public JSON Web Key (EC P-256 또는 OKP Ed25519)
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// JWKSet is the /.well-known/jwks.json document
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys verifiers should trust: 활성 키, 미리 공개된 다음 키, 아직 은퇴하지 않은 이전 키.
+func (m *Manager) JWKS() JWKSet {
+	now := time.Now()
+	m.keys.mu.RLock()
+	defer m.keys.mu.RUnlock()
+	set := JWKSet{Keys: []JWK{}}
+	for _, k := range m.keys.keys {
+		if k.retired(now) {
+			continue
+		}
+		jwk, err := publicJWK(k.Public)
+		if err != nil {
+			continue
+		}
+		jwk.Use, jwk.Alg, jwk.Kid = "sig", k.Algorithm, k.ID
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// Thumbprint returns the RFC 7638 JWK thumbprint of a public key
+func Thumbprint(pub crypto.PublicKey) (string, error) {
+	jwk, err := publicJWK(pub)
+	if err != nil {
+		return "", err
+	}
+	// 필수 멤버만 사전순으로 (RFC 7638 §3.2)
+	var canonical string
+	if jwk.Kty == "EC" {
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, jwk.Crv, jwk.X, jwk.Y)
+	} else {
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, jwk.Crv, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func publicJWK(pub crypto.PublicKey) (JWK, error) {
+	enc := base64.RawURLEncoding.EncodeToString
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return JWK{}, ErrUnsupportedAlgorithm
+		}
+		// 좌표는 곡선 크기(32바이트)로 앞을 0 으로 채운다 (RFC 7518 §6.2.1.2)
+		x, y := make([]byte, 32), make([]byte, 32)
+		k.X.FillBytes(x)
+		k.Y.FillBytes(y)
+		return JWK{Kty: "EC", Crv: "P-256", X: enc(x), Y: enc(y)}, nil
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: enc(k)}, nil
+	}
+	return JWK{}, ErrUnsupportedAlgorithm
+}
+
+// ParseJWK converts a public JWK back into a verification-only SigningKey
+func ParseJWK(jwk JWK) (*SigningKey, error) {
+	dec := base64.RawURLEncoding.DecodeString
+	key := &SigningKey{ID: jwk.Kid, Algorithm: jwk.Alg}
+	switch {
+	case jwk.Kty == "EC" && jwk.Crv == "P-256":
+		x, errX := dec(jwk.X)
+		y, errY := dec(jwk.Y)
+		if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return nil, ErrInvalidToken
+		}
+		// 곡선 밖의 점은 거절한다 (비압축 점 형식으로 crypto/ecdh 가 확인)
+		if _, err := ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, ErrInvalidToken
+		}
+		key.Public = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if key.Algorithm == "" {
+			key.Algorithm = AlgES256
+		}
+	case jwk.Kty == "OKP" && jwk.Crv == "Ed25519":
+		x, err := dec(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, ErrInvalidToken
+		}
+		key.Public = ed25519.PublicKey(x)
+		if key.Algorithm == "" {
+			key.Algorithm = AlgEdDSA
+		}
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if key.ID == "" {
+		return nil, ErrInvalidToken
+	}
+	return key, nil
+}
+
+// JWKSSource loads verification keys from a JWKS URL (게임 서버 등 공개키로만 검증하는 곳).
+// 모르는 kid·모르는 kty 의 키는 건너뛴다.
+func JWKSSource(url string, client *http.Client) KeySource {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return func(ctx context.Context) ([]*SigningKey, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("jwks: unexpected status %d", resp.StatusCode)
+		}
+		var set JWKSet
+		if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+			return nil, fmt.Errorf("jwks: %w", err)
+		}
+		keys := make([]*SigningKey, 0, len(set.Keys))
+		for _, jwk := range set.Keys {
+			if jwk.Use != "" && jwk.Use != "sig" {
+				continue
+			}
+			if key, err := ParseJWK(jwk); err == nil {
+				keys = append(keys, key)
+			}
+		}
+		return keys, nil
+	}
+}
@@ -0,0 +1,168 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func mustKey(t *testing.T, alg string, activatesAt time.Time) *SigningKey {
+	t.Helper()
+	key, err := GenerateSigningKey(alg)
+	if err != nil {
+		t.Fatalf("generate %s: %v", alg, err)
+	}
+	key.ActivatesAt = activatesAt
+	return key
+}
+
+func tokenHeader(t *testing.T, raw string) map[string]interface{} {
+	t.Helper()
+	tok, _, err := jwt.NewParser().ParseUnverified(raw, &Claims{})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return tok.Header
+}
+
+func TestAsymmetricSigning(t *testing.T) {
+	for _, alg := range []string{AlgES256, AlgEdDSA} {
+		m := NewManager("secret", 900, 3600)
+		key := mustKey(t, alg, time.Now().Add(-time.Hour))
+		m.SetSigningKeys([]*SigningKey{key})
+
+		raw, err := m.GenerateAccessToken("zoe", "zoe", "조", 2)
+		if err != nil {
+			t.Fatalf("%s sign: %v", alg, err)
+		}
+		if h := tokenHeader(t, raw); h["alg"] != alg || h["kid"] != key.ID {
+			t.Fatalf("%s: unexpected header %v", alg, h)
+		}
+		claims, err := m.VerifyToken(raw)
+		if err != nil || claims.UserID != "zoe" {
+			t.Fatalf("%s verify: %+v %v", alg, claims, err)
+		}
+	}
+}
+
+func TestHS256DuringMigration(t *testing.T) {
+	m := NewManager("secret", 900, 3600)
+	legacy, _ := m.GenerateAccessToken("zoe", "zoe", "조", 2)
+	if h := tokenHeader(t, legacy); h["alg"] != "HS256" {
+		t.Fatalf("no keyring should still sign HS256, got %v", h)
+	}
+
+	m.SetSigningKeys([]*SigningKey{mustKey(t, AlgES256, time.Now().Add(-time.Minute))})
+	if _, err := m.VerifyToken(legacy); err != nil {
+		t.Fatalf("HS256 token must be accepted during migration: %v", err)
+	}
+	m.SetAcceptHS256(false)
+	if _, err := m.VerifyToken(legacy); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("HS256 must be rejected once disabled, got %v", err)
+	}
+}
+
+func TestKeyRotationOverlap(t *testing.T) {
+	now := time.Now()
+	old := mustKey(t, AlgES256, now.Add(-48*time.Hour))
+	next := mustKey(t, AlgES256, now.Add(time.Hour))
+	m := NewManager("secret", 900, 3600)
+	m.SetSigningKeys([]*SigningKey{old, next})
+
+	// 다음 키는 활성 전이라 서명엔 안 쓰지만 JWKS 에는 미리 실린다
+	raw, _ := m.GenerateAccessToken("zoe", "zoe", "조", 2)
+	if tokenHeader(t, raw)["kid"] != old.ID {
+		t.Fatal("a pre-published key must not sign before it activates")
+	}
+	if set := m.JWKS(); len(set.Keys) != 2 {
+		t.Fatalf("expected both keys published, got %+v", set)
+	}
+
+	// 다음 키가 활성되면 그 키로 서명하고, 은퇴 전까지 이전 키 토큰도 받는다
+	next.ActivatesAt = now.Add(-time.Minute)
+	retires := now.Add(time.Hour)
+	old.RetiresAt = &retires
+	if raw2, _ := m.GenerateAccessToken("zoe", "zoe", "조", 2); tokenHeader(t, raw2)["kid"] != next.ID {
+		t.Fatal("the newest active key should sign")
+	}
+	if _, err := m.VerifyToken(raw); err != nil {
+		t.Fatalf("old key inside the overlap window: %v", err)
+	}
+
+	retired := now.Add(-time.Second)
+	old.RetiresAt = &retired
+	if _, err := m.VerifyToken(raw); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("retired key must not verify, got %v", err)
+	}
+	if set := m.JWKS(); len(set.Keys) != 1 || set.Keys[0].Kid != next.ID {
+		t.Fatalf("retired key must leave the JWKS, got %+v", set)
+	}
+}
+
+func TestVerifyRejectsAlgorithmMismatch(t *testing.T) {
+	es := mustKey(t, AlgES256, time.Now().Add(-time.Hour))
+	ed := mustKey(t, AlgEdDSA, time.Now().Add(-time.Hour))
+	m := NewManager("secret", 900, 3600)
+	m.SetSigningKeys([]*SigningKey{es})
+
+	// EdDSA 로 서명하고 ES256 키의 kid 를 단 토큰
+	tok := jwt.NewWithClaims(jwt.SigningMethodEdDSA, &Claims{UserID: "zoe"})
+	tok.Header["kid"] = es.ID
+	raw, _ := tok.SignedString(ed.Private)
+	if _, err := m.VerifyToken(raw); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestJWKSSource(t *testing.T) {
+	issuer := NewManager("", 900, 3600)
+	issuer.SetSigningKeys([]*SigningKey{mustKey(t, AlgEdDSA, time.Now().Add(-time.Hour))})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(issuer.JWKS())
+	}))
+	defer srv.Close()
+
+	// 게임 서버처럼 공개키로만 검증한다 — HS256 시크릿이 없다
+	verifier := NewManager("", 900, 3600)
+	verifier.SetKeySource(JWKSSource(srv.URL, nil))
+	if err := verifier.ReloadKeys(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	raw, _ := issuer.GenerateAccessToken("zoe", "zoe", "조", 2)
+	if claims, err := verifier.VerifyToken(raw); err != nil || claims.UserID != "zoe" {
+		t.Fatalf("verify via JWKS: %+v %v", claims, err)
+	}
+	// 검증 전용 키로는 서명하지 않는다
+	if _, err := verifier.GenerateAccessToken("zoe", "zoe", "조", 2); !errors.Is(err, ErrNoSigningKey) {
+		t.Fatal("a verifier without a secret or private key must not sign")
+	}
+
+	// 발급 측이 키를 바꾸면 모르는 kid 로 다시 받는다 (간격 제한 이후)
+	issuer.SetSigningKeys([]*SigningKey{mustKey(t, AlgEdDSA, time.Now().Add(-time.Minute))})
+	raw2, _ := issuer.GenerateAccessToken("zoe", "zoe", "조", 2)
+	verifier.keys.loadedAt = time.Now().Add(-2 * keyReloadEvery)
+	if _, err := verifier.VerifyToken(raw2); err != nil {
+		t.Fatalf("rotated key should be fetched: %v", err)
+	}
+}
+
+func TestThumbprintKid(t *testing.T) {
+	key := mustKey(t, AlgES256, time.Time{})
+	m := NewManager("", 0, 0)
+	m.SetSigningKeys([]*SigningKey{key})
+	set := m.JWKS()
+	parsed, err := ParseJWK(set.Keys[0])
+	if err != nil {
+		t.Fatalf("ParseJWK: %v", err)
+	}
+	if kid, _ := Thumbprint(parsed.Public); kid != key.ID || strings.ContainsAny(kid, "+/=") {
+		t.Fatalf("thumbprint %q != kid %q", kid, key.ID)
+	}
+}
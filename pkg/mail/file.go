@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// FileTransport writes each message to Dir as an .eml file instead of sending it
+// (로컬 개발·테스트용 — 메일 클라이언트로 열어 볼 수 있다)
+type FileTransport struct {
+	Dir string
+	seq atomic.Uint64
+}
+
+// NewFileTransport creates the directory and returns a FileTransport
+func NewFileTransport(dir string) (*FileTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileTransport{Dir: dir}, nil
+}
+
+// Send implements Transport
+func (t *FileTransport) Send(_ context.Context, msg *Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%04d.eml", time.Now().Format("20060102-150405.000"), t.seq.Add(1))
+	return os.WriteFile(filepath.Join(t.Dir, name), data, 0o644)
+}
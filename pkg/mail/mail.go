@@ -0,0 +1,137 @@
+// Package mail builds MIME messages (text + HTML, multipart/alternative) and delivers them through a
+// pluggable Transport — SMTP 로 실제 발송하거나, 개발·테스트에서는 FileTransport 로 .eml 파일에 쓴다.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Message is one outgoing email
+type Message struct {
+	From    string // "이름 <주소>" 또는 주소
+	To      string
+	Subject string
+	Text    string // text/plain 본문 (필수)
+	HTML    string // text/html 본문 (비우면 text 만 보낸다)
+	// Headers 는 추가 헤더다 (예: List-Unsubscribe). 개행이 든 값은 Bytes 가 거절한다.
+	Headers map[string]string
+}
+
+// Transport delivers messages
+type Transport interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// PermanentError is a rejection that will not succeed on retry (SMTP 5xx — 없는 주소, 수신 거부 등).
+// 발송자는 이 주소를 수신 거부 목록에 올린다.
+type PermanentError struct {
+	Code int
+	Err  error
+}
+
+func (e *PermanentError) Error() string {
+	return fmt.Sprintf("mail: permanent failure (%d): %v", e.Code, e.Err)
+}
+
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// IsPermanent reports whether err is a PermanentError
+func IsPermanent(err error) bool {
+	var pe *PermanentError
+	return errors.As(err, &pe)
+}
+
+// ErrInvalidMessage is returned for messages that cannot be sent as given
+var ErrInvalidMessage = errors.New("mail: invalid message")
+
+// Bytes renders the message as RFC 5322 with CRLF line endings
+func (m *Message) Bytes() ([]byte, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from: %v", ErrInvalidMessage, err)
+	}
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to: %v", ErrInvalidMessage, err)
+	}
+	if m.Text == "" {
+		return nil, fmt.Errorf("%w: text body is required", ErrInvalidMessage)
+	}
+
+	var b bytes.Buffer
+	header := func(k, v string) {
+		b.WriteString(k + ": " + v + "\r\n")
+	}
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+randomToken()+"@"+domainOf(from.Address)+">")
+	header("MIME-Version", "1.0")
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := m.Headers[k]
+		if strings.ContainsAny(k+v, "\r\n") || strings.Contains(k, ":") {
+			return nil, fmt.Errorf("%w: header %q", ErrInvalidMessage, k)
+		}
+		header(k, v)
+	}
+
+	if m.HTML == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		b.WriteString("\r\n")
+		writeQP(&b, m.Text)
+		return b.Bytes(), nil
+	}
+
+	boundary := "alt-" + randomToken()
+	header("Content-Type", `multipart/alternative; boundary="`+boundary+`"`)
+	b.WriteString("\r\n")
+	for _, part := range []struct{ typ, body string }{{"text/plain", m.Text}, {"text/html", m.HTML}} {
+		b.WriteString("--" + boundary + "\r\n")
+		header("Content-Type", part.typ+"; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		b.WriteString("\r\n")
+		writeQP(&b, part.body)
+		b.WriteString("\r\n")
+	}
+	b.WriteString("--" + boundary + "--\r\n")
+	return b.Bytes(), nil
+}
+
+// writeQP writes body quoted-printable with CRLF line endings
+func writeQP(b *bytes.Buffer, body string) {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	w := quotedprintable.NewWriter(b)
+	_, _ = w.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n")))
+	_ = w.Close()
+}
+
+func randomToken() string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func domainOf(address string) string {
+	if i := strings.LastIndexByte(address, '@'); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
@@ -0,0 +1,97 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMessageBytes(t *testing.T) {
+	msg := &Message{
+		From:    "다모앙 <noreply@damoang.net>",
+		To:      "zoe@example.com",
+		Subject: "포인트 만료 안내",
+		Text:    "7일 내 100P가 만료됩니다\n/point",
+		HTML:    "<p>7일 내 <b>100P</b>가 만료됩니다</p>",
+		Headers: map[string]string{"List-Unsubscribe-Post": "List-Unsubscribe=One-Click"},
+	}
+	data, err := msg.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if subject != msg.Subject || parsed.Header.Get("List-Unsubscribe-Post") != "List-Unsubscribe=One-Click" {
+		t.Fatalf("unexpected headers %v", parsed.Header)
+	}
+	if !strings.HasSuffix(parsed.Header.Get("Message-ID"), "@damoang.net>") {
+		t.Fatalf("unexpected Message-ID %q", parsed.Header.Get("Message-ID"))
+	}
+
+	_, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("content type: %v", err)
+	}
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	var got []string
+	for {
+		p, err := parts.NextPart() // quoted-printable 은 NextPart 가 풀어 준다
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		body, _ := io.ReadAll(p)
+		got = append(got, p.Header.Get("Content-Type")+"|"+string(body))
+	}
+	want := []string{
+		"text/plain; charset=utf-8|7일 내 100P가 만료됩니다\r\n/point",
+		"text/html; charset=utf-8|<p>7일 내 <b>100P</b>가 만료됩니다</p>",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected parts:\n%s", strings.Join(got, "\n"))
+	}
+
+	msg.Headers = map[string]string{"X-Evil": "a\r\nBcc: x@example.com"}
+	if _, err := msg.Bytes(); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("header injection should fail, got %v", err)
+	}
+}
+
+func TestFileTransport(t *testing.T) {
+	tr, err := NewFileTransport(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := &Message{From: "noreply@damoang.net", To: "zoe@example.com", Subject: "hi", Text: "hello"}
+	if err := tr.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	entries, _ := os.ReadDir(tr.Dir)
+	if len(entries) != 1 || !strings.HasSuffix(entries[0].Name(), ".eml") {
+		t.Fatalf("expected one .eml, got %v", entries)
+	}
+	if err := tr.Send(context.Background(), &Message{From: "noreply@damoang.net", To: "not an address", Text: "x"}); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("invalid recipient should fail, got %v", err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	if !IsPermanent(classify(&textproto.Error{Code: 550, Msg: "no such user"})) {
+		t.Fatal("550 should be permanent")
+	}
+	if IsPermanent(classify(&textproto.Error{Code: 451, Msg: "try later"})) || IsPermanent(classify(io.EOF)) {
+		t.Fatal("4xx and network errors are temporary")
+	}
+}
@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig configures SMTPTransport
+type SMTPConfig struct {
+	Host     string
+	Port     int // 587(STARTTLS) 이 기본. 465 면 처음부터 TLS 로 붙는다
+	Username string
+	Password string // Username 이 있으면 PLAIN 인증 — TLS 위에서만 보낸다
+}
+
+// SMTPTransport sends each message over its own SMTP connection.
+// 발송량이 많지 않은 알림 메일 용도다 — 연결 재사용·풀링은 하지 않는다.
+type SMTPTransport struct {
+	cfg     SMTPConfig
+	timeout time.Duration
+}
+
+// NewSMTPTransport creates a new SMTPTransport
+func NewSMTPTransport(cfg SMTPConfig) *SMTPTransport {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	return &SMTPTransport{cfg: cfg, timeout: 30 * time.Second}
+}
+
+// Send implements Transport. 서버가 5xx 로 거절하면 *PermanentError 를 돌려준다.
+func (t *SMTPTransport) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	from, _ := mail.ParseAddress(msg.From) // Bytes 가 검증했다
+	to, _ := mail.ParseAddress(msg.To)
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	addr := net.JoinHostPort(t.cfg.Host, strconv.Itoa(t.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: t.cfg.Host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	if t.cfg.Port == 465 {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, t.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if t.cfg.Username != "" {
+		// smtp.PlainAuth 는 TLS 가 아니면(로컬호스트 제외) 인증을 거절한다
+		if err := client.Auth(smtp.PlainAuth("", t.cfg.Username, t.cfg.Password, t.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return classify(err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return classify(err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return classify(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return classify(err)
+	}
+	return client.Quit()
+}
+
+// classify wraps SMTP 5xx replies as PermanentError (4xx 는 일시적 — 다시 시도한다)
+func classify(err error) error {
+	var tp *textproto.Error
+	if errors.As(err, &tp) && tp.Code >= 500 && tp.Code < 600 {
+		return &PermanentError{Code: tp.Code, Err: fmt.Errorf("%s", tp.Msg)}
+	}
+	return err
+}
@@ -0,0 +1,524 @@
+// Package oidc is a small OpenID Connect relying-party client: discovery, authorization code
+// flow with PKCE (S256) and nonce, and id_token verification against the provider's JWKS.
+//
+// 디스커버리가 없는 OAuth2 제공자(GitHub 등)도 엔드포인트를 직접 적으면 쓸 수 있다 —
+// 그때 신원은 id_token 대신 userinfo 응답에서 SubjectClaim 으로 읽는다.
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 제공자 통신 설정
+const (
+	discoveryPath    = "/.well-known/openid-configuration"
+	discoveryTTL     = time.Hour
+	jwksRefreshEvery = time.Minute // 모르는 kid 로 JWKS 를 다시 받는 최소 간격 (키 롤오버 대응)
+	clockSkew        = time.Minute
+	maxResponseBytes = 1 << 20
+)
+
+// Errors
+var (
+	ErrInvalidConfig  = errors.New("oidc: invalid provider config")
+	ErrInvalidIDToken = errors.New("oidc: invalid id_token")
+	ErrNoIdentity     = errors.New("oidc: provider returned no subject")
+)
+
+// Config describes one provider. Issuer 가 있으면 디스커버리로 엔드포인트를 채우고,
+// 직접 적은 엔드포인트가 그보다 우선한다.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string // 비우면 openid email profile
+
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+	JWKSURL     string
+
+	// Claim names for the identity (userinfo 응답이나 id_token) — 비우면 표준 클레임
+	SubjectClaim string // sub
+	EmailClaim   string // email
+	NameClaim    string // name
+	PictureClaim string // picture
+
+	// ResponseMode "form_post" 면 제공자가 콜백을 POST 로 보낸다(Apple)
+	ResponseMode string
+}
+
+// Token is the token endpoint response
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// Identity is who the provider says the user is
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+	Claims        map[string]interface{} // id_token 클레임(없으면 userinfo 응답)
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is a configured relying party for one issuer (동시 사용 안전)
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu           sync.Mutex
+	meta         discovery
+	discoveredAt time.Time
+	keys         map[string]interface{}
+	keysAt       time.Time
+}
+
+// New validates cfg and returns a Provider. 네트워크는 첫 사용 때 탄다(디스커버리는 지연 로딩).
+func New(cfg Config) (*Provider, error) {
+	if cfg.ClientID == "" {
+		return nil, fmt.Errorf("%w: client_id is required", ErrInvalidConfig)
+	}
+	if cfg.Issuer == "" && (cfg.AuthURL == "" || cfg.TokenURL == "") {
+		return nil, fmt.Errorf("%w: issuer or auth_url and token_url are required", ErrInvalidConfig)
+	}
+	if cfg.Issuer == "" && cfg.UserInfoURL == "" && cfg.JWKSURL == "" {
+		return nil, fmt.Errorf("%w: without an issuer, userinfo_url is required", ErrInvalidConfig)
+	}
+	for _, u := range []string{cfg.Issuer, cfg.AuthURL, cfg.TokenURL, cfg.UserInfoURL, cfg.JWKSURL} {
+		if u == "" {
+			continue
+		}
+		if parsed, err := url.Parse(u); err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Hostname() != "localhost" && parsed.Hostname() != "127.0.0.1") {
+			return nil, fmt.Errorf("%w: %q must be an https URL", ErrInvalidConfig, u)
+		}
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if cfg.SubjectClaim == "" {
+		cfg.SubjectClaim = "sub"
+	}
+	if cfg.EmailClaim == "" {
+		cfg.EmailClaim = "email"
+	}
+	if cfg.NameClaim == "" {
+		cfg.NameClaim = "name"
+	}
+	if cfg.PictureClaim == "" {
+		cfg.PictureClaim = "picture"
+	}
+	return &Provider{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// SetHTTPClient replaces the client used to talk to the provider (테스트·프록시)
+func (p *Provider) SetHTTPClient(c *http.Client) {
+	p.client = c
+}
+
+// Config returns the provider configuration (기본값이 채워진 상태)
+func (p *Provider) Config() Config {
+	return p.cfg
+}
+
+// PKCEChallenge returns the S256 code_challenge of verifier
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// endpoints returns the effective endpoints, running discovery when an issuer is set
+func (p *Provider) endpoints(ctx context.Context) (discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cfg.Issuer != "" && time.Since(p.discoveredAt) > discoveryTTL {
+		var doc discovery
+		if err := p.getJSON(ctx, strings.TrimSuffix(p.cfg.Issuer, "/")+discoveryPath, "", &doc); err != nil {
+			if !p.discoveredAt.IsZero() {
+				return p.meta, nil // 이전 문서로 버틴다 — 제공자 일시 장애로 로그인을 막지 않게
+			}
+			return discovery{}, fmt.Errorf("oidc discovery: %w", err)
+		}
+		// 디스커버리 문서의 issuer 는 설정과 같아야 한다(OIDC Discovery §4.3) — 다른 발급자 문서로 바꿔치기 방지
+		if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(p.cfg.Issuer, "/") {
+			return discovery{}, fmt.Errorf("%w: discovery issuer %q does not match %q", ErrInvalidConfig, doc.Issuer, p.cfg.Issuer)
+		}
+		p.meta, p.discoveredAt = doc, time.Now()
+	}
+	meta := p.meta
+	if p.cfg.Issuer == "" {
+		meta = discovery{}
+	}
+	for _, o := range []struct {
+		dst *string
+		v   string
+	}{
+		{&meta.AuthorizationEndpoint, p.cfg.AuthURL},
+		{&meta.TokenEndpoint, p.cfg.TokenURL},
+		{&meta.UserInfoEndpoint, p.cfg.UserInfoURL},
+		{&meta.JWKSURI, p.cfg.JWKSURL},
+	} {
+		if o.v != "" {
+			*o.dst = o.v
+		}
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" {
+		return discovery{}, fmt.Errorf("%w: provider has no authorization or token endpoint", ErrInvalidConfig)
+	}
+	return meta, nil
+}
+
+// AuthCodeURL returns the authorization request URL with state, nonce and a PKCE S256 challenge
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	meta, err := p.endpoints(ctx)
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"code_challenge":        {PKCEChallenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	if nonce != "" {
+		params.Set("nonce", nonce)
+	}
+	if p.cfg.ResponseMode != "" {
+		params.Set("response_mode", p.cfg.ResponseMode)
+	}
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange trades an authorization code (and its PKCE verifier) for tokens
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	meta, err := p.endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json") // GitHub 은 이게 없으면 form 인코딩으로 답한다
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Token
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("oidc token response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if out.Error != "" {
+		return nil, fmt.Errorf("oidc token error: %s %s", out.Error, out.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || out.AccessToken == "" {
+		return nil, fmt.Errorf("oidc token endpoint returned HTTP %d", resp.StatusCode)
+	}
+	return &out.Token, nil
+}
+
+// Identify resolves the user behind tok. id_token 이 있으면 검증해 쓰고(nonce 포함),
+// 이메일·이름이 빠졌거나 id_token 이 없는 제공자는 userinfo 로 채운다.
+func (p *Provider) Identify(ctx context.Context, tok *Token, nonce string) (*Identity, error) {
+	var claims map[string]interface{}
+	if tok.IDToken != "" {
+		c, err := p.VerifyIDToken(ctx, tok.IDToken, nonce)
+		if err != nil {
+			return nil, err
+		}
+		claims = c
+	}
+	meta, err := p.endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := identityFrom(claims, p.cfg)
+	if meta.UserInfoEndpoint != "" && (claims == nil || id.Email == "" || id.Name == "") {
+		var info map[string]interface{}
+		if err := p.getJSON(ctx, meta.UserInfoEndpoint, tok.AccessToken, &info); err != nil {
+			if claims == nil {
+				return nil, fmt.Errorf("oidc userinfo: %w", err)
+			}
+		} else {
+			fromInfo := identityFrom(info, p.cfg)
+			if claims == nil {
+				id, claims = fromInfo, info
+			} else if fromInfo.Subject == id.Subject {
+				// userinfo 의 sub 가 id_token 과 다르면 버린다(OIDC Core §5.3.2)
+				if id.Email == "" {
+					id.Email, id.EmailVerified = fromInfo.Email, fromInfo.EmailVerified
+				}
+				if id.Name == "" {
+					id.Name = fromInfo.Name
+				}
+				if id.Picture == "" {
+					id.Picture = fromInfo.Picture
+				}
+			}
+		}
+	}
+	if claims == nil {
+		return nil, fmt.Errorf("%w: no id_token and no userinfo endpoint", ErrNoIdentity)
+	}
+	if id.Subject == "" {
+		return nil, ErrNoIdentity
+	}
+	id.Claims = claims
+	return id, nil
+}
+
+func identityFrom(claims map[string]interface{}, cfg Config) *Identity {
+	id := &Identity{}
+	if claims == nil {
+		return id
+	}
+	id.Subject = claimString(claims[cfg.SubjectClaim])
+	id.Email = claimString(claims[cfg.EmailClaim])
+	id.Name = claimString(claims[cfg.NameClaim])
+	id.Picture = claimString(claims[cfg.PictureClaim])
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		id.EmailVerified = v
+	case string: // Apple 은 "true" 문자열로 준다
+		id.EmailVerified = v == "true"
+	}
+	return id
+}
+
+// claimString renders a string or numeric claim (GitHub id 는 숫자다)
+func claimString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case float64:
+		return big.NewFloat(s).Text('f', -1)
+	case json.Number:
+		return s.String()
+	}
+	return ""
+}
+
+// VerifyIDToken checks the signature (JWKS), issuer, audience, expiry and nonce of an id_token
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (map[string]interface{}, error) {
+	meta, err := p.endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: provider has no jwks_uri", ErrInvalidIDToken)
+	}
+	issuer := meta.Issuer
+	if issuer == "" {
+		issuer = p.cfg.Issuer
+	}
+	opts := []jwt.ParserOption{
+		// 비대칭 알고리즘만 — HS256 을 허용하면 client_secret 을 아는 누구나 토큰을 만든다
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string) //nolint:errcheck // 없으면 키가 하나뿐인 JWKS 에서 고른다
+		return p.key(ctx, meta.JWKSURI, kid)
+	}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	// 여러 audience 면 azp 가 우리여야 한다(OIDC Core §3.1.3.7)
+	if aud, _ := claims.GetAudience(); len(aud) > 1 { //nolint:errcheck // 위에서 검증됨
+		if azp, _ := claims["azp"].(string); azp != p.cfg.ClientID { //nolint:errcheck // 타입 단언
+			return nil, fmt.Errorf("%w: azp does not match client_id", ErrInvalidIDToken)
+		}
+	}
+	if nonce != "" {
+		if got, _ := claims["nonce"].(string); got != nonce { //nolint:errcheck // 타입 단언
+			return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+		}
+	}
+	return claims, nil
+}
+
+// key returns the verification key for kid, refetching the JWKS once for an unknown kid
+func (p *Provider) key(ctx context.Context, jwksURI, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	lookup := func() (interface{}, bool) {
+		if kid == "" && len(p.keys) == 1 {
+			for _, k := range p.keys {
+				return k, true
+			}
+		}
+		k, ok := p.keys[kid]
+		return k, ok
+	}
+	if k, ok := lookup(); ok {
+		return k, nil
+	}
+	if time.Since(p.keysAt) < jwksRefreshEvery {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, jwksURI, "", &set); err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if pub, err := k.publicKey(); err == nil {
+			keys[k.Kid] = pub
+		}
+	}
+	p.keys, p.keysAt = keys, time.Now()
+	if k, ok := lookup(); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	b64 := base64.RawURLEncoding.DecodeString
+	switch k.Kty {
+	case "RSA":
+		n, err := b64(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() > 1<<31-1 || exp.Int64() < 3 {
+			return nil, errors.New("bad RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := b64(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) { //nolint:staticcheck // 공개키 검증 — crypto/ecdh 로는 ecdsa 키를 못 만든다
+			return nil, errors.New("EC point is not on the curve")
+		}
+		return pub, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func (p *Provider) getJSON(ctx context.Context, endpoint, bearer string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned HTTP %d", endpoint, resp.StatusCode)
+	}
+	dec := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes))
+	dec.UseNumber()
+	return dec.Decode(out)
+}
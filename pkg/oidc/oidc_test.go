@@ -0,0 +1,258 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeIdP is a minimal OpenID provider: discovery, JWKS, token (PKCE 확인) and userinfo
+type fakeIdP struct {
+	t         *testing.T
+	srv       *httptest.Server
+	key       *rsa.PrivateKey
+	kid       string
+	challenge string // 마지막 인가 요청의 code_challenge
+	claims    jwt.MapClaims
+	userinfo  map[string]interface{}
+	issuer    string // 디스커버리 문서에 적는 issuer (비우면 서버 URL)
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	idp := &fakeIdP{t: t, key: key, kid: "k1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		issuer := idp.issuer
+		if issuer == "" {
+			issuer = idp.srv.URL
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": idp.srv.URL + "/authorize",
+			"token_endpoint":         idp.srv.URL + "/token",
+			"userinfo_endpoint":      idp.srv.URL + "/userinfo",
+			"jwks_uri":               idp.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		enc := base64.RawURLEncoding.EncodeToString
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": idp.kid, "use": "sig",
+			"n": enc(idp.key.N.Bytes()), "e": enc(big.NewInt(int64(idp.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("code") != "good-code" || PKCEChallenge(r.Form.Get("code_verifier")) != idp.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		resp := map[string]interface{}{"access_token": "at-1", "token_type": "Bearer"}
+		if idp.claims != nil {
+			resp["id_token"] = idp.sign(idp.claims)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer at-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(idp.userinfo)
+	})
+	idp.srv = httptest.NewServer(mux)
+	t.Cleanup(idp.srv.Close)
+	return idp
+}
+
+func (idp *fakeIdP) sign(claims jwt.MapClaims) string {
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = idp.kid
+	raw, err := tok.SignedString(idp.key)
+	if err != nil {
+		idp.t.Fatalf("sign: %v", err)
+	}
+	return raw
+}
+
+func (idp *fakeIdP) baseClaims(nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss": idp.srv.URL, "aud": "client-1", "sub": "user-77", "nonce": nonce,
+		"email": "zoe@example.com", "email_verified": true,
+		"iat": now.Unix(), "exp": now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+// authorize runs AuthCodeURL and records the PKCE challenge the way the IdP would
+func (idp *fakeIdP) authorize(t *testing.T, p *Provider, nonce, verifier string) url.Values {
+	t.Helper()
+	raw, err := p.AuthCodeURL(context.Background(), "st", nonce, verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	u, _ := url.Parse(raw)
+	q := u.Query()
+	idp.challenge = q.Get("code_challenge")
+	return q
+}
+
+func TestProviderCodeFlow(t *testing.T) {
+	idp := newFakeIdP(t)
+	p, err := New(Config{Issuer: idp.srv.URL, ClientID: "client-1", RedirectURL: "https://app.test/cb"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q := idp.authorize(t, p, "n-1", "verifier-1")
+	if q.Get("code_challenge_method") != "S256" || q.Get("nonce") != "n-1" || q.Get("scope") != "openid email profile" {
+		t.Fatalf("unexpected authorization request %v", q)
+	}
+
+	// 다른 verifier 로는 코드를 바꿀 수 없다 (PKCE)
+	if _, err := p.Exchange(context.Background(), "good-code", "verifier-2"); err == nil {
+		t.Fatal("exchange with a wrong verifier should fail")
+	}
+	idp.claims = idp.baseClaims("n-1")
+	idp.userinfo = map[string]interface{}{"sub": "user-77", "name": "조"}
+	tok, err := p.Exchange(context.Background(), "good-code", "verifier-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	id, err := p.Identify(context.Background(), tok, "n-1")
+	if err != nil {
+		t.Fatalf("Identify: %v", err)
+	}
+	if id.Subject != "user-77" || id.Email != "zoe@example.com" || !id.EmailVerified || id.Name != "조" {
+		t.Fatalf("unexpected identity %+v", id)
+	}
+
+	// userinfo 의 sub 가 다르면 그 응답은 섞지 않는다
+	idp.userinfo = map[string]interface{}{"sub": "someone-else", "name": "남"}
+	if id, err := p.Identify(context.Background(), tok, "n-1"); err != nil || id.Name != "" {
+		t.Fatalf("mismatched userinfo must be ignored: %+v %v", id, err)
+	}
+}
+
+func TestVerifyIDTokenRejects(t *testing.T) {
+	idp := newFakeIdP(t)
+	p, _ := New(Config{Issuer: idp.srv.URL, ClientID: "client-1"})
+	ctx := context.Background()
+
+	cases := map[string]func(c jwt.MapClaims){
+		"wrong nonce":    func(c jwt.MapClaims) { c["nonce"] = "other" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "client-2" },
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.test" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"foreign azp":    func(c jwt.MapClaims) { c["aud"] = []string{"client-1", "client-2"}; c["azp"] = "client-2" },
+	}
+	for name, mutate := range cases {
+		claims := idp.baseClaims("n")
+		mutate(claims)
+		if _, err := p.VerifyIDToken(ctx, idp.sign(claims), "n"); !errors.Is(err, ErrInvalidIDToken) {
+			t.Errorf("%s: expected ErrInvalidIDToken, got %v", name, err)
+		}
+	}
+
+	// client_secret 으로 서명한 HS256 토큰은 받지 않는다
+	hs, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, idp.baseClaims("n")).SignedString([]byte("client-secret"))
+	if _, err := p.VerifyIDToken(ctx, hs, "n"); !errors.Is(err, ErrInvalidIDToken) {
+		t.Errorf("HS256 id_token must be rejected, got %v", err)
+	}
+	if _, err := p.VerifyIDToken(ctx, idp.sign(idp.baseClaims("n")), "n"); err != nil {
+		t.Fatalf("valid token: %v", err)
+	}
+}
+
+func TestVerifyIDTokenKeyRotation(t *testing.T) {
+	idp := newFakeIdP(t)
+	p, _ := New(Config{Issuer: idp.srv.URL, ClientID: "client-1"})
+	ctx := context.Background()
+	if _, err := p.VerifyIDToken(ctx, idp.sign(idp.baseClaims("")), ""); err != nil {
+		t.Fatalf("first key: %v", err)
+	}
+
+	newKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	idp.key, idp.kid = newKey, "k2"
+	// 방금 받은 JWKS 는 바로 다시 받지 않는다 (모르는 kid 로 제공자를 두드리지 않게)
+	if _, err := p.VerifyIDToken(ctx, idp.sign(idp.baseClaims("")), ""); err == nil {
+		t.Fatal("unknown kid inside the refresh interval should fail")
+	}
+	p.keysAt = time.Now().Add(-2 * jwksRefreshEvery)
+	if _, err := p.VerifyIDToken(ctx, idp.sign(idp.baseClaims("")), ""); err != nil {
+		t.Fatalf("rotated key should be fetched: %v", err)
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	idp := newFakeIdP(t)
+	idp.issuer = "https://other.test"
+	p, _ := New(Config{Issuer: idp.srv.URL, ClientID: "client-1"})
+	if _, err := p.AuthCodeURL(context.Background(), "s", "n", "v"); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected issuer mismatch, got %v", err)
+	}
+}
+
+func TestPlainOAuth2Provider(t *testing.T) {
+	idp := newFakeIdP(t)
+	p, err := New(Config{
+		ClientID:     "client-1",
+		AuthURL:      idp.srv.URL + "/authorize",
+		TokenURL:     idp.srv.URL + "/token",
+		UserInfoURL:  idp.srv.URL + "/userinfo",
+		Scopes:       []string{"read:user"},
+		SubjectClaim: "id",
+		PictureClaim: "avatar_url",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q := idp.authorize(t, p, "", "v")
+	if q.Has("nonce") || q.Get("scope") != "read:user" {
+		t.Fatalf("unexpected authorization request %v", q)
+	}
+	idp.userinfo = map[string]interface{}{"id": 1234567, "login": "zoe", "avatar_url": "https://a.test/z.png"}
+	tok, err := p.Exchange(context.Background(), "good-code", "v")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	id, err := p.Identify(context.Background(), tok, "")
+	if err != nil || id.Subject != "1234567" || id.Picture != "https://a.test/z.png" {
+		t.Fatalf("unexpected identity %+v %v", id, err)
+	}
+}
+
+func TestNewRejectsInsecureEndpoints(t *testing.T) {
+	for _, cfg := range []Config{
+		{ClientID: "c", Issuer: "http://idp.example.com"},
+		{ClientID: "", Issuer: "https://idp.example.com"},
+		{ClientID: "c", AuthURL: "https://a.example.com", TokenURL: "https://t.example.com"},
+	} {
+		if _, err := New(cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%+v: expected ErrInvalidConfig, got %v", cfg, err)
+		}
+	}
+}
+
+// RFC 7636 Appendix B
+func TestPKCEChallenge(t *testing.T) {
+	if got := PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); got != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("unexpected PKCE challenge %q", got)
+	}
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned when an object does not exist in the backend
+var ErrNotFound = errors.New("storage: object not found")
+
+// Backend is an object store for uploaded media.
+// S3Client(S3/R2/MinIO)와 LocalBackend(로컬 디스크 + 서명 URL 핸들러)가 구현한다.
+// key 는 Upload 가 돌려준 UploadResult.Key 다 — DB 에 저장되는 값이라 백엔드를 옮겨도 그대로 쓴다.
+type Backend interface {
+	Upload(ctx context.Context, key string, body io.Reader, contentType string, size int64) (*UploadResult, error)
+	// Open returns the object's content; 호출자가 Body 를 닫는다
+	Open(ctx context.Context, key string) (*Object, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+	// List calls fn for every key under prefix (빈 prefix 는 전체)
+	List(ctx context.Context, prefix string, fn func(key string) error) error
+	// GetCDNURL returns the long-lived public URL of key (본문에 박히는 주소)
+	GetCDNURL(key string) string
+	// GetPresignedURL returns a URL that stops working after expiry
+	GetPresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// Object is an opened stored object
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+var (
+	_ Backend = (*S3Client)(nil)
+	_ Backend = (*LocalBackend)(nil)
+)
+
+// Config selects and configures a Backend (config.StorageConfig.Backend 로 고른다)
+type Config struct {
+	Backend string // s3(기본) | local
+	S3      S3Config
+	Local   LocalConfig
+}
+
+// New creates the configured Backend
+func New(cfg Config) (Backend, error) {
+	switch cfg.Backend {
+	case "", "s3":
+		if cfg.S3.Bucket == "" {
+			return nil, errors.New("s3 storage: bucket is required")
+		}
+		return NewS3Client(cfg.S3)
+	case "local":
+		return NewLocalBackend(cfg.Local)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (s3 | local)", cfg.Backend)
+	}
+}
@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	pkglogger "github.com/damoang/angple-backend/pkg/logger"
+)
+
+var (
+	// ErrInvalidKey is returned for keys that are empty or escape the storage root
+	ErrInvalidKey = errors.New("storage: invalid key")
+	// ErrInvalidSignature is returned when a local file URL was not signed by this server
+	ErrInvalidSignature = errors.New("storage: invalid signature")
+	// ErrURLExpired is returned when a presigned local file URL is past its expiry
+	ErrURLExpired = errors.New("storage: url expired")
+)
+
+// localSigBytes 는 URL 에 붙이는 HMAC 길이다 (128bit — 본문에 박히는 주소라 짧게)
+const localSigBytes = 16
+
+// LocalBackend stores media on the local filesystem and serves it through signed URLs.
+// S3·MinIO 가 없는 자체 호스팅과 개발·테스트 환경용이다. 파일은 API 서버가 직접 내려 주며
+// (handler.LocalStorageHandler), URL 의 서명이 맞아야 열린다 — 디렉터리를 훑거나 key 를 바꿔 보는 요청을 막는다.
+type LocalBackend struct {
+	root      string
+	publicURL string // 서빙 핸들러의 주소 (e.g. https://api.example.com/api/v2/media/files)
+	key       []byte
+}
+
+// LocalConfig holds local-disk storage configuration
+type LocalConfig struct {
+	Root       string // 파일을 둘 디렉터리
+	PublicURL  string // 서빙 핸들러 주소, 끝의 / 는 무시
+	SigningKey string // URL 서명 키 — 바뀌면 이미 나간 주소가 모두 막힌다
+}
+
+// NewLocalBackend creates the root directory if needed and returns a LocalBackend
+func NewLocalBackend(cfg LocalConfig) (*LocalBackend, error) {
+	if cfg.Root == "" {
+		return nil, errors.New("local storage: root path is required")
+	}
+	if cfg.SigningKey == "" {
+		return nil, errors.New("local storage: signing key is required")
+	}
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, fmt.Errorf("local storage: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("local storage: create root: %w", err)
+	}
+
+	pkglogger.GetLogger().Info().
+		Str("root", root).
+		Str("public_url", cfg.PublicURL).
+		Msg("local storage initialized")
+
+	return &LocalBackend{
+		root:      root,
+		publicURL: strings.TrimRight(cfg.PublicURL, "/"),
+		key:       []byte(cfg.SigningKey),
+	}, nil
+}
+
+// filePath maps a key to its path under root — 정규화된 상대 경로만 받는다 ("..", 절대 경로, "//" 거부)
+func (b *LocalBackend) filePath(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key {
+		return "", ErrInvalidKey
+	}
+	if key == ".." || strings.HasPrefix(key, "../") {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(b.root, filepath.FromSlash(key)), nil
+}
+
+// Upload writes the file atomically (임시 파일에 쓴 뒤 rename — 읽는 쪽이 반쯤 쓴 파일을 보지 않는다)
+func (b *LocalBackend) Upload(_ context.Context, key string, body io.Reader, contentType string, _ int64) (*UploadResult, error) {
+	dst, err := b.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return nil, fmt.Errorf("local upload failed: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("local upload failed: %w", err)
+	}
+	written, copyErr := io.Copy(tmp, body)
+	closeErr := tmp.Close()
+	if copyErr == nil {
+		copyErr = closeErr
+	}
+	if copyErr == nil {
+		copyErr = os.Chmod(tmp.Name(), 0o644)
+	}
+	if copyErr == nil {
+		copyErr = os.Rename(tmp.Name(), dst)
+	}
+	if copyErr != nil {
+		_ = os.Remove(tmp.Name())
+		return nil, fmt.Errorf("local upload failed: %w", copyErr)
+	}
+
+	fileURL := b.GetCDNURL(key)
+	return &UploadResult{
+		Key:         key,
+		URL:         fileURL,
+		CDNURL:      fileURL,
+		OriginURL:   fileURL,
+		ContentType: contentType,
+		Size:        written,
+	}, nil
+}
+
+// OpenFile opens the stored file for serving (http.ServeContent 에 Range 용 Seek 이 필요해서 *os.File 그대로)
+func (b *LocalBackend) OpenFile(key string) (*os.File, fs.FileInfo, error) {
+	p, err := b.filePath(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		_ = f.Close()
+		if err == nil {
+			err = ErrNotFound
+		}
+		return nil, nil, err
+	}
+	return f, info, nil
+}
+
+// Open returns the stored file (Content-Type 은 확장자로 추정)
+func (b *LocalBackend) Open(_ context.Context, key string) (*Object, error) {
+	f, info, err := b.OpenFile(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Object{
+		Body:        f,
+		ContentType: mime.TypeByExtension(path.Ext(key)),
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+// Exists reports whether key is stored
+func (b *LocalBackend) Exists(_ context.Context, key string) (bool, error) {
+	p, err := b.filePath(key)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// Delete removes a file — 없는 파일은 성공으로 본다 (S3 DeleteObject 와 같다)
+func (b *LocalBackend) Delete(_ context.Context, key string) error {
+	p, err := b.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("local delete failed: %w", err)
+	}
+	return nil
+}
+
+// List walks every file under prefix. prefix 는 S3 처럼 문자열 접두사다 ("images/2026" 은 "images/2026/..." 를 포함).
+func (b *LocalBackend) List(ctx context.Context, prefix string, fn func(key string) error) error {
+	// 접두사가 걸치는 가장 깊은 디렉터리부터 훑는다
+	start := b.root
+	if dir := path.Dir(prefix); prefix != "" && dir != "." {
+		p, err := b.filePath(dir)
+		if err != nil {
+			return err
+		}
+		start = p
+	}
+	err := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(b.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		return fn(key)
+	})
+	return err
+}
+
+// GetCDNURL returns the signed, non-expiring URL of key
+func (b *LocalBackend) GetCDNURL(key string) string {
+	return b.signedURL(key, 0)
+}
+
+// GetPresignedURL returns a signed URL that expires after expiry
+func (b *LocalBackend) GetPresignedURL(_ context.Context, key string, expiry time.Duration) (string, error) {
+	if _, err := b.filePath(key); err != nil {
+		return "", err
+	}
+	return b.signedURL(key, time.Now().Add(expiry).Unix()), nil
+}
+
+// Verify checks the exp·sig query of a served URL and returns the expiry (만료 없는 주소는 zero time)
+func (b *LocalBackend) Verify(key string, query url.Values) (time.Time, error) {
+	if _, err := b.filePath(key); err != nil {
+		return time.Time{}, err
+	}
+	var exp int64
+	if raw := query.Get("exp"); raw != "" {
+		var err error
+		if exp, err = strconv.ParseInt(raw, 10, 64); err != nil || exp <= 0 {
+			return time.Time{}, ErrInvalidSignature
+		}
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(query.Get("sig"))
+	if err != nil || !hmac.Equal(sig, b.sign(key, exp)) {
+		return time.Time{}, ErrInvalidSignature
+	}
+	if exp == 0 {
+		return time.Time{}, nil
+	}
+	expiresAt := time.Unix(exp, 0)
+	if time.Now().After(expiresAt) {
+		return expiresAt, ErrURLExpired
+	}
+	return expiresAt, nil
+}
+
+func (b *LocalBackend) signedURL(key string, exp int64) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	q := url.Values{}
+	if exp > 0 {
+		q.Set("exp", strconv.FormatInt(exp, 10))
+	}
+	q.Set("sig", base64.RawURLEncoding.EncodeToString(b.sign(key, exp)))
+	return b.publicURL + "/" + strings.Join(segments, "/") + "?" + q.Encode()
+}
+
+// sign 은 key 와 만료 시각(0 = 없음)을 묶어 서명한다 — 만료를 지우거나 늘린 주소는 서명이 맞지 않는다
+func (b *LocalBackend) sign(key string, exp int64) []byte {
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write([]byte(key))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(exp, 10)))
+	return mac.Sum(nil)[:localSigBytes]
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocal(t *testing.T) *LocalBackend {
+	t.Helper()
+	b, err := NewLocalBackend(LocalConfig{Root: t.TempDir(), PublicURL: "https://api.test/files/", SigningKey: "secret"})
+	if err != nil {
+		t.Fatalf("NewLocalBackend: %v", err)
+	}
+	return b
+}
+
+func signedQuery(t *testing.T, raw string) (string, url.Values) {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse %q: %v", raw, err)
+	}
+	return strings.TrimPrefix(u.Path, "/files/"), u.Query()
+}
+
+func TestLocalBackendRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	b := newTestLocal(t)
+
+	res, err := b.Upload(ctx, "images/2026/10/17/사진 1_1.png", strings.NewReader("png-bytes"), "image/png", 9)
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if res.Key != "images/2026/10/17/사진 1_1.png" || res.Size != 9 || !strings.HasPrefix(res.URL, "https://api.test/files/images/2026/10/17/") {
+		t.Fatalf("unexpected result %+v", res)
+	}
+
+	obj, err := b.Open(ctx, res.Key)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	body, _ := io.ReadAll(obj.Body)
+	_ = obj.Body.Close()
+	if string(body) != "png-bytes" || obj.ContentType != "image/png" {
+		t.Fatalf("unexpected object %q %q", body, obj.ContentType)
+	}
+
+	_, _ = b.Upload(ctx, "images/2026/11/01/b.jpg", strings.NewReader("x"), "image/jpeg", 1)
+	_, _ = b.Upload(ctx, "attachments/2026/10/17/c.pdf", strings.NewReader("x"), "application/pdf", 1)
+	var keys []string
+	if err := b.List(ctx, "images/2026/1", func(key string) error { keys = append(keys, key); return nil }); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	sort.Strings(keys)
+	if len(keys) != 2 || keys[0] != "images/2026/10/17/사진 1_1.png" || keys[1] != "images/2026/11/01/b.jpg" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+
+	if err := b.Delete(ctx, res.Key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if ok, _ := b.Exists(ctx, res.Key); ok {
+		t.Fatal("deleted file still exists")
+	}
+	if _, err := b.Open(ctx, res.Key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Open after delete: %v", err)
+	}
+	if err := b.Delete(ctx, res.Key); err != nil {
+		t.Fatalf("deleting a missing file should succeed, got %v", err)
+	}
+}
+
+func TestLocalBackendKeys(t *testing.T) {
+	b := newTestLocal(t)
+	for _, key := range []string{"", "../etc/passwd", "images/../../x", "/abs", "a//b", "a/./b", `a\b`, ".."} {
+		if _, err := b.Upload(context.Background(), key, strings.NewReader("x"), "", 1); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("key %q: want ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
+
+func TestLocalBackendSignedURL(t *testing.T) {
+	b := newTestLocal(t)
+
+	key, q := signedQuery(t, b.GetCDNURL("images/a b.jpg"))
+	if key != "images/a b.jpg" || q.Get("exp") != "" {
+		t.Fatalf("unexpected CDN URL %q %v", key, q)
+	}
+	if exp, err := b.Verify(key, q); err != nil || !exp.IsZero() {
+		t.Fatalf("CDN URL should verify without expiry: %v %v", exp, err)
+	}
+	if _, err := b.Verify("images/other.jpg", q); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("signature must be bound to the key, got %v", err)
+	}
+
+	raw, _ := b.GetPresignedURL(context.Background(), "images/a b.jpg", time.Minute)
+	key, q = signedQuery(t, raw)
+	if exp, err := b.Verify(key, q); err != nil || exp.IsZero() {
+		t.Fatalf("presigned URL should verify with expiry: %v %v", exp, err)
+	}
+	// 만료를 지우거나 늘리면 서명이 맞지 않는다
+	stripped := url.Values{"sig": q["sig"]}
+	if _, err := b.Verify(key, stripped); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("dropping exp must fail, got %v", err)
+	}
+	q.Set("exp", "99999999999")
+	if _, err := b.Verify(key, q); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("extending exp must fail, got %v", err)
+	}
+
+	raw, _ = b.GetPresignedURL(context.Background(), "images/a b.jpg", -time.Minute)
+	key, q = signedQuery(t, raw)
+	if _, err := b.Verify(key, q); !errors.Is(err, ErrURLExpired) {
+		t.Fatalf("past expiry: want ErrURLExpired, got %v", err)
+	}
+
+	other, _ := NewLocalBackend(LocalConfig{Root: t.TempDir(), SigningKey: "rotated"})
+	key, q = signedQuery(t, b.GetCDNURL("images/a.jpg"))
+	if _, err := other.Verify(key, q); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("another signing key must not verify, got %v", err)
+	}
+}
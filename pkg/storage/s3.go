@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -12,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	pkglogger "github.com/damoang/angple-backend/pkg/logger"
 )
 
@@ -117,6 +119,68 @@ func (c *S3Client) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Open downloads a file from storage
+func (c *S3Client) Open(ctx context.Context, key string) (*Object, error) {
+	out, err := c.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("s3 get failed: %w", err)
+	}
+	return &Object{
+		Body:        out.Body,
+		ContentType: aws.ToString(out.ContentType),
+		Size:        aws.ToInt64(out.ContentLength),
+		ModTime:     aws.ToTime(out.LastModified),
+	}, nil
+}
+
+// Exists reports whether key is stored (HeadObject)
+func (c *S3Client) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := c.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err == nil {
+		return true, nil
+	}
+	// HeadObject 는 본문이 없어 NoSuchKey 대신 NotFound 로 온다
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return false, nil
+	}
+	return false, fmt.Errorf("s3 head failed: %w", err)
+}
+
+// List walks every key under prefix (base_path 포함 전체 key)
+func (c *S3Client) List(ctx context.Context, prefix string, fn func(key string) error) error {
+	pages := s3.NewListObjectsV2Paginator(c.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("s3 list failed: %w", err)
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if strings.HasSuffix(key, "/") {
+				continue // 콘솔이 만든 폴더 표시 객체
+			}
+			if err := fn(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // GetPresignedURL generates a pre-signed URL for direct download
 func (c *S3Client) GetPresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
 	presignClient := s3.NewPresignClient(c.client)
@@ -151,3 +215,31 @@ func GenerateKey(prefix, filename string) string {
 		prefix, now.Year(), now.Month(), now.Day(),
 		base, now.UnixMilli(), ext)
 }
+
+// ContentKey returns the content-addressed key for a SHA-256 hex digest — 같은 내용은 항상 같은 key 다.
+// 앞 두 글자로 디렉터리를 나눠 한 디렉터리에 파일이 몰리지 않게 한다 (images/ab/ab12…ef.jpg).
+func ContentKey(prefix, sum, ext string) string {
+	return fmt.Sprintf("%s/%s/%s%s", prefix, sum[:2], sum, ext)
+}
+
+// ContentHash extracts the SHA-256 digest from a content-addressed key or URL (파생본 "_list" 접미사 포함).
+// 쿼리·조각은 무시하고, 내용 주소가 아니면 false.
+func ContentHash(keyOrURL string) (string, bool) {
+	if i := strings.IndexAny(keyOrURL, "?#"); i >= 0 {
+		keyOrURL = keyOrURL[:i]
+	}
+	base := path.Base(keyOrURL)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	if i := strings.IndexByte(base, '_'); i >= 0 {
+		base = base[:i]
+	}
+	if len(base) != 64 {
+		return "", false
+	}
+	for _, r := range base {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return "", false
+		}
+	}
+	return base, true
+}
@@ -0,0 +1,52 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 Appendix B (SHA1, 시크릿 "12345678901234567890") 의 8자리 값에서 뒤 6자리
+func TestCodeAtRFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range cases {
+		got, err := CodeAt(secret, Step(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatalf("CodeAt: %v", err)
+		}
+		if got != want {
+			t.Errorf("t=%d: got %s, want %s", unix, got, want)
+		}
+	}
+}
+
+func TestValidateSkewAndURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	prev, _ := CodeAt(secret, Step(now)-1) //nolint:errcheck // 위에서 만든 시크릿
+	if step, ok := Validate(secret, prev, now, 1); !ok || step != Step(now)-1 {
+		t.Fatalf("previous step should validate with skew 1, got %d %v", step, ok)
+	}
+	if _, ok := Validate(secret, prev, now, 0); ok {
+		t.Fatal("previous step should not validate without skew")
+	}
+	if _, ok := Validate(secret, "12345", now, 1); ok {
+		t.Fatal("short code should not validate")
+	}
+
+	uri := ProvisioningURI("Damoang", "zoe", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Damoang:zoe?") || !strings.Contains(uri, "secret="+secret) {
+		t.Fatalf("unexpected URI %s", uri)
+	}
+}
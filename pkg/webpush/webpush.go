@@ -0,0 +1,307 @@
+// Package webpush sends standard Web Push messages (RFC 8030) with encrypted payloads (RFC 8291, aes128gcm)
+// and VAPID application server identification (RFC 8292). 브라우저(Chrome·Firefox·Safari)의 PushManager
+// 구독이면 푸시 서비스가 어디든 같은 방식이다.
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// recordSize 는 aes128gcm 레코드 크기다 — 한 레코드에 담는다 (RFC 8291 §4: 4096 이하 권장)
+	recordSize = 4096
+	// MaxPayload is the largest plaintext one message can carry
+	// (레코드 크기 - 헤더(salt 16 + rs 4 + idlen 1 + keyid 65) - GCM 태그 16 - 구분자 1)
+	MaxPayload = recordSize - 86 - 16 - 1
+
+	// vapidExpiry 는 VAPID JWT 유효 시간이다 (RFC 8292: 24시간 이하)
+	vapidExpiry = 12 * time.Hour
+)
+
+var (
+	// ErrPayloadTooLarge is returned when the payload exceeds MaxPayload
+	ErrPayloadTooLarge = errors.New("webpush: payload too large")
+	// ErrInvalidSubscription is returned for malformed subscription keys
+	ErrInvalidSubscription = errors.New("webpush: invalid subscription")
+
+	b64 = base64.RawURLEncoding
+)
+
+// Subscription is a browser PushSubscription (PushSubscription.toJSON() 의 endpoint·keys)
+type Subscription struct {
+	Endpoint string
+	P256dh   string // base64url, 비압축 P-256 공개키 65바이트
+	Auth     string // base64url, 16바이트
+}
+
+// pushServiceHosts are the push services browsers hand out endpoints for. 서버가 구독의 URL 로 직접 POST 하므로
+// 여기 없는 호스트는 받지 않는다(내부망으로 요청을 보내게 하는 SSRF 차단). 접미사 일치.
+var pushServiceHosts = []string{
+	"fcm.googleapis.com",        // Chrome·Edge(Chromium)·Android 브라우저
+	"push.services.mozilla.com", // Firefox
+	"push.apple.com",            // Safari (web.push.apple.com)
+	"notify.windows.com",        // 구 Edge (WNS)
+}
+
+// Validate checks the endpoint (https, 알려진 푸시 서비스) and the encryption keys
+func (s Subscription) Validate() error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Port() != "" || u.User != nil {
+		return ErrInvalidSubscription
+	}
+	host := strings.ToLower(u.Hostname())
+	known := false
+	for _, h := range pushServiceHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return ErrInvalidSubscription
+	}
+	_, _, _, err = s.keys()
+	return err
+}
+
+// keys decodes the subscription's public key and auth secret
+func (s Subscription) keys() (public *ecdh.PublicKey, raw, authSecret []byte, err error) {
+	raw, err = b64.DecodeString(s.P256dh)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidSubscription
+	}
+	public, err = ecdh.P256().NewPublicKey(raw)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidSubscription
+	}
+	authSecret, err = b64.DecodeString(s.Auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, nil, nil, ErrInvalidSubscription
+	}
+	return public, raw, authSecret, nil
+}
+
+// VAPID identifies the application server to push services
+type VAPID struct {
+	key       *ecdsa.PrivateKey
+	publicKey string
+	subject   string
+}
+
+// GenerateVAPIDKeys returns a new key pair in the usual base64url form
+// (공개키 = 비압축 65바이트 — 브라우저 subscribe 의 applicationServerKey, 개인키 = 32바이트 스칼라)
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	pub, err := key.PublicKey.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+	priv, err := key.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+	return b64.EncodeToString(pub), b64.EncodeToString(priv), nil
+}
+
+// NewVAPID parses a VAPID key pair. subject 는 푸시 서비스가 연락할 주소다 (mailto: 또는 https:).
+func NewVAPID(publicKey, privateKey, subject string) (*VAPID, error) {
+	raw, err := b64.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: invalid VAPID private key: %w", err)
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), raw)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: invalid VAPID private key: %w", err)
+	}
+	pub, err := key.PublicKey.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if b64.EncodeToString(pub) != publicKey {
+		return nil, errors.New("webpush: VAPID public key does not match the private key")
+	}
+	if subject == "" {
+		return nil, errors.New("webpush: VAPID subject is required")
+	}
+	return &VAPID{key: key, publicKey: publicKey, subject: subject}, nil
+}
+
+// PublicKey returns the applicationServerKey browsers subscribe with
+func (v *VAPID) PublicKey() string {
+	return v.publicKey
+}
+
+// Authorization returns the Authorization header value for a push endpoint (RFC 8292 §3)
+func (v *VAPID) Authorization(endpoint string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", ErrInvalidSubscription
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(vapidExpiry).Unix(),
+		"sub": v.subject,
+	}).SignedString(v.key)
+	if err != nil {
+		return "", err
+	}
+	return "vapid t=" + token + ", k=" + v.publicKey, nil
+}
+
+// Encrypt encrypts a payload for a subscription (RFC 8291, Content-Encoding: aes128gcm)
+func Encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	serverKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encrypt(sub, payload, serverKey, salt)
+}
+
+func encrypt(sub Subscription, payload []byte, serverKey *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(payload) > MaxPayload {
+		return nil, ErrPayloadTooLarge
+	}
+	uaPublic, uaRaw, authSecret, err := sub.keys()
+	if err != nil {
+		return nil, err
+	}
+	shared, err := serverKey.ECDH(uaPublic)
+	if err != nil {
+		return nil, ErrInvalidSubscription
+	}
+	asPublic := serverKey.PublicKey().Bytes()
+
+	// §3.3: IKM = HKDF(auth_secret, ecdh_secret, "WebPush: info" || 0x00 || ua_public || as_public, 32)
+	keyInfo := append(append([]byte("WebPush: info\x00"), uaRaw...), asPublic...)
+	ikm, err := hkdf.Key(sha256.New, shared, authSecret, string(keyInfo), 32)
+	if err != nil {
+		return nil, err
+	}
+	// §3.4 (RFC 8188): CEK·NONCE
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// 헤더: salt(16) || rs(4) || idlen(1) || keyid(as_public 65). 레코드 하나 — 구분자 0x02 (마지막 레코드)
+	var out bytes.Buffer
+	out.Write(salt)
+	_ = binary.Write(&out, binary.BigEndian, uint32(recordSize))
+	out.WriteByte(byte(len(asPublic)))
+	out.Write(asPublic)
+	plaintext := append(append(make([]byte, 0, len(payload)+1), payload...), 0x02)
+	out.Write(gcm.Seal(nil, nonce, plaintext, nil))
+	return out.Bytes(), nil
+}
+
+// Options are per-message push service hints (RFC 8030 §5)
+type Options struct {
+	// TTL 은 푸시 서비스가 오프라인 기기를 위해 메시지를 들고 있을 시간이다 (0 이면 바로 못 받으면 버린다)
+	TTL time.Duration
+	// Urgency: very-low | low | normal | high
+	Urgency string
+	// Topic 이 같은 미배달 메시지는 새 것으로 바뀐다 (32자 이하 base64url)
+	Topic string
+}
+
+// Sender delivers messages to push services
+type Sender struct {
+	vapid  *VAPID
+	client *http.Client
+}
+
+// NewSender creates a new Sender
+func NewSender(vapid *VAPID, client *http.Client) *Sender {
+	if client == nil {
+		client = &http.Client{
+			Timeout: 10 * time.Second,
+			// 푸시 서비스는 리다이렉트하지 않는다 — 따라가면 Validate 가 막은 곳으로 갈 수 있다
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+	}
+	return &Sender{vapid: vapid, client: client}
+}
+
+// Send encrypts and posts one message. 푸시 서비스의 상태 코드를 돌려준다 — 201 이 성공,
+// Expired(status) 면 구독이 사라졌으니 지운다. err 는 요청을 만들거나 보내지 못했을 때만이다.
+func (s *Sender) Send(ctx context.Context, sub Subscription, payload []byte, opts Options) (int, error) {
+	if err := sub.Validate(); err != nil {
+		return 0, err
+	}
+	body, err := Encrypt(sub, payload)
+	if err != nil {
+		return 0, err
+	}
+	auth, err := s.vapid.Authorization(sub.Endpoint, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(opts.TTL/time.Second)))
+	if opts.Urgency != "" {
+		req.Header.Set("Urgency", opts.Urgency)
+	}
+	if opts.Topic != "" {
+		req.Header.Set("Topic", opts.Topic)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// Expired reports whether a push service status means the subscription is gone (404·410)
+func Expired(status int) bool {
+	return status == http.StatusNotFound || status == http.StatusGone
+}
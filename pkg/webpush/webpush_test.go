@@ -0,0 +1,100 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RFC 8291 Appendix A
+func TestEncryptMatchesRFC8291(t *testing.T) {
+	serverPriv, _ := b64.DecodeString("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw")
+	serverKey, err := ecdh.P256().NewPrivateKey(serverPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt, _ := b64.DecodeString("DGv6ra1nlYgDCS1FRnbzlw")
+	sub := Subscription{
+		Endpoint: "https://push.example.net/push/JzLQ3raZJfFBR0aqvOMsLrt54w4rJUsV",
+		P256dh:   "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+		Auth:     "BTBZMqHH6r4Tts7J_aSIgg",
+	}
+	got, err := encrypt(sub, []byte("When I grow up, I want to be a watermelon"), serverKey, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	if b64.EncodeToString(got) != want {
+		t.Fatalf("unexpected ciphertext %s", b64.EncodeToString(got))
+	}
+
+	if _, err := encrypt(sub, make([]byte, MaxPayload+1), serverKey, salt); err != ErrPayloadTooLarge {
+		t.Fatalf("expected ErrPayloadTooLarge, got %v", err)
+	}
+	sub.Auth = "short"
+	if _, err := encrypt(sub, []byte("x"), serverKey, salt); err != ErrInvalidSubscription {
+		t.Fatalf("expected ErrInvalidSubscription, got %v", err)
+	}
+}
+
+func TestVAPIDAuthorization(t *testing.T) {
+	pub, priv, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewVAPID(pub, priv, ""); err == nil {
+		t.Fatal("subject must be required")
+	}
+	other, _, _ := GenerateVAPIDKeys()
+	if _, err := NewVAPID(other, priv, "mailto:ops@example.com"); err == nil {
+		t.Fatal("mismatched public key must be rejected")
+	}
+	v, err := NewVAPID(pub, priv, "mailto:ops@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	header, err := v.Authorization("https://fcm.googleapis.com/fcm/send/abc:def", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, key, ok := strings.Cut(strings.TrimPrefix(header, "vapid t="), ", k=")
+	if !ok || key != pub {
+		t.Fatalf("unexpected header %q", header)
+	}
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return &v.key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}), jwt.WithAudience("https://fcm.googleapis.com")); err != nil {
+		t.Fatalf("token does not verify: %v", err)
+	}
+	if claims["sub"] != "mailto:ops@example.com" {
+		t.Fatalf("unexpected claims %v", claims)
+	}
+}
+
+func TestSubscriptionValidate(t *testing.T) {
+	keys := Subscription{
+		P256dh: "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+		Auth:   "BTBZMqHH6r4Tts7J_aSIgg",
+	}
+	for endpoint, ok := range map[string]bool{
+		"https://fcm.googleapis.com/fcm/send/abc":              true,
+		"https://updates.push.services.mozilla.com/wpush/v2/x": true,
+		"https://web.push.apple.com/QGx":                       true,
+		"http://fcm.googleapis.com/fcm/send/abc":               false,
+		"https://fcm.googleapis.com:8443/fcm/send/abc":         false,
+		"https://evilfcm.googleapis.com.example.com/x":         false,
+		"https://169.254.169.254/latest/meta-data":             false,
+	} {
+		sub := keys
+		sub.Endpoint = endpoint
+		if err := sub.Validate(); (err == nil) != ok {
+			t.Fatalf("Validate(%s) = %v", endpoint, err)
+		}
+	}
+}